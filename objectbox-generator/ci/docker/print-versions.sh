@@ -39,3 +39,5 @@ ld --version | grep "ld"
 cmake --version | grep version
 make --version | grep Make
 ccache --version | grep "ccache version"
+echo "node $(node --version)"
+echo "npm $(npm --version)"
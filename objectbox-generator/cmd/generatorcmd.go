@@ -24,15 +24,69 @@
 package generatorcmd
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/objectbox/objectbox-generator/v4/internal/daemon"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
 const defaultErrorCode = 2
 
+// Subcommands, given as the first positional argument; "generate" is the default if none is given.
+const (
+	cmdGenerate  = "generate"
+	cmdValidate  = "validate"
+	cmdClean     = "clean"
+	cmdModelDiff = "model-diff"
+	cmdLint      = "lint"
+	cmdVersion   = "version"
+
+	cmdVersionCheck = "version-check"
+	cmdDiff         = "diff"
+	cmdInspect      = "inspect"
+	cmdEstimate     = "estimate"
+	cmdFmtModel     = "fmt-model"
+	cmdRetired      = "retired-report"
+	cmdModelMerge   = "model-merge"
+	cmdServe        = "serve"
+	cmdErrors       = "errors"
+
+	cmdVerifyBuildInfo = "verify-build-info"
+)
+
+var subcommands = []string{cmdGenerate, cmdValidate, cmdClean, cmdModelDiff, cmdLint, cmdVersion, cmdVersionCheck, cmdDiff, cmdInspect, cmdEstimate, cmdFmtModel, cmdRetired, cmdModelMerge, cmdServe, cmdErrors, cmdVerifyBuildInfo}
+
+// serveMethods lists the subcommands available as JSON-RPC methods of the serve subcommand
+var serveMethods = []string{cmdGenerate, cmdValidate, cmdClean, cmdModelDiff, cmdLint, cmdVersion, cmdVersionCheck, cmdDiff, cmdInspect, cmdEstimate, cmdVerifyBuildInfo}
+
+// commandResult is the common part of the output printed with the -json flag
+type commandResult struct {
+	Command string `json:"command"`
+	Path    string `json:"path,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+
+	// the error with its position (if known), e.g. for IDE integrations, see generator.Diagnostics()
+	Diagnostics []generator.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// streamArgs configure the streaming mode, i.e. `-out -`, see generator.ProcessStream()
+type streamArgs struct {
+	fromStdin  bool
+	sourceName string
+	format     string
+}
+
 // / generatorCommand defines an interface for command-line applications to implement
 type generatorCommand interface {
 	ShowUsage()
@@ -40,19 +94,430 @@ type generatorCommand interface {
 	ParseFlags(remainingPosArgs *[]string, options *generator.Options) error
 }
 
+// regenerate outdated files found by the version-check subcommand
+var regenerate bool
+
+// inspectFormat is the output format of the inspect subcommand, one of generator.InspectFormats
+var inspectFormat string
+
+// estimateOptions configure the estimate subcommand, parsed from the -avg-size and -objects flags
+var estimateOptions generator.EstimateOptions
+
+// listen is the socket the serve subcommand accepts connections on, e.g. "unix:/tmp/objectbox.sock"; stdio if empty
+var listen string
+
+// messagesFile is an optional JSON file with translated error messages, see messages.LoadTranslations()
+var messagesFile string
+
+// modelMergeFiles are the "ours" and "theirs" model files of the model-merge subcommand, following the base one
+var modelMergeFiles []string
+
+// modelDiffHTMLFile is the optional HTML report written by the model-diff subcommand, see generator.WriteModelDiffHTML()
+var modelDiffHTMLFile string
+
 func Main(impl generatorCommand) {
-	clean, options := getArgs(impl)
+	command, jsonOutput, stream, options := getArgs(impl)
 
-	var err error
-	if clean {
+	if command == cmdServe {
+		stopOnError(0, serve(impl, options))
+		return
+	}
+
+	if stream != nil {
+		stopOnError(0, runStream(options, *stream))
+		return
+	}
+
+	if !jsonOutput {
+		options.Output = os.Stdout
+		stopOnError(0, run(command, options))
+		return
+	}
+
+	// keep stdout machine-readable: messages printed by the generator while running go to stderr instead
+	options.Output = os.Stderr
+	var result, err = runForJSON(command, options)
+
+	data, jsonErr := json.MarshalIndent(result, "", "  ")
+	stopOnError(0, jsonErr)
+	fmt.Println(string(data))
+
+	if err != nil {
+		os.Exit(defaultErrorCode)
+	}
+}
+
+// run executes the given subcommand, printing human-readable output
+func run(command string, options generator.Options) error {
+	switch command {
+	case cmdVersion:
+		fmt.Println(fmt.Sprintf("ObjectBox Generator v%s #%d", generator.Version, generator.VersionId))
+		return nil
+	case cmdErrors:
+		for _, message := range messages.Catalog() {
+			fmt.Printf("%s  %s\n", message.Code, message.Format)
+		}
+		return nil
+	case cmdClean:
 		fmt.Printf("Removing ObjectBox bindings for %s\n", options.InPath)
-		err = generator.Clean(options.CodeGenerator, options.InPath)
-	} else {
-		fmt.Printf("Generating ObjectBox bindings for %s\n", options.InPath)
+		return clean(options)
+	case cmdValidate:
+		fmt.Printf("Validating ObjectBox model for %s\n", options.InPath)
+		modelInfo, err := generator.Validate(options)
+		if err == nil {
+			fmt.Printf("The model is valid, entities: %s\n", strings.Join(entityNames(modelInfo), ", "))
+		}
+		return err
+	case cmdModelDiff:
+		changes, err := modelDiff(options)
+		if err == nil && len(changes) == 0 {
+			fmt.Println("No changes to the model")
+		}
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+		return err
+	case cmdLint:
+		issues, err := generator.LintSources(options)
+		if err == nil && len(issues) == 0 {
+			fmt.Println("No lint issues found")
+		}
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		return err
+	case cmdVersionCheck:
+		outdated, err := versionCheck(options)
+		if err == nil && len(outdated) == 0 {
+			fmt.Printf("All generated files are up to date with ObjectBox Generator v%s #%d\n", generator.Version, generator.VersionId)
+		}
+		for _, file := range outdated {
+			fmt.Println(file)
+		}
+		return err
+	case cmdVerifyBuildInfo:
+		mismatches, err := verifyBuildInfo(options)
+		if err == nil && len(mismatches) == 0 {
+			fmt.Println("All generated files match their build info")
+		}
+		for _, file := range mismatches {
+			fmt.Println(file)
+		}
+		return err
+	case cmdDiff:
+		diffs, err := generator.DiffOutput(options)
+		if err == nil && len(diffs) == 0 {
+			fmt.Println("No changes to the generated files")
+		}
+		for _, diff := range diffs {
+			fmt.Print(diff.Diff)
+		}
+		return err
+	case cmdInspect:
+		inspected, err := generator.Inspect(options)
+		if err != nil {
+			return err
+		}
+		return inspected.Write(os.Stdout, inspectFormat)
+	case cmdEstimate:
+		estimated, err := generator.Estimate(options, estimateOptions)
+		if err != nil {
+			return err
+		}
+		return estimated.Write(os.Stdout)
+	case cmdFmtModel:
+		modelFile, changed, err := generator.FormatModel(options)
+		if err == nil && changed {
+			fmt.Printf("Formatted %s\n", modelFile)
+		} else if err == nil {
+			fmt.Printf("%s is already formatted\n", modelFile)
+		}
+		return err
+	case cmdRetired:
+		report, err := generator.ReportRetired(options, generator.GitNameResolver{})
+		if err != nil {
+			return err
+		}
+		return report.Write(os.Stdout)
+	case cmdModelMerge:
+		notices, err := generator.MergeModelFiles(options.InPath, modelMergeFiles[0], modelMergeFiles[1])
+		for _, notice := range notices {
+			fmt.Println(notice)
+		}
+		if err == nil {
+			fmt.Printf("Merged %s into %s\n", modelMergeFiles[1], modelMergeFiles[0])
+		}
+		return err
+	default:
+		if len(options.BundleFile) > 0 {
+			fmt.Printf("Generating ObjectBox bindings for %s into %s\n", options.InPath, options.BundleFile)
+		} else {
+			fmt.Printf("Generating ObjectBox bindings for %s\n", options.InPath)
+		}
+		return generator.Process(options)
+	}
+}
+
+// serve runs the JSON-RPC server until a client requests a shutdown, see the daemon package
+func serve(impl generatorCommand, options generator.Options) error {
+	var server = daemon.NewServer(serveMethods, func() (generator.Options, error) {
+		// each request gets fresh code generators, configured by the flags given when starting the server
+		var requestOptions = options
+		requestOptions.Output = os.Stderr
+		return requestOptions, impl.ParseFlags(&[]string{}, &requestOptions)
+	}, func(method string, options generator.Options) interface{} {
+		// a failed command is reported in the result, same as with -json, including the diagnostics
+		result, _ := runForJSON(method, options)
+		return result
+	})
+	server.PathOptional = []string{cmdVersion}
+
+	// stdout is reserved for the protocol: messages printed by the generator go to stderr instead, see NewOptions above
+	if len(listen) == 0 {
+		return server.Serve(os.Stdin, os.Stdout)
+	}
+	fmt.Fprintf(os.Stderr, "Serving ObjectBox Generator JSON-RPC on %s\n", listen)
+	return server.Listen(listen)
+}
+
+// runStream generates code, writing all the files to stdout instead of the file system
+func runStream(options generator.Options, stream streamArgs) error {
+	// keep stdout clean for the generated files: messages printed by the generator go to stderr instead
+	options.Output = os.Stderr
+
+	if stream.fromStdin {
+		return generator.ProcessStream(options, os.Stdin, stream.sourceName, os.Stdout, stream.format)
+	}
+	return generator.ProcessStream(options, nil, "", os.Stdout, stream.format)
+}
+
+// runForJSON executes the given subcommand, returning its result to be printed as JSON
+func runForJSON(command string, options generator.Options) (interface{}, error) {
+	var common = commandResult{Command: command, Path: options.InPath}
+	var result interface{} = &common
+	var err error
+
+	switch command {
+	case cmdVersion:
+		result = &struct {
+			*commandResult
+			Version   string `json:"version"`
+			VersionId int    `json:"versionId"`
+		}{&common, generator.Version, generator.VersionId}
+	case cmdErrors:
+		result = &struct {
+			*commandResult
+			Messages []messages.Message `json:"messages"`
+		}{&common, messages.Catalog()}
+	case cmdClean:
+		err = clean(options)
+	case cmdValidate:
+		var validateResult = &struct {
+			*commandResult
+			Entities []string `json:"entities"`
+		}{&common, []string{}}
+		result = validateResult
+
+		var modelInfo *model.ModelInfo
+		if modelInfo, err = generator.Validate(options); err == nil {
+			validateResult.Entities = append(validateResult.Entities, entityNames(modelInfo)...)
+		}
+	case cmdModelDiff:
+		var diffResult = &struct {
+			*commandResult
+			Changes []generator.ModelChange `json:"changes"`
+		}{&common, []generator.ModelChange{}}
+		result = diffResult
+
+		var changes []generator.ModelChange
+		if changes, err = modelDiff(options); err == nil {
+			diffResult.Changes = append(diffResult.Changes, changes...)
+		}
+	case cmdLint:
+		var lintResult = &struct {
+			*commandResult
+			Issues []generator.LintIssue `json:"issues"`
+		}{&common, []generator.LintIssue{}}
+		result = lintResult
+
+		var issues []generator.LintIssue
+		issues, err = generator.LintSources(options)
+		lintResult.Issues = append(lintResult.Issues, issues...)
+	case cmdVersionCheck:
+		var checkResult = &struct {
+			*commandResult
+			VersionId   int                      `json:"versionId"`
+			Outdated    []generator.OutdatedFile `json:"outdated"`
+			Regenerated bool                     `json:"regenerated"`
+		}{&common, generator.VersionId, []generator.OutdatedFile{}, false}
+		result = checkResult
+
+		var outdated []generator.OutdatedFile
+		outdated, err = versionCheck(options)
+		checkResult.Outdated = append(checkResult.Outdated, outdated...)
+		checkResult.Regenerated = err == nil && len(outdated) > 0
+	case cmdVerifyBuildInfo:
+		var verifyResult = &struct {
+			*commandResult
+			Mismatches []generator.BuildInfoMismatch `json:"mismatches"`
+		}{&common, []generator.BuildInfoMismatch{}}
+		result = verifyResult
+
+		var mismatches []generator.BuildInfoMismatch
+		mismatches, err = verifyBuildInfo(options)
+		verifyResult.Mismatches = append(verifyResult.Mismatches, mismatches...)
+	case cmdDiff:
+		var diffResult = &struct {
+			*commandResult
+			Files []generator.FileDiff `json:"files"`
+		}{&common, []generator.FileDiff{}}
+		result = diffResult
+
+		var diffs []generator.FileDiff
+		if diffs, err = generator.DiffOutput(options); err == nil {
+			diffResult.Files = append(diffResult.Files, diffs...)
+		}
+	case cmdInspect:
+		var inspectResult = &struct {
+			*commandResult
+			*generator.InspectedModel
+		}{&common, &generator.InspectedModel{Entities: []generator.InspectedEntity{}}}
+		result = inspectResult
+
+		var inspected *generator.InspectedModel
+		if inspected, err = generator.Inspect(options); err == nil {
+			inspectResult.InspectedModel = inspected
+		}
+	case cmdEstimate:
+		var estimateResult = &struct {
+			*commandResult
+			*generator.EstimatedModel
+		}{&common, &generator.EstimatedModel{Entities: []generator.EstimatedEntity{}}}
+		result = estimateResult
+
+		var estimated *generator.EstimatedModel
+		if estimated, err = generator.Estimate(options, estimateOptions); err == nil {
+			estimateResult.EstimatedModel = estimated
+		}
+	case cmdFmtModel:
+		var fmtResult = &struct {
+			*commandResult
+			File    string `json:"file,omitempty"`
+			Changed bool   `json:"changed"`
+		}{commandResult: &common}
+		result = fmtResult
+
+		fmtResult.File, fmtResult.Changed, err = generator.FormatModel(options)
+	case cmdRetired:
+		var retiredResult = &struct {
+			*commandResult
+			*generator.RetiredReport
+		}{&common, &generator.RetiredReport{Versions: []generator.RetiredVersion{}}}
+		result = retiredResult
+
+		var report *generator.RetiredReport
+		if report, err = generator.ReportRetired(options, generator.GitNameResolver{}); err == nil {
+			retiredResult.RetiredReport = report
+		}
+	case cmdModelMerge:
+		var mergeResult = &struct {
+			*commandResult
+			File    string   `json:"file"`
+			Notices []string `json:"notices"`
+		}{&common, modelMergeFiles[0], []string{}}
+		result = mergeResult
+
+		var notices []string
+		notices, err = generator.MergeModelFiles(options.InPath, modelMergeFiles[0], modelMergeFiles[1])
+		mergeResult.Notices = append(mergeResult.Notices, notices...)
+	default:
 		err = generator.Process(options)
 	}
 
-	stopOnError(0, err)
+	common.Success = err == nil
+	if err != nil {
+		common.Error = err.Error()
+		common.Diagnostics = generator.Diagnostics(err)
+	}
+	return result, err
+}
+
+// modelDiff lists changes to the stored model that running the generation would make
+func modelDiff(options generator.Options) ([]generator.ModelChange, error) {
+	var modelInfoFile = options.ModelInfoFile
+	if len(modelInfoFile) == 0 {
+		modelInfoFile = generator.ModelInfoFile(filepath.Dir(options.InPath))
+	}
+
+	storedModel, err := model.LoadModelReadOnly(modelInfoFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the stored model: %s", err)
+	}
+
+	currentModel, err := generator.Validate(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(modelDiffHTMLFile) > 0 {
+		var b strings.Builder
+		if err := generator.WriteModelDiffHTML(&b, storedModel, currentModel); err != nil {
+			return nil, fmt.Errorf("can't create the HTML report: %s", err)
+		} else if err := ioutil.WriteFile(modelDiffHTMLFile, []byte(b.String()), 0644); err != nil {
+			return nil, fmt.Errorf("can't write the HTML report: %s", err)
+		}
+	}
+
+	return generator.DiffModels(storedModel, currentModel), nil
+}
+
+// clean removes the files generated for all the selected languages
+func clean(options generator.Options) error {
+	for _, codeGenerator := range options.Targets() {
+		if err := generator.CleanTo(options.Output, codeGenerator, options.InPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionCheck lists generated files which don't match the current generator and regenerates them if requested;
+// outdated files are reported as an error unless they're regenerated, e.g. to fail a CI build
+func versionCheck(options generator.Options) ([]generator.OutdatedFile, error) {
+	outdated, err := generator.CheckVersions(options)
+	if err != nil || len(outdated) == 0 {
+		return outdated, err
+	} else if !regenerate {
+		return outdated, fmt.Errorf("found %d outdated generated file(s), run the generator or use -regenerate", len(outdated))
+	}
+
+	fmt.Fprintf(options.Output, "Regenerating ObjectBox bindings for %s\n", options.InPath)
+	return outdated, generator.Process(options)
+}
+
+// verifyBuildInfo lists generated files which don't match their build info; mismatches are reported as an error,
+// e.g. to fail a CI build when the generated files weren't regenerated after changing the sources
+func verifyBuildInfo(options generator.Options) ([]generator.BuildInfoMismatch, error) {
+	checked, mismatches, err := generator.VerifyBuildInfo(options)
+	if err != nil {
+		return mismatches, err
+	} else if checked == 0 {
+		return mismatches, fmt.Errorf("no generated files found in %s", options.InPath)
+	} else if len(mismatches) > 0 {
+		return mismatches, fmt.Errorf("found %d generated file(s) not matching their build info, run the generator with -build-info", len(mismatches))
+	}
+	return mismatches, nil
+}
+
+// entityNames returns the names of entities found in the processed sources
+func entityNames(modelInfo *model.ModelInfo) []string {
+	var names []string
+	for _, entity := range modelInfo.Entities {
+		if entity.CurrentlyPresent {
+			names = append(names, entity.Name)
+		}
+	}
+	return names
 }
 
 func stopOnError(code int, err error) {
@@ -65,6 +530,62 @@ func stopOnError(code int, err error) {
 	}
 }
 
+func isSubcommand(arg string) bool {
+	for _, command := range subcommands {
+		if arg == command {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultAverageSizes lists generator.DefaultAverageSizes in the -avg-size format, e.g. "String=32"
+func defaultAverageSizes() string {
+	var sizes []string
+	for typeName, size := range generator.DefaultAverageSizes {
+		sizes = append(sizes, fmt.Sprintf("%s=%d", typeName, size))
+	}
+	sort.Strings(sizes)
+	return strings.Join(sizes, ", ")
+}
+
+func isInspectFormat(format string) bool {
+	for _, known := range generator.InspectFormats {
+		if format == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEstimateArgs parses the -avg-size (Type=bytes) and -objects ([Entity=]count) flags of the estimate subcommand
+func parseEstimateArgs(avgSizes, objects []string) (generator.EstimateOptions, error) {
+	var result = generator.EstimateOptions{AverageSizes: make(map[string]int), Objects: make(map[string]int64)}
+	for _, arg := range avgSizes {
+		var parts = strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return result, fmt.Errorf("argument -avg-size must be given as Type=bytes, e.g. String=64; got %s", arg)
+		}
+		size, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return result, fmt.Errorf("argument -avg-size %s: invalid size: %s", arg, err)
+		}
+		result.AverageSizes[parts[0]] = size
+	}
+	for _, arg := range objects {
+		var entity, count = "", arg
+		if i := strings.LastIndex(arg, "="); i >= 0 {
+			entity, count = arg[:i], arg[i+1:]
+		}
+		value, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || value < 0 {
+			return result, fmt.Errorf("argument -objects must be given as a count or Entity=count, e.g. Order=10000; got %s", arg)
+		}
+		result.Objects[entity] = value
+	}
+	return result, nil
+}
+
 func showUsageAndExit(impl generatorCommand, a ...interface{}) {
 	if len(a) > 0 {
 		a = append(a, "\n\n")
@@ -74,18 +595,115 @@ func showUsageAndExit(impl generatorCommand, a ...interface{}) {
 	os.Exit(1)
 }
 
-func getArgs(impl generatorCommand) (clean bool, options generator.Options) {
+// stringsFlag implements flag.Value for flags which can be given multiple times
+type stringsFlag []string
+
+func (list *stringsFlag) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *stringsFlag) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
+
+// namesFlag implements flag.Value for comma-separated lists of names, e.g. "Task,Note"; it can be given multiple times too
+type namesFlag []string
+
+func (list *namesFlag) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *namesFlag) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			*list = append(*list, name)
+		}
+	}
+	return nil
+}
+
+func getArgs(impl generatorCommand) (command string, jsonOutput bool, stream *streamArgs, options generator.Options) {
 	var printVersion bool
+	var checkVersion bool
+	var printDiff bool
 	var printHelp bool
+	var avgSizes, objectCounts stringsFlag
+	var lintConfig string
+	var configFile string
+	var inPath string
+	var streamConfig streamArgs
 	flag.Usage = impl.ShowUsage
 	impl.ConfigureFlags()
-	flag.StringVar(&options.OutPath, "out", "", "output path for generated source files")
+	flag.StringVar(&inPath, "in", "", "optional: the source path, same as the positional {path}; use - to read a single source file from stdin")
+	flag.StringVar(&streamConfig.sourceName, "in-name", "schema.fbs", "file name of the source read from stdin (-in -), determines the names of the generated files")
+	flag.StringVar(&options.OutPath, "out", "", "output path for generated source files; use - to write all generated files to stdout (see -out-format)")
+	flag.StringVar(&streamConfig.format, "out-format", generator.StreamFormatJSON, "format of the output written to stdout (-out -): json (an envelope with a list of files) or tar")
 	flag.StringVar(&options.OutHeadersPath, "out-headers", "", "optional: output path for generated header files") // opt-in: C and C++
+	flag.StringVar(&options.ManifestFile, "manifest", "", "optional: write a JSON manifest listing all generated files to the given path; with validate, the files that would be generated")
+	flag.StringVar(&options.AdminMetadataFile, "admin-metadata", "", "optional: write a JSON file describing the model for ObjectBox Admin (the data browser) to the given path,\n"+
+		"i.e. labels, descriptions from the source comments, external names and relations of each entity, e.g. objectbox-admin.json")
+	flag.StringVar(&options.BundleFile, "bundle", "", "optional: write all generated files, including the updated model JSON, to the given zip archive instead of the source tree;\n"+
+		"the archive is reproducible (sorted files with fixed timestamps), e.g. for other build steps or artifact stores")
 	flag.StringVar(&options.ModelInfoFile, "model", "", "path to the model information persistence file (JSON)")
+	flag.BoolVar(&options.AtomicWrites, "atomic-writes", false, "write the generated files to temporary files renamed to the targets, so that interrupted or concurrent builds never leave partially written files")
+	flag.BoolVar(&options.BackupFiles, "backup", false, "keep the previous content of changed generated files as <file>.bak")
+	flag.BoolVar(&options.BuildInfo, "build-info", false, "stamp the generated files with a build info comment (generator version, input and options hashes);\n"+
+		"no timestamps or other environment details are included, see the verify-build-info subcommand")
+	flag.BoolVar(&options.Checksums, "checksum", false, "record a checksum of the content in a footer comment of the generated files and refuse to overwrite files edited manually,\n"+
+		"showing a diff of the changes instead; use -force to overwrite them")
+	flag.BoolVar(&options.Force, "force", false, "overwrite generated files even if they were edited manually, see -checksum")
+	flag.BoolVar(&options.Strict, "strict", false, "fail on constructs the generated code doesn't fully support (e.g. property types not handled by the selected language) instead of skipping them")
+	flag.BoolVar(&options.KeepGoing, "keep-going", false, "continue reading and validating the sources after an error and report all of them at once (e.g. with -json as a list of diagnostics);\n"+
+		"no files are written unless all the sources are valid")
+	flag.BoolVar(&options.AllowDrop, "allow-drop", false, "allow previously stored properties to become transient (ignored), dropping their data;\n"+
+		"alternatively, confirm it per field using the transient(allow-drop) annotation")
+	flag.BoolVar(&options.Frozen, "frozen", false, "fail if the sources change the model (e.g. add entities or properties, assigning new IDs/UIDs), without writing any files;\n"+
+		"e.g. for release branches - an "+generator.FreezeFileName+" file next to the model JSON has the same effect")
+	flag.BoolVar(&options.AcceptOptionsChange, "accept-options-change", false, "C, C++, JS: accept changes of the options recorded in the model JSON that affect how data is stored,\n"+
+		"i.e. -optional, -empty-string-as-null and -nan-as-null; data written by the previously generated code may be read differently")
+	flag.BoolVar(&options.GenerateBenchmarks, "benchmarks", false, "Go, C++, JS: additionally generate benchmarks of the FlatBuffers serialization (put/get) of each entity,\n"+
+		"i.e. <source>.obx.bench_test.go (testing.B), <source>.obx.bench.cpp (google-benchmark) or schema.obx.bench.js (node)")
+	flag.BoolVar(&options.GenerateFixtures, "fixtures", false, "Go, C++: additionally generate test fixtures creating objects of each entity with defaults or seeded random values,\n"+
+		"i.e. New<Entity>Fixture()/Random<Entity>Fixture() in <source>.obx.fixtures_test.go or new<Entity>Fixture()/random<Entity>Fixture() in <source>.obx.fixtures.hpp")
+	flag.BoolVar(&options.GenerateExport, "export", false, "Go, C++: additionally generate functions exporting objects of each entity to JSON/CSV and importing them back, e.g. for backups,\n"+
+		"i.e. Export<Entity>JSON()/Import<Entity>JSON()/...CSV() in <source>.obx.export.go or export<Entity>JSON()/import<Entity>JSON()/...CSV() in <source>.obx.export.hpp")
+	flag.Var((*stringsFlag)(&options.ModuleModelFiles), "module-model", "optional: model JSON file of another module (e.g. in a monorepo) to merge into the generated model; can be given multiple times.\n"+
+		"The entity names and UIDs must be unique across all modules; Go: the other module's bindings are imported from the directory of its model file")
+	flag.Var((*namesFlag)(&options.OnlyEntities), "only", "optional: comma-separated list of entities to write the binding files of, e.g. Task,Note; the model JSON and the model file\n"+
+		"still cover all entities and files generated per source are written if any of its entities is selected, e.g. for incremental builds")
+	flag.Var((*namesFlag)(&options.ExcludeEntities), "exclude", "optional: comma-separated list of entities not to write the binding files of, see -only")
+	flag.StringVar(&options.TenantPrefix, "tenant-prefix", "", "C, C++, JS: prefix all entity names to generate a tenant's variant of the schema, e.g. Acme_;\n"+
+		"use a separate -model file for each tenant, merge them using -module-model if needed")
+	flag.StringVar(&options.PathStyle, "path-style", generator.PathStyleNative, "separators of the paths written by the generator, e.g. to the -manifest; one of: "+strings.Join(generator.PathStyles, ", ")+"\n"+
+		"(use slash to get the same output on all OSes)")
+	flag.BoolVar(&options.DeterministicUids, "deterministic-uids", false, "derive new UIDs from names instead of generating random ones (reproducible without the model JSON file)")
+	flag.StringVar(&options.UidSalt, "uid-salt", "", "optional: salt for -deterministic-uids, e.g. a project name")
+	flag.StringVar(&options.TemplateOverridesDir, "template-overrides", "", "optional: directory with *.tmpl files customizing the generated code,\n"+
+		"e.g. defining \"file-header\" and \"file-footer\" blocks or replacing a whole template by its name (e.g. \"model.tmpl\")")
 	// TODO remove in v0.15.0 or later
 	flag.StringVar(&options.ModelInfoFile, "persist", "", "[DEPRECATED, use 'model'] path to the model information persistence file (JSON)")
 	flag.BoolVar(&printVersion, "version", false, "print the generator version info")
+	flag.BoolVar(&checkVersion, "version-check", false, "same as the version-check subcommand: list generated files that don't match the current generator version (or templates)")
+	flag.BoolVar(&regenerate, "regenerate", false, "with version-check: regenerate the bindings if any generated file is outdated")
+	flag.BoolVar(&printDiff, "diff", false, "same as the diff subcommand: print unified diffs between the generated files on disk and the new output, without writing any files")
+	flag.StringVar(&inspectFormat, "format", generator.InspectFormatTree, "output format of the inspect subcommand; one of: "+strings.Join(generator.InspectFormats, ", "))
+	flag.StringVar(&listen, "listen", "", "with serve: accept JSON-RPC connections on the given local socket instead of stdin/stdout,\n"+
+		"e.g. unix:/tmp/objectbox.sock or tcp:localhost:7000; TCP is only allowed on loopback addresses")
+	flag.StringVar(&modelDiffHTMLFile, "html", "", "with model-diff: additionally write the changes as an HTML report to the given file (entities colored by added/removed/changed),\n"+
+		"e.g. to be published as a CI artifact")
+	flag.Var(&avgSizes, "avg-size", "with estimate: the average size of variable-length values of the given type in bytes, e.g. String=64; can be given multiple times.\n"+
+		"Defaults: "+defaultAverageSizes())
+	flag.Var(&objectCounts, "objects", "with estimate: the expected number of objects of all entities or of the given one, e.g. 1000 or Order=50000; can be given multiple times")
 	flag.BoolVar(&printHelp, "help", false, "print this help")
+	flag.StringVar(&lintConfig, "lint-config", "", "optional: JSON file with lint rule severities, e.g. {\"huge-entity\": \"error\"}; also enables linting before generating.\n"+
+		"Available rules: "+strings.Join(generator.LintRuleNames(), ", "))
+	flag.StringVar(&messagesFile, "messages", "", "optional: JSON file with translated error messages, mapping the codes (see the errors subcommand) to texts,\n"+
+		"e.g. {\"OBXG1005\": \"...\"}; the texts must use the same placeholders (e.g. %s) in the same order")
+	flag.StringVar(&configFile, "config", "", "optional: YAML file setting flags by their names, e.g. \"cpp: true\" or \"module-model: [a.json, b.json]\", and lint rule severities\n"+
+		"in a \"lint:\" mapping; flags given on the command line take precedence. By default, "+generator.ConfigFileName+" is looked up in the source\n"+
+		"directory and its parents; use -config none to ignore it")
+	flag.BoolVar(&jsonOutput, "json", false, "print the result as JSON, e.g. for build tooling; errors include diagnostics with the file, line and column (if known); other messages are printed to stderr")
 	flag.Parse()
 
 	if printHelp {
@@ -93,35 +711,219 @@ func getArgs(impl generatorCommand) (clean bool, options generator.Options) {
 		os.Exit(0)
 	}
 
-	if printVersion {
-		fmt.Println(fmt.Sprintf("ObjectBox Generator v%s #%d", generator.Version, generator.VersionId))
-		os.Exit(0)
-	}
-
 	// process positional args
 	var args = flag.Args()
 
-	if len(args) > 0 && args[0] == "clean" {
-		clean = true
+	command = cmdGenerate
+	if len(args) > 0 && isSubcommand(args[0]) {
+		command = args[0]
 		args = args[1:]
 	}
 
-	if len(args) > 0 {
+	// the config file is looked up from the source path, which is only known after the flags are parsed
+	var config *generator.Config
+	if !printVersion && command != cmdVersion && command != cmdErrors {
+		var sourcePath = inPath
+		if len(sourcePath) == 0 && len(args) > 0 {
+			sourcePath = args[0]
+		}
+		var err error
+		config, err = applyConfig(configFile, sourcePath)
+		stopOnError(0, err)
+	}
+
+	if len(messagesFile) > 0 {
+		stopOnError(0, messages.LoadTranslations(messagesFile))
+	}
+
+	if checkVersion {
+		if command != cmdGenerate && command != cmdVersionCheck {
+			showUsageAndExit(impl, "argument -version-check can't be combined with the subcommand", command)
+		}
+		command = cmdVersionCheck
+	}
+
+	if printDiff {
+		if command != cmdGenerate && command != cmdDiff {
+			showUsageAndExit(impl, "argument -diff can't be combined with the subcommand", command)
+		}
+		command = cmdDiff
+	}
+
+	if regenerate && command != cmdVersionCheck {
+		showUsageAndExit(impl, "argument -regenerate is only allowed in combination with version-check")
+	}
+
+	if inspectFormat != generator.InspectFormatTree && command != cmdInspect {
+		showUsageAndExit(impl, "argument -format is only allowed in combination with inspect")
+	} else if !isInspectFormat(inspectFormat) {
+		showUsageAndExit(impl, fmt.Sprintf("argument -format must be one of: %s; got %s", strings.Join(generator.InspectFormats, ", "), inspectFormat))
+	}
+
+	if len(listen) > 0 && command != cmdServe {
+		showUsageAndExit(impl, "argument -listen is only allowed in combination with serve")
+	}
+
+	if command == cmdServe && jsonOutput {
+		showUsageAndExit(impl, "argument -json can't be combined with serve, the results are always JSON")
+	}
+
+	if len(modelDiffHTMLFile) > 0 && command != cmdModelDiff {
+		showUsageAndExit(impl, "argument -html is only allowed in combination with model-diff")
+	}
+
+	if (len(avgSizes) > 0 || len(objectCounts) > 0) && command != cmdEstimate {
+		showUsageAndExit(impl, "arguments -avg-size and -objects are only allowed in combination with estimate")
+	} else if parsed, err := parseEstimateArgs(avgSizes, objectCounts); err != nil {
+		showUsageAndExit(impl, err)
+	} else {
+		estimateOptions = parsed
+	}
+
+	if command == cmdErrors {
+		if len(args) > 0 {
+			showUsageAndExit(impl, "unknown arguments", args)
+		}
+		return
+	}
+
+	if printVersion || command == cmdVersion {
+		if len(args) > 0 {
+			showUsageAndExit(impl, "unknown arguments", args)
+		}
+		command = cmdVersion
+		return
+	}
+
+	if len(inPath) > 0 {
+		options.InPath = inPath
+	} else if len(args) > 0 {
 		options.InPath = args[0]
 		args = args[1:]
 	}
 
-	if err := impl.ParseFlags(&args, &options); err != nil {
-		showUsageAndExit(impl, err)
+	// the base model file is the path, followed by ours and theirs
+	if command == cmdModelMerge {
+		if len(args) != 2 {
+			showUsageAndExit(impl, "model-merge expects three model files: base, ours and theirs")
+		}
+		modelMergeFiles, args = args, nil
+	}
+
+	// fmt-model, retired-report and model-merge only process model JSON files, they don't need an output language
+	if command != cmdFmtModel && command != cmdRetired && command != cmdModelMerge {
+		if err := impl.ParseFlags(&args, &options); err != nil {
+			showUsageAndExit(impl, err)
+		}
 	}
 
-	if len(options.InPath) == 0 {
+	// the server gets the paths with each request
+	if len(options.InPath) == 0 && command != cmdServe {
 		showUsageAndExit(impl, "path not specified")
 	}
 
+	if len(options.CodeGenerators) > 0 && (command == cmdVersionCheck || command == cmdDiff) {
+		showUsageAndExit(impl, fmt.Sprintf("multiple output languages can't be combined with the %s subcommand", command))
+	}
+
+	if config != nil && config.LintRules != nil {
+		options.LintRules = config.LintRules
+	}
+
+	if len(lintConfig) > 0 {
+		rules, err := generator.LoadLintRules(lintConfig)
+		stopOnError(0, err)
+		// rules of the -lint-config file take precedence over the ones in the config file
+		if options.LintRules == nil {
+			options.LintRules = rules
+		} else {
+			for name, severity := range rules {
+				options.LintRules[name] = severity
+			}
+		}
+	}
+
+	if options.PathStyle != generator.PathStyleNative && options.PathStyle != generator.PathStyleSlash {
+		showUsageAndExit(impl, fmt.Sprintf("argument -path-style must be one of: %s; got %s", strings.Join(generator.PathStyles, ", "), options.PathStyle))
+	}
+
+	if len(options.UidSalt) > 0 && !options.DeterministicUids {
+		showUsageAndExit(impl, "argument -uid-salt is only allowed in combination with -deterministic-uids")
+	}
+
+	if len(options.BundleFile) > 0 && (command != cmdGenerate || options.OutPath == "-") {
+		showUsageAndExit(impl, "argument -bundle is only supported by the generate command, without writing to stdout (-out -)")
+	}
+
+	if options.OutPath == "-" {
+		if command != cmdGenerate || jsonOutput {
+			showUsageAndExit(impl, "writing to stdout (-out -) is only supported by the generate command, without -json")
+		}
+		streamConfig.fromStdin = options.InPath == "-"
+		stream = &streamConfig
+	} else if options.InPath == "-" {
+		showUsageAndExit(impl, "reading from stdin (-in -) requires writing to stdout (-out -)")
+	}
+
 	if len(args) > 0 {
 		showUsageAndExit(impl, "unknown arguments", args)
 	}
 
 	return
 }
+
+// configPathFlags are the flags holding paths, these are relative to the config file if given in it
+var configPathFlags = map[string]bool{
+	"in": true, "out": true, "out-headers": true, "manifest": true, "admin-metadata": true, "bundle": true, "model": true, "persist": true,
+	"module-model": true, "template-overrides": true, "lint-config": true, "messages": true, "html": true,
+	"I": true, "include-dir": true, "typeMappings": true,
+}
+
+// configLanguageFlags select the output languages: if any is given on the command line, the config file ones are ignored
+var configLanguageFlags = map[string]bool{"c": true, "cpp": true, "cpp11": true, "js": true, "go": true, "docs": true, "lang": true}
+
+// applyConfig sets the flags not given on the command line from the given config file or the one found by
+// generator.FindConfigFile(sourcePath); returns nil if there's no config file.
+func applyConfig(configFile string, sourcePath string) (*generator.Config, error) {
+	if configFile == "none" {
+		return nil, nil
+	} else if len(configFile) == 0 {
+		if len(sourcePath) == 0 || sourcePath == "-" {
+			sourcePath = "."
+		}
+		var err error
+		if configFile, err = generator.FindConfigFile(sourcePath); err != nil || len(configFile) == 0 {
+			return nil, err
+		}
+	}
+
+	config, err := generator.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var givenFlags = make(map[string]bool)
+	var givenLanguage bool
+	flag.Visit(func(f *flag.Flag) {
+		givenFlags[f.Name] = true
+		givenLanguage = givenLanguage || configLanguageFlags[f.Name]
+	})
+
+	for _, configFlag := range config.Flags {
+		if configFlag.Name == "config" || configFlag.Name == "help" || flag.Lookup(configFlag.Name) == nil {
+			return nil, fmt.Errorf("invalid config file %s: unknown flag %s", configFlag.Position, configFlag.Name)
+		} else if givenFlags[configFlag.Name] || (givenLanguage && configLanguageFlags[configFlag.Name]) {
+			continue
+		}
+
+		for _, value := range configFlag.Values {
+			if configPathFlags[configFlag.Name] && len(value) > 0 && value != "-" && !filepath.IsAbs(value) {
+				value = filepath.Join(filepath.Dir(configFile), value)
+			}
+			if err := flag.Set(configFlag.Name, value); err != nil {
+				return nil, fmt.Errorf("invalid config file %s: invalid value %q of flag %s: %s", configFlag.Position, value, configFlag.Name, err)
+			}
+		}
+	}
+	return config, nil
+}
@@ -33,6 +33,7 @@ import (
 	generatorcmd "github.com/objectbox/objectbox-generator/v4/cmd"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
 	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc"
 	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
 	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
@@ -49,14 +50,45 @@ func main() {
 // implements generatorcmd.generatorCommand
 type command struct {
 	langs                map[string]*bool
+	lang                 stringList // -lang, e.g. "cpp,js"; combined with the individual language flags
 	optional             *string
 	empty_string_as_null *bool // pointers due to flag API (https://pkg.go.dev/flag#Bool)
 	nan_as_null          *bool
+	strict_schema        *bool
+	out_pattern          *string
+	extension_hooks      *bool
+	json_helpers         *bool
+	accessors            *bool
+	namespace_modules    *bool
+	module_format        *string
+	browser_safe         *bool
+	verify_flatbuffers   *bool
+	reuse_buffers        *bool
+	pmr                  *bool
+	thread_safety        *bool
+	cmake_file           *bool
+	typed_arrays         *bool
+	id_type              *string
+	validation           *bool
+	docs_format          *string
+	include_dirs         stringList
+}
+
+// stringList implements flag.Value for flags which can be given multiple times, e.g. "-I dir1 -I dir2"
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *stringList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
 }
 
 func (cmd command) ShowUsage() {
 	fmt.Fprint(flag.CommandLine.Output(), `Usage:
-  objectbox-generator [flags] {path}
+  objectbox-generator [flags] [generate] {path}
       * to execute "clean" action (see below) on the path, removing previously generated code and missing entities,
       * and execute code generation on the path afterwards.
 
@@ -67,14 +99,92 @@ func (cmd command) ShowUsage() {
 
 
 or
-  objectbox-generator [flags] {model/file/path.fbs}
-      to generate the binding code for a single file
+  objectbox-generator [flags] [generate] {model/file/path.fbs}
+      to generate the binding code for a single file;
+      C, C++ and JS: the schema may also be a YAML file named like "model.schema.yaml"
+
+
+or
+  objectbox-generator [flags] -out - {model/file/path.fbs}
+  objectbox-generator [flags] -in - -out -
+      to generate the binding code for a single file (or a schema read from stdin) and write all generated files,
+      including the updated model JSON, to stdout (see -out-format), e.g. for build sandboxes like Bazel
 
 
 or
   objectbox-generator [flags] clean {path}
       to remove the generated files instead of creating them - this removes *.obx.* and objectbox-model.h but keeps objectbox-model.json
 
+or
+  objectbox-generator [flags] validate {path}
+      to check the sources and their compatibility with objectbox-model.json without writing any files
+
+or
+  objectbox-generator [flags] [-html report.html] model-diff {path}
+      to list the changes the generation would make to objectbox-model.json, without writing any files (except for the
+      optional HTML report showing the entities colored by added/removed/changed, e.g. for CI artifacts)
+
+or
+  objectbox-generator [flags] lint {path}
+      to check the sources for common pitfalls; rule severities can be configured using -lint-config
+
+or
+  objectbox-generator [flags] diff {path}
+      to print unified diffs between the generated files on disk (including objectbox-model.json) and the output
+      the generation would produce, without writing any files, e.g. to review the impact of schema changes in CI
+
+or
+  objectbox-generator [flags] [-format tree|table|json|yaml] inspect {path}
+      to print the model read from the sources: entities, properties with their IDs/UIDs, types and flags, indexes and
+      relations, without writing any files, e.g. to debug annotations
+
+or
+  objectbox-generator [flags] [-objects [Entity=]count] [-avg-size Type=bytes] estimate {path}
+      to estimate the storage size of the entities (FlatBuffers data, indexes and HNSW graphs) for capacity planning,
+      e.g. before deploying to constrained devices, without writing any files
+
+or
+  objectbox-generator [-model {file}] fmt-model {path}
+      to rewrite an existing objectbox-model.json in the canonical format written by the generator (fixed indentation,
+      sorted retired UIDs, trailing newline), e.g. after merging it by hand; {path} is the model file or its directory
+
+or
+  objectbox-generator [-model {file}] retired-report {path}
+      to list the entities, properties, indexes and relations retired from objectbox-model.json per model version, e.g.
+      to audit which data was dropped when; their names are recovered from the git history of the model file if available
+
+or
+  objectbox-generator model-merge {base} {ours} {theirs}
+      to merge the changes made to the model JSON file {base} in {theirs} into {ours}, e.g. both branches adding entities:
+      elements are matched by UID and those added in {theirs} get new IDs if needed (regenerate their bindings).
+      Fails without writing {ours} if both sides changed the same element. To use it as a git merge driver, add
+      "objectbox-model.json merge=objectbox-model" to .gitattributes and run:
+      git config merge.objectbox-model.driver "objectbox-generator model-merge %O %A %B"
+
+or
+  objectbox-generator [flags] version-check {path}
+      to list generated files which don't match the current generator version (or its templates), failing if any are
+      found; use -regenerate to regenerate the bindings instead. {path} is scanned for generated files unless -out is given
+
+or
+  objectbox-generator [flags] verify-build-info {path}
+      to list generated files whose build info (written with -build-info) doesn't match the current generator version,
+      their input file or the given flags, failing if any are found, e.g. to check a committed tree is up to date in CI
+
+or
+  objectbox-generator [flags] [-listen unix:{socket}] serve
+      to run a long-lived JSON-RPC 2.0 server (newline-delimited messages on stdin/stdout or the -listen socket), e.g.
+      for IDE plugins and build daemons; methods are the subcommands (e.g. generate, validate, inspect) and "shutdown",
+      params: {"path": ..., "model": ..., "out": ...}; the flags given here (e.g. -cpp) apply to all requests
+
+or
+  objectbox-generator [-json] version
+      to print the generator version info
+
+or
+  objectbox-generator [-json] [-messages translations.json] errors
+      to list the codes of the generator error messages (e.g. OBXG1005, also printed with each error) and their texts
+
 or
   objectbox-generator FLATC [flatc arguments]
       to execute FlatBuffers flatc command line tool Any arguments after the FLATC keyword are passed through.
@@ -94,63 +204,280 @@ func (cmd *command) ConfigureFlags() {
 	cmd.langs["cpp11"] = flag.Bool("cpp11", false, "generate C++11 code")
 	cmd.langs["js"] = flag.Bool("js", false, "generate JS code")
 	cmd.langs["go"] = flag.Bool("go", false, "generate Go code")
+	cmd.langs["docs"] = flag.Bool("docs", false, "generate model documentation (a page per entity) from FlatBuffers schema, see -docs-format")
+	flag.Var(&cmd.lang, "lang", "comma-separated list of languages to generate in a single run, e.g. cpp,js (may be given multiple times);\n"+
+		"the language flags (e.g. -cpp -js) may be combined the same way. All targets share one model merge, keeping their outputs consistent")
 
 	// for c++ generator
-	cmd.optional = flag.String("optional", "", "C++ wrapper type to use for fields annotated \"optional\"; one of: std::optional, std::unique_ptr, std::shared_ptr")
+	cmd.optional = flag.String("optional", "", "C++ wrapper type to use for fields annotated \"optional\"; one of: std::optional, std::unique_ptr, std::shared_ptr;\n"+
+		"C: representation of fields annotated \"optional\"; one of: ptr (a pointer, the default), flag (a value and a has_<field> flag)")
 	cmd.empty_string_as_null = flag.Bool("empty-string-as-null", false, "C++: empty strings are treated as 0 (null)")
 	cmd.nan_as_null = flag.Bool("nan-as-null", false, "C++: NaNs are treated as 0 (null)")
+	cmd.out_pattern = flag.String("out-pattern", "", "C, C++: generate a binding file per entity, named by the given pattern, e.g. \"{{.Entity}}.obx.{{.Ext}}\"; available fields: Entity, Source, Ext")
+
+	cmd.namespace_modules = flag.Bool("namespace-modules", false, "JS: generate a module per FlatBuffers namespace, in a directory named by the namespace (e.g. shop/schema.obx.js), re-exported by the parent module")
+
+	cmd.module_format = flag.String("module-format", jsgenerator.ModuleFormatESM, "JS: module format of the generated code; one of: esm (import/export), commonjs (require/module.exports)")
+	cmd.typed_arrays = flag.Bool("typed-arrays", false, "JS: read float vectors as Float32Array (a view of the buffer if possible) and write typed arrays without converting them, e.g. for vector search")
+	cmd.id_type = flag.String("id-type", jsgenerator.IdTypeBigInt, "JS: type of the ID fields of the generated classes; one of: bigint, string (decimal strings, e.g. for JSON APIs);\n"+
+		"IDs are unsigned 64-bit integers which a JS number can't hold without losing precision")
+	cmd.validation = flag.Bool("validation", false, "JS: generate validate<Entity>(object) functions checking required properties, value types and vector dimensions;\n"+
+		"toFlatbuffers() calls them and throws a ValidationError listing the problems instead of failing while writing")
+	cmd.browser_safe = flag.Bool("browser-safe", false, "JS: avoid Node-only APIs (e.g. node:perf_hooks, process) in the generated code")
+
+	cmd.docs_format = flag.String("docs-format", docsgenerator.FormatMarkdown, "docs: output format; one of: md, html")
+
+	cmd.extension_hooks = flag.Bool("extension-hooks", false, "C++, JS: let users extend the generated entity types by optional <Entity>.custom.hpp/.js files")
+	cmd.json_helpers = flag.Bool("json-helpers", false, "C++: generate to_json()/from_json() functions for the entities, serializing them using nlohmann::json")
+	cmd.verify_flatbuffers = flag.Bool("verify-flatbuffers", false, "C, C++, JS: verify FlatBuffers before reading objects from them (bounds checks, UTF-8 strings), e.g. for untrusted input; costs read performance")
+	cmd.reuse_buffers = flag.Bool("reuse-buffers", false, "C++: reading into existing objects (e.g. box.get(id, object) or the generated Entity_::getInto()) reuses their strings and vectors instead of allocating new ones")
+	cmd.pmr = flag.Bool("pmr", false, "C++: use std::pmr::string and std::pmr::vector members (C++17 polymorphic memory resources), e.g. for allocator-aware applications;\n"+
+		"can be changed per entity by the \"pmr\" annotation")
+	cmd.thread_safety = flag.Bool("thread-safety", false, "C++: annotate the generated query helpers with clang thread safety attributes (checked by -Wthread-safety), requiring\n"+
+		"an open transaction, and generate Entity_::ReadTx and Entity_::WriteTx RAII transaction helpers holding it")
+	cmd.cmake_file = flag.Bool("cmake-file", false, "C, C++: additionally write objectbox-generated.cmake (to -out or next to the model JSON) listing the schema and generated files,\n"+
+		"with an add_objectbox_schema(<target> [REGENERATE]) function adding them to a CMake target")
+	cmd.accessors = flag.Bool("accessors", false, "C++, JS: generate private members with get/set accessors instead of public members; can be changed per entity by the \"accessors\" annotation")
+
+	// for generators reading FlatBuffers schema
+	cmd.strict_schema = flag.Bool("strict-schema", false, "C, C++, JS: fail on FlatBuffers schema features ignored by ObjectBox (required, key, nested_flatbuffer)")
+	flag.Var(&cmd.include_dirs, "I", "C, C++, JS: a directory to look up files included by the schema (e.g. include \"common.fbs\";) in; may be given multiple times")
+	flag.Var(&cmd.include_dirs, "include-dir", "same as -I")
 }
 
-func (cmd *command) ParseFlags(remainingPosArgs *[]string, options *generator.Options) error {
-	var selectedLang string
+// languages lists the supported output languages, in the order they're generated when multiple are selected
+var languages = []string{"c", "cpp", "cpp11", "js", "go", "docs"}
+
+// selectedLangs returns the languages selected by the individual flags (e.g. -cpp) and -lang, in the languages order
+func (cmd *command) selectedLangs() ([]string, error) {
+	var selected = make(map[string]bool)
 	for lang, val := range cmd.langs {
 		if *val {
-			if len(selectedLang) != 0 {
-				return fmt.Errorf("only one output language can be specified at the moment, you've selected %s and %s", selectedLang, lang)
+			selected[lang] = true
+		}
+	}
+	for _, list := range cmd.lang {
+		for _, lang := range strings.Split(list, ",") {
+			lang = strings.TrimSpace(lang)
+			if _, known := cmd.langs[lang]; !known {
+				return nil, fmt.Errorf("argument -lang must be a comma-separated list of: %s; got %s", strings.Join(languages, ", "), lang)
 			}
-			selectedLang = lang
+			selected[lang] = true
+		}
+	}
+
+	var result []string
+	for _, lang := range languages {
+		if selected[lang] {
+			result = append(result, lang)
 		}
 	}
+	return result, nil
+}
 
-	if len(*cmd.optional) != 0 && selectedLang != "cpp" {
-		return errors.New("argument -optional is only allowed in combination with -cpp")
+func (cmd *command) ParseFlags(remainingPosArgs *[]string, options *generator.Options) error {
+	selectedLangs, err := cmd.selectedLangs()
+	if err != nil {
+		return err
+	} else if len(selectedLangs) == 0 {
+		return errors.New("you must specify an output language")
 	}
 
-	switch selectedLang {
+	// checks whether any of the given languages is selected, i.e. whether an argument is applicable
+	var anySelected = func(langs ...string) bool {
+		for _, selected := range selectedLangs {
+			for _, lang := range langs {
+				if selected == lang {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if len(*cmd.optional) != 0 && !anySelected("cpp", "c") {
+		return errors.New("argument -optional is only allowed in combination with -cpp or -c")
+	}
+
+	var cOptional = "ptr"
+	if anySelected("c") && len(*cmd.optional) != 0 {
+		if *cmd.optional != "ptr" && *cmd.optional != "flag" {
+			return fmt.Errorf("argument -optional for -c must be one of: ptr, flag; got %s", *cmd.optional)
+		}
+		cOptional = *cmd.optional
+	}
+
+	if *cmd.strict_schema && !anySelected("c", "cpp", "cpp11", "js", "docs") {
+		return errors.New("argument -strict-schema is only allowed in combination with FlatBuffers schema based languages: -c, -cpp, -cpp11, -js")
+	}
+
+	if len(cmd.include_dirs) != 0 && !anySelected("c", "cpp", "cpp11", "js", "docs") {
+		return errors.New("argument -I (-include-dir) is only allowed in combination with FlatBuffers schema based languages: -c, -cpp, -cpp11, -js")
+	}
+
+	if len(*cmd.out_pattern) != 0 {
+		if !anySelected("c", "cpp", "cpp11") {
+			return errors.New("argument -out-pattern is only allowed in combination with -c, -cpp, -cpp11")
+		}
+		options.OutPattern = *cmd.out_pattern
+	}
+
+	if *cmd.extension_hooks && !anySelected("cpp", "cpp11", "js") {
+		return errors.New("argument -extension-hooks is only allowed in combination with -cpp, -cpp11, -js")
+	}
+
+	if *cmd.json_helpers && !anySelected("cpp", "cpp11") {
+		return errors.New("argument -json-helpers is only allowed in combination with -cpp, -cpp11")
+	}
+
+	if *cmd.verify_flatbuffers && !anySelected("c", "cpp", "cpp11", "js") {
+		return errors.New("argument -verify-flatbuffers is only allowed in combination with -c, -cpp, -cpp11, -js")
+	}
+
+	if *cmd.reuse_buffers && !anySelected("cpp", "cpp11") {
+		return errors.New("argument -reuse-buffers is only allowed in combination with -cpp, -cpp11")
+	}
+
+	if *cmd.thread_safety && !anySelected("cpp", "cpp11") {
+		return errors.New("argument -thread-safety is only allowed in combination with -cpp, -cpp11")
+	}
+
+	if *cmd.pmr && !anySelected("cpp") {
+		return errors.New("argument -pmr is only allowed in combination with -cpp")
+	}
+
+	if *cmd.cmake_file && !anySelected("c", "cpp", "cpp11") {
+		return errors.New("argument -cmake-file is only allowed in combination with -c, -cpp, -cpp11")
+	}
+
+	if *cmd.accessors && !anySelected("cpp", "cpp11", "js") {
+		return errors.New("argument -accessors is only allowed in combination with -cpp, -cpp11, -js")
+	}
+
+	if *cmd.namespace_modules && !anySelected("js") {
+		return errors.New("argument -namespace-modules is only allowed in combination with -js")
+	}
+
+	if *cmd.module_format != jsgenerator.ModuleFormatESM {
+		if !anySelected("js") {
+			return errors.New("argument -module-format is only allowed in combination with -js")
+		} else if *cmd.module_format != jsgenerator.ModuleFormatCommonJS {
+			return fmt.Errorf("argument -module-format must be one of: esm, commonjs; got %s", *cmd.module_format)
+		}
+	}
+
+	if *cmd.typed_arrays && !anySelected("js") {
+		return errors.New("argument -typed-arrays is only allowed in combination with -js")
+	}
+
+	if *cmd.id_type != jsgenerator.IdTypeBigInt {
+		if !anySelected("js") {
+			return errors.New("argument -id-type is only allowed in combination with -js")
+		} else if *cmd.id_type != jsgenerator.IdTypeString {
+			return fmt.Errorf("argument -id-type must be one of: bigint, string; got %s", *cmd.id_type)
+		}
+	}
+
+	if *cmd.validation && !anySelected("js") {
+		return errors.New("argument -validation is only allowed in combination with -js")
+	}
+
+	if *cmd.browser_safe && !anySelected("js") {
+		return errors.New("argument -browser-safe is only allowed in combination with -js")
+	}
+
+	if anySelected("docs") && *cmd.docs_format != docsgenerator.FormatMarkdown && *cmd.docs_format != docsgenerator.FormatHtml {
+		return fmt.Errorf("argument -docs-format must be one of: md, html; got %s", *cmd.docs_format)
+	}
+
+	var codeGenerators []generator.CodeGenerator
+	for _, lang := range selectedLangs {
+		codeGenerators = append(codeGenerators, cmd.codeGenerator(lang, cOptional))
+	}
+
+	if len(codeGenerators) == 1 {
+		options.CodeGenerator = codeGenerators[0]
+	} else {
+		// generated in a single run, sharing the model (merged and written once) and the parsed sources
+		options.CodeGenerators = codeGenerators
+	}
+	return nil
+}
+
+// codeGenerator creates the code generator for the given (selected) language, configured by the parsed flags
+func (cmd *command) codeGenerator(lang string, cOptional string) generator.CodeGenerator {
+	switch lang {
 	case "go":
-		options.CodeGenerator = &gogenerator.GoGenerator{}
+		return &gogenerator.GoGenerator{}
 	case "c":
-		options.CodeGenerator = &cgenerator.CGenerator{
-			PlainC:      true,
-			LangVersion: -1,    // unspecified, take the default
-			Optional:    "ptr", // dummy value for checks to evaluate to true if "optional" annotation is used
+		return &cgenerator.CGenerator{
+			PlainC:            true,
+			LangVersion:       -1, // unspecified, take the default
+			Optional:          cOptional,
+			StrictSchema:      *cmd.strict_schema,
+			VerifyFlatBuffers: *cmd.verify_flatbuffers,
+			IncludeDirs:       cmd.include_dirs,
+			CMakeFile:         *cmd.cmake_file,
 		}
 	case "cpp":
-		options.CodeGenerator = &cgenerator.CGenerator{
+		return &cgenerator.CGenerator{
 			PlainC:            false,
 			LangVersion:       14,
 			Optional:          *cmd.optional,
 			EmptyStringAsNull: *cmd.empty_string_as_null,
 			NaNAsNull:         *cmd.nan_as_null,
+			StrictSchema:      *cmd.strict_schema,
+			ExtensionHooks:    *cmd.extension_hooks,
+			Accessors:         *cmd.accessors,
+			JsonHelpers:       *cmd.json_helpers,
+			VerifyFlatBuffers: *cmd.verify_flatbuffers,
+			ReuseBuffers:      *cmd.reuse_buffers,
+			Pmr:               *cmd.pmr,
+			ThreadSafety:      *cmd.thread_safety,
+			IncludeDirs:       cmd.include_dirs,
+			CMakeFile:         *cmd.cmake_file,
 		}
 	case "cpp11":
-		options.CodeGenerator = &cgenerator.CGenerator{
+		return &cgenerator.CGenerator{
 			PlainC:            false,
 			LangVersion:       11,
 			Optional:          *cmd.optional,
 			EmptyStringAsNull: *cmd.empty_string_as_null,
 			NaNAsNull:         *cmd.nan_as_null,
+			StrictSchema:      *cmd.strict_schema,
+			ExtensionHooks:    *cmd.extension_hooks,
+			Accessors:         *cmd.accessors,
+			JsonHelpers:       *cmd.json_helpers,
+			VerifyFlatBuffers: *cmd.verify_flatbuffers,
+			ReuseBuffers:      *cmd.reuse_buffers,
+			ThreadSafety:      *cmd.thread_safety,
+			IncludeDirs:       cmd.include_dirs,
+			CMakeFile:         *cmd.cmake_file,
 		}
 	case "js":
-		options.CodeGenerator = &jsgenerator.JSGenerator{
+		return &jsgenerator.JSGenerator{
 			Optional:          *cmd.optional,
 			EmptyStringAsNull: *cmd.empty_string_as_null,
 			NaNAsNull:         *cmd.nan_as_null,
+			StrictSchema:      *cmd.strict_schema,
+			ExtensionHooks:    *cmd.extension_hooks,
+			Accessors:         *cmd.accessors,
+			NamespaceModules:  *cmd.namespace_modules,
+			IncludeDirs:       cmd.include_dirs,
+			ModuleFormat:      *cmd.module_format,
+			BrowserSafe:       *cmd.browser_safe,
+			TypedArrays:       *cmd.typed_arrays,
+			IdType:            *cmd.id_type,
+			Validation:        *cmd.validation,
+			VerifyFlatBuffers: *cmd.verify_flatbuffers,
+		}
+	case "docs":
+		return &docsgenerator.DocsGenerator{
+			Format: *cmd.docs_format,
+			Source: &cgenerator.CGenerator{LangVersion: 14, StrictSchema: *cmd.strict_schema, IncludeDirs: cmd.include_dirs},
 		}
-	default:
-		return errors.New("you must specify an output language")
 	}
-	return nil
+	panic("unknown language " + lang)
 }
 
 // runFlatcIfRequested checks command line arguments and if they start with FLATC, executes flatc compiler with the remainder of the arguments
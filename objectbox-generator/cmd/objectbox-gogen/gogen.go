@@ -23,6 +23,7 @@
 package gogen
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -40,19 +41,80 @@ func Main() {
 
 // implements generatorcmd.generatorCommand
 type command struct {
-	byValue bool
+	byValue          bool
+	typeMappings     string
+	fbs              bool
+	noAutoConverters bool
+	entityHelpers    bool
+	tinyGoCheck      bool
+	perPackage       bool
 }
 
 func (cmd command) ShowUsage() {
 	fmt.Fprint(flag.CommandLine.Output(), `Usage:
-	objectbox-gogen [flags] {source-file}
+	objectbox-gogen [flags] [generate] {source-file}
 		to generate the binding code
 
+or
+
+	objectbox-gogen [flags] -perPackage [generate] {path}
+		to generate the binding code for each package with entities separately, e.g. for all packages of a monorepo using ./...
+
 or
 
 	objectbox-gogen clean {path}
 		to remove the generated files instead of creating them - this removes *.obx.go and objectbox-model.go but keeps objectbox-model.json
 
+or
+
+	objectbox-gogen [flags] validate|model-diff|lint|diff {path}
+		to check the sources against objectbox-model.json or list the changes to it (or to all the generated files), without writing any files
+
+or
+
+	objectbox-gogen [-format tree|table|json|yaml] inspect {path}
+		to print the model read from the sources, e.g. to debug annotations, without writing any files
+
+or
+
+	objectbox-gogen [-objects [Entity=]count] [-avg-size Type=bytes] estimate {path}
+		to estimate the storage size of the entities for capacity planning, without writing any files
+
+or
+
+	objectbox-gogen [-regenerate] version-check {path}
+		to list generated files which don't match the current generator version (and regenerate them with -regenerate)
+
+or
+
+	objectbox-gogen [flags] verify-build-info {path}
+		to list generated files whose build info (written with -build-info) doesn't match the generator, the sources or the flags
+
+or
+
+	objectbox-gogen [-listen unix:{socket}] serve
+		to run a long-lived JSON-RPC 2.0 server on stdin/stdout (or the socket) running the subcommands on request
+
+or
+
+	objectbox-gogen fmt-model {path}
+		to rewrite objectbox-model.json (or the given model file) in the canonical format, e.g. after merging it by hand
+
+or
+
+	objectbox-gogen retired-report {path}
+		to list the elements retired from objectbox-model.json per model version, named using its git history if available
+
+or
+
+	objectbox-gogen model-merge {base} {ours} {theirs}
+		to merge the changes of objectbox-model.json {base} in {theirs} into {ours}, e.g. as a git merge driver (%O %A %B)
+
+or
+
+	objectbox-gogen [-json] version
+		to print the generator version info
+
 path:
   * a source file path or a valid path pattern as accepted by the go tool (e.g. ./...)
   * if not given, the generator expects GOFILE environment variable to be set
@@ -64,11 +126,41 @@ Available flags:
 
 func (cmd *command) ConfigureFlags() {
 	flag.BoolVar(&cmd.byValue, "byValue", false, "getters should return a struct value (a copy) instead of a struct pointer")
+	flag.StringVar(&cmd.typeMappings, "typeMappings", "", "optional: JSON file mapping user-defined types to the stored type and converter,\n"+
+		"e.g. {\"decimal.Decimal\": {\"type\": \"string\", \"converter\": \"decimalString\"}};\n"+
+		"generic containers are mapped by how to unwrap and wrap the value, e.g. {\"Optional[T]\": {\"unwrap\": \"%s.Value\", \"wrap\": \"Some(%s)\"}}")
+	flag.BoolVar(&cmd.fbs, "fbs", false, "additionally write an equivalent FlatBuffers schema (<source>.obx.fbs) describing the entities, e.g. for ObjectBox in other languages")
+	flag.BoolVar(&cmd.entityHelpers, "entityHelpers", false, "additionally generate String(), Equal() and Clone() methods for each entity, e.g. for tests and logging")
+	flag.BoolVar(&cmd.noAutoConverters, "noAutoConverters", false, "don't store well-known types (time.Time, uuid.UUID, *big.Int) automatically\n"+
+		"using generated converters, annotate such fields explicitly instead")
+	flag.BoolVar(&cmd.tinyGoCheck, "tinyGoCheck", false, "check that the entities don't use features TinyGo can't compile, e.g. for embedded devices:\n"+
+		"features relying on reflection (map fields without a custom converter, -entityHelpers, -export) are rejected;\n"+
+		"the generated code is the same as without the flag, it still needs the objectbox-go runtime")
+	flag.BoolVar(&cmd.perPackage, "perPackage", false, "generate each package matched by the path (e.g. ./...) separately, with its own objectbox-model.json and objectbox-model.go;\n"+
+		"packages without entities are skipped, with -out the generated files are written to the same relative directory under it")
 }
 
 func (cmd *command) ParseFlags(remainingPosArgs *[]string, options *generator.Options) error {
-	options.CodeGenerator = &gogenerator.GoGenerator{
-		ByValue: cmd.byValue,
+	var gen = &gogenerator.GoGenerator{
+		ByValue:          cmd.byValue,
+		Fbs:              cmd.fbs,
+		NoAutoConverters: cmd.noAutoConverters,
+		EntityHelpers:    cmd.entityHelpers,
+		TinyGoCheck:      cmd.tinyGoCheck,
+	}
+	if len(cmd.typeMappings) > 0 {
+		var err error
+		if gen.TypeMappings, err = gogenerator.LoadTypeMappings(cmd.typeMappings); err != nil {
+			return err
+		}
+	}
+	options.CodeGenerator = gen
+
+	if cmd.perPackage {
+		if options.OutPath == "-" {
+			return errors.New("argument -perPackage can't be combined with writing to stdout (-out -)")
+		}
+		options.PerPackage = true
 	}
 
 	if len(options.InPath) == 0 {
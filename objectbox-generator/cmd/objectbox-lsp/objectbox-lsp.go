@@ -0,0 +1,46 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package main provides the objectbox-lsp executable, a language server for ObjectBox annotations in Go sources and
+// FlatBuffers schemas, communicating over stdin and stdout; see the lsp package for the supported features.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/internal/lsp"
+)
+
+func main() {
+	var version = flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+	if *version {
+		fmt.Printf("ObjectBox language server v%s #%d\n", generator.Version, generator.VersionId)
+		return
+	}
+
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
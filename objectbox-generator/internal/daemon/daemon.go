@@ -0,0 +1,260 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package daemon implements a long-running JSON-RPC 2.0 server running generator commands (generate, validate,
+// inspect, ...) on request, e.g. for IDE plugins and build daemons (Gradle, Bazel workers) that would otherwise spawn
+// a generator process per file. Messages are newline-delimited JSON, read from stdin or a local socket.
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+)
+
+// request is an incoming JSON-RPC message; requests without an ID are notifications, which get no response
+type request struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes
+const (
+	errParse          = -32700
+	errMethodNotFound = -32601
+	errInvalidParams  = -32602
+)
+
+// Params select the sources of a request; the output languages and other options are given by Server.NewOptions
+type Params struct {
+	Path       string `json:"path"`
+	Model      string `json:"model,omitempty"`      // the model JSON file, see Options.ModelInfoFile
+	Out        string `json:"out,omitempty"`        // see Options.OutPath
+	OutHeaders string `json:"outHeaders,omitempty"` // see Options.OutHeadersPath
+}
+
+// Server runs the requested commands one at a time; "shutdown" stops serving.
+// Note: the generator prints messages to generator.Options.Output, set it in NewOptions when serving on stdin/stdout.
+type Server struct {
+	// Methods lists the supported commands, e.g. "generate" or "validate"; "shutdown" is always supported
+	Methods []string
+
+	// PathOptional lists the methods that don't require Params.Path, e.g. "version"
+	PathOptional []string
+
+	// NewOptions creates the options for a request, e.g. with fresh code generators configured by command line flags
+	NewOptions func() (generator.Options, error)
+
+	// Run executes the command, returning its result; failures are part of the result, e.g. with diagnostics
+	Run func(method string, options generator.Options) interface{}
+
+	// the generator isn't safe for concurrent use, connections share the lock
+	lock sync.Mutex
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// NewServer creates a server for the given methods
+func NewServer(methods []string, newOptions func() (generator.Options, error), run func(method string, options generator.Options) interface{}) *Server {
+	return &Server{
+		Methods:    methods,
+		NewOptions: newOptions,
+		Run:        run,
+		shutdown:   make(chan struct{}),
+	}
+}
+
+// Listen accepts connections on the given local socket, e.g. "unix:/tmp/objectbox.sock" or "tcp:localhost:7000",
+// serving them concurrently (running the commands one at a time) until a client requests "shutdown".
+// Only unix sockets and TCP on loopback addresses are accepted: clients can write and remove files (e.g. generate and
+// clean) on any path the server can access, so they must not be reachable from other machines.
+func (server *Server) Listen(address string) error {
+	network, localAddress, err := parseLocalAddress(address)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen(network, localAddress)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-server.shutdown
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-server.shutdown:
+				return nil
+			default:
+				return err
+			}
+		}
+		go func() {
+			defer conn.Close()
+			server.Serve(conn, conn) // errors only affect this connection, e.g. a client disconnecting
+		}()
+	}
+}
+
+// Serve processes messages, one per line, until the input is closed or "shutdown" is requested
+func (server *Server) Serve(reader io.Reader, writer io.Writer) error {
+	var scanner = bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		var resp *response
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			resp = &response{Error: &responseError{errParse, "invalid message: " + err.Error()}}
+		} else {
+			resp = server.handle(&req)
+		}
+
+		if resp != nil {
+			resp.JSONRPC = "2.0"
+			data, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			} else if _, err = fmt.Fprintf(writer, "%s\n", data); err != nil {
+				return err
+			}
+		}
+
+		if req.Method == "shutdown" {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// handle runs the requested command, returning nil for notifications
+func (server *Server) handle(req *request) *response {
+	var resp = &response{ID: req.ID}
+	if req.Method == "shutdown" {
+		server.shutdownOnce.Do(func() { close(server.shutdown) })
+		resp.Result = map[string]bool{"success": true}
+	} else if !contains(server.Methods, req.Method) {
+		resp.Error = &responseError{errMethodNotFound, fmt.Sprintf("method not supported: %s; expecting one of: %s, shutdown",
+			req.Method, strings.Join(server.Methods, ", "))}
+	} else if options, err := server.requestOptions(req); err != nil {
+		resp.Error = &responseError{errInvalidParams, err.Error()}
+	} else {
+		server.lock.Lock()
+		resp.Result = server.Run(req.Method, options)
+		server.lock.Unlock()
+	}
+
+	if req.ID == nil {
+		return nil
+	}
+	return resp
+}
+
+func (server *Server) requestOptions(req *request) (generator.Options, error) {
+	var params Params
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return generator.Options{}, fmt.Errorf("invalid params: %s", err)
+		}
+	}
+	if len(params.Path) == 0 && !contains(server.PathOptional, req.Method) {
+		return generator.Options{}, fmt.Errorf("params.path not specified")
+	}
+
+	options, err := server.NewOptions()
+	if err != nil {
+		return options, err
+	}
+	if len(params.Path) > 0 {
+		options.InPath = params.Path
+	}
+	if len(params.Model) > 0 {
+		options.ModelInfoFile = params.Model
+	}
+	if len(params.Out) > 0 {
+		options.OutPath = params.Out
+	}
+	if len(params.OutHeaders) > 0 {
+		options.OutHeadersPath = params.OutHeaders
+	}
+	return options, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLocalAddress splits the "network:address" given to Listen(), rejecting addresses reachable from other machines
+func parseLocalAddress(address string) (string, string, error) {
+	var separator = strings.Index(address, ":")
+	if separator <= 0 {
+		return "", "", fmt.Errorf("invalid address %q, expecting network:address, e.g. unix:/tmp/objectbox.sock or tcp:localhost:7000", address)
+	}
+	var network, localAddress = address[:separator], address[separator+1:]
+
+	switch network {
+	case "unix":
+		return network, localAddress, nil
+	case "tcp", "tcp4", "tcp6":
+		host, _, err := net.SplitHostPort(localAddress)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid address %q: %s", address, err)
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return "", "", fmt.Errorf("invalid address %q: only loopback TCP addresses (e.g. localhost or 127.0.0.1) are allowed, "+
+				"the server must not be reachable from other machines", address)
+		}
+		return network, localAddress, nil
+	}
+	return "", "", fmt.Errorf("invalid address %q: unsupported network %q, expecting unix or tcp", address, network)
+}
@@ -0,0 +1,86 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/daemon"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestDaemon(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "daemon")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+
+	var server = daemon.NewServer([]string{"generate", "validate"}, func() (generator.Options, error) {
+		return generator.Options{CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}, nil
+	}, func(method string, options generator.Options) interface{} {
+		var err error
+		if method == "generate" {
+			err = generator.Process(options)
+		} else {
+			_, err = generator.Validate(options)
+		}
+		return map[string]interface{}{"path": options.InPath, "success": err == nil}
+	})
+
+	var input = strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "validate", "params": {"path": ` + strconv.Quote(schemaFile) + `}}`,
+		`{"jsonrpc": "2.0", "method": "generate", "params": {"path": ` + strconv.Quote(schemaFile) + `}}`, // notification
+		`{"jsonrpc": "2.0", "id": 2, "method": "inspect", "params": {"path": ` + strconv.Quote(schemaFile) + `}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "validate", "params": {}}`,
+		`not a JSON`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "shutdown"}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "validate", "params": {"path": ` + strconv.Quote(schemaFile) + `}}`,
+	}, "\n")
+
+	var output bytes.Buffer
+	assert.NoErr(t, server.Serve(strings.NewReader(input), &output))
+
+	var lines = strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Eq(t, 5, len(lines)) // no response to the notification, nothing after the shutdown
+	assert.Eq(t, `{"jsonrpc":"2.0","id":1,"result":{"path":`+strconv.Quote(schemaFile)+`,"success":true}}`, lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not supported: inspect`))
+	assert.Eq(t, `{"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"params.path not specified"}}`, lines[2])
+	assert.True(t, strings.HasPrefix(lines[3], `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,`))
+	assert.Eq(t, `{"jsonrpc":"2.0","id":4,"result":{"success":true}}`, lines[4])
+
+	// the notification ran the generation
+	_, err := os.Stat(filepath.Join(dir, "schema.obx.hpp"))
+	assert.NoErr(t, err)
+
+	// sockets reachable from other machines are rejected, clients could write files anywhere
+	for _, address := range []string{"tcp:0.0.0.0:7000", "tcp::7000", "tcp:192.168.1.1:7000", "tcp6:[::]:7000", "udp:localhost:7000", "localhost:7000"} {
+		assert.Err(t, server.Listen(address))
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// writeAdminMetadata writes the Admin metadata of the model to Options.AdminMetadataFile, see model.AdminMetadata
+func writeAdminMetadata(options Options, modelInfo *model.ModelInfo) error {
+	data, err := json.MarshalIndent(modelInfo.CreateAdminMetadata(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't serialize the Admin metadata: %s", err)
+	}
+	if err = options.WriteFile(options.AdminMetadataFile, append(data, '\n'), options.ModelInfoFile); err != nil {
+		return fmt.Errorf("can't write the Admin metadata file %s: %s", options.AdminMetadataFile, err)
+	}
+	return nil
+}
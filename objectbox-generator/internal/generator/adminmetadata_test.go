@@ -0,0 +1,103 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestAdminMetadata(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "admin-metadata")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var adminFile = filepath.Join(dir, "objectbox-admin.json")
+	fixture.WriteFile(t, schemaFile, `/// A customer placing orders
+/// objectbox:external-name=customers
+/// objectbox:backlink(name=orders, to=CustomerOrder)
+/// objectbox:relation(name=favoriteItems, to=Item)
+table Customer {
+	id: ulong;
+	/// The full name
+	fullName: string;
+}
+table CustomerOrder {
+	id: ulong;
+	/// objectbox:relation=Customer
+	customerId: ulong;
+	HTTPHeaders: string;
+}
+table Item {
+	id: ulong;
+}
+`)
+
+	// validation doesn't write anything
+	var options = generator.Options{InPath: schemaFile, AdminMetadataFile: adminFile, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+	_, err := generator.Validate(options)
+	assert.NoErr(t, err)
+	_, err = os.Stat(adminFile)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoErr(t, generator.Process(options))
+	data, err := ioutil.ReadFile(adminFile)
+	assert.NoErr(t, err)
+	var metadata model.AdminMetadata
+	assert.NoErr(t, json.Unmarshal(data, &metadata))
+	assert.Eq(t, model.AdminMetadataVersion, metadata.Version)
+	assert.Eq(t, 3, len(metadata.Entities))
+
+	var entities = make(map[string]*model.AdminEntityMetadata)
+	for _, entity := range metadata.Entities {
+		entities[entity.Name] = entity
+	}
+	var customer = entities["Customer"]
+	storedModel, err := model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	storedCustomer, err := storedModel.FindEntityByName("Customer")
+	assert.NoErr(t, err)
+	assert.Eq(t, storedCustomer.Id, customer.Id)
+	assert.Eq(t, "Customer|A customer placing orders|customers", customer.Label+"|"+customer.Description+"|"+customer.ExternalName)
+	assert.Eq(t, "Full name|The full name|String", customer.Properties[1].Label+"|"+customer.Properties[1].Description+"|"+customer.Properties[1].Type)
+	assert.Eq(t, 2, len(customer.Relations))
+	assert.Eq(t, "Favorite items|Item", customer.Relations[0].Label+"|"+customer.Relations[0].Target)
+	assert.True(t, len(customer.Relations[0].Id) > 0)
+	assert.Eq(t, "Orders|CustomerOrder|customerId", customer.Relations[1].Label+"|"+customer.Relations[1].Target+"|"+customer.Relations[1].Backlink)
+	assert.Eq(t, "", string(customer.Relations[1].Id))
+
+	var order = entities["CustomerOrder"]
+	assert.Eq(t, "Customer order", order.Label)
+	assert.Eq(t, "Customer ID|Relation|Customer", order.Properties[1].Label+"|"+order.Properties[1].Type+"|"+order.Properties[1].Target)
+	assert.Eq(t, "HTTP headers", order.Properties[2].Label)
+
+	assert.Eq(t, "ID", model.AdminLabel("id"))
+	assert.Eq(t, "Created at UTC", model.AdminLabel("_createdAt_UTC"))
+}
@@ -22,7 +22,10 @@ package binding
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 )
 
 // Annotation is a tag on a struct-field
@@ -56,6 +59,7 @@ func HasBooleanDetail(annotations map[string]*Annotation, parentAnnotation, name
 // name="name",index - creates two annotations, name and index, the former having a non-empty value
 // relation(name=manyToManyRelName,to=TargetEntity) - creates a single annotation relation with two items as details
 // id - creates a single annotation
+// reserved=5,6 - creates a single annotation with a comma-separated list of numbers as the value
 // NOTE: this started as a very simple parser but it seems like the requirements are ever-increasing... maybe some form
 //
 //	of recursive tokenization would be better in case we decided to rework.
@@ -88,22 +92,26 @@ func ParseAnnotations(str string, annotations *map[string]*Annotation, supported
 				s.value.Details = make(map[string]*Annotation)
 				var supportedDetails map[string]bool
 				if s.name == "relation" {
-					supportedDetails = map[string]bool{"to": true, "name": true, "uid": true, "external-name": true, "external-type": true}
+					supportedDetails = map[string]bool{"to": true, "name": true, "uid": true, "external-name": true, "external-type": true, "cascade": true}
+				} else if s.name == "backlink" {
+					supportedDetails = map[string]bool{"to": true, "name": true, "property": true}
 				} else if s.name == "sync" {
 					supportedDetails = map[string]bool{"sharedglobalids": true}
 				} else if s.name == "id" {
 					supportedDetails = map[string]bool{"assignable": true}
+				} else if s.name == "transient" {
+					supportedDetails = map[string]bool{"allow-drop": true}
 				} else {
-					return fmt.Errorf("invalid annotation format: details only supported for `relation` & `sync` annotations, found `%s`", s.name)
+					return fmt.Errorf("invalid annotation format: details only supported for `relation`, `backlink` & `sync` annotations, found `%s`", s.name)
 				}
 				if err := ParseAnnotations(detailsStr, &s.value.Details, supportedDetails); err != nil {
 					return err
 				}
-				if s.name == "relation" {
+				if s.name == "relation" || s.name == "backlink" {
 					if s.value.Details["name"] == nil {
-						return fmt.Errorf("invalid annotation format: relation name missing in `%s`", str)
+						return fmt.Errorf("invalid annotation format: %s name missing in `%s`", s.name, str)
 					}
-					s.key = fmt.Sprintf("relation-%10d-%s", relationsCount(*annotations), s.value.Details["name"].Value)
+					s.key = fmt.Sprintf("%s-%10d-%s", s.name, prefixedCount(*annotations, s.name+"-"), s.value.Details["name"].Value)
 				}
 				if err := s.finishAnnotation(annotations, supportedAnnotations); err != nil {
 					return err
@@ -115,6 +123,9 @@ func ParseAnnotations(str string, annotations *map[string]*Annotation, supported
 				i = j - 1 // continue processing on the next non-space character
 			}
 
+		} else if !s.valueQuoted && char == ',' && s.value != nil && len(s.value.Value) > 0 && i+1 < len(str) && isDigit(str[i+1]) {
+			// a list of numbers, e.g. reserved=5,6 - annotation names never start with a digit
+			s.value.Value += string(char)
 		} else if !s.valueQuoted && (char == ',' || char == ' ') { // finish an annotation on a separator
 			// A space may also be used before an equal sign, which means this isn't an annotation separator after all.
 			if char == ' ' {
@@ -151,6 +162,10 @@ func ParseAnnotations(str string, annotations *map[string]*Annotation, supported
 	return s.finishAnnotation(annotations, supportedAnnotations)
 }
 
+func isDigit(c uint8) bool {
+	return c >= '0' && c <= '9'
+}
+
 type annotationInProgress struct {
 	name          string
 	key           string
@@ -190,7 +205,7 @@ func (s *annotationInProgress) finishAnnotation(annotations *map[string]*Annotat
 	if (*annotations)[key] != nil {
 		return fmt.Errorf("duplicate annotation %s", key)
 	} else if !supportedAnnotations[s.name] {
-		return fmt.Errorf("unknown annotation '%s'", s.name)
+		return messages.UnknownAnnotation.Errorf(s.name)
 	} else {
 		if strings.HasPrefix(key, "hnsw-") {
 			var indexAnnotation = (*annotations)["index"]
@@ -203,13 +218,27 @@ func (s *annotationInProgress) finishAnnotation(annotations *map[string]*Annotat
 	return nil
 }
 
-// counts all "relation-" prefixed annotations (standalone relations) - used to ensure consistent processing order
-func relationsCount(annotations map[string]*Annotation) uint {
+// counts all annotations with the given prefix, e.g. "relation-" (standalone relations) or "backlink-" - used to ensure
+// consistent processing order
+func prefixedCount(annotations map[string]*Annotation, prefix string) uint {
 	var count uint
 	for key := range annotations {
-		if strings.HasPrefix(key, "relation-") {
+		if strings.HasPrefix(key, prefix) {
 			count++
 		}
 	}
 	return count
 }
+
+// SupportedAnnotationNames returns the sorted names of the annotations enabled in the given map, as passed to
+// ParseAnnotations(), e.g. to offer them in an editor
+func SupportedAnnotationNames(supportedAnnotations map[string]bool) []string {
+	var names []string
+	for name, supported := range supportedAnnotations {
+		if supported {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
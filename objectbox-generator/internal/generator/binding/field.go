@@ -37,6 +37,9 @@ type Field struct {
 	Name          string
 	Optional      string
 	IsSkipped     bool
+	AllowDrop     bool // with IsSkipped: the data of a previously stored property with the same name may be dropped
+	IsRetired     bool // with IsSkipped: the property is explicitly retired, its UID must never be reused
+	IsExternalId  bool // a secondary, unique ID used by other systems (e.g. a UUID), see the external-id annotation
 }
 
 func CreateField(prop *model.Property) *Field {
@@ -51,12 +54,27 @@ func (field *Field) SetName(name string) {
 }
 func (field *Field) PreProcessAnnotations(a map[string]*Annotation) error {
 	field.IsSkipped = false
+	field.AllowDrop = false
+	field.IsRetired = false
+	if a["retired"] != nil {
+		if len(a) != 1 || a["retired"].Value != "" {
+			return errors.New("to retire the property, use only `objectbox:\"retired\"` as an annotation")
+		}
+		field.IsSkipped = true
+		field.IsRetired = true
+		return nil
+	}
 	for _, alternative := range []string{"-", "transient"} {
 		if a[alternative] != nil {
 			if len(a) != 1 || a[alternative].Value != "" {
 				return errors.New("to ignore the property, use only `objectbox:\"" + alternative + "\"` as an annotation")
 			}
 			field.IsSkipped = true
+			if allowDrop, err := HasBooleanDetail(a, alternative, "allow-drop"); err != nil {
+				return err
+			} else {
+				field.AllowDrop = allowDrop
+			}
 			return nil
 		}
 	}
@@ -112,9 +130,47 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 		field.ModelProperty.AddFlag(model.PropertyFlagIdCompanion)
 	}
 
+	if a["not-null"] != nil {
+		if len(a["not-null"].Value) != 0 {
+			return errors.New("not-null annotation value must be empty")
+		}
+		if a["optional"] != nil {
+			return errors.New("not-null and optional annotations cannot be used at the same time")
+		}
+		field.ModelProperty.AddFlag(model.PropertyFlagNotNull)
+	}
+
+	if a["external-id"] != nil {
+		if len(a["external-id"].Value) != 0 {
+			return errors.New("external-id annotation value must be empty")
+		}
+		if a["id"] != nil {
+			return errors.New("external-id and id annotations cannot be used at the same time, an external ID is a secondary ID")
+		}
+		if field.ModelProperty.Type != model.PropertyTypeString {
+			return fmt.Errorf("invalid underlying type '%v' for external-id field; expecting string", model.PropertyTypeNames[field.ModelProperty.Type])
+		}
+		field.IsExternalId = true
+		field.ModelProperty.ExternalId = true
+
+		// an external ID identifies a single object so it must be unique (which also adds an index)
+		if a["unique"] == nil {
+			a["unique"] = &Annotation{}
+		}
+	}
+
 	if a["unique"] != nil {
 		field.ModelProperty.AddFlag(model.PropertyFlagUnique)
 
+		switch strings.ToLower(a["unique"].Value) {
+		case "":
+		case "replace":
+			// putting an object with an existing value replaces the stored object, e.g. to upsert by a unique key
+			field.ModelProperty.AddFlag(model.PropertyFlagUniqueOnConflictReplace)
+		default:
+			return fmt.Errorf("unknown unique conflict resolution %s, expecting 'replace' or no value", a["unique"].Value)
+		}
+
 		// add a default index type, unless specified otherwise
 		if a["index"] == nil {
 			a["index"] = &Annotation{}
@@ -134,7 +190,12 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 	}
 
 	if a["index"] != nil {
-		switch strings.ToLower(a["index"].Value) {
+		var indexType = strings.ToLower(a["index"].Value)
+		if err := checkIndexType(indexType, field.ModelProperty.Type); err != nil {
+			return err
+		}
+
+		switch indexType {
 		case "":
 			// if the user doesn't define index type use the default based on the data-type
 			if field.ModelProperty.Type == model.PropertyTypeString {
@@ -149,12 +210,8 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 		case "hash64":
 			field.ModelProperty.AddFlag(model.PropertyFlagIndexHash64)
 		case "hnsw":
-			if field.ModelProperty.Type == model.PropertyTypeFloatVector {
-				field.ModelProperty.CreateHnswParams()
-				field.ModelProperty.AddFlag(model.PropertyFlagIndexed)
-			} else {
-				return fmt.Errorf("index type 'hnsw' only supported for float vectors")
-			}
+			field.ModelProperty.CreateHnswParams()
+			field.ModelProperty.AddFlag(model.PropertyFlagIndexed)
 		default:
 			return fmt.Errorf("unknown index type %s", a["index"].Value)
 		}
@@ -164,6 +221,17 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 		}
 	}
 
+	if a["storage-type"] != nil {
+		var storageType = strings.ToLower(a["storage-type"].Value)
+		if storageType != model.StorageTypeFloat32 && storageType != model.StorageTypeFloat16 {
+			return fmt.Errorf("unknown storage-type %s, expecting 'float16' or 'float32'", a["storage-type"].Value)
+		} else if field.ModelProperty.Type != model.PropertyTypeFloatVector {
+			return fmt.Errorf("storage-type is only supported for float vectors, found %s", model.PropertyTypeNames[field.ModelProperty.Type])
+		} else if storageType == model.StorageTypeFloat16 {
+			field.ModelProperty.StorageType = storageType
+		}
+	}
+
 	if a["uid"] != nil {
 		if len(a["uid"].Value) == 0 {
 			// in case the user doesn't provide `objectbox:"uid"` value, it's considered in-process of setting up UID
@@ -199,6 +267,15 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 		}
 	}
 
+	if a["cascade"] != nil {
+		if len(a["cascade"].Value) != 0 {
+			return errors.New("cascade annotation value must be empty")
+		} else if field.ModelProperty.RelationTarget == "" {
+			return errors.New("cascade annotation is only supported on relations")
+		}
+		field.ModelProperty.Cascade = true
+	}
+
 	if a["optional"] != nil {
 		field.Optional = a["optional"].Value
 	}
@@ -299,3 +376,29 @@ func (field *Field) ProcessAnnotations(a map[string]*Annotation) error {
 
 	return nil
 }
+
+// checkIndexType validates the index type given by the "index" annotation (empty for the default) for the property type:
+// hash indexes are only supported for strings and float vectors can only be indexed using HNSW.
+func checkIndexType(indexType string, propertyType model.PropertyType) error {
+	var typeName = model.PropertyTypeNames[propertyType]
+	switch indexType {
+	case "hnsw":
+		if propertyType != model.PropertyTypeFloatVector {
+			return fmt.Errorf("index type 'hnsw' only supported for float vectors")
+		}
+		return nil
+	case "hash", "hash64":
+		if propertyType != model.PropertyTypeString {
+			return fmt.Errorf("index type '%s' is only supported for strings, found %s - use index (or index:value) instead", indexType, typeName)
+		}
+		return nil
+	case "", "value":
+		switch propertyType {
+		case model.PropertyTypeFloatVector:
+			return fmt.Errorf("float vectors can only be indexed using index type 'hnsw', e.g. for nearest neighbor search")
+		case model.PropertyTypeByteVector:
+			return fmt.Errorf("index isn't supported for %s properties", typeName)
+		}
+	}
+	return nil // unknown index types are reported by the caller
+}
@@ -37,6 +37,7 @@ type Object struct {
 	Name        string
 	Namespace   string
 	IsSkipped   bool
+	IsRetired   bool // with IsSkipped: the entity is explicitly retired, its UID must never be reused
 }
 
 func CreateObject(entity *model.Entity) *Object {
@@ -59,6 +60,15 @@ func (object *Object) SetName(name string) {
 
 // ProcessAnnotations checks all set annotations for any inconsistencies and sets local/entity properties (uid, name, ...)
 func (object *Object) ProcessAnnotations(a map[string]*Annotation) error {
+	if a["retired"] != nil {
+		if len(a) != 1 || a["retired"].Value != "" {
+			return errors.New("to retire the entity, use only `objectbox:\"retired\"` as an annotation")
+		}
+		object.IsSkipped = true
+		object.IsRetired = true
+		return nil
+	}
+
 	for _, alternative := range []string{"-", "transient"} {
 		if a[alternative] != nil {
 			if len(a) != 1 || a[alternative].Value != "" {
@@ -106,6 +116,10 @@ func (object *Object) ProcessAnnotations(a map[string]*Annotation) error {
 		}
 	}
 
+	if err := object.processReservations(a); err != nil {
+		return err
+	}
+
 	// Always process standalone relations in the same order by gathering the keys and sorting them, instead of relying
 	// on the random order of map keys. We're doing this to avoid unintended order changes in the generated code/model.
 	var relationKeys []string
@@ -121,6 +135,77 @@ func (object *Object) ProcessAnnotations(a map[string]*Annotation) error {
 		}
 	}
 
+	// same for backlinks, keeping the order of the generated accessors
+	var backlinkKeys []string
+	for key := range a {
+		if strings.HasPrefix(key, "backlink-") {
+			backlinkKeys = append(backlinkKeys, key)
+		}
+	}
+	sort.Strings(backlinkKeys)
+	for _, key := range backlinkKeys {
+		if _, err := object.AddBacklink(a[key].Details); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddBacklink declares a backlink given by annotation details, e.g. backlink(name=orders, to=Order, property=customer),
+// where "to" is the source entity of the to-one relation and "property" is optional if there's only one such relation
+func (object *Object) AddBacklink(details map[string]*Annotation) (*model.Backlink, error) {
+	if details["to"] == nil || len(details["to"].Value) == 0 {
+		return nil, fmt.Errorf("to annotation value must not be empty on backlink %s - specify the source entity", details["name"].Value)
+	}
+	var property string
+	if details["property"] != nil {
+		property = details["property"].Value
+	}
+	return object.ModelEntity.AddBacklink(details["name"].Value, details["to"].Value, property)
+}
+
+// processReservations reads the lists of IDs/UIDs the generator must never assign, see model.Reservations
+func (object *Object) processReservations(a map[string]*Annotation) error {
+	var parse = func(name string, bitSize int) ([]uint64, error) {
+		if a[name] == nil {
+			return nil, nil
+		} else if len(a[name].Value) == 0 {
+			return nil, fmt.Errorf("%s annotation value must not be empty, expecting a comma-separated list, e.g. %s=5,6", name, name)
+		}
+		var values []uint64
+		for _, str := range strings.Split(a[name].Value, ",") {
+			value, err := strconv.ParseUint(strings.TrimSpace(str), 10, bitSize)
+			if err != nil || value == 0 {
+				return nil, fmt.Errorf("invalid %s annotation value %q - expecting a comma-separated list of positive numbers", name, str)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	var reserved = &object.ModelEntity.Reserved
+	if values, err := parse("reserved", 32); err != nil {
+		return err
+	} else {
+		for _, value := range values {
+			reserved.PropertyIds = append(reserved.PropertyIds, model.Id(value))
+		}
+	}
+	if values, err := parse("reserved-entity-ids", 32); err != nil {
+		return err
+	} else {
+		for _, value := range values {
+			reserved.EntityIds = append(reserved.EntityIds, model.Id(value))
+		}
+	}
+	if values, err := parse("reserved-uids", 64); err != nil {
+		return err
+	} else {
+		for _, value := range values {
+			reserved.Uids = append(reserved.Uids, model.Uid(value))
+		}
+	}
 	return nil
 }
 
@@ -148,6 +233,13 @@ func (object *Object) AddRelation(details map[string]*Annotation) (*model.Standa
 		relation.ExternalType = externalType
 	}
 
+	if details["cascade"] != nil {
+		if len(details["cascade"].Value) != 0 {
+			return nil, fmt.Errorf("cascade annotation value must be empty on relation %s", relation.Name)
+		}
+		relation.Cascade = true
+	}
+
 	// NOTE: we don't need an actual entity pointer, it's resolved during stored model merging.
 	relation.Target = &model.Entity{Name: details["to"].Value}
 
@@ -0,0 +1,245 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// BuildInfo is stamped into the header comment of each generated file with Options.BuildInfo, e.g.:
+//
+//	// ObjectBox Generator build info: version=5.0.0 input="schema.fbs" input-hash=3f2a... options-hash=9c1e...
+//
+// It only depends on the generator, the input file and the options, i.e. it's the same when generating again
+// (no timestamps or host names), and nothing is sent anywhere. See VerifyBuildInfo().
+type BuildInfo struct {
+	Version     string `json:"version"`     // the generator version, see Version
+	Input       string `json:"input"`       // the source (or model JSON) file, relative to the generated file's directory
+	InputHash   string `json:"inputHash"`   // a hash of the input file content
+	OptionsHash string `json:"optionsHash"` // a hash of the settings affecting the generated code, see Options.optionsHash()
+}
+
+func (info BuildInfo) String() string {
+	return fmt.Sprintf("%s version=%s input=%s input-hash=%s options-hash=%s", buildInfoMarker, info.Version,
+		strconv.Quote(info.Input), info.InputHash, info.OptionsHash)
+}
+
+const buildInfoMarker = "ObjectBox Generator build info:"
+
+var buildInfoRegexp = regexp.MustCompile(regexp.QuoteMeta(buildInfoMarker) +
+	` version=(\S+) input=("(?:[^"\\]|\\.)*") input-hash=([0-9a-f]+) options-hash=([0-9a-f]+)`)
+
+// templateVersionLineRegexp finds the header line with the templates version (see templateVersionRegexp) and its
+// comment prefix, e.g. "// " or none in an HTML comment; the build info is inserted before it with the same prefix
+var templateVersionLineRegexp = regexp.MustCompile(`(?m)^(.*)ObjectBox Generator templates version: `)
+
+// parseBuildInfo returns the build info stamped into the given generated source, nil if there's none
+func parseBuildInfo(source []byte) *BuildInfo {
+	var match = buildInfoRegexp.FindSubmatch(source)
+	if match == nil {
+		return nil
+	}
+	input, err := strconv.Unquote(string(match[2]))
+	if err != nil {
+		return nil
+	}
+	return &BuildInfo{Version: string(match[1]), Input: input, InputHash: string(match[3]), OptionsHash: string(match[4])}
+}
+
+// buildInfo returns the build info of the given generated file
+func (options Options) buildInfo(file, input string) (*BuildInfo, error) {
+	inputHash, err := fileHash(input)
+	if err != nil {
+		return nil, err
+	}
+	optionsHash, err := options.optionsHash()
+	if err != nil {
+		return nil, err
+	}
+
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	absInput, err := filepath.Abs(input)
+	if err != nil {
+		return nil, err
+	}
+	relInput, err := filepath.Rel(filepath.Dir(absFile), absInput)
+	if err != nil {
+		return nil, err
+	}
+	return &BuildInfo{Version: Version, Input: filepath.ToSlash(relInput), InputHash: inputHash, OptionsHash: optionsHash}, nil
+}
+
+// stampBuildInfo inserts the build info into the header of the generated file, before the templates version line.
+// Files without a header (e.g. a template override replacing it) are returned unchanged.
+func (options Options) stampBuildInfo(file string, data []byte, input string) ([]byte, error) {
+	var loc = templateVersionLineRegexp.FindSubmatchIndex(data)
+	if loc == nil {
+		return data, nil
+	}
+	info, err := options.buildInfo(file, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix = data[loc[2]:loc[3]]
+	var result = make([]byte, 0, len(data)+len(prefix)+200)
+	result = append(result, data[:loc[0]]...)
+	result = append(result, prefix...)
+	result = append(result, info.String()...)
+	result = append(result, '\n')
+	return append(result, data[loc[0]:]...), nil
+}
+
+// fileHash returns a short hash of the file content, in the same format as TemplateVersion()
+func fileHash(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	var hash = sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:16], nil
+}
+
+// optionsHash identifies the settings affecting the generated code: the code generators (i.e. their exported fields,
+// such as the language version) and the options adding outputs or changing the names
+func (options Options) optionsHash() (string, error) {
+	type target struct {
+		Type     string
+		Settings CodeGenerator
+	}
+	var settings = struct {
+		Targets            []target
+		OutPattern         string
+		TenantPrefix       string
+		GenerateBenchmarks bool
+		GenerateFixtures   bool
+		GenerateExport     bool
+		TemplateOverrides  bool
+		Checksums          bool
+	}{
+		OutPattern:         options.OutPattern,
+		TenantPrefix:       options.TenantPrefix,
+		GenerateBenchmarks: options.GenerateBenchmarks,
+		GenerateFixtures:   options.GenerateFixtures,
+		GenerateExport:     options.GenerateExport,
+		TemplateOverrides:  len(options.TemplateOverridesDir) > 0,
+		Checksums:          options.Checksums,
+	}
+	for _, codeGenerator := range options.Targets() {
+		settings.Targets = append(settings.Targets, target{fmt.Sprintf("%T", codeGenerator), codeGenerator})
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	var hash = sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:16], nil
+}
+
+// BuildInfoMismatch describes a generated file whose build info doesn't match the current generator, its input file or
+// the options, see VerifyBuildInfo()
+type BuildInfoMismatch struct {
+	Path      string     `json:"path"`
+	BuildInfo *BuildInfo `json:"buildInfo,omitempty"` // nil if the file isn't stamped
+	Reason    string     `json:"reason"`
+}
+
+func (file BuildInfoMismatch) String() string {
+	return file.Path + ": " + file.Reason
+}
+
+// VerifyBuildInfo scans the generated files (in the output path, or the source path if no output path is configured)
+// and lists those whose build info (see Options.BuildInfo) doesn't match: files without a build info, generated by
+// a different generator version, from an input file which has changed since, or using different options.
+// Returns the number of generated files checked along with the mismatches.
+func VerifyBuildInfo(options Options) (int, []BuildInfoMismatch, error) {
+	if err := options.normalizePaths(); err != nil {
+		return 0, nil, err
+	}
+
+	var path = options.OutPath
+	if len(path) == 0 {
+		path = options.InPath
+	}
+	if !PathIsDirOrPattern(path) {
+		path = filepath.Dir(path)
+	}
+
+	optionsHash, err := options.optionsHash()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var checked int
+	var mismatches []BuildInfoMismatch
+	err = pathForEach(path, func(filePath string) error {
+		if !options.isGeneratedFile(filePath) {
+			return nil
+		}
+		source, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("can't read generated file %s: %s", filePath, err)
+		}
+		checked++
+
+		var file = BuildInfoMismatch{Path: options.FormatPath(filePath), BuildInfo: parseBuildInfo(source)}
+		if file.BuildInfo == nil {
+			file.Reason = "no build info, generate with -build-info"
+		} else if file.BuildInfo.Version != Version {
+			file.Reason = fmt.Sprintf("generated by generator version %s, the current version is %s", file.BuildInfo.Version, Version)
+		} else if inputHash, err := fileHash(filepath.Join(filepath.Dir(filePath), filepath.FromSlash(file.BuildInfo.Input))); err != nil {
+			file.Reason = fmt.Sprintf("can't read the input file %s: %s", file.BuildInfo.Input, err)
+		} else if inputHash != file.BuildInfo.InputHash {
+			file.Reason = fmt.Sprintf("the input file %s has changed since generating", file.BuildInfo.Input)
+		} else if optionsHash != file.BuildInfo.OptionsHash {
+			file.Reason = "generated using different options"
+		}
+		if len(file.Reason) > 0 {
+			mismatches = append(mismatches, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return checked, mismatches, nil
+}
+
+// isGeneratedFile returns true if any of the code generators recognizes the file as generated by it
+func (options Options) isGeneratedFile(file string) bool {
+	for _, codeGenerator := range options.Targets() {
+		if codeGenerator.IsGeneratedFile(file) {
+			return true
+		}
+	}
+	return false
+}
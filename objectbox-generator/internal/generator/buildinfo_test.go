@@ -0,0 +1,101 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestBuildInfo(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "build-info")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		OutPath:       dir,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// files generated without -build-info aren't stamped
+	assert.NoErr(t, generator.Process(options))
+	checked, mismatches, err := generator.VerifyBuildInfo(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, checked)
+	assert.Eq(t, 3, len(mismatches))
+	assert.True(t, mismatches[0].BuildInfo == nil)
+	assert.Eq(t, "no build info, generate with -build-info", mismatches[0].Reason)
+
+	options.BuildInfo = true
+	assert.NoErr(t, generator.Process(options))
+	checked, mismatches, err = generator.VerifyBuildInfo(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, checked)
+	assert.Eq(t, 0, len(mismatches))
+
+	// the stamp is stable, i.e. generating again doesn't change the files
+	var hppFile = filepath.Join(dir, "schema.obx.hpp")
+	source, err := ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	assert.True(t, bytes.Contains(source, []byte("\n// ObjectBox Generator build info: version="+generator.Version+" input=\"schema.fbs\" input-hash=")))
+	assert.NoErr(t, generator.Process(options))
+	regenerated, err := ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(source), string(regenerated))
+
+	// the model file is the input of objectbox-model.h
+	modelHeader, err := ioutil.ReadFile(filepath.Join(dir, "objectbox-model.h"))
+	assert.NoErr(t, err)
+	assert.True(t, bytes.Contains(modelHeader, []byte(" input=\"objectbox-model.json\" ")))
+
+	// different options
+	options.GenerateFixtures = true
+	_, mismatches, err = generator.VerifyBuildInfo(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(mismatches))
+	assert.Eq(t, "generated using different options", mismatches[0].Reason)
+	options.GenerateFixtures = false
+
+	// a changed source
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	_, mismatches, err = generator.VerifyBuildInfo(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(mismatches)) // schema.obx.hpp and schema.obx.cpp; the model file isn't changed yet
+	assert.Eq(t, filepath.Join(dir, "schema.obx.cpp"), mismatches[0].Path)
+	assert.Eq(t, "schema.fbs", mismatches[0].BuildInfo.Input)
+	assert.Eq(t, hppFile+": the input file schema.fbs has changed since generating", mismatches[1].String())
+
+	// regenerating brings the files up to date
+	assert.NoErr(t, generator.Process(options))
+	_, mismatches, err = generator.VerifyBuildInfo(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(mismatches))
+}
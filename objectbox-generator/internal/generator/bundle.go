@@ -0,0 +1,91 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// bundleTime is the modification time of all files in a bundle; the earliest time representable in a zip archive
+var bundleTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ProcessBundle runs Process() without writing to the source tree and stores all the generated files, including the
+// updated model JSON, in the zip archive Options.BundleFile, e.g. for other build steps or artifact stores.
+// The archive is reproducible: files are sorted by name and have a fixed timestamp and permissions. Note that new
+// model elements get random UIDs unless Options.DeterministicUids is set.
+// An existing model JSON file (options.ModelInfoFile or the default one next to the sources) is only read.
+func ProcessBundle(options Options) error {
+	if err := options.normalizePaths(); err != nil {
+		return err
+	}
+
+	var bundleFile = options.BundleFile
+	if len(bundleFile) == 0 {
+		return fmt.Errorf("bundle file not specified")
+	}
+
+	tempDir, err := ioutil.TempDir("", "objectbox-generator-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if len(options.ModelInfoFile) == 0 {
+		options.ModelInfoFile = ModelInfoFile(filepath.Dir(options.InPath))
+	}
+
+	files, err := processToDir(options, filepath.Join(tempDir, "out"))
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err = writeZip(&buffer, files); err != nil {
+		return fmt.Errorf("can't create the bundle: %s", err)
+	}
+
+	if err = ioutil.WriteFile(bundleFile, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("can't write the bundle: %s", err)
+	}
+	return nil
+}
+
+func writeZip(out *bytes.Buffer, files []StreamedFile) error {
+	var writer = zip.NewWriter(out)
+	for _, file := range files {
+		var header = &zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: bundleTime}
+		header.SetMode(0644)
+		fileWriter, err := writer.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err = fileWriter.Write([]byte(file.Content)); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
@@ -0,0 +1,87 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestBundle(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "bundle")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		BundleFile:    filepath.Join(dir, "bundle1.zip"),
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+
+		DeterministicUids: true, // for the UIDs of new entities
+	}
+
+	// UIDs of existing entities are kept by the stored model JSON
+	fixture.Generate(t, schemaFile, options.CodeGenerator)
+	modelJSON, err := ioutil.ReadFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.NoErr(t, os.Remove(filepath.Join(dir, "schema.obx.hpp")))
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\ntable Note {\n id: ulong;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+
+	// nothing is written to the source tree
+	_, err = os.Stat(filepath.Join(dir, "schema.obx.hpp"))
+	assert.True(t, os.IsNotExist(err))
+	data, err := ioutil.ReadFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, string(modelJSON), string(data))
+
+	reader, err := zip.OpenReader(options.BundleFile)
+	assert.NoErr(t, err)
+	defer reader.Close()
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+		assert.Eq(t, int64(315532800), file.Modified.Unix()) // 1980-01-01
+	}
+	assert.Eq(t, "objectbox-model.h objectbox-model.json schema.obx.cpp schema.obx.hpp", strings.Join(names, " "))
+
+	// the same sources produce the same archive
+	bundle1, err := ioutil.ReadFile(options.BundleFile)
+	assert.NoErr(t, err)
+	options.BundleFile = filepath.Join(dir, "bundle2.zip")
+	assert.NoErr(t, generator.Process(options))
+	bundle2, err := ioutil.ReadFile(options.BundleFile)
+	assert.NoErr(t, err)
+	assert.True(t, bytes.Equal(bundle1, bundle2))
+}
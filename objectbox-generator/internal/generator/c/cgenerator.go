@@ -21,29 +21,85 @@
 package cgenerator
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/c/templates"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/yamlschema"
 )
 
+// templatesVersion identifies all the templates used by the C and C++ generator
+var templatesVersion = generator.TemplateVersion(templates.CBindingTemplate, templates.CppBindingTemplateHeader,
+	templates.CppBindingTemplate, templates.ModelTemplate, templates.CppBenchmarkTemplate, templates.CppFixturesTemplate,
+	templates.CppExportTemplate, templates.CMakeTemplate)
+
+// cTemplates holds the templates actually used for generating, i.e. including user overrides
+type cTemplates struct {
+	binding, bindingHeader, bindingCpp, model, benchmark, fixtures, export, cmake *template.Template
+	version                                                                       string
+}
+
+// loadTemplates returns the embedded templates with overrides from the given directory (if any) applied
+func loadTemplates(overridesDir string) (*cTemplates, error) {
+	tpls, version, err := generator.LoadTemplates(overridesDir, templates.CBindingTemplate,
+		templates.CppBindingTemplateHeader, templates.CppBindingTemplate, templates.ModelTemplate, templates.CppBenchmarkTemplate, templates.CppFixturesTemplate,
+		templates.CppExportTemplate, templates.CMakeTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &cTemplates{tpls[0], tpls[1], tpls[2], tpls[3], tpls[4], tpls[5], tpls[6], tpls[7], version}, nil
+}
+
 type CGenerator struct {
 	PlainC            bool
 	LangVersion       int    // -1: unset, cpp: 11, 14, 17
-	Optional          string // std::optional, std::unique_ptr, std::shared_ptr
+	Optional          string // C++: std::optional, std::unique_ptr, std::shared_ptr; C: ptr, flag (value with a has_<field> member)
 	EmptyStringAsNull bool
 	NaNAsNull         bool
+	StrictSchema      bool     // reject FlatBuffers schema features ObjectBox doesn't honor, e.g. required fields
+	ExtensionHooks    bool     // C++: include optional user-provided <Entity>.custom.hpp files inside the generated structs
+	Accessors         bool     // C++: generate private members with get/set accessors, unless overridden per entity
+	JsonHelpers       bool     // C++: generate nlohmann::json to_json() and from_json() functions for the entities
+	VerifyFlatBuffers bool     // verify FlatBuffers before reading them (bounds checks, UTF-8 strings), e.g. for untrusted input
+	ReuseBuffers      bool     // C++: reading into an existing object reuses its strings and vectors instead of allocating new ones
+	Pmr               bool     // C++17: use std::pmr::string and std::pmr::vector members, unless overridden per entity
+	ThreadSafety      bool     // C++: annotate the query helpers for clang's thread safety analysis, with RAII transaction helpers
+	IncludeDirs       []string // directories to look up files included by the schema in, see flatbuffersc.ParseSchemaFileWithIncludes()
+	CMakeFile         bool     // write objectbox-generated.cmake listing the generated files for CMake projects, see cmakeFile()
+
+	entityNamespaces map[string]string     // lower-case entity name => namespace, see ResolveSources()
+	parseCache       *generator.ParseCache // see SetParseCache()
 }
 
 // BindingFiles returns the names of the generated C or C++ language binding files for the given entity file.
-func (gen *CGenerator) BindingFiles(forFile string, options generator.Options) []string {
+// With options.GenerateBenchmarks, C++ additionally gets a (google-benchmark) benchmark source file, with
+// options.GenerateFixtures a header with test fixtures and with options.GenerateExport a header with export functions.
+func (gen *CGenerator) BindingFiles(forFile string, options generator.Options) ([]string, error) {
+	if len(options.OutPattern) > 0 {
+		// per-entity files: we need to read the schema to find out which entities there are
+		m, err := gen.ParseSource(forFile)
+		if err != nil {
+			return nil, err
+		}
+		var files []string
+		for _, entity := range m.Entities {
+			entityFiles, err := gen.entityBindingFiles(forFile, options.TenantPrefix+entity.Name, options)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, entityFiles...)
+		}
+		return files, nil
+	}
 
 	if len(options.OutPath) > 0 {
 		forFile = filepath.Join(options.OutPath, filepath.Base(forFile))
@@ -52,7 +108,7 @@ func (gen *CGenerator) BindingFiles(forFile string, options generator.Options) [
 	var base = forFile[0 : len(forFile)-len(extension)]
 
 	if gen.PlainC {
-		return []string{base + ".obx.h"}
+		return []string{base + ".obx.h"}, nil
 	}
 	var headerBase = base
 	if len(options.OutHeadersPath) > 0 {
@@ -60,7 +116,60 @@ func (gen *CGenerator) BindingFiles(forFile string, options generator.Options) [
 		headerBase = headerBase[0 : len(headerBase)-len(extension)]
 	}
 
-	return []string{headerBase + ".obx.hpp", base + ".obx.cpp"}
+	var files = []string{headerBase + ".obx.hpp", base + ".obx.cpp"}
+	if options.GenerateBenchmarks {
+		files = append(files, base+".obx.bench.cpp")
+	}
+	if options.GenerateFixtures {
+		files = append(files, headerBase+".obx.fixtures.hpp")
+	}
+	if options.GenerateExport {
+		files = append(files, headerBase+".obx.export.hpp")
+	}
+	return files, nil
+}
+
+// entityBindingFiles returns the names of the binding files for a single entity, see Options.OutPattern
+func (gen *CGenerator) entityBindingFiles(forFile, entityName string, options generator.Options) ([]string, error) {
+	var dir = filepath.Dir(forFile)
+	if len(options.OutPath) > 0 {
+		dir = options.OutPath
+	}
+
+	var extensions = []string{"hpp", "cpp"}
+	if gen.PlainC {
+		extensions = []string{"h"}
+	} else {
+		if options.GenerateBenchmarks {
+			extensions = append(extensions, "bench.cpp")
+		}
+		if options.GenerateFixtures {
+			extensions = append(extensions, "fixtures.hpp")
+		}
+		if options.GenerateExport {
+			extensions = append(extensions, "export.hpp")
+		}
+	}
+
+	var files []string
+	for _, ext := range extensions {
+		name, err := options.OutPatternFile(forFile, entityName, ext)
+		if err != nil {
+			return nil, err
+		}
+
+		// the names must be recognized as generated, otherwise "clean" wouldn't remove them
+		if !strings.HasSuffix(name, ".obx."+ext) {
+			return nil, fmt.Errorf("invalid out-pattern %q: file names must end with \".obx.{{.Ext}}\" to be recognized as generated", options.OutPattern)
+		}
+
+		if strings.HasSuffix(ext, "hpp") && len(options.OutHeadersPath) > 0 {
+			files = append(files, filepath.Join(options.OutHeadersPath, name))
+		} else {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	return files, nil
 }
 
 // ModelFile returns the generated model C header file for the given JSON info file path
@@ -78,22 +187,31 @@ func (gen *CGenerator) ModelFile(forFile string, options generator.Options) stri
 func (CGenerator) IsGeneratedFile(file string) bool {
 	var name = filepath.Base(file)
 	return name == "objectbox-model.h" ||
+		name == cmakeFileName ||
 		strings.HasSuffix(name, ".obx.h") ||
 		strings.HasSuffix(name, ".obx.hpp") ||
-		strings.HasSuffix(name, ".obx.cpp")
+		strings.HasSuffix(name, ".obx.cpp") ||
+		strings.HasSuffix(name, ".obx.bench.cpp") ||
+		strings.HasSuffix(name, ".obx.fixtures.hpp") ||
+		strings.HasSuffix(name, ".obx.export.hpp")
 }
 
 func (CGenerator) IsSourceFile(file string) bool {
-	return strings.HasSuffix(file, ".fbs")
+	return strings.HasSuffix(file, ".fbs") || yamlschema.IsSchemaFile(file)
 }
 
 func (gen *CGenerator) ParseSource(sourceFile string) (*model.ModelInfo, error) {
-	schemaReflection, err := flatbuffersc.ParseSchemaFile(sourceFile)
+	// the parsed schema only depends on the include dirs so it can be shared with other FlatBuffers based generators
+	var key = strings.Join(append([]string{"fbs"}, gen.IncludeDirs...), "\n")
+	parsed, err := gen.parseCache.Load(sourceFile, key, func() (interface{}, error) {
+		return flatbuffersc.ParseSchemaFileWithIncludes(sourceFile, gen.IncludeDirs)
+	})
 	if err != nil {
 		return nil, err // already includes file name so no more context should be necessary
 	}
+	var schemaReflection = parsed.(*reflection.Schema)
 
-	reader := fbSchemaReader{model: &model.ModelInfo{}, optional: gen.Optional}
+	reader := fbSchemaReader{model: &model.ModelInfo{}, optional: gen.Optional, accessors: gen.Accessors, pmr: gen.Pmr, strict: gen.StrictSchema, entityNamespaces: gen.entityNamespaces, sourceFile: sourceFile}
 	if err = reader.read(schemaReflection); err != nil {
 		return nil, fmt.Errorf("error generating model from schema %s: %s", sourceFile, err)
 	}
@@ -101,14 +219,130 @@ func (gen *CGenerator) ParseSource(sourceFile string) (*model.ModelInfo, error)
 	return reader.model, nil
 }
 
+// SetParseCache implements generator.ParseCacheUser
+func (gen *CGenerator) SetParseCache(cache *generator.ParseCache) {
+	gen.parseCache = cache
+}
+
+// RenameEntity implements generator.EntityRenamer - the C and C++ types are named after the entity
+func (gen *CGenerator) RenameEntity(entity *model.Entity, name string) {
+	entity.Name = name
+	entity.Meta.(*fbsObject).Name = name
+}
+
+// ResolveSources implements generator.SourcesResolver - it collects namespaces of all the entities so that relations
+// can target entities declared in a different file.
+func (gen *CGenerator) ResolveSources(entities []*model.Entity) {
+	gen.entityNamespaces = make(map[string]string)
+	for _, entity := range entities {
+		gen.entityNamespaces[strings.ToLower(entity.Name)] = entity.Meta.(*fbsObject).Namespace
+	}
+}
+
 func (gen *CGenerator) WriteBindingFiles(sourceFile string, options generator.Options, mergedModel *model.ModelInfo) error {
-	var err, err2 error
+	if err := gen.checkFloat16Vectors(mergedModel.EntitiesWithMeta(), options); err != nil {
+		return err
+	}
+	if err := gen.checkPmr(mergedModel.EntitiesWithMeta(), options); err != nil {
+		return err
+	}
+
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return err
+	}
+
+	var constants = cppConstantGroups(mergedModel.ConstantGroups)
+	if len(options.OutPattern) == 0 {
+		bindingFiles, err := gen.BindingFiles(sourceFile, options)
+		if err != nil {
+			return err
+		}
+		return gen.writeBindingFiles(sourceFile, bindingFiles, mergedModel.EntitiesWithMeta(), constants, tpls, options)
+	}
+
+	var usedFiles = make(map[string]string) // file => entity name
+	for _, entity := range mergedModel.EntitiesWithMeta() {
+		bindingFiles, err := gen.entityBindingFiles(sourceFile, entity.Name, options)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range bindingFiles {
+			if otherEntity, used := usedFiles[file]; used {
+				return messages.OutPatternConflict.Errorf(options.OutPattern, otherEntity, entity.Name)
+			}
+			usedFiles[file] = entity.Name
+		}
 
-	var bindingFiles = gen.BindingFiles(sourceFile, options)
+		if !options.EntitySelected(entity.Name) {
+			continue
+		}
+
+		if err = gen.writeBindingFiles(sourceFile, bindingFiles, []*model.Entity{entity}, constants, tpls, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFloat16Vectors rejects float vectors stored with half precision (see model.StorageTypeFloat16) where the
+// bindings don't convert them: only the C++ struct members are converted, not the C ones, optionals or exported records
+func (gen *CGenerator) checkFloat16Vectors(entities []*model.Entity, options generator.Options) error {
+	for _, entity := range entities {
+		for _, property := range entity.Properties {
+			if property.StorageType != model.StorageTypeFloat16 {
+				continue
+			}
+			var reason string
+			if gen.PlainC {
+				reason = "by the C generator, use C++"
+			} else if len(property.Meta.(*fbsField).Optional) != 0 {
+				reason = "for optional properties"
+			} else if options.GenerateExport {
+				reason = "with export helpers"
+			} else {
+				continue
+			}
+			return fmt.Errorf("property %s.%s: storage-type float16 is not supported %s", entity.Name, property.Name, reason)
+		}
+	}
+	return nil
+}
+
+// checkPmr rejects std::pmr containers (see CGenerator.Pmr) where the bindings don't support them: the C++11 bindings,
+// and the JSON helpers and exported records, converting the members using the std::string and std::vector types
+func (gen *CGenerator) checkPmr(entities []*model.Entity, options generator.Options) error {
+	if gen.PlainC {
+		return nil // C structs don't have containers
+	}
+	for _, entity := range entities {
+		if !entity.Meta.(*fbsObject).pmr {
+			continue
+		}
+		var reason string
+		if gen.LangVersion == 11 {
+			reason = "for C++11, they require C++17"
+		} else if gen.JsonHelpers {
+			reason = "with JSON helpers"
+		} else if options.GenerateExport {
+			reason = "with export helpers"
+		} else {
+			continue
+		}
+		return fmt.Errorf("entity %s: std::pmr containers are not supported %s", entity.Name, reason)
+	}
+	return nil
+}
+
+// writeBindingFiles generates the given binding files (the first one being the header) for the given entities.
+// The constants are declared in each header, guarded so that multiple headers may be included, see -out-pattern.
+func (gen *CGenerator) writeBindingFiles(sourceFile string, bindingFiles []string, entities []*model.Entity, constants []*cppConstantGroup, tpls *cTemplates, options generator.Options) error {
+	var err, err2 error
 
 	for _, bindingFile := range bindingFiles {
 		var bindingSource []byte
-		if bindingSource, err = gen.generateBindingFile(bindingFile, bindingFiles[0], mergedModel); err != nil {
+		if bindingSource, err = gen.generateBindingFile(bindingFile, bindingFiles[0], entities, constants, tpls); err != nil {
 			return fmt.Errorf("can't generate binding file %s: %s", sourceFile, err)
 		}
 
@@ -119,7 +353,7 @@ func (gen *CGenerator) WriteBindingFiles(sourceFile string, options generator.Op
 			bindingSource = formattedSource
 		}
 
-		if err = generator.WriteFile(bindingFile, bindingSource, sourceFile); err != nil {
+		if err = options.WriteFile(bindingFile, bindingSource, sourceFile); err != nil {
 			return fmt.Errorf("can't write binding file %s: %s", sourceFile, err)
 		} else if err2 != nil {
 			// now when the binding has been written (for debugging purposes), we can return the error
@@ -130,16 +364,15 @@ func (gen *CGenerator) WriteBindingFiles(sourceFile string, options generator.Op
 	return nil
 }
 
-func (gen *CGenerator) generateBindingFile(bindingFile, headerFile string, m *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
-
+func (gen *CGenerator) generateBindingFile(bindingFile, headerFile string, entities []*model.Entity, constants []*cppConstantGroup, tpls *cTemplates) (data []byte, err error) {
 	var replaceSpecialChars = strings.NewReplacer("-", "_", ".", "_")
 	var fileIdentifier = strings.ToLower(filepath.Base(bindingFile))
 	fileIdentifier = replaceSpecialChars.Replace(fileIdentifier)
 
 	var tplArguments = struct {
-		Model             *model.ModelInfo
+		Entities          []*model.Entity
+		Enums             []*cppEnum
+		Constants         []*cppConstantGroup
 		GeneratorVersion  int
 		FileIdentifier    string
 		HeaderFile        string
@@ -147,27 +380,34 @@ func (gen *CGenerator) generateBindingFile(bindingFile, headerFile string, m *mo
 		LangVersion       int
 		EmptyStringAsNull bool
 		NaNAsNull         bool
-	}{m, generator.VersionId, fileIdentifier, filepath.Base(headerFile), gen.Optional, gen.LangVersion, gen.EmptyStringAsNull, gen.NaNAsNull}
+		ExtensionHooks    bool
+		JsonHelpers       bool
+		VerifyFlatBuffers bool
+		ReuseBuffers      bool
+		ThreadSafety      bool
+		TemplateVersion   string
+	}{entities, cppEnums(entities), constants, generator.VersionId, fileIdentifier, filepath.Base(headerFile), gen.Optional, gen.LangVersion, gen.EmptyStringAsNull, gen.NaNAsNull, gen.ExtensionHooks, gen.JsonHelpers, gen.VerifyFlatBuffers, gen.ReuseBuffers, gen.ThreadSafety, tpls.version}
 
 	var tpl *template.Template
 
 	if gen.PlainC {
-		tpl = templates.CBindingTemplate
+		tpl = tpls.binding
 	} else if bindingFile == headerFile {
-		tpl = templates.CppBindingTemplateHeader
+		tpl = tpls.bindingHeader
+	} else if strings.HasSuffix(bindingFile, ".obx.bench.cpp") {
+		tpl = tpls.benchmark
+	} else if strings.HasSuffix(bindingFile, ".obx.fixtures.hpp") {
+		tpl = tpls.fixtures
+	} else if strings.HasSuffix(bindingFile, ".obx.export.hpp") {
+		tpl = tpls.export
 	} else {
-		tpl = templates.CppBindingTemplate
+		tpl = tpls.bindingCpp
 	}
 
-	if err = tpl.Execute(writer, tplArguments); err != nil {
+	if data, err = generator.ExecuteTemplate(tpl, tplArguments); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
-
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
-	}
-
-	return b.Bytes(), nil
+	return data, nil
 }
 
 func (gen *CGenerator) WriteModelBindingFile(options generator.Options, mergedModel *model.ModelInfo) error {
@@ -176,7 +416,12 @@ func (gen *CGenerator) WriteModelBindingFile(options generator.Options, mergedMo
 	var modelFile = gen.ModelFile(options.ModelInfoFile, options)
 	var modelSource []byte
 
-	if modelSource, err = generateModelFile(mergedModel); err != nil {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return err
+	}
+
+	if modelSource, err = generateModelFile(mergedModel, tpls); err != nil {
 		return fmt.Errorf("can't generate model file %s: %s", modelFile, err)
 	}
 
@@ -187,34 +432,95 @@ func (gen *CGenerator) WriteModelBindingFile(options generator.Options, mergedMo
 		modelSource = formattedSource
 	}
 
-	if err = generator.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
+	if err = options.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
 		return fmt.Errorf("can't write model file %s: %s", modelFile, err)
 	} else if err2 != nil {
 		// now when the model has been written (for debugging purposes), we can return the error
 		return err2
 	}
 
+	if gen.CMakeFile {
+		return gen.writeCMakeFile(options, tpls)
+	}
 	return nil
 }
 
-func generateModelFile(m *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
+func generateModelFile(m *model.ModelInfo, tpls *cTemplates) (data []byte, err error) {
+	externalMapping, err := externalMappingLiteral(m)
+	if err != nil {
+		return nil, err
+	}
+
+	cascadeDeletes, err := cascadeDeletesLiteral(m)
+	if err != nil {
+		return nil, err
+	}
 
 	var tplArguments = struct {
 		Model            *model.ModelInfo
+		ExternalMapping  string
+		CascadeDeletes   string
 		GeneratorVersion int
-	}{m, generator.VersionId}
+		TemplateVersion  string
+	}{m, externalMapping, cascadeDeletes, generator.VersionId, tpls.version}
 
-	if err = templates.ModelTemplate.Execute(writer, tplArguments); err != nil {
+	if data, err = generator.ExecuteTemplate(tpls.model, tplArguments); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
+	return data, nil
+}
+
+// TemplateVersion returns an identifier of the C and C++ templates
+func (gen *CGenerator) TemplateVersion() string {
+	return templatesVersion
+}
+
+// CompatibilityOptions implements generator.CompatibilityOptionsProvider; only options changing the stored data are
+// recorded, not those only affecting the generated code (e.g. -optional)
+func (gen *CGenerator) CompatibilityOptions() map[string]string {
+	return map[string]string{
+		"empty-string-as-null": strconv.FormatBool(gen.EmptyStringAsNull),
+		"nan-as-null":          strconv.FormatBool(gen.NaNAsNull),
+	}
+}
 
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
+// externalMappingLiteral returns the model's external mapping JSON as a C string literal (concatenated from one
+// literal per line), or "" if there's none
+func externalMappingLiteral(m *model.ModelInfo) (string, error) {
+	var mapping = m.CreateExternalMapping()
+	if mapping == nil {
+		return "", nil
 	}
 
-	return b.Bytes(), nil
+	json, err := mapping.JSON("  ")
+	if err != nil {
+		return "", fmt.Errorf("can't create the external mapping: %s", err)
+	}
+	return jsonLiteral(json), nil
+}
+
+// cascadeDeletesLiteral returns the model's cascade delete rules JSON as a C string literal, or "" if there are none
+func cascadeDeletesLiteral(m *model.ModelInfo) (string, error) {
+	var cascades = m.CreateCascadeDeletes()
+	if len(cascades) == 0 {
+		return "", nil
+	}
+
+	json, err := model.CascadeDeletesJSON(cascades, "  ")
+	if err != nil {
+		return "", fmt.Errorf("can't create the cascade delete rules: %s", err)
+	}
+	return jsonLiteral(json), nil
+}
+
+// jsonLiteral returns the given JSON as a C string literal, concatenated from one literal per line
+func jsonLiteral(json string) string {
+	var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	var lines = strings.Split(json, "\n")
+	for i, line := range lines {
+		lines[i] = `"` + escaper.Replace(line) + `\n"`
+	}
+	return strings.Join(lines, "\n\t")
 }
 
 func format(source []byte) ([]byte, error) {
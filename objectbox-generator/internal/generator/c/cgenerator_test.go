@@ -0,0 +1,75 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgenerator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+// TestYamlSchema generates the code from a YAML schema file; the conversion itself is tested in the yamlschema package
+func TestYamlSchema(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "yaml")
+	defer remove()
+
+	var schemaFile = fixture.WriteFile(t, filepath.Join(dir, "shop.schema.yaml"), `entities:
+  - name: Customer
+    properties:
+      - name: id
+        type: ulong
+      - name: email
+        type: string
+        external-id: true
+`)
+	var codeGenerator = &cgenerator.CGenerator{LangVersion: 14}
+	assert.True(t, codeGenerator.IsSourceFile(schemaFile))
+	assert.True(t, !codeGenerator.IsSourceFile(filepath.Join(dir, "docker-compose.yaml")))
+	fixture.Generate(t, schemaFile, codeGenerator)
+
+	var binding = fixture.ReadFile(t, filepath.Join(dir, "shop.schema.obx.hpp"))
+	assert.True(t, strings.Contains(binding, "static std::unique_ptr<Customer> findByEmail("))
+}
+
+func TestBindingFilesOutPattern(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "out-pattern")
+	defer remove()
+
+	var schemaFile = fixture.WriteFile(t, filepath.Join(dir, "schema.fbs"), "table Task {\n id: ulong;\n}\ntable Note {\n id: ulong;\n}\n")
+	var options = generator.Options{OutPattern: "{{.Entity}}.obx.{{.Ext}}"}
+	var codeGenerator = &cgenerator.CGenerator{LangVersion: 11}
+	files, err := codeGenerator.BindingFiles(schemaFile, options)
+	assert.NoErr(t, err)
+	assert.EqItems(t, []string{
+		filepath.Join(dir, "Task.obx.hpp"), filepath.Join(dir, "Task.obx.cpp"),
+		filepath.Join(dir, "Note.obx.hpp"), filepath.Join(dir, "Note.obx.cpp"),
+	}, files)
+
+	// the files depend on the entities, so a schema that can't be parsed is an error instead of no files
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong\n")
+	_, err = codeGenerator.BindingFiles(schemaFile, options)
+	assert.Err(t, err)
+}
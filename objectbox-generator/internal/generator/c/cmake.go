@@ -0,0 +1,214 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgenerator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+)
+
+// cmakeFileName is the name of the file listing the generated files for CMake projects, see CGenerator.CMakeFile
+const cmakeFileName = "objectbox-generated.cmake"
+
+// cmakeFile returns the path of the CMake integration file: in the output path, if given, or next to the model JSON
+func (gen *CGenerator) cmakeFile(options generator.Options) string {
+	if len(options.OutPath) > 0 {
+		return filepath.Join(options.OutPath, cmakeFileName)
+	}
+	return filepath.Join(filepath.Dir(options.ModelInfoFile), cmakeFileName)
+}
+
+// writeCMakeFile writes the CMake integration file listing the schema files and the files generated from them (see
+// generator.BuildManifest()) with paths relative to the CMake file, and the arguments to generate them again
+func (gen *CGenerator) writeCMakeFile(options generator.Options, tpls *cTemplates) error {
+	var file = gen.cmakeFile(options)
+	options.CodeGenerator = gen
+	options.PathStyle = generator.PathStyleNative
+	manifest, err := generator.BuildManifest(options)
+	if err != nil {
+		return fmt.Errorf("can't list the generated files for %s: %s", file, err)
+	}
+
+	var dir = filepath.Dir(file)
+	var relative = func(path string) (string, error) {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(absDir, absPath)
+		return filepath.ToSlash(rel), err
+	}
+
+	var tplArguments = struct {
+		Schemas         []string
+		Sources         []string
+		Headers         []string
+		Benchmarks      []string
+		IncludeDirs     []string
+		Args            []string
+		TemplateVersion string
+	}{TemplateVersion: tpls.version}
+
+	var includeDirs = make(map[string]bool)
+	var addFile = func(path string, list *[]string) error {
+		rel, err := relative(path)
+		if err != nil {
+			return err
+		}
+		*list = append(*list, rel)
+		if list == &tplArguments.Headers && !includeDirs[filepath.ToSlash(filepath.Dir(rel))] {
+			includeDirs[filepath.ToSlash(filepath.Dir(rel))] = true
+			tplArguments.IncludeDirs = append(tplArguments.IncludeDirs, filepath.ToSlash(filepath.Dir(rel)))
+		}
+		return nil
+	}
+
+	for _, source := range manifest.Sources {
+		if err = addFile(source.Path, &tplArguments.Schemas); err != nil {
+			return err
+		}
+		for _, output := range source.Outputs {
+			var list = &tplArguments.Headers
+			if strings.HasSuffix(output, ".obx.bench.cpp") {
+				list = &tplArguments.Benchmarks
+			} else if strings.HasSuffix(output, ".cpp") {
+				list = &tplArguments.Sources
+			}
+			if err = addFile(output, list); err != nil {
+				return err
+			}
+		}
+	}
+	if err = addFile(manifest.ModelFile, &tplArguments.Headers); err != nil {
+		return err
+	}
+	sort.Strings(tplArguments.IncludeDirs)
+
+	if tplArguments.Args, err = gen.cmakeGeneratorArgs(options, relative); err != nil {
+		return err
+	}
+
+	source, err := generator.ExecuteTemplate(tpls.cmake, tplArguments)
+	if err != nil {
+		return fmt.Errorf("can't generate %s: template execution failed: %s", file, err)
+	}
+	if err = options.WriteFile(file, source, options.ModelInfoFile); err != nil {
+		return fmt.Errorf("can't write %s: %s", file, err)
+	}
+	return nil
+}
+
+// cmakeGeneratorArgs returns the objectbox-generator command line arguments (quoted for CMake) generating the same
+// files as this run, with paths relative to the CMake file's directory
+func (gen *CGenerator) cmakeGeneratorArgs(options generator.Options, relative func(string) (string, error)) ([]string, error) {
+	var args []string
+	var arg = func(values ...string) {
+		for _, value := range values {
+			args = append(args, cmakeQuote(value))
+		}
+	}
+	var pathArg = func(name, path string) error {
+		if len(path) == 0 {
+			return nil
+		}
+		rel, err := relative(path)
+		if err != nil {
+			return err
+		}
+		arg(name, rel)
+		return nil
+	}
+
+	if gen.PlainC {
+		arg("-c")
+	} else if gen.LangVersion == 11 {
+		arg("-cpp11")
+	} else {
+		arg("-cpp")
+	}
+	if len(gen.Optional) > 0 && !(gen.PlainC && gen.Optional == "ptr") {
+		arg("-optional", gen.Optional)
+	}
+	for _, flag := range []struct {
+		name  string
+		value bool
+	}{
+		{"-empty-string-as-null", gen.EmptyStringAsNull},
+		{"-nan-as-null", gen.NaNAsNull},
+		{"-strict-schema", gen.StrictSchema},
+		{"-extension-hooks", gen.ExtensionHooks},
+		{"-accessors", gen.Accessors},
+		{"-json-helpers", gen.JsonHelpers},
+		{"-verify-flatbuffers", gen.VerifyFlatBuffers},
+		{"-reuse-buffers", gen.ReuseBuffers},
+		{"-pmr", gen.Pmr},
+		{"-thread-safety", gen.ThreadSafety},
+		{"-benchmarks", options.GenerateBenchmarks},
+		{"-fixtures", options.GenerateFixtures},
+		{"-export", options.GenerateExport},
+		{"-cmake-file", gen.CMakeFile},
+	} {
+		if flag.value {
+			arg(flag.name)
+		}
+	}
+	for _, dir := range gen.IncludeDirs {
+		if err := pathArg("-I", dir); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.OutPattern) > 0 {
+		arg("-out-pattern", options.OutPattern)
+	}
+	if len(options.TenantPrefix) > 0 {
+		arg("-tenant-prefix", options.TenantPrefix)
+	}
+	if err := pathArg("-model", options.ModelInfoFile); err != nil {
+		return nil, err
+	}
+	if err := pathArg("-out", options.OutPath); err != nil {
+		return nil, err
+	}
+	if err := pathArg("-out-headers", options.OutHeadersPath); err != nil {
+		return nil, err
+	}
+	in, err := relative(options.InPath)
+	if err != nil {
+		return nil, err
+	}
+	arg(in)
+	return args, nil
+}
+
+var cmakeEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+
+// cmakeQuote returns the value as a quoted CMake argument
+func cmakeQuote(value string) string {
+	return `"` + cmakeEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,75 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgenerator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestCMakeFile(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "cmake-file")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+
+	var gen = &cgenerator.CGenerator{LangVersion: 14, CMakeFile: true, JsonHelpers: true}
+	var options = generator.Options{
+		InPath:             schemaFile,
+		ModelInfoFile:      filepath.Join(dir, "objectbox-model.json"),
+		OutPath:            filepath.Join(dir, "gen"),
+		OutHeadersPath:     filepath.Join(dir, "include"),
+		CodeGenerator:      gen,
+		GenerateBenchmarks: true,
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	var cmakeFile = filepath.Join(dir, "gen", "objectbox-generated.cmake")
+	source, err := ioutil.ReadFile(cmakeFile)
+	assert.NoErr(t, err)
+	var cmake = string(source)
+	assert.True(t, strings.HasPrefix(cmake, "# Code generated by ObjectBox; DO NOT EDIT.\n"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_SCHEMA_FILES\n    \"${OBJECTBOX_GENERATED_DIR}/../schema.fbs\"\n)"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_GENERATED_SOURCES\n    \"${OBJECTBOX_GENERATED_DIR}/schema.obx.cpp\"\n)"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_GENERATED_HEADERS\n"+
+		"    \"${OBJECTBOX_GENERATED_DIR}/../include/schema.obx.hpp\"\n"+
+		"    \"${OBJECTBOX_GENERATED_DIR}/../include/objectbox-model.h\"\n)"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_GENERATED_BENCHMARK_SOURCES\n    \"${OBJECTBOX_GENERATED_DIR}/schema.obx.bench.cpp\"\n)"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_GENERATED_INCLUDE_DIRS\n    \"${OBJECTBOX_GENERATED_DIR}/../include\"\n)"))
+	assert.True(t, strings.Contains(cmake, "set(OBJECTBOX_GENERATOR_ARGS \"-cpp\" \"-json-helpers\" \"-benchmarks\" \"-cmake-file\" "+
+		"\"-model\" \"../objectbox-model.json\" \"-out\" \".\" \"-out-headers\" \"../include\" \"../schema.fbs\")"))
+	assert.True(t, strings.Contains(cmake, "function(add_objectbox_schema target)"))
+
+	// removed together with the other generated files
+	assert.True(t, gen.IsGeneratedFile(cmakeFile))
+	assert.NoErr(t, generator.Clean(gen, filepath.Join(dir, "gen")))
+	_, err = os.Stat(cmakeFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,85 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgenerator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestSchemaIncludes(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "includes")
+	defer remove()
+
+	var commonDir = filepath.Join(dir, "common")
+	var srcDir = filepath.Join(dir, "src")
+	assert.NoErr(t, os.Mkdir(commonDir, 0700))
+	assert.NoErr(t, os.Mkdir(srcDir, 0700))
+	fixture.WriteFile(t, filepath.Join(commonDir, "common.fbs"), `namespace crm;
+table Customer {
+	id: ulong;
+}
+`)
+	fixture.WriteFile(t, filepath.Join(srcDir, "order.fbs"), `include "common.fbs";
+namespace shop;
+table Order {
+	id: ulong;
+	/// objectbox:relation=Customer
+	customerId: ulong;
+}
+`)
+
+	var modelInfoFile = filepath.Join(dir, "objectbox-model.json")
+	var gen = &cgenerator.CGenerator{LangVersion: 14}
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:        filepath.Join(commonDir, "common.fbs"),
+		OutPath:       dir,
+		ModelInfoFile: modelInfoFile,
+		CodeGenerator: gen,
+	}))
+
+	var options = generator.Options{
+		InPath:        filepath.Join(srcDir, "order.fbs"),
+		OutPath:       dir,
+		ModelInfoFile: modelInfoFile,
+		CodeGenerator: gen,
+	}
+	err := generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "unable to locate include file common.fbs, searched in: "+srcDir+", ."))
+
+	gen.IncludeDirs = []string{commonDir}
+	assert.NoErr(t, generator.Process(options))
+
+	// the included entity is only generated along with the file declaring it
+	data, err := ioutil.ReadFile(filepath.Join(dir, "order.obx.hpp"))
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(data), "crm::Customer"))
+	assert.True(t, !strings.Contains(string(data), "struct Customer {"))
+}
@@ -33,6 +33,15 @@ import (
 type fbsObject struct {
 	*binding.Object
 	fbsObject *reflection.Object
+
+	// namespaces of all entities in the current run (lower-case name => namespace), see CGenerator.ResolveSources()
+	entityNamespaces map[string]string
+
+	// C++: generate private members with get/set accessors instead of public members
+	accessors bool
+
+	// C++: use std::pmr containers (polymorphic memory resource) for string and vector members
+	pmr bool
 }
 
 // Merge implements model.EntityMeta interface
@@ -41,6 +50,16 @@ func (mo *fbsObject) Merge(entity *model.Entity) model.EntityMeta {
 	return mo
 }
 
+// Accessors returns true if the members are private, accessed using the generated getters and setters
+func (mo *fbsObject) Accessors() bool {
+	return mo.accessors
+}
+
+// Pmr returns true if string and vector members use std::pmr containers, see CGenerator.Pmr
+func (mo *fbsObject) Pmr() bool {
+	return mo.pmr
+}
+
 // CppName returns C++ symbol/variable name with reserved keywords suffixed by an underscore
 func (mo *fbsObject) CppName() string {
 	return cppName(mo.Name)
@@ -70,24 +89,32 @@ func (mo *fbsObject) CppNamespacePrefix() string {
 
 // CppNamespaceStart returns c++ namespace opening declaration
 func (mo *fbsObject) CppNamespaceStart() string {
-	if len(mo.Namespace) == 0 {
+	return cppNamespaceStart(mo.Namespace)
+}
+
+// CppNamespaceEnd returns c++ namespace closing declaration
+func (mo *fbsObject) CppNamespaceEnd() string {
+	return cppNamespaceEnd(mo.Namespace)
+}
+
+func cppNamespaceStart(ns string) string {
+	if len(ns) == 0 {
 		return ""
 	}
 
-	var nss = strings.Split(mo.Namespace, ".")
+	var nss = strings.Split(ns, ".")
 	for i, ns := range nss {
 		nss[i] = "namespace " + ns + " {"
 	}
 	return strings.Join(nss, "\n")
 }
 
-// CppNamespaceEnd returns c++ namespace closing declaration
-func (mo *fbsObject) CppNamespaceEnd() string {
-	if len(mo.Namespace) == 0 {
+func cppNamespaceEnd(ns string) string {
+	if len(ns) == 0 {
 		return ""
 	}
 	var result = ""
-	var nss = strings.Split(mo.Namespace, ".")
+	var nss = strings.Split(ns, ".")
 	for _, ns := range nss {
 		// print in reversed order
 		result = "}  // namespace " + ns + "\n" + result
@@ -95,13 +122,45 @@ func (mo *fbsObject) CppNamespaceEnd() string {
 	return result
 }
 
-// PreDeclareCppRelTargets returns C++ struct pre-declarations for related entities.
+// relTargetNamespace returns the namespace of the given relation target entity. If the target is declared in a
+// different file, it's looked up among the entities of all the files processed in the current run.
+func (mo *fbsObject) relTargetNamespace(target string) string {
+	if targetEntity, err := mo.ModelEntity.Model.FindEntityByName(target); err == nil && targetEntity.Meta != nil {
+		return targetEntity.Meta.(*fbsObject).Namespace
+	}
+	return mo.entityNamespaces[strings.ToLower(target)]
+}
+
+// cppBacklink holds the C++ names of a backlink accessor, see fbsObject.CppBacklinks()
+type cppBacklink struct {
+	*model.Backlink
+	CppName   string // accessor (function) name
+	CppSource string // source entity class name, including the namespace
+}
+
+// CppBacklinks returns the backlinks declared on the entity, with the names used by the generated C++ accessors
+func (mo *fbsObject) CppBacklinks() []cppBacklink {
+	var result []cppBacklink
+	for _, backlink := range mo.ModelEntity.Backlinks {
+		result = append(result, cppBacklink{
+			Backlink:  backlink,
+			CppName:   cppName(backlink.Name),
+			CppSource: cppNamespacePrefix(mo.relTargetNamespace(backlink.Source.Name)) + cppName(backlink.Source.Name),
+		})
+	}
+	return result
+}
+
+// PreDeclareCppRelTargets returns C++ struct pre-declarations for related entities (including backlink sources).
 func (mo *fbsObject) PreDeclareCppRelTargets() (string, error) {
 	// first create a map `(ns.entity) => bool`, then sort it to keep the code from changing, and generate the C++ decl.
 	var m = make(map[string]bool)
 
 	for _, rel := range mo.ModelEntity.Relations {
-		m[rel.Target.Meta.(*fbsObject).Namespace+"."+rel.Target.Name] = true
+		m[mo.relTargetNamespace(rel.Target.Name)+"."+rel.Target.Name] = true
+	}
+	for _, backlink := range mo.ModelEntity.Backlinks {
+		m[mo.relTargetNamespace(backlink.Source.Name)+"."+backlink.Source.Name] = true
 	}
 	for _, prop := range mo.ModelEntity.Properties {
 		if len(prop.RelationTarget) > 0 {
@@ -160,12 +219,32 @@ func (mp *fbsField) CppNameRelationTarget() string {
 	return cppNamespacePrefix(mp.relTargetNamespace()) + cppName(mp.ModelProperty.RelationTarget)
 }
 
+// CppMemberName returns C++ struct member name, i.e. CppName() or, with accessors, the name suffixed by an underscore
+func (mp *fbsField) CppMemberName() string {
+	if mp.ModelProperty.Entity.Meta.(*fbsObject).accessors {
+		return mp.Name + "_"
+	}
+	return mp.CppName()
+}
+
+// CppGetter returns C++ getter name used with accessors, e.g. getName
+func (mp *fbsField) CppGetter() string {
+	return "get" + strings.ToUpper(mp.Name[:1]) + mp.Name[1:]
+}
+
+// CppSetter returns C++ setter name used with accessors, e.g. setName
+func (mp *fbsField) CppSetter() string {
+	return "set" + strings.ToUpper(mp.Name[:1]) + mp.Name[1:]
+}
+
 // CppType returns C++ type name
 func (mp *fbsField) CppType() string {
 	var fbsType = mp.fbsField.Type(nil)
 	var baseType = fbsType.BaseType()
 	var cppType = fbsTypeToCppType[baseType]
-	if baseType == reflection.BaseTypeVector {
+	if baseType == reflection.BaseTypeVector && mp.ModelProperty.ArrayLength > 0 {
+		cppType = fmt.Sprintf("std::array<%s, %d>", fbsTypeToCppType[fbsType.Element()], mp.ModelProperty.ArrayLength)
+	} else if baseType == reflection.BaseTypeVector {
 		cppType = cppType + "<" + fbsTypeToCppType[fbsType.Element()] + ">"
 	} else if (mp.ModelProperty.IsIdProperty() || mp.ModelProperty.Type == model.PropertyTypeRelation) && cppType == "uint64_t" {
 		cppType = "obx_id" // defined in objectbox.h
@@ -173,6 +252,65 @@ func (mp *fbsField) CppType() string {
 	return cppType
 }
 
+// CppValueType returns C++ type name of the struct member, i.e. the enum for enum properties, the std::pmr container
+// for strings and vectors of entities using them (see fbsObject.Pmr()), otherwise same as CppType()
+func (mp *fbsField) CppValueType() string {
+	if mp.ModelProperty.Enum != nil {
+		return cppNamespacePrefix(mp.ModelProperty.Enum.Namespace) + cppName(mp.ModelProperty.Enum.Name)
+	}
+	if mp.ModelProperty.Entity.Meta.(*fbsObject).pmr {
+		switch mp.ModelProperty.Type {
+		case model.PropertyTypeString:
+			return "std::pmr::string"
+		case model.PropertyTypeStringVector:
+			return "std::pmr::vector<std::pmr::string>"
+		case model.PropertyTypeByteVector, model.PropertyTypeFloatVector:
+			if mp.ModelProperty.ArrayLength == 0 { // fixed-length arrays don't allocate
+				return "std::pmr::vector<" + fbsTypeToCppType[mp.fbsField.Type(nil).Element()] + ">"
+			}
+		}
+	}
+	return mp.CppType()
+}
+
+// CppQueryValueType returns C++ type of the value argument in generated query helpers
+func (mp *fbsField) CppQueryValueType() string {
+	if mp.ModelProperty.Type == model.PropertyTypeString {
+		return "const std::string&"
+	}
+	return mp.CppValueType()
+}
+
+// CppQueryValue returns the given query helper argument converted to the type expected by the obx::Property conditions
+func (mp *fbsField) CppQueryValue(name string) string {
+	if mp.ModelProperty.Enum != nil {
+		return "static_cast<" + mp.CppType() + ">(" + name + ")"
+	}
+	return name
+}
+
+// CppQueryFinder returns the name of the generated function looking up objects by an indexed property value,
+// e.g. "findByName", or an empty string if the property can't be looked up by an exact value using an index.
+func (mp *fbsField) CppQueryFinder() string {
+	var property = mp.ModelProperty
+	var indexFlags = model.PropertyFlagIndexed | model.PropertyFlagIndexHash | model.PropertyFlagIndexHash64 | model.PropertyFlagUnique
+	if property.IsIdProperty() || property.Flags&indexFlags == 0 {
+		return ""
+	}
+	switch property.Type {
+	case model.PropertyTypeFloat, model.PropertyTypeDouble, model.PropertyTypeRelation,
+		model.PropertyTypeByteVector, model.PropertyTypeFloatVector, model.PropertyTypeStringVector:
+		return ""
+	}
+	var name = mp.CppName()
+	return "findBy" + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// CppQueryUnique returns true if the finder returned by CppQueryFinder() matches at most one object
+func (mp *fbsField) CppQueryUnique() bool {
+	return mp.ModelProperty.Flags&model.PropertyFlagUnique != 0
+}
+
 // CppFbType returns C++ type name used in flatbuffers templated functions
 func (mp *fbsField) CppFbType() string {
 	var cppType = mp.CppType()
@@ -184,7 +322,7 @@ func (mp *fbsField) CppFbType() string {
 
 // CppTypeWithOptional returns full C++ type name, including wrapper if the value is not defined
 func (mp *fbsField) CppTypeWithOptional() (string, error) {
-	var cppType = mp.CppType()
+	var cppType = mp.CppValueType()
 	if len(mp.Optional) != 0 {
 		if mp.ModelProperty.IsIdProperty() {
 			return "", fmt.Errorf("ID property must not be optional: %s.%s", mp.ModelProperty.Entity.Name, mp.ModelProperty.Name)
@@ -194,6 +332,189 @@ func (mp *fbsField) CppTypeWithOptional() (string, error) {
 	return cppType, nil
 }
 
+// CTypeWithOptional returns the C type of a scalar struct member, i.e. a pointer for properties optional as "ptr"
+func (mp *fbsField) CTypeWithOptional() (string, error) {
+	if len(mp.Optional) != 0 && mp.ModelProperty.IsIdProperty() {
+		return "", fmt.Errorf("ID property must not be optional: %s.%s", mp.ModelProperty.Entity.Name, mp.ModelProperty.Name)
+	}
+	if mp.Optional == "ptr" {
+		return mp.CppType() + "*", nil
+	}
+	return mp.CppType(), nil
+}
+
+// CppObjectValue returns the expression reading the property of a struct instance named "object", e.g. in to_json()
+func (mp *fbsField) CppObjectValue() string {
+	if mp.ModelProperty.Entity.Meta.(*fbsObject).accessors {
+		return "object." + mp.CppGetter() + "()"
+	}
+	return "object." + mp.CppName()
+}
+
+// CppObjectAssign returns the statement assigning the given value to the property of a struct instance named "object"
+func (mp *fbsField) CppObjectAssign(value string) string {
+	if mp.ModelProperty.Entity.Meta.(*fbsObject).accessors {
+		return "object." + mp.CppSetter() + "(" + value + ");"
+	}
+	return "object." + mp.CppName() + " = " + value + ";"
+}
+
+// CppOptionalOf returns the expression wrapping the given value into the optional type of the property, see CppTypeWithOptional()
+func (mp *fbsField) CppOptionalOf(value string) string {
+	switch mp.Optional {
+	case "std::unique_ptr":
+		return "std::unique_ptr<" + mp.CppValueType() + ">(new " + mp.CppValueType() + "(" + value + "))"
+	case "std::shared_ptr":
+		return "std::make_shared<" + mp.CppValueType() + ">(" + value + ")"
+	}
+	return value
+}
+
+// fixtureVectorLength is the number of elements of vectors filled by the generated fixtures, unless the length is
+// given by the property, i.e. a fixed-length array or the dimensions of an HNSW index
+const fixtureVectorLength = 4
+
+// CppFixtureDefault returns the value the generated new<Entity>Fixture() assigns to the property, or an empty string
+// to keep the zero value. Strings (unless optional) are set to the property name, so they're easy to recognize.
+func (mp *fbsField) CppFixtureDefault() string {
+	if mp.ModelProperty.Type == model.PropertyTypeString && len(mp.Optional) == 0 && !mp.ModelProperty.IsIdProperty() {
+		return fmt.Sprintf("%q", mp.Name)
+	}
+	return ""
+}
+
+// CppFixtureValue returns the expression generating a pseudo-random value of the property from a std::mt19937_64
+// named "rng", used by the generated random<Entity>Fixture(), or an empty string if the property keeps its default
+// value, i.e. the ID and relations, which would otherwise point to objects that don't exist.
+func (mp *fbsField) CppFixtureValue() string {
+	var property = mp.ModelProperty
+	if property.IsIdProperty() || property.Type == model.PropertyTypeRelation {
+		return ""
+	}
+
+	if property.Enum != nil && len(property.Enum.Values) > 0 {
+		var values []string
+		for _, value := range property.Enum.Values {
+			values = append(values, mp.CppValueType()+"::"+value.Name)
+		}
+		return fmt.Sprintf("std::array<%s, %d>{%s}[rng() %% %d]", mp.CppValueType(), len(values), strings.Join(values, ", "), len(values))
+	}
+
+	var randomString = fmt.Sprintf("%q + std::to_string(rng() %% 1000000)", mp.Name+"-")
+	if property.Entity.Meta.(*fbsObject).pmr && (property.Type == model.PropertyTypeString || property.Type == model.PropertyTypeStringVector) {
+		randomString = "std::pmr::string(" + randomString + ")" // the conversion from std::string is explicit
+	}
+	switch property.Type {
+	case model.PropertyTypeBool:
+		return "rng() % 2 == 1"
+	case model.PropertyTypeDate:
+		return "static_cast<" + mp.CppType() + ">(1700000000000 + rng() % 31536000000)" // within a year from 2023-11-14
+	case model.PropertyTypeDateNano:
+		return "static_cast<" + mp.CppType() + ">((1700000000000 + rng() % 31536000000) * 1000000)"
+	case model.PropertyTypeFloat, model.PropertyTypeDouble:
+		return fmt.Sprintf("std::uniform_real_distribution<%s>(0, 1)(rng)", mp.CppType())
+	case model.PropertyTypeString:
+		return randomString
+	case model.PropertyTypeStringVector:
+		return fmt.Sprintf("%s{%s, %s}", mp.CppValueType(), randomString, randomString)
+	case model.PropertyTypeByteVector:
+		var element = "static_cast<" + mp.CElementType() + ">(rng())"
+		return fmt.Sprintf("%s{%s}", mp.CppValueType(), strings.Repeat(element+", ", fixtureVectorLength-1)+element)
+	case model.PropertyTypeFloatVector:
+		var length = uint64(fixtureVectorLength)
+		if property.ArrayLength > 0 {
+			length = uint64(property.ArrayLength)
+		} else if property.HnswParams != nil && property.HnswParams.Dimensions != nil {
+			length = *property.HnswParams.Dimensions
+		}
+		var init = fmt.Sprintf("(%d)", length)
+		if property.ArrayLength > 0 {
+			init = "{}"
+		}
+		return fmt.Sprintf("[&rng]() { %s values%s; for (auto& value : values) value = std::uniform_real_distribution<float>(0, 1)(rng); return values; }()",
+			mp.CppValueType(), init)
+	}
+	return "static_cast<" + mp.CppType() + ">(rng())"
+}
+
+// CppExportRead returns the expression reading the property value from a flatbuffers::Table named "table" as a
+// nlohmann::json value, used by the generated toExportRecord(). Byte vectors are base64-encoded and missing optional
+// values and vectors are null; see the obx_export helpers in the export template.
+func (mp *fbsField) CppExportRead() (string, error) {
+	offset, err := mp.ModelProperty.FbvTableOffset()
+	if err != nil {
+		return "", err
+	}
+	switch mp.ModelProperty.Type {
+	case model.PropertyTypeString:
+		return fmt.Sprintf("obx_export::stringValue(table->GetPointer<const flatbuffers::String*>(%d), %v)", offset, len(mp.Optional) != 0), nil
+	case model.PropertyTypeStringVector:
+		return fmt.Sprintf("obx_export::stringsValue(table->GetPointer<const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>*>(%d))", offset), nil
+	case model.PropertyTypeByteVector:
+		return fmt.Sprintf("obx_export::bytesValue(table->GetPointer<const %s*>(%d))", mp.FbOffsetType(), offset), nil
+	case model.PropertyTypeFloatVector:
+		return fmt.Sprintf("obx_export::vectorValue(table->GetPointer<const %s*>(%d))", mp.FbOffsetType(), offset), nil
+	}
+
+	var value = fmt.Sprintf("table->GetField<%s>(%d, %s)", mp.CppFbType(), offset, mp.FbDefaultValue())
+	if mp.CppType() == "bool" {
+		value = value + " != 0"
+	}
+	if len(mp.Optional) != 0 {
+		return fmt.Sprintf("table->CheckField(%d) ? nlohmann::json(%s) : nlohmann::json()", offset, value), nil
+	}
+	return value, nil
+}
+
+// CppExportOffset returns the statements creating the FlatBuffers offset of a string or vector property from a
+// nlohmann::json named "record", used by the generated fromExportRecord(), or an empty string for scalars
+func (mp *fbsField) CppExportOffset() string {
+	var offsetType, value string
+	var key = fmt.Sprintf("%q", mp.ModelProperty.Name)
+	switch mp.ModelProperty.Type {
+	case model.PropertyTypeString:
+		offsetType = "flatbuffers::String"
+		value = "fbb.CreateString(record.at(" + key + ").get<std::string>())"
+	case model.PropertyTypeStringVector:
+		offsetType = "flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>"
+		value = "fbb.CreateVectorOfStrings(record.at(" + key + ").get<std::vector<std::string>>())"
+	case model.PropertyTypeByteVector:
+		offsetType = "flatbuffers::Vector<uint8_t>"
+		value = "fbb.CreateVector(obx_export::base64Decode(record.at(" + key + ").get<std::string>()))"
+	case model.PropertyTypeFloatVector:
+		offsetType = mp.FbOffsetType()
+		value = "fbb.CreateVector(record.at(" + key + ").get<std::vector<" + mp.CElementType() + ">>())"
+	default:
+		return ""
+	}
+	return fmt.Sprintf("flatbuffers::Offset<%s> offset%s;\n\tif (obx_export::has(record, %s)) offset%s = %s;",
+		offsetType, mp.Name, key, mp.Name, value)
+}
+
+// CppExportWrite returns the statement adding the property value from a nlohmann::json named "record" to the table
+// built by the generated fromExportRecord(); missing and null values are left out, as if the property was empty
+func (mp *fbsField) CppExportWrite() (string, error) {
+	offset, err := mp.ModelProperty.FbvTableOffset()
+	if err != nil {
+		return "", err
+	}
+	if len(mp.CppExportOffset()) > 0 {
+		return fmt.Sprintf("fbb.AddOffset(%d, offset%s);", offset, mp.Name), nil
+	}
+	var key = fmt.Sprintf("%q", mp.ModelProperty.Name)
+	var value = "record.at(" + key + ").get<" + mp.CppFbType() + ">()"
+	if mp.CppType() == "bool" {
+		value = "record.at(" + key + ").get<bool>() ? 1 : 0"
+	}
+	return fmt.Sprintf("if (obx_export::has(record, %s)) fbb.AddElement<%s>(%d, %s);", key, mp.CppFbType(), offset, value), nil
+}
+
+// CppExportText checks whether the exported value of the property is a string in the JSON record, i.e. it's kept as
+// the text of its cell when importing CSV instead of being parsed as JSON
+func (mp *fbsField) CppExportText() bool {
+	return mp.ModelProperty.Type == model.PropertyTypeString || mp.ModelProperty.Type == model.PropertyTypeByteVector
+}
+
 // CppValOp returns field value access operator
 func (mp *fbsField) CppValOp() string {
 	if len(mp.Optional) != 0 {
@@ -202,6 +523,11 @@ func (mp *fbsField) CppValOp() string {
 	return "."
 }
 
+// CppFloat16 returns true if the float vector is stored with half precision, i.e. converted by the binding
+func (mp *fbsField) CppFloat16() bool {
+	return mp.ModelProperty.StorageType == model.StorageTypeFloat16
+}
+
 // FbIsVector returns true if the property is considered a vector type.
 func (mp *fbsField) FbIsVector() bool {
 	switch mp.ModelProperty.Type {
@@ -267,6 +593,9 @@ func (mp *fbsField) FbOffsetType() string {
 	case model.PropertyTypeByteVector:
 		return "flatbuffers::Vector<" + fbsTypeToCppType[mp.fbsField.Type(nil).Element()] + ">"
 	case model.PropertyTypeFloatVector:
+		if mp.ModelProperty.StorageType == model.StorageTypeFloat16 {
+			return "flatbuffers::Vector<uint16_t>" // IEEE 754 half-precision values, see CppFloat16()
+		}
 		return "flatbuffers::Vector<" + fbsTypeToCppType[mp.fbsField.Type(nil).Element()] + ">"
 	case model.PropertyTypeStringVector:
 		return "" // NOTE custom handling in the template
@@ -296,19 +625,13 @@ func (mp *fbsField) FbIsFloatingPoint() bool {
 	return false
 }
 
-// Try to determine the namespace of the target entity but don't fail if we can't because it's declared in a different
-// file. Assume no namespace in that case and hope for the best.
 func (mp *fbsField) relTargetNamespace() string {
-	if targetEntity, err := mp.ModelProperty.Entity.Model.FindEntityByName(mp.ModelProperty.RelationTarget); err == nil {
-		if targetEntity.Meta != nil {
-			return targetEntity.Meta.(*fbsObject).Namespace
-		}
-	}
-	return ""
+	return mp.ModelProperty.Entity.Meta.(*fbsObject).relTargetNamespace(mp.ModelProperty.RelationTarget)
 }
 
 type standaloneRel struct {
 	ModelRelation *model.StandaloneRelation
+	entity        *fbsObject
 }
 
 // Merge implements model.PropertyMeta interface
@@ -321,3 +644,152 @@ func (mr *standaloneRel) Merge(rel *model.StandaloneRelation) model.StandaloneRe
 func (mr *standaloneRel) CppName() string {
 	return cppName(mr.ModelRelation.Name)
 }
+
+// CppNameRelationTarget returns C++ target class name, including the namespace, with reserved keywords suffixed by an underscore
+func (mr *standaloneRel) CppNameRelationTarget() string {
+	var target = mr.ModelRelation.Target.Name
+	return cppNamespacePrefix(mr.entity.relTargetNamespace(target)) + cppName(target)
+}
+
+// cppEnum wraps model.Enum to provide C++ specific template functions
+type cppEnum struct {
+	*model.Enum
+}
+
+func cppEnums(entities []*model.Entity) []*cppEnum {
+	var result []*cppEnum
+	for _, enum := range model.EnumsOf(entities) {
+		result = append(result, &cppEnum{enum})
+	}
+	return result
+}
+
+// CppName returns C++ enum name with reserved keywords suffixed by an underscore
+func (enum *cppEnum) CppName() string {
+	return cppName(enum.Name)
+}
+
+// CppType returns the underlying C++ integer type
+func (enum *cppEnum) CppType() string {
+	var cppType string
+	switch enum.Type {
+	case model.PropertyTypeByte:
+		cppType = "int8_t"
+	case model.PropertyTypeShort:
+		cppType = "int16_t"
+	case model.PropertyTypeInt:
+		cppType = "int32_t"
+	default:
+		cppType = "int64_t"
+	}
+	if enum.Unsigned {
+		cppType = "u" + cppType
+	}
+	return cppType
+}
+
+// CppNamespaceStart returns c++ namespace opening declaration
+func (enum *cppEnum) CppNamespaceStart() string {
+	return cppNamespaceStart(enum.Namespace)
+}
+
+// CppNamespaceEnd returns c++ namespace closing declaration
+func (enum *cppEnum) CppNamespaceEnd() string {
+	return cppNamespaceEnd(enum.Namespace)
+}
+
+// Guard returns a preprocessor macro name guarding the declaration, so that multiple headers may declare the same enum
+func (enum *cppEnum) Guard() string {
+	return "OBX_ENUM_" + strings.Replace(enum.FullName(), ".", "_", -1)
+}
+
+// cppConstantGroup wraps model.ConstantGroup to provide C & C++ specific template functions
+type cppConstantGroup struct {
+	*model.ConstantGroup
+}
+
+func cppConstantGroups(groups []*model.ConstantGroup) []*cppConstantGroup {
+	var result []*cppConstantGroup
+	for _, group := range groups {
+		result = append(result, &cppConstantGroup{group})
+	}
+	return result
+}
+
+// CppName returns C++ namespace name of the group with reserved keywords suffixed by an underscore
+func (group *cppConstantGroup) CppName() string {
+	return cppName(group.Name)
+}
+
+// CName returns the prefix of the C macros, i.e. the group name prefixed by the namespace, e.g. "my_ns_Limits"
+func (group *cppConstantGroup) CName() string {
+	return strings.Replace(group.FullName(), ".", "_", -1)
+}
+
+// CppNamespaceStart returns c++ namespace opening declaration
+func (group *cppConstantGroup) CppNamespaceStart() string {
+	return cppNamespaceStart(group.Namespace)
+}
+
+// CppNamespaceEnd returns c++ namespace closing declaration
+func (group *cppConstantGroup) CppNamespaceEnd() string {
+	return cppNamespaceEnd(group.Namespace)
+}
+
+// Guard returns a preprocessor macro name guarding the declaration, so that multiple headers may declare the same group
+func (group *cppConstantGroup) Guard() string {
+	return "OBX_CONSTANTS_" + group.CName()
+}
+
+// Constants returns the constants of the group with C & C++ specific template functions
+func (group *cppConstantGroup) Constants() []*cppConstant {
+	var result []*cppConstant
+	for _, constant := range group.ConstantGroup.Constants {
+		result = append(result, &cppConstant{constant})
+	}
+	return result
+}
+
+// cppConstant wraps model.Constant to provide C & C++ specific template functions
+type cppConstant struct {
+	*model.Constant
+}
+
+// CppName returns C++ constant name with reserved keywords suffixed by an underscore
+func (constant *cppConstant) CppName() string {
+	return cppName(constant.Name)
+}
+
+// CppType returns the C & C++ type of the constant
+func (constant *cppConstant) CppType() string {
+	switch constant.Type {
+	case model.PropertyTypeBool:
+		return "bool"
+	case model.PropertyTypeFloat:
+		return "float"
+	case model.PropertyTypeDouble:
+		return "double"
+	}
+	return (&cppEnum{&model.Enum{Type: constant.Type, Unsigned: constant.Unsigned}}).CppType()
+}
+
+// Literal returns the value with a suffix for unsigned and 64-bit integers, e.g. "64u" or "5000000000LL",
+// so that even the values outside the range of an int are valid literals of the right signedness
+func (constant *cppConstant) Literal() string {
+	var suffix string
+	switch constant.Type {
+	case model.PropertyTypeByte, model.PropertyTypeShort, model.PropertyTypeInt:
+		if constant.Unsigned {
+			suffix = "u"
+		}
+	case model.PropertyTypeLong:
+		suffix = "LL"
+		if constant.Unsigned {
+			suffix = "ULL"
+		}
+	}
+	if constant.Value == "-9223372036854775808" {
+		return "(-9223372036854775807LL - 1)" // the literal without the sign would be out of range
+	}
+	return constant.Value + suffix
+}
@@ -23,32 +23,47 @@ package cgenerator
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/binding"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
 var supportedEntityAnnotations = map[string]bool{
-	"name":          true,
-	"relation":      true, // to-many, standalone
-	"sync":          true,
-	"transient":     true,
-	"uid":           true,
-	"external-name": true,
+	"name":                true,
+	"relation":            true, // to-many, standalone
+	"backlink":            true, // to-many inverse of a to-one relation
+	"retired":             true,
+	"reserved":            true, // property IDs
+	"reserved-entity-ids": true,
+	"reserved-uids":       true,
+	"sync":                true,
+	"transient":           true,
+	"uid":                 true,
+	"external-name":       true,
+	"accessors":           true, // C++ only
+	"pmr":                 true, // C++ only
+	"constants":           true, // not an entity, the fields declare constants, see readConstants()
 }
 
 var supportedPropertyAnnotations = map[string]bool{
+	"cascade":                              true, // on to-one relations
 	"date":                                 true,
 	"date-nano":                            true,
+	"external-id":                          true,
 	"id":                                   true,
 	"id-companion":                         true,
 	"index":                                true,
 	"name":                                 true,
 	"optional":                             true,
 	"relation":                             true, // to-one
+	"retired":                              true,
+	"storage-type":                         true, // float vectors
 	"transient":                            true,
 	"uid":                                  true,
 	"unique":                               true,
@@ -63,6 +78,11 @@ var supportedPropertyAnnotations = map[string]bool{
 	"external-type":                        true,
 }
 
+// SupportedAnnotations returns the names of the entity and property annotations accepted in FlatBuffers schemas
+func SupportedAnnotations() (entity, property []string) {
+	return binding.SupportedAnnotationNames(supportedEntityAnnotations), binding.SupportedAnnotationNames(supportedPropertyAnnotations)
+}
+
 // fbSchemaReader reads FlatBuffers schema and populates a model
 type fbSchemaReader struct {
 	// model produced by reading the schema
@@ -70,17 +90,52 @@ type fbSchemaReader struct {
 
 	// see CGenerator.Optional
 	optional string
+
+	// see CGenerator.Accessors, may be overridden by the "accessors" entity annotation
+	accessors bool
+
+	// see CGenerator.Pmr, may be overridden by the "pmr" entity annotation
+	pmr bool
+
+	// see CGenerator.StrictSchema
+	strict bool
+
+	// see CGenerator.ResolveSources()
+	entityNamespaces map[string]string
+
+	// the parsed schema file; objects declared in the files it includes are skipped
+	sourceFile string
+
+	// the schema being read and enums referenced by its fields, indexed by their position in the schema
+	schema *reflection.Schema
+	enums  map[int32]*model.Enum
 }
 
 // const annotationPrefix = "objectbox:"
 
 func (r *fbSchemaReader) read(schema *reflection.Schema) error {
+	r.schema = schema
+	r.enums = make(map[int32]*model.Enum)
+
+	if r.entityNamespaces == nil {
+		r.entityNamespaces = make(map[string]string)
+	}
+
 	for i := 0; i < schema.ObjectsLength(); i++ {
 		var object reflection.Object
 		if !schema.Objects(&object, i) {
 			return fmt.Errorf("can't access object %d", i)
 		}
 
+		// included objects are generated along with the file declaring them, only their namespace is needed for relations
+		if len(r.sourceFile) != 0 && !flatbuffersc.DeclaredIn(&object, r.sourceFile) {
+			var name = string(object.Name())
+			if lastDot := strings.LastIndex(name, "."); lastDot > 0 {
+				r.entityNamespaces[strings.ToLower(name[lastDot+1:])] = name[:lastDot]
+			}
+			continue
+		}
+
 		if err := r.readObject(&object); err != nil {
 			return fmt.Errorf("object %d %s: %v", i, string(object.Name()), err)
 		}
@@ -91,7 +146,7 @@ func (r *fbSchemaReader) read(schema *reflection.Schema) error {
 
 func (r *fbSchemaReader) readObject(object *reflection.Object) error {
 	var entity = model.CreateEntity(r.model, 0, 0)
-	var metaEntity = &fbsObject{binding.CreateObject(entity), object}
+	var metaEntity = &fbsObject{binding.CreateObject(entity), object, r.entityNamespaces, r.accessors, r.pmr}
 	entity.Meta = metaEntity
 	metaEntity.SetName(string(object.Name()))
 
@@ -105,18 +160,50 @@ func (r *fbSchemaReader) readObject(object *reflection.Object) error {
 			entity.Comments = append(entity.Comments, comment)
 		}
 	}
+	entity.Comments = model.DocComments(entity.Comments)
+
+	if annotations["constants"] != nil {
+		return r.readConstants(object, annotations, entity.Comments)
+	}
+
+	if err := parseSyncAttribute(object, &annotations); err != nil {
+		return err
+	}
 
 	if err := metaEntity.ProcessAnnotations(annotations); err != nil {
 		return err
 	}
 
 	if metaEntity.IsSkipped {
+		if metaEntity.IsRetired {
+			r.model.RetiredEntityNames = append(r.model.RetiredEntityNames, entity.Name)
+		}
 		return nil
 	}
 
+	if annotations["accessors"] != nil {
+		if len(annotations["accessors"].Value) == 0 {
+			metaEntity.accessors = true
+		} else if value, err := strconv.ParseBool(annotations["accessors"].Value); err != nil {
+			return fmt.Errorf("accessors annotation value must be empty, true or false, found %s", annotations["accessors"].Value)
+		} else {
+			metaEntity.accessors = value
+		}
+	}
+
+	if annotations["pmr"] != nil {
+		if len(annotations["pmr"].Value) == 0 {
+			metaEntity.pmr = true
+		} else if value, err := strconv.ParseBool(annotations["pmr"].Value); err != nil {
+			return fmt.Errorf("pmr annotation value must be empty, true or false, found %s", annotations["pmr"].Value)
+		} else {
+			metaEntity.pmr = value
+		}
+	}
+
 	// attach "meta" objects to relations
 	for _, rel := range entity.Relations {
-		rel.Meta = &standaloneRel{ModelRelation: rel}
+		rel.Meta = &standaloneRel{ModelRelation: rel, entity: metaEntity}
 	}
 
 	for i := 0; i < object.FieldsLength(); i++ {
@@ -157,15 +244,27 @@ func (r *fbSchemaReader) readObjectField(entity *model.Entity, field *reflection
 			property.Comments = append(property.Comments, comment)
 		}
 	}
+	property.Comments = model.DocComments(property.Comments)
 
 	if err := metaProperty.PreProcessAnnotations(annotations); err != nil {
 		return err
 	}
 
 	if metaProperty.IsSkipped {
+		if metaProperty.IsRetired {
+			entity.AddRetiredProperty(property.Name)
+		} else {
+			entity.AddTransientProperty(property.Name, metaProperty.AllowDrop)
+		}
 		return nil
 	}
 
+	if r.strict {
+		if err := checkUnsupportedFieldFeatures(field); err != nil {
+			return err
+		}
+	}
+
 	if fbsType := field.Type(nil); fbsType == nil {
 		return errors.New("can't access Type() from the source schema")
 	} else {
@@ -192,6 +291,15 @@ func (r *fbSchemaReader) readObjectField(entity *model.Entity, field *reflection
 			return fmt.Errorf("unsupported type: %s", reflection.EnumNamesBaseType[fbsBaseType])
 		}
 
+		// integer fields declared with an enum type keep their base type, the enum is only used by the templates
+		if fbsType.Index() >= 0 && fbsBaseType != reflection.BaseTypeVector {
+			if enum, err := r.readEnum(fbsType.Index()); err != nil {
+				return err
+			} else {
+				property.Enum = enum
+			}
+		}
+
 		// apply flags defined for this type (e.g.
 		property.AddFlag(fbsTypeToObxFlag[fbsBaseType])
 	}
@@ -201,16 +309,165 @@ func (r *fbSchemaReader) readObjectField(entity *model.Entity, field *reflection
 			return errors.New("optional annotation value must be empty")
 		}
 		annotations["optional"].Value = r.optional
+	} else if isOptionalScalar(field) {
+		// FlatBuffers optional scalars, e.g. `count: int = null;`, behave the same as the "optional" annotation
+		annotations["optional"] = &binding.Annotation{Value: r.optional}
 	}
 
 	if err := metaProperty.ProcessAnnotations(annotations); err != nil {
 		return err
 	}
 
+	// fixed-length arrays, e.g. `[float:4]`, are parsed as vectors with the length attribute, see flatbuffersc
+	var attr reflection.KeyValue
+	if field.AttributesByKey(&attr, flatbuffersc.ArrayLengthAttribute) {
+		if length, err := strconv.ParseUint(string(attr.Value()), 10, 32); err != nil {
+			return fmt.Errorf("invalid array length: %s", err)
+		} else if err := property.SetArrayLength(uint32(length)); err != nil {
+			return err
+		}
+	}
+
 	entity.Properties = append(entity.Properties, property)
 	return nil
 }
 
+// readEnum returns the enum at the given index of the schema, reading it on the first access
+func (r *fbSchemaReader) readEnum(index int32) (*model.Enum, error) {
+	if enum, found := r.enums[index]; found {
+		return enum, nil
+	}
+
+	var fbsEnum reflection.Enum
+	if !r.schema.Enums(&fbsEnum, int(index)) {
+		return nil, fmt.Errorf("can't access enum %d", index)
+	}
+	if fbsEnum.IsUnion() {
+		return nil, fmt.Errorf("unions are not supported: %s", string(fbsEnum.Name()))
+	}
+
+	var enum = &model.Enum{Name: string(fbsEnum.Name())}
+	if lastDot := strings.LastIndex(enum.Name, "."); lastDot > 0 {
+		enum.Namespace = enum.Name[:lastDot]
+		enum.Name = enum.Name[lastDot+1:]
+	}
+
+	if fbsType := fbsEnum.UnderlyingType(nil); fbsType == nil {
+		return nil, fmt.Errorf("enum %s: can't access UnderlyingType()", enum.Name)
+	} else {
+		enum.Type = fbsTypeToObxType[fbsType.BaseType()]
+		enum.Unsigned = fbsTypeToObxFlag[fbsType.BaseType()]&model.PropertyFlagUnsigned != 0
+	}
+
+	for i := 0; i < fbsEnum.DocumentationLength(); i++ {
+		enum.Comments = append(enum.Comments, strings.TrimSpace(string(fbsEnum.Documentation(i))))
+	}
+	enum.Comments = model.DocComments(enum.Comments)
+
+	for i := 0; i < fbsEnum.ValuesLength(); i++ {
+		var fbsValue reflection.EnumVal
+		if !fbsEnum.Values(&fbsValue, i) {
+			return nil, fmt.Errorf("enum %s: can't access value %d", enum.Name, i)
+		}
+		var value = &model.EnumValue{Name: string(fbsValue.Name()), Value: fbsValue.Value()}
+		for j := 0; j < fbsValue.DocumentationLength(); j++ {
+			value.Comments = append(value.Comments, strings.TrimSpace(string(fbsValue.Documentation(j))))
+		}
+		value.Comments = model.DocComments(value.Comments)
+		enum.Values = append(enum.Values, value)
+	}
+
+	r.enums[index] = enum
+	return enum, nil
+}
+
+// readConstants reads a table annotated "constants": instead of an entity, it declares a model.ConstantGroup with
+// the fields as constants and their default values as the values, e.g. `maxNameLength: int = 64;`
+func (r *fbSchemaReader) readConstants(object *reflection.Object, annotations map[string]*binding.Annotation, comments []string) error {
+	if len(annotations["constants"].Value) != 0 {
+		return errors.New("constants annotation value must be empty")
+	} else if len(annotations) > 1 {
+		return errors.New("constants annotation can't be combined with other annotations, the table isn't an entity")
+	}
+
+	var group = &model.ConstantGroup{Name: string(object.Name()), Comments: comments}
+	if lastDot := strings.LastIndex(group.Name, "."); lastDot > 0 {
+		group.Namespace = group.Name[:lastDot]
+		group.Name = group.Name[lastDot+1:]
+	}
+
+	var fields = make([]*reflection.Field, object.FieldsLength())
+	for i := range fields {
+		fields[i] = &reflection.Field{}
+		if !object.Fields(fields[i], i) {
+			return fmt.Errorf("can't access field %d", i)
+		}
+	}
+
+	// the same order as in the schema, see readObject()
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Id() < fields[j].Id()
+	})
+
+	for _, field := range fields {
+		var constant = &model.Constant{Name: string(field.Name())}
+		if err := readConstantValue(field, constant); err != nil {
+			return fmt.Errorf("constant %s: %v", constant.Name, err)
+		}
+		for i := 0; i < field.DocumentationLength(); i++ {
+			constant.Comments = append(constant.Comments, strings.TrimSpace(string(field.Documentation(i))))
+		}
+		constant.Comments = model.DocComments(constant.Comments)
+		group.Constants = append(group.Constants, constant)
+	}
+
+	r.model.ConstantGroups = append(r.model.ConstantGroups, group)
+	return nil
+}
+
+// readConstantValue sets the type and the value of the constant from the field and its default value
+func readConstantValue(field *reflection.Field, constant *model.Constant) error {
+	var fbsType = field.Type(nil)
+	if fbsType == nil {
+		return errors.New("can't access Type() from the source schema")
+	}
+	var fbsBaseType = fbsType.BaseType()
+	if fbsType.Index() >= 0 {
+		return errors.New("enum and table types are not supported, use the underlying type instead")
+	}
+
+	constant.Type = fbsTypeToObxType[fbsBaseType]
+	constant.Unsigned = fbsTypeToObxFlag[fbsBaseType]&model.PropertyFlagUnsigned != 0
+	switch constant.Type {
+	case model.PropertyTypeBool:
+		constant.Value = strconv.FormatBool(field.DefaultInteger() != 0)
+	case model.PropertyTypeByte, model.PropertyTypeShort, model.PropertyTypeInt, model.PropertyTypeLong:
+		if constant.Unsigned {
+			constant.Value = strconv.FormatUint(uint64(field.DefaultInteger()), 10)
+		} else {
+			constant.Value = strconv.FormatInt(field.DefaultInteger(), 10)
+		}
+	case model.PropertyTypeFloat, model.PropertyTypeDouble:
+		var value = field.DefaultReal()
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return fmt.Errorf("value %v is not supported, only finite numbers can be constants", value)
+		}
+		var bitSize = 64
+		if constant.Type == model.PropertyTypeFloat {
+			bitSize = 32
+		}
+		constant.Value = strconv.FormatFloat(value, 'g', -1, bitSize)
+	default:
+		return fmt.Errorf("type %s is not supported, only scalars can be constants", reflection.EnumNamesBaseType[fbsBaseType])
+	}
+
+	// optional scalars, e.g. `count: int = null;`
+	if field.Optional() {
+		return errors.New("a constant must have a value, null is not supported")
+	}
+	return nil
+}
+
 // NOTE this is a copy of gogenerator.parseAnnotations with changes to accommodate a different format
 func parseCommentAsAnnotations(comment string, annotations *map[string]*binding.Annotation, supportedAnnotations map[string]bool) (bool, error) {
 	if strings.HasPrefix(comment, "objectbox:") || strings.HasPrefix(comment, "ObjectBox:") {
@@ -222,3 +479,50 @@ func parseCommentAsAnnotations(comment string, annotations *map[string]*binding.
 	}
 	return false, nil
 }
+
+// parseSyncAttribute handles the native FlatBuffers attribute, i.e. `table Foo (sync)` or
+// `table Foo (sync: "sharedGlobalIds")`, as an alternative to the `/// objectbox:sync` annotation.
+// Note: flatc requires the attribute to be declared in the schema using `attribute "sync";`
+func parseSyncAttribute(object *reflection.Object, annotations *map[string]*binding.Annotation) error {
+	var attr reflection.KeyValue
+	if !object.AttributesByKey(&attr, "sync") {
+		return nil
+	}
+
+	// flatc stores "0" as the value of attributes declared without one
+	var str = "sync"
+	if value := strings.TrimSpace(string(attr.Value())); len(value) != 0 && value != "0" {
+		str = "sync(" + value + ")"
+	}
+	if err := binding.ParseAnnotations(str, annotations, supportedEntityAnnotations); err != nil {
+		return fmt.Errorf("sync attribute: %s", err)
+	}
+	return nil
+}
+
+// isOptionalScalar returns true for scalar fields declared with a `null` default value.
+// NOTE flatc reports all non-scalar fields as optional, these are nullable in the generated code anyway.
+func isOptionalScalar(field *reflection.Field) bool {
+	if !field.Optional() {
+		return false
+	}
+	var fbsType = field.Type(nil)
+	return fbsType != nil && fbsType.BaseType() >= reflection.BaseTypeBool && fbsType.BaseType() <= reflection.BaseTypeDouble
+}
+
+// checkUnsupportedFieldFeatures rejects FlatBuffers field features that have no effect in ObjectBox, so that schema
+// authors aren't misled about the runtime behavior. Only used in the "strict schema" mode.
+func checkUnsupportedFieldFeatures(field *reflection.Field) error {
+	// NOTE check key first, flatc implicitly marks key fields as required
+	if field.Key() {
+		return errors.New("attribute 'key' is not supported by ObjectBox - use the `/// objectbox:unique` or `/// objectbox:index` annotation instead")
+	}
+	if field.Required() {
+		return errors.New("attribute 'required' is not supported by ObjectBox - objects are stored even if the field is not set; remove the attribute or disable strict schema mode")
+	}
+	var attr reflection.KeyValue
+	if field.AttributesByKey(&attr, "nested_flatbuffer") {
+		return errors.New("attribute 'nested_flatbuffer' is not supported by ObjectBox - the field is stored as a plain byte vector; remove the attribute or disable strict schema mode")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// CppBenchmarkTemplate is used to generate google-benchmark functions measuring the FlatBuffers serialization
+var CppBenchmarkTemplate = template.Must(template.New("benchmark-cpp").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Benchmarks of the FlatBuffers serialization, link with benchmark::benchmark_main (google-benchmark) to run them.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+#include <benchmark/benchmark.h>
+
+#include "{{.HeaderFile}}"
+{{range $entity := .Entities}}
+{{- $type := print $entity.Meta.CppNamespacePrefix $entity.Meta.CppName}}
+static void BM_{{$entity.Meta.CName}}_toFlatBuffer(benchmark::State& state) {
+	{{$type}} object{};
+	flatbuffers::FlatBufferBuilder fbb;
+	for (auto _ : state) {
+		{{$type}}::_OBX_MetaInfo::toFlatBuffer(fbb, object);
+		benchmark::DoNotOptimize(fbb.GetBufferPointer());
+	}
+}
+BENCHMARK(BM_{{$entity.Meta.CName}}_toFlatBuffer);
+
+static void BM_{{$entity.Meta.CName}}_fromFlatBuffer(benchmark::State& state) {
+	{{$type}} object{};
+	flatbuffers::FlatBufferBuilder fbb;
+	{{$type}}::_OBX_MetaInfo::toFlatBuffer(fbb, object);
+	for (auto _ : state) {
+		{{$type}}::_OBX_MetaInfo::fromFlatBuffer(fbb.GetBufferPointer(), fbb.GetSize(), object);
+		benchmark::DoNotOptimize(object);
+	}
+}
+BENCHMARK(BM_{{$entity.Meta.CName}}_fromFlatBuffer);
+{{end}}
+{{- block "file-footer" .}}{{end}}`))
@@ -27,6 +27,7 @@ import (
 // CBindingTemplate is used to generated the binding code
 var CBindingTemplate = template.Must(template.New("binding-c").Funcs(funcMap).Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
 #pragma once
 
@@ -36,6 +37,9 @@ var CBindingTemplate = template.Must(template.New("binding-c").Funcs(funcMap).Pa
 
 #include "flatcc/flatcc.h"
 #include "flatcc/flatcc_builder.h"
+{{- if .VerifyFlatBuffers}}
+#include "flatcc/flatcc_verifier.h"
+{{- end}}
 #include "objectbox.h"
 
 /// Internal function used in other generated functions to put (write) explicitly typed objects.
@@ -47,14 +51,30 @@ static void* {{.FileIdentifier}}_get_object(OBX_box* box, obx_id id, void* (*fro
 
 /// Internal function used in other generated functions to get a vTable offset for a given field.
 static flatbuffers_voffset_t {{.FileIdentifier}}_fb_field_offset(flatbuffers_voffset_t vs, const flatbuffers_voffset_t* vt, size_t field);
+{{- if and .VerifyFlatBuffers (HasStrings .Entities)}}
+
+/// Internal function used in other generated functions to check strings read from a FlatBuffer are valid UTF-8.
+static bool {{.FileIdentifier}}_utf8_valid(const char* str, size_t len);
+{{- end}}
 
-{{range $entity := .Model.EntitiesWithMeta}}
+{{range $group := .Constants}}
+#ifndef {{$group.Guard}}
+#define {{$group.Guard}}
+{{with $group.Comments}}{{PrintComments 0 .}}
+{{end}}{{range $constant := $group.Constants -}}
+{{PrintComments 0 $constant.Comments}}#define {{$group.CName}}_{{$constant.Name}} (({{$constant.CppType}}) {{$constant.Literal}})
+{{end -}}
+#endif
+{{end}}
+{{range $entity := .Entities}}
 {{PrintComments 0 $entity.Comments}}typedef struct {{$entity.Meta.CName}} {
 	{{range $property := $entity.Properties}}{{$propType := PropTypeName $property.Type -}}
 	{{PrintComments 1 $property.Comments}}{{if $property.Meta.FbIsVector}}{{$property.Meta.CElementType}}* {{$property.Meta.CppName}};
 	{{- if or (or (eq $propType "StringVector") (eq $propType "ByteVector")) (eq $propType "FloatVector")}}
 	size_t {{$property.Meta.CppName}}_len;{{end}}
-	{{else}}{{$property.Meta.CppType}}{{if $property.Meta.Optional}}*{{end}} {{$property.Meta.CppName}};
+	{{else}}{{$property.Meta.CTypeWithOptional}} {{$property.Meta.CppName}};
+	{{- if eq $property.Meta.Optional "flag"}}
+	bool has_{{$property.Meta.CppName}};{{end}}
 	{{end}}{{end}}
 } {{$entity.Meta.CName}};
 
@@ -76,7 +96,16 @@ static bool {{$entity.Meta.CName}}_to_flatbuffer(flatcc_builder_t* B, const {{$e
 /// Thus, when calling this function multiple times on the same object, ensure to call {{$entity.Meta.CName}}_free_pointers() before subsequent calls to avoid leaks. 
 /// @returns true if the object was deserialized successfully or false on (allocation) error in which case any memory 
 ///          allocated by this function will also be freed before returning, allowing you to retry.
+{{- if $.VerifyFlatBuffers}}
+///          Also returns false if the FlatBuffer isn't valid, see {{$entity.Meta.CName}}_verify_flatbuffer().
+{{- end}}
 static bool {{$entity.Meta.CName}}_from_flatbuffer(const void* data, size_t size, {{$entity.Meta.CName}}* out_object);
+{{- if $.VerifyFlatBuffers}}
+
+/// Check the FlatBuffer is well-formed, i.e. all fields are in bounds, e.g. when reading untrusted input.
+/// {{$entity.Meta.CName}}_from_flatbuffer() calls it and additionally checks strings are valid UTF-8.
+static bool {{$entity.Meta.CName}}_verify_flatbuffer(const void* data, size_t size);
+{{- end}}
 
 /// Read an object from a valid FlatBuffer, allocating the object on heap. 
 /// The object must be freed after use by calling {{$entity.Meta.CName}}_free();
@@ -89,7 +118,7 @@ static void {{$entity.Meta.CName}}_free_pointers({{$entity.Meta.CName}}* object)
 /// Equivalent to calling {{$entity.Meta.CName}}_free_pointers() followed by free();
 static void {{$entity.Meta.CName}}_free({{$entity.Meta.CName}}* object);
 {{end}}
-{{- range $entity := .Model.EntitiesWithMeta}}
+{{- range $entity := .Entities}}
 static bool {{$entity.Meta.CName}}_to_flatbuffer(flatcc_builder_t* B, const {{$entity.Meta.CName}}* object, void** out_buffer, size_t* out_size) {
     assert(B);
     assert(object);
@@ -104,6 +133,9 @@ static bool {{$entity.Meta.CName}}_to_flatbuffer(flatcc_builder_t* B, const {{$e
 	{{- else if eq $propType "ByteVector"}}
 	flatcc_builder_ref_t offset_{{$property.Meta.CppName}} = !object->{{$property.Meta.CppName}} ? 0 : flatcc_builder_create_vector(B, object->{{$property.Meta.CppName}}, object->{{$property.Meta.CppName}}_len, sizeof({{$property.Meta.CElementType}}), sizeof({{$property.Meta.CElementType}}), FLATBUFFERS_COUNT_MAX(sizeof({{$property.Meta.CElementType}})));
 	{{- else if eq $propType "FloatVector"}}
+	{{- if $property.ArrayLength}}
+	if (object->{{$property.Meta.CppName}} && object->{{$property.Meta.CppName}}_len != {{$property.ArrayLength}}) return false;  // fixed-length array
+	{{- end}}
 	flatcc_builder_ref_t offset_{{$property.Meta.CppName}} = !object->{{$property.Meta.CppName}} ? 0 : flatcc_builder_create_vector(B, object->{{$property.Meta.CppName}}, object->{{$property.Meta.CppName}}_len, sizeof({{$property.Meta.CElementType}}), sizeof({{$property.Meta.CElementType}}), FLATBUFFERS_COUNT_MAX(sizeof({{$property.Meta.CElementType}})));
 	{{- else if eq $propType "StringVector"}}
 	flatcc_builder_ref_t offset_{{$property.Meta.CppName}} = 0;
@@ -128,9 +160,9 @@ static bool {{$entity.Meta.CName}}_to_flatbuffer(flatcc_builder_t* B, const {{$e
         *_p = offset_{{$property.Meta.CppName}};
     }
 	{{- else}}
-	{{if $property.Meta.Optional}}if (object->{{$property.Meta.CppName}}) {{end}}{
+	{{if eq $property.Meta.Optional "ptr"}}if (object->{{$property.Meta.CppName}}) {{else if eq $property.Meta.Optional "flag"}}if (object->has_{{$property.Meta.CppName}}) {{end}}{
 		if (!(p = flatcc_builder_table_add(B, {{$property.FbSlot}}, {{$property.Meta.FbTypeSize}}, {{$property.Meta.FbTypeSize}}))) return false;
-    	{{$property.Meta.FlatccFnPrefix}}_write_to_pe(p, {{if $property.Meta.Optional}}*{{end}}object->{{$property.Meta.CppName}});
+    	{{$property.Meta.FlatccFnPrefix}}_write_to_pe(p, {{if eq $property.Meta.Optional "ptr"}}*{{end}}object->{{$property.Meta.CppName}});
 	}{{- end}}
 	{{end}}
     flatcc_builder_ref_t ref;
@@ -143,6 +175,9 @@ static bool {{$entity.Meta.CName}}_from_flatbuffer(const void* data, size_t size
 	assert(data);
 	assert(size > 0);
 	assert(out_object);
+	{{- if $.VerifyFlatBuffers}}
+	if (!{{$entity.Meta.CName}}_verify_flatbuffer(data, size)) return false;
+	{{- end}}
 
 	const uint8_t* table = (const uint8_t*) data + __flatbuffers_uoffset_read_from_pe(data);
 	assert(table);
@@ -165,6 +200,18 @@ static bool {{$entity.Meta.CName}}_from_flatbuffer(const void* data, size_t size
 	{{- if $property.Meta.FbIsVector}}
 		val = (const flatbuffers_uoffset_t*)(table + offset + sizeof(flatbuffers_uoffset_t) + __flatbuffers_uoffset_read_from_pe(table + offset));
 		len = (size_t) __flatbuffers_uoffset_read_from_pe(val - 1);
+		{{- if $property.ArrayLength}}
+		if (len != {{$property.ArrayLength}}) {  // fixed-length array
+			{{$entity.Meta.CName}}_free_pointers(out_object);
+			return false;
+		}
+		{{- end}}
+		{{- if and $.VerifyFlatBuffers (eq $propType "String")}}
+		if (!{{$.FileIdentifier}}_utf8_valid((const char*) val, len)) {
+			{{$entity.Meta.CName}}_free_pointers(out_object);
+			return false;
+		}
+		{{- end}}
 		out_object->{{$property.Meta.CppName}} = ({{$property.Meta.CElementType}}*) malloc({{if eq $propType "String"}}(len+1){{else}}len{{end}} * sizeof({{$property.Meta.CElementType}}));
 		if (out_object->{{$property.Meta.CppName}} == NULL) {
 			{{$entity.Meta.CName}}_free_pointers(out_object);
@@ -180,6 +227,13 @@ static bool {{$entity.Meta.CName}}_from_flatbuffer(const void* data, size_t size
 		{{else}}{{/* StringVector - FB vector contains offsets to strings, each must be read separately*/ -}}
 		for (size_t i = 0; i < len; i++, val++) {
 			const uint8_t* str = (const uint8_t*) val + (size_t)__flatbuffers_uoffset_read_from_pe(val) + sizeof(val[0]);
+			{{- if $.VerifyFlatBuffers}}
+			if (!{{$.FileIdentifier}}_utf8_valid((const char*) str, (size_t) __flatbuffers_uoffset_read_from_pe(str - sizeof(val[0])))) {
+				out_object->{{$property.Meta.CppName}}_len = i; // only free() indexes before the current "i"
+				{{$entity.Meta.CName}}_free_pointers(out_object);
+				return false;
+			}
+			{{- end}}
 			out_object->{{$property.Meta.CppName}}[i] = (char*) malloc((strlen((const char*)str) + 1) * sizeof(char));
 			if (out_object->{{$property.Meta.CppName}}[i] == NULL) {
 				out_object->{{$property.Meta.CppName}}_len = i; // only free() indexes before the current "i"
@@ -194,17 +248,41 @@ static bool {{$entity.Meta.CName}}_from_flatbuffer(const void* data, size_t size
 		out_object->{{$property.Meta.CppName}}_len = 0;
 		{{- end}}
 	{{- else}}
-		{{if $property.Meta.Optional -}}
+		{{if eq $property.Meta.Optional "ptr" -}}
 		out_object->{{$property.Meta.CppName}} = ({{$property.Meta.CppType}}*) malloc(sizeof({{$property.Meta.CppType}}));
 		if (out_object->{{$property.Meta.CppName}} == NULL) {
 			{{$entity.Meta.CName}}_free_pointers(out_object);
 			return false;
 		}
 		*{{end}}out_object->{{$property.Meta.CppName}} = {{$property.Meta.FlatccFnPrefix}}_read_from_pe(table + offset);
+		{{- if eq $property.Meta.Optional "flag"}}
+		out_object->has_{{$property.Meta.CppName}} = true;{{end}}
 	{{- end}}
 	}
 	{{end}}return true;
 }
+{{- if $.VerifyFlatBuffers}}
+
+static int {{$entity.Meta.CName}}_verify_table(flatcc_table_verifier_descriptor_t* td) {
+	int ret;
+	{{- range $property := $entity.Properties}}{{$propType := PropTypeName $property.Type}}
+	{{- if eq $propType "String"}}
+	if ((ret = flatcc_verify_string_field(td, {{$property.FbSlot}}, 0))) return ret;
+	{{- else if eq $propType "StringVector"}}
+	if ((ret = flatcc_verify_string_vector_field(td, {{$property.FbSlot}}, 0))) return ret;
+	{{- else if $property.Meta.FbIsVector}}
+	if ((ret = flatcc_verify_vector_field(td, {{$property.FbSlot}}, 0, sizeof({{$property.Meta.CElementType}}), sizeof({{$property.Meta.CElementType}}), FLATBUFFERS_COUNT_MAX(sizeof({{$property.Meta.CElementType}}))))) return ret;
+	{{- else}}
+	if ((ret = flatcc_verify_field(td, {{$property.FbSlot}}, {{$property.Meta.FbTypeSize}}, {{$property.Meta.FbTypeSize}}))) return ret;
+	{{- end}}
+	{{- end}}
+	return flatcc_verify_ok;
+}
+
+static bool {{$entity.Meta.CName}}_verify_flatbuffer(const void* data, size_t size) {
+	return flatcc_verify_table_as_root(data, size, NULL, {{$entity.Meta.CName}}_verify_table) == flatcc_verify_ok;
+}
+{{- end}}
 
 static {{$entity.Meta.CName}}* {{$entity.Meta.CName}}_new_from_flatbuffer(const void* data, size_t size) {
 	{{$entity.Meta.CName}}* object = ({{$entity.Meta.CName}}*) malloc(sizeof({{$entity.Meta.CName}}));
@@ -233,7 +311,7 @@ static void {{$entity.Meta.CName}}_free_pointers({{$entity.Meta.CName}}* object)
 		assert(object->{{$property.Meta.CppName}}_len == 0);
 	{{- end}}
 	}
-	{{else if $property.Meta.Optional -}}
+	{{else if eq $property.Meta.Optional "ptr" -}}
 	if (object->{{$property.Meta.CppName}}) {
 		free(object->{{$property.Meta.CppName}});
 		object->{{$property.Meta.CppName}} = NULL;
@@ -246,7 +324,20 @@ static void {{$entity.Meta.CName}}_free({{$entity.Meta.CName}}* object) {
 	{{$entity.Meta.CName}}_free_pointers(object);
 	free(object);
 }
+{{range $property := $entity.Properties}}{{if and $property.Meta.Optional (not $property.Meta.FbIsVector)}}
+/// Checks whether the optional property {{$property.Meta.CppName}} has a value.
+static bool {{$entity.Meta.CName}}_has_{{$property.Meta.CppName}}(const {{$entity.Meta.CName}}* object) {
+	return {{if eq $property.Meta.Optional "ptr"}}object->{{$property.Meta.CppName}} != NULL{{else}}object->has_{{$property.Meta.CppName}}{{end}};
+}
 
+/// Reads the optional property {{$property.Meta.CppName}}.
+/// @returns false if the property has no value, leaving out_value unchanged.
+static bool {{$entity.Meta.CName}}_get_{{$property.Meta.CppName}}(const {{$entity.Meta.CName}}* object, {{$property.Meta.CppType}}* out_value) {
+	if (!{{$entity.Meta.CName}}_has_{{$property.Meta.CppName}}(object)) return false;
+	*out_value = {{if eq $property.Meta.Optional "ptr"}}*{{end}}object->{{$property.Meta.CppName}};
+	return true;
+}
+{{end}}{{end}}
 /// Insert or update the given object in the database.
 /// @param object (in & out) will be updated with a newly inserted ID if the one specified previously was zero. If an ID 
 /// was already specified (non-zero), it will remain unchanged.
@@ -314,4 +405,41 @@ static void* {{.FileIdentifier}}_get_object(OBX_box* box, obx_id id, void* (*fro
 static flatbuffers_voffset_t {{.FileIdentifier}}_fb_field_offset(flatbuffers_voffset_t vs, const flatbuffers_voffset_t* vt, size_t field) {
     return (vs < sizeof(vt[0]) * (field + 3)) ? 0 : __flatbuffers_voffset_read_from_pe(vt + field + 2);
 }
-`))
+{{- if and .VerifyFlatBuffers (HasStrings .Entities)}}
+
+static bool {{.FileIdentifier}}_utf8_valid(const char* str, size_t len) {
+    const unsigned char* bytes = (const unsigned char*) str;
+    size_t i = 0;
+    while (i < len) {
+        unsigned char c = bytes[i];
+        size_t seq_len;
+        uint32_t code_point;
+        if (c < 0x80) {
+            i++;
+            continue;
+        } else if ((c & 0xE0) == 0xC0) {
+            seq_len = 2;
+            code_point = c & 0x1F;
+        } else if ((c & 0xF0) == 0xE0) {
+            seq_len = 3;
+            code_point = c & 0x0F;
+        } else if ((c & 0xF8) == 0xF0) {
+            seq_len = 4;
+            code_point = c & 0x07;
+        } else {
+            return false;
+        }
+        if (len - i < seq_len) return false;
+        for (size_t j = 1; j < seq_len; j++) {
+            if ((bytes[i + j] & 0xC0) != 0x80) return false;
+            code_point = (code_point << 6) | (bytes[i + j] & 0x3F);
+        }
+        // reject overlong encodings, surrogates and code points above U+10FFFF
+        if ((seq_len == 2 && code_point < 0x80) || (seq_len == 3 && code_point < 0x800) || (seq_len == 4 && code_point < 0x10000)) return false;
+        if (code_point > 0x10FFFF || (code_point >= 0xD800 && code_point <= 0xDFFF)) return false;
+        i += seq_len;
+    }
+    return true;
+}
+{{- end}}
+{{block "file-footer" .}}{{end}}`))
@@ -27,16 +27,123 @@ import (
 // CppBindingTemplate is used to generated the binding code
 var CppBindingTemplate = template.Must(template.New("binding-cpp").Funcs(funcMap).Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
-{{define "field-value"}}{{if .Optional}}*{{end}}object.{{.CppName}}{{end -}}
-{{define "field-value-assign-pre"}}{{if IsOptionalPtr .Optional}}.reset(new {{.CppType}}({{else}} = {{end}}{{end -}}
+{{define "field-value"}}{{if .Optional}}*{{end}}object.{{.CppMemberName}}{{end -}}
+{{define "field-value-assign-pre"}}{{if IsOptionalPtr .Optional}}.reset(new {{.CppValueType}}({{else}} = {{end}}{{end -}}
 {{define "field-value-assign-post"}}{{if IsOptionalPtr .Optional}})){{end}}{{end -}}
 
 #include "{{.HeaderFile}}"
 {{if .NaNAsNull}}
 #include <cmath>
 {{end -}}
-{{range $entity := .Model.EntitiesWithMeta}}
+{{if .VerifyFlatBuffers}}
+#include <stdexcept>
+{{- if HasStrings .Entities}}
+
+namespace {
+/// Checks the string is valid UTF-8, i.e. no invalid bytes, truncated or overlong sequences, surrogates or code points
+/// above U+10FFFF
+bool isValidUtf8(const char* str, size_t size) {
+	const auto* bytes = reinterpret_cast<const unsigned char*>(str);
+	for (size_t i = 0; i < size;) {
+		unsigned char c = bytes[i];
+		size_t len;
+		uint32_t codePoint;
+		if (c < 0x80) {
+			i++;
+			continue;
+		} else if ((c & 0xE0) == 0xC0) {
+			len = 2;
+			codePoint = c & 0x1F;
+		} else if ((c & 0xF0) == 0xE0) {
+			len = 3;
+			codePoint = c & 0x0F;
+		} else if ((c & 0xF8) == 0xF0) {
+			len = 4;
+			codePoint = c & 0x07;
+		} else {
+			return false;
+		}
+		if (size - i < len) return false;
+		for (size_t j = 1; j < len; j++) {
+			if ((bytes[i + j] & 0xC0) != 0x80) return false;
+			codePoint = (codePoint << 6) | (bytes[i + j] & 0x3F);
+		}
+		if ((len == 2 && codePoint < 0x80) || (len == 3 && codePoint < 0x800) || (len == 4 && codePoint < 0x10000)) {
+			return false;  // overlong encoding
+		}
+		if (codePoint > 0x10FFFF || (codePoint >= 0xD800 && codePoint <= 0xDFFF)) return false;
+		i += len;
+	}
+	return true;
+}
+}  // namespace
+{{- end}}
+{{end -}}
+{{if HasFloat16Vectors .Entities}}
+#include <algorithm>
+#include <cstring>
+
+namespace {
+/// Converts to IEEE 754 half precision, rounding to the nearest value (ties to even); out of range values become infinity
+uint16_t float16FromFloat(float value) {
+	uint32_t bits;
+	std::memcpy(&bits, &value, sizeof(bits));
+	auto sign = static_cast<uint16_t>((bits >> 16) & 0x8000);
+	uint32_t exponent = (bits >> 23) & 0xFF;
+	uint32_t mantissa = bits & 0x7FFFFF;
+	if (exponent == 0xFF) return sign | (mantissa ? 0x7E00 : 0x7C00);  // NaN or infinity
+	int32_t halfExponent = static_cast<int32_t>(exponent) - 127 + 15;
+	if (halfExponent >= 0x1F) return sign | 0x7C00;
+	uint32_t shift = 13;
+	if (halfExponent <= 0) {  // subnormal
+		if (halfExponent < -10) return sign;
+		mantissa |= 0x800000;
+		shift = static_cast<uint32_t>(14 - halfExponent);
+		halfExponent = 0;
+	}
+	uint32_t half = (static_cast<uint32_t>(halfExponent) << 10) + (mantissa >> shift);
+	uint32_t rest = mantissa & ((1u << shift) - 1);
+	uint32_t halfway = 1u << (shift - 1);
+	if (rest > halfway || (rest == halfway && (half & 1))) half++;  // may carry into the exponent, up to infinity
+	return sign | static_cast<uint16_t>(half);
+}
+
+/// Converts from IEEE 754 half precision
+float float16ToFloat(uint16_t half) {
+	uint32_t sign = static_cast<uint32_t>(half & 0x8000) << 16;
+	uint32_t exponent = (half >> 10) & 0x1F;
+	uint32_t mantissa = half & 0x3FF;
+	uint32_t bits;
+	if (exponent == 0x1F) {
+		bits = sign | 0x7F800000 | (mantissa << 13);  // infinity or NaN
+	} else if (exponent != 0) {
+		bits = sign | ((exponent + 127 - 15) << 23) | (mantissa << 13);
+	} else if (mantissa == 0) {
+		bits = sign;
+	} else {  // subnormal, normalized for single precision
+		exponent = 127 - 15 + 1;
+		while ((mantissa & 0x400) == 0) {
+			mantissa <<= 1;
+			exponent--;
+		}
+		bits = sign | (exponent << 23) | ((mantissa & 0x3FF) << 13);
+	}
+	float value;
+	std::memcpy(&value, &bits, sizeof(value));
+	return value;
+}
+
+/// Creates a vector of half-precision values, see float16FromFloat()
+flatbuffers::Offset<flatbuffers::Vector<uint16_t>> createFloat16Vector(flatbuffers::FlatBufferBuilder& fbb, const float* values, size_t size) {
+	std::vector<uint16_t> halves(size);
+	std::transform(values, values + size, halves.begin(), float16FromFloat);
+	return fbb.CreateVector(halves);
+}
+}  // namespace
+{{end -}}
+{{range $entity := .Entities}}
 	{{- range $property := $entity.Properties}}
 const 
 		{{- if $property.RelationTarget}} obx::RelationProperty<{{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}, {{$property.Meta.CppNameRelationTarget}}>
@@ -44,23 +151,24 @@ const
 		{{- end}} {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}_::{{$property.Meta.CppName}}({{$property.Id.GetId}});
 	{{- end}}
 	{{- range $relation := $entity.Relations}}
-const obx::RelationStandalone<{{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}, {{$relation.Target.Meta.CppNamespacePrefix}}{{$relation.Target.Meta.CppName}}> {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}_::{{$relation.Meta.CppName}}({{$relation.Id.GetId}});
+const obx::RelationStandalone<{{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}, {{$relation.Meta.CppNameRelationTarget}}> {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}_::{{$relation.Meta.CppName}}({{$relation.Id.GetId}});
 	{{- end}}
 
 void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo::toFlatBuffer(flatbuffers::FlatBufferBuilder& fbb, const {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}& object) {
 	fbb.Clear();
 	{{- range $property := $entity.Properties}}{{$factory := $property.Meta.FbOffsetFactory}}{{if $factory}}
 	auto offset{{$property.Meta.CppName}} =
-		{{- if $property.Meta.Optional}} !object.{{$property.Meta.CppName}} ? 0 : {{end -}}
+		{{- if $property.Meta.Optional}} !object.{{$property.Meta.CppMemberName}} ? 0 : {{end -}}
 		{{- if and $.EmptyStringAsNull (eq "std::string" $property.Meta.CppType) }} ({{template "field-value" $property.Meta}}).empty() ? 0 : 
-		{{- end }} fbb.{{$factory}}({{template "field-value" $property.Meta}});
+		{{- end }} {{if $property.Meta.CppFloat16}}createFloat16Vector(fbb, ({{template "field-value" $property.Meta}}).data(), {{if $property.ArrayLength}}{{$property.ArrayLength}}{{else}}({{template "field-value" $property.Meta}}).size(){{end}})
+		{{- else}}fbb.{{$factory}}({{if $property.ArrayLength}}({{template "field-value" $property.Meta}}).data(), {{$property.ArrayLength}}{{else}}{{template "field-value" $property.Meta}}{{end}}){{end}};
 	{{- end}}{{end}}
 	flatbuffers::uoffset_t fbStart = fbb.StartTable();
 	{{range $property := $entity.Properties}}
-	{{- if $property.Meta.Optional}}if (object.{{$property.Meta.CppName}}) {{end}}
+	{{- if $property.Meta.Optional}}if (object.{{$property.Meta.CppMemberName}}) {{end}}
 	{{- if $property.Meta.FbOffsetFactory}}fbb.AddOffset({{$property.FbvTableOffset}}, offset{{$property.Meta.CppName}});
 	{{- else -}}
-		{{- if and $.NaNAsNull $property.Meta.FbIsFloatingPoint -}} if (!std::isnan({{template "field-value" $property.Meta}})) {{end -}} fbb.AddElement({{$property.FbvTableOffset}}, {{template "field-value" $property.Meta}}{{if eq "bool" $property.Meta.CppType}} ? 1 : 0{{end}});
+		{{- if and $.NaNAsNull $property.Meta.FbIsFloatingPoint -}} if (!std::isnan({{template "field-value" $property.Meta}})) {{end -}} fbb.AddElement({{$property.FbvTableOffset}}, {{if $property.Enum}}static_cast<{{$property.Meta.CppFbType}}>({{template "field-value" $property.Meta}}){{else}}{{template "field-value" $property.Meta}}{{end}}{{if eq "bool" $property.Meta.CppType}} ? 1 : 0{{end}});
 	{{- end}}
 	{{end -}}
 	flatbuffers::Offset<flatbuffers::Table> offset;
@@ -84,7 +192,12 @@ std::unique_ptr<{{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}> {{$
 	return object;
 }
 
-void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo::fromFlatBuffer(const void* data, size_t, {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}& outObject) {
+void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo::fromFlatBuffer(const void* data, size_t{{if $.VerifyFlatBuffers}} size{{end}}, {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}& outObject) {
+	{{- if $.VerifyFlatBuffers}}
+	if (!verifyFlatBuffer(data, size)) {
+		throw std::invalid_argument("{{$entity.Name}}: invalid FlatBuffers data");
+	}
+	{{- end}}
 	const auto* table = flatbuffers::GetRoot<flatbuffers::Table>(data);
 	assert(table);
 	{{- range $property := $entity.Properties}}
@@ -92,18 +205,31 @@ void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo:
 	{
 		auto* ptr = table->GetPointer<const flatbuffers::String*>({{$property.FbvTableOffset}});
 		if (ptr) {
-			outObject.{{$property.Meta.CppName}}
+			{{- if and $.ReuseBuffers (IsOptionalReusable $property.Meta.Optional)}}
+			if (outObject.{{$property.Meta.CppMemberName}}) {
+				outObject.{{$property.Meta.CppMemberName}}->assign(ptr->c_str(), ptr->size());
+			} else {
+				outObject.{{$property.Meta.CppMemberName}}
+					{{- if IsOptionalPtr $.Optional -}}
+						.reset(new {{$property.Meta.CppValueType}}(ptr->c_str(), ptr->size()));
+					{{- else -}}
+						.emplace(ptr->c_str(), ptr->size());
+					{{- end}}
+			}
+			{{- else}}
+			outObject.{{$property.Meta.CppMemberName}}
 				{{- if $property.Meta.Optional}}
 					{{- if IsOptionalPtr $.Optional -}}
-						.reset(new std::string(ptr->c_str(), ptr->size()));
+						.reset(new {{$property.Meta.CppValueType}}(ptr->c_str(), ptr->size()));
 					{{- else -}}
 						.emplace(ptr->c_str(), ptr->size());
 					{{- end}}
 				{{- else -}}
 					.assign(ptr->c_str(), ptr->size());
 				{{- end}}
+			{{- end}}
 		} else {
-			outObject.{{$property.Meta.CppName}}
+			outObject.{{$property.Meta.CppMemberName}}
 			{{- if $property.Meta.Optional -}}
 				.reset();
 			{{- else -}}
@@ -116,33 +242,93 @@ void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo:
 		auto* ptr = table->GetPointer<const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>*>({{$property.FbvTableOffset}});
 		if (ptr) {
 			{{- if $property.Meta.Optional}}
-			outObject.{{$property.Meta.CppName}}{{if IsOptionalPtr $property.Meta.Optional}}.reset(new {{$property.Meta.CppType}}({{else}} = {{$property.Meta.CppType}}(){{end}}{{template "field-value-assign-post" $property.Meta}};
+			{{if and $.ReuseBuffers (IsOptionalReusable $property.Meta.Optional)}}if (!outObject.{{$property.Meta.CppMemberName}}) {{end -}}
+			outObject.{{$property.Meta.CppMemberName}}{{if IsOptionalPtr $property.Meta.Optional}}.reset(new {{$property.Meta.CppValueType}}({{else}} = {{$property.Meta.CppValueType}}(){{end}}{{template "field-value-assign-post" $property.Meta}};
 			{{- end}}
-			outObject.{{$property.Meta.CppName}}{{$property.Meta.CppValOp}}reserve(ptr->size());
+			{{- if $.ReuseBuffers}}
+			auto& strings = {{if $property.Meta.Optional}}*{{end}}outObject.{{$property.Meta.CppMemberName}};
+			strings.resize(ptr->size());  // keeps the existing strings, reusing their buffers
+			size_t count = 0;
 			for (flatbuffers::uoffset_t i = 0; i < ptr->size(); i++) {
 				auto* itemPtr = ptr->Get(i);
-				if (itemPtr) outObject.{{$property.Meta.CppName}}{{$property.Meta.CppValOp}}emplace_back(itemPtr->c_str());
+				if (itemPtr) strings[count++].assign(itemPtr->c_str(), itemPtr->size());
 			}
+			strings.resize(count);
+			{{- else}}
+			outObject.{{$property.Meta.CppMemberName}}{{$property.Meta.CppValOp}}reserve(ptr->size());
+			for (flatbuffers::uoffset_t i = 0; i < ptr->size(); i++) {
+				auto* itemPtr = ptr->Get(i);
+				if (itemPtr) outObject.{{$property.Meta.CppMemberName}}{{$property.Meta.CppValOp}}emplace_back(itemPtr->c_str());
+			}
+			{{- end}}
 		} else {
-			outObject.{{$property.Meta.CppName}}
+			outObject.{{$property.Meta.CppMemberName}}
 			{{- if $property.Meta.Optional -}}
 				.reset();
 			{{- else -}}
 				.clear();
 			{{- end}}
 		}
+	}
+		{{- else if $property.Meta.CppFloat16}}
+	{
+		auto* ptr = table->GetPointer<const {{$property.Meta.FbOffsetType}}*>({{$property.FbvTableOffset}});
+		if (ptr) {
+			{{- if $property.ArrayLength}}
+			if (ptr->size() != {{$property.ArrayLength}}) {
+				throw std::out_of_range("{{$entity.Name}}.{{$property.Name}}: expected {{$property.ArrayLength}} elements, got " + std::to_string(ptr->size()));
+			}
+			{{- else}}
+			outObject.{{$property.Meta.CppMemberName}}.resize(ptr->size());
+			{{- end}}
+			std::transform(ptr->begin(), ptr->end(), outObject.{{$property.Meta.CppMemberName}}.begin(), float16ToFloat);
+		} else {
+			outObject.{{$property.Meta.CppMemberName}}.{{if $property.ArrayLength}}fill(0){{else}}clear(){{end}};
+		}
+	}
+		{{- else if $property.ArrayLength}}
+	{
+		auto* ptr = table->GetPointer<const {{$property.Meta.FbOffsetType}}*>({{$property.FbvTableOffset}});
+		if (ptr) {
+			if (ptr->size() != {{$property.ArrayLength}}) {
+				throw std::out_of_range("{{$entity.Name}}.{{$property.Name}}: expected {{$property.ArrayLength}} elements, got " + std::to_string(ptr->size()));
+			}
+			{{- if $property.Meta.Optional}}
+			{{if and $.ReuseBuffers (IsOptionalReusable $property.Meta.Optional)}}if (!outObject.{{$property.Meta.CppMemberName}}) {{end -}}
+			outObject.{{$property.Meta.CppMemberName}}{{if IsOptionalPtr $property.Meta.Optional}}.reset(new {{$property.Meta.CppType}}()){{else}} = {{$property.Meta.CppType}}(){{end}};
+			{{- end}}
+			std::copy(ptr->begin(), ptr->end(), outObject.{{$property.Meta.CppMemberName}}{{$property.Meta.CppValOp}}begin());
+		} else {
+			outObject.{{$property.Meta.CppMemberName}}
+			{{- if $property.Meta.Optional -}}
+				.reset();
+			{{- else -}}
+				.fill(0);
+			{{- end}}
+		}
 	}
 		{{- else if $property.Meta.FbIsVector}}
 	{
 		auto* ptr = table->GetPointer<const {{$property.Meta.FbOffsetType}}*>({{$property.FbvTableOffset}});
 		if (ptr) { 
-			outObject.{{$property.Meta.CppName}}
+			{{- if and $.ReuseBuffers (IsOptionalReusable $property.Meta.Optional)}}
+			if (outObject.{{$property.Meta.CppMemberName}}) {
+				outObject.{{$property.Meta.CppMemberName}}->assign(ptr->begin(), ptr->end());
+			} else {
+				outObject.{{$property.Meta.CppMemberName}}
+				{{- if IsOptionalPtr $property.Meta.Optional}}{{template "field-value-assign-pre" $property.Meta}}ptr->begin(), ptr->end(){{template "field-value-assign-post" $property.Meta}}
+				{{- else}} = {{$property.Meta.CppValueType}}(ptr->begin(), ptr->end())
+				{{- end}};
+			}
+			{{- else}}
+			outObject.{{$property.Meta.CppMemberName}}
 			{{- if IsOptionalPtr $property.Meta.Optional}}{{template "field-value-assign-pre" $property.Meta}}ptr->begin(), ptr->end(){{template "field-value-assign-post" $property.Meta}}
-			{{- else if $property.Meta.Optional}} = {{$property.Meta.CppType}}(ptr->begin(), ptr->end())
+			{{- else if $property.Meta.Optional}} = {{$property.Meta.CppValueType}}(ptr->begin(), ptr->end())
 			{{- else}}.assign(ptr->begin(), ptr->end())
 			{{- end}};
+			{{- end}}
 		} else {
-			outObject.{{$property.Meta.CppName}}
+			outObject.{{$property.Meta.CppMemberName}}
 			{{- if $property.Meta.Optional -}}
 				.reset();
 			{{- else -}}
@@ -153,13 +339,51 @@ void {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo:
 		{{- else }}
 	{{			if $property.Meta.Optional -}}
 	if (table->CheckField({{$property.FbvTableOffset}})) {{end -}} 
-	outObject.{{$property.Meta.CppName}}
+	outObject.{{$property.Meta.CppMemberName}}
 			{{- template "field-value-assign-pre" $property.Meta -}}
+		{{- if $property.Enum}}static_cast<{{$property.Meta.CppValueType}}>({{end -}}
 		table->GetField<{{$property.Meta.CppFbType}}>({{- $property.FbvTableOffset}}, {{$property.Meta.FbDefaultValue}}){{if eq "bool" $property.Meta.CppType}} != 0{{end}}
+		{{- if $property.Enum}}){{end}}
 			{{- template "field-value-assign-post" $property.Meta}};
-			{{- if $property.Meta.Optional}} else outObject.{{$property.Meta.CppName}}.reset();{{- end}}
+			{{- if $property.Meta.Optional}} else outObject.{{$property.Meta.CppMemberName}}.reset();{{- end}}
 		{{- end }}
 	{{- end}}
 }
+{{- if $.VerifyFlatBuffers}}
+
+bool {{$entity.Meta.CppNamespacePrefix}}{{$entity.Meta.CppName}}::_OBX_MetaInfo::verifyFlatBuffer(const void* data, size_t size) {
+	flatbuffers::Verifier verifier(static_cast<const uint8_t*>(data), size);
+	if (!verifier.VerifyOffset(0)) return false;
+	const auto* table = flatbuffers::GetRoot<flatbuffers::Table>(data);
+	if (!table->VerifyTableStart(verifier)) return false;
+	{{- range $property := $entity.Properties}}
+		{{- if eq "std::string" $property.Meta.CppType}}
+	{
+		if (!table->VerifyOffset(verifier, {{$property.FbvTableOffset}})) return false;
+		auto* ptr = table->GetPointer<const flatbuffers::String*>({{$property.FbvTableOffset}});
+		if (!verifier.VerifyString(ptr) || (ptr && !isValidUtf8(ptr->c_str(), ptr->size()))) return false;
+	}
+		{{- else if eq "std::vector<std::string>" $property.Meta.CppType}}
+	{
+		if (!table->VerifyOffset(verifier, {{$property.FbvTableOffset}})) return false;
+		auto* ptr = table->GetPointer<const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>*>({{$property.FbvTableOffset}});
+		if (!verifier.VerifyVector(ptr) || !verifier.VerifyVectorOfStrings(ptr)) return false;
+		for (flatbuffers::uoffset_t i = 0; ptr && i < ptr->size(); i++) {
+			auto* itemPtr = ptr->Get(i);
+			if (itemPtr && !isValidUtf8(itemPtr->c_str(), itemPtr->size())) return false;
+		}
+	}
+		{{- else if $property.Meta.FbIsVector}}
+	if (!table->VerifyOffset(verifier, {{$property.FbvTableOffset}}) ||
+		!verifier.VerifyVector(table->GetPointer<const {{$property.Meta.FbOffsetType}}*>({{$property.FbvTableOffset}}))) {
+		return false;
+	}
+		{{- else}}
+	if (!table->VerifyField<{{$property.Meta.CppFbType}}>(verifier, {{$property.FbvTableOffset}}, sizeof({{$property.Meta.CppFbType}}))) return false;
+		{{- end}}
+	{{- end}}
+	return verifier.EndTable();
+}
+{{- end}}
 {{end}}
-`))
+{{block "file-footer" .}}{{end}}`))
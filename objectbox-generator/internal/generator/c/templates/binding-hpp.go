@@ -27,35 +27,109 @@ import (
 // CppBindingTemplateHeader is used to generated the binding code
 var CppBindingTemplateHeader = template.Must(template.New("binding-hpp").Funcs(funcMap).Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
 #pragma once
 
+{{- if HasArrays .Entities}}
+#include <array>
+{{- end}}
 #include <cstdbool>
 #include <cstdint>
+{{- if HasPmr .Entities}}
+#include <memory_resource>
+#include <string>
+#include <vector>
+{{- end}}
+{{- if HasAccessors .Entities}}
+#include <utility>
+{{- end}}
 {{- if eq "std::optional" .Optional}} 
 #include <optional>
 {{- else if .Optional}}
 #include <memory>
 {{end}}
 
+{{- if .JsonHelpers}}
+#include <nlohmann/json.hpp>
+{{- end}}
 #include "flatbuffers/flatbuffers.h"
 #include "objectbox.h"
 #include "objectbox.hpp"
-{{range $entity := .Model.EntitiesWithMeta}}
+{{- if .ThreadSafety}}
+
+// Clang thread safety analysis attributes (checked with -Wthread-safety); no-ops for other compilers
+#ifndef OBX_THREAD_ANNOTATION
+#if defined(__clang__)
+#define OBX_THREAD_ANNOTATION(x) __attribute__((x))
+#else
+#define OBX_THREAD_ANNOTATION(x)
+#endif
+#endif
+{{- end}}
+{{range $enum := .Enums}}
+#ifndef {{$enum.Guard}}
+#define {{$enum.Guard}}
+{{with $enum.CppNamespaceStart}}{{.}}
+{{end}}
+{{- PrintComments 0 $enum.Comments}}enum class {{$enum.CppName}} : {{$enum.CppType}} {
+	{{- range $value := $enum.Values}}
+	{{PrintComments 1 $value.Comments}}{{$value.Name}} = {{$value.Value}},
+	{{- end}}
+};
+{{with $enum.CppNamespaceEnd}}{{.}}{{end -}}
+#endif
+{{end -}}
+{{range $group := .Constants}}
+#ifndef {{$group.Guard}}
+#define {{$group.Guard}}
+{{with $group.CppNamespaceStart}}{{.}}
+{{end}}
+{{- PrintComments 0 $group.Comments}}namespace {{$group.CppName}} {
+{{- range $constant := $group.Constants}}
+{{PrintComments 0 $constant.Comments}}constexpr {{$constant.CppType}} {{$constant.CppName}} = {{$constant.Literal}};
+{{- end}}
+}  // namespace {{$group.CppName}}
+{{with $group.CppNamespaceEnd}}{{.}}{{end -}}
+#endif
+{{end -}}
+{{range $entity := .Entities}}
 {{$entity.Meta.PreDeclareCppRelTargets -}}
 {{with $entity.Meta.CppNamespaceStart}}
 {{.}}{{end}}
 struct {{$entity.Meta.CppName}}_;
 
 {{PrintComments 0 $entity.Comments}}struct {{$entity.Meta.CppName}} {
+	{{- if $entity.Meta.Accessors}}
+	{{- range $property := $entity.Properties}}
+	{{- if or $property.Meta.Optional $property.Meta.FbIsVector}}
+	{{PrintComments 1 $property.Comments}}const {{$property.Meta.CppTypeWithOptional}}& {{$property.Meta.CppGetter}}() const { return {{$property.Meta.CppMemberName}}; }
+	void {{$property.Meta.CppSetter}}({{$property.Meta.CppTypeWithOptional}} value) { {{$property.Meta.CppMemberName}} = std::move(value); }
+	{{- else}}
+	{{PrintComments 1 $property.Comments}}{{$property.Meta.CppValueType}} {{$property.Meta.CppGetter}}() const { return {{$property.Meta.CppMemberName}}; }
+	void {{$property.Meta.CppSetter}}({{$property.Meta.CppValueType}} value) { {{$property.Meta.CppMemberName}} = value; }
+	{{- end}}
+	{{- end}}
+	{{- else}}
 	{{- range $property := $entity.Properties}}
 	{{PrintComments 1 $property.Comments}}{{$property.Meta.CppTypeWithOptional}} {{$property.Meta.CppName}};
 	{{- end}}
+	{{- end}}
+	{{- if $.ExtensionHooks}}
+
+	// Extension hook: "{{$entity.Name}}.custom.hpp", if it exists next to this file, is included here, inside the struct,
+	// e.g. to add methods without editing this generated file.
+#if defined __has_include
+#if __has_include("{{$entity.Name}}.custom.hpp")
+#include "{{$entity.Name}}.custom.hpp"
+#endif
+#endif
+	{{- end}}
 
     struct _OBX_MetaInfo {
 		static constexpr obx_schema_id entityId() { return {{$entity.Id.GetId}}; }
 	
-		static void setObjectId({{$entity.Meta.CppName}}& object, obx_id newId) { object.{{$entity.IdProperty.Meta.CppName}} = newId; }
+		static void setObjectId({{$entity.Meta.CppName}}& object, obx_id newId) { object.{{$entity.IdProperty.Meta.CppMemberName}} = newId; }
 	
 		/// Write given object to the FlatBufferBuilder
 		static void toFlatBuffer(flatbuffers::FlatBufferBuilder& fbb, const {{$entity.Meta.CppName}}& object);
@@ -68,20 +142,146 @@ struct {{$entity.Meta.CppName}}_;
 	
 		/// Read an object from a valid FlatBuffer
 		static void fromFlatBuffer(const void* data, size_t size, {{$entity.Meta.CppName}}& outObject);
+		{{- if $.VerifyFlatBuffers}}
+	
+		/// Check the FlatBuffer is well-formed, i.e. all fields are in bounds and strings are valid UTF-8, e.g. when
+		/// reading untrusted input; the fromFlatBuffer() functions call it, throwing std::invalid_argument if it isn't
+		static bool verifyFlatBuffer(const void* data, size_t size);
+		{{- end}}
 	};
+	{{- if $entity.Meta.Accessors}}
+
+private:
+	{{- range $property := $entity.Properties}}
+	{{$property.Meta.CppTypeWithOptional}} {{$property.Meta.CppMemberName}};
+	{{- end}}
+	{{- end}}
 };
 
 struct {{$entity.Meta.CppName}}_ {
+{{- if $.ThreadSafety}}
+	/// Capability representing an open transaction, held by ReadTx & WriteTx and required by the query helpers below.
+	/// Only used by clang's thread safety analysis, it's not defined.
+	struct OBX_THREAD_ANNOTATION(capability("transaction")) TxCapability {};
+	static TxCapability txCapability;
+
+	/// RAII read transaction, e.g. ` + "`" + `{{$entity.Meta.CppName}}_::ReadTx tx(store);` + "`" + ` before calling the query helpers
+	class OBX_THREAD_ANNOTATION(scoped_lockable) ReadTx {
+		obx::Transaction tx_;
+
+	public:
+		explicit ReadTx(obx::Store& store) OBX_THREAD_ANNOTATION(acquire_shared_capability(txCapability)) : tx_(store.txRead()) {}
+		~ReadTx() OBX_THREAD_ANNOTATION(release_capability()) {}
+	};
+
+	/// RAII write transaction, committed by success(); otherwise its changes are rolled back when it goes out of scope
+	class OBX_THREAD_ANNOTATION(scoped_lockable) WriteTx {
+		obx::Transaction tx_;
+
+	public:
+		explicit WriteTx(obx::Store& store) OBX_THREAD_ANNOTATION(acquire_capability(txCapability)) : tx_(store.txWrite()) {}
+		~WriteTx() OBX_THREAD_ANNOTATION(release_capability()) {}
+
+		/// Commits the transaction when it goes out of scope
+		void success() { tx_.success(); }
+	};
+{{end}}
 {{- range $property := $entity.Properties}}
-	static const 
+	{{PrintComments 1 $property.Comments}}static const 
 	{{- if $property.RelationTarget}} obx::RelationProperty<{{$entity.Meta.CppName}}, {{$property.Meta.CppNameRelationTarget}}>
 	{{- else}} obx::Property<{{$entity.Meta.CppName}}, OBXPropertyType_{{PropTypeName $property.Type}}>
 	{{- end}} {{$property.Meta.CppName}};
 {{- end}}
 {{- range $relation := $entity.Relations}}
-	static const obx::RelationStandalone<{{$entity.Meta.CppName}}, {{$relation.Target.Meta.CppName}}> {{$relation.Meta.CppName}};
+	static const obx::RelationStandalone<{{$entity.Meta.CppName}}, {{$relation.Meta.CppNameRelationTarget}}> {{$relation.Meta.CppName}};
+{{- end}}
+{{- range $property := $entity.Properties}}
+{{- if $property.Enum}}
+
+	/// Query condition matching the given {{$property.Meta.CppName}}, e.g. ` + "`" + `box.query({{$entity.Meta.CppName}}_::{{$property.Meta.CppName}}Equals(value))` + "`" + `
+	static auto {{$property.Meta.CppName}}Equals({{$property.Meta.CppValueType}} value) -> decltype({{$property.Meta.CppName}}.equals(0)) {
+		return {{$property.Meta.CppName}}.equals({{$property.Meta.CppQueryValue "value"}});
+	}
+
+	/// Query condition matching any {{$property.Meta.CppName}} but the given one
+	static auto {{$property.Meta.CppName}}NotEquals({{$property.Meta.CppValueType}} value) -> decltype({{$property.Meta.CppName}}.notEquals(0)) {
+		return {{$property.Meta.CppName}}.notEquals({{$property.Meta.CppQueryValue "value"}});
+	}
+{{- end}}
+{{- with $property.Meta.CppQueryFinder}}
+
+	/// Finds {{if $property.Meta.CppQueryUnique}}the object{{else}}all objects{{end}} with the given {{$property.Meta.CppName}}
+	{{- if $property.Meta.IsExternalId}}, a unique external ID{{end}}
+	{{- if eq (PropTypeName $property.Type) "String"}} (case-sensitive){{end}}, using the property index
+	static {{if $property.Meta.CppQueryUnique}}std::unique_ptr<{{$entity.Meta.CppName}}>{{else}}std::vector<{{$entity.Meta.CppName}}>{{end}} {{.}}(obx::Box<{{$entity.Meta.CppName}}>& box, {{$property.Meta.CppQueryValueType}} value)
+	{{- if $.ThreadSafety}} OBX_THREAD_ANNOTATION(requires_shared_capability(txCapability)){{end}} {
+		return box.query({{$property.Meta.CppName}}.equals({{$property.Meta.CppQueryValue "value"}})).build().{{if $property.Meta.CppQueryUnique}}findUnique{{else}}find{{end}}();
+	}
+{{- end}}
+{{- end}}
+{{- if $.ReuseBuffers}}
+
+	/// Reads the objects with the given IDs into outObjects, reusing the objects (and their string and vector buffers)
+	/// from previous calls, e.g. in a read loop: ` + "`" + `{{$entity.Meta.CppName}}_::getInto(box, ids, objects)` + "`" + `. Missing IDs are skipped.
+	/// @returns the number of objects read, i.e. the new size of outObjects
+	static size_t getInto(obx::Box<{{$entity.Meta.CppName}}>& box, const std::vector<obx_id>& ids, std::vector<{{$entity.Meta.CppName}}>& outObjects)
+	{{- if $.ThreadSafety}} OBX_THREAD_ANNOTATION(requires_shared_capability(txCapability)){{end}} {
+		if (outObjects.size() < ids.size()) outObjects.resize(ids.size());
+		size_t count = 0;
+		for (obx_id id : ids) {
+			if (box.get(id, outObjects[count])) count++;
+		}
+		outObjects.resize(count);
+		return count;
+	}
+{{- end}}
+{{- range $backlink := $entity.Meta.CppBacklinks}}
+
+	/// Finds the {{$backlink.Source.Name}} objects pointing to the {{$entity.Meta.CppName}} with the given ID using the to-one relation
+	/// {{$backlink.Source.Name}}::{{$backlink.Property.Name}}, i.e. the "{{$backlink.Name}}" backlink, e.g. ` + "`" + `{{$entity.Meta.CppName}}_::{{$backlink.CppName}}(box, id)` + "`" + `.
+	/// It's a template so that {{$backlink.Source.Name}} only needs to be complete where it's called.
+	template <typename SourceT = {{$backlink.CppSource}}>
+	static std::vector<SourceT> {{$backlink.CppName}}(obx::Box<SourceT>& box, obx_id id)
+	{{- if $.ThreadSafety}} OBX_THREAD_ANNOTATION(requires_shared_capability(txCapability)){{end}} {
+		return box.query(obx::RelationProperty<SourceT, {{$entity.Meta.CppName}}>({{$backlink.Property.Id.GetId}}).equals(id)).build().find();
+	}
 {{- end}}
 };
+{{- if $.JsonHelpers}}
+
+/// Serializes {{$entity.Meta.CppName}} to JSON, e.g. ` + "`" + `nlohmann::json json = object;` + "`" + `; unset optional values are written as null
+inline void to_json(nlohmann::json& json, const {{$entity.Meta.CppName}}& object) {
+	json = nlohmann::json::object();
+	{{- range $property := $entity.Properties}}
+	{{- if $property.Meta.Optional}}
+	if ({{$property.Meta.CppObjectValue}}) {
+		json["{{$property.Name}}"] = *{{$property.Meta.CppObjectValue}};
+	} else {
+		json["{{$property.Name}}"] = nullptr;
+	}
+	{{- else}}
+	json["{{$property.Name}}"] = {{$property.Meta.CppObjectValue}};
+	{{- end}}
+	{{- end}}
+}
+
+/// Deserializes {{$entity.Meta.CppName}} from JSON, e.g. ` + "`" + `auto object = json.get<{{$entity.Meta.CppName}}>();` + "`" + `; properties missing in the JSON are left unchanged
+inline void from_json(const nlohmann::json& json, {{$entity.Meta.CppName}}& object) {
+	{{- range $property := $entity.Properties}}
+	if (json.contains("{{$property.Name}}")) {
+		{{- if $property.Meta.Optional}}
+		if (json.at("{{$property.Name}}").is_null()) {
+			{{$property.Meta.CppObjectAssign (print $property.Meta.CppTypeWithOptional "()")}}
+		} else {
+			{{$property.Meta.CppObjectAssign ($property.Meta.CppOptionalOf (print "json.at(\"" $property.Name "\").get<" $property.Meta.CppValueType ">()"))}}
+		}
+		{{- else}}
+		{{$property.Meta.CppObjectAssign (print "json.at(\"" $property.Name "\").get<" $property.Meta.CppValueType ">()")}}
+		{{- end}}
+	}
+	{{- end}}
+}
+{{- end}}
 {{with $entity.Meta.CppNamespaceEnd}}{{.}}{{end -}}
 {{end}}
-`))
+{{block "file-footer" .}}{{end}}`))
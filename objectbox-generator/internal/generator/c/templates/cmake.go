@@ -0,0 +1,100 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// CMakeTemplate is used to generate objectbox-generated.cmake, listing the generated files for CMake projects
+var CMakeTemplate = template.Must(template.New("cmake").Funcs(funcMap).Parse(
+	`# Code generated by ObjectBox; DO NOT EDIT.
+# ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+#
+# Lists the files generated from the ObjectBox schema. Include it in CMakeLists.txt and add them to a target:
+#
+#   include(path/to/objectbox-generated.cmake)
+#   add_objectbox_schema(myapp)
+#
+# With REGENERATE, i.e. add_objectbox_schema(myapp REGENERATE), the files are generated again when a schema changes,
+# running objectbox-generator with the flags used for this file; the executable is ObjectBoxGenerator_EXECUTABLE
+# (e.g. set by find_package(ObjectBoxGenerator)) or looked up in PATH.
+
+set(OBJECTBOX_GENERATED_DIR "${CMAKE_CURRENT_LIST_DIR}")
+
+set(OBJECTBOX_SCHEMA_FILES
+{{- range .Schemas}}
+    "${OBJECTBOX_GENERATED_DIR}/{{.}}"
+{{- end}}
+)
+
+set(OBJECTBOX_GENERATED_SOURCES
+{{- range .Sources}}
+    "${OBJECTBOX_GENERATED_DIR}/{{.}}"
+{{- end}}
+)
+
+set(OBJECTBOX_GENERATED_HEADERS
+{{- range .Headers}}
+    "${OBJECTBOX_GENERATED_DIR}/{{.}}"
+{{- end}}
+)
+{{- if .Benchmarks}}
+
+# google-benchmark sources, to be built as separate executables
+set(OBJECTBOX_GENERATED_BENCHMARK_SOURCES
+{{- range .Benchmarks}}
+    "${OBJECTBOX_GENERATED_DIR}/{{.}}"
+{{- end}}
+)
+{{- end}}
+
+set(OBJECTBOX_GENERATED_INCLUDE_DIRS
+{{- range .IncludeDirs}}
+    "${OBJECTBOX_GENERATED_DIR}{{if ne . "."}}/{{.}}{{end}}"
+{{- end}}
+)
+
+# the arguments objectbox-generator was called with, relative to OBJECTBOX_GENERATED_DIR
+set(OBJECTBOX_GENERATOR_ARGS {{range $i, $arg := .Args}}{{if $i}} {{end}}{{$arg}}{{end}})
+
+function(add_objectbox_schema target)
+    cmake_parse_arguments(ARG "REGENERATE" "" "" ${ARGN})
+    if (ARG_REGENERATE)
+        if (NOT ObjectBoxGenerator_EXECUTABLE)
+            find_program(ObjectBoxGenerator_EXECUTABLE objectbox-generator)
+            if (NOT ObjectBoxGenerator_EXECUTABLE)
+                message(FATAL_ERROR "objectbox-generator not found, set ObjectBoxGenerator_EXECUTABLE")
+            endif ()
+        endif ()
+        add_custom_command(
+                OUTPUT ${OBJECTBOX_GENERATED_SOURCES} ${OBJECTBOX_GENERATED_HEADERS}
+                COMMAND ${ObjectBoxGenerator_EXECUTABLE} ${OBJECTBOX_GENERATOR_ARGS}
+                DEPENDS ${OBJECTBOX_SCHEMA_FILES}
+                WORKING_DIRECTORY "${OBJECTBOX_GENERATED_DIR}"
+                COMMENT "Generating ObjectBox bindings"
+                VERBATIM
+        )
+    endif ()
+    target_sources(${target} PRIVATE ${OBJECTBOX_GENERATED_SOURCES} ${OBJECTBOX_GENERATED_HEADERS})
+    target_include_directories(${target} PRIVATE ${OBJECTBOX_GENERATED_INCLUDE_DIRS})
+endfunction()
+`))
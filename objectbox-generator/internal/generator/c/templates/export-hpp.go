@@ -0,0 +1,297 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// CppExportTemplate is used to generate functions exporting entity objects to JSON and CSV and importing them back
+var CppExportTemplate = template.Must(template.New("export-hpp").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Export and import of the entities as JSON (export<Entity>JSON, import<Entity>JSON) and CSV (export<Entity>CSV,
+// import<Entity>CSV), e.g. for backups and migrations. Requires nlohmann::json (https://github.com/nlohmann/json).
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+#pragma once
+
+#include <cstdint>
+#include <istream>
+#include <ostream>
+#include <set>
+#include <stdexcept>
+#include <string>
+#include <vector>
+
+#include <nlohmann/json.hpp>
+
+#include "{{.HeaderFile}}"
+
+#ifndef OBX_EXPORT_HELPERS
+#define OBX_EXPORT_HELPERS
+/// Helpers of the generated export and import functions, shared by all .obx.export.hpp files.
+/// The functions templates rely on toExportRecord() and fromExportRecord() overloads generated for each entity.
+namespace obx_export {
+
+inline bool has(const nlohmann::json& record, const char* key) {
+	auto it = record.find(key);
+	return it != record.end() && !it->is_null();
+}
+
+inline std::string base64Encode(const uint8_t* data, size_t size) {
+	static const char* chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/";
+	std::string result;
+	result.reserve((size + 2) / 3 * 4);
+	for (size_t i = 0; i < size; i += 3) {
+		uint32_t chunk = static_cast<uint32_t>(data[i]) << 16;
+		if (i + 1 < size) chunk |= static_cast<uint32_t>(data[i + 1]) << 8;
+		if (i + 2 < size) chunk |= static_cast<uint32_t>(data[i + 2]);
+		result.push_back(chars[(chunk >> 18) & 63]);
+		result.push_back(chars[(chunk >> 12) & 63]);
+		result.push_back(i + 1 < size ? chars[(chunk >> 6) & 63] : '=');
+		result.push_back(i + 2 < size ? chars[chunk & 63] : '=');
+	}
+	return result;
+}
+
+inline std::vector<uint8_t> base64Decode(const std::string& text) {
+	std::vector<uint8_t> result;
+	result.reserve(text.size() / 4 * 3);
+	uint32_t chunk = 0;
+	int bits = 0;
+	for (char c : text) {
+		uint32_t value;
+		if (c >= 'A' && c <= 'Z') value = static_cast<uint32_t>(c - 'A');
+		else if (c >= 'a' && c <= 'z') value = static_cast<uint32_t>(c - 'a' + 26);
+		else if (c >= '0' && c <= '9') value = static_cast<uint32_t>(c - '0' + 52);
+		else if (c == '+') value = 62;
+		else if (c == '/') value = 63;
+		else if (c == '=') break;
+		else throw std::invalid_argument("invalid base64 value: " + text);
+		chunk = (chunk << 6) | value;
+		bits += 6;
+		if (bits >= 8) {
+			bits -= 8;
+			result.push_back(static_cast<uint8_t>(chunk >> bits));
+		}
+	}
+	return result;
+}
+
+inline nlohmann::json stringValue(const flatbuffers::String* ptr, bool optional) {
+	if (ptr) return ptr->str();
+	return optional ? nlohmann::json() : nlohmann::json("");
+}
+
+inline nlohmann::json stringsValue(const flatbuffers::Vector<flatbuffers::Offset<flatbuffers::String>>* ptr) {
+	if (!ptr) return nlohmann::json();
+	nlohmann::json result = nlohmann::json::array();
+	for (flatbuffers::uoffset_t i = 0; i < ptr->size(); i++) {
+		result.push_back(ptr->Get(i)->str());
+	}
+	return result;
+}
+
+template <typename T>
+nlohmann::json vectorValue(const flatbuffers::Vector<T>* ptr) {
+	if (!ptr) return nlohmann::json();
+	return std::vector<T>(ptr->begin(), ptr->end());
+}
+
+template <typename T>
+nlohmann::json bytesValue(const flatbuffers::Vector<T>* ptr) {
+	if (!ptr) return nlohmann::json();
+	return base64Encode(reinterpret_cast<const uint8_t*>(ptr->data()), ptr->size());
+}
+
+template <typename T>
+void writeJSON(std::ostream& out, const std::vector<T>& objects) {
+	nlohmann::json records = nlohmann::json::array();
+	for (const T& object : objects) {
+		records.push_back(toExportRecord(object));
+	}
+	out << records.dump(2) << "\n";
+}
+
+template <typename T>
+std::vector<T> readJSON(std::istream& in) {
+	nlohmann::json records = nlohmann::json::parse(in);
+	std::vector<T> objects;
+	objects.reserve(records.size());
+	for (const nlohmann::json& record : records) {
+		objects.emplace_back();
+		fromExportRecord(record, objects.back());
+	}
+	return objects;
+}
+
+inline void writeCSVRow(std::ostream& out, const std::vector<std::string>& cells) {
+	for (size_t i = 0; i < cells.size(); i++) {
+		if (i > 0) out << ',';
+		if (cells[i].find_first_of(",\"\r\n") == std::string::npos) {
+			out << cells[i];
+			continue;
+		}
+		out << '"';
+		for (char c : cells[i]) {
+			if (c == '"') out << '"';
+			out << c;
+		}
+		out << '"';
+	}
+	out << '\n';
+}
+
+/// Reads a row of RFC 4180 CSV, returns false at the end of the input
+inline bool readCSVRow(std::istream& in, std::vector<std::string>& cells) {
+	cells.clear();
+	if (in.peek() == std::istream::traits_type::eof()) return false;
+	std::string cell;
+	bool quoted = false;
+	char c;
+	while (in.get(c)) {
+		if (quoted) {
+			if (c != '"') {
+				cell.push_back(c);
+			} else if (in.peek() == '"') {
+				cell.push_back(static_cast<char>(in.get()));
+			} else {
+				quoted = false;
+			}
+		} else if (c == '"') {
+			quoted = true;
+		} else if (c == ',') {
+			cells.push_back(std::move(cell));
+			cell.clear();
+		} else if (c == '\n') {
+			break;
+		} else if (c != '\r') {
+			cell.push_back(c);
+		}
+	}
+	cells.push_back(std::move(cell));
+	return true;
+}
+
+template <typename T>
+void writeCSV(std::ostream& out, const std::vector<T>& objects, const std::vector<std::string>& columns) {
+	writeCSVRow(out, columns);
+	std::vector<std::string> row;
+	for (const T& object : objects) {
+		nlohmann::json record = toExportRecord(object);
+		row.clear();
+		for (const std::string& column : columns) {
+			const nlohmann::json& value = record.at(column);
+			row.push_back(value.is_null() ? std::string() : value.is_string() ? value.get<std::string>() : value.dump());
+		}
+		writeCSVRow(out, row);
+	}
+}
+
+/// Reads objects from CSV with a header row; empty cells are left out of the record, i.e. the properties stay empty.
+/// The cells of textColumns are taken as strings, all others are parsed as JSON values.
+template <typename T>
+std::vector<T> readCSV(std::istream& in, const std::set<std::string>& textColumns) {
+	std::vector<T> objects;
+	std::vector<std::string> header;
+	std::vector<std::string> row;
+	if (!readCSVRow(in, header)) return objects;
+	for (size_t line = 2; readCSVRow(in, row); line++) {
+		if (row.size() == 1 && row[0].empty()) continue;  // empty line
+		if (row.size() != header.size()) {
+			throw std::invalid_argument("CSV line " + std::to_string(line) + " has " + std::to_string(row.size()) +
+										" cells, expected " + std::to_string(header.size()));
+		}
+		nlohmann::json record = nlohmann::json::object();
+		for (size_t i = 0; i < header.size(); i++) {
+			if (row[i].empty()) continue;
+			record[header[i]] = textColumns.count(header[i]) ? nlohmann::json(row[i]) : nlohmann::json::parse(row[i]);
+		}
+		objects.emplace_back();
+		fromExportRecord(record, objects.back());
+	}
+	return objects;
+}
+
+}  // namespace obx_export
+#endif
+{{range $entity := .Entities}}
+{{- with $entity.Meta.CppNamespaceStart}}
+{{.}}{{end}}
+
+/// Returns the property values of the object as stored in the database (i.e. read from its FlatBuffers representation),
+/// keyed by the property names; byte vectors are base64-encoded and missing optional values and vectors are null.
+inline nlohmann::json toExportRecord(const {{$entity.Meta.CppName}}& object) {
+	flatbuffers::FlatBufferBuilder fbb;
+	{{$entity.Meta.CppName}}::_OBX_MetaInfo::toFlatBuffer(fbb, object);
+	const auto* table = flatbuffers::GetRoot<flatbuffers::Table>(fbb.GetBufferPointer());
+	nlohmann::json record = nlohmann::json::object();
+	{{- range $property := $entity.Properties}}
+	record["{{$property.Name}}"] = {{$property.Meta.CppExportRead}};
+	{{- end}}
+	return record;
+}
+
+/// Sets the object from a record returned by toExportRecord(), going through the FlatBuffers representation like when
+/// reading from the database; properties missing in the record (or null) are left empty.
+inline void fromExportRecord(const nlohmann::json& record, {{$entity.Meta.CppName}}& object) {
+	flatbuffers::FlatBufferBuilder fbb;
+	{{- range $property := $entity.Properties}}
+	{{- with $property.Meta.CppExportOffset}}
+	{{.}}
+	{{- end}}
+	{{- end}}
+	flatbuffers::uoffset_t fbStart = fbb.StartTable();
+	{{- range $property := $entity.Properties}}
+	{{$property.Meta.CppExportWrite}}
+	{{- end}}
+	flatbuffers::Offset<flatbuffers::Table> offset;
+	offset.o = fbb.EndTable(fbStart);
+	fbb.Finish(offset);
+	{{$entity.Meta.CppName}}::_OBX_MetaInfo::fromFlatBuffer(fbb.GetBufferPointer(), fbb.GetSize(), object);
+}
+
+/// Writes the objects to out as a JSON array of records (see toExportRecord()), e.g. for backups or migrations.
+inline void export{{$entity.Meta.CppName}}JSON(std::ostream& out, const std::vector<{{$entity.Meta.CppName}}>& objects) {
+	obx_export::writeJSON(out, objects);
+}
+
+/// Reads objects written by export{{$entity.Meta.CppName}}JSON(); they aren't put, e.g. use box.putMany() to store them, keeping their IDs.
+inline std::vector<{{$entity.Meta.CppName}}> import{{$entity.Meta.CppName}}JSON(std::istream& in) {
+	return obx_export::readJSON<{{$entity.Meta.CppName}}>(in);
+}
+
+/// Writes the objects to out as CSV with a header row of the property names, using the same values as
+/// export{{$entity.Meta.CppName}}JSON(); string and float vectors are written as JSON arrays and empty cells stand for null.
+inline void export{{$entity.Meta.CppName}}CSV(std::ostream& out, const std::vector<{{$entity.Meta.CppName}}>& objects) {
+	obx_export::writeCSV(out, objects, {
+	{{- range $i, $property := $entity.Properties}}{{if $i}}, {{end}}"{{$property.Name}}"{{end -}} });
+}
+
+/// Reads objects written by export{{$entity.Meta.CppName}}CSV(), matching the columns by the header row; properties without a
+/// column are left empty. See import{{$entity.Meta.CppName}}JSON() regarding the returned objects.
+inline std::vector<{{$entity.Meta.CppName}}> import{{$entity.Meta.CppName}}CSV(std::istream& in) {
+	return obx_export::readCSV<{{$entity.Meta.CppName}}>(in, {
+	{{- $first := true}}{{range $property := $entity.Properties}}{{if $property.Meta.CppExportText}}{{if not $first}}, {{end}}"{{$property.Name}}"{{$first = false}}{{end}}{{end -}} });
+}
+{{with $entity.Meta.CppNamespaceEnd}}{{.}}{{end -}}
+{{end}}
+{{- block "file-footer" .}}{{end}}`))
@@ -0,0 +1,75 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// CppFixturesTemplate is used to generate functions creating entity objects for tests
+var CppFixturesTemplate = template.Must(template.New("fixtures-hpp").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Test fixtures: functions creating objects with defaults (new<Entity>Fixture) or seeded random values (random<Entity>Fixture).
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+#pragma once
+
+#include <array>
+#include <cstdint>
+#include <random>
+#include <string>
+
+#include "{{.HeaderFile}}"
+{{range $entity := .Entities}}
+{{- with $entity.Meta.CppNamespaceStart}}
+{{.}}{{end}}
+
+/// Returns an object for tests with non-optional strings set to the property names and other properties left at their
+/// defaults; the ID is zero so the object is inserted as a new one on put.
+inline {{$entity.Meta.CppName}} new{{$entity.Meta.CppName}}Fixture() {
+	{{$entity.Meta.CppName}} object{};
+	{{- range $property := $entity.Properties}}
+	{{- with $property.Meta.CppFixtureDefault}}
+	{{$property.Meta.CppObjectAssign .}}
+	{{- end}}
+	{{- end}}
+	return object;
+}
+
+/// Returns an object for tests filled with pseudo-random values, the same ones for the same seed.
+/// The ID and relations are left unset, i.e. the object is inserted as a new one on put.
+inline {{$entity.Meta.CppName}} random{{$entity.Meta.CppName}}Fixture(uint64_t seed) {
+	std::mt19937_64 rng(seed);
+	{{$entity.Meta.CppName}} object = new{{$entity.Meta.CppName}}Fixture();
+	{{- range $property := $entity.Properties}}
+	{{- with $property.Meta.CppFixtureValue}}
+	{{- if $property.Meta.Optional}}
+	{{$property.Meta.CppObjectAssign ($property.Meta.CppOptionalOf .)}}
+	{{- else}}
+	{{$property.Meta.CppObjectAssign .}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	return object;
+}
+{{with $entity.Meta.CppNamespaceEnd}}{{.}}{{end -}}
+{{end}}
+{{- block "file-footer" .}}{{end}}`))
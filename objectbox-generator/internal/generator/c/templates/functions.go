@@ -43,6 +43,52 @@ func cccToUc(str string) string {
 }
 
 var funcMap = template.FuncMap{
+	"HasArrays": func(entities []*model.Entity) bool {
+		for _, entity := range entities {
+			for _, property := range entity.Properties {
+				if property.ArrayLength > 0 {
+					return true
+				}
+			}
+		}
+		return false
+	},
+	"HasStrings": func(entities []*model.Entity) bool {
+		for _, entity := range entities {
+			for _, property := range entity.Properties {
+				if property.Type == model.PropertyTypeString || property.Type == model.PropertyTypeStringVector {
+					return true
+				}
+			}
+		}
+		return false
+	},
+	"HasFloat16Vectors": func(entities []*model.Entity) bool {
+		for _, entity := range entities {
+			for _, property := range entity.Properties {
+				if property.StorageType == model.StorageTypeFloat16 {
+					return true
+				}
+			}
+		}
+		return false
+	},
+	"HasAccessors": func(entities []*model.Entity) bool {
+		for _, entity := range entities {
+			if meta, ok := entity.Meta.(interface{ Accessors() bool }); ok && meta.Accessors() {
+				return true
+			}
+		}
+		return false
+	},
+	"HasPmr": func(entities []*model.Entity) bool {
+		for _, entity := range entities {
+			if meta, ok := entity.Meta.(interface{ Pmr() bool }); ok && meta.Pmr() {
+				return true
+			}
+		}
+		return false
+	},
 	"PropTypeName": func(val model.PropertyType) string {
 		return model.PropertyTypeNames[val]
 	},
@@ -109,12 +155,21 @@ var funcMap = template.FuncMap{
 	"PrintComments": func(tabs int, comments []string) string {
 		var result string
 		for _, comment := range comments {
-			result += "/// " + comment + "\n" + strings.Repeat("\t", tabs)
+			result += strings.TrimRight("/// "+comment, " ") + "\n" + strings.Repeat("\t", tabs)
 		}
 		return result
 	},
 	"IsOptionalPtr": func(optional string) bool {
 		return optional == "std::unique_ptr" || optional == "std::shared_ptr"
 	},
+	// IsOptionalReusable tells whether the value of an optional member may be overwritten when reading an object;
+	// not for std::shared_ptr, which may be shared with other objects
+	"IsOptionalReusable": func(optional string) bool {
+		return optional == "std::optional" || optional == "std::unique_ptr"
+	},
 	"ToUpper": strings.ToUpper,
+	// SchemaMacroName converts "Entity.property" to a macro name suffix, i.e. "Entity_property"
+	"SchemaMacroName": func(name string) string {
+		return strings.Replace(name, ".", "_", -1)
+	},
 }
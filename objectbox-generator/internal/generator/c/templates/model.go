@@ -27,6 +27,7 @@ import (
 // ModelTemplate is used to generate the model initialization code
 var ModelTemplate = template.Must(template.New("model").Funcs(funcMap).Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
 #pragma once
 
@@ -120,8 +121,32 @@ static inline OBX_model* create_obx_model() {
 	{{- end}}
 	return model; // NOTE: the returned model will contain error information if an error occurred.
 }
+{{- if .Model.SchemaVersion}}
+
+/// Incremented by the generator whenever the model changes. Together with the OBX_SCHEMA_ADDED_IN_* versions of
+/// entities and their properties and relations, it lets the app run data backfills for objects stored by older app
+/// versions.
+#define OBX_SCHEMA_VERSION {{.Model.SchemaVersion}}
+{{- range $element := .Model.SchemaElementVersions}}
+#define OBX_SCHEMA_ADDED_IN_{{SchemaMacroName $element.Name}} {{$element.Version}}
+{{- end}}
+{{- end}}
+{{- with .ExternalMapping}}
+
+/// The external names and types of all entities and their properties and relations (JSON), e.g. to configure the
+/// ObjectBox Sync MongoDB connector directly from the generated code.
+static const char* const OBX_EXTERNAL_MAPPING_JSON =
+	{{.}};
+{{- end}}
+{{- with .CascadeDeletes}}
+
+/// The relations annotated with ` + "`cascade`" + ` (JSON): removing an object of "entity" should also remove the related
+/// objects of "target". It's up to the application (or a runtime library) to honor the rules.
+static const char* const OBX_CASCADE_DELETES_JSON =
+	{{.}};
+{{- end}}
 
 #ifdef __cplusplus
 }
 #endif
-`))
+{{block "file-footer" .}}{{end}}`))
@@ -0,0 +1,100 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+)
+
+// With Options.Checksums, the last line of each generated file records a hash of the content before it, e.g.:
+//
+//	// ObjectBox Generator checksum: 3f2a...
+//
+// or wrapped in an HTML comment for the docs. A file whose content doesn't match its checksum was edited manually.
+const checksumMarker = "ObjectBox Generator checksum:"
+
+var checksumRegexp = regexp.MustCompile(`(?m)^.*` + regexp.QuoteMeta(checksumMarker) + ` ([0-9a-f]+).*\n?\z`)
+
+// ManualEditError is returned when writing a generated file which has been edited manually since it was generated,
+// see Options.Checksums. Diff shows the changes overwriting it would make, i.e. also the local modifications.
+type ManualEditError struct {
+	Path string
+	Diff string
+}
+
+func (err ManualEditError) Error() string {
+	return fmt.Sprintf("%s has been edited manually (its content doesn't match the checksum), refusing to overwrite it; "+
+		"move the changes elsewhere (e.g. to the source) or use -force to overwrite it:\n%s", err.Path, err.Diff)
+}
+
+// contentChecksum returns the hash recorded in the footer, in the same format as TemplateVersion()
+func contentChecksum(content []byte) string {
+	var hash = sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// appendChecksum appends the checksum footer to the generated file, using the comment style of its header.
+// Files without a header (e.g. a template override replacing it) are returned unchanged.
+func appendChecksum(data []byte) []byte {
+	var loc = templateVersionLineRegexp.FindSubmatchIndex(data)
+	if loc == nil {
+		return data
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+
+	// the header line is inside an HTML comment if it has no prefix, see docs templates
+	var prefix, suffix = string(data[loc[2]:loc[3]]), ""
+	if len(bytes.TrimSpace(data[loc[2]:loc[3]])) == 0 {
+		prefix, suffix = "<!-- ", " -->"
+	}
+	return append(data, prefix+checksumMarker+" "+contentChecksum(data)+suffix+"\n"...)
+}
+
+// isManuallyEdited checks whether the given generated source doesn't match its checksum.
+// Sources without a checksum, e.g. generated without Options.Checksums, are never considered edited.
+func isManuallyEdited(source []byte) bool {
+	var loc = checksumRegexp.FindSubmatchIndex(source)
+	if loc == nil {
+		return false
+	}
+	return contentChecksum(source[:loc[0]]) != string(source[loc[2]:loc[3]])
+}
+
+// checkManualEdits fails if the existing file has been edited manually, unless confirmed by Options.Force.
+// A file which already has the new content (e.g. edited to match) is fine as overwriting it doesn't lose anything.
+func (options Options) checkManualEdits(file string, data []byte) error {
+	if options.Force {
+		return nil
+	}
+	previous, err := ioutil.ReadFile(file)
+	if err != nil || bytes.Equal(previous, data) || !isManuallyEdited(previous) {
+		return nil
+	}
+	var path = options.FormatPath(file)
+	return ManualEditError{Path: path, Diff: UnifiedDiff(path+" (edited)", path+" (generated)", string(previous), string(data))}
+}
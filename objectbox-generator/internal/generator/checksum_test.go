@@ -0,0 +1,140 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestChecksums(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "checksum")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+		Checksums:     true,
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	var hppFile = filepath.Join(dir, "schema.obx.hpp")
+	source, err := ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	var lines = strings.Split(strings.TrimSuffix(string(source), "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[len(lines)-1], "// ObjectBox Generator checksum: "))
+
+	// regenerating a file which hasn't been edited is fine
+	assert.NoErr(t, generator.Process(options))
+
+	// manual edits aren't overwritten, the error shows them
+	var edited = strings.Replace(string(source), "struct Task {", "struct Task { // edited", 1)
+	assert.NoErr(t, ioutil.WriteFile(hppFile, []byte(edited), 0600))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), hppFile+" has been edited manually"))
+	assert.True(t, strings.Contains(err.Error(), "use -force to overwrite it"))
+	assert.True(t, strings.Contains(err.Error(), "\n-struct Task { // edited\n+struct Task {\n"))
+	current, err := ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, edited, string(current))
+
+	// nor removed by the implicit cleanup when generating for a directory
+	var dirOptions = options
+	dirOptions.InPath = dir
+	err = generator.Process(dirOptions)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), hppFile+" has been edited manually"))
+	current, err = ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, edited, string(current))
+
+	// unless forced
+	options.Force = true
+	assert.NoErr(t, generator.Process(options))
+	current, err = ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(source), string(current))
+
+	// the docs use an HTML comment
+	options.CodeGenerator = &docsgenerator.DocsGenerator{Source: &cgenerator.CGenerator{LangVersion: 14}}
+	assert.NoErr(t, generator.Process(options))
+	page, err := ioutil.ReadFile(filepath.Join(dir, "Task.obx.md"))
+	assert.NoErr(t, err)
+	lines = strings.Split(strings.TrimSuffix(string(page), "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[len(lines)-1], "<!-- ObjectBox Generator checksum: "))
+	assert.True(t, strings.HasSuffix(lines[len(lines)-1], " -->"))
+}
+
+func TestChecksumsBeforeWriting(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "checksum-before-writing")
+	defer remove()
+
+	var firstSchema = fixture.WriteFile(t, filepath.Join(dir, "first.fbs"), "table First {\n id: ulong;\n}\n")
+	var secondSchema = fixture.WriteFile(t, filepath.Join(dir, "second.fbs"), "table Second {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        dir,
+		ModelInfoFile: generator.ModelInfoFile(dir),
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+		Checksums:     true,
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	// only the binding of the second source is edited, while both sources change
+	var secondHpp = filepath.Join(dir, "second.obx.hpp")
+	var edited = strings.Replace(fixture.ReadFile(t, secondHpp), "struct Second {", "struct Second { // edited", 1)
+	fixture.WriteFile(t, secondHpp, edited)
+	fixture.WriteFile(t, firstSchema, "table First {\n id: ulong;\n name: string;\n}\n")
+	fixture.WriteFile(t, secondSchema, "table Second {\n id: ulong;\n name: string;\n}\n")
+
+	var firstHpp = filepath.Join(dir, "first.obx.hpp")
+	var modelJson = options.ModelInfoFile
+	var modelFile = filepath.Join(dir, "objectbox-model.h")
+	var firstSource = fixture.ReadFile(t, firstHpp)
+	var modelJsonSource = fixture.ReadFile(t, modelJson)
+	var modelSource = fixture.ReadFile(t, modelFile)
+
+	// all the files are checked before any is written, so the first source's binding and the model are left as they were
+	err := generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), secondHpp+" has been edited manually"))
+	assert.Eq(t, firstSource, fixture.ReadFile(t, firstHpp))
+	assert.Eq(t, modelJsonSource, fixture.ReadFile(t, modelJson))
+	assert.Eq(t, modelSource, fixture.ReadFile(t, modelFile))
+	assert.Eq(t, edited, fixture.ReadFile(t, secondHpp))
+
+	options.Force = true
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, strings.Contains(fixture.ReadFile(t, firstHpp), "std::string name;"))
+	assert.True(t, strings.Contains(fixture.ReadFile(t, secondHpp), "std::string name;"))
+}
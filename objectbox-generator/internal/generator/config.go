@@ -0,0 +1,126 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/yamlschema"
+)
+
+// ConfigFileName is the name of the project-level config file, looked up in the source directory and its parents.
+// It sets command line flags by their names (without the dash), e.g.:
+//
+//	cpp: true
+//	optional: std::optional
+//	out: generated
+//	module-model: [../common/objectbox-model.json]
+//	lint:
+//	  huge-entity: error
+const ConfigFileName = "objectbox-gen.yaml"
+
+// Config holds the settings of a config file, see LoadConfig()
+type Config struct {
+	File      string
+	Flags     []ConfigFlag // in the declaration order
+	LintRules LintRules    // the "lint" section, rule severities same as in the -lint-config file
+}
+
+// ConfigFlag is a command line flag set by a config file
+type ConfigFlag struct {
+	Name     string   // without the dash, e.g. "out"
+	Values   []string // a single value unless the flag is given multiple times, e.g. "module-model"
+	Position string   // e.g. "objectbox-gen.yaml:3:1", for error messages
+}
+
+// FindConfigFile returns the ConfigFileName closest to the given source path, i.e. in its directory (the path itself
+// if it's a directory) or any of the parents. Returns an empty string if there's none.
+func FindConfigFile(sourcePath string) (string, error) {
+	// cut off path patterns, e.g. "./..." or "schemas/*.fbs"
+	var dir = filepath.Clean(strings.TrimSuffix(sourcePath, "..."))
+	for strings.ContainsAny(dir, "*?[") {
+		dir = filepath.Dir(dir)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		var file = filepath.Join(dir, ConfigFileName)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, nil
+		}
+		var parent = filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadConfig reads the given config file, see ConfigFileName.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := yamlschema.ParseSettings(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s", err)
+	}
+
+	var config = &Config{File: path}
+	for _, setting := range settings {
+		var position = fmt.Sprintf("%s:%d:%d", path, setting.Line, setting.Column)
+		switch setting.Section {
+		case "":
+			if setting.Key == "lint" {
+				return nil, fmt.Errorf("invalid config file %s: lint must be a mapping of rule severities, e.g. `huge-entity: error`", position)
+			}
+			config.Flags = append(config.Flags, ConfigFlag{Name: setting.Key, Values: setting.Values, Position: position})
+		case "lint":
+			if len(setting.Values) != 1 {
+				return nil, fmt.Errorf("invalid config file %s: lint rule %s must have a single severity", position, setting.Key)
+			}
+			if config.LintRules == nil {
+				config.LintRules = LintRules{}
+			}
+			config.LintRules[setting.Key] = LintSeverity(setting.Values[0])
+		default:
+			return nil, fmt.Errorf("invalid config file %s: %s can't be given as a mapping, only lint can", position, setting.Section)
+		}
+	}
+
+	if err = config.LintRules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %s", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestConfigFile(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "config")
+	defer remove()
+
+	var schemasDir = filepath.Join(dir, "schemas", "shop")
+	assert.NoErr(t, os.MkdirAll(schemasDir, 0700))
+	var schemaFile = filepath.Join(schemasDir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `table Task { id: ulong; }`)
+
+	// no config file
+	file, err := generator.FindConfigFile(schemaFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, "", file)
+
+	var configFile = filepath.Join(dir, generator.ConfigFileName)
+	fixture.WriteFile(t, configFile, `
+# shared by all schemas
+cpp: true
+optional: "std::optional"
+module-model:
+  - ../common/objectbox-model.json
+  - ../auth/objectbox-model.json
+lint:
+  huge-entity: error
+  unnamed-relation: off
+`)
+
+	// looked up from files, directories and path patterns
+	for _, path := range []string{schemaFile, schemasDir, filepath.Join(schemasDir, "*.fbs"), filepath.Join(dir, "schemas") + "/...", dir} {
+		file, err = generator.FindConfigFile(path)
+		assert.NoErr(t, err)
+		assert.Eq(t, configFile, file)
+	}
+
+	config, err := generator.LoadConfig(configFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(config.Flags))
+	assert.Eq(t, "cpp", config.Flags[0].Name)
+	assert.Eq(t, []string{"true"}, config.Flags[0].Values)
+	assert.Eq(t, configFile+":3:1", config.Flags[0].Position)
+	assert.Eq(t, []string{"std::optional"}, config.Flags[1].Values)
+	assert.Eq(t, []string{"../common/objectbox-model.json", "../auth/objectbox-model.json"}, config.Flags[2].Values)
+	assert.Eq(t, generator.LintRules{"huge-entity": generator.LintError, "unnamed-relation": generator.LintOff}, config.LintRules)
+
+	var loadErr = func(content string) string {
+		assert.NoErr(t, ioutil.WriteFile(configFile, []byte(content), 0600))
+		_, err := generator.LoadConfig(configFile)
+		assert.Err(t, err)
+		return strings.TrimPrefix(err.Error(), "invalid config file "+configFile)
+	}
+	assert.Eq(t, ": unknown rule no-such-rule - must be one of: "+strings.Join(generator.LintRuleNames(), ", "), loadErr("lint:\n  no-such-rule: error"))
+	assert.Eq(t, ":1:1: lint must be a mapping of rule severities, e.g. `huge-entity: error`", loadErr("lint: error"))
+	assert.Eq(t, ":2:3: out can't be given as a mapping, only lint can", loadErr("out:\n  dir: generated"))
+	assert.Eq(t, ":1:1: expecting a mapping of settings, e.g. `out: generated`", loadErr("- cpp"))
+}
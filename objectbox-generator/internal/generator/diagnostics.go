@@ -0,0 +1,205 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+)
+
+// Diagnostic codes, identifying the processing step which failed
+const (
+	DiagnosticParse    = "parse"     // reading the source, e.g. a syntax error or an invalid annotation
+	DiagnosticStrict   = "strict"    // constructs not supported by the generated code, see Options.Strict
+	DiagnosticRelation = "relation"  // a relation target not declared in any of the processed sources
+	DiagnosticMerge    = "merge"     // merging the source with the stored model, e.g. a UID mismatch
+	DiagnosticModel    = "model"     // the resulting model is invalid
+	DiagnosticWrite    = "write"     // generating or writing the binding files
+	DiagnosticGeneric  = "generator" // any other error, e.g. loading the stored model
+)
+
+// Diagnostic is a machine-readable description of a generator error, e.g. for IDE integrations showing it inline.
+// File, Line and Column are only set if known; Line and Column are 1-based.
+type Diagnostic struct {
+	File     string       `json:"file,omitempty"`
+	Line     int          `json:"line,omitempty"`
+	Column   int          `json:"column,omitempty"`
+	Code     string       `json:"code"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+
+	// ErrorCode identifies the message in the catalog, e.g. "OBXG1001", if it's a cataloged one, see messages.Code()
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// SourceError is returned by Process() and Validate() for errors caused by a source file, see Diagnostics().
+// The error message is the same as the one of the wrapped error.
+type SourceError struct {
+	File string
+	Code string // one of the Diagnostic* constants
+	Err  error
+}
+
+func (err *SourceError) Error() string {
+	return err.Err.Error()
+}
+
+// sourceError wraps the given error (if any) with the source file it was caused by
+func sourceError(file string, code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SourceError{file, code, err}
+}
+
+// ErrorList is returned by Process() and Validate() with Options.KeepGoing if there are multiple errors, e.g. invalid
+// annotations in several source files. The message lists all the errors, one per line.
+type ErrorList []error
+
+func (list ErrorList) Error() string {
+	var lines = make([]string, len(list))
+	for i, err := range list {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add appends the error to the list with Options.KeepGoing and returns nil so that the processing continues,
+// otherwise returns the error as is
+func (list *ErrorList) add(options Options, err error) error {
+	if err == nil || !options.KeepGoing {
+		return err
+	}
+	if nested, ok := err.(ErrorList); ok {
+		*list = append(*list, nested...)
+	} else {
+		*list = append(*list, err)
+	}
+	return nil
+}
+
+// err returns nil for an empty list, the error itself if there's a single one
+func (list ErrorList) err() error {
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	default:
+		return list
+	}
+}
+
+// positionRegexp matches a position at the beginning of the message or after a prefix, as reported by flatc
+// ("schema.fbs:5: 3: error: ...", with a 0-based column) or by the Go parser ("entity.go:5:3: ...")
+var positionRegexp = regexp.MustCompile(`(?:^|: )([^\s:]+\.\w+):(\d+):( ?)(\d+): (?:error: )?`)
+
+// flatc reports undefined types at the end of the file, mentioning where they're used
+var originalLineRegexp = regexp.MustCompile(`, originally at: [^\s:]+:(\d+)$`)
+
+// entityRegexp and propertyRegexp find the entity and the property an error message refers to
+var entityRegexp = regexp.MustCompile(`(?:\bentity|found in|\bobject \d+) ([A-Za-z_][\w.]*)`)
+var propertyRegexp = regexp.MustCompile(`(?:\bproperty|\brelation|: field \d+) ([A-Za-z_]\w*)`)
+
+// Diagnostics converts an error returned by Process() or Validate() to diagnostics. The generation stops at the first
+// error so there's a single diagnostic for a non-nil error, unless running with Options.KeepGoing, see ErrorList.
+// The position is taken from the message if it contains one (e.g. a syntax error), otherwise it's the declaration
+// of the entity (or the property) the error refers to, looked up in the source file.
+func Diagnostics(err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	if list, ok := err.(ErrorList); ok {
+		var result []Diagnostic
+		for _, item := range list {
+			result = append(result, Diagnostics(item)...)
+		}
+		return result
+	}
+
+	var diag = Diagnostic{Code: DiagnosticGeneric, Severity: LintError, Message: err.Error(), ErrorCode: messages.Code(err)}
+	if srcErr, ok := err.(*SourceError); ok {
+		diag.File = srcErr.File
+		diag.Code = srcErr.Code
+	}
+
+	if loc := positionRegexp.FindStringSubmatchIndex(diag.Message); loc != nil {
+		diag.File = diag.Message[loc[2]:loc[3]]
+		diag.Line, _ = strconv.Atoi(diag.Message[loc[4]:loc[5]])
+		diag.Column, _ = strconv.Atoi(diag.Message[loc[8]:loc[9]])
+		if loc[7] > loc[6] { // flatc
+			diag.Column++
+		}
+		if match := originalLineRegexp.FindStringSubmatch(diag.Message); match != nil {
+			diag.Line, _ = strconv.Atoi(match[1])
+			diag.Column = 0
+		}
+		diag.Message = diag.Message[:loc[2]] + diag.Message[loc[1]:]
+	} else if len(diag.File) > 0 {
+		diag.Line, diag.Column = locateDeclaration(diag.File, diag.Message)
+	}
+	return []Diagnostic{diag}
+}
+
+// locateDeclaration finds the declaration of the entity (and its property) the given message refers to in the source.
+// Returns zeros if not found.
+func locateDeclaration(file string, message string) (line, column int) {
+	var entity, property string
+	if match := entityRegexp.FindStringSubmatch(message); match != nil {
+		entity = match[1][strings.LastIndex(match[1], ".")+1:] // without a namespace
+	} else {
+		return 0, 0
+	}
+	if match := propertyRegexp.FindStringSubmatch(message); match != nil {
+		property = match[1]
+	}
+
+	source, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, 0
+	}
+
+	// a FlatBuffers "table Entity" or a Go "type Entity struct"
+	var entityDecl = regexp.MustCompile(`^\s*(?:table|type)\s+(` + regexp.QuoteMeta(entity) + `)\b`)
+	var propertyDecl = regexp.MustCompile(`^\s*(` + regexp.QuoteMeta(property) + `)\s*[:\s]`)
+	var lines = strings.Split(string(source), "\n")
+	for i, text := range lines {
+		if loc := entityDecl.FindStringSubmatchIndex(text); loc != nil {
+			line, column = i+1, loc[2]+1
+			if len(property) == 0 {
+				return line, column
+			}
+			// the property is declared in the entity body, i.e. before the closing brace at the beginning of a line
+			for j := i + 1; j < len(lines) && !strings.HasPrefix(lines[j], "}"); j++ {
+				if loc := propertyDecl.FindStringSubmatchIndex(lines[j]); loc != nil {
+					return j + 1, loc[2] + 1
+				}
+			}
+			return line, column
+		}
+	}
+	return 0, 0
+}
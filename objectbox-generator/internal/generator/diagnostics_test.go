@@ -0,0 +1,83 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestDiagnostics(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "diagnostics")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+	var diagnose = func(schema string) generator.Diagnostic {
+		assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(schema), 0600))
+		var diags = generator.Diagnostics(generator.Process(options))
+		assert.Eq(t, 1, len(diags))
+		assert.Eq(t, schemaFile, diags[0].File)
+		assert.Eq(t, generator.LintError, diags[0].Severity)
+		return diags[0]
+	}
+
+	assert.Eq(t, 0, len(generator.Diagnostics(nil)))
+
+	// the position reported by flatc
+	var diag = diagnose("table Task {\n id: ulong;\n text: strin;\n}\n")
+	assert.Eq(t, generator.DiagnosticParse, diag.Code)
+	assert.Eq(t, 3, diag.Line)
+	assert.True(t, strings.HasPrefix(diag.Message, "type referenced but not defined"))
+
+	// the declaration of the property the error refers to
+	diag = diagnose("table Task {\n id: ulong;\n /// objectbox:index=unknown\n text: string;\n}\n")
+	assert.Eq(t, generator.DiagnosticParse, diag.Code)
+	assert.Eq(t, 4, diag.Line)
+	assert.Eq(t, 2, diag.Column)
+
+	// the declaration of the entity
+	diag = diagnose("table Task {\n text: string;\n}\n")
+	assert.Eq(t, generator.DiagnosticModel, diag.Code)
+	assert.Eq(t, "OBXG1005", diag.ErrorCode)
+	assert.Eq(t, 1, diag.Line)
+	assert.Eq(t, 7, diag.Column)
+
+	// the errors not caused by a source file have no position
+	options.ModelInfoFile = filepath.Join(dir, "missing", "objectbox-model.json")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+	err := generator.Process(options)
+	assert.Err(t, err)
+	diag = generator.Diagnostics(err)[0]
+	assert.Eq(t, generator.DiagnosticGeneric, diag.Code)
+	assert.Eq(t, "", diag.File)
+	assert.Eq(t, err.Error(), diag.Message)
+}
@@ -0,0 +1,200 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package docsgenerator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/docs/templates"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Documentation formats supported by DocsGenerator
+const (
+	FormatMarkdown = "md"
+	FormatHtml     = "html"
+)
+
+// templatesVersion identifies all the templates used by the docs generator
+var templatesVersion = generator.TemplateVersion(templates.EntityMarkdownTemplate, templates.ModelMarkdownTemplate,
+	templates.EntityHtmlTemplate, templates.ModelHtmlTemplate)
+
+// docsTemplates holds the templates actually used for generating, i.e. including user overrides
+type docsTemplates struct {
+	entity, model generator.ExecutableTemplate
+	version       string
+}
+
+// loadTemplates returns the embedded templates for the given format with overrides from the given directory (if any) applied
+func loadTemplates(format string, overridesDir string) (*docsTemplates, error) {
+	var entityTpl, modelTpl = templates.EntityMarkdownTemplate, templates.ModelMarkdownTemplate
+	if format == FormatHtml {
+		entityTpl, modelTpl = templates.EntityHtmlTemplate, templates.ModelHtmlTemplate
+	}
+
+	tpls, version, err := generator.LoadTemplates(overridesDir, entityTpl, modelTpl)
+	if err != nil {
+		return nil, err
+	}
+	if format != FormatHtml {
+		return &docsTemplates{tpls[0], tpls[1], version}, nil
+	}
+
+	// HTML pages are rendered by html/template, escaping e.g. the comments from the sources
+	var result = &docsTemplates{version: version}
+	if result.entity, err = templates.HtmlTemplate(tpls[0]); err != nil {
+		return nil, err
+	}
+	if result.model, err = templates.HtmlTemplate(tpls[1]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DocsGenerator renders the merged model as documentation instead of code: a page per entity (e.g. Task.obx.md) listing
+// its properties, indexes and relations, including the comments from the source, and an index page for the whole model
+// (objectbox-model.md). Reading the sources is delegated to another code generator, e.g. CGenerator for FlatBuffers schema.
+type DocsGenerator struct {
+	Format string                  // FormatMarkdown (the default) or FormatHtml
+	Source generator.CodeGenerator // reads the sources
+}
+
+func (gen *DocsGenerator) ext() string {
+	if gen.Format == FormatHtml {
+		return FormatHtml
+	}
+	return FormatMarkdown
+}
+
+// BindingFiles returns the names of the documentation pages for the entities in the given source file.
+func (gen *DocsGenerator) BindingFiles(forFile string, options generator.Options) ([]string, error) {
+	// we need to read the source to find out which entities there are
+	m, err := gen.ParseSource(forFile)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entity := range m.Entities {
+		files = append(files, gen.entityFile(forFile, entity.Name, options))
+	}
+	return files, nil
+}
+
+func (gen *DocsGenerator) entityFile(forFile string, entityName string, options generator.Options) string {
+	var dir = filepath.Dir(forFile)
+	if len(options.OutPath) > 0 {
+		dir = options.OutPath
+	}
+	return filepath.Join(dir, entityName+".obx."+gen.ext())
+}
+
+// ModelFile returns the name of the documentation index page for the given model JSON file.
+func (gen *DocsGenerator) ModelFile(forFile string, options generator.Options) string {
+	if len(options.OutPath) > 0 {
+		forFile = filepath.Join(options.OutPath, filepath.Base(forFile))
+	}
+	var extension = filepath.Ext(forFile)
+	return forFile[0:len(forFile)-len(extension)] + "." + gen.ext()
+}
+
+func (gen *DocsGenerator) IsGeneratedFile(file string) bool {
+	var name = filepath.Base(file)
+	return name == "objectbox-model."+gen.ext() || strings.HasSuffix(name, ".obx."+gen.ext())
+}
+
+func (gen *DocsGenerator) IsSourceFile(file string) bool {
+	return gen.Source.IsSourceFile(file)
+}
+
+func (gen *DocsGenerator) ParseSource(sourceFile string) (*model.ModelInfo, error) {
+	return gen.Source.ParseSource(sourceFile)
+}
+
+// SetParseCache implements generator.ParseCacheUser, passing the cache on to the Source generator
+func (gen *DocsGenerator) SetParseCache(cache *generator.ParseCache) {
+	if user, ok := gen.Source.(generator.ParseCacheUser); ok {
+		user.SetParseCache(cache)
+	}
+}
+
+// WriteBindingFiles writes a documentation page for each entity declared in the given source file
+func (gen *DocsGenerator) WriteBindingFiles(sourceFile string, options generator.Options, mergedModel *model.ModelInfo) error {
+	tpls, err := loadTemplates(gen.ext(), options.TemplateOverridesDir)
+	if err != nil {
+		return err
+	}
+
+	var indexFile = filepath.Base(gen.ModelFile(options.ModelInfoFile, options))
+	for _, entity := range mergedModel.EntitiesWithMeta() {
+		if !options.EntitySelected(entity.Name) {
+			continue
+		}
+
+		var tplArguments = struct {
+			Entity          *model.Entity
+			IndexFile       string
+			TemplateVersion string
+		}{entity, indexFile, tpls.version}
+
+		source, err := generator.ExecuteTemplate(tpls.entity, tplArguments)
+		if err != nil {
+			return fmt.Errorf("can't generate documentation for entity %s: template execution failed: %s", entity.Name, err)
+		}
+
+		var file = gen.entityFile(sourceFile, entity.Name, options)
+		if err = options.WriteFile(file, source, sourceFile); err != nil {
+			return fmt.Errorf("can't write documentation file %s: %s", file, err)
+		}
+	}
+	return nil
+}
+
+// WriteModelBindingFile writes the documentation index page, listing all entities of the model
+func (gen *DocsGenerator) WriteModelBindingFile(options generator.Options, mergedModel *model.ModelInfo) error {
+	tpls, err := loadTemplates(gen.ext(), options.TemplateOverridesDir)
+	if err != nil {
+		return err
+	}
+
+	var tplArguments = struct {
+		Model           *model.ModelInfo
+		TemplateVersion string
+	}{mergedModel, tpls.version}
+
+	source, err := generator.ExecuteTemplate(tpls.model, tplArguments)
+	if err != nil {
+		return fmt.Errorf("can't generate documentation index: template execution failed: %s", err)
+	}
+
+	var modelFile = gen.ModelFile(options.ModelInfoFile, options)
+	if err = options.WriteFile(modelFile, source, options.ModelInfoFile); err != nil {
+		return fmt.Errorf("can't write documentation index %s: %s", modelFile, err)
+	}
+	return nil
+}
+
+// TemplateVersion returns an identifier of the docs templates
+func (gen *DocsGenerator) TemplateVersion() string {
+	return templatesVersion
+}
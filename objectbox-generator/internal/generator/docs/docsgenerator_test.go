@@ -0,0 +1,54 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package docsgenerator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+// see test/comparison/testdata/docs for the generated pages
+func TestBindingFiles(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "docs")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Customer {\n id: ulong;\n}\ntable Order {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &docsgenerator.DocsGenerator{Format: docsgenerator.FormatHtml, Source: &cgenerator.CGenerator{LangVersion: 14}},
+	}
+	files, err := options.CodeGenerator.BindingFiles(schemaFile, options)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{filepath.Join(dir, "Customer.obx.html"), filepath.Join(dir, "Order.obx.html")}, files)
+
+	// the pages depend on the entities, so a source that can't be parsed is an error instead of no pages
+	fixture.WriteFile(t, schemaFile, "table Customer {\n")
+	_, err = options.CodeGenerator.BindingFiles(schemaFile, options)
+	assert.Err(t, err)
+}
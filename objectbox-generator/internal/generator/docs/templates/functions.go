@@ -0,0 +1,96 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// propertyFlagLabels are the flags worth mentioning in the documentation, with the label to show
+var propertyFlagLabels = map[model.PropertyFlags]string{
+	model.PropertyFlagId:                      "id",
+	model.PropertyFlagIdSelfAssignable:        "self-assignable id",
+	model.PropertyFlagIdCompanion:             "id companion",
+	model.PropertyFlagNotNull:                 "not null",
+	model.PropertyFlagUnsigned:                "unsigned",
+	model.PropertyFlagUnique:                  "unique",
+	model.PropertyFlagUniqueOnConflictReplace: "replaces on unique conflict",
+	model.PropertyFlagIndexHash:               "hash index",
+	model.PropertyFlagIndexHash64:             "hash64 index",
+	model.PropertyFlagIndexPartialSkipNull:    "index skips null",
+	model.PropertyFlagIndexPartialSkipZero:    "index skips zero",
+}
+
+var funcMap = template.FuncMap{
+	"PropTypeName": func(val model.PropertyType) string {
+		return model.PropertyTypeNames[val]
+	},
+	"ExternalTypeName": func(val model.ExternalType) string {
+		return model.ExternalTypeNames[val]
+	},
+	"PropFlags": func(val model.PropertyFlags) string {
+		var result []string
+		for flag, label := range propertyFlagLabels {
+			if val&flag != 0 {
+				result = append(result, label)
+			}
+		}
+		// Go map iteration order is not guaranteed, sort to avoid changes in the generated docs
+		sort.Strings(result)
+		return strings.Join(result, ", ")
+	},
+	"IsIndexed": isIndexed,
+	"HasIndexes": func(entity *model.Entity) bool {
+		for _, property := range entity.Properties {
+			if isIndexed(property) || property.HnswParams != nil {
+				return true
+			}
+		}
+		return false
+	},
+	"HasRelations": func(entity *model.Entity) bool {
+		if len(entity.Relations) > 0 {
+			return true
+		}
+		for _, property := range entity.Properties {
+			if len(property.RelationTarget) > 0 {
+				return true
+			}
+		}
+		return false
+	},
+	"JoinComments": func(comments []string) string {
+		return strings.TrimSpace(strings.Join(comments, " "))
+	},
+	// MdCell escapes a value so that it can be used in a Markdown table cell
+	"MdCell": func(s string) string {
+		return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+	},
+}
+
+// isIndexed returns true for properties with a user-defined index, i.e. excluding the implicit to-one relation index
+func isIndexed(property *model.Property) bool {
+	return property.IndexId != nil && len(property.RelationTarget) == 0
+}
@@ -0,0 +1,125 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// The HTML templates are parsed as text templates, like all the others, so that user overrides and the templates
+// version work the same; they're only turned into HTML templates by HtmlTemplate() when executing them.
+
+// htmlFuncMap complements funcMap for the HTML templates
+var htmlFuncMap = template.FuncMap{
+	// GeneratedComment returns the "generated" notice; html/template drops comments written in the template itself
+	"GeneratedComment": func(templateVersion string) htmltemplate.HTML {
+		return htmltemplate.HTML("<!-- Code generated by ObjectBox; DO NOT EDIT.\nObjectBox Generator templates version: " +
+			htmltemplate.HTMLEscapeString(templateVersion) + " -->")
+	},
+}
+
+// HtmlTemplate converts the given (parsed) HTML page template, including the templates associated with it, e.g. the
+// overrides, to an html/template one, which escapes the values, e.g. the comments from the source, as needed.
+func HtmlTemplate(tpl *template.Template) (*htmltemplate.Template, error) {
+	var result = htmltemplate.New(tpl.Name()).Funcs(htmltemplate.FuncMap(funcMap)).Funcs(htmltemplate.FuncMap(htmlFuncMap))
+	for _, associated := range tpl.Templates() {
+		if associated.Tree == nil {
+			continue
+		}
+		// html/template rewrites the parse tree when escaping, the text template must stay usable as it's cached
+		if _, err := result.AddParseTree(associated.Name(), associated.Tree.Copy()); err != nil {
+			return nil, err
+		}
+	}
+	return result.Lookup(tpl.Name()), nil
+}
+
+// EntityHtmlTemplate renders a documentation page for a single entity
+var EntityHtmlTemplate = template.Must(template.New("entity-html").Funcs(funcMap).Funcs(htmlFuncMap).Parse(
+	`<!DOCTYPE html>
+{{GeneratedComment .TemplateVersion}}{{block "file-header" .}}{{end}}
+{{- with .Entity}}
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{- with JoinComments .Comments}}
+<p>{{.}}</p>
+{{- end}}
+<p><a href="{{$.IndexFile}}">All entities</a></p>
+<table>
+<tr><th>Property</th><th>Type</th><th>Flags</th><th>Description</th></tr>
+{{- range .Properties}}
+<tr><td>{{.Name}}</td><td>{{PropTypeName .Type}}{{with .ArrayLength}}[{{.}}]{{end}}{{with .ExternalType}} ({{ExternalTypeName .}}){{end}}</td><td>{{PropFlags .Flags}}</td><td>{{JoinComments .Comments}}</td></tr>
+{{- end}}
+</table>
+{{- if HasIndexes .}}
+<h2>Indexes</h2>
+<ul>
+{{- range .Properties}}{{if .HnswParams}}
+<li>{{.Name}}: HNSW vector index{{with .HnswParams.Dimensions}}, {{.}} dimensions{{end}}{{with .HnswParams.DistanceType}}, distance type {{.}}{{end}}</li>
+{{- else if IsIndexed .}}
+<li>{{.Name}}{{with PropFlags .Flags}} ({{.}}){{end}}</li>
+{{- end}}{{end}}
+</ul>
+{{- end}}
+{{- if HasRelations .}}
+<h2>Relations</h2>
+<ul>
+{{- range .Properties}}{{if .RelationTarget}}
+<li>{{.Name}}: to-one relation to <a href="{{.RelationTarget}}.obx.html">{{.RelationTarget}}</a></li>
+{{- end}}{{end}}
+{{- range .Relations}}
+<li>{{.Name}}: to-many relation to <a href="{{.Target.Name}}.obx.html">{{.Target.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{block "file-footer" .}}{{end}}
+</body>
+</html>
+`))
+
+// ModelHtmlTemplate renders the index page listing all entities of the model
+var ModelHtmlTemplate = template.Must(template.New("model-html").Funcs(funcMap).Funcs(htmlFuncMap).Parse(
+	`<!DOCTYPE html>
+{{GeneratedComment .TemplateVersion}}{{block "file-header" .}}{{end}}
+<html>
+<head>
+<meta charset="utf-8">
+<title>ObjectBox model</title>
+</head>
+<body>
+<h1>ObjectBox model</h1>
+<table>
+<tr><th>Entity</th><th>Properties</th><th>Relations</th></tr>
+{{- range .Model.Entities}}
+<tr><td><a href="{{.Name}}.obx.html">{{.Name}}</a></td><td>{{len .Properties}}</td><td>{{len .Relations}}</td></tr>
+{{- end}}
+</table>
+{{block "file-footer" .}}{{end}}
+</body>
+</html>
+`))
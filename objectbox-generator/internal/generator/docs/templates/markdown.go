@@ -0,0 +1,78 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// EntityMarkdownTemplate renders a documentation page for a single entity
+var EntityMarkdownTemplate = template.Must(template.New("entity-md").Funcs(funcMap).Parse(
+	`<!-- Code generated by ObjectBox; DO NOT EDIT.
+ObjectBox Generator templates version: {{.TemplateVersion}} -->{{block "file-header" .}}{{end}}
+{{with .Entity}}
+# {{.Name}}
+{{- with JoinComments .Comments}}
+
+{{.}}
+{{- end}}
+
+[All entities]({{$.IndexFile}})
+
+| Property | Type | Flags | Description |
+|----------|------|-------|-------------|
+{{- range .Properties}}
+| {{MdCell .Name}} | {{PropTypeName .Type}}{{with .ArrayLength}}[{{.}}]{{end}}{{with .ExternalType}} ({{ExternalTypeName .}}){{end}} | {{PropFlags .Flags}} | {{MdCell (JoinComments .Comments)}} |
+{{- end}}
+{{- if HasIndexes .}}
+
+## Indexes
+{{range .Properties}}{{if .HnswParams}}
+* {{.Name}}: HNSW vector index{{with .HnswParams.Dimensions}}, {{.}} dimensions{{end}}{{with .HnswParams.DistanceType}}, distance type {{.}}{{end}}
+{{- else if IsIndexed .}}
+* {{.Name}}{{with PropFlags .Flags}} ({{.}}){{end}}
+{{- end}}{{end}}
+{{- end}}
+{{- if HasRelations .}}
+
+## Relations
+{{range .Properties}}{{if .RelationTarget}}
+* {{.Name}}: to-one relation to [{{.RelationTarget}}]({{.RelationTarget}}.obx.md)
+{{- end}}{{end}}
+{{- range .Relations}}
+* {{.Name}}: to-many relation to [{{.Target.Name}}]({{.Target.Name}}.obx.md)
+{{- end}}
+{{- end}}
+{{end}}{{block "file-footer" .}}{{end}}`))
+
+// ModelMarkdownTemplate renders the index page listing all entities of the model
+var ModelMarkdownTemplate = template.Must(template.New("model-md").Funcs(funcMap).Parse(
+	`<!-- Code generated by ObjectBox; DO NOT EDIT.
+ObjectBox Generator templates version: {{.TemplateVersion}} -->{{block "file-header" .}}{{end}}
+
+# ObjectBox model
+
+| Entity | Properties | Relations |
+|--------|------------|-----------|
+{{- range .Model.Entities}}
+| [{{.Name}}]({{.Name}}.obx.md) | {{len .Properties}} | {{len .Relations}} |
+{{- end}}
+{{block "file-footer" .}}{{end}}`))
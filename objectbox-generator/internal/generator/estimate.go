@@ -0,0 +1,255 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// DefaultAverageSizes are the average sizes (in bytes) of variable-length values used by Estimate(), indexed by the
+// property type name. The size of a FloatVector defaults to its array length or the HNSW dimensions, if known.
+var DefaultAverageSizes = map[string]int{
+	"String":       32,
+	"ByteVector":   128,
+	"StringVector": 128, // all the elements together
+	"FloatVector":  512,
+}
+
+// rough sizes of the ObjectBox storage structures used by Estimate()
+const (
+	estimateKeySize         = 4 + 8 // entity prefix and the object ID of each object key
+	estimateIndexEntrySize  = 4 + 8 // index prefix and the object ID of each index entry
+	estimateHnswNeighbors   = 30    // default HNSW neighbors per node
+	estimateHnswNeighborRef = 8     // an object ID for each link to a neighbor
+)
+
+// EstimateOptions configure Estimate()
+type EstimateOptions struct {
+	// AverageSizes override DefaultAverageSizes, e.g. {"String": 100}
+	AverageSizes map[string]int
+
+	// Objects is the expected number of objects of each entity, by entity name; the value for "" applies to entities
+	// not listed. Defaults to a single object.
+	Objects map[string]int64
+}
+
+// EstimatedModel is the result of Estimate(); sizes are in bytes
+type EstimatedModel struct {
+	Entities []EstimatedEntity `json:"entities"`
+	Total    int64             `json:"total"`
+}
+
+// EstimatedEntity describes the estimated size of a single object of an entity and of all its (expected) objects
+type EstimatedEntity struct {
+	Name       string              `json:"name"`
+	Objects    int64               `json:"objects"`
+	ObjectSize int                 `json:"objectSize"` // the FlatBuffers table including the object key
+	IndexSize  int                 `json:"indexSize"`  // entries of all the (non-HNSW) indexes
+	HnswSize   int                 `json:"hnswSize"`   // HNSW graph nodes and the cached vectors
+	Total      int64               `json:"total"`      // all the objects
+	Properties []EstimatedProperty `json:"properties"`
+}
+
+// EstimatedProperty describes the estimated size of a property value of a single object
+type EstimatedProperty struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	DataSize  int    `json:"dataSize"` // including the vtable entry and the FlatBuffers vector header
+	IndexSize int    `json:"indexSize,omitempty"`
+	HnswSize  int    `json:"hnswSize,omitempty"`
+}
+
+// Estimate reads the sources and merges them with the stored model, same as Validate(), without writing any files.
+// Returns the estimated storage size of each entity, e.g. for capacity planning of constrained devices.
+// The estimation doesn't include standalone relations and the overhead of the storage engine (e.g. B+tree pages).
+func Estimate(options Options, estimateOptions EstimateOptions) (*EstimatedModel, error) {
+	var averageSizes = make(map[string]int)
+	for typeName, size := range DefaultAverageSizes {
+		averageSizes[typeName] = size
+	}
+	for typeName, size := range estimateOptions.AverageSizes {
+		if _, known := DefaultAverageSizes[typeName]; !known {
+			return nil, fmt.Errorf("can't set an average size of type %q, expecting one of: %s", typeName, variableSizeTypeNames())
+		}
+		if size < 0 {
+			return nil, fmt.Errorf("invalid average size of type %s: %d", typeName, size)
+		}
+		averageSizes[typeName] = size
+	}
+
+	modelInfo, err := Validate(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = &EstimatedModel{Entities: []EstimatedEntity{}}
+	for _, entity := range modelInfo.Entities {
+		if !entity.CurrentlyPresent {
+			continue
+		}
+
+		var estimated = EstimatedEntity{Name: entity.Name, Objects: 1, Properties: []EstimatedProperty{}}
+		if count, found := estimateOptions.Objects[entity.Name]; found {
+			estimated.Objects = count
+		} else if count, found := estimateOptions.Objects[""]; found {
+			estimated.Objects = count
+		}
+
+		// FlatBuffers root offset, vtable offset and the vtable header (vtable and table sizes)
+		var tableSize = 4 + 4 + 4
+		for _, property := range entity.Properties {
+			var estimatedProperty = estimateProperty(property, averageSizes)
+			tableSize += estimatedProperty.DataSize
+			estimated.IndexSize += estimatedProperty.IndexSize
+			estimated.HnswSize += estimatedProperty.HnswSize
+			estimated.Properties = append(estimated.Properties, estimatedProperty)
+		}
+		estimated.ObjectSize = align(tableSize, 8) + estimateKeySize
+		estimated.Total = estimated.Objects * int64(estimated.ObjectSize+estimated.IndexSize+estimated.HnswSize)
+		result.Total += estimated.Total
+		result.Entities = append(result.Entities, estimated)
+	}
+	return result, nil
+}
+
+func estimateProperty(property *model.Property, averageSizes map[string]int) EstimatedProperty {
+	var typeName = model.PropertyTypeNames[property.Type]
+	var result = EstimatedProperty{Name: property.Name, Type: typeName}
+
+	var valueSize = scalarSize(property.Type)
+	if valueSize == 0 { // variable-length values are stored as an offset to a vector: its length and the data
+		valueSize = averageSizes[typeName]
+		if property.Type == model.PropertyTypeFloatVector {
+			if property.ArrayLength > 0 {
+				valueSize = 4 * int(property.ArrayLength)
+			} else if property.HnswParams != nil && property.HnswParams.Dimensions != nil {
+				valueSize = 4 * int(*property.HnswParams.Dimensions)
+			}
+		} else if property.Type == model.PropertyTypeString {
+			valueSize++ // zero terminated
+		}
+		result.DataSize = 4 + 4 + align(valueSize, 4)
+	} else {
+		result.DataSize = valueSize
+	}
+	result.DataSize += 2 // vtable entry
+
+	if property.HnswParams != nil {
+		var neighbors = estimateHnswNeighbors
+		if property.HnswParams.NeighborsPerNode != nil {
+			neighbors = int(*property.HnswParams.NeighborsPerNode)
+		}
+		// the lowest graph level links up to twice the neighbors per node; vectors are cached in memory
+		result.HnswSize = estimateIndexEntrySize + 2*neighbors*estimateHnswNeighborRef + valueSize
+	} else if property.IndexId != nil {
+		var keySize = valueSize
+		if property.Flags&model.PropertyFlagIndexHash != 0 {
+			keySize = 4
+		} else if property.Flags&model.PropertyFlagIndexHash64 != 0 {
+			keySize = 8
+		}
+		result.IndexSize = estimateIndexEntrySize + keySize
+	}
+	return result
+}
+
+// scalarSize returns the size of a scalar property type or 0 for variable-length types
+func scalarSize(propertyType model.PropertyType) int {
+	switch propertyType {
+	case model.PropertyTypeBool, model.PropertyTypeByte:
+		return 1
+	case model.PropertyTypeShort, model.PropertyTypeChar:
+		return 2
+	case model.PropertyTypeInt, model.PropertyTypeFloat:
+		return 4
+	case model.PropertyTypeLong, model.PropertyTypeDouble, model.PropertyTypeDate, model.PropertyTypeDateNano, model.PropertyTypeRelation:
+		return 8
+	}
+	return 0
+}
+
+func align(size, alignment int) int {
+	return (size + alignment - 1) / alignment * alignment
+}
+
+func variableSizeTypeNames() string {
+	var names []string
+	for typeName := range DefaultAverageSizes {
+		names = append(names, typeName)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Write prints the estimation as a table with a row for each entity, followed by rows for its properties
+func (estimated *EstimatedModel) Write(w io.Writer) error {
+	var b strings.Builder
+	var tw = tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tOBJECTS\tDATA\tINDEXES\tHNSW\tTOTAL")
+	for _, entity := range estimated.Entities {
+		fmt.Fprintf(tw, "%s\t\t%d\t%s\t%s\t%s\t%s\n", entity.Name, entity.Objects, formatSize(int64(entity.ObjectSize)),
+			formatSize(int64(entity.IndexSize)), formatSize(int64(entity.HnswSize)), formatSize(entity.Total))
+		for _, property := range entity.Properties {
+			fmt.Fprintf(tw, "  %s\t%s\t\t%s\t%s\t%s\t\n", property.Name, property.Type, formatSize(int64(property.DataSize)),
+				formatOptionalSize(property.IndexSize), formatOptionalSize(property.HnswSize))
+		}
+	}
+	fmt.Fprintf(tw, "total\t\t\t\t\t\t%s\n", formatSize(estimated.Total))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// property rows end with empty cells, padded by the tabwriter
+	var lines = strings.Split(b.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	_, err := fmt.Fprintf(w, "%sSizes are estimated for a single object (the total for all of them), excluding standalone relations "+
+		"and the storage engine overhead.\n", strings.Join(lines, "\n"))
+	return err
+}
+
+func formatOptionalSize(size int) string {
+	if size == 0 {
+		return ""
+	}
+	return formatSize(int64(size))
+}
+
+// formatSize returns a human-readable size, e.g. "1.5 KiB"
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	var value = float64(size) / unit
+	var prefix = 0
+	for ; value >= unit && prefix < 3; prefix++ {
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[prefix])
+}
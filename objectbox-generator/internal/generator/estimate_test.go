@@ -0,0 +1,96 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestEstimate(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "estimate")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `table Task {
+	id: ulong;
+	/// objectbox:index
+	text: string;
+	/// objectbox:index=hnsw, hnsw-dimensions=3, hnsw-neighbors-per-node=10
+	embedding: [float];
+}
+table Tag {
+	id: ulong;
+}
+`)
+	var options = generator.Options{InPath: schemaFile, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+
+	estimated, err := generator.Estimate(options, generator.EstimateOptions{
+		AverageSizes: map[string]int{"String": 20},
+		Objects:      map[string]int64{"Task": 1000, "": 10},
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(estimated.Entities))
+	var task, tag = estimated.Entities[0], estimated.Entities[1]
+	if task.Name != "Task" {
+		task, tag = tag, task
+	}
+
+	// id: 8 + 2 (vtable); text: 4 (offset) + 4 (length) + 24 (aligned "20 + 1") + 2, indexed by a 4 byte hash;
+	// embedding: 4 + 4 + 3 * 4 + 2, HNSW: 12 (key) + 2 * 10 neighbors * 8 + 12 (cached vector)
+	assert.Eq(t, []generator.EstimatedProperty{
+		{Name: "id", Type: "Long", DataSize: 10},
+		{Name: "text", Type: "String", DataSize: 34, IndexSize: 16},
+		{Name: "embedding", Type: "FloatVector", DataSize: 22, HnswSize: 184},
+	}, task.Properties)
+
+	// table: 12 (header) + 66 (properties) aligned to 80, plus a 12 byte object key
+	assert.Eq(t, 92, task.ObjectSize)
+	assert.Eq(t, 16, task.IndexSize)
+	assert.Eq(t, 184, task.HnswSize)
+	assert.Eq(t, int64(292000), task.Total)
+	assert.Eq(t, int64(10), tag.Objects)
+	assert.Eq(t, int64(360), tag.Total)
+	assert.Eq(t, int64(292360), estimated.Total)
+
+	// nothing is written, not even the model JSON
+	_, err = os.Stat(generator.ModelInfoFile(dir))
+	assert.True(t, os.IsNotExist(err))
+
+	var out bytes.Buffer
+	assert.NoErr(t, estimated.Write(&out))
+	assert.True(t, strings.Contains(out.String(), "\n  embedding  FloatVector           22 B           184 B\n"))
+	assert.True(t, strings.Contains(out.String(), "\ntotal "))
+	assert.True(t, strings.HasSuffix(out.String(), " 285.5 KiB\nSizes are estimated for a single object (the total for all of them), "+
+		"excluding standalone relations and the storage engine overhead.\n"))
+
+	_, err = generator.Estimate(options, generator.EstimateOptions{AverageSizes: map[string]int{"Int": 8}})
+	assert.Err(t, err)
+	assert.Eq(t, `can't set an average size of type "Int", expecting one of: ByteVector, FloatVector, String, StringVector`, err.Error())
+}
@@ -0,0 +1,83 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestExternalMapping(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "external-mapping")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `/// objectbox:sync
+/// objectbox:external-name=orders
+/// objectbox:relation(name=items, to=Item, external-type=MongoIdVector)
+table Order {
+	id: ulong;
+	/// objectbox:external-type=Json
+	payload: string;
+}
+/// objectbox:sync
+table Item {
+	id: ulong;
+}
+`)
+	fixture.Generate(t, schemaFile, &cgenerator.CGenerator{LangVersion: 14})
+
+	// the model JSON includes the mapping, with external names defaulting to the ObjectBox names;
+	// see test/comparison/testdata/fbs/external-id for the mapping in the generated objectbox-model.h
+	storedModel, err := model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.True(t, storedModel.ExternalMapping != nil)
+	assert.Eq(t, 2, len(storedModel.ExternalMapping.Entities))
+	var entities = make(map[string]*model.ExternalEntityMapping)
+	for _, entity := range storedModel.ExternalMapping.Entities {
+		entities[entity.Name] = entity
+	}
+	var order = entities["Order"]
+	assert.Eq(t, "Order orders", order.Name+" "+order.ExternalName)
+	assert.True(t, order.Sync)
+	assert.Eq(t, "payload payload String Json", order.Properties[1].Name+" "+order.Properties[1].ExternalName+" "+
+		order.Properties[1].Type+" "+order.Properties[1].ExternalType)
+	assert.Eq(t, "items items MongoIdVector Item", order.Relations[0].Name+" "+order.Relations[0].ExternalName+" "+
+		order.Relations[0].ExternalType+" "+order.Relations[0].Target)
+	assert.Eq(t, "Item", entities["Item"].ExternalName)
+
+	// without any external names or types, there's no mapping
+	fixture.WriteFile(t, schemaFile, "table Order {\n id: ulong;\n}\n")
+	assert.NoErr(t, os.Remove(generator.ModelInfoFile(dir)))
+	fixture.Generate(t, schemaFile, &cgenerator.CGenerator{LangVersion: 14})
+	data, err := ioutil.ReadFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.True(t, !strings.Contains(string(data), "externalMapping"))
+}
@@ -0,0 +1,141 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package flatbuffersc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ArrayLengthAttribute is set on table fields declared as fixed-length arrays, e.g. `[float:4]`, see rewriteTableArrays()
+const ArrayLengthAttribute = "obx_array_length"
+
+// arrayTypeRegex matches a fixed-length array type, e.g. `[float:4]`, capturing the element type and the length
+var arrayTypeRegex = regexp.MustCompile(`^\[\s*([\w.]+)\s*:\s*(\d+)\s*\]`)
+
+// rewriteTableArrays replaces fixed-length array types of table fields, which the FlatBuffers parser only accepts in
+// structs, by vectors with the ArrayLengthAttribute, e.g. `vec: [float:4];` becomes `vec: [float] (obx_array_length: 4);`.
+// The rewrite stays on the same line so that the parser reports correct line numbers. Comments and strings are skipped.
+func rewriteTableArrays(source string) string {
+	var result strings.Builder
+	var depth int          // nesting level of braces
+	var lastKeyword string // the last declaration keyword at the top level, e.g. "table" or "struct"
+
+	for i := 0; i < len(source); {
+		var c = source[i]
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			var end = strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			result.WriteString(source[i : i+end])
+			i += end
+			continue
+		case strings.HasPrefix(source[i:], "/*"):
+			var end = strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				end = len(source) - i
+			} else {
+				end += 4
+			}
+			result.WriteString(source[i : i+end])
+			i += end
+			continue
+		case c == '"':
+			var end = i + 1
+			for end < len(source) && source[end] != '"' && source[end] != '\n' {
+				if source[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(source) {
+				end++
+			}
+			result.WriteString(source[i:end])
+			i = end
+			continue
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && isIdentStart(c):
+			var end = i
+			for end < len(source) && isIdentPart(source[end]) {
+				end++
+			}
+			switch source[i:end] {
+			case "table", "struct", "enum", "union", "rpc_service":
+				lastKeyword = source[i:end]
+			}
+			result.WriteString(source[i:end])
+			i = end
+			continue
+		case c == '[' && depth == 1 && lastKeyword == "table":
+			if match := arrayTypeRegex.FindStringSubmatchIndex(source[i:]); match != nil {
+				var elementType = source[i+match[2] : i+match[3]]
+				var length = source[i+match[4] : i+match[5]]
+				i += match[1]
+				result.WriteString("[" + elementType + "]")
+				result.WriteString(arrayAttributes(source, &i, length))
+				continue
+			}
+		}
+		result.WriteByte(c)
+		i++
+	}
+	return result.String()
+}
+
+// arrayAttributes returns the field attributes following an array type (which starts at *pos) with the array length
+// attribute added, advancing *pos past the original attributes, if there are any.
+func arrayAttributes(source string, pos *int, length string) string {
+	var attribute = fmt.Sprintf("%s: %s", ArrayLengthAttribute, length)
+
+	var i = *pos
+	for i < len(source) && (source[i] == ' ' || source[i] == '\t') {
+		i++
+	}
+	if i >= len(source) || source[i] != '(' {
+		return " (" + attribute + ")"
+	}
+
+	// existing attributes, e.g. `(deprecated)`: keep the whitespace and add the length as the first one
+	var whitespace = source[*pos:i]
+	*pos = i + 1
+	if rest := strings.TrimLeftFunc(source[*pos:], unicode.IsSpace); strings.HasPrefix(rest, ")") {
+		return whitespace + "(" + attribute
+	}
+	return whitespace + "(" + attribute + ", "
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -35,19 +35,73 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"unsafe"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/yamlschema"
 )
 
 func ParseSchemaFile(filename string) (*reflection.Schema, error) {
+	return ParseSchemaFileWithIncludes(filename, nil)
+}
+
+// ParseSchemaFileWithIncludes parses the given schema file, looking up included files relative to the including file,
+// then in the given include directories and finally in the current directory.
+// Use DeclaredIn() to tell objects declared in the given file from those declared in the included files.
+// Fixed-length arrays in tables, including those in the included files, are parsed as vectors with the
+// ArrayLengthAttribute, see rewriteTableArrays().
+// YAML schema files (see yamlschema.IsSchemaFile()) are converted to FlatBuffers schema first.
+func ParseSchemaFileWithIncludes(filename string, includeDirs []string) (*reflection.Schema, error) {
+	includedFiles, err := checkIncludes(filename, includeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load file: %s", filename)
+	}
+
+	if yamlschema.IsSchemaFile(filename) {
+		fbs, err := yamlschema.ToFbs(filename, source)
+		if err != nil {
+			return nil, err
+		}
+		source = []byte(fbs)
+	}
+
 	var cFilename = C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 
+	var cSource = C.CString(rewriteTableArrays(string(source)))
+	defer C.free(unsafe.Pointer(cSource))
+
+	var cIncludeDirs = goStringArrayToC(includeDirs)
+	defer cIncludeDirs.free()
+
+	// the parser reads the included files itself, pass those it needs to read rewritten
+	var rewrittenFiles, rewrittenSources []string
+	for _, file := range includedFiles {
+		included, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load file: %s", file)
+		}
+		if rewritten := rewriteTableArrays(string(included)); rewritten != string(included) {
+			rewrittenFiles = append(rewrittenFiles, file)
+			rewrittenSources = append(rewrittenSources, rewritten)
+		}
+	}
+	var cIncludedFiles = goStringArrayToC(rewrittenFiles)
+	defer cIncludedFiles.free()
+	var cIncludedSources = goStringArrayToC(rewrittenSources)
+	defer cIncludedSources.free()
+
 	var cErr *C.char = nil
 	defer C.fbs_error_free(cErr)
 
-	var fbsBytes *C.FBS_bytes = C.fbs_schema_parse_file(cFilename, &cErr)
+	var fbsBytes *C.FBS_bytes = C.fbs_schema_parse_sources(cFilename, cSource, cIncludeDirs.cArray, C.size_t(cIncludeDirs.size),
+		cIncludedFiles.cArray, cIncludedSources.cArray, C.size_t(len(rewrittenFiles)), &cErr)
 	if fbsBytes == nil {
 		if cErr == nil {
 			return nil, errors.New("unknown error")
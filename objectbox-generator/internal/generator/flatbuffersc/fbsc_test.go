@@ -23,6 +23,7 @@ package flatbuffersc
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -136,3 +137,111 @@ func TestFbsFlatc(t *testing.T) {
 	assert.True(t, len(outFiles) == 3)
 	assert.EqItems(t, []string{"Being.go", "Item.go", "Planet.go"}, []string{outFiles[0].Name(), outFiles[1].Name(), outFiles[2].Name()})
 }
+
+func TestFbsIncludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fbs-test-includes")
+	assert.NoErr(t, err)
+	defer func() {
+		assert.NoErr(t, os.RemoveAll(dir))
+	}()
+
+	var includeDir = filepath.Join(dir, "include")
+	assert.NoErr(t, os.Mkdir(includeDir, 0700))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(includeDir, "item.fbs"), []byte("table Item { name:string; }"), 0600))
+	var file = filepath.Join(dir, "being.fbs")
+	assert.NoErr(t, ioutil.WriteFile(file, []byte("include \"item.fbs\";\ntable Being { belongings:[Item]; }"), 0600))
+
+	_, err = ParseSchemaFile(file)
+	assert.Err(t, err)
+	assert.Eq(t, file+": unable to locate include file item.fbs, searched in: "+dir+", .", err.Error())
+
+	schema, err := ParseSchemaFileWithIncludes(file, []string{includeDir})
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, schema.ObjectsLength())
+
+	var object reflection.Object
+	assert.True(t, schema.Objects(&object, 0))
+	assert.Eq(t, "Being", string(object.Name()))
+	assert.True(t, DeclaredIn(&object, file))
+	assert.True(t, schema.Objects(&object, 1))
+	assert.Eq(t, "Item", string(object.Name()))
+	assert.True(t, !DeclaredIn(&object, file))
+
+	// item.fbs includes being.fbs back
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(includeDir, "item.fbs"), []byte("include \"../being.fbs\";\ntable Item { name:string; }"), 0600))
+	_, err = ParseSchemaFileWithIncludes(file, []string{includeDir})
+	assert.Err(t, err)
+	assert.Eq(t, "include cycle: "+file+" -> "+filepath.Join(includeDir, "item.fbs")+" -> "+file, err.Error())
+}
+
+func TestFbsTableArrays(t *testing.T) {
+	assert.Eq(t, "table T { a: [float] (obx_array_length: 4); b: [ubyte] (obx_array_length: 2, deprecated); }",
+		rewriteTableArrays("table T { a: [float:4]; b: [ubyte : 2] (deprecated); }"))
+	assert.Eq(t, "table T { a: [float] (obx_array_length: 4 ); }", rewriteTableArrays("table T { a: [float:4] ( ); }"))
+
+	// structs support arrays natively, comments and strings are kept as they are
+	var unchanged = "struct S { a: [float:4]; }\n// table T { a: [float:4]; }\n/* [int:2] */ table T { a: string (s: \"[int:2]\"); }"
+	assert.Eq(t, unchanged, rewriteTableArrays(unchanged))
+
+	dir, err := ioutil.TempDir("", "fbs-test-arrays")
+	assert.NoErr(t, err)
+	defer func() {
+		assert.NoErr(t, os.RemoveAll(dir))
+	}()
+
+	var file = filepath.Join(dir, "schema.fbs")
+	assert.NoErr(t, ioutil.WriteFile(file, []byte("table Doc {\n  vec: [float:4];\n}"), 0600))
+	schema, err := ParseSchemaFile(file)
+	assert.NoErr(t, err)
+
+	var object reflection.Object
+	var field reflection.Field
+	var attr reflection.KeyValue
+	assert.True(t, schema.Objects(&object, 0))
+	assert.True(t, object.Fields(&field, 0))
+	assert.Eq(t, reflection.BaseTypeVector, field.Type(nil).BaseType())
+	assert.True(t, field.AttributesByKey(&attr, ArrayLengthAttribute))
+	assert.Eq(t, "4", string(attr.Value()))
+}
+
+func TestFbsTableArraysIncluded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fbs-test-arrays-included")
+	assert.NoErr(t, err)
+	defer func() {
+		assert.NoErr(t, os.RemoveAll(dir))
+	}()
+
+	// included transitively, from the directory of the including file and from an include directory
+	var includeDir = filepath.Join(dir, "include")
+	assert.NoErr(t, os.Mkdir(includeDir, 0700))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(includeDir, "vector.fbs"), []byte("table Vector {\n  vec: [float:3];\n}"), 0600))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(dir, "doc.fbs"), []byte("include \"vector.fbs\";\ntable Doc {\n  vec: [float:4];\n}"), 0600))
+	var file = filepath.Join(dir, "schema.fbs")
+	assert.NoErr(t, ioutil.WriteFile(file, []byte("include \"doc.fbs\";\ntable Page {\n  vec: [float:2];\n}"), 0600))
+
+	schema, err := ParseSchemaFileWithIncludes(file, []string{includeDir})
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, schema.ObjectsLength())
+
+	var object reflection.Object
+	var field reflection.Field
+	var attr reflection.KeyValue
+	for i, expected := range []string{"Doc:4", "Page:2", "Vector:3"} { // sorted by name
+		assert.True(t, schema.Objects(&object, i))
+		assert.True(t, object.Fields(&field, 0))
+		assert.Eq(t, reflection.BaseTypeVector, field.Type(nil).BaseType())
+		assert.True(t, field.AttributesByKey(&attr, ArrayLengthAttribute))
+		assert.Eq(t, expected, string(object.Name())+":"+string(attr.Value()))
+	}
+	assert.True(t, schema.Objects(&object, 1))
+	assert.True(t, DeclaredIn(&object, file))
+
+	// the files on disk aren't changed, parsing another file including them rewrites them again
+	content, err := ioutil.ReadFile(filepath.Join(dir, "doc.fbs"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "include \"vector.fbs\";\ntable Doc {\n  vec: [float:4];\n}", string(content))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(dir, "plain.fbs"), []byte("include \"doc.fbs\";\ntable Plain {\n  vec: [float:5];\n}"), 0600))
+	schema, err = ParseSchemaFileWithIncludes(filepath.Join(dir, "plain.fbs"), []string{includeDir})
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, schema.ObjectsLength())
+}
@@ -0,0 +1,84 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package flatbuffersc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
+)
+
+// FuzzParseFbs feeds arbitrary schema files to the parser; errors are fine, crashes and hangs are not.
+// Run with `go test ./internal/generator/flatbuffersc -fuzz FuzzParseFbs`.
+func FuzzParseFbs(f *testing.F) {
+	f.Add(testSchema)
+	f.Add("table Entity { id:ulong; name:string; }")
+	f.Add("namespace ns;\n/// objectbox:relation(name=rel,to=Target)\ntable Source { id:ulong; }\ntable Target { id:ulong; }")
+	f.Add("table T { v:[ubyte] (id: 1); }")
+
+	f.Fuzz(func(t *testing.T, source string) {
+		var file = filepath.Join(t.TempDir(), "fuzz.fbs")
+		if err := ioutil.WriteFile(file, []byte(source), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		schema, err := ParseSchemaFile(file)
+		if err != nil {
+			return
+		}
+
+		// walk the whole reflection tree the same way the C/C++ schema reader does
+		var object reflection.Object
+		var field reflection.Field
+		for i := 0; i < schema.ObjectsLength(); i++ {
+			if !schema.Objects(&object, i) {
+				t.Fatalf("can't read object %d", i)
+			}
+			_ = object.Name()
+			for j := 0; j < object.DocumentationLength(); j++ {
+				_ = object.Documentation(j)
+			}
+			for j := 0; j < object.FieldsLength(); j++ {
+				if !object.Fields(&field, j) {
+					t.Fatalf("can't read field %d of object %d", j, i)
+				}
+				_ = field.Name()
+				_ = field.Type(nil)
+				for k := 0; k < field.DocumentationLength(); k++ {
+					_ = field.Documentation(k)
+				}
+			}
+		}
+
+		var enum reflection.Enum
+		for i := 0; i < schema.EnumsLength(); i++ {
+			if !schema.Enums(&enum, i) {
+				t.Fatalf("can't read enum %d", i)
+			}
+			_ = enum.Name()
+		}
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package flatbuffersc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
+)
+
+// includeRegex matches an include statement, e.g. `include "common.fbs";`, capturing the included file name
+var includeRegex = regexp.MustCompile(`^\s*include\s+"([^"]*)"\s*;`)
+
+// checkIncludes verifies all (transitively) included files can be found, the same way as the FlatBuffers parser looks
+// them up, and that they don't include each other in a cycle. The parser itself would silently ignore a cycle and
+// only report the name of an included file it can't find, without the directories it has looked in.
+// Returns the absolute paths of all the included files.
+func checkIncludes(filename string, includeDirs []string) ([]string, error) {
+	var checked = make(map[string]bool)
+	var stack []string
+	var included []string
+
+	var check func(file string) error
+	check = func(file string) error {
+		if absPath, err := filepath.Abs(file); err == nil {
+			file = absPath
+		}
+
+		for i, f := range stack {
+			if f == file {
+				return fmt.Errorf("include cycle: %s", strings.Join(append(stack[i:], file), " -> "))
+			}
+		}
+		if checked[file] {
+			return nil
+		}
+
+		includes, err := readIncludes(file)
+		if err != nil {
+			return err
+		}
+
+		stack = append(stack, file)
+		for _, name := range includes {
+			var searchPath = append([]string{filepath.Dir(file)}, includeDirs...)
+			searchPath = append(searchPath, ".")
+			var found string
+			for _, dir := range searchPath {
+				if path := filepath.Join(dir, filepath.FromSlash(name)); fileExists(path) {
+					found = path
+					break
+				}
+			}
+			if len(found) == 0 {
+				return fmt.Errorf("%s: unable to locate include file %s, searched in: %s", file, name, strings.Join(searchPath, ", "))
+			}
+			if err = check(found); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		checked[file] = true
+		if len(stack) > 0 {
+			included = append(included, file)
+		}
+		return nil
+	}
+
+	if err := check(filename); err != nil {
+		return nil, err
+	}
+	return included, nil
+}
+
+// readIncludes returns the names of files included by the given schema file
+func readIncludes(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load file: %s", file)
+	}
+	defer f.Close()
+
+	var includes []string
+	var scanner = bufio.NewScanner(f)
+	for scanner.Scan() {
+		if match := includeRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			includes = append(includes, match[1])
+		}
+	}
+	return includes, scanner.Err()
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// DeclaredIn returns true if the object of a schema parsed by ParseSchemaFileWithIncludes() has been declared in the
+// given (parsed) schema file itself, i.e. not in one of the included files.
+func DeclaredIn(object *reflection.Object, filename string) bool {
+	// declaration files are relative to the directory of the parsed file, see fbs_schema_parse_file_with_includes()
+	return string(object.DeclarationFile()) == "//"+filepath.Base(filename)
+}
@@ -0,0 +1,73 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// FormatModel rewrites an existing model JSON file in the canonical format (see model.ModelInfo.MarshalCanonical()),
+// e.g. a file written by an older generator version or merged by hand, so that later changes have a minimal diff.
+// options.InPath is the model file or its directory. The model itself isn't changed.
+// Returns the model file path and whether its content has changed.
+func FormatModel(options Options) (string, bool, error) {
+	if err := options.normalizePaths(); err != nil {
+		return "", false, err
+	}
+
+	modelFile, err := findModelFile(options)
+	if err != nil {
+		return "", false, err
+	}
+
+	original, err := ioutil.ReadFile(modelFile)
+	if err != nil {
+		return modelFile, false, err
+	}
+
+	modelInfo, err := model.LoadModelReadOnly(modelFile)
+	if err != nil {
+		return modelFile, false, err
+	}
+
+	if err = modelInfo.Validate(); err != nil {
+		return modelFile, false, fmt.Errorf("can't format model file %s: %s", modelFile, err)
+	}
+
+	// only the format changes, e.g. the external mapping is kept as is instead of being recreated like model.Write() does
+	formatted, err := modelInfo.MarshalCanonical()
+	if err != nil {
+		return modelFile, false, err
+	}
+
+	if bytes.Equal(original, formatted) {
+		return modelFile, false, nil
+	}
+
+	if err = ioutil.WriteFile(modelFile, formatted, 0644); err != nil {
+		return modelFile, false, fmt.Errorf("can't write model file %s: %s", modelFile, err)
+	}
+	return modelFile, true, nil
+}
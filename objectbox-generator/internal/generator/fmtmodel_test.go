@@ -0,0 +1,84 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestFormatModel(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "fmt-model")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	fixture.Generate(t, schemaFile, &cgenerator.CGenerator{LangVersion: 11})
+	var modelFile = generator.ModelInfoFile(dir)
+	canonical, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+	assert.True(t, strings.HasSuffix(string(canonical), "}\n"))
+
+	file, changed, err := generator.FormatModel(generator.Options{InPath: dir})
+	assert.NoErr(t, err)
+	assert.Eq(t, modelFile, file)
+	assert.True(t, !changed)
+
+	// a file edited by hand: different indentation, unsorted retired UIDs, no trailing newline
+	var content map[string]interface{}
+	assert.NoErr(t, json.Unmarshal(canonical, &content))
+	content["retiredEntityUids"] = []int{30, 10, 20}
+	content["retiredRelationUids"] = nil
+	edited, err := json.MarshalIndent(content, "", "\t")
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(modelFile, edited, 0600))
+
+	_, changed, err = generator.FormatModel(generator.Options{InPath: modelFile})
+	assert.NoErr(t, err)
+	assert.True(t, changed)
+	formatted, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+	var expected = strings.Replace(string(canonical), `"retiredEntityUids": [],`, "\"retiredEntityUids\": [\n    10,\n    20,\n    30\n  ],", 1)
+	assert.Eq(t, expected, string(formatted))
+
+	_, changed, err = generator.FormatModel(generator.Options{InPath: modelFile})
+	assert.NoErr(t, err)
+	assert.True(t, !changed)
+
+	_, _, err = generator.FormatModel(generator.Options{InPath: filepath.Join(dir, "missing")})
+	assert.Err(t, err)
+
+	// only the marshalled copy is sorted, not the model itself
+	var modelInfo = &model.ModelInfo{RetiredEntityUids: []model.Uid{30, 10, 20}}
+	_, err = modelInfo.MarshalCanonical()
+	assert.NoErr(t, err)
+	assert.Eq(t, []model.Uid{30, 10, 20}, modelInfo.RetiredEntityUids)
+	assert.True(t, modelInfo.RetiredIndexUids == nil)
+}
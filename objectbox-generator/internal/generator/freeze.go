@@ -0,0 +1,83 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// FreezeFileName is a file next to the model JSON file freezing the model, same as Options.Frozen, e.g. committed to
+// a release branch. Its content (optional) is included in the error message, e.g. the reason or whom to ask.
+const FreezeFileName = "objectbox-model.lock"
+
+// frozenModel is the stored model before processing the sources, if the model is frozen, see Options.Frozen
+type frozenModel struct {
+	stored *model.ModelInfo
+	reason string // e.g. "by -frozen"
+}
+
+// loadFrozenModel reads the stored model if it's frozen by Options.Frozen or a FreezeFileName. Returns nil otherwise.
+func loadFrozenModel(options Options) (*frozenModel, error) {
+	var reason string
+	var freezeFile = filepath.Join(filepath.Dir(options.ModelInfoFile), FreezeFileName)
+	if content, err := ioutil.ReadFile(freezeFile); err == nil {
+		reason = "by " + freezeFile
+		if text := strings.TrimSpace(string(content)); len(text) > 0 {
+			reason += " (" + text + ")"
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("can't read %s: %s", freezeFile, err)
+	} else if options.Frozen {
+		reason = "by -frozen"
+	} else {
+		return nil, nil
+	}
+
+	stored, err := model.LoadModelReadOnly(options.ModelInfoFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't init ModelInfo: %s", err)
+	}
+	return &frozenModel{stored: stored, reason: reason}, nil
+}
+
+// check fails if the current model changes the schema of the frozen one, e.g. adds an entity or a property (assigning
+// new IDs/UIDs), removes or renames them, or changes their types or flags
+func (frozen *frozenModel) check(options Options, current *model.ModelInfo) error {
+	if frozen == nil || frozen.stored.SchemaFingerprint() == current.SchemaFingerprint() {
+		return nil
+	}
+
+	var changes []string
+	for _, change := range DiffModels(frozen.stored, current) {
+		changes = append(changes, change.String())
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "the schema differs") // e.g. an external name, not listed by DiffModels()
+	}
+	return messages.ModelFrozen.Errorf(options.ModelInfoFile, frozen.reason, strings.Join(changes, "; "))
+}
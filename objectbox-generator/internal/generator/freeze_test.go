@@ -0,0 +1,90 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestFrozenModel(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "frozen")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var modelFile = generator.ModelInfoFile(dir)
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// a frozen model can't be created
+	options.Frozen = true
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+	err := generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "OBXG4006"))
+	assert.True(t, strings.Contains(err.Error(), "add entity Task"))
+	_, err = os.Stat(modelFile)
+	assert.True(t, os.IsNotExist(err))
+
+	options.Frozen = false
+	assert.NoErr(t, generator.Process(options))
+	modelJSON, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+
+	// unchanged sources are fine
+	options.Frozen = true
+	assert.NoErr(t, generator.Process(options))
+
+	// adding a property fails without writing anything
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	assert.NoErr(t, os.Remove(filepath.Join(dir, "schema.obx.hpp")))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "by -frozen"))
+	assert.True(t, strings.Contains(err.Error(), "add property Task.text"))
+	_, err = os.Stat(filepath.Join(dir, "schema.obx.hpp"))
+	assert.True(t, os.IsNotExist(err))
+	current, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(modelJSON), string(current))
+
+	// the lock file freezes the model as well, the validation fails too
+	options.Frozen = false
+	fixture.WriteFile(t, filepath.Join(dir, generator.FreezeFileName), "release 1.2\n")
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), generator.FreezeFileName+" (release 1.2)"))
+	_, err = generator.Validate(options)
+	assert.Err(t, err)
+
+	assert.NoErr(t, os.Remove(filepath.Join(dir, generator.FreezeFileName)))
+	assert.NoErr(t, generator.Process(options))
+}
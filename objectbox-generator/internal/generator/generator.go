@@ -22,15 +22,21 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
@@ -49,13 +55,31 @@ func ModelInfoFile(dir string) string {
 	return filepath.Join(dir, "objectbox-model.json")
 }
 
+// findModelFile returns options.ModelInfoFile, if given, or the model JSON file given by options.InPath, either
+// directly or as the directory containing it
+func findModelFile(options Options) (string, error) {
+	var modelFile = options.InPath
+	if len(options.ModelInfoFile) > 0 {
+		modelFile = options.ModelInfoFile
+	} else if info, err := os.Stat(options.InPath); err != nil {
+		return "", err
+	} else if info.IsDir() {
+		modelFile = ModelInfoFile(options.InPath)
+		if _, err := os.Stat(modelFile); os.IsNotExist(err) {
+			return "", fmt.Errorf("model file %s not found", modelFile)
+		}
+	}
+	return modelFile, nil
+}
+
 // CodeGenerator interface is used to abstract per-language generators, e.g. for Go, C, C++, etc
 type CodeGenerator interface {
 	// BindingFiles returns the names of language binding files for the given entity file.
+	// Fails if the file names depend on the source contents (e.g. per-entity files) and it can't be parsed.
 	// TODO "binding files" is not intuitive name (especially without mentioning "**language** binding").
 	//      Rename to "language", "generated" or "output" files instead?
 	//      Rename functions, variables, etc. accordingly.
-	BindingFiles(forFile string, options Options) []string
+	BindingFiles(forFile string, options Options) ([]string, error)
 
 	// ModelFile returns the language-specific model source file for the given JSON info file path
 	ModelFile(forFile string, options Options) string
@@ -75,10 +99,61 @@ type CodeGenerator interface {
 
 	// WriteModelBindingFile generates and writes binding source code file for model setup
 	WriteModelBindingFile(options Options, mergedModel *model.ModelInfo) error
+
+	// TemplateVersion returns an identifier of the templates used by this generator, see generator.TemplateVersion()
+	TemplateVersion() string
+}
+
+// StrictChecker may be implemented by a CodeGenerator that generates incomplete code for some constructs, e.g. a
+// property type it can't read back, to report them as errors when running with Options.Strict.
+type StrictChecker interface {
+	// Unsupported returns a description of each construct in the given (parsed) source the generated code doesn't handle
+	Unsupported(currentModel *model.ModelInfo) []string
+}
+
+// SourcesResolver may be implemented by a CodeGenerator that needs to know about the entities declared in all the
+// sources of a run, e.g. to resolve relations targeting entities declared in a different file.
+// ResolveSources is called before ParseSource() for the individual files, only when processing multiple files at once.
+type SourcesResolver interface {
+	ResolveSources(entities []*model.Entity)
 }
 
-// WriteFile writes data to targetFile, while using permissions either from the targetFile or permSource
+// CompatibilityOptionsProvider may be implemented by a CodeGenerator with options that change how the generated code
+// stores and reads data, e.g. whether empty strings are stored as null. The options are recorded in the model JSON
+// file and changing them between runs fails unless confirmed by Options.AcceptOptionsChange.
+type CompatibilityOptionsProvider interface {
+	// CompatibilityOptions returns the current values of such options, indexed by their (command line flag) name
+	CompatibilityOptions() map[string]string
+}
+
+// WriteFile writes data to targetFile, while using permissions either from the targetFile or permSource.
+// Code generators should call Options.WriteFile() instead, honoring Options.AtomicWrites and Options.BackupFiles.
 func WriteFile(file string, data []byte, permSource string) error {
+	return Options{}.WriteFile(file, data, permSource)
+}
+
+// WriteFile writes data to targetFile, while using permissions either from the targetFile or permSource. With
+// AtomicWrites, the data is written to a temporary file renamed to the target; with BackupFiles, the previous content
+// is kept as "<file>.bak" if it differs; with BuildInfo, the file is stamped with permSource as its input; with
+// Checksums, a file edited manually since it was generated isn't overwritten unless Force is set, see ManualEditError.
+func (options Options) WriteFile(file string, data []byte, permSource string) error {
+	if options.BuildInfo {
+		var err error
+		if data, err = options.stampBuildInfo(file, data, permSource); err != nil {
+			return fmt.Errorf("can't stamp build info into %s: %s", file, err)
+		}
+	}
+
+	if options.Checksums {
+		data = appendChecksum(data)
+	}
+	if err := options.checkManualEdits(file, data); err != nil {
+		return err
+	}
+	if options.checkEditsOnly {
+		return nil
+	}
+
 	var perm os.FileMode
 	// copy permissions either from the existing file or from the source file
 	if info, _ := os.Stat(file); info != nil {
@@ -89,84 +164,347 @@ func WriteFile(file string, data []byte, permSource string) error {
 		return err
 	}
 
-	return ioutil.WriteFile(file, data, perm)
+	if options.BackupFiles {
+		if previous, err := ioutil.ReadFile(file); err == nil && !bytes.Equal(previous, data) {
+			if err = options.writeFile(file+".bak", previous, perm); err != nil {
+				return fmt.Errorf("can't back up %s: %s", file, err)
+			}
+		}
+	}
+
+	return options.writeFile(file, data, perm)
 }
 
-// Process is the main API method of the package
-// it takes source file & model-information file paths and generates bindings (as a sibling file to the source file)
-func Process(options Options) error {
-	var err error
+func (options Options) writeFile(file string, data []byte, perm os.FileMode) error {
+	if !options.AtomicWrites {
+		return ioutil.WriteFile(file, data, perm)
+	}
 
-	// Ensure output directory is existing or create
-	if len(options.OutPath) != 0 {
-		err := os.MkdirAll(options.OutPath, 0750)
-		if err != nil {
-			return fmt.Errorf("can't create output path '"+options.OutPath+"': %s", err)
+	// the temporary file is hidden (e.g. ignored by go build) and in the same directory so that rename is atomic
+	temp, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = temp.Write(data); err == nil {
+		if err = temp.Chmod(perm); err == nil {
+			err = temp.Sync()
 		}
 	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+	}
+	return err
+}
 
-	// Ensure output header directory is existing or create
-	if len(options.OutHeadersPath) != 0 {
-		err := os.MkdirAll(options.OutHeadersPath, 0750)
+// writeModel writes the model JSON file, honoring Options.AtomicWrites
+func writeModel(options Options, modelInfo *model.ModelInfo) error {
+	if !options.AtomicWrites {
+		return modelInfo.Write()
+	}
+	return modelInfo.WriteUsing(func(file string, data []byte) error {
+		info, err := os.Stat(file)
 		if err != nil {
-			return fmt.Errorf("can't create output headers path '"+options.OutPath+"': %s", err)
+			return err
 		}
+		return options.writeFile(file, data, info.Mode())
+	})
+}
+
+// Process is the main API method of the package
+// it takes source file & model-information file paths and generates bindings (as a sibling file to the source file)
+func Process(options Options) error {
+	if options.PerPackage {
+		return processPackages(options)
 	}
 
-	if PathIsDirOrPattern(options.InPath) {
-		var additional string
-		var cleanPath = options.InPath
-		if len(options.OutPath) != 0 {
-			additional = "of output path (-out=" + options.OutPath + ") "
-			cleanPath = options.OutPath
+	if len(options.BundleFile) > 0 {
+		return ProcessBundle(options)
+	}
+
+	// lint before generating so that nothing is written if there are lint errors
+	if options.LintRules != nil {
+		issues, err := LintSources(options)
+		for _, issue := range issues {
+			fmt.Fprintln(options.output(), issue)
 		}
-		fmt.Printf("Requested to generate for directory/pattern %s, performing an implicit cleanup %sfirst\n", options.InPath, additional)
-		err = Clean(options.CodeGenerator, cleanPath)
 		if err != nil {
 			return err
 		}
 	}
 
-	// if no random generator is provided, we create and seed a new one
-	if options.Rand == nil {
-		options.Rand = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	_, err := process(options, false)
+	return err
+}
+
+// Validate performs the same steps as Process() - reading the sources and merging them with the stored model - but
+// doesn't write any files (not even the model JSON), except for the Options.ManifestFile, if set. Returns the resulting model, e.g. to compare with the stored one.
+func Validate(options Options) (*model.ModelInfo, error) {
+	if options.PerPackage {
+		return nil, errors.New("validating isn't supported when generating per package, validate each package instead")
+	}
+	return process(options, true)
+}
+
+func process(options Options, dryRun bool) (*model.ModelInfo, error) {
+	var err error
+
+	if err = options.normalizePaths(); err != nil {
+		return nil, err
 	}
 
 	if len(options.ModelInfoFile) == 0 {
 		options.ModelInfoFile = ModelInfoFile(filepath.Dir(options.InPath))
 	}
 
+	if options.hasEntityFilter() && len(options.ManifestFile) > 0 {
+		return nil, errors.New("a manifest file is not supported when generating only some of the entities")
+	}
+
+	frozen, err := loadFrozenModel(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect all the errors (or check the frozen model, the entity names to generate or all the generated files for
+	// manual edits) without writing anything first, the files are only written if there are none
+	var checkEdits = options.Checksums && !options.Force
+	if (options.KeepGoing || frozen != nil || options.hasEntityFilter() || checkEdits) && !dryRun {
+		var validateOptions = options
+		validateOptions.ManifestFile = ""
+		validateOptions.checkEditsOnly = checkEdits
+		if _, err = process(validateOptions, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = prepareTargets(options); err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		if err = prepareOutput(options); err != nil {
+			return nil, err
+		}
+	}
+
+	// if no random generator is provided, we create and seed a new one
+	if options.Rand == nil {
+		options.Rand = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	}
+
 	var modelInfo *model.ModelInfo
 
-	modelInfo, err = model.LoadOrCreateModel(options.ModelInfoFile)
+	if dryRun {
+		modelInfo, err = model.LoadModelReadOnly(options.ModelInfoFile)
+	} else {
+		modelInfo, err = model.LoadOrCreateModel(options.ModelInfoFile)
+	}
 	if err != nil {
-		return fmt.Errorf("can't init ModelInfo: %s", err)
+		return nil, fmt.Errorf("can't init ModelInfo: %s", err)
 	}
 
 	modelInfo.Rand = options.Rand
+	modelInfo.DeterministicUids = options.DeterministicUids
+	modelInfo.UidSalt = options.UidSalt
+	modelInfo.AllowDrop = options.AllowDrop
 	defer modelInfo.Close()
 
 	if err = modelInfo.Validate(); err != nil {
-		return fmt.Errorf("invalid ModelInfo loaded: %s", err)
+		return nil, fmt.Errorf("invalid ModelInfo loaded: %s", err)
 	}
 
 	// if the model is valid, upgrade it to the latest version
 	modelInfo.MinimumParserVersion = model.ModelVersion
 	modelInfo.ModelVersion = model.ModelVersion
+	modelInfo.UpgradeSchemaVersion()
+	var schemaFingerprint = modelInfo.SchemaFingerprint()
 
-	if err = createBinding(options, modelInfo); err != nil {
-		return err
+	if err = checkTenantPrefix(options, modelInfo); err != nil {
+		return nil, err
 	}
 
-	if err = createModel(options, modelInfo); err != nil {
-		return err
+	if err = checkCompatibilityOptions(options, modelInfo); err != nil {
+		return nil, err
+	}
+
+	// all targets merge their sources into the same model, which is written only once, see createModel()
+	for _, codeGenerator := range options.Targets() {
+		var targetOptions = options
+		targetOptions.CodeGenerator = codeGenerator
+		clearMeta(modelInfo) // merged again by each target
+		if err = createBinding(targetOptions, modelInfo, dryRun); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = checkEntityFilter(options, modelInfo); err != nil {
+		return nil, err
+	}
+
+	if err = mergeModuleModels(options, modelInfo); err != nil {
+		return nil, err
+	}
+
+	if err = createModel(options, modelInfo, schemaFingerprint, frozen, dryRun); err != nil {
+		return nil, err
+	}
+
+	// written even in the dry-run mode, listing the files that would be generated
+	if len(options.ManifestFile) > 0 {
+		manifest, err := BuildManifest(options)
+		if err != nil {
+			return nil, fmt.Errorf("can't build the manifest: %s", err)
+		}
+		if err = manifest.Write(options.ManifestFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return modelInfo, nil
+}
+
+// prepareTargets checks that the Options.CodeGenerators (if there are multiple) can be run together and lets them
+// share the parsed sources
+func prepareTargets(options Options) error {
+	if len(options.CodeGenerators) == 0 {
+		return nil
+	}
+
+	if options.CodeGenerator != nil {
+		return errors.New("only one of CodeGenerator and CodeGenerators may be set")
+	}
+	if len(options.ManifestFile) > 0 {
+		return errors.New("a manifest file is not supported when generating for multiple targets at once")
+	}
+
+	// e.g. C and C++ both write objectbox-model.h
+	var modelFiles = make(map[string]bool)
+	for _, codeGenerator := range options.CodeGenerators {
+		var modelFile = codeGenerator.ModelFile(options.ModelInfoFile, options)
+		if modelFiles[modelFile] {
+			return fmt.Errorf("can't generate for multiple targets writing the same files, e.g. %s", modelFile)
+		}
+		modelFiles[modelFile] = true
 	}
 
+	var cache = NewParseCache()
+	for _, codeGenerator := range options.CodeGenerators {
+		if user, ok := codeGenerator.(ParseCacheUser); ok {
+			user.SetParseCache(cache)
+		}
+	}
 	return nil
 }
 
-func createBinding(options Options, storedModel *model.ModelInfo) error {
-	return pathForEach(options.InPath, func(filePath string) error {
+// checkCompatibilityOptions compares the CompatibilityOptions of the code generators to the ones recorded in the model
+// and records the current ones. Models written by previous generator versions don't have any options recorded yet.
+func checkCompatibilityOptions(options Options, modelInfo *model.ModelInfo) error {
+	var current map[string]string
+	for _, codeGenerator := range options.Targets() {
+		provider, ok := codeGenerator.(CompatibilityOptionsProvider)
+		if !ok {
+			continue
+		}
+		if current == nil {
+			current = provider.CompatibilityOptions()
+			continue
+		}
+		// only one set of options can be recorded, i.e. all targets must read the data the same way
+		for name, value := range provider.CompatibilityOptions() {
+			if recorded, found := current[name]; found && recorded != value {
+				return fmt.Errorf("targets generated at once must use the same options affecting the stored data: %s differs (%q and %q)",
+					name, recorded, value)
+			}
+			current[name] = value
+		}
+	}
+	if current == nil {
+		return nil
+	}
+
+	if modelInfo.GeneratorOptions != nil {
+		var names []string
+		for name := range current {
+			names = append(names, name)
+		}
+		for name := range modelInfo.GeneratorOptions {
+			if _, found := current[name]; !found {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		var changes []string
+		for _, name := range names {
+			if recorded, value := modelInfo.GeneratorOptions[name], current[name]; recorded != value {
+				changes = append(changes, fmt.Sprintf("%s changed from %q to %q", name, recorded, value))
+			}
+		}
+
+		if len(changes) > 0 {
+			if !options.AcceptOptionsChange {
+				return messages.OptionsChanged.Errorf(options.ModelInfoFile, strings.Join(changes, ", "))
+			}
+			log.Printf("Notice - accepting changed generator options: %s", strings.Join(changes, ", "))
+		}
+	}
+
+	modelInfo.GeneratorOptions = current
+	return nil
+}
+
+// prepareOutput creates the output directories and cleans previously generated files
+func prepareOutput(options Options) error {
+	// Ensure output directory is existing or create
+	if len(options.OutPath) != 0 {
+		err := os.MkdirAll(options.OutPath, 0750)
+		if err != nil {
+			return fmt.Errorf("can't create output path '"+options.OutPath+"': %s", err)
+		}
+	}
+
+	// Ensure output header directory is existing or create
+	if len(options.OutHeadersPath) != 0 {
+		err := os.MkdirAll(options.OutHeadersPath, 0750)
+		if err != nil {
+			return fmt.Errorf("can't create output headers path '"+options.OutPath+"': %s", err)
+		}
+	}
+
+	// the files of the entities not selected are kept as they are
+	if PathIsDirOrPattern(options.InPath) && !options.hasEntityFilter() {
+		var additional string
+		var cleanPath = options.InPath
+		if len(options.OutPath) != 0 {
+			additional = "of output path (-out=" + options.OutPath + ") "
+			cleanPath = options.OutPath
+		}
+		fmt.Fprintf(options.output(), "Requested to generate for directory/pattern %s, performing an implicit cleanup %sfirst\n", options.InPath, additional)
+		for _, codeGenerator := range options.Targets() {
+			if err := options.clean(codeGenerator, cleanPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func createBinding(options Options, storedModel *model.ModelInfo, dryRun bool) error {
+	var errs ErrorList
+	if PathIsDirOrPattern(options.InPath) {
+		if err := errs.add(options, resolveSources(options, storedModel)); err != nil {
+			return err
+		}
+	}
+
+	err := pathForEach(options.InPath, func(filePath string) error {
 		if !options.CodeGenerator.IsSourceFile(filePath) {
 			return nil
 		}
@@ -176,21 +514,36 @@ func createBinding(options Options, storedModel *model.ModelInfo) error {
 			entity.Meta = nil
 		}
 
-		currentModel, err := options.CodeGenerator.ParseSource(filePath)
+		currentModel, err := parseSource(options, filePath)
 		if err != nil {
-			return err
+			return errs.add(options, sourceError(filePath, DiagnosticParse, err))
+		}
+
+		if err = checkStrict(options, filePath, currentModel); err != nil {
+			return errs.add(options, sourceError(filePath, DiagnosticStrict, err))
 		}
 
 		if err = mergeBindingWithModelInfo(currentModel, storedModel); err != nil {
-			return fmt.Errorf("can't merge model information: %s", err)
+			return errs.add(options, sourceError(filePath, DiagnosticMerge, fmt.Errorf("can't merge model information: %s", err)))
 		}
+		storedModel.ConstantGroups = currentModel.ConstantGroups
 
 		if err = storedModel.Finalize(); err != nil {
-			return fmt.Errorf("model finalization failed: %s", err)
+			if options.KeepGoing {
+				// drop the invalid entities so that the model can be finalized when processing the remaining files
+				for _, entity := range storedModel.EntitiesWithMeta() {
+					_ = storedModel.RemoveEntity(entity)
+				}
+			}
+			return errs.add(options, sourceError(filePath, DiagnosticModel, fmt.Errorf("model finalization failed: %s", err)))
 		}
 
-		if err = options.CodeGenerator.WriteBindingFiles(filePath, options, storedModel); err != nil {
-			return err
+		// a source declaring only constants is generated too, unless selecting entities
+		var hasConstants = len(storedModel.ConstantGroups) > 0 && !options.hasEntityFilter()
+		if (!dryRun || options.checkEditsOnly) && (anyEntitySelected(options, storedModel.EntitiesWithMeta()) || hasConstants) {
+			if err = options.CodeGenerator.WriteBindingFiles(filePath, options, storedModel); err != nil {
+				return sourceError(filePath, DiagnosticWrite, err)
+			}
 		}
 
 		for _, entity := range storedModel.EntitiesWithMeta() {
@@ -199,15 +552,140 @@ func createBinding(options Options, storedModel *model.ModelInfo) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	return errs.err()
 }
 
-func createModel(options Options, modelInfo *model.ModelInfo) error {
+// anyEntitySelected checks whether the binding files of a source declaring the given entities should be written
+func anyEntitySelected(options Options, entities []*model.Entity) bool {
+	for _, entity := range entities {
+		if options.EntitySelected(entity.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEntityFilter fails if Options.OnlyEntities or Options.ExcludeEntities name an entity not declared in the sources
+func checkEntityFilter(options Options, modelInfo *model.ModelInfo) error {
+	var names = make(map[string]bool)
+	for _, entity := range modelInfo.Entities {
+		if entity.CurrentlyPresent {
+			names[strings.ToLower(strings.TrimPrefix(entity.Name, options.TenantPrefix))] = true
+		}
+	}
+	for _, name := range append(append([]string{}, options.OnlyEntities...), options.ExcludeEntities...) {
+		if !names[strings.ToLower(name)] {
+			return fmt.Errorf("can't select entity %s to generate: it's not declared in the sources", name)
+		}
+	}
+	return nil
+}
+
+// checkStrict fails if running with Options.Strict and the code generator doesn't fully support the given source
+func checkStrict(options Options, filePath string, currentModel *model.ModelInfo) error {
+	if !options.Strict {
+		return nil
+	}
+	if checker, ok := options.CodeGenerator.(StrictChecker); ok {
+		if unsupported := checker.Unsupported(currentModel); len(unsupported) > 0 {
+			return fmt.Errorf("strict mode: %s contains constructs not supported by the generated code: %s",
+				filePath, strings.Join(unsupported, "; "))
+		}
+	}
+	return nil
+}
+
+// resolveSources reads all the sources processed in a single run to check that relations targeting entities in other
+// files can be resolved. All the entities are merged into the stored model up front (in the same order as they would be
+// by processing the files one by one) so that relations can be merged regardless of the order the files are read.
+func resolveSources(options Options, storedModel *model.ModelInfo) error {
+	var entities []*model.Entity
+	var sourceFiles = make(map[*model.Entity]string)
+	err := pathForEach(options.InPath, func(filePath string) error {
+		if !options.CodeGenerator.IsSourceFile(filePath) {
+			return nil
+		}
+
+		currentModel, err := parseSource(options, filePath)
+		if err != nil {
+			if options.KeepGoing {
+				return nil // reported when the file is processed by createBinding()
+			}
+			return sourceError(filePath, DiagnosticParse, err)
+		}
+
+		for _, entity := range currentModel.Entities {
+			entities = append(entities, entity)
+			sourceFiles[entity] = filePath
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var declared = make(map[string]bool) // lower-case entity name => true
+	for _, entity := range entities {
+		declared[strings.ToLower(entity.Name)] = true
+	}
+
+	var errs ErrorList
+	for _, entity := range entities {
+		for _, property := range entity.Properties {
+			if len(property.RelationTarget) > 0 && !declared[strings.ToLower(property.RelationTarget)] {
+				if err = errs.add(options, sourceError(sourceFiles[entity], DiagnosticRelation, messages.UndeclaredTarget.Errorf(entity.Name, property.Name, sourceFiles[entity], property.RelationTarget))); err != nil {
+					return err
+				}
+			}
+		}
+		for _, relation := range entity.Relations {
+			if relation.Target != nil && !declared[strings.ToLower(relation.Target.Name)] {
+				if err = errs.add(options, sourceError(sourceFiles[entity], DiagnosticRelation, messages.UndeclaredTarget.Errorf(entity.Name, relation.Name, sourceFiles[entity], relation.Target.Name))); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if err = mergeBindingWithModelInfo(&model.ModelInfo{Entities: entities}, storedModel); err != nil {
+		return fmt.Errorf("can't merge model information: %s", err)
+	}
+
+	// the binding is created by merging the individual files again - clear the meta information set by this merge
+	clearMeta(storedModel)
+
+	if resolver, ok := options.CodeGenerator.(SourcesResolver); ok {
+		resolver.ResolveSources(entities)
+	}
+	return nil
+}
+
+// clearMeta removes the (code generator specific) meta information set by merging sources into the model
+func clearMeta(storedModel *model.ModelInfo) {
+	for _, entity := range storedModel.Entities {
+		entity.Meta = nil
+		for _, property := range entity.Properties {
+			property.Meta = nil
+		}
+		for _, relation := range entity.Relations {
+			relation.Meta = nil
+		}
+	}
+}
+
+func createModel(options Options, modelInfo *model.ModelInfo, schemaFingerprint string, frozen *frozenModel, dryRun bool) error {
 	// clean entities not present in the current run - ONLY if running for a path
 	if PathIsDirOrPattern(options.InPath) {
 		removedEntities := make([]*model.Entity, 0)
 		for _, entity := range modelInfo.Entities {
 			if !entity.CurrentlyPresent {
-				fmt.Printf("Removing missing entity %s %s from the model\n", entity.Name, entity.Id)
+				fmt.Fprintf(options.output(), "Removing missing entity %s %s from the model\n", entity.Name, entity.Id)
 				removedEntities = append(removedEntities, entity)
 			}
 		}
@@ -223,21 +701,69 @@ func createModel(options Options, modelInfo *model.ModelInfo) error {
 		}
 	}
 
-	if err := modelInfo.Write(); err != nil {
-		return fmt.Errorf("can't write model-info file %s: %s", options.ModelInfoFile, err)
+	// checked only after all sources have been processed, i.e. when entity flags across all files are up-to-date
+	if err := modelInfo.CheckSyncRelations(); err != nil {
+		return err
 	}
 
-	return options.CodeGenerator.WriteModelBindingFile(options, modelInfo)
+	if err := modelInfo.CheckReserved(); err != nil {
+		return err
+	}
+
+	// after removing the missing entities, which changes the schema as well
+	if err := frozen.check(options, modelInfo); err != nil {
+		return err
+	}
+	modelInfo.UpdateSchemaVersion(schemaFingerprint)
+
+	if dryRun && !options.checkEditsOnly {
+		return nil
+	}
+
+	if !dryRun {
+		if err := writeModel(options, modelInfo); err != nil {
+			return fmt.Errorf("can't write model-info file %s: %s", options.ModelInfoFile, err)
+		}
+	}
+
+	for _, codeGenerator := range options.Targets() {
+		if err := codeGenerator.WriteModelBindingFile(options, modelInfo); err != nil {
+			return err
+		}
+	}
+
+	if len(options.AdminMetadataFile) > 0 {
+		return writeAdminMetadata(options, modelInfo)
+	}
+	return nil
 }
 
 // Clean removes generated files in the given path.
-// Removes *.obx.* and objectbox-model.[go|h|...] but keeps objectbox-model.json
+// Removes *.obx.* and objectbox-model.[go|h|...], including their backups (see Options.BackupFiles), but keeps
+// objectbox-model.json
 func Clean(codeGenerator CodeGenerator, path string) error {
+	return Options{}.clean(codeGenerator, path)
+}
+
+// CleanTo removes generated files like Clean(), printing the removed files to the given writer instead of stdout
+func CleanTo(output io.Writer, codeGenerator CodeGenerator, path string) error {
+	return Options{Output: output}.clean(codeGenerator, path)
+}
+
+// clean removes generated files, see Clean(), except for those edited manually (unless Force is set) so that writing
+// them fails with a ManualEditError instead of silently dropping the changes, see Options.Checksums
+func (options Options) clean(codeGenerator CodeGenerator, path string) error {
 	return pathForEach(path, func(filePath string) error {
-		if !codeGenerator.IsGeneratedFile(filePath) {
+		if !codeGenerator.IsGeneratedFile(strings.TrimSuffix(filePath, ".bak")) {
 			return nil
 		}
-		fmt.Printf("Removing %s\n", filePath)
+		if !options.Force && !strings.HasSuffix(filePath, ".bak") {
+			if source, err := ioutil.ReadFile(filePath); err == nil && isManuallyEdited(source) {
+				fmt.Fprintf(options.output(), "Keeping manually edited %s\n", filePath)
+				return nil
+			}
+		}
+		fmt.Fprintf(options.output(), "Removing %s\n", filePath)
 		return os.Remove(filePath)
 	})
 }
@@ -246,6 +772,8 @@ const recursionSuffix = "/..."
 
 // PathIsDirOrPattern checks whether the given path is a path pattern, a directory or a single file.
 func PathIsDirOrPattern(path string) bool {
+	path = NormalizePath(path)
+
 	// if it's a recursion pattern
 	if strings.HasSuffix(path, recursionSuffix) {
 		return true
@@ -268,6 +796,8 @@ func PathIsDirOrPattern(path string) bool {
 func pathForEach(path string, fn func(filePath string) error) error {
 	var recursive bool
 
+	path = NormalizePath(path)
+
 	// if it's a pattern
 	if strings.HasSuffix(path, recursionSuffix) {
 		recursive = true
@@ -0,0 +1,365 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestAtomicWritesAndBackups(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "atomic")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n text: string;\n}\n"), 0640))
+
+	var gen = &cgenerator.CGenerator{PlainC: true}
+	var options = generator.Options{
+		InPath:        schemaFile,
+		ModelInfoFile: generator.ModelInfoFile(dir),
+		CodeGenerator: gen,
+		AtomicWrites:  true,
+		BackupFiles:   true,
+	}
+	bindingFiles, err := gen.BindingFiles(schemaFile, options)
+	assert.NoErr(t, err)
+	var bindingFile = bindingFiles[0]
+	var listFiles = func() []string {
+		entries, err := ioutil.ReadDir(dir)
+		assert.NoErr(t, err)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	// nothing to back up on the first run, no temporary files are left behind
+	assert.NoErr(t, generator.Process(options))
+	assert.Eq(t, []string{"objectbox-model.h", "objectbox-model.json", "schema.fbs", "schema.obx.h"}, listFiles())
+	info, err := os.Stat(bindingFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, os.FileMode(0640), info.Mode())
+	previous, err := ioutil.ReadFile(bindingFile)
+	assert.NoErr(t, err)
+
+	// unchanged content isn't backed up
+	assert.NoErr(t, generator.Process(options))
+	assert.Eq(t, 4, len(listFiles()))
+
+	modelBefore, err := os.Stat(options.ModelInfoFile)
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n text: string;\n done: bool;\n}\n"), 0640))
+	assert.NoErr(t, generator.Process(options))
+
+	// the model JSON file has been replaced too, instead of updated in place
+	modelAfter, err := os.Stat(options.ModelInfoFile)
+	assert.NoErr(t, err)
+	assert.True(t, !os.SameFile(modelBefore, modelAfter))
+	assert.Eq(t, modelBefore.Mode(), modelAfter.Mode())
+
+	backup, err := ioutil.ReadFile(bindingFile + ".bak")
+	assert.NoErr(t, err)
+	assert.Eq(t, string(previous), string(backup))
+	current, err := ioutil.ReadFile(bindingFile)
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(current), "done"))
+
+	// backups are removed together with the generated files, the removed files are listed in the given output
+	var output bytes.Buffer
+	assert.NoErr(t, generator.CleanTo(&output, gen, dir))
+	assert.Eq(t, []string{"objectbox-model.json", "schema.fbs"}, listFiles())
+	assert.True(t, strings.Contains(output.String(), "Removing "+bindingFile+".bak\n"))
+}
+
+func TestEntityFilter(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "entity-filter")
+	defer remove()
+
+	fixture.WriteFile(t, filepath.Join(dir, "a.fbs"), "table Task {\n id: ulong;\n}\ntable Note {\n id: ulong;\n}\n")
+	fixture.WriteFile(t, filepath.Join(dir, "b.fbs"), "table Item {\n id: ulong;\n}\n")
+	var exists = func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	var removeGenerated = func() {
+		for _, name := range []string{"a.obx.hpp", "a.obx.cpp", "b.obx.hpp", "b.obx.cpp", "objectbox-model.h"} {
+			assert.NoErr(t, os.RemoveAll(filepath.Join(dir, name)))
+		}
+	}
+
+	var options = generator.Options{InPath: dir, ModelInfoFile: generator.ModelInfoFile(dir), OnlyEntities: []string{"item"}, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("b.obx.hpp"))
+	assert.True(t, !exists("a.obx.hpp"))
+	assert.True(t, exists("objectbox-model.h"))
+
+	// the model covers all the entities
+	storedModel, err := model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(storedModel.Entities))
+
+	// files of a source are written if any of its entities is selected; the others aren't cleaned up
+	options.OnlyEntities = nil
+	options.ExcludeEntities = []string{"Task", "Item"}
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("a.obx.hpp"))
+	assert.True(t, exists("b.obx.hpp"))
+
+	removeGenerated()
+	options.ExcludeEntities = []string{"Item"}
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("a.obx.hpp"))
+	assert.True(t, !exists("b.obx.hpp"))
+
+	// per-entity files
+	removeGenerated()
+	options.ExcludeEntities = nil
+	options.OnlyEntities = []string{"Note"}
+	options.OutPattern = "{{.Entity}}.obx.{{.Ext}}"
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("Note.obx.hpp"))
+	assert.True(t, !exists("Task.obx.hpp"))
+	assert.True(t, !exists("Item.obx.hpp"))
+
+	// unknown entities are reported before writing anything
+	assert.NoErr(t, os.Remove(filepath.Join(dir, "Note.obx.hpp")))
+	options.OnlyEntities = []string{"Note", "Missing"}
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "can't select entity Missing to generate"))
+	assert.True(t, !exists("Note.obx.hpp"))
+
+	options.OnlyEntities = []string{"Note"}
+	options.ManifestFile = filepath.Join(dir, "manifest.json")
+	assert.Err(t, generator.Process(options))
+}
+
+func TestCompatibilityOptions(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "options")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task { id: ulong; text: string; }\n")
+
+	var generate = func(codeGenerator *cgenerator.CGenerator, accept bool) (*model.ModelInfo, error) {
+		if err := generator.Process(generator.Options{InPath: schemaFile, CodeGenerator: codeGenerator, AcceptOptionsChange: accept}); err != nil {
+			return nil, err
+		}
+		return model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	}
+
+	// options are recorded on the first run
+	modelInfo, err := generate(&cgenerator.CGenerator{LangVersion: 14, Optional: "std::optional"}, false)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[string]string{"empty-string-as-null": "false", "nan-as-null": "false"}, modelInfo.GeneratorOptions)
+
+	// options only affecting the generated code aren't recorded, e.g. generating C and C++ from the same model
+	_, err = generate(&cgenerator.CGenerator{PlainC: true, Optional: "ptr"}, false)
+	assert.NoErr(t, err)
+
+	// changes must be confirmed
+	_, err = generate(&cgenerator.CGenerator{LangVersion: 14, Optional: "std::optional", NaNAsNull: true}, false)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), `nan-as-null changed from "false" to "true"; data written by the previously generated code may be read differently`))
+
+	modelInfo, err = generate(&cgenerator.CGenerator{LangVersion: 14, NaNAsNull: true}, true)
+	assert.NoErr(t, err)
+	assert.Eq(t, map[string]string{"empty-string-as-null": "false", "nan-as-null": "true"}, modelInfo.GeneratorOptions)
+
+	// models written by previous versions don't have the options recorded, so any are accepted
+	modelInfo, err = model.LoadOrCreateModel(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	modelInfo.GeneratorOptions = nil
+	assert.NoErr(t, modelInfo.Write())
+	modelInfo.Close()
+	modelInfo, err = generate(&cgenerator.CGenerator{LangVersion: 14, EmptyStringAsNull: true}, false)
+	assert.NoErr(t, err)
+	assert.Eq(t, "true", modelInfo.GeneratorOptions["empty-string-as-null"])
+}
+
+func TestCrossFileRelations(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "cross-file")
+	defer remove()
+
+	// the relations are declared in a file that's processed before the one with the target entities
+	fixture.WriteFile(t, filepath.Join(dir, "a.fbs"), `namespace shop;
+/// objectbox:relation(name=tags, to=Tag)
+table Order {
+	id: ulong;
+	/// objectbox:relation=Customer
+	customerId: ulong;
+}
+`)
+	fixture.WriteFile(t, filepath.Join(dir, "b.fbs"), `namespace crm;
+table Customer {
+	id: ulong;
+}
+table Tag {
+	id: ulong;
+}
+`)
+
+	var options = generator.Options{
+		InPath:        dir,
+		OutPath:       dir,
+		ModelInfoFile: generator.ModelInfoFile(dir),
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "a.obx.hpp"))
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(data), "namespace crm { struct Customer; }"))
+	assert.True(t, strings.Contains(string(data), "namespace crm { struct Tag; }"))
+	assert.True(t, strings.Contains(string(data), "crm::Customer"))
+
+	// a relation target that isn't declared in any of the files
+	fixture.WriteFile(t, filepath.Join(dir, "c.fbs"), `table Invoice {
+	id: ulong;
+	/// objectbox:relation=Missing
+	orderId: ulong;
+}
+`)
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.Eq(t, "OBXG3002: relation Invoice.orderId in "+filepath.Join(dir, "c.fbs")+
+		" targets entity Missing which isn't declared in any of the processed sources", err.Error())
+}
+
+func TestTransientAllowDrop(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "allow-drop")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+
+	// the stored property becomes transient - its data would be dropped so it needs to be confirmed
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n /// objectbox:transient\n text: string;\n}\n")
+	err := generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "property text was stored before but is now transient"))
+
+	options.AllowDrop = true
+	assert.NoErr(t, generator.Process(options))
+
+	storedModel, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(storedModel.Entities[0].Properties))
+	assert.Eq(t, 1, len(storedModel.RetiredPropertyUids))
+}
+
+func TestStrict(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "strict")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n embedding: [float];\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &jsgenerator.JSGenerator{},
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	options.Strict = true
+	err := generator.Process(options)
+	assert.Err(t, err)
+	assert.Eq(t, "strict mode: "+schemaFile+" contains constructs not supported by the generated code: "+
+		"property Task.embedding of type FloatVector isn't read back from the database", err.Error())
+
+	// the C++ generator supports all property types
+	options.CodeGenerator = &cgenerator.CGenerator{LangVersion: 14}
+	assert.NoErr(t, generator.Process(options))
+}
+
+func TestKeepGoing(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "keep-going")
+	defer remove()
+
+	var write = func(name, schema string) string {
+		var file = filepath.Join(dir, name)
+		assert.NoErr(t, ioutil.WriteFile(file, []byte(schema), 0600))
+		return file
+	}
+	var syntaxFile = write("a.fbs", "table Task {\n id: ulong;\n text: strin;\n}\n")
+	var annotationFile = write("b.fbs", "table Note {\n id: ulong;\n /// objectbox:index=unknown\n text: string;\n}\n")
+	var idFile = write("c.fbs", "table Tag {\n name: string;\n}\n")
+	write("d.fbs", "table Valid {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        dir,
+		ModelInfoFile: generator.ModelInfoFile(dir),
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// stops at the first error by default
+	assert.Eq(t, 1, len(generator.Diagnostics(generator.Process(options))))
+
+	options.KeepGoing = true
+	err := generator.Process(options)
+	assert.Err(t, err)
+	var diags = generator.Diagnostics(err)
+	assert.Eq(t, 3, len(diags))
+	assert.Eq(t, syntaxFile, diags[0].File)
+	assert.Eq(t, 3, diags[0].Line)
+	assert.Eq(t, annotationFile, diags[1].File)
+	assert.Eq(t, 4, diags[1].Line)
+	assert.Eq(t, idFile, diags[2].File)
+	assert.Eq(t, generator.DiagnosticModel, diags[2].Code)
+	assert.Eq(t, 3, len(strings.Split(err.Error(), "\n")))
+
+	// nothing is written, not even for the valid source
+	_, err = os.Stat(filepath.Join(dir, "d.obx.hpp"))
+	assert.True(t, os.IsNotExist(err))
+
+	// a single error is returned as is
+	write("a.fbs", "table Task {\n id: ulong;\n}\n")
+	write("c.fbs", "table Tag {\n id: ulong;\n name: string;\n}\n")
+	err = generator.Process(options)
+	assert.Err(t, err)
+	_, isList := err.(generator.ErrorList)
+	assert.True(t, !isList)
+
+	// all the files are written once the sources are valid
+	write("b.fbs", "table Note {\n id: ulong;\n /// objectbox:index\n text: string;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+	_, err = os.Stat(filepath.Join(dir, "d.obx.hpp"))
+	assert.NoErr(t, err)
+}
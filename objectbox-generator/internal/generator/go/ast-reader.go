@@ -24,14 +24,17 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/types"
 	"log"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/binding"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
@@ -39,32 +42,56 @@ type uid = uint64
 type id = uint32
 
 var supportedEntityAnnotations = map[string]bool{
-	"name":         false, // TODO
-	"sync":         true,
-	"transient":    true,
-	"uid":          true,
-	"external-name": true,
+	"name":                false, // TODO
+	"sync":                true,
+	"transient":           true,
+	"uid":                 true,
+	"external-name":       true,
+	"reserved":            true, // property IDs
+	"reserved-entity-ids": true,
+	"reserved-uids":       true,
+}
+
+// flexMapConverters maps the supported map types to the converters storing them as FlexBuffers
+var flexMapConverters = map[string]string{
+	"map[string]string":      "objectbox.StringMapFlexConvert",
+	"map[string]interface{}": "objectbox.MapFlexConvert",
+	"map[string]any":         "objectbox.MapFlexConvert",
 }
 
 var supportedPropertyAnnotations = map[string]bool{
-	"-":            true,
-	"converter":    true,
-	"date":         true,
-	"date-nano":    true,
-	"id":           true,
-	"id-companion": true,
-	"index":        true,
-	"inline":       true,
-	"lazy":         true,
-	"link":         true,
-	"name":         true,
-	"type":         true,
-	"uid":          true,
-	"unique":       true,
+	"-":             true,
+	"backlink":      true,
+	"cascade":       true,
+	"converter":     true,
+	"date":          true,
+	"date-nano":     true,
+	"encrypted":     true,
+	"external-id":   true,
+	"flex":          true,
+	"id":            true,
+	"id-companion":  true,
+	"index":         true,
+	"inline":        true,
+	"lazy":          true,
+	"link":          true,
+	"name":          true,
+	"related":       true,
+	"retired":       true,
+	"storage-type":  true,
+	"transient":     true,
+	"type":          true,
+	"uid":           true,
+	"unique":        true,
 	"external-name": true,
 	"external-type": true,
 }
 
+// SupportedAnnotations returns the names of the entity and property annotations accepted in Go sources
+func SupportedAnnotations() (entity, property []string) {
+	return binding.SupportedAnnotationNames(supportedEntityAnnotations), binding.SupportedAnnotationNames(supportedPropertyAnnotations)
+}
+
 // astReader contains information about the processed set of Entities
 type astReader struct {
 	Package *types.Package
@@ -73,6 +100,17 @@ type astReader struct {
 	// model produced by reading the schema
 	model *model.ModelInfo
 
+	typeMappings TypeMappings
+
+	// disables automatic converters for well-known types, see GoGenerator.NoAutoConverters
+	noAutoConverters bool
+
+	// rejects types the generated code would handle using reflection, see GoGenerator.TinyGoCheck
+	tinyGoCheck bool
+
+	// fields skipped while reading the source, see GoGenerator.Unsupported()
+	skipped []string
+
 	err    error
 	source *file
 }
@@ -83,6 +121,10 @@ type Entity struct {
 
 	Fields []*Field // the tree of struct fields (necessary for embedded structs)
 
+	Backlinks []*Field // fields annotated as backlinks, not persisted; see Field.Backlink
+
+	RelatedOwner *Field // set on entities generated to store the elements of a related slice, see Field.Related
+
 	binding *astReader // parent
 }
 
@@ -104,13 +146,23 @@ type Property struct {
 	// type casts for named types
 	CastOnRead  string
 	CastOnWrite string
+	RangeCheck  bool // whether the value must be checked to fit the stored type, see setTypeOverride()
 
 	GoField *Field // actual code field this property represents
 	Entity  *Entity
 
+	// set if the property is stored using converters generated into the binding, i.e. for well-known types (see
+	// wellKnownTypes), generic containers (see TypeMapping.Unwrap) and encrypted properties
+	generatedConverter converterGenerator
+
 	annotations map[string]*binding.Annotation
 }
 
+// converterGenerator provides the code of the converter functions generated into the binding for a property
+type converterGenerator interface {
+	converterCode(converter string) string
+}
+
 // Merge implements model.PropertyMeta interface
 func (property *Property) Merge(mProperty *model.Property) model.PropertyMeta {
 	property.ModelProperty = mProperty
@@ -127,6 +179,8 @@ type Field struct {
 	Fields             []*Field                  // inner fields, nil if it's a property
 	StandaloneRelation *model.StandaloneRelation // to-many relation stored as a standalone relation in the model
 	IsLazyLoaded       bool                      // only standalone (to-many) relations currently support lazy loading
+	Backlink           *model.Backlink           // to-many inverse of a to-one relation, always lazy-loaded
+	Related            *RelatedSlice             // slice of structs stored as objects of a generated entity
 	Meta               *Field                    // self reference for recursive ".Meta.Fields" access in the template
 
 	path   string // relative addressing path for embedded structs
@@ -205,6 +259,24 @@ func (r *astReader) entityLoader(node ast.Node, prevDecl **ast.GenDecl) bool {
 }
 
 func (r *astReader) createEntityFromAst(strct *ast.StructType, name string, comments []*ast.Comment) error {
+	entity, err := r.createEntity(astStructFieldList{strct, r.source}, name, comments)
+	if err != nil {
+		return err
+	}
+
+	r.model.Entities = append(r.model.Entities, entity.ModelEntity)
+
+	// entities generated to store the elements of related slices, see Field.Related
+	for _, field := range entity.Fields {
+		if field.Related != nil {
+			r.model.Entities = append(r.model.Entities, field.Related.Entity.ModelEntity)
+		}
+	}
+	return nil
+}
+
+// createEntity creates an entity with the given fields, e.g. of a struct declared in the source file
+func (r *astReader) createEntity(fieldList fieldList, name string, comments []*ast.Comment) (*Entity, error) {
 	var modelEntity = model.CreateEntity(r.model, 0, 0)
 	var entity = &Entity{Object: binding.CreateObject(modelEntity), binding: r}
 	modelEntity.Meta = entity
@@ -212,18 +284,17 @@ func (r *astReader) createEntityFromAst(strct *ast.StructType, name string, comm
 
 	if comments != nil {
 		if err := entity.setAnnotations(comments); err != nil {
-			return fmt.Errorf("%s on entity %s", err, entity.Name)
+			return nil, fmt.Errorf("%s on entity %s", err, entity.Name)
 		}
 	}
 
 	{
-		var fieldList = astStructFieldList{strct, r.source}
 		var recursionStack = map[string]bool{}
 		recursionStack[entity.Name] = true
 		var err error
 		entity.Fields, err = entity.addFields(nil, fieldList, entity.Name, "", &recursionStack)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -233,12 +304,12 @@ func (r *astReader) createEntityFromAst(strct *ast.StructType, name string, comm
 	// }
 
 	if err := modelEntity.AutosetIdProperty([]model.PropertyType{model.PropertyTypeLong, model.PropertyTypeString}); err != nil {
-		return fmt.Errorf("%s on entity %s", err, entity.Name)
+		return nil, fmt.Errorf("%s on entity %s", err, entity.Name)
 	}
 
 	// special handling for string IDs = they are transformed to uint64 in the binding
 	if idProp, err := modelEntity.IdProperty(); err != nil {
-		return fmt.Errorf("%s on entity %s", err, entity.Name)
+		return nil, fmt.Errorf("%s on entity %s", err, entity.Name)
 	} else if idProp.Type == model.PropertyTypeString {
 		var idPropMeta = idProp.Meta.(*Property)
 		idProp.Type = model.PropertyTypeLong
@@ -250,15 +321,13 @@ func (r *astReader) createEntityFromAst(strct *ast.StructType, name string, comm
 			idPropMeta.Converter = &converter
 		}
 	} else if !idProp.Meta.(*Property).hasValidTypeAsId() {
-		return fmt.Errorf("id field '%s' has unsupported type '%s' on entity %s - must be one of [int64, uint64, string]",
+		return nil, fmt.Errorf("id field '%s' has unsupported type '%s' on entity %s - must be one of [int64, uint64, string]",
 			idProp.Meta.(*Property).Name, idProp.Meta.(*Property).GoType, entity.Name)
 	} else {
 		idProp.Meta.(*Property).FbType = "Uint64" // always stored as Uint64
 	}
 
-	r.model.Entities = append(r.model.Entities, modelEntity)
-
-	return nil
+	return entity, nil
 }
 
 func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, prefix string, recursionStack *map[string]bool) ([]*Field, error) {
@@ -303,8 +372,18 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 		if err := property.setAnnotations(f.Tag()); err != nil {
 			return nil, propertyError(err, property)
 		}
+		modelProperty.Comments = model.DocComments(f.Doc())
 
 		if property.IsSkipped {
+			var name = property.Name
+			if len(prefix) != 0 {
+				name = prefix + "_" + property.Name
+			}
+			if property.IsRetired {
+				entity.ModelEntity.AddRetiredProperty(name)
+			} else {
+				entity.ModelEntity.AddTransientProperty(name, property.AllowDrop)
+			}
 			continue
 		}
 
@@ -318,6 +397,8 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 			// check if it's available (starts with an uppercase letter)
 			if len(field.Name) == 0 || field.Name[0] < 65 || field.Name[0] > 90 {
 				propertyLog("Notice: skipping unavailable (private)", property)
+				entity.binding.skipped = append(entity.binding.skipped,
+					fmt.Sprintf("unavailable (private) field %s in %s", property.Name, fieldPath))
 				continue
 			}
 
@@ -331,8 +412,87 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 			}
 		}
 
+		if property.annotations["backlink"] != nil {
+			if err := field.processBacklink(f); err != nil {
+				return nil, propertyError(err, property)
+			}
+			continue
+		}
+
 		children = append(children, field)
 
+		if property.annotations["related"] != nil {
+			if err := field.processRelatedSlice(f); err != nil {
+				return nil, propertyError(err, property)
+			}
+			continue
+		}
+
+		// slices of structs annotated `flex` are stored as bytes, using generated FlexBuffers converters
+		if property.annotations["flex"] != nil {
+			var name = entity.Name + "." + property.Name
+			if len(prefix) != 0 {
+				name = entity.Name + "." + prefix + "_" + property.Name
+			}
+			if err := field.processFlexSlice(f, name); err != nil {
+				return nil, propertyError(err, property)
+			}
+		}
+
+		// encrypted properties are stored as bytes, using converters calling the user-supplied hooks
+		if property.annotations["encrypted"] != nil {
+			var name = entity.Name + "." + property.Name
+			if len(prefix) != 0 {
+				name = entity.Name + "." + prefix + "_" + property.Name
+			}
+			encrypted, err := newEncryptedProperty(property, f.Type().String(), name)
+			if err != nil {
+				return nil, propertyError(err, property)
+			}
+			property.generatedConverter = encrypted
+			property.annotations["type"] = &binding.Annotation{Value: "[]byte"}
+			property.annotations["converter"] = &binding.Annotation{Value: entityNameCamel(strings.Replace(name, ".", "", 1)) + "Encrypted"}
+			property.ModelProperty.Encrypted = true
+		}
+
+		// apply the configured type mapping unless the field is annotated explicitly
+		if property.annotations["type"] == nil && property.annotations["converter"] == nil {
+			if mapping := entity.binding.typeMappings.find(f.Type().String()); mapping != nil {
+				property.annotations["type"] = &binding.Annotation{Value: mapping.Type}
+				property.annotations["converter"] = &binding.Annotation{Value: mapping.Converter}
+			} else if generic := entity.binding.typeMappings.findGeneric(f.Type().String()); generic != nil {
+				if err := entity.useGenericType(property, f, generic); err != nil {
+					return nil, propertyError(err, property)
+				}
+			}
+		}
+
+		// recognize popular types (e.g. uuid.UUID) and store them using converters generated into the binding
+		if property.annotations["type"] == nil && property.annotations["converter"] == nil && !entity.binding.noAutoConverters {
+			var resolveImport func(name string) string // fields read using go/types are qualified by the package path
+			if astField, isAst := f.(*astStructField); isAst {
+				resolveImport = func(name string) string {
+					if pkg, err := astField.source.importedPackage(name); err == nil {
+						return pkg.Path()
+					}
+					return ""
+				}
+			}
+			if known := findWellKnownType(f.Type().String(), resolveImport); known != nil {
+				property.generatedConverter = known
+				property.annotations["type"] = &binding.Annotation{Value: known.Type}
+				property.annotations["converter"] = &binding.Annotation{Value: entityNameCamel(entity.Name) + known.Converter}
+				if property.annotations["external-type"] == nil {
+					property.annotations["external-type"] = &binding.Annotation{Value: known.ExternalType}
+				}
+				if known.pkgName == path.Base(known.pkgPath) {
+					entity.binding.Imports[known.pkgPath] = known.pkgPath
+				} else {
+					entity.binding.Imports[known.pkgName] = known.pkgPath
+				}
+			}
+		}
+
 		if property.annotations["type"] != nil {
 			var annotatedType = property.annotations["type"].Value
 			if len(annotatedType) > 1 && annotatedType[0] == '*' {
@@ -344,12 +504,22 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 				return nil, propertyError(err, property)
 			}
 
+			if property.annotations["converter"] == nil {
+				if err := property.setTypeOverride(f); err != nil {
+					return nil, propertyError(err, property)
+				}
+			}
+
 		} else if innerStructFields, err := field.processType(f); err != nil {
 			return nil, propertyError(err, property)
 
 		} else if field.Type == "time.Time" {
 			// first, try to handle time.Time struct - automatically set a converter if it's declared a date by the user
 			if property.annotations["date"] == nil && property.annotations["date-nano"] == nil {
+				if entity.binding.noAutoConverters {
+					return nil, propertyError(errors.New("time.Time requires a `date` or `date-nano` annotation "+
+						"or a custom converter when automatic converters are disabled"), property)
+				}
 				property.annotations["date"] = &binding.Annotation{}
 				propertyLog("Notice: time.Time is stored and read using millisecond precision in UTC by default on", property)
 				log.Printf("To silence this notice either define your own converter using `converter` and " +
@@ -389,7 +559,7 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 			// Let's make sure this doesn't happen because it causes the generator (and a whole OS) to "freeze".
 			if field.Type != "" {
 				if (*recursionStack)[field.Type] {
-					return nil, propertyError(fmt.Errorf("embedded struct cycle detected: %v", fieldPath), property)
+					return nil, propertyError(messages.EmbeddedCycle.Errorf(fieldPath), property)
 				}
 				(*recursionStack)[field.Type] = true
 			}
@@ -429,6 +599,20 @@ func (entity *Entity) addFields(parent *Field, fields fieldList, fieldPath, pref
 			return nil, propertyError(err, property)
 		}
 
+		// float vectors stored with half precision are converted by functions generated into the binding
+		if property.ModelProperty.StorageType == model.StorageTypeFloat16 {
+			if field.IsPointer {
+				return nil, propertyError(errors.New("storage-type float16 is not supported on pointers"), property)
+			}
+			entity.binding.Imports["math"] = "math"
+		}
+
+		if length := property.ModelProperty.ArrayLength; length > 0 {
+			if err := property.ModelProperty.SetArrayLength(length); err != nil {
+				return nil, propertyError(err, property)
+			}
+		}
+
 		if len(prefix) != 0 {
 			property.ModelProperty.Name = prefix + "_" + property.ModelProperty.Name
 			property.Name = prefix + "_" + property.Name
@@ -478,11 +662,54 @@ func (field *Field) processType(f field) (fields fieldList, err error) {
 		if isNamed {
 			property.CastOnRead = baseType.String()
 			property.CastOnWrite = path.Base(typ.String()) // sometimes, it may contain a full import path
+
+			if isIntegerType(baseType.String()) {
+				property.ModelProperty.Enum = enumOf(f, property.ModelProperty)
+			}
 		}
 
 		return nil, nil
 	}
 
+	// maps are stored as FlexBuffers-encoded byte vectors, using converters provided by the objectbox package
+	if _, isMap := baseType.(*types.Map); isMap {
+		var converter, supported = flexMapConverters[baseType.String()]
+		if !supported {
+			return nil, fmt.Errorf("unsupported map type %s - only map[string]string and map[string]interface{} are supported, "+
+				"use a custom converter for others", typ.String())
+		}
+
+		if err := property.setBasicType("[]byte"); err != nil {
+			return nil, err
+		}
+		property.IsBasicType = false // override the value set by setBasicType
+
+		if property.annotations["converter"] == nil {
+			if field.Entity.binding.tinyGoCheck {
+				return nil, fmt.Errorf("map type %s isn't supported with TinyGo - the FlexBuffers converters use reflection, "+
+					"use a custom converter instead", typ.String())
+			}
+			property.Converter = &converter
+			property.annotations["type"] = &binding.Annotation{Value: "[]byte"}
+		}
+		if property.annotations["external-type"] == nil {
+			property.annotations["external-type"] = &binding.Annotation{Value: "FlexMap"}
+		}
+		return nil, nil
+	}
+
+	// fixed-length arrays, e.g. [4]float32, are stored as vectors, the length is checked when loading
+	if array, isArray := baseType.(*types.Array); isArray {
+		if isNamed || field.IsPointer {
+			return nil, fmt.Errorf("unsupported type %s - arrays are only supported as unnamed non-pointer types, e.g. [4]float32", typ.String())
+		}
+		if err := property.setBasicType("[]" + array.Elem().String()); err != nil {
+			return nil, fmt.Errorf("unsupported array type %s - only float32 arrays are supported", typ.String())
+		}
+		property.ModelProperty.ArrayLength = uint32(array.Len()) // validated by SetArrayLength() after the annotations
+		return nil, nil
+	}
+
 	// try if it's a struct - it can be either embedded or a relation
 	if strct, isStruct := baseType.(*types.Struct); isStruct {
 		// fill in the field information
@@ -518,6 +745,9 @@ func (field *Field) processType(f field) (fields fieldList, err error) {
 		if property.annotations["external-type"] != nil {
 			relDetails["external-type"] = property.annotations["external-type"]
 		}
+		if property.annotations["cascade"] != nil {
+			relDetails["cascade"] = property.annotations["cascade"]
+		}
 		if rel, err := field.Entity.AddRelation(relDetails); err != nil {
 			return nil, err
 		} else {
@@ -545,6 +775,43 @@ func (field *Field) processType(f field) (fields fieldList, err error) {
 	return nil, fmt.Errorf("unknown type %s", typ.String())
 }
 
+// processBacklink handles a field annotated as a backlink, e.g. `objectbox:"backlink:Customer"` on `Orders []*Order`.
+// It's not persisted (thus not added to the fields tree) but recorded on the entity to generate the Fetch* accessor.
+// The annotation value names the to-one relation property of the source entity; it's optional if there's only one.
+func (field *Field) processBacklink(f field) error {
+	if len(field.Property.annotations) != 1 {
+		return errors.New("backlink annotation can't be combined with other annotations")
+	} else if field.parent != nil {
+		return errors.New("backlinks are only supported directly in the entity struct, not in embedded structs")
+	}
+
+	var typ = f.Type()
+	baseType, err := typ.UnderlyingOrError()
+	if err != nil {
+		return err
+	}
+	slice, isSlice := baseType.(*types.Slice)
+	if !isSlice {
+		return fmt.Errorf("backlink must be a slice of the source entity, e.g. []*Order, got %s", typ.String())
+	}
+	var elementType = slice.Elem()
+
+	field.fillInfo(f, typesTypeErrorful{elementType})
+	if _, isPointer := elementType.(*types.Pointer); isPointer {
+		field.Type = "[]*" + field.Type
+	} else {
+		field.Type = "[]" + field.Type
+	}
+
+	var source = typeBaseName(elementType.String())
+	if field.Backlink, err = field.Entity.ModelEntity.AddBacklink(field.Name, source, field.Property.annotations["backlink"].Value); err != nil {
+		return err
+	}
+	field.Property = nil
+	field.Entity.Backlinks = append(field.Entity.Backlinks, field)
+	return nil
+}
+
 func (field *Field) fillInfo(f field, typ typeErrorful) {
 	if namedType, isNamed := f.TypeInternal().(*types.Named); isNamed {
 		field.Type = namedType.Obj().Name()
@@ -579,14 +846,19 @@ func (entity *Entity) setAnnotations(comments []*ast.Comment) error {
 	lines := parseCommentsLines(comments)
 	var annotations = make(map[string]*binding.Annotation)
 
+	var docs []string
+
 	for _, tags := range lines {
 		// only handle comments in the form of:   // `tags`
 		if len(tags) > 1 && tags[0] == tags[len(tags)-1] && tags[0] == '`' {
 			if err := parseAnnotations(tags, &annotations, supportedEntityAnnotations); err != nil {
 				return err
 			}
+		} else {
+			docs = append(docs, tags)
 		}
 	}
+	entity.ModelEntity.Comments = model.DocComments(docs)
 
 	return entity.ProcessAnnotations(annotations)
 }
@@ -751,6 +1023,84 @@ func (property *Property) setBasicType(baseType string) error {
 	return nil
 }
 
+// setTypeOverride configures storing an integer field as a different integer type, given by the `type` annotation
+// without a converter, e.g. `objectbox:"type:int8"` on an int field to save space. Values are range-checked on put.
+func (property *Property) setTypeOverride(f field) error {
+	var typ = f.Type()
+	baseType, err := typ.UnderlyingOrError()
+	if err != nil {
+		return err
+	}
+
+	var fieldType = baseType.String()
+	if fieldType == property.GoType && !typ.IsNamed() {
+		return nil // same type, nothing to convert
+	}
+
+	if property.GoField.IsPointer || !isIntegerType(fieldType) || !isIntegerType(property.GoType) {
+		return fmt.Errorf("type annotation without a converter can only be used to store an integer field as another "+
+			"integer type, e.g. `type:int8` on an int field; got type %s on a %s field", property.GoType, typ.String())
+	}
+
+	property.CastOnRead = property.GoType
+	property.CastOnWrite = path.Base(typ.String())
+	property.RangeCheck = fieldType != property.GoType
+	return nil
+}
+
+// enumOf returns an enum if the field is of a named integer type with typed constants declared in its package
+func enumOf(f field, property *model.Property) *model.Enum {
+	var typ = f.TypeInternal()
+	if expr, isExpr := typ.(astTypeExpr); isExpr {
+		var err error
+		if typ, err = expr.source.getType(expr.Expr); err != nil {
+			return nil
+		}
+	}
+	if pointer, isPointer := typ.(*types.Pointer); isPointer {
+		typ = pointer.Elem()
+	}
+
+	named, isNamed := typ.(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	var enum = &model.Enum{
+		Name:     named.Obj().Name(),
+		Type:     property.Type,
+		Unsigned: property.Flags&model.PropertyFlagUnsigned != 0,
+	}
+	var scope = named.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		if c, isConst := scope.Lookup(name).(*types.Const); isConst && types.Identical(c.Type(), named) {
+			var value, exact = constant.Int64Val(c.Val())
+			if !exact {
+				var unsigned, _ = constant.Uint64Val(c.Val())
+				value = int64(unsigned) // see model.EnumValue
+			}
+			enum.Values = append(enum.Values, &model.EnumValue{Name: name, Value: value})
+		}
+	}
+	if len(enum.Values) == 0 {
+		return nil
+	}
+
+	// the scope only lists names alphabetically, order the values as they're usually declared
+	sort.SliceStable(enum.Values, func(i, j int) bool {
+		return enum.Values[i].Value < enum.Values[j].Value
+	})
+	return enum
+}
+
+func isIntegerType(name string) bool {
+	switch name {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return true
+	}
+	return false
+}
+
 // ObTypeString is called from the template
 func (property *Property) ObTypeString() string {
 	return model.PropertyTypeNames[property.ModelProperty.Type]
@@ -762,6 +1112,53 @@ func (entity *Entity) HasNonIdProperty() bool {
 	return len(entity.ModelEntity.Properties) > 1
 }
 
+// WellKnownConverters called from the template. Returns the code of the converters generated for this entity, i.e. for
+// well-known types, generic containers, encrypted properties and flex slices.
+func (entity *Entity) WellKnownConverters() []string {
+	var result []string
+	var generated = make(map[string]bool)
+	for _, mProperty := range entity.ModelEntity.Properties {
+		var property = mProperty.Meta.(*Property)
+		if property.generatedConverter != nil && !generated[*property.Converter] {
+			generated[*property.Converter] = true
+			result = append(result, property.generatedConverter.converterCode(*property.Converter))
+		}
+	}
+	return result
+}
+
+// useGenericType configures the property of a generic container type to be stored as its type argument, using
+// converters generated into the binding
+func (entity *Entity) useGenericType(property *Property, f field, generic *genericTypeUse) error {
+	if err := property.setBasicType(generic.typeArg); err != nil {
+		return fmt.Errorf("unsupported type argument %s of the generic container %s - only basic types can be stored",
+			generic.typeArg, generic.containerType)
+	}
+
+	// the container type is qualified by the package name (as imported in the source) or by the full package path
+	if dot := strings.LastIndex(generic.containerType, "."); dot > 0 {
+		var pkgPath, pkgName = generic.containerType[:dot], path.Base(generic.containerType[:dot])
+		if astField, isAst := f.(*astStructField); isAst {
+			pkg, err := astField.source.importedPackage(generic.containerType[:dot])
+			if err != nil {
+				return err
+			}
+			pkgPath, pkgName = pkg.Path(), pkg.Name()
+		}
+		if pkgName == path.Base(pkgPath) {
+			entity.binding.Imports[pkgPath] = pkgPath
+		} else {
+			entity.binding.Imports[pkgName] = pkgPath
+		}
+		generic.containerType = pkgName + generic.containerType[dot:]
+	}
+
+	property.generatedConverter = generic
+	property.annotations["type"] = &binding.Annotation{Value: generic.typeArg}
+	property.annotations["converter"] = &binding.Annotation{Value: entityNameCamel(entity.Name) + generic.converterSuffix()}
+	return nil
+}
+
 // HasRelations called from the template.
 func (entity *Entity) HasRelations() bool {
 	for _, field := range entity.Fields {
@@ -784,6 +1181,17 @@ func (entity *Entity) HasLazyLoadedRelations() bool {
 	return false
 }
 
+// HasEagerStandaloneRelations called from the template, e.g. to skip benchmarks which would need a store to load them.
+func (entity *Entity) HasEagerStandaloneRelations() bool {
+	for _, field := range entity.Fields {
+		if field.HasEagerStandaloneRelations() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HasRelations called from the template.
 func (field *Field) HasRelations() bool {
 	if field.StandaloneRelation != nil || len(field.Property.ModelProperty.RelationTarget) > 0 {
@@ -814,6 +1222,21 @@ func (field *Field) HasLazyLoadedRelations() bool {
 	return false
 }
 
+// HasEagerStandaloneRelations called from the template.
+func (field *Field) HasEagerStandaloneRelations() bool {
+	if field.StandaloneRelation != nil && !field.IsLazyLoaded {
+		return true
+	}
+
+	for _, inner := range field.Fields {
+		if inner.HasEagerStandaloneRelations() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Path returns full path to the field (in embedded struct)
 // called from the template
 func (field *Field) Path() string {
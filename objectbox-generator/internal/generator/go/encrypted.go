@@ -0,0 +1,89 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+)
+
+// defaultEncryptionHooks is the name prefix of the user-supplied functions encrypting and decrypting the properties
+// annotated `encrypted`, i.e. objectboxEncrypt() and objectboxDecrypt(); use `encrypted:<prefix>` to choose others
+const defaultEncryptionHooks = "objectbox"
+
+// encryptedProperty is a string or []byte property stored encrypted, see Property.generatedConverter.
+// The generated converters call the user-supplied hooks (declared in the entity package), e.g.
+//
+//	func objectboxEncrypt(property string, plaintext []byte) ([]byte, error)
+//	func objectboxDecrypt(property string, ciphertext []byte) ([]byte, error)
+//
+// with the property given as "Entity.Property", e.g. to choose a key. Empty values are stored (and read) as they are.
+type encryptedProperty struct {
+	name      string // e.g. "Task.Secret"
+	fieldType string // "string" or "[]byte"
+	hooks     string // the hooks name prefix, e.g. "objectbox"
+}
+
+// newEncryptedProperty validates the field type & annotations of an `encrypted` property
+func newEncryptedProperty(property *Property, fieldType, name string) (*encryptedProperty, error) {
+	if fieldType != "string" && fieldType != "[]byte" {
+		return nil, fmt.Errorf("encrypted properties must be of type string or []byte, found %s", fieldType)
+	}
+	for _, annotation := range []string{"converter", "type", "id", "index", "unique"} {
+		if property.annotations[annotation] != nil {
+			return nil, fmt.Errorf("encrypted properties can't be annotated with `%s`", annotation)
+		}
+	}
+
+	var hooks = property.annotations["encrypted"].Value
+	if len(hooks) == 0 {
+		hooks = defaultEncryptionHooks
+	}
+	return &encryptedProperty{name: name, fieldType: fieldType, hooks: hooks}, nil
+}
+
+// converterCode returns the converter functions with the given name
+func (encrypted *encryptedProperty) converterCode(converter string) string {
+	var toBytes, fromBytes, zero = "goValue", "plaintext", "nil"
+	if encrypted.fieldType == "string" {
+		toBytes, fromBytes, zero = "[]byte(goValue)", "string(plaintext)", `""`
+	}
+	return fmt.Sprintf(`
+// %[1]sToDatabaseValue encrypts %[2]s using the user-supplied %[4]sEncrypt()
+func %[1]sToDatabaseValue(goValue %[3]s) ([]byte, error) {
+	if len(goValue) == 0 {
+		return nil, nil
+	}
+	return %[4]sEncrypt("%[2]s", %[5]s)
+}
+
+// %[1]sToEntityProperty decrypts %[2]s using the user-supplied %[4]sDecrypt()
+func %[1]sToEntityProperty(dbValue []byte) (%[3]s, error) {
+	if len(dbValue) == 0 {
+		return %[7]s, nil
+	}
+	plaintext, err := %[4]sDecrypt("%[2]s", dbValue)
+	if err != nil {
+		return %[7]s, err
+	}
+	return %[6]s, nil
+}
+`, converter, encrypted.name, encrypted.fieldType, encrypted.hooks, toBytes, fromBytes, zero)
+}
@@ -0,0 +1,414 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// ExportFunctions returns the code of the Export<Entity>JSON(), Import<Entity>JSON(), Export<Entity>CSV() and
+// Import<Entity>CSV() functions of the entity, see generator.Options.GenerateExport. The objects are converted using
+// the FlatBuffers representation of the binding (Flatten() and Load()), so the exported values are the stored ones,
+// i.e. after converters are applied. Called from the template.
+func (entity *Entity) ExportFunctions() (string, error) {
+	var name = entity.ModelEntity.Name
+	var record = entityNameCamel(name) + "ExportRecord"
+	var properties = entity.exportProperties()
+
+	lastPropertyId, err := entity.ModelEntity.LastPropertyId.GetId()
+	if err != nil {
+		return "", fmt.Errorf("entity %s: %s", name, err)
+	}
+
+	var w codeWriter
+
+	w.line("")
+	w.line("// %s holds the property values of %s objects as stored in the database, keyed by the property names", record, name)
+	w.line("type %s struct {", record)
+	for _, property := range properties {
+		w.line("%s %s `json:%q`", property.exportFieldName(), property.exportType(), property.ModelProperty.Name)
+	}
+	w.line("}")
+
+	w.line("")
+	w.line("func %sToExportRecord(obj *%s) (*%s, error) {", entityNameCamel(name), name, record)
+	w.line("id, err := %sBinding.GetId(obj)", name)
+	w.line("if err != nil {")
+	w.line("return nil, err")
+	w.line("}")
+	w.line("var fbb = flatbuffers.NewBuilder(512)")
+	w.line("if err = %sBinding.Flatten(obj, fbb, id); err != nil {", name)
+	w.line("return nil, err")
+	w.line("}")
+	w.line("fbb.Finish(fbb.EndObject())")
+	w.line("var bytes = fbb.FinishedBytes()")
+	w.line("var table = &flatbuffers.Table{Bytes: bytes, Pos: flatbuffers.GetUOffsetT(bytes)}")
+	w.line("var record = &%s{}", record)
+	for _, property := range properties {
+		offset, err := property.ModelProperty.FbvTableOffset()
+		if err != nil {
+			return "", err
+		}
+		var getter = fmt.Sprintf("fbutils.Get%sSlot(table, %d)", property.exportSlotType(), offset)
+		if name := property.Float16Vector(); len(name) != 0 {
+			getter = fmt.Sprintf("%sSlot(table, %d)", name, offset)
+		}
+		if property.exportNullable() && !property.exportVector() {
+			w.line("if table.Offset(%d) != 0 {", offset)
+			w.line("var value = %s", getter)
+			w.line("record.%s = &value", property.exportFieldName())
+			w.line("}")
+		} else {
+			w.line("record.%s = %s", property.exportFieldName(), getter)
+		}
+	}
+	w.line("return record, nil")
+	w.line("}")
+
+	w.line("")
+	w.line("func %sFromExportRecords(ob *objectbox.ObjectBox, records []*%s) ([]*%s, error) {", entityNameCamel(name), record, name)
+	if entity.exportNeedsStore() {
+		w.line("if ob == nil {")
+		w.line(`return nil, errors.New("can't import %s without an ObjectBox instance, it's needed to load related objects")`, name)
+		w.line("}")
+	}
+	w.line("var objects = make([]*%s, 0, len(records))", name)
+	w.line("for _, record := range records {")
+	w.line("var fbb = flatbuffers.NewBuilder(512)")
+	for _, property := range properties {
+		if property.FbType != "UOffsetT" {
+			continue
+		}
+		var offset = "offset" + property.exportFieldName()
+		var creator = "fbutils.Create" + property.ObTypeString() + "Offset"
+		if name := property.Float16Vector(); len(name) != 0 {
+			creator = name + "Offset"
+		}
+		if property.ModelProperty.Type == model.PropertyTypeString && !property.exportNullable() {
+			w.line("var %s = %s(fbb, record.%s)", offset, creator, property.exportFieldName())
+			continue
+		}
+		var value = "record." + property.exportFieldName()
+		if property.ModelProperty.Type == model.PropertyTypeString {
+			value = "*" + value
+		}
+		w.line("var %s flatbuffers.UOffsetT", offset)
+		w.line("if record.%s != nil {", property.exportFieldName())
+		w.line("%s = %s(fbb, %s)", offset, creator, value)
+		w.line("}")
+	}
+	w.line("fbb.StartObject(%d)", lastPropertyId)
+	for _, property := range properties {
+		var slot = property.ModelProperty.FbSlot()
+		if property.FbType == "UOffsetT" {
+			w.line("fbutils.SetUOffsetTSlot(fbb, %d, offset%s)", slot, property.exportFieldName())
+		} else if property.exportNullable() {
+			w.line("if record.%s != nil {", property.exportFieldName())
+			w.line("fbutils.Set%sSlot(fbb, %d, *record.%s)", property.FbType, slot, property.exportFieldName())
+			w.line("}")
+		} else {
+			w.line("fbutils.Set%sSlot(fbb, %d, record.%s)", property.FbType, slot, property.exportFieldName())
+		}
+	}
+	w.line("fbb.Finish(fbb.EndObject())")
+	w.line("object, err := %sBinding.Load(ob, fbb.FinishedBytes())", name)
+	w.line("if err != nil {")
+	w.line("return nil, err")
+	w.line("}")
+	w.line("objects = append(objects, object.(*%s))", name)
+	w.line("}")
+	w.line("return objects, nil")
+	w.line("}")
+
+	w.line("")
+	w.line("// Export%sJSON writes the given objects to w as a JSON array of objects with the values as stored in the database,", name)
+	w.line("// keyed by the property names, e.g. for backups or migrations; see Import%sJSON(). Byte vectors are base64-encoded,", name)
+	w.line("// related objects are represented by their IDs and to-many relations aren't exported.")
+	w.line("func Export%sJSON(w io.Writer, objects []*%s) error {", name, name)
+	w.line("var records = make([]*%s, 0, len(objects))", record)
+	w.line("for _, obj := range objects {")
+	w.line("record, err := %sToExportRecord(obj)", entityNameCamel(name))
+	w.line("if err != nil {")
+	w.line("return err")
+	w.line("}")
+	w.line("records = append(records, record)")
+	w.line("}")
+	w.line("var encoder = json.NewEncoder(w)")
+	w.line(`encoder.SetIndent("", "  ")`)
+	w.line("return encoder.Encode(records)")
+	w.line("}")
+
+	w.line("")
+	w.line("// Import%sJSON reads objects written by Export%sJSON() from r; properties missing in the JSON are left empty.", name, name)
+	w.line("// The objects aren't put, e.g. use BoxFor%s(ob).PutMany() to store them, keeping their IDs. ob is used to load", name)
+	w.line("// related objects and may be nil if there are none.")
+	w.line("func Import%sJSON(ob *objectbox.ObjectBox, r io.Reader) ([]*%s, error) {", name, name)
+	w.line("var records []*%s", record)
+	w.line("if err := json.NewDecoder(r).Decode(&records); err != nil {")
+	w.line("return nil, err")
+	w.line("}")
+	w.line("return %sFromExportRecords(ob, records)", entityNameCamel(name))
+	w.line("}")
+
+	w.line("")
+	w.line("// Export%sCSV writes the given objects to w as CSV with a header row of the property names, using the same", name)
+	w.line("// values as Export%sJSON(); string and float vectors are written as JSON arrays and empty cells stand for nil.", name)
+	w.line("func Export%sCSV(w io.Writer, objects []*%s) error {", name, name)
+	w.line("var writer = csv.NewWriter(w)")
+	var header []string
+	for _, property := range properties {
+		header = append(header, fmt.Sprintf("%q", property.ModelProperty.Name))
+	}
+	w.line("if err := writer.Write([]string{%s}); err != nil {", strings.Join(header, ", "))
+	w.line("return err")
+	w.line("}")
+	w.line("for _, obj := range objects {")
+	w.line("record, err := %sToExportRecord(obj)", entityNameCamel(name))
+	w.line("if err != nil {")
+	w.line("return err")
+	w.line("}")
+	w.line("var row = make([]string, 0, %d)", len(properties))
+	for _, property := range properties {
+		exportCSVCell(&w, property)
+	}
+	w.line("if err = writer.Write(row); err != nil {")
+	w.line("return err")
+	w.line("}")
+	w.line("}")
+	w.line("writer.Flush()")
+	w.line("return writer.Error()")
+	w.line("}")
+
+	w.line("")
+	w.line("// Import%sCSV reads objects written by Export%sCSV() from r, matching the columns by the header row;", name, name)
+	w.line("// properties without a column are left empty. See Import%sJSON() regarding the returned objects and ob.", name)
+	w.line("func Import%sCSV(ob *objectbox.ObjectBox, r io.Reader) ([]*%s, error) {", name, name)
+	w.line("var reader = csv.NewReader(r)")
+	w.line("header, err := reader.Read()")
+	w.line("if err == io.EOF {")
+	w.line("return []*%s{}, nil", name)
+	w.line("} else if err != nil {")
+	w.line("return nil, err")
+	w.line("}")
+	w.line("var columns = make(map[string]int, len(header))")
+	w.line("for i, column := range header {")
+	w.line("columns[column] = i")
+	w.line("}")
+	w.line("var records []*%s", record)
+	w.line("for line := 2; ; line++ {")
+	w.line("row, err := reader.Read()")
+	w.line("if err == io.EOF {")
+	w.line("break")
+	w.line("} else if err != nil {")
+	w.line("return nil, err")
+	w.line("}")
+	w.line("var record = &%s{}", record)
+	for _, property := range properties {
+		exportCSVParse(&w, property)
+	}
+	w.line("records = append(records, record)")
+	w.line("}")
+	w.line("return %sFromExportRecords(ob, records)", entityNameCamel(name))
+	w.line("}")
+
+	return w.String(), nil
+}
+
+// exportImports returns the packages used by the ExportFunctions() of the given entities
+func exportImports(entities []*model.Entity) ([]string, error) {
+	var code strings.Builder
+	for _, entity := range entities {
+		if functions, err := entity.Meta.(*Entity).ExportFunctions(); err != nil {
+			return nil, err
+		} else {
+			code.WriteString(functions)
+		}
+	}
+
+	var imports = []string{"encoding/csv", "encoding/json"}
+	for _, pkg := range []string{"encoding/base64", "errors", "fmt", "strconv"} {
+		if strings.Contains(code.String(), pkg[strings.LastIndex(pkg, "/")+1:]+".") {
+			imports = append(imports, pkg)
+		}
+	}
+	return append(imports, "io"), nil
+}
+
+// exportProperties returns the properties of the entity in the order of the model, i.e. the exported columns
+func (entity *Entity) exportProperties() []*Property {
+	var properties []*Property
+	for _, property := range entity.ModelEntity.Properties {
+		properties = append(properties, property.Meta.(*Property))
+	}
+	return properties
+}
+
+// exportNeedsStore checks whether loading an object of the entity needs an ObjectBox instance, i.e. to load related
+// objects of to-one relations or eagerly loaded to-many relations
+func (entity *Entity) exportNeedsStore() bool {
+	var check func(fields []*Field) bool
+	check = func(fields []*Field) bool {
+		for _, field := range fields {
+			if field.StandaloneRelation != nil && !field.IsLazyLoaded || field.isRelationObject() || check(field.Fields) {
+				return true
+			}
+		}
+		return false
+	}
+	return check(entity.Fields)
+}
+
+// exportFieldName returns the name of the field of the export record holding the property value
+func (property *Property) exportFieldName() string {
+	return strings.ToUpper(property.Name[:1]) + property.Name[1:]
+}
+
+// exportNullable checks whether the property may be missing in the FlatBuffers representation, i.e. its field (or an
+// embedded struct containing it) is a pointer; the export record then holds a pointer (or a nil slice) as well
+func (property *Property) exportNullable() bool {
+	return !property.ModelProperty.IsIdProperty() && property.GoField.HasPointersInPath()
+}
+
+// exportVector checks whether the property is a vector, represented by a slice in the export record
+func (property *Property) exportVector() bool {
+	return property.FbType == "UOffsetT" && property.ModelProperty.Type != model.PropertyTypeString
+}
+
+// exportSlotType returns the type name used by the fbutils getters and setters of the property, e.g. Int64 or String
+func (property *Property) exportSlotType() string {
+	if property.FbType == "UOffsetT" {
+		return property.ObTypeString()
+	}
+	return property.FbType
+}
+
+// exportType returns the type of the export record field holding the property value
+func (property *Property) exportType() string {
+	switch property.ModelProperty.Type {
+	case model.PropertyTypeByteVector:
+		return "[]byte"
+	case model.PropertyTypeFloatVector:
+		return "[]float32"
+	case model.PropertyTypeStringVector:
+		return "[]string"
+	}
+
+	var valueType = strings.ToLower(property.FbType)
+	if property.ModelProperty.Type == model.PropertyTypeString {
+		valueType = "string"
+	}
+	if property.exportNullable() {
+		return "*" + valueType
+	}
+	return valueType
+}
+
+// exportCSVCell writes the code appending the CSV cell of the property to the "row" slice
+func exportCSVCell(w *codeWriter, property *Property) {
+	var value = "record." + property.exportFieldName()
+	switch property.ModelProperty.Type {
+	case model.PropertyTypeByteVector:
+		w.line("row = append(row, base64.StdEncoding.EncodeToString(%s))", value)
+		return
+	case model.PropertyTypeFloatVector, model.PropertyTypeStringVector:
+		w.line("if %s != nil {", value)
+		w.line("if data, err := json.Marshal(%s); err != nil {", value)
+		w.line("return err")
+		w.line("} else {")
+		w.line("row = append(row, string(data))")
+		w.line("}")
+		w.line("} else {")
+		w.line(`row = append(row, "")`)
+		w.line("}")
+		return
+	}
+
+	var cell = value
+	if property.exportNullable() {
+		cell = "*" + value
+	}
+	if property.ModelProperty.Type != model.PropertyTypeString {
+		cell = "fmt.Sprint(" + cell + ")"
+	}
+	if property.exportNullable() {
+		w.line("if %s != nil {", value)
+		w.line("row = append(row, %s)", cell)
+		w.line("} else {")
+		w.line(`row = append(row, "")`)
+		w.line("}")
+	} else {
+		w.line("row = append(row, %s)", cell)
+	}
+}
+
+// exportCSVParse writes the code reading the property value from its CSV cell (if any) into the export record
+func exportCSVParse(w *codeWriter, property *Property) {
+	var failed = func() {
+		w.line("if err != nil {")
+		w.line(`return nil, fmt.Errorf("can't read %s.%s in CSV line %%d: %%s", line, err)`,
+			property.Entity.ModelEntity.Name, property.ModelProperty.Name)
+		w.line("}")
+	}
+
+	w.line("if i, found := columns[%q]; found && row[i] != \"\" {", property.ModelProperty.Name)
+	var valueType = strings.TrimPrefix(property.exportType(), "*")
+	switch property.ModelProperty.Type {
+	case model.PropertyTypeString:
+		w.line("var value = row[i]")
+	case model.PropertyTypeByteVector:
+		w.line("value, err := base64.StdEncoding.DecodeString(row[i])")
+		failed()
+	case model.PropertyTypeFloatVector, model.PropertyTypeStringVector:
+		w.line("var value %s", valueType)
+		w.line("var err = json.Unmarshal([]byte(row[i]), &value)")
+		failed()
+	default:
+		// parse into "value" directly if the parser returns the value type, otherwise convert
+		var bits = strings.TrimLeft(valueType, "abcdefghijklmnopqrstuvwxyz")
+		var parsed = "parsed"
+		if valueType == "bool" || bits == "64" {
+			parsed = "value"
+		}
+		switch {
+		case valueType == "bool":
+			w.line("value, err := strconv.ParseBool(row[i])")
+		case strings.HasPrefix(valueType, "float"):
+			w.line("%s, err := strconv.ParseFloat(row[i], %s)", parsed, bits)
+		case strings.HasPrefix(valueType, "uint"):
+			w.line("%s, err := strconv.ParseUint(row[i], 10, %s)", parsed, bits)
+		default:
+			w.line("%s, err := strconv.ParseInt(row[i], 10, %s)", parsed, bits)
+		}
+		failed()
+		if parsed != "value" {
+			w.line("var value = %s(parsed)", valueType)
+		}
+	}
+	if property.exportNullable() && !property.exportVector() {
+		w.line("record.%s = &value", property.exportFieldName())
+	} else {
+		w.line("record.%s = value", property.exportFieldName())
+	}
+	w.line("}")
+}
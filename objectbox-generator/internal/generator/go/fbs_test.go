@@ -0,0 +1,95 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestGoFbsSchema(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "go-fbs")
+	defer remove()
+
+	var sourceFile = filepath.Join(dir, "shop.go")
+	assert.NoErr(t, ioutil.WriteFile(sourceFile, []byte(`package shop
+
+type Customer struct {
+	Id   uint64
+	Name string `+"`objectbox:\"unique\"`"+`
+}
+
+type Order struct {
+	Id        uint64
+	Date      int64     `+"`objectbox:\"date\"`"+`
+	Customer  *Customer `+"`objectbox:\"link\"`"+`
+	Customers []*Customer
+	Location  []float32 `+"`objectbox:\"index:hnsw\"`"+`
+	Status    Status
+	Embedding [3]float32
+}
+
+type Status int16
+
+const (
+	StatusNew Status = iota
+	StatusPaid
+)
+`), 0600))
+
+	var modelInfoFile = filepath.Join(dir, "objectbox-model.json")
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:        sourceFile,
+		ModelInfoFile: modelInfoFile,
+		CodeGenerator: &gogenerator.GoGenerator{Fbs: true},
+	}))
+	goModel, err := ioutil.ReadFile(modelInfoFile)
+	assert.NoErr(t, err)
+
+	// generating from the schema must result in the same model;
+	// see test/comparison/testdata/go/fbs-schema for the generated schema itself
+	var schemaFile = filepath.Join(dir, "shop.obx.fbs")
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:        schemaFile,
+		OutPath:       dir,
+		ModelInfoFile: modelInfoFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}))
+	fbsModel, err := ioutil.ReadFile(modelInfoFile)
+	assert.NoErr(t, err)
+
+	// ... apart from the options recorded by the C++ generator
+	var withoutGeneratorOptions = func(data []byte) map[string]interface{} {
+		var m map[string]interface{}
+		assert.NoErr(t, json.Unmarshal(data, &m))
+		delete(m, "generatorOptions")
+		return m
+	}
+	assert.Eq(t, withoutGeneratorOptions(goModel), withoutGeneratorOptions(fbsModel))
+}
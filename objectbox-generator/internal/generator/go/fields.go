@@ -37,6 +37,7 @@ type fieldList interface {
 type field interface {
 	Name() (string, error)
 	Tag() string
+	Doc() []string // documentation comment lines
 	Type() typeErrorful
 	TypeInternal() types.Type
 	Package() (*types.Package, error)
@@ -98,6 +99,18 @@ func (field astStructField) Tag() string {
 	return ""
 }
 
+// Doc returns the lines of the doc comment preceding the field, followed by the trailing line comment (if any)
+func (field astStructField) Doc() []string {
+	var lines []string
+	if field.Field.Doc != nil {
+		lines = append(lines, parseCommentsLines(field.Field.Doc.List)...)
+	}
+	if field.Field.Comment != nil {
+		lines = append(lines, parseCommentsLines(field.Field.Comment.List)...)
+	}
+	return lines
+}
+
 func (field astStructField) Type() typeErrorful {
 	return astTypeExpr{Expr: field.Field.Type, source: field.source}
 }
@@ -187,6 +200,11 @@ func (field structField) Tag() string {
 	return field.tag
 }
 
+// Doc returns nil, comments of fields declared in other packages are not available from the type checker
+func (field structField) Doc() []string {
+	return nil
+}
+
 func (field structField) Type() typeErrorful {
 	return typesTypeErrorful{field.Var.Type()}
 }
@@ -0,0 +1,221 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// fixtureVectorLength is the number of elements of slices filled by the generated fixtures, unless the length is
+// given by the property, i.e. a fixed-length array or the dimensions of an HNSW index
+const fixtureVectorLength = 4
+
+// FixtureFunctions returns the code of the New<Entity>Fixture() and Random<Entity>Fixture() functions of the entity,
+// see generator.Options.GenerateFixtures. Called from the template.
+func (entity *Entity) FixtureFunctions() string {
+	var name = entity.ModelEntity.Name
+	var w codeWriter
+
+	w.line("")
+	w.line("// New%sFixture returns an object for tests with string fields set to their names and other fields left empty;", name)
+	w.line("// the ID is zero so the object is inserted as a new one on put. The given modifiers are applied before returning,")
+	w.line("// e.g. to override individual fields.")
+	w.line("func New%sFixture(modifiers ...func(*%s)) *%s {", name, name, name)
+	w.line("var obj = &%s{}", name)
+	fixtureDefaults(&w, entity.Fields)
+	w.line("for _, modify := range modifiers {")
+	w.line("modify(obj)")
+	w.line("}")
+	w.line("return obj")
+	w.line("}")
+
+	var values codeWriter
+	fixtureValues(&values, entity.Fields)
+
+	w.line("")
+	w.line("// Random%sFixture returns an object for tests filled with pseudo-random values, the same ones for the same seed.", name)
+	w.line("// The ID and relations are left empty, i.e. the object is inserted as a new one on put. The given modifiers are")
+	w.line("// applied before returning, e.g. to override individual fields.")
+	w.line("func Random%sFixture(seed int64, modifiers ...func(*%s)) *%s {", name, name, name)
+	w.line("var obj = New%sFixture()", name)
+	if values.Len() > 0 {
+		w.line("var rng = rand.New(rand.NewSource(seed))")
+		w.WriteString(values.String())
+	}
+	w.line("for _, modify := range modifiers {")
+	w.line("modify(obj)")
+	w.line("}")
+	w.line("return obj")
+	w.line("}")
+
+	return w.String()
+}
+
+// fixtureImports returns the packages used by the FixtureFunctions() of the given entities
+func fixtureImports(entities []*model.Entity) []string {
+	var code strings.Builder
+	for _, entity := range entities {
+		code.WriteString(entity.Meta.(*Entity).FixtureFunctions())
+	}
+
+	var imports []string
+	if strings.Contains(code.String(), "rand.New(") {
+		imports = append(imports, "math/rand")
+	}
+	if strings.Contains(code.String(), "strconv.") {
+		imports = append(imports, "strconv")
+	}
+	return imports
+}
+
+// isLocalType checks whether the given type name can be used in the generated code without an import
+func isLocalType(typeName string) bool {
+	return !strings.Contains(typeName, ".")
+}
+
+// fixtureFieldType returns the Go type of the property field (the pointer element type for pointers)
+func (property *Property) fixtureFieldType() string {
+	if len(property.CastOnWrite) > 0 {
+		return property.CastOnWrite
+	}
+	return property.GoType
+}
+
+// hasFixtureValue checks whether the generated fixtures set the property, i.e. it's not the ID, a relation or stored
+// using a converter, and its type is declared in the current package
+func (property *Property) hasFixtureValue() bool {
+	return property.Converter == nil && !property.ModelProperty.IsIdProperty() &&
+		len(property.ModelProperty.RelationTarget) == 0 && isLocalType(property.fixtureFieldType())
+}
+
+func fixtureDefaults(w *codeWriter, fields []*Field) {
+	for _, field := range fields {
+		switch {
+		case field.StandaloneRelation != nil:
+			// related objects are left empty
+
+		case field.Property != nil:
+			if !field.IsPointer && field.Property.Converter == nil && !field.Property.ModelProperty.IsIdProperty() &&
+				field.Property.ModelProperty.Type == model.PropertyTypeString {
+				w.line("obj.%s = %q", field.Path(), field.Name)
+			}
+
+		default: // embedded struct
+			if field.IsPointer {
+				if !isLocalType(field.Type) {
+					continue // left nil, including its fields
+				}
+				w.line("obj.%s = &%s{}", field.Path(), field.Type)
+			}
+			fixtureDefaults(w, field.Fields)
+		}
+	}
+}
+
+func fixtureValues(w *codeWriter, fields []*Field) {
+	for _, field := range fields {
+		switch {
+		case field.StandaloneRelation != nil:
+			// related objects are left empty
+
+		case field.Property != nil:
+			var property = field.Property
+			if !property.hasFixtureValue() {
+				continue
+			}
+			if field.IsPointer {
+				w.line("{")
+				w.line("var value %s", property.fixtureFieldType())
+				fixtureValue(w, property, "value")
+				w.line("obj.%s = &value", field.Path())
+				w.line("}")
+			} else {
+				fixtureValue(w, property, "obj."+field.Path())
+			}
+
+		default: // embedded struct
+			if !field.IsPointer || isLocalType(field.Type) {
+				fixtureValues(w, field.Fields) // pointers have been allocated by New<Entity>Fixture()
+			}
+		}
+	}
+}
+
+// fixtureValue writes the code assigning a pseudo-random value of the property to the given variable, using a
+// rand.Rand named "rng"
+func fixtureValue(w *codeWriter, property *Property, target string) {
+	var modelProperty = property.ModelProperty
+	var fieldType = property.fixtureFieldType()
+
+	// converts the value of the given type to the field type, e.g. a named type or the type stored instead
+	var convert = func(valueType, value string) string {
+		if fieldType != valueType {
+			return fieldType + "(" + value + ")"
+		}
+		return value
+	}
+
+	if modelProperty.Enum != nil && len(modelProperty.Enum.Values) > 0 {
+		var values []string
+		for _, value := range modelProperty.Enum.Values {
+			values = append(values, value.Name)
+		}
+		w.line("%s = []%s{%s}[rng.Intn(%d)]", target, fieldType, strings.Join(values, ", "), len(values))
+		return
+	}
+
+	var randomString = fmt.Sprintf(`%q + strconv.Itoa(rng.Intn(1000000))`, property.GoField.Name+"-")
+	switch modelProperty.Type {
+	case model.PropertyTypeBool:
+		w.line("%s = rng.Intn(2) == 1", target)
+	case model.PropertyTypeDate:
+		w.line("%s = %s // within a year from 2023-11-14", target, convert("int64", "1700000000000 + rng.Int63n(31536000000)"))
+	case model.PropertyTypeDateNano:
+		w.line("%s = %s", target, convert("int64", "(1700000000000 + rng.Int63n(31536000000)) * 1000000"))
+	case model.PropertyTypeFloat:
+		w.line("%s = %s", target, convert("float32", "rng.Float32()"))
+	case model.PropertyTypeDouble:
+		w.line("%s = %s", target, convert("float64", "rng.Float64()"))
+	case model.PropertyTypeString:
+		w.line("%s = %s", target, convert("string", randomString))
+	case model.PropertyTypeStringVector:
+		w.line("%s = %s{%s, %s}", target, fieldType, randomString, randomString)
+	case model.PropertyTypeByteVector:
+		var element = "byte(rng.Uint32())"
+		w.line("%s = %s{%s}", target, fieldType, strings.Repeat(element+", ", fixtureVectorLength-1)+element)
+	case model.PropertyTypeFloatVector:
+		if modelProperty.ArrayLength == 0 {
+			var length = uint64(fixtureVectorLength)
+			if modelProperty.HnswParams != nil && modelProperty.HnswParams.Dimensions != nil {
+				length = *modelProperty.HnswParams.Dimensions
+			}
+			w.line("%s = make(%s, %d)", target, fieldType, length)
+		}
+		w.line("for i := range %s {", target)
+		w.line("%s[i] = rng.Float32()", target)
+		w.line("}")
+	default: // integers
+		w.line("%s = %s", target, convert(property.GoType, property.GoType+"(rng.Uint64())"))
+	}
+}
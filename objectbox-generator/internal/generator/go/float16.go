@@ -0,0 +1,133 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Float16Vector returns the name prefix of the functions writing and reading a float vector stored with half precision
+// (see model.StorageTypeFloat16) instead of the fbutils ones, e.g. "taskFloat16Vector"; empty for other properties.
+// Called from the template.
+func (property *Property) Float16Vector() string {
+	if property.ModelProperty.StorageType != model.StorageTypeFloat16 {
+		return ""
+	}
+	return entityNameCamel(property.Entity.Name) + "Float16Vector"
+}
+
+// Float16VectorFunctions returns the code of the functions used by the entity's properties stored with half precision,
+// see Property.Float16Vector(). Called from the template.
+func (entity *Entity) Float16VectorFunctions() string {
+	for _, mProperty := range entity.ModelEntity.Properties {
+		if name := mProperty.Meta.(*Property).Float16Vector(); len(name) != 0 {
+			return fmt.Sprintf(float16VectorCode, name)
+		}
+	}
+	return ""
+}
+
+// float16VectorCode converts float32 to IEEE 754 half precision (rounding to the nearest value, ties to even) and back
+var float16VectorCode = `
+// %[1]sOffset writes the values as a vector of IEEE 754 half-precision floats
+func %[1]sOffset(fbb *flatbuffers.Builder, values []float32) flatbuffers.UOffsetT {
+	if values == nil {
+		return 0
+	}
+	fbb.StartVector(2, len(values), 2)
+	for i := len(values) - 1; i >= 0; i-- {
+		fbb.PrependUint16(%[1]sFromFloat32(values[i]))
+	}
+	return fbb.EndVector(len(values))
+}
+
+// %[1]sSlot reads a vector of IEEE 754 half-precision floats
+func %[1]sSlot(table *flatbuffers.Table, slot flatbuffers.VOffsetT) []float32 {
+	var offset = flatbuffers.UOffsetT(table.Offset(slot))
+	if offset == 0 {
+		return nil
+	}
+	var start = table.Vector(offset)
+	var values = make([]float32, table.VectorLen(offset))
+	for i := range values {
+		values[i] = %[1]sToFloat32(table.GetUint16(start + flatbuffers.UOffsetT(i*2)))
+	}
+	return values
+}
+
+// %[1]sFromFloat32 converts to half precision; out of range values become infinity
+func %[1]sFromFloat32(value float32) uint16 {
+	var bits = math.Float32bits(value)
+	var sign = uint16(bits>>16) & 0x8000
+	var exponent = int32(bits>>23) & 0xFF
+	var mantissa = bits & 0x7FFFFF
+	if exponent == 0xFF { // infinity or NaN
+		if mantissa != 0 {
+			return sign | 0x7E00
+		}
+		return sign | 0x7C00
+	}
+	exponent = exponent - 127 + 15
+	if exponent >= 0x1F {
+		return sign | 0x7C00
+	}
+	var shift uint32 = 13
+	if exponent <= 0 { // subnormal
+		if exponent < -10 {
+			return sign
+		}
+		mantissa |= 0x800000
+		shift = uint32(14 - exponent)
+		exponent = 0
+	}
+	var half = uint32(exponent)<<10 + mantissa>>shift
+	var rest = mantissa & (1<<shift - 1)
+	var halfway = uint32(1) << (shift - 1)
+	if rest > halfway || (rest == halfway && half&1 == 1) {
+		half++ // may carry into the exponent, up to infinity
+	}
+	return sign | uint16(half)
+}
+
+// %[1]sToFloat32 converts from half precision
+func %[1]sToFloat32(half uint16) float32 {
+	var sign = uint32(half&0x8000) << 16
+	var exponent = uint32(half>>10) & 0x1F
+	var mantissa = uint32(half & 0x3FF)
+	switch {
+	case exponent == 0x1F: // infinity or NaN
+		return math.Float32frombits(sign | 0x7F800000 | mantissa<<13)
+	case exponent != 0:
+		return math.Float32frombits(sign | (exponent+127-15)<<23 | mantissa<<13)
+	case mantissa == 0:
+		return math.Float32frombits(sign)
+	}
+	// subnormal, normalized for single precision
+	exponent = 127 - 15 + 1
+	for mantissa&0x400 == 0 {
+		mantissa <<= 1
+		exponent--
+	}
+	return math.Float32frombits(sign | exponent<<23 | (mantissa&0x3FF)<<13)
+}
+`
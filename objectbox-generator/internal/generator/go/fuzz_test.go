@@ -0,0 +1,50 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzParseGoSource feeds arbitrary Go source files to the AST reader; errors are fine, crashes and hangs are not.
+// Run with `go test ./internal/generator/go -fuzz FuzzParseGoSource`.
+func FuzzParseGoSource(f *testing.F) {
+	f.Add("package fuzz\n\ntype Entity struct {\n\tId   uint64\n\tName string `objectbox:\"index\"`\n}\n")
+	f.Add("package fuzz\n\ntype Target struct {\n\tId uint64\n}\n\ntype Source struct {\n\tId     uint64\n\tTarget *Target `objectbox:\"link\"`\n\tMany   []*Target\n}\n")
+	f.Add("package fuzz\n\n// Entity is annotated\n// `objectbox:\"sync uid:123\"`\ntype Entity struct {\n\tID   int64 `objectbox:\"id(assignable)\"`\n\tDate int64 `objectbox:\"date\"`\n}\n")
+	f.Add("package fuzz\n\ntype Embedded struct {\n\tValue float32\n}\n\ntype Entity struct {\n\tId uint64\n\tEmbedded\n\tMap map[string]int `objectbox:\"type:[]byte converter:mapJson\"`\n}\n")
+
+	f.Fuzz(func(t *testing.T, source string) {
+		// each source gets its own directory because parseFile() reads the whole package
+		var file = filepath.Join(t.TempDir(), "entity.go")
+		if err := ioutil.WriteFile(file, []byte(source), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		var gen = &GoGenerator{}
+		_, _ = gen.ParseSource(file)
+	})
+}
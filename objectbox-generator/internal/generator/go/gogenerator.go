@@ -21,30 +21,80 @@
 package gogenerator
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"go/format"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/go/templates"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
+// templatesVersion identifies all the templates used by the Go generator
+var templatesVersion = generator.TemplateVersion(templates.BindingTemplate, templates.ModelTemplate, templates.SchemaTemplate, templates.BenchmarkTemplate,
+	templates.FixturesTemplate, templates.ExportTemplate)
+
+// goTemplates holds the templates actually used for generating, i.e. including user overrides
+type goTemplates struct {
+	binding, model, schema, benchmark, fixtures, export *template.Template
+	version                                             string
+}
+
+// loadTemplates returns the embedded templates with overrides from the given directory (if any) applied
+func loadTemplates(overridesDir string) (*goTemplates, error) {
+	tpls, version, err := generator.LoadTemplates(overridesDir, templates.BindingTemplate, templates.ModelTemplate, templates.SchemaTemplate,
+		templates.BenchmarkTemplate, templates.FixturesTemplate, templates.ExportTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &goTemplates{tpls[0], tpls[1], tpls[2], tpls[3], tpls[4], tpls[5], version}, nil
+}
+
 type GoGenerator struct {
-	binding *astReader
-	ByValue bool
+	binding      *astReader
+	ByValue      bool
+	TypeMappings TypeMappings // storage types & converters for user-defined types, instead of annotating each field
+	Fbs          bool         // additionally write an equivalent FlatBuffers schema, e.g. to use the model in other languages
+
+	// EntityHelpers enables generating String(), Equal() and Clone() methods for each entity, e.g. for tests and logging
+	EntityHelpers bool
+
+	// NoAutoConverters disables converters generated for well-known types (e.g. uuid.UUID, *big.Int), see wellKnownTypes,
+	// and the default `date` storage of time.Time fields.
+	NoAutoConverters bool
+
+	// TinyGoCheck rejects the features whose generated code relies on reflection or heavy standard library packages
+	// (FlexBuffers maps, entity helpers, export), which TinyGo doesn't support, see checkTinyGo(). It's a compatibility
+	// check only: the generated binding is the same as without it and still needs the objectbox-go runtime.
+	TinyGoCheck bool
 }
 
-// BindingFiles returns names of binding files for the given entity file.
-func (gen *GoGenerator) BindingFiles(forFile string, options generator.Options) []string {
+// BindingFiles returns names of binding files for the given entity file. With Fbs, the second one is the schema file.
+// With options.GenerateBenchmarks, they're followed by the benchmark (test) file, with options.GenerateFixtures by
+// the fixtures (test) file and with options.GenerateExport by the export file.
+func (gen *GoGenerator) BindingFiles(forFile string, options generator.Options) ([]string, error) {
 	if len(options.OutPath) > 0 {
 		forFile = filepath.Join(options.OutPath, filepath.Base(forFile))
 	}
 	var extension = filepath.Ext(forFile)
-	return []string{forFile[0:len(forFile)-len(extension)] + ".obx" + extension}
+	var base = forFile[0 : len(forFile)-len(extension)]
+	var files = []string{base + ".obx" + extension}
+	if gen.Fbs {
+		files = append(files, base+".obx.fbs")
+	}
+	if options.GenerateBenchmarks {
+		files = append(files, base+".obx.bench_test"+extension)
+	}
+	if options.GenerateFixtures {
+		files = append(files, base+".obx.fixtures_test"+extension)
+	}
+	if options.GenerateExport {
+		files = append(files, base+".obx.export"+extension)
+	}
+	return files, nil
 }
 
 // ModelFile returns the model GO file for the given JSON info file path
@@ -58,7 +108,9 @@ func (gen *GoGenerator) ModelFile(forFile string, options generator.Options) str
 
 func (GoGenerator) IsGeneratedFile(file string) bool {
 	var name = filepath.Base(file)
-	return name == "objectbox-model.go" || strings.HasSuffix(name, ".obx.go")
+	return name == "objectbox-model.go" || strings.HasSuffix(name, ".obx.go") || strings.HasSuffix(name, ".obx.fbs") ||
+		strings.HasSuffix(name, ".obx.bench_test.go") || strings.HasSuffix(name, ".obx.fixtures_test.go") ||
+		strings.HasSuffix(name, ".obx.export.go")
 }
 
 func (GoGenerator) IsSourceFile(file string) bool {
@@ -78,6 +130,9 @@ func (goGen *GoGenerator) ParseSource(sourceFile string) (*model.ModelInfo, erro
 	if goGen.binding, err = NewBinding(); err != nil {
 		return nil, fmt.Errorf("can't init Go AST reader: %s", err)
 	}
+	goGen.binding.typeMappings = goGen.TypeMappings
+	goGen.binding.noAutoConverters = goGen.NoAutoConverters
+	goGen.binding.tinyGoCheck = goGen.TinyGoCheck
 
 	if err = goGen.binding.CreateFromAst(f); err != nil {
 		return nil, fmt.Errorf("can't prepare bindings for %s: %s", sourceFile, err)
@@ -86,6 +141,11 @@ func (goGen *GoGenerator) ParseSource(sourceFile string) (*model.ModelInfo, erro
 	return goGen.binding.model, nil
 }
 
+// Unsupported implements generator.StrictChecker - reports fields skipped by the last ParseSource() call
+func (goGen *GoGenerator) Unsupported(currentModel *model.ModelInfo) []string {
+	return goGen.binding.skipped
+}
+
 func (goGen *GoGenerator) WriteBindingFiles(sourceFile string, options generator.Options, mergedModel *model.ModelInfo) error {
 	// NOTE: find a better place for this check - we only want to do it for some languages
 	// should be called after generator calls storedMode.Finalize()
@@ -93,6 +153,10 @@ func (goGen *GoGenerator) WriteBindingFiles(sourceFile string, options generator
 		return err
 	}
 
+	if err := goGen.checkTinyGo(options); err != nil {
+		return err
+	}
+
 	var err, err2 error
 
 	var bindingSource []byte
@@ -100,8 +164,10 @@ func (goGen *GoGenerator) WriteBindingFiles(sourceFile string, options generator
 		return fmt.Errorf("can't generate binding file %s: %s", sourceFile, err)
 	}
 
-	var bindingFiles = goGen.BindingFiles(sourceFile, options)
-	if len(bindingFiles) != 1 {
+	bindingFiles, err := goGen.BindingFiles(sourceFile, options)
+	if err != nil {
+		return err
+	} else if len(bindingFiles) == 0 {
 		panic("internal error - someone changed GoGenerator::BindingFiles()?")
 	}
 	if formattedSource, err := format.Source(bindingSource); err != nil {
@@ -111,37 +177,183 @@ func (goGen *GoGenerator) WriteBindingFiles(sourceFile string, options generator
 		bindingSource = formattedSource
 	}
 
-	if err = generator.WriteFile(bindingFiles[0], bindingSource, sourceFile); err != nil {
+	if err = options.WriteFile(bindingFiles[0], bindingSource, sourceFile); err != nil {
 		return fmt.Errorf("can't write binding file %s: %s", sourceFile, err)
 	} else if err2 != nil {
 		// now when the binding has been written (for debugging purposes), we can return the error
 		return err2
 	}
 
+	if goGen.Fbs {
+		var schemaSource []byte
+		if schemaSource, err = goGen.generateSchemaFile(sourceFile, options, mergedModel); err != nil {
+			return fmt.Errorf("can't generate schema file %s: %s", bindingFiles[1], err)
+		}
+		if err = options.WriteFile(bindingFiles[1], schemaSource, sourceFile); err != nil {
+			return fmt.Errorf("can't write schema file %s: %s", bindingFiles[1], err)
+		}
+	}
+
+	// the optional files follow the binding (and schema) file, see BindingFiles()
+	var extraFiles = bindingFiles[1:]
+	if goGen.Fbs {
+		extraFiles = extraFiles[1:]
+	}
+
+	if options.GenerateBenchmarks {
+		var benchmarkFile = extraFiles[0]
+		extraFiles = extraFiles[1:]
+		var benchmarkSource []byte
+		if benchmarkSource, err = goGen.generateBenchmarkFile(sourceFile, options, mergedModel); err != nil {
+			return fmt.Errorf("can't generate benchmark file %s: %s", benchmarkFile, err)
+		}
+		if err = writeFormattedFile(benchmarkFile, benchmarkSource, sourceFile, "benchmark", options); err != nil {
+			return err
+		}
+	}
+
+	if options.GenerateFixtures {
+		var fixturesFile = extraFiles[0]
+		extraFiles = extraFiles[1:]
+		var fixturesSource []byte
+		if fixturesSource, err = goGen.generateFixturesFile(sourceFile, options, mergedModel); err != nil {
+			return fmt.Errorf("can't generate fixtures file %s: %s", fixturesFile, err)
+		}
+		if err = writeFormattedFile(fixturesFile, fixturesSource, sourceFile, "fixtures", options); err != nil {
+			return err
+		}
+	}
+
+	if options.GenerateExport {
+		var exportFile = extraFiles[0]
+		var exportSource []byte
+		if exportSource, err = goGen.generateExportFile(sourceFile, options, mergedModel); err != nil {
+			return fmt.Errorf("can't generate export file %s: %s", exportFile, err)
+		}
+		if err = writeFormattedFile(exportFile, exportSource, sourceFile, "export", options); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (goGen *GoGenerator) generateBindingFile(options generator.Options, m *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var tplArguments = struct {
 		Model            *model.ModelInfo
 		Binding          *astReader
 		ByValue          bool
+		EntityHelpers    bool
 		GeneratorVersion int
 		Options          generator.Options
-	}{m, goGen.binding, goGen.ByValue, generator.VersionId, options}
+		TemplateVersion  string
+	}{m, goGen.binding, goGen.ByValue, goGen.EntityHelpers, generator.VersionId, options, tpls.version}
+
+	if data, err = generator.ExecuteTemplate(tpls.binding, tplArguments); err != nil {
+		return nil, fmt.Errorf("template execution failed: %s", err)
+	}
+	return data, nil
+}
+
+func (goGen *GoGenerator) generateSchemaFile(sourceFile string, options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tplArguments = struct {
+		Source          string
+		Entities        []*model.Entity
+		TemplateVersion string
+	}{filepath.Base(sourceFile), m.EntitiesWithMeta(), tpls.version}
+
+	if data, err = generator.ExecuteTemplate(tpls.schema, tplArguments); err != nil {
+		return nil, fmt.Errorf("template execution failed: %s", err)
+	}
+	return data, nil
+}
+
+func (goGen *GoGenerator) generateBenchmarkFile(sourceFile string, options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tplArguments = struct {
+		Source          string
+		Model           *model.ModelInfo
+		Binding         *astReader
+		TemplateVersion string
+	}{filepath.Base(sourceFile), m, goGen.binding, tpls.version}
+
+	if data, err = generator.ExecuteTemplate(tpls.benchmark, tplArguments); err != nil {
+		return nil, fmt.Errorf("template execution failed: %s", err)
+	}
+	return data, nil
+}
 
-	if err = templates.BindingTemplate.Execute(writer, tplArguments); err != nil {
+func (goGen *GoGenerator) generateFixturesFile(sourceFile string, options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tplArguments = struct {
+		Source          string
+		Model           *model.ModelInfo
+		Binding         *astReader
+		Imports         []string
+		TemplateVersion string
+	}{filepath.Base(sourceFile), m, goGen.binding, fixtureImports(m.EntitiesWithMeta()), tpls.version}
+
+	if data, err = generator.ExecuteTemplate(tpls.fixtures, tplArguments); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
+	return data, nil
+}
+
+func (goGen *GoGenerator) generateExportFile(sourceFile string, options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err := exportImports(m.EntitiesWithMeta())
+	if err != nil {
+		return nil, err
+	}
 
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
+	var tplArguments = struct {
+		Source          string
+		Model           *model.ModelInfo
+		Binding         *astReader
+		Imports         []string
+		TemplateVersion string
+	}{filepath.Base(sourceFile), m, goGen.binding, imports, tpls.version}
+
+	if data, err = generator.ExecuteTemplate(tpls.export, tplArguments); err != nil {
+		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
+	return data, nil
+}
 
-	return b.Bytes(), nil
+// writeFormattedFile formats the given Go source and writes it, even if formatting fails (to be able to check it)
+func writeFormattedFile(file string, source []byte, sourceFile string, kind string, options generator.Options) error {
+	var err2 error
+	if formattedSource, err := format.Source(source); err != nil {
+		err2 = fmt.Errorf("failed to format generated %s file %s: %s", kind, file, err)
+	} else {
+		source = formattedSource
+	}
+	if err := options.WriteFile(file, source, sourceFile); err != nil {
+		return fmt.Errorf("can't write %s file %s: %s", kind, file, err)
+	}
+	return err2
 }
 
 func (goGen *GoGenerator) WriteModelBindingFile(options generator.Options, modelInfo *model.ModelInfo) error {
@@ -150,7 +362,7 @@ func (goGen *GoGenerator) WriteModelBindingFile(options generator.Options, model
 	var modelFile = goGen.ModelFile(options.ModelInfoFile, options)
 	var modelSource []byte
 
-	if modelSource, err = goGen.generateModelFile(modelInfo); err != nil {
+	if modelSource, err = goGen.generateModelFile(options, modelInfo); err != nil {
 		return fmt.Errorf("can't generate model file %s: %s", modelFile, err)
 	}
 
@@ -161,7 +373,7 @@ func (goGen *GoGenerator) WriteModelBindingFile(options generator.Options, model
 		modelSource = formattedSource
 	}
 
-	if err = generator.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
+	if err = options.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
 		return fmt.Errorf("can't write model file %s: %s", modelFile, err)
 	} else if err2 != nil {
 		// now when the model has been written (for debugging purposes), we can return the error
@@ -171,23 +383,81 @@ func (goGen *GoGenerator) WriteModelBindingFile(options generator.Options, model
 	return nil
 }
 
-func (goGen *GoGenerator) generateModelFile(m *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
+func (goGen *GoGenerator) generateModelFile(options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, qualifiers, err := moduleImports(goGen.ModelFile(options.ModelInfoFile, options), m)
+	if err != nil {
+		return nil, err
+	}
+
+	externalMapping, err := externalMappingLiteral(m)
+	if err != nil {
+		return nil, err
+	}
+
+	cascadeDeletes, err := cascadeDeletesLiteral(m)
+	if err != nil {
+		return nil, err
+	}
 
 	var tplArguments = struct {
 		Package          string
 		Model            *model.ModelInfo
+		Imports          []moduleImport
+		Qualifiers       map[string]string
+		ExternalMapping  string
+		CascadeDeletes   string
 		GeneratorVersion int
-	}{goGen.binding.Package.Name(), m, generator.VersionId}
+		TemplateVersion  string
+	}{goGen.binding.Package.Name(), m, imports, qualifiers, externalMapping, cascadeDeletes, generator.VersionId, tpls.version}
 
-	if err = templates.ModelTemplate.Execute(writer, tplArguments); err != nil {
+	if data, err = generator.ExecuteTemplate(tpls.model, tplArguments); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
+	return data, nil
+}
+
+// TemplateVersion returns an identifier of the Go templates
+func (goGen *GoGenerator) TemplateVersion() string {
+	return templatesVersion
+}
+
+// externalMappingLiteral returns the model's external mapping JSON as a Go string literal, or "" if there's none
+func externalMappingLiteral(m *model.ModelInfo) (string, error) {
+	var mapping = m.CreateExternalMapping()
+	if mapping == nil {
+		return "", nil
+	}
+
+	json, err := mapping.JSON("  ")
+	if err != nil {
+		return "", fmt.Errorf("can't create the external mapping: %s", err)
+	}
+	return jsonLiteral(json), nil
+}
 
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
+// cascadeDeletesLiteral returns the model's cascade delete rules JSON as a Go string literal, or "" if there are none
+func cascadeDeletesLiteral(m *model.ModelInfo) (string, error) {
+	var cascades = m.CreateCascadeDeletes()
+	if len(cascades) == 0 {
+		return "", nil
 	}
 
-	return b.Bytes(), nil
+	json, err := model.CascadeDeletesJSON(cascades, "  ")
+	if err != nil {
+		return "", fmt.Errorf("can't create the cascade delete rules: %s", err)
+	}
+	return jsonLiteral(json), nil
+}
+
+func jsonLiteral(json string) string {
+	// a raw string is more readable but can't contain backticks, e.g. in an external name
+	if strings.Contains(json, "`") {
+		return strconv.Quote(json)
+	}
+	return "`" + json + "`"
 }
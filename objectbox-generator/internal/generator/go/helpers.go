@@ -0,0 +1,304 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// codeWriter collects lines of generated code
+type codeWriter struct {
+	strings.Builder
+}
+
+func (w *codeWriter) line(format string, args ...interface{}) {
+	w.WriteString(fmt.Sprintf(format, args...))
+	w.WriteString("\n")
+}
+
+// HelperMethods returns the code of the String(), Equal() and Clone() methods of the entity, see GoGenerator.EntityHelpers.
+// Called from the template.
+func (entity *Entity) HelperMethods() string {
+	var name = entity.ModelEntity.Name
+	var w codeWriter
+
+	w.line("")
+	w.line("// String returns a human-readable representation of the %s, e.g. for logging; related objects are", name)
+	w.line("// represented by their IDs")
+	w.line("func (obj *%s) String() string {", name)
+	w.line("if obj == nil {")
+	w.line(`return "<nil>"`)
+	w.line("}")
+	w.line("var fields []string")
+	helperString(&w, entity.Fields)
+	w.line(`return "%s{" + strings.Join(fields, ", ") + "}"`, name)
+	w.line("}")
+
+	w.line("")
+	w.line("// Equal checks whether the given %s has the same stored values as this one; related objects are", name)
+	w.line("// compared by their IDs")
+	w.line("func (obj *%s) Equal(other *%s) bool {", name, name)
+	w.line("if obj == nil || other == nil {")
+	w.line("return obj == other")
+	w.line("}")
+	helperEqual(&w, entity.Fields)
+	w.line("return true")
+	w.line("}")
+
+	w.line("")
+	w.line("// Clone returns a copy of the %s, including copies of slices and embedded structs; related objects", name)
+	w.line("// and values of fields stored using a converter are shared with the original")
+	w.line("func (obj *%s) Clone() *%s {", name, name)
+	w.line("if obj == nil {")
+	w.line("return nil")
+	w.line("}")
+	w.line("var clone = *obj")
+	helperClone(&w, entity.Fields)
+	w.line("return &clone")
+	w.line("}")
+
+	return w.String()
+}
+
+// isRelationObject checks whether the field holds a related object (to-one relation), as opposed to just its ID
+func (field *Field) isRelationObject() bool {
+	return field.Property != nil && !field.Property.IsBasicType && len(field.Property.ModelProperty.RelationTarget) > 0
+}
+
+// relatedIdCode returns the code assigning the ID of the related object (pointer) to the given variable
+func relatedIdCode(target, rel, idVar string) string {
+	return fmt.Sprintf("%s, err := %sBinding.GetId(%s)", idVar, target, rel)
+}
+
+func helperString(w *codeWriter, fields []*Field) {
+	for _, field := range fields {
+		var path = field.Path()
+		var value = "obj." + path
+		switch {
+		case field.StandaloneRelation != nil:
+			var target = field.StandaloneRelation.Target.Name
+			var pointers = strings.HasPrefix(field.Type, "[]*")
+			w.line("{")
+			w.line("var ids []string")
+			w.line("for _, rel := range %s {", value)
+			if pointers {
+				w.line("if rel == nil {")
+				w.line(`ids = append(ids, "nil")`)
+				w.line("} else if %s; err != nil {", relatedIdCode(target, "rel", "rId"))
+			} else {
+				w.line("if %s; err != nil {", relatedIdCode(target, "&rel", "rId"))
+			}
+			w.line(`ids = append(ids, "?")`)
+			w.line("} else {")
+			w.line("ids = append(ids, fmt.Sprint(rId))")
+			w.line("}")
+			w.line("}")
+			w.line(`fields = append(fields, "%s: ["+strings.Join(ids, " ")+"]")`, path)
+			w.line("}")
+
+		case field.isRelationObject():
+			var target = field.Property.ModelProperty.RelationTarget
+			if field.IsPointer {
+				w.line("if %s == nil {", value)
+				w.line(`fields = append(fields, "%s: nil")`, path)
+				w.line("} else if %s; err != nil {", relatedIdCode(target, value, "rId"))
+			} else {
+				w.line("if %s; err != nil {", relatedIdCode(target, "&"+value, "rId"))
+			}
+			w.line(`fields = append(fields, "%s: ?")`, path)
+			w.line("} else {")
+			w.line(`fields = append(fields, fmt.Sprintf("%s: %%v", rId))`, path)
+			w.line("}")
+
+		case field.Property != nil:
+			var verb = "%v"
+			if field.Property.Converter == nil && field.Property.ModelProperty.Type == model.PropertyTypeString {
+				verb = "%q"
+			}
+			if field.IsPointer && field.Property.Converter == nil {
+				w.line("if %s == nil {", value)
+				w.line(`fields = append(fields, "%s: nil")`, path)
+				w.line("} else {")
+				w.line(`fields = append(fields, fmt.Sprintf("%s: %s", *%s))`, path, verb, value)
+				w.line("}")
+			} else {
+				w.line(`fields = append(fields, fmt.Sprintf("%s: %s", %s))`, path, verb, value)
+			}
+
+		default: // embedded struct
+			if field.IsPointer {
+				w.line("if %s == nil {", value)
+				w.line(`fields = append(fields, "%s: nil")`, path)
+				w.line("} else {")
+				helperString(w, field.Fields)
+				w.line("}")
+			} else {
+				helperString(w, field.Fields)
+			}
+		}
+	}
+}
+
+// helperEqualNil writes the code comparing the presence of pointer values and opens a block for the non-nil case
+func helperEqualNil(w *codeWriter, a, b string) {
+	w.line("if (%s == nil) != (%s == nil) {", a, b)
+	w.line("return false")
+	w.line("} else if %s != nil {", a)
+}
+
+// helperEqualIds writes the code comparing the IDs of the given related objects (pointers)
+func helperEqualIds(w *codeWriter, target, a, b string) {
+	w.line("if %s; err != nil {", relatedIdCode(target, a, "aId"))
+	w.line("return false")
+	w.line("} else if %s; err != nil || aId != bId {", relatedIdCode(target, b, "bId"))
+	w.line("return false")
+	w.line("}")
+}
+
+// helperEqualValues writes the code comparing the given values of a basic Go type, e.g. "int64" or "[]string"
+func helperEqualValues(w *codeWriter, goType string, a, b string) {
+	if strings.HasPrefix(goType, "[]") {
+		w.line("if len(%s) != len(%s) {", a, b)
+		w.line("return false")
+		w.line("}")
+		w.line("for i := range %s {", a)
+		w.line("if %s[i] != %s[i] {", a, b)
+		w.line("return false")
+		w.line("}")
+		w.line("}")
+	} else {
+		w.line("if %s != %s {", a, b)
+		w.line("return false")
+		w.line("}")
+	}
+}
+
+func helperEqual(w *codeWriter, fields []*Field) {
+	for _, field := range fields {
+		var a = "obj." + field.Path()
+		var b = "other." + field.Path()
+		switch {
+		case field.StandaloneRelation != nil:
+			var target = field.StandaloneRelation.Target.Name
+			w.line("if len(%s) != len(%s) {", a, b)
+			w.line("return false")
+			w.line("}")
+			w.line("for i := range %s {", a)
+			if strings.HasPrefix(field.Type, "[]*") {
+				helperEqualNil(w, a+"[i]", b+"[i]")
+				helperEqualIds(w, target, a+"[i]", b+"[i]")
+				w.line("}")
+			} else {
+				helperEqualIds(w, target, "&"+a+"[i]", "&"+b+"[i]")
+			}
+			w.line("}")
+
+		case field.isRelationObject():
+			var target = field.Property.ModelProperty.RelationTarget
+			if field.IsPointer {
+				helperEqualNil(w, a, b)
+				helperEqualIds(w, target, a, b)
+				w.line("}")
+			} else {
+				helperEqualIds(w, target, "&"+a, "&"+b)
+			}
+
+		case field.Property != nil:
+			var property = field.Property
+			if field.IsPointer {
+				helperEqualNil(w, a, b)
+			}
+			if property.Converter != nil {
+				// compare the stored values, e.g. time.Time with the precision it's stored with
+				w.line("if aValue, err := %sToDatabaseValue(%s); err != nil {", *property.Converter, a)
+				w.line("return false")
+				w.line("} else if bValue, err := %sToDatabaseValue(%s); err != nil {", *property.Converter, b)
+				w.line("return false")
+				w.line("} else {")
+				helperEqualValues(w, property.GoType, "aValue", "bValue")
+				w.line("}")
+			} else if field.IsPointer {
+				helperEqualValues(w, property.GoType, "(*"+a+")", "(*"+b+")")
+			} else if property.ModelProperty.ArrayLength > 0 {
+				helperEqualValues(w, "array", a, b) // arrays are comparable
+			} else {
+				helperEqualValues(w, property.GoType, a, b)
+			}
+			if field.IsPointer {
+				w.line("}")
+			}
+
+		default: // embedded struct
+			if field.IsPointer {
+				helperEqualNil(w, a, b)
+				helperEqual(w, field.Fields)
+				w.line("}")
+			} else {
+				helperEqual(w, field.Fields)
+			}
+		}
+	}
+}
+
+func helperClone(w *codeWriter, fields []*Field) {
+	for _, field := range fields {
+		var src = "obj." + field.Path()
+		var dst = "clone." + field.Path()
+		switch {
+		case field.StandaloneRelation != nil:
+			w.line("if %s != nil {", src)
+			w.line("%s = append(%s[:0:0], %s...)", dst, src, src)
+			w.line("}")
+
+		case field.isRelationObject():
+			// the related object is shared
+
+		case field.Property != nil:
+			var property = field.Property
+			if property.Converter != nil {
+				// the value is shared, a generic copy isn't possible
+			} else if field.IsPointer {
+				w.line("if %s != nil {", src)
+				w.line("var value = *%s", src)
+				w.line("%s = &value", dst)
+				w.line("}")
+			} else if strings.HasPrefix(property.GoType, "[]") && property.ModelProperty.ArrayLength == 0 {
+				w.line("if %s != nil {", src)
+				w.line("%s = append(%s[:0:0], %s...)", dst, src, src)
+				w.line("}")
+			}
+
+		default: // embedded struct
+			if field.IsPointer {
+				w.line("if %s != nil {", src)
+				w.line("var value = *%s", src)
+				w.line("%s = &value", dst)
+				helperClone(w, field.Fields)
+				w.line("}")
+			} else {
+				helperClone(w, field.Fields)
+			}
+		}
+	}
+}
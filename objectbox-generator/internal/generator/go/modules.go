@@ -0,0 +1,138 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"bufio"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// moduleImport is a Go package providing bindings of entities merged from a module model file
+type moduleImport struct {
+	Alias string
+	Path  string
+}
+
+// moduleImports collects the packages of entities merged from module model files (generator.Options.ModuleModelFiles).
+// The bindings are expected in the same directory as the module model, i.e. where objectbox-gogen generated them.
+// Returns the imports and the package qualifier (including the trailing dot) for each entity name.
+func moduleImports(modelFile string, m *model.ModelInfo) ([]moduleImport, map[string]string, error) {
+	var qualifiers = make(map[string]string)
+	var importsByDir = make(map[string]*moduleImport)
+	var imports []moduleImport
+	var aliases = make(map[string]bool)
+
+	ownDir, err := filepath.Abs(filepath.Dir(modelFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entity := range m.Entities {
+		if len(entity.ModuleModelFile) == 0 {
+			continue
+		}
+
+		dir, err := filepath.Abs(filepath.Dir(entity.ModuleModelFile))
+		if err != nil {
+			return nil, nil, err
+		} else if dir == ownDir {
+			continue // same package, no qualifier
+		}
+
+		imp := importsByDir[dir]
+		if imp == nil {
+			importPath, err := goImportPath(dir)
+			if err != nil {
+				return nil, nil, fmt.Errorf("can't determine Go package of module model %s: %s", entity.ModuleModelFile, err)
+			}
+
+			var alias = goPackageName(dir)
+			for i := 2; aliases[alias]; i++ {
+				alias = goPackageName(dir) + strconv.Itoa(i)
+			}
+			aliases[alias] = true
+
+			imp = &moduleImport{Alias: alias, Path: importPath}
+			importsByDir[dir] = imp
+			imports = append(imports, *imp)
+		}
+		qualifiers[entity.Name] = imp.Alias + "."
+	}
+
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+	return imports, qualifiers, nil
+}
+
+// goImportPath determines the import path of the package in the given (absolute) directory from the closest go.mod
+func goImportPath(dir string) (string, error) {
+	for moduleDir := dir; ; moduleDir = filepath.Dir(moduleDir) {
+		if file, err := os.Open(filepath.Join(moduleDir, "go.mod")); err == nil {
+			var modulePath string
+			var scanner = bufio.NewScanner(file)
+			for scanner.Scan() {
+				if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "module" {
+					modulePath = strings.Trim(fields[1], `"`)
+					break
+				}
+			}
+			file.Close()
+			if len(modulePath) == 0 {
+				return "", fmt.Errorf("no module declaration in %s", filepath.Join(moduleDir, "go.mod"))
+			}
+
+			rel, err := filepath.Rel(moduleDir, dir)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(modulePath, filepath.ToSlash(rel)), nil
+		}
+
+		if filepath.Dir(moduleDir) == moduleDir {
+			return "", fmt.Errorf("go.mod not found in %s or any of its parent directories", dir)
+		}
+	}
+}
+
+// goPackageName reads the package name from the Go files in the given directory, defaulting to the directory name
+func goPackageName(dir string) string {
+	if files, err := filepath.Glob(filepath.Join(dir, "*.go")); err == nil {
+		sort.Strings(files)
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			if f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly); err == nil {
+				return f.Name.Name
+			}
+		}
+	}
+	return filepath.Base(dir)
+}
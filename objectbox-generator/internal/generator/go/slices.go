@@ -0,0 +1,296 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/binding"
+)
+
+// structSlice is the element type of a slice of structs, e.g. `[]Tag` or `[]*Tag`, see sliceOfStructs()
+type structSlice struct {
+	named   *types.Named
+	strct   *types.Struct
+	pointer bool // whether the elements are pointers, i.e. `[]*Tag`
+}
+
+// sliceOfStructs checks the field is an (unnamed) slice of structs declared in the entity package
+func (field *Field) sliceOfStructs(f field, annotation string) (*structSlice, error) {
+	var typ = f.Type()
+	var usage = fmt.Errorf("`%s` annotation is only supported on slices of structs declared in the same package, "+
+		"e.g. []Tag or []*Tag, got %s", annotation, typ.String())
+	if typ.IsNamed() {
+		return nil, usage
+	}
+	baseType, err := typ.UnderlyingOrError()
+	if err != nil {
+		return nil, err
+	}
+	slice, isSlice := baseType.(*types.Slice)
+	if !isSlice {
+		return nil, usage
+	}
+
+	var result = &structSlice{}
+	var elementType = slice.Elem()
+	if pointer, isPointer := elementType.(*types.Pointer); isPointer {
+		result.pointer = true
+		elementType = pointer.Elem()
+	}
+	var isNamed bool
+	if result.named, isNamed = elementType.(*types.Named); !isNamed {
+		return nil, usage
+	} else if result.strct, isNamed = result.named.Underlying().(*types.Struct); !isNamed {
+		return nil, usage
+	} else if pkg := result.named.Obj().Pkg(); pkg == nil || pkg.Path() != field.Entity.binding.Package.Path() {
+		return nil, usage
+	}
+	return result, nil
+}
+
+// flexSlice is a slice of structs annotated `flex`, stored as a FlexBuffers vector of maps (one per element, keyed by
+// the struct field names) using converters generated into the binding, see Property.generatedConverter.
+// Only structs with fields of basic types (numbers, bool, string and []byte) are supported, e.g.
+//
+//	type Tag struct {
+//		Name   string
+//		Weight float32
+//	}
+//
+//	type Task struct {
+//		Id   uint64
+//		Tags []Tag `objectbox:"flex"`
+//	}
+type flexSlice struct {
+	name    string // e.g. "Task.Tags"
+	element string // e.g. "Tag"
+	pointer bool
+	fields  []flexSliceField
+}
+
+type flexSliceField struct {
+	name      string
+	basicKind types.BasicKind // types.Invalid for []byte
+}
+
+// processFlexSlice configures a field annotated `flex` to be stored using generated FlexBuffers converters
+func (field *Field) processFlexSlice(f field, name string) error {
+	var property = field.Property
+	for _, annotation := range []string{"converter", "type", "link", "id", "index", "unique", "encrypted"} {
+		if property.annotations[annotation] != nil {
+			return fmt.Errorf("`flex` annotation can't be combined with `%s`", annotation)
+		}
+	}
+	if len(property.annotations["flex"].Value) != 0 {
+		return errors.New("`flex` annotation value must be empty")
+	}
+
+	slice, err := field.sliceOfStructs(f, "flex")
+	if err != nil {
+		return err
+	}
+	if field.Entity.binding.tinyGoCheck {
+		return fmt.Errorf("`flex` slice %s isn't supported with TinyGo - the FlexBuffers converters use reflection, "+
+			"use a custom converter instead", f.Type().String())
+	}
+
+	var flex = &flexSlice{name: name, element: slice.named.Obj().Name(), pointer: slice.pointer}
+	for i := 0; i < slice.strct.NumFields(); i++ {
+		var structField = slice.strct.Field(i)
+		if tag := slice.strct.Tag(i); len(tag) > 0 && strings.Contains(tag, "objectbox:") {
+			return fmt.Errorf("`flex` slice element %s can't use objectbox annotations, found on field %s",
+				flex.element, structField.Name())
+		}
+		var fieldType = structField.Type()
+		if basic, isBasic := fieldType.(*types.Basic); isBasic && basic.Info()&(types.IsNumeric|types.IsBoolean|types.IsString) != 0 &&
+			basic.Info()&types.IsComplex == 0 {
+			flex.fields = append(flex.fields, flexSliceField{name: structField.Name(), basicKind: basic.Kind()})
+		} else if fieldType.String() == "[]byte" {
+			flex.fields = append(flex.fields, flexSliceField{name: structField.Name(), basicKind: types.Invalid})
+		} else {
+			return fmt.Errorf("`flex` slice element %s has field %s of unsupported type %s - only numbers, bool, "+
+				"string and []byte are supported", flex.element, structField.Name(), fieldType.String())
+		}
+	}
+
+	if len(flex.fields) == 0 {
+		return fmt.Errorf("`flex` slice element %s has no fields", flex.element)
+	}
+
+	property.generatedConverter = flex
+	property.annotations["type"] = &binding.Annotation{Value: "[]byte"}
+	property.annotations["converter"] = &binding.Annotation{Value: entityNameCamel(strings.Replace(name, ".", "", 1)) + "Flex"}
+	if property.annotations["external-type"] == nil {
+		property.annotations["external-type"] = &binding.Annotation{Value: "FlexVector"}
+	}
+	return nil
+}
+
+// converterCode returns the converter functions with the given name
+func (flex *flexSlice) converterCode(converter string) string {
+	var sliceType = "[]" + flex.element
+	if flex.pointer {
+		sliceType = "[]*" + flex.element
+	}
+
+	var code strings.Builder
+	fmt.Fprintf(&code, `
+// %[1]sToDatabaseValue converts %[2]s to a FlexBuffers vector of maps, keyed by the field names
+func %[1]sToDatabaseValue(goValue %[3]s) ([]byte, error) {
+	if goValue == nil {
+		return nil, nil
+	}
+	var vector = make([]interface{}, 0, len(goValue))
+	for _, element := range goValue {
+`, converter, flex.name, sliceType)
+	if flex.pointer {
+		code.WriteString(`		if element == nil {
+			vector = append(vector, nil)
+			continue
+		}
+`)
+	}
+	code.WriteString("\t\tvector = append(vector, map[string]interface{}{\n")
+	for _, field := range flex.fields {
+		fmt.Fprintf(&code, "\t\t\t%q: element.%s,\n", field.name, field.name)
+	}
+	fmt.Fprintf(&code, `		})
+	}
+	return objectbox.SliceFlexConvertToDatabaseValue(vector)
+}
+
+// %[1]sToEntityProperty converts a FlexBuffers vector of maps back to %[2]s
+func %[1]sToEntityProperty(dbValue []byte) (%[3]s, error) {
+	vector, err := objectbox.SliceFlexConvertToEntityProperty(dbValue)
+	if err != nil || vector == nil {
+		return nil, err
+	}
+	var goValue = make(%[3]s, 0, len(vector))
+	for _, item := range vector {
+`, converter, flex.name, sliceType)
+	if flex.pointer {
+		code.WriteString(`		if item == nil {
+			goValue = append(goValue, nil)
+			continue
+		}
+`)
+	}
+	fmt.Fprintf(&code, `		values, isMap := item.(map[string]interface{})
+		if !isMap {
+			return nil, errors.New("unexpected FlexBuffers value in %[1]s, expected a map")
+		}
+		var element %[2]s
+`, flex.name, flex.element)
+	for _, field := range flex.fields {
+		code.WriteString(field.readCode())
+	}
+	if flex.pointer {
+		code.WriteString("\t\tgoValue = append(goValue, &element)\n")
+	} else {
+		code.WriteString("\t\tgoValue = append(goValue, element)\n")
+	}
+	code.WriteString("\t}\n\treturn goValue, nil\n}\n")
+	return code.String()
+}
+
+// readCode returns the code assigning the field from the decoded FlexBuffers map; missing values are left unset.
+// FlexBuffers numbers are decoded as int64, uint64 or float64 and converted to the field type.
+func (field flexSliceField) readCode() string {
+	var goType = types.Typ[field.basicKind].Name()
+	switch {
+	case field.basicKind == types.Invalid:
+		goType = "[]byte"
+		fallthrough
+	case field.basicKind == types.Bool || field.basicKind == types.String:
+		return fmt.Sprintf("\t\tif value, ok := values[%q].(%s); ok {\n\t\t\telement.%s = value\n\t\t}\n",
+			field.name, goType, field.name)
+	}
+	var code = fmt.Sprintf("\t\tswitch value := values[%q].(type) {\n", field.name)
+	for _, decoded := range []string{"int64", "uint64", "float64"} {
+		code += fmt.Sprintf("\t\tcase %s:\n\t\t\telement.%s = %s(value)\n", decoded, field.name, goType)
+	}
+	return code + "\t\t}\n"
+}
+
+// RelatedSlice is a slice of structs annotated `related`, stored as objects of an entity generated for the elements.
+// The generated entity links each element to its owner (removed along with it) and inlines the element fields, e.g.
+// `Items []Item` on entity Task is stored as TaskItems{Id uint64; Task uint64; Value Item}. The elements are put
+// (replacing the previous ones) and loaded together with the owner.
+type RelatedSlice struct {
+	Entity  *Entity // the generated entity, e.g. TaskItems
+	Element string  // the element type, e.g. "Item"
+	Pointer bool    // whether the elements are pointers, i.e. `[]*Item`
+}
+
+// processRelatedSlice creates the entity storing the elements of a field annotated `related`
+func (field *Field) processRelatedSlice(f field) error {
+	var property = field.Property
+	if len(property.annotations) != 1 {
+		return errors.New("`related` annotation can't be combined with other annotations")
+	} else if len(property.annotations["related"].Value) != 0 {
+		return errors.New("`related` annotation value must be empty")
+	} else if field.parent != nil {
+		return errors.New("`related` slices are only supported directly in the entity struct, not in embedded structs")
+	}
+
+	slice, err := field.sliceOfStructs(f, "related")
+	if err != nil {
+		return err
+	}
+
+	var r = field.Entity.binding
+	if pos := slice.named.Obj().Pos(); pos >= r.source.ast.Pos() && pos <= r.source.ast.End() {
+		return fmt.Errorf("%s is an entity (declared in the source file), use a to-many relation instead of `related`",
+			slice.named.Obj().Name())
+	}
+
+	var owner = field.Entity
+	var name = owner.Name + field.Name
+	var link = types.NewVar(token.NoPos, r.Package, owner.Name, types.Typ[types.Uint64])
+	var strct = types.NewStruct([]*types.Var{
+		types.NewVar(token.NoPos, r.Package, "Id", types.Typ[types.Uint64]),
+		link,
+		types.NewField(token.NoPos, r.Package, "Value", slice.named, false),
+	}, []string{"", "objectbox:\"link:" + owner.Name + " cascade\"", "objectbox:\"inline\""})
+
+	related, err := r.createEntity(structFieldList{strct}, name, nil)
+	if err != nil {
+		return fmt.Errorf("can't create entity %s storing the elements: %s", name, err)
+	}
+	related.RelatedOwner = field
+
+	if _, err := owner.ModelEntity.AddBacklink(field.Name, name, owner.Name); err != nil {
+		return err
+	}
+
+	field.Type = "[]" + slice.named.Obj().Name()
+	if slice.pointer {
+		field.Type = "[]*" + slice.named.Obj().Name()
+	}
+	field.Related = &RelatedSlice{Entity: related, Element: slice.named.Obj().Name(), Pointer: slice.pointer}
+	field.Property = nil
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// BenchmarkTemplate is used to generate benchmarks of the FlatBuffers serialization of the entities in a source file
+var BenchmarkTemplate = template.Must(template.New("benchmark").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Benchmarks of the FlatBuffers serialization of the entities declared in {{.Source}}, run with ` + "`go test -bench .`" + `
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+package {{.Binding.Package.Name}}
+
+import (
+	"testing"
+
+	"github.com/google/flatbuffers/go"
+)
+{{range $entity := .Model.EntitiesWithMeta}}
+// Benchmark{{$entity.Name}}Flatten measures serializing {{$entity.Name}} objects to FlatBuffers, as done on put
+func Benchmark{{$entity.Name}}Flatten(b *testing.B) {
+	var object = &{{$entity.Name}}{}
+	var fbb = flatbuffers.NewBuilder(512)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fbb.Reset()
+		if err := {{$entity.Name}}Binding.Flatten(object, fbb, 1); err != nil {
+			b.Fatal(err)
+		}
+		fbb.Finish(fbb.EndObject())
+	}
+}
+
+// Benchmark{{$entity.Name}}Load measures reading {{$entity.Name}} objects from FlatBuffers, as done on get
+func Benchmark{{$entity.Name}}Load(b *testing.B) {
+	{{- if $entity.Meta.HasEagerStandaloneRelations}}
+	b.Skip("{{$entity.Name}} has standalone relations which can only be loaded using a store")
+	{{- else}}
+	var fbb = flatbuffers.NewBuilder(512)
+	if err := {{$entity.Name}}Binding.Flatten(&{{$entity.Name}}{}, fbb, 1); err != nil {
+		b.Fatal(err)
+	}
+	fbb.Finish(fbb.EndObject())
+	var data = fbb.FinishedBytes()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := {{$entity.Name}}Binding.Load(nil, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	{{- end}}
+}
+{{end -}}
+{{block "file-footer" .}}{{end}}`))
@@ -28,15 +28,17 @@ import (
 var BindingTemplate = template.Must(template.New("binding").Funcs(funcMap).Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT. 
 // Learn more about defining entities and generating this file - visit https://golang.objectbox.io/entity-annotations
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
 {{define "property-getter-with-converter-val"}}{{/* used in Load*/}}
-	{{- if .Converter}} prop{{.Name}}
+	{{- if or .Converter .ModelProperty.ArrayLength}} prop{{.Name}}
 	{{- else}} {{template "property-getter" .}}
 	{{- end}}
 {{- end -}}
 {{define "property-getter"}}{{/* used in Load*/}}
 	{{- if .CastOnWrite}}{{.CastOnWrite}}({{end}}
-		{{- if eq .FbType "UOffsetT"}} fbutils.Get{{.ObTypeString}}{{if .GoField.IsPointer}}Ptr{{end}}Slot(table, {{.ModelProperty.FbvTableOffset}})
+		{{- if .Float16Vector}} {{.Float16Vector}}Slot(table, {{.ModelProperty.FbvTableOffset}})
+		{{- else if eq .FbType "UOffsetT"}} fbutils.Get{{.ObTypeString}}{{if .GoField.IsPointer}}Ptr{{end}}Slot(table, {{.ModelProperty.FbvTableOffset}})
     	{{- else}} fbutils.Get{{.GoType | StringTitle}}{{if .GoField.IsPointer}}Ptr{{end}}Slot(table, {{.ModelProperty.FbvTableOffset}})
     	{{- end}}
 	{{- if .CastOnWrite}}){{end}}
@@ -44,6 +46,7 @@ var BindingTemplate = template.Must(template.New("binding").Funcs(funcMap).Parse
 
 {{define "property-access"}}{{/* used in Flatten*/ -}}
 	{{- if .Converter}} {{if .GoField.IsPointer}}*{{end}}prop{{.Name}}
+	{{- else if .ModelProperty.ArrayLength}}obj.{{.Path}}[:]
 	{{- else if .CastOnRead}}{{.CastOnRead}}({{if .GoField.IsPointer}}*{{end}}obj.{{.Path}})
 	{{- else}}{{if .GoField.IsPointer}}*{{end}}obj.{{.Path}}{{end}}
 {{- end -}}
@@ -53,6 +56,10 @@ package {{.Binding.Package.Name}}
 
 import (
 	"errors"
+	{{- if .EntityHelpers}}
+	"fmt"
+	"strings"
+	{{- end}}
 	"github.com/google/flatbuffers/go"
 	"github.com/objectbox/objectbox-go/objectbox"
 	"github.com/objectbox/objectbox-go/objectbox/fbutils"
@@ -63,6 +70,15 @@ import (
 
 {{range $entity := .Model.EntitiesWithMeta -}}
 {{$entityNameCamel := $entity.Name | StringCamel -}}
+{{with $entity.Meta.RelatedOwner -}}
+// {{$entity.Name}} stores an element of {{.Entity.Name}}.{{.Name}} (annotated ` + "`related`" + `), linked to its owner
+type {{$entity.Name}} struct {
+	Id    uint64
+	{{.Entity.Name}} uint64 ` + "`" + `objectbox:"link:{{.Entity.Name}} cascade"` + "`" + `
+	Value {{.Related.Element}} ` + "`" + `objectbox:"inline"` + "`" + `
+}
+
+{{end -}}
 type {{$entityNameCamel}}_EntityInfo struct {
 	objectbox.Entity
 	Uid uint64
@@ -78,6 +94,8 @@ var {{$entity.Name}}Binding = {{$entityNameCamel}}_EntityInfo {
 // {{$entity.Name}}_ contains type-based Property helpers to facilitate some common operations such as Queries. 
 var {{$entity.Name}}_ = struct {
 	{{range $property := $entity.Properties -}}
+		{{range $comment := $property.Comments}}//{{with $comment}} {{.}}{{end}}
+		{{end -}}
     	{{$property.Meta.Name}} *objectbox.{{with $property.RelationTarget}}RelationToOne{{else}}Property{{$property.Meta.GoType | TypeIdentifier}}{{end}}
     {{end -}}
 	{{range $relation := $entity.Relations -}}
@@ -186,6 +204,20 @@ func ({{$entityNameCamel}}_EntityInfo) PutRelated(ob *objectbox.ObjectBox, objec
 				return err
 			}
 			{{if $field.IsLazyLoaded}} } {{end}}
+		{{- else if $field.Related}}{{with $field.Related}}
+			if _, err := BoxFor{{.Entity.Name}}(ob).Query({{.Entity.Name}}_.{{$field.Entity.Name}}.Equals(id)).Remove(); err != nil {
+				return err
+			}
+			for _, element := range object.(*{{$field.Entity.Name}}).{{$field.Path}} {
+				{{if .Pointer}}if element == nil {
+					continue
+				}
+				{{end -}}
+				if _, err := BoxFor{{.Entity.Name}}(ob).Put(&{{.Entity.Name}}{ {{- $field.Entity.Name}}: id, Value: {{if .Pointer}}*{{end}}element}); err != nil {
+					return err
+				}
+			}
+		{{- end}}
 		{{- else if $field.Property}}
 			{{- if and (not $field.Property.IsBasicType) $field.Property.ModelProperty.RelationTarget}}
 			if rel := {{if not $field.IsPointer}}&{{end}}object.(*{{$field.Entity.Name}}).{{$field.Path}}; rel != nil {
@@ -231,12 +263,18 @@ func ({{$entityNameCamel}}_EntityInfo) Flatten(object interface{}, fbb *flatbuff
 	}
 	{{end}}{{end}}
 
+	{{- range $property := $entity.Properties}}{{if $property.Meta.RangeCheck}}
+	if {{$property.Meta.CastOnWrite}}({{$property.Meta.CastOnRead}}(obj.{{$property.Meta.Path}})) != obj.{{$property.Meta.Path}} {
+		return errors.New("value of {{$entity.Name}}.{{$property.Meta.Path}} is out of range for the stored type {{$property.Meta.GoType}}")
+	}
+	{{end}}{{end}}
+
     {{- range $property := $entity.Properties}}{{if eq $property.Meta.FbType "UOffsetT"}}
 	{{if $property.Meta.GoField.IsPointer}}
 	var offset{{$property.Meta.Name}} flatbuffers.UOffsetT
 	if obj.{{$property.Meta.Path}} != nil {
 	{{else}}var {{end -}}
-	offset{{$property.Meta.Name}} = fbutils.Create{{$property.Meta.ObTypeString}}Offset(fbb, {{template "property-access" $property.Meta}})
+	offset{{$property.Meta.Name}} = {{with $property.Meta.Float16Vector}}{{.}}Offset{{else}}fbutils.Create{{$property.Meta.ObTypeString}}Offset{{end}}(fbb, {{template "property-access" $property.Meta}})
 	{{- if $property.Meta.GoField.IsPointer -}} } {{- end}}
 	{{- end}}{{end}}
 
@@ -308,6 +346,15 @@ func ({{$entityNameCamel}}_EntityInfo) Load(ob *objectbox.ObjectBox, bytes []byt
 		return nil, errors.New("converter {{$property.Meta.Converter}}ToEntityProperty() failed on {{$entity.Name}}.{{$property.Meta.Path}}: " + err.Error())
 	}
 	{{end}}{{end}}
+
+	{{- range $property := $entity.Properties}}{{if $property.ArrayLength}}
+	var prop{{$property.Name}} [{{$property.ArrayLength}}]float32
+	if slice := {{template "property-getter" $property.Meta}}; len(slice) == len(prop{{$property.Name}}) {
+		copy(prop{{$property.Name}}[:], slice)
+	} else if len(slice) != 0 {
+		return nil, errors.New("can't load {{$entity.Name}}.{{$property.Meta.Path}} - expected {{$property.ArrayLength}} elements")
+	}
+	{{end}}{{end}}
 	
 	{{- block "load-relations" $entity}}
 	{{- range $field := .Meta.Fields}}
@@ -322,6 +369,17 @@ func ({{$entityNameCamel}}_EntityInfo) Load(ob *objectbox.ObjectBox, bytes []byt
 				rel{{$field.Name}} = rSlice
 			}
 			{{- end -}} {{/* see Fetch* for lazy loaded relations */}}
+		{{else if $field.Related -}}{{with $field.Related}}
+			var rel{{$field.Name}} {{$field.Type}}
+			if rSlice, err := BoxFor{{.Entity.Name}}(ob).Query({{.Entity.Name}}_.{{$field.Entity.Name}}.Equals(prop{{$field.Entity.ModelEntity.IdProperty.Name}})).Find(); err != nil {
+				return nil, err
+			} else if len(rSlice) > 0 {
+				rel{{$field.Name}} = make({{$field.Type}}, 0, len(rSlice))
+				for i := range rSlice {
+					rel{{$field.Name}} = append(rel{{$field.Name}}, {{if .Pointer}}&{{end}}rSlice[i].Value)
+				}
+			}
+		{{end}}
 		{{else if $field.Property -}}
 			{{- if and (not $field.Property.IsBasicType) $field.Property.ModelProperty.RelationTarget }}
 			var rel{{$field.Name}} *{{$field.Type}}
@@ -353,6 +411,7 @@ func ({{$entityNameCamel}}_EntityInfo) Load(ob *objectbox.ObjectBox, bytes []byt
 					{{- if $field.IsLazyLoaded}}nil, // use {{$field.Entity.Name}}Box::Fetch{{$field.Name}}() to fetch this lazy-loaded relation
 					{{- else}}rel{{$field.Name}}
 					{{- end}}
+				{{- else if $field.Related}}rel{{$field.Name}}
 				{{- else if $field.Property}}
 					{{- if and (not $field.Property.IsBasicType) $field.Property.ModelProperty.RelationTarget}}{{if not $field.IsPointer}}*{{end}}rel{{$field.Name}}
 					{{- else if $field.Property.ModelProperty.IsIdProperty}} prop{{$field.Property.Name}}
@@ -512,6 +571,42 @@ func (box *{{$entity.Name}}Box) GetAll() ([]{{if not $.ByValue}}*{{end}}{{$entit
 	{{- else if not .Property}}{{/* recursively visit fields in embedded structs */}}{{template "fetch-related" $field}}
 	{{- end}}
 {{- end}}{{end}}
+{{range $field := $entity.Meta.Backlinks}}{{with $field.Backlink}}
+// Fetch{{$field.Name}} reads the backlinks of relation {{.Source.Name}}::{{.Property.Meta.Name}}, i.e. all {{.Source.Name}} objects
+// pointing to each source object, and sets sourceObject.{{$field.Name}} to the slice of them, as currently stored in DB.
+func (box *{{$entity.Name}}Box) Fetch{{$field.Name}}(sourceObjects ...*{{$entity.Name}}) error {
+	var slices = make([]{{$field.Type}}, len(sourceObjects))
+	err := box.ObjectBox.RunInReadTx(func() error {
+		// collect slices before setting the source objects' fields
+		// this keeps all the sourceObjects untouched in case there's an error during any of the requests
+		for k, object := range sourceObjects {
+			{{with $entity.IdProperty -}}
+			{{if .Meta.Converter -}}
+			sourceId, err := {{.Meta.Converter}}ToDatabaseValue(object.{{.Meta.Path}})
+			if err != nil {
+				return err
+			}
+			{{else -}}
+			var sourceId = {{if not (eq .Meta.GoType "uint64")}}uint64(object.{{.Meta.Path}}){{else}}object.{{.Meta.Path}}{{end}}
+			var err error
+			{{end -}}
+			{{end -}}
+			slices[k], err = BoxFor{{.Source.Name}}(box.ObjectBox).Query({{.Source.Name}}_.{{.Property.Meta.Name}}.Equals(sourceId)).Find()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err == nil { // update the field on all objects if we got all slices
+		for k := range sourceObjects {
+			sourceObjects[k].{{$field.Name}} = slices[k]
+		}
+	}
+	return err
+}
+{{end}}{{end}}
 
 // Remove deletes a single object
 func (box *{{$entity.Name}}Box) Remove(object *{{$entity.Name}}) error {
@@ -563,8 +658,26 @@ func (box *{{$entity.Name}}Box) QueryOrError(conditions ...objectbox.Condition)
 		return &{{$entity.Name}}Query{query}, nil
 	}
 }
-
-// Async provides access to the default Async Box for asynchronous operations. See {{$entity.Name}}AsyncBox for more information.
+{{range $property := $entity.Properties}}{{if $property.HnswParams}}
+// QueryNearest{{$property.Meta.Name}} creates a query finding up to maxCount objects with the {{$property.Meta.Name}} vector nearest
+// to the given one, using the HNSW index{{with $property.HnswParams.Dimensions}} ({{.}} dimensions){{end}}. Additional conditions filter the results.
+// It's a shortcut for {{$entity.Name}}_.{{$property.Meta.Name}}.NearestNeighbors() and works just like Query().
+func (box *{{$entity.Name}}Box) QueryNearest{{$property.Meta.Name}}(queryVector []float32, maxCount int, conditions ...objectbox.Condition) *{{$entity.Name}}Query {
+	return box.Query(append([]objectbox.Condition{ {{- $entity.Name}}_.{{$property.Meta.Name}}.NearestNeighbors(queryVector, maxCount)}, conditions...)...)
+}
+{{end}}{{end}}
+{{range $property := $entity.Properties}}{{if $property.Meta.IsExternalId}}
+// GetBy{{$property.Meta.Name}} reads the object with the given {{$property.Meta.Name}}, a unique external ID.
+//
+// Returns nil (and no error) in case there's no such object.
+func (box *{{$entity.Name}}Box) GetBy{{$property.Meta.Name}}(value string) (*{{$entity.Name}}, error) {
+	objects, err := box.Query({{$entity.Name}}_.{{$property.Meta.Name}}.Equals(value, true)).Limit(1).Find()
+	if err != nil || len(objects) == 0 {
+		return nil, err
+	}
+	return {{if $.ByValue}}&{{end}}objects[0], nil
+}
+{{end}}{{end}}// Async provides access to the default Async Box for asynchronous operations. See {{$entity.Name}}AsyncBox for more information.
 func (box *{{$entity.Name}}Box) Async() *{{$entity.Name}}AsyncBox {
 	return &{{$entity.Name}}AsyncBox{AsyncBox: box.Box.Async()}
 }
@@ -655,4 +768,7 @@ func (query *{{$entity.Name}}Query) Limit(limit uint64) *{{$entity.Name}}Query {
 	query.Query.Limit(limit)
 	return query
 }
-{{end -}}`))
+{{if $.EntityHelpers}}{{$entity.Meta.HelperMethods}}{{end -}}
+{{range $entity.Meta.WellKnownConverters}}{{.}}{{end}}
+{{- $entity.Meta.Float16VectorFunctions}}
+{{end -}}{{block "file-footer" .}}{{end}}`))
@@ -0,0 +1,49 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// ExportTemplate is used to generate functions exporting objects of the entities in a source file to JSON and CSV and
+// importing them back
+var ExportTemplate = template.Must(template.New("export").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Export and import of the entities declared in {{.Source}} as JSON (Export<Entity>JSON, Import<Entity>JSON) and CSV
+// (Export<Entity>CSV, Import<Entity>CSV), e.g. for backups and migrations.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+package {{.Binding.Package.Name}}
+
+import (
+	{{- range .Imports}}
+	"{{.}}"
+	{{- end}}
+
+	"github.com/google/flatbuffers/go"
+	"github.com/objectbox/objectbox-go/objectbox"
+	"github.com/objectbox/objectbox-go/objectbox/fbutils"
+)
+{{range $entity := .Model.EntitiesWithMeta}}
+{{- $entity.Meta.ExportFunctions}}
+{{- end}}
+{{block "file-footer" .}}{{end}}`))
@@ -0,0 +1,45 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// FixturesTemplate is used to generate functions creating objects of the entities in a source file for tests
+var FixturesTemplate = template.Must(template.New("fixtures").Funcs(funcMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// Test fixtures for the entities declared in {{.Source}}: objects with defaults (New<Entity>Fixture) or seeded
+// pseudo-random values (Random<Entity>Fixture).
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+package {{.Binding.Package.Name}}
+{{with .Imports}}
+import (
+	{{- range .}}
+	"{{.}}"
+	{{- end}}
+)
+{{end}}
+{{- range $entity := .Model.EntitiesWithMeta}}
+{{- $entity.Meta.FixtureFunctions}}
+{{- end}}
+{{block "file-footer" .}}{{end}}`))
@@ -27,11 +27,15 @@ import (
 // ModelTemplate is used to generate the model initialization code
 var ModelTemplate = template.Must(template.New("model").Parse(
 	`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
 package {{.Package}}
 
 import (
 	"github.com/objectbox/objectbox-go/objectbox"
+	{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+	{{- end}}
 )
 
 // ObjectBoxModel declares and builds the model from all the entities in the package. 
@@ -41,11 +45,37 @@ func ObjectBoxModel() *objectbox.Model {
 	model.GeneratorVersion({{.GeneratorVersion}})
 
 	{{range $entity := .Model.Entities -}}
-	model.RegisterBinding({{$entity.Name}}Binding)
+	model.RegisterBinding({{index $.Qualifiers $entity.Name}}{{$entity.Name}}Binding)
 	{{end -}}
 	model.LastEntityId({{.Model.LastEntityId.GetId}}, {{.Model.LastEntityId.GetUid}})
 	{{if .Model.LastIndexId}}model.LastIndexId({{.Model.LastIndexId.GetId}}, {{.Model.LastIndexId.GetUid}}){{end}}
 	{{if .Model.LastRelationId}}model.LastRelationId({{.Model.LastRelationId.GetId}}, {{.Model.LastRelationId.GetUid}}){{end}}
 
 	return model
-}`))
+}
+{{- if .Model.SchemaVersion}}
+
+// ObjectBoxSchemaVersion is incremented by the generator whenever the model changes. Together with
+// ObjectBoxSchemaAddedIn, it lets the app run data backfills for objects stored by older app versions.
+const ObjectBoxSchemaVersion = {{.Model.SchemaVersion}}
+
+// ObjectBoxSchemaAddedIn maps entities ("Entity") and their properties and relations ("Entity.name") to the
+// ObjectBoxSchemaVersion they were added in.
+var ObjectBoxSchemaAddedIn = map[string]int{
+	{{- range $element := .Model.SchemaElementVersions}}
+	"{{$element.Name}}": {{$element.Version}},
+	{{- end}}
+}
+{{- end}}
+{{- with .ExternalMapping}}
+
+// ObjectBoxExternalMapping describes the external names and types of all entities and their properties and relations
+// (JSON), e.g. to configure the ObjectBox Sync MongoDB connector directly from the generated code.
+const ObjectBoxExternalMapping = {{.}}
+{{- end}}
+{{- with .CascadeDeletes}}
+
+// ObjectBoxCascadeDeletes lists the relations annotated with ` + "`cascade`" + ` (JSON): removing an object of "entity" should
+// also remove the related objects of "target". It's up to the application (or a runtime library) to honor the rules.
+const ObjectBoxCascadeDeletes = {{.}}
+{{- end}}{{block "file-footer" .}}{{end}}`))
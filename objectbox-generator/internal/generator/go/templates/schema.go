@@ -0,0 +1,298 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// SchemaTemplate is used to generate a FlatBuffers schema equivalent to the entities declared in a Go source file
+// (or built in memory, see generator.ProcessModel())
+var SchemaTemplate = template.Must(template.New("schema").Funcs(schemaFuncMap).Parse(
+	`// Code generated by ObjectBox; DO NOT EDIT.
+// FlatBuffers schema of the entities declared in {{.Source}}, e.g. to generate ObjectBox bindings for other languages.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+{{range $enum := FbsEnums .Entities}}
+{{range $comment := $enum.Comments}}///{{with $comment}} {{.}}{{end}}
+{{end -}}
+enum {{$enum.Name}} : {{FbsEnumType $enum}} {
+	{{- range $value := $enum.Values}}
+	{{range $comment := $value.Comments}}///{{with $comment}} {{.}}{{end}}
+	{{end -}}
+	{{$value.Name}} = {{FbsEnumValue $enum $value}},
+	{{- end}}
+}
+{{end -}}
+{{range $entity := .Entities}}
+{{range $comment := $entity.Comments}}///{{with $comment}} {{.}}{{end}}
+{{end -}}
+{{with FbsEntityAnnotations $entity}}/// objectbox:{{.}}
+{{end -}}
+{{range $relation := $entity.Relations}}/// objectbox:{{FbsRelationAnnotation $relation}}
+{{end -}}
+table {{$entity.Name}} {
+	{{- range $property := $entity.Properties}}
+	{{range $comment := $property.Comments}}///{{with $comment}} {{.}}{{end}}
+	{{end -}}
+	{{with FbsPropertyAnnotations $property}}/// objectbox:{{.}}
+	{{end -}}
+	{{FbsFieldName $property.Name}}: {{FbsType $property}};
+	{{- end}}
+}
+{{end}}{{block "file-footer" .}}{{end}}`))
+
+var schemaFuncMap = template.FuncMap{
+	"FbsEnums":               fbsEnums,
+	"FbsEnumType":            fbsEnumType,
+	"FbsEnumValue":           fbsEnumValue,
+	"FbsType":                fbsType,
+	"FbsFieldName":           fbsFieldName,
+	"FbsEntityAnnotations":   fbsEntityAnnotations,
+	"FbsRelationAnnotation":  fbsRelationAnnotation,
+	"FbsPropertyAnnotations": fbsPropertyAnnotations,
+}
+
+// fbsEnums returns enums used by the given entities which can be declared in the schema. FlatBuffers requires
+// enum fields to default to a declared value and Go fields default to zero, thus enums without a zero value are skipped.
+func fbsEnums(entities []*model.Entity) []*model.Enum {
+	var result []*model.Enum
+	for _, enum := range model.EnumsOf(entities) {
+		if fbsEnumHasZero(enum) {
+			result = append(result, enum)
+		}
+	}
+	return result
+}
+
+func fbsEnumHasZero(enum *model.Enum) bool {
+	for _, value := range enum.Values {
+		if value.Value == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fbsEnumType returns the FlatBuffers schema type underlying the given enum
+func fbsEnumType(enum *model.Enum) (string, error) {
+	var property = &model.Property{Name: enum.Name, Type: enum.Type, Entity: &model.Entity{}}
+	if enum.Unsigned {
+		property.Flags = model.PropertyFlagUnsigned
+	}
+	return fbsType(property)
+}
+
+// fbsEnumValue formats the value of the given enum constant, respecting the signedness of the enum
+func fbsEnumValue(enum *model.Enum, value *model.EnumValue) string {
+	if enum.Unsigned {
+		return fmt.Sprintf("%d", uint64(value.Value))
+	}
+	return fmt.Sprintf("%d", value.Value)
+}
+
+// fbsType returns the FlatBuffers schema type of the given property, as understood by the FlatBuffers schema reader
+func fbsType(property *model.Property) (string, error) {
+	if property.Enum != nil && property.Type == property.Enum.Type && property.Flags&model.PropertyFlagId == 0 && fbsEnumHasZero(property.Enum) {
+		return property.Enum.Name, nil
+	}
+
+	var prefix string
+	if property.Flags&(model.PropertyFlagUnsigned|model.PropertyFlagId) != 0 { // IDs are unsigned by definition
+		prefix = "u"
+	}
+
+	switch property.Type {
+	case model.PropertyTypeBool:
+		return "bool", nil
+	case model.PropertyTypeByte:
+		return prefix + "byte", nil
+	case model.PropertyTypeShort:
+		return prefix + "short", nil
+	case model.PropertyTypeInt:
+		return prefix + "int", nil
+	case model.PropertyTypeLong, model.PropertyTypeDate, model.PropertyTypeDateNano:
+		return prefix + "long", nil
+	case model.PropertyTypeRelation:
+		return "ulong", nil
+	case model.PropertyTypeFloat:
+		return "float", nil
+	case model.PropertyTypeDouble:
+		return "double", nil
+	case model.PropertyTypeString:
+		return "string", nil
+	case model.PropertyTypeByteVector:
+		return "[ubyte]", nil
+	case model.PropertyTypeFloatVector:
+		if property.ArrayLength > 0 {
+			return fmt.Sprintf("[float:%d]", property.ArrayLength), nil
+		}
+		return "[float]", nil
+	case model.PropertyTypeStringVector:
+		return "[string]", nil
+	}
+	return "", fmt.Errorf("property %s.%s: type %s can't be represented in a FlatBuffers schema",
+		property.Entity.Name, property.Name, model.PropertyTypeNames[property.Type])
+}
+
+// fbsFieldName converts a Go field name to the snake_case used by FlatBuffers, e.g. "CustomerId" to "customer_id".
+// Apart from following the convention, this avoids clashes of fields and tables names, e.g. `Customer *Customer`.
+func fbsFieldName(name string) string {
+	var runes = []rune(name)
+	var result []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			result = append(result, '_')
+		}
+		result = append(result, unicode.ToLower(r))
+	}
+	return string(result)
+}
+
+// fbsEntityAnnotations returns the "/// objectbox:" annotations of the given entity, apart from standalone relations
+func fbsEntityAnnotations(entity *model.Entity) string {
+	var result []string
+	if entity.Flags&model.EntityFlagSharedGlobalIds != 0 {
+		result = append(result, "sync(sharedGlobalIds)")
+	} else if entity.Flags&model.EntityFlagSyncEnabled != 0 {
+		result = append(result, "sync")
+	}
+	if len(entity.ExternalName) != 0 {
+		result = append(result, "external-name="+quoted(entity.ExternalName))
+	}
+	result = appendUidAnnotation(result, entity.Id)
+	return strings.Join(result, ", ")
+}
+
+// fbsRelationAnnotation returns the "/// objectbox:" annotation declaring the given standalone relation
+func fbsRelationAnnotation(relation *model.StandaloneRelation) string {
+	var details = []string{"name=" + relation.Name, "to=" + relation.Target.Name}
+	if len(relation.ExternalName) != 0 {
+		details = append(details, "external-name="+quoted(relation.ExternalName))
+	}
+	if relation.ExternalType != 0 {
+		details = append(details, "external-type="+model.ExternalTypeNames[relation.ExternalType])
+	}
+	details = appendUidAnnotation(details, relation.Id)
+	return "relation(" + strings.Join(details, ", ") + ")"
+}
+
+// fbsPropertyAnnotations returns the "/// objectbox:" annotations of the given property
+func fbsPropertyAnnotations(property *model.Property) string {
+	var result []string
+	if fbsFieldName(property.Name) != property.Name {
+		result = append(result, "name="+quoted(property.Name))
+	}
+	if property.Flags&model.PropertyFlagIdSelfAssignable != 0 {
+		result = append(result, "id(assignable)")
+	} else if property.Flags&model.PropertyFlagId != 0 {
+		result = append(result, "id")
+	}
+
+	switch property.Type {
+	case model.PropertyTypeDate:
+		result = append(result, "date")
+	case model.PropertyTypeDateNano:
+		result = append(result, "date-nano")
+	case model.PropertyTypeRelation:
+		result = append(result, "relation="+property.RelationTarget) // implies the index
+	}
+
+	if property.Flags&model.PropertyFlagIdCompanion != 0 {
+		result = append(result, "id-companion")
+	}
+	if property.Flags&model.PropertyFlagUniqueOnConflictReplace != 0 {
+		result = append(result, "unique=replace")
+	} else if property.Flags&model.PropertyFlagUnique != 0 {
+		result = append(result, "unique")
+	}
+
+	if property.Type != model.PropertyTypeRelation {
+		if property.HnswParams != nil {
+			result = append(result, "index=hnsw")
+			result = append(result, fbsHnswAnnotations(property.HnswParams)...)
+		} else if property.Flags&model.PropertyFlagIndexHash != 0 {
+			result = append(result, "index=hash")
+		} else if property.Flags&model.PropertyFlagIndexHash64 != 0 {
+			result = append(result, "index=hash64")
+		} else if property.Flags&model.PropertyFlagIndexed != 0 {
+			result = append(result, "index=value")
+		}
+	}
+
+	if len(property.ExternalName) != 0 {
+		result = append(result, "external-name="+quoted(property.ExternalName))
+	}
+	if property.ExternalType != 0 {
+		result = append(result, "external-type="+model.ExternalTypeNames[property.ExternalType])
+	}
+	result = appendUidAnnotation(result, property.Id)
+	return strings.Join(result, ", ")
+}
+
+func fbsHnswAnnotations(params *model.HnswParams) []string {
+	var result []string
+	if params.Dimensions != nil {
+		result = append(result, fmt.Sprintf("hnsw-dimensions=%d", *params.Dimensions))
+	}
+	if len(params.DistanceType) != 0 {
+		result = append(result, "hnsw-distance-type="+params.DistanceType)
+	}
+	if params.NeighborsPerNode != nil {
+		result = append(result, fmt.Sprintf("hnsw-neighbors-per-node=%d", *params.NeighborsPerNode))
+	}
+	if params.IndexingSearchCount != nil {
+		result = append(result, fmt.Sprintf("hnsw-indexing-search-count=%d", *params.IndexingSearchCount))
+	}
+	if params.ReparationBacklinkProbability != nil {
+		result = append(result, fmt.Sprintf("hnsw-reparation-backlink-probability=%v", *params.ReparationBacklinkProbability))
+	}
+	if params.VectorCacheHintSizeKb != nil {
+		result = append(result, fmt.Sprintf("hnsw-vector-cache-hint-size-kb=%d", *params.VectorCacheHintSizeKb))
+	}
+	if params.Flags != nil && *params.Flags != model.HnswFlagNone {
+		var names []string
+		for flag := model.HnswFlags(1); flag <= *params.Flags; flag <<= 1 {
+			if *params.Flags&flag != 0 {
+				names = append(names, model.HnswFlagNames[flag])
+			}
+		}
+		result = append(result, "hnsw-flags="+quoted(strings.Join(names, "|")))
+	}
+	return result
+}
+
+// appendUidAnnotation adds the uid annotation unless the UID is yet to be assigned, e.g. for entities built in memory
+func appendUidAnnotation(annotations []string, id model.IdUid) []string {
+	if uid, err := id.GetUid(); err == nil { // the model has been validated already, an error means there's no UID
+		return append(annotations, fmt.Sprintf("uid=%d", uid))
+	}
+	return annotations
+}
+
+func quoted(value string) string {
+	return `"` + value + `"`
+}
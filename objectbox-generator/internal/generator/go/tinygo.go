@@ -0,0 +1,42 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+)
+
+// checkTinyGo rejects the options generating code which doesn't compile (or bloats the binary) under TinyGo, see
+// GoGenerator.TinyGoCheck. Unsupported field types are rejected while reading the source.
+func (goGen *GoGenerator) checkTinyGo(options generator.Options) error {
+	if !goGen.TinyGoCheck {
+		return nil
+	}
+	if goGen.EntityHelpers {
+		return fmt.Errorf("entity helpers aren't supported with TinyGo - String() formats the values using fmt, which relies on reflection")
+	}
+	if options.GenerateExport {
+		return fmt.Errorf("export functions aren't supported with TinyGo - they use encoding/json and encoding/csv, which rely on reflection")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// TypeMapping configures how fields of a user-defined Go type are stored, the same way as if the fields had
+// `objectbox:"type:<Type> converter:<Converter>"` annotations.
+//
+// For generic containers (mapped by a name with a type parameter, e.g. "Optional[T]"), Unwrap and Wrap are given
+// instead: the stored type is the type argument of the field, e.g. int64 for Optional[int64], and the converters
+// (using the expressions) are generated into the binding.
+type TypeMapping struct {
+	Type      string `json:"type,omitempty"`      // the stored (basic) Go type, e.g. "string" or "int64"
+	Converter string `json:"converter,omitempty"` // converter functions name prefix, e.g. "decimalString"
+
+	// Unwrap is an expression reading the contained value, "%s" being the container, e.g. "%s.Value" or "%s.Get()"
+	Unwrap string `json:"unwrap,omitempty"`
+
+	// Wrap is an expression creating the container, "%s" being the stored value, e.g. "Some(%s)" or "opt.Of(%s)";
+	// note: it's also called with the zero value when nothing is stored
+	Wrap string `json:"wrap,omitempty"`
+}
+
+// TypeMappings maps Go type names to their TypeMapping. A type name can be given as a full path
+// (e.g. "github.com/shopspring/decimal.Decimal"), with a package name (e.g. "decimal.Decimal") or just by the name,
+// e.g. "Money" for a type declared next to the entities. Prefix the name with "*" to match pointer fields.
+// Generic containers are mapped by the name with a single type parameter, e.g. "Optional[T]" or "opt.Optional[T]",
+// matching (non-pointer) fields of any instantiation with a basic type, e.g. Optional[string].
+type TypeMappings map[string]TypeMapping
+
+// LoadTypeMappings reads type mappings from a JSON file, e.g. {"decimal.Decimal": {"type": "string", "converter": "decimalString"}}
+func LoadTypeMappings(path string) (TypeMappings, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read type mappings file: %s", err)
+	}
+
+	var mappings TypeMappings
+	if err = json.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("can't parse type mappings file %s: %s", path, err)
+	}
+
+	for name, mapping := range mappings {
+		if _, _, generic := genericTypeName(name); generic {
+			if strings.HasPrefix(name, "*") {
+				return nil, fmt.Errorf("invalid type mapping for %s in %s: generic containers can't be mapped as pointers", name, path)
+			} else if len(mapping.Type) != 0 || len(mapping.Converter) != 0 {
+				return nil, fmt.Errorf("invalid type mapping for %s in %s: the type and converter of generic containers "+
+					"are inferred, specify unwrap and wrap instead", name, path)
+			} else if strings.Count(mapping.Unwrap, "%s") != 1 || strings.Count(mapping.Wrap, "%s") != 1 {
+				return nil, fmt.Errorf("invalid type mapping for %s in %s: both unwrap and wrap must be specified, "+
+					"each using %%s exactly once, e.g. {\"unwrap\": \"%%s.Value\", \"wrap\": \"Some(%%s)\"}", name, path)
+			}
+		} else if len(mapping.Type) == 0 || len(mapping.Converter) == 0 {
+			return nil, fmt.Errorf("invalid type mapping for %s in %s: both type and converter must be specified", name, path)
+		}
+	}
+	return mappings, nil
+}
+
+// find returns the mapping for the given field type, if there's one
+func (mappings TypeMappings) find(typ string) *TypeMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	var pointer string
+	if strings.HasPrefix(typ, "*") {
+		pointer = "*"
+		typ = typ[1:]
+	}
+
+	for _, name := range []string{typ, path.Base(typ), typeBaseName(typ)} {
+		if mapping, found := mappings[pointer+name]; found {
+			return &mapping
+		}
+	}
+	return nil
+}
+
+// genericTypeName splits an instantiated generic type name into the type name and the (single) type argument,
+// e.g. "opt.Optional[int64]" into "opt.Optional" and "int64"
+func genericTypeName(typ string) (name, arg string, ok bool) {
+	var bracket = strings.Index(typ, "[")
+	if bracket <= 0 || !strings.HasSuffix(typ, "]") {
+		return "", "", false
+	}
+	name = typ[:bracket]
+	arg = typ[bracket+1 : len(typ)-1]
+	if len(arg) == 0 || strings.ContainsAny(arg, ",[]") && !strings.HasPrefix(arg, "[]") {
+		return "", "", false // e.g. a slice type "[]byte" or multiple type arguments
+	}
+	return name, arg, true
+}
+
+// findGeneric returns the generic container mapping the field type is an instantiation of, if there's one
+func (mappings TypeMappings) findGeneric(typ string) *genericTypeUse {
+	var name, arg, ok = genericTypeName(typ)
+	if !ok || len(mappings) == 0 {
+		return nil
+	}
+
+	for _, candidate := range []string{name, path.Base(name), typeBaseName(name)} {
+		for key, mapping := range mappings {
+			if keyName, _, generic := genericTypeName(key); generic && keyName == candidate {
+				var mapping = mapping
+				return &genericTypeUse{mapping: &mapping, containerType: name, typeArg: arg}
+			}
+		}
+	}
+	return nil
+}
+
+// genericTypeUse is a field of a generic container type, see Property.genericType
+type genericTypeUse struct {
+	mapping       *TypeMapping
+	containerType string // e.g. "opt.Optional" as used in the generated code, or with the full package path when found
+	typeArg       string // the stored type, e.g. "int64"
+}
+
+// fieldType returns the field type as used in the generated code, e.g. "opt.Optional[int64]"
+func (use *genericTypeUse) fieldType() string {
+	return use.containerType + "[" + use.typeArg + "]"
+}
+
+// converterSuffix returns the converter name suffix, unique for the container and the type argument
+func (use *genericTypeUse) converterSuffix() string {
+	var arg = use.typeArg
+	if strings.HasPrefix(arg, "[]") {
+		arg = arg[2:] + "Vector"
+	}
+	return typeBaseName(use.containerType) + strings.ToUpper(arg[0:1]) + arg[1:]
+}
+
+// converterCode returns the converter functions with the given name
+func (use *genericTypeUse) converterCode(converter string) string {
+	return fmt.Sprintf(`
+// %[1]sToDatabaseValue returns the %[3]s contained in the %[2]s (a generic container type mapping)
+func %[1]sToDatabaseValue(goValue %[2]s) (%[3]s, error) {
+	return %[4]s, nil
+}
+
+// %[1]sToEntityProperty wraps the stored %[3]s into %[2]s
+func %[1]sToEntityProperty(dbValue %[3]s) (%[2]s, error) {
+	return %[5]s, nil
+}
+`, converter, use.fieldType(), use.typeArg, fmt.Sprintf(use.mapping.Unwrap, "goValue"), fmt.Sprintf(use.mapping.Wrap, "dbValue"))
+}
@@ -0,0 +1,137 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gogenerator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// wellKnownType describes how fields of a popular non-basic Go type are stored without any annotations, i.e. the stored
+// type, the external type and the code of the converter functions generated into the binding file.
+type wellKnownType struct {
+	Type         string // the stored (basic) Go type
+	ExternalType string // see model.ExternalTypes
+	Pointer      bool   // whether the type is recognized as a pointer (e.g. *big.Int) or a value (e.g. uuid.UUID)
+	Converter    string // converter name suffix, prefixed by the entity name to avoid collisions between source files
+
+	// code of the converter functions; arguments: converter name, the field type and the package name
+	code string
+}
+
+// wellKnownTypes maps the full type names (package path & name) to their storage. Note: time.Time is handled directly.
+var wellKnownTypes = map[string]wellKnownType{
+	"github.com/google/uuid.UUID": {Type: "[]byte", ExternalType: "Uuid", Converter: "UuidBytes", code: `
+// %[1]sToDatabaseValue converts a %[2]s to the 16 bytes stored (external type Uuid)
+func %[1]sToDatabaseValue(goValue %[2]s) ([]byte, error) {
+	return goValue[:], nil
+}
+
+// %[1]sToEntityProperty converts the stored bytes back to a %[2]s; nothing stored results in %[3]s.Nil
+func %[1]sToEntityProperty(dbValue []byte) (%[2]s, error) {
+	if len(dbValue) == 0 {
+		return %[3]s.Nil, nil
+	}
+	return %[3]s.FromBytes(dbValue)
+}
+`},
+	"math/big.Int": {Type: "[]byte", ExternalType: "Int128", Pointer: true, Converter: "BigIntInt128", code: `
+// %[1]sToDatabaseValue converts a %[2]s to 16 bytes (big-endian two's complement, external type Int128)
+func %[1]sToDatabaseValue(goValue %[2]s) ([]byte, error) {
+	if goValue == nil {
+		return nil, nil
+	}
+	var value = goValue
+	if value.Sign() < 0 {
+		value = new(%[3]s.Int).Add(new(%[3]s.Int).Lsh(%[3]s.NewInt(1), 128), value)
+		if value.BitLen() != 128 {
+			return nil, errors.New("value " + goValue.String() + " doesn't fit into a 128-bit integer")
+		}
+	} else if value.BitLen() > 127 {
+		return nil, errors.New("value " + goValue.String() + " doesn't fit into a 128-bit integer")
+	}
+	return value.FillBytes(make([]byte, 16)), nil
+}
+
+// %[1]sToEntityProperty converts the stored bytes back to a %[2]s; nothing stored results in nil
+func %[1]sToEntityProperty(dbValue []byte) (%[2]s, error) {
+	if len(dbValue) == 0 {
+		return nil, nil
+	} else if len(dbValue) != 16 {
+		return nil, errors.New("invalid Int128 value - expected 16 bytes")
+	}
+	var value = new(%[3]s.Int).SetBytes(dbValue)
+	if dbValue[0]&0x80 != 0 {
+		value.Sub(value, new(%[3]s.Int).Lsh(%[3]s.NewInt(1), 128))
+	}
+	return value, nil
+}
+`},
+}
+
+// wellKnownTypeUse is a field of a well-known type, see Property.wellKnownType
+type wellKnownTypeUse struct {
+	*wellKnownType
+	pkgPath   string // e.g. "github.com/google/uuid"
+	pkgName   string // as imported in the source file, e.g. "uuid"
+	fieldType string // as used in the generated code, e.g. "uuid.UUID" or "*big.Int"
+}
+
+// findWellKnownType returns the well-known type of a field of the given type, if it is one. The type is qualified either
+// by the full package path or, if resolveImport is given, by a package name/alias imported in the source file.
+func findWellKnownType(typ string, resolveImport func(name string) string) *wellKnownTypeUse {
+	var pointer = strings.HasPrefix(typ, "*")
+	typ = strings.TrimPrefix(typ, "*")
+
+	var dot = strings.LastIndex(typ, ".")
+	if dot < 0 {
+		return nil
+	}
+	var use = &wellKnownTypeUse{pkgPath: typ[:dot], pkgName: path.Base(typ[:dot])}
+	if resolveImport != nil {
+		use.pkgName = use.pkgPath
+		if use.pkgPath = resolveImport(use.pkgName); len(use.pkgPath) == 0 {
+			return nil
+		}
+	}
+
+	known, found := wellKnownTypes[use.pkgPath+typ[dot:]]
+	if !found || known.Pointer != pointer {
+		return nil
+	}
+	use.wellKnownType = &known
+	use.fieldType = use.pkgName + typ[dot:]
+	if pointer {
+		use.fieldType = "*" + use.fieldType
+	}
+	return use
+}
+
+// entityNameCamel returns the entity name starting with a lower-case letter, as used for the converter names
+func entityNameCamel(name string) string {
+	return strings.ToLower(name[0:1]) + name[1:]
+}
+
+// converterCode returns the converter functions with the given name
+func (use *wellKnownTypeUse) converterCode(converter string) string {
+	return fmt.Sprintf(use.code, converter, use.fieldType, use.pkgName)
+}
@@ -0,0 +1,83 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	gotemplates "github.com/objectbox/objectbox-generator/v4/internal/generator/go/templates"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// ProcessModel generates bindings for entities built in memory (see model.NewEntity()) instead of declared in source
+// files. The entities are written as a FlatBuffers schema to options.InPath, which must be a ".fbs" file, and processed
+// like any other source, i.e. IDs and UIDs are assigned and kept stable using the model JSON file. Therefore, the code
+// generators must read FlatBuffers schemas (C, C++, JS and docs); the Go generator needs Go sources.
+func ProcessModel(options Options, entities []*model.Entity) error {
+	if !strings.HasSuffix(options.InPath, ".fbs") {
+		return fmt.Errorf("entities built in memory are written as a FlatBuffers schema, expecting a .fbs file path, got %q", options.InPath)
+	}
+	if len(entities) == 0 {
+		return fmt.Errorf("no entities given")
+	}
+	for _, target := range options.Targets() {
+		if target != nil && !target.IsSourceFile(options.InPath) {
+			return fmt.Errorf("code generator %T can't generate from a FlatBuffers schema", target)
+		}
+	}
+
+	// report all the problems at once, before writing the schema, see model.Validate()
+	var errs ErrorList
+	for _, issue := range model.Validate(&model.ModelInfo{Entities: entities}) {
+		errs = append(errs, issue)
+	}
+	if err := errs.err(); err != nil {
+		return err
+	}
+
+	source, err := fbsSchema("a model built in memory", entities)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(options.InPath, source, 0644); err != nil {
+		return fmt.Errorf("can't write schema file %s: %s", options.InPath, err)
+	}
+
+	return Process(options)
+}
+
+// fbsSchema returns a FlatBuffers schema declaring the given entities, described as declared in the given source
+func fbsSchema(source string, entities []*model.Entity) ([]byte, error) {
+	var b bytes.Buffer
+	var tplArguments = struct {
+		Source          string
+		Entities        []*model.Entity
+		TemplateVersion string
+	}{source, entities, TemplateVersion(gotemplates.SchemaTemplate)}
+	if err := gotemplates.SchemaTemplate.Execute(&b, tplArguments); err != nil {
+		return nil, fmt.Errorf("can't write entities as a FlatBuffers schema: %s", err)
+	}
+	return b.Bytes(), nil
+}
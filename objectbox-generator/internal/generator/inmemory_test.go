@@ -0,0 +1,55 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestProcessModelSourceType(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "process-model-source-type")
+	defer remove()
+
+	task, err := model.NewEntity("Task").AddProperty("id", model.PropertyTypeLong, model.PropertyFlagId).Entity()
+	assert.NoErr(t, err)
+
+	// code generators not reading FlatBuffers schemas are rejected
+	var options = generator.Options{
+		InPath:        filepath.Join(dir, "model.fbs"),
+		CodeGenerator: &gogenerator.GoGenerator{},
+	}
+	err = generator.ProcessModel(options, []*model.Entity{task})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "can't generate from a FlatBuffers schema"))
+
+	options.InPath = filepath.Join(dir, "model.go")
+	err = generator.ProcessModel(options, []*model.Entity{task})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "expecting a .fbs file path"))
+}
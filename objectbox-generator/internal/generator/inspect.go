@@ -0,0 +1,281 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Output formats of the inspected model, see InspectedModel.Write()
+const (
+	InspectFormatTree  = "tree"
+	InspectFormatTable = "table"
+	InspectFormatJSON  = "json"
+	InspectFormatYAML  = "yaml"
+)
+
+// InspectFormats lists all formats supported by InspectedModel.Write()
+var InspectFormats = []string{InspectFormatTree, InspectFormatTable, InspectFormatJSON, InspectFormatYAML}
+
+// InspectedModel is a human-friendly view of the model read from the sources, see Inspect()
+type InspectedModel struct {
+	Entities []InspectedEntity `json:"entities"`
+}
+
+// InspectedEntity describes an entity including its properties and standalone relations
+type InspectedEntity struct {
+	Name       string              `json:"name"`
+	Id         model.IdUid         `json:"id"`
+	Flags      []string            `json:"flags"`
+	Properties []InspectedProperty `json:"properties"`
+	Relations  []InspectedRelation `json:"relations"`
+}
+
+// InspectedProperty describes a property; Index and RelationTarget are only set if the property has them
+type InspectedProperty struct {
+	Name           string      `json:"name"`
+	Id             model.IdUid `json:"id"`
+	Type           string      `json:"type"`
+	Flags          []string    `json:"flags"`
+	Index          model.IdUid `json:"index,omitempty"`
+	RelationTarget string      `json:"relationTarget,omitempty"`
+}
+
+// InspectedRelation describes a standalone (many-to-many) relation
+type InspectedRelation struct {
+	Name   string      `json:"name"`
+	Id     model.IdUid `json:"id"`
+	Target string      `json:"target"`
+}
+
+// Inspect reads the sources and merges them with the stored model, same as Validate(), without writing any files.
+// Returns the entities found in the sources with their IDs/UIDs, flags, indexes and relations, e.g. to debug annotations.
+func Inspect(options Options) (*InspectedModel, error) {
+	modelInfo, err := Validate(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = &InspectedModel{Entities: []InspectedEntity{}}
+	for _, entity := range modelInfo.Entities {
+		if !entity.CurrentlyPresent {
+			continue
+		}
+
+		var inspected = InspectedEntity{
+			Name:       entity.Name,
+			Id:         entity.Id,
+			Flags:      entityFlagNames(entity.Flags),
+			Properties: []InspectedProperty{},
+			Relations:  []InspectedRelation{},
+		}
+		for _, property := range entity.Properties {
+			var inspectedProperty = InspectedProperty{
+				Name:           property.Name,
+				Id:             property.Id,
+				Type:           model.PropertyTypeNames[property.Type],
+				Flags:          propertyFlagNames(property.Flags),
+				RelationTarget: property.RelationTarget,
+			}
+			if property.IndexId != nil {
+				inspectedProperty.Index = *property.IndexId
+			}
+			inspected.Properties = append(inspected.Properties, inspectedProperty)
+		}
+		for _, relation := range entity.Relations {
+			var inspectedRelation = InspectedRelation{Name: relation.Name, Id: relation.Id}
+			if relation.Target != nil {
+				inspectedRelation.Target = relation.Target.Name
+			}
+			inspected.Relations = append(inspected.Relations, inspectedRelation)
+		}
+		result.Entities = append(result.Entities, inspected)
+	}
+	return result, nil
+}
+
+// Write prints the model in the given format, one of InspectFormats
+func (inspected *InspectedModel) Write(w io.Writer, format string) error {
+	switch format {
+	case InspectFormatTree:
+		return inspected.writeTree(w)
+	case InspectFormatTable:
+		return inspected.writeTable(w)
+	case InspectFormatJSON:
+		data, err := json.MarshalIndent(inspected, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case InspectFormatYAML:
+		return inspected.writeYAML(w)
+	}
+	return fmt.Errorf("unknown inspect format %q, expecting one of: %s", format, strings.Join(InspectFormats, ", "))
+}
+
+func (inspected *InspectedModel) writeTree(w io.Writer) error {
+	var b strings.Builder
+	for _, entity := range inspected.Entities {
+		fmt.Fprintf(&b, "%s %s%s\n", entity.Name, entity.Id, flagsSuffix(entity.Flags))
+
+		var count = len(entity.Properties) + len(entity.Relations)
+		var branch = func(i int) string {
+			if i == count-1 {
+				return "└── "
+			}
+			return "├── "
+		}
+		for i, property := range entity.Properties {
+			fmt.Fprintf(&b, "%s%s %s %s%s", branch(i), property.Name, property.Id, property.Type, flagsSuffix(property.Flags))
+			if len(property.Index) > 0 {
+				fmt.Fprintf(&b, " index %s", property.Index)
+			}
+			if len(property.RelationTarget) > 0 {
+				fmt.Fprintf(&b, " -> %s", property.RelationTarget)
+			}
+			b.WriteString("\n")
+		}
+		for i, relation := range entity.Relations {
+			fmt.Fprintf(&b, "%srelation %s %s -> %s\n", branch(len(entity.Properties)+i), relation.Name, relation.Id, relation.Target)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (inspected *InspectedModel) writeTable(w io.Writer) error {
+	var tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENTITY\tKIND\tNAME\tID\tTYPE\tFLAGS\tINDEX\tTARGET")
+	for _, entity := range inspected.Entities {
+		fmt.Fprintf(tw, "%s\tentity\t%s\t%s\t\t%s\t\t\n", entity.Name, entity.Name, entity.Id, strings.Join(entity.Flags, ","))
+		for _, property := range entity.Properties {
+			fmt.Fprintf(tw, "%s\tproperty\t%s\t%s\t%s\t%s\t%s\t%s\n", entity.Name, property.Name, property.Id, property.Type,
+				strings.Join(property.Flags, ","), property.Index, property.RelationTarget)
+		}
+		for _, relation := range entity.Relations {
+			fmt.Fprintf(tw, "%s\trelation\t%s\t%s\t\t\t\t%s\n", entity.Name, relation.Name, relation.Id, relation.Target)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeYAML writes the same structure as the JSON output; all strings are quoted so there's no need for escaping rules
+func (inspected *InspectedModel) writeYAML(w io.Writer) error {
+	var b strings.Builder
+	if len(inspected.Entities) == 0 {
+		b.WriteString("entities: []\n")
+	} else {
+		b.WriteString("entities:\n")
+	}
+	for _, entity := range inspected.Entities {
+		fmt.Fprintf(&b, "  - name: %s\n", strconv.Quote(entity.Name))
+		fmt.Fprintf(&b, "    id: %s\n", strconv.Quote(string(entity.Id)))
+		fmt.Fprintf(&b, "    flags: %s\n", yamlList(entity.Flags))
+		if len(entity.Properties) == 0 {
+			b.WriteString("    properties: []\n")
+		} else {
+			b.WriteString("    properties:\n")
+		}
+		for _, property := range entity.Properties {
+			fmt.Fprintf(&b, "      - name: %s\n", strconv.Quote(property.Name))
+			fmt.Fprintf(&b, "        id: %s\n", strconv.Quote(string(property.Id)))
+			fmt.Fprintf(&b, "        type: %s\n", strconv.Quote(property.Type))
+			fmt.Fprintf(&b, "        flags: %s\n", yamlList(property.Flags))
+			if len(property.Index) > 0 {
+				fmt.Fprintf(&b, "        index: %s\n", strconv.Quote(string(property.Index)))
+			}
+			if len(property.RelationTarget) > 0 {
+				fmt.Fprintf(&b, "        relationTarget: %s\n", strconv.Quote(property.RelationTarget))
+			}
+		}
+		if len(entity.Relations) == 0 {
+			b.WriteString("    relations: []\n")
+		} else {
+			b.WriteString("    relations:\n")
+		}
+		for _, relation := range entity.Relations {
+			fmt.Fprintf(&b, "      - name: %s\n", strconv.Quote(relation.Name))
+			fmt.Fprintf(&b, "        id: %s\n", strconv.Quote(string(relation.Id)))
+			fmt.Fprintf(&b, "        target: %s\n", strconv.Quote(relation.Target))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yamlList formats the given strings as a YAML flow sequence, e.g. ["Id", "Unsigned"]
+func yamlList(values []string) string {
+	var quoted = make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func flagsSuffix(flags []string) string {
+	if len(flags) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(flags, ", ") + "]"
+}
+
+// propertyFlagNames returns the names of the flags set, ordered by the flag value
+func propertyFlagNames(flags model.PropertyFlags) []string {
+	var values []int
+	for flag := range model.PropertyFlagNames {
+		if flags&flag != 0 {
+			values = append(values, int(flag))
+		}
+	}
+	sort.Ints(values)
+
+	var names = []string{}
+	for _, value := range values {
+		names = append(names, model.PropertyFlagNames[model.PropertyFlags(value)])
+	}
+	return names
+}
+
+// entityFlagNames returns the names of the flags set, ordered by the flag value
+func entityFlagNames(flags model.EntityFlags) []string {
+	var values []int
+	for flag := range model.EntityFlagNames {
+		if flags&flag != 0 {
+			values = append(values, int(flag))
+		}
+	}
+	sort.Ints(values)
+
+	var names = []string{}
+	for _, value := range values {
+		names = append(names, model.EntityFlagNames[model.EntityFlags(value)])
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestInspect(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "inspect")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `/// objectbox:sync
+/// objectbox:relation(name=tags, to=Tag)
+table Task {
+	id: ulong;
+	/// objectbox:index
+	text: string;
+}
+/// objectbox:sync
+table Tag {
+	id: ulong;
+}
+`)
+	var options = generator.Options{InPath: schemaFile, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+
+	inspected, err := generator.Inspect(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(inspected.Entities))
+	var task = inspected.Entities[0]
+	if task.Name != "Task" {
+		task = inspected.Entities[1]
+	}
+	assert.Eq(t, []string{"SyncEnabled"}, task.Flags)
+	assert.Eq(t, "text String [IndexHash]", task.Properties[1].Name+" "+task.Properties[1].Type+" "+fmt.Sprint(task.Properties[1].Flags))
+	assert.True(t, len(task.Properties[1].Index) > 0)
+	assert.Eq(t, "tags Tag", task.Relations[0].Name+" "+task.Relations[0].Target)
+
+	// nothing is written, not even the model JSON
+	_, err = os.Stat(generator.ModelInfoFile(dir))
+	assert.True(t, os.IsNotExist(err))
+
+	var out bytes.Buffer
+	assert.NoErr(t, inspected.Write(&out, generator.InspectFormatTree))
+	assert.True(t, strings.Contains(out.String(), fmt.Sprintf("├── text %s String [IndexHash] index %s\n", task.Properties[1].Id, task.Properties[1].Index)))
+	assert.True(t, strings.Contains(out.String(), fmt.Sprintf("└── relation tags %s -> Tag\n", task.Relations[0].Id)))
+
+	out.Reset()
+	assert.NoErr(t, inspected.Write(&out, generator.InspectFormatYAML))
+	assert.True(t, strings.Contains(out.String(), "    flags: [\"SyncEnabled\"]\n"))
+	assert.True(t, strings.Contains(out.String(), "        target: \"Tag\"\n"))
+
+	out.Reset()
+	assert.NoErr(t, inspected.Write(&out, generator.InspectFormatJSON))
+	var decoded generator.InspectedModel
+	assert.NoErr(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Eq(t, *inspected, decoded)
+
+	out.Reset()
+	assert.NoErr(t, inspected.Write(&out, generator.InspectFormatTable))
+	assert.True(t, strings.HasPrefix(out.String(), "ENTITY"))
+
+	assert.Err(t, inspected.Write(&out, "xml"))
+}
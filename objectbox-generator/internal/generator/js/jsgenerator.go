@@ -21,18 +21,41 @@
 package jsgenerator
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/flatbuffersc/reflection"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/js/templates"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/yamlschema"
 )
 
+// templatesVersion identifies all the templates used by the JS generator
+var templatesVersion = generator.TemplateVersion(templates.JsBindingTemplate, templates.JsModelTemplate, templates.JsBenchmarkTemplate)
+
+// jsTemplates holds the templates actually used for generating, i.e. including user overrides
+type jsTemplates struct {
+	binding, model, benchmark *template.Template
+	version                   string
+}
+
+// loadTemplates returns the embedded templates with overrides from the given directory (if any) applied
+func loadTemplates(overridesDir string) (*jsTemplates, error) {
+	tpls, version, err := generator.LoadTemplates(overridesDir, templates.JsBindingTemplate, templates.JsModelTemplate, templates.JsBenchmarkTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &jsTemplates{tpls[0], tpls[1], tpls[2], version}, nil
+}
+
 // JS generator, given a .fbs and an optional *model.json file, is responsible for generating:
 // - objectbox-model.js
 // - sche
@@ -40,18 +63,110 @@ type JSGenerator struct {
 	Optional          string // std::optional, std::unique_ptr, std::shared_ptr
 	EmptyStringAsNull bool
 	NaNAsNull         bool
+	StrictSchema      bool     // reject FlatBuffers schema features ObjectBox doesn't honor, e.g. required fields
+	ExtensionHooks    bool     // import optional user-provided <Entity>.custom.js modules extending the generated classes
+	Accessors         bool     // generate private fields with get/set accessors, unless overridden per entity
+	NamespaceModules  bool     // generate a module per FlatBuffers namespace, in a directory named by the namespace
+	IncludeDirs       []string // directories to look up files included by the schema in, see flatbuffersc.ParseSchemaFileWithIncludes()
+	ModuleFormat      string   // ModuleFormatESM (the default if empty) or ModuleFormatCommonJS
+	BrowserSafe       bool     // avoid Node-only APIs (e.g. node:perf_hooks, process) in the generated code
+	VerifyFlatBuffers bool     // verify FlatBuffers before reading them (bounds checks, UTF-8 strings), e.g. for untrusted input
+	TypedArrays       bool     // read float vectors as Float32Array views of the buffer and write typed arrays directly
+	IdType            string   // IdTypeBigInt (the default if empty) or IdTypeString, the type of the ID fields
+	Validation        bool     // generate validate<Entity>() functions, checking objects before toFlatbuffers() writes them
+
+	parseCache *generator.ParseCache // see SetParseCache()
+}
+
+// Module formats of the generated code, see JSGenerator.ModuleFormat
+const (
+	ModuleFormatESM      = "esm"      // ECMAScript modules: import & export
+	ModuleFormatCommonJS = "commonjs" // CommonJS modules: require() & module.exports
+)
+
+// ID field types of the generated classes, see JSGenerator.IdType. IDs are unsigned 64-bit integers, which don't fit
+// into a JS number without losing precision; either way, getId() and setId() use BigInt.
+const (
+	IdTypeBigInt = "bigint" // BigInt, e.g. 42n
+	IdTypeString = "string" // decimal strings, e.g. "42", e.g. for JSON APIs
+)
+
+// commonJS returns true if the generated code should use CommonJS modules instead of ECMAScript ones
+func (gen *JSGenerator) commonJS() bool {
+	return gen.ModuleFormat == ModuleFormatCommonJS
 }
 
-// Return the names of the generated JS binding file (only one!) for the given entity file.
-// For example: given a schema.fbs file, outputs schema.obx.fbs.
-func (gen *JSGenerator) BindingFiles(forFile string, options generator.Options) []string {
+// Return the names of the generated JS binding files for the given entity file.
+// For example: given a schema.fbs file, outputs schema.obx.fbs. With NamespaceModules, there's an additional module
+// for each namespace declared in the schema (and its parents), e.g. shop/orders/schema.obx.js for "shop.orders".
+// With options.GenerateBenchmarks, the second one is the benchmark script, e.g. schema.obx.bench.js.
+func (gen *JSGenerator) BindingFiles(forFile string, options generator.Options) ([]string, error) {
+	var bindingFile = gen.bindingFile(forFile, options)
+	var files = []string{bindingFile}
+	if options.GenerateBenchmarks {
+		files = append(files, benchmarkFile(bindingFile))
+	}
+	if gen.NamespaceModules {
+		// we need to read the schema to find out which namespaces there are
+		m, err := gen.ParseSource(forFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range namespaceModules(m.Entities, m.ConstantGroups) {
+			files = append(files, namespaceModuleFile(bindingFile, ns))
+		}
+	}
+	return files, nil
+}
 
+// bindingFile returns the name of the (root) binding file for the given entity file
+func (gen *JSGenerator) bindingFile(forFile string, options generator.Options) string {
 	if len(options.OutPath) > 0 {
 		forFile = filepath.Join(options.OutPath, filepath.Base(forFile))
 	}
 	var extension = filepath.Ext(forFile)
 	var base = forFile[0 : len(forFile)-len(extension)]
-	return []string{base + ".obx.js"}
+	return base + ".obx.js"
+}
+
+// benchmarkFile returns the name of the benchmark script for the given (root) binding file
+func benchmarkFile(bindingFile string) string {
+	return strings.TrimSuffix(bindingFile, ".js") + ".bench.js"
+}
+
+// namespaceModules returns all namespaces of the given entities and constants, including the parent namespaces, sorted.
+func namespaceModules(entities []*model.Entity, constants []*model.ConstantGroup) []string {
+	var unique = make(map[string]bool)
+	for _, entity := range entities {
+		for ns := entity.Meta.(*fbsObject).Namespace; len(ns) > 0; ns = parentNamespace(ns) {
+			unique[ns] = true
+		}
+	}
+	for _, group := range constants {
+		for ns := group.Namespace; len(ns) > 0; ns = parentNamespace(ns) {
+			unique[ns] = true
+		}
+	}
+
+	var result []string
+	for ns := range unique {
+		result = append(result, ns)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// parentNamespace returns the enclosing namespace, e.g. "shop" for "shop.orders", or an empty string for "shop"
+func parentNamespace(ns string) string {
+	if lastDot := strings.LastIndex(ns, "."); lastDot > 0 {
+		return ns[:lastDot]
+	}
+	return ""
+}
+
+// namespaceModuleFile returns the binding file for the given namespace, placed in a directory named by the namespace
+func namespaceModuleFile(bindingFile string, ns string) string {
+	return filepath.Join(filepath.Dir(bindingFile), filepath.Join(strings.Split(ns, ".")...), filepath.Base(bindingFile))
 }
 
 // Return the model filename for the given model JSON file.
@@ -66,20 +181,25 @@ func (gen *JSGenerator) ModelFile(forFile string, options generator.Options) str
 
 func (JSGenerator) IsGeneratedFile(file string) bool {
 	var name = filepath.Base(file)
-	return name == "objectbox-model.js" || name == "schema.obx.js"
+	return name == "objectbox-model.js" || name == "schema.obx.js" || name == "schema.obx.bench.js"
 }
 
 func (JSGenerator) IsSourceFile(file string) bool {
-	return strings.HasSuffix(file, ".fbs")
+	return strings.HasSuffix(file, ".fbs") || yamlschema.IsSchemaFile(file)
 }
 
 func (gen *JSGenerator) ParseSource(sourceFile string) (*model.ModelInfo, error) {
-	schemaReflection, err := flatbuffersc.ParseSchemaFile(sourceFile)
+	// the same key as in CGenerator.ParseSource() so that the parsed schema is shared when generating both
+	var key = strings.Join(append([]string{"fbs"}, gen.IncludeDirs...), "\n")
+	parsed, err := gen.parseCache.Load(sourceFile, key, func() (interface{}, error) {
+		return flatbuffersc.ParseSchemaFileWithIncludes(sourceFile, gen.IncludeDirs)
+	})
 	if err != nil {
 		return nil, err // already includes file name so no more context should be necessary
 	}
+	var schemaReflection = parsed.(*reflection.Schema)
 
-	reader := fbSchemaReader{model: &model.ModelInfo{}, optional: gen.Optional}
+	reader := fbSchemaReader{model: &model.ModelInfo{}, optional: gen.Optional, accessors: gen.Accessors, stringIds: gen.IdType == IdTypeString, strict: gen.StrictSchema, sourceFile: sourceFile}
 	if err = reader.read(schemaReflection); err != nil {
 		return nil, fmt.Errorf("error generating model from schema %s: %s", sourceFile, err)
 	}
@@ -87,15 +207,116 @@ func (gen *JSGenerator) ParseSource(sourceFile string) (*model.ModelInfo, error)
 	return reader.model, nil
 }
 
-// Generate the schema.obx.js file, given the merged model info
+// RenameEntity implements generator.EntityRenamer - the JS classes are named after the entity
+func (gen *JSGenerator) RenameEntity(entity *model.Entity, name string) {
+	entity.Name = name
+	entity.Meta.(*fbsObject).Name = name
+}
+
+// SetParseCache implements generator.ParseCacheUser
+func (gen *JSGenerator) SetParseCache(cache *generator.ParseCache) {
+	gen.parseCache = cache
+}
+
+// unsupportedPropertyTypes lists property types the JS binding can't fully write & read (see templates.funcMap)
+var unsupportedPropertyTypes = map[model.PropertyType]string{
+	model.PropertyTypeRelation:     "can't be stored",
+	model.PropertyTypeDateNano:     "can't be stored",
+	model.PropertyTypeByteVector:   "isn't read back from the database",
+	model.PropertyTypeStringVector: "can't be stored",
+	model.PropertyTypeFloatVector:  "isn't read back from the database",
+}
+
+// UnsupportedPropertyTypes returns the property types the generated code can't fully write & read, with the reason
+func UnsupportedPropertyTypes() map[model.PropertyType]string {
+	var result = make(map[model.PropertyType]string, len(unsupportedPropertyTypes))
+	for propertyType, reason := range unsupportedPropertyTypes {
+		result[propertyType] = reason
+	}
+	return result
+}
+
+// Unsupported implements generator.StrictChecker - reports properties of types the generated JS code doesn't handle
+func (gen *JSGenerator) Unsupported(currentModel *model.ModelInfo) []string {
+	var result []string
+	for _, entity := range currentModel.Entities {
+		for _, property := range entity.Properties {
+			if reason, found := unsupportedPropertyTypes[property.Type]; found {
+				result = append(result, fmt.Sprintf("property %s.%s of type %s %s", entity.Name, property.Name,
+					model.PropertyTypeNames[property.Type], reason))
+			}
+		}
+	}
+	return result
+}
+
+// Generate the schema.obx.js file (and the namespace modules), given the merged model info
 func (gen *JSGenerator) WriteBindingFiles(sourceFile string, options generator.Options, mergedModel *model.ModelInfo) error {
-	var err, err2 error
+	var bindingFile = gen.bindingFile(sourceFile, options)
+	var entities = mergedModel.EntitiesWithMeta()
 
-	var bindingFile = gen.BindingFiles(sourceFile, options)[0]
+	if options.GenerateBenchmarks {
+		if err := gen.writeBenchmarkFile(sourceFile, bindingFile, options, entities); err != nil {
+			return err
+		}
+	}
+
+	if !gen.NamespaceModules {
+		return gen.writeBindingFile(sourceFile, bindingFile, options, mergedModel, entities, mergedModel.ConstantGroups, nil)
+	}
+
+	var namespaces = namespaceModules(entities, mergedModel.ConstantGroups)
+	for _, ns := range append([]string{""}, namespaces...) {
+		var file = bindingFile
+		if len(ns) > 0 {
+			file = namespaceModuleFile(bindingFile, ns)
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				return fmt.Errorf("can't create the directory for namespace %s: %s", ns, err)
+			}
+		}
+
+		var nsEntities []*model.Entity
+		for _, entity := range entities {
+			if entity.Meta.(*fbsObject).Namespace == ns {
+				nsEntities = append(nsEntities, entity)
+			}
+		}
+
+		// re-export the nested namespaces, e.g. `export * as orders from "./orders/schema.obx.js"` in shop/schema.obx.js
+		var subModules []subModule
+		for _, subNs := range namespaces {
+			if parentNamespace(subNs) == ns {
+				var name = subNs[strings.LastIndex(subNs, ".")+1:]
+				subModules = append(subModules, subModule{name, "./" + name + "/" + filepath.Base(bindingFile)})
+			}
+		}
+
+		var nsConstants []*model.ConstantGroup
+		for _, group := range mergedModel.ConstantGroups {
+			if group.Namespace == ns {
+				nsConstants = append(nsConstants, group)
+			}
+		}
+
+		if err := gen.writeBindingFile(sourceFile, file, options, mergedModel, nsEntities, nsConstants, subModules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subModule is a namespace module re-exported by the module of the parent namespace
+type subModule struct {
+	Name string
+	Path string
+}
+
+func (gen *JSGenerator) writeBindingFile(sourceFile, bindingFile string, options generator.Options, mergedModel *model.ModelInfo, entities []*model.Entity, constants []*model.ConstantGroup, subModules []subModule) error {
+	var err, err2 error
 
 	// First generate the binding source
 	var bindingSource []byte
-	if bindingSource, err = gen.generateBindingFile(bindingFile, mergedModel); err != nil {
+	if bindingSource, err = gen.generateBindingFile(bindingFile, options, mergedModel, entities, constants, subModules); err != nil {
 		return fmt.Errorf("can't generate binding file %s: %s", sourceFile, err)
 	}
 
@@ -106,7 +327,7 @@ func (gen *JSGenerator) WriteBindingFiles(sourceFile string, options generator.O
 		bindingSource = formattedSource
 	}
 
-	if err = generator.WriteFile(bindingFile, bindingSource, sourceFile); err != nil {
+	if err = options.WriteFile(bindingFile, bindingSource, sourceFile); err != nil {
 		return fmt.Errorf("can't write binding file %s: %s", sourceFile, err)
 	} else if err2 != nil {
 		// Now when the binding has been written (for debugging purposes), we can return the error
@@ -116,9 +337,48 @@ func (gen *JSGenerator) WriteBindingFiles(sourceFile string, options generator.O
 	return nil
 }
 
-func (gen *JSGenerator) generateBindingFile(bindingFile string, modelInfo *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
+// writeBenchmarkFile generates the benchmark script for all the given entities, importing them from the binding file
+func (gen *JSGenerator) writeBenchmarkFile(sourceFile, bindingFile string, options generator.Options, entities []*model.Entity) error {
+	var err, err2 error
+	var file = benchmarkFile(bindingFile)
+
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return err
+	}
+
+	var tplArgs = struct {
+		Entities         []*model.Entity
+		FileName         string
+		BindingFile      string
+		NamespaceModules bool
+		CommonJS         bool
+		BrowserSafe      bool
+		TemplateVersion  string
+	}{entities, filepath.Base(file), filepath.Base(bindingFile), gen.NamespaceModules, gen.commonJS(), gen.BrowserSafe, tpls.version}
+
+	source, err := generator.ExecuteTemplate(tpls.benchmark, tplArgs)
+	if err != nil {
+		return fmt.Errorf("can't generate benchmark file %s: template execution failed: %s", file, err)
+	}
+
+	if formattedSource, err := format(source); err != nil {
+		err2 = fmt.Errorf("failed to format generated benchmark file %s: %s", file, err)
+	} else {
+		source = formattedSource
+	}
+
+	if err = options.WriteFile(file, source, sourceFile); err != nil {
+		return fmt.Errorf("can't write benchmark file %s: %s", file, err)
+	}
+	return err2
+}
+
+func (gen *JSGenerator) generateBindingFile(bindingFile string, options generator.Options, modelInfo *model.ModelInfo, entities []*model.Entity, constants []*model.ConstantGroup, subModules []subModule) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var replaceSpecialChars = strings.NewReplacer("-", "_", ".", "_")
 	var fileIdentifier = strings.ToLower(filepath.Base(bindingFile))
@@ -127,32 +387,51 @@ func (gen *JSGenerator) generateBindingFile(bindingFile string, modelInfo *model
 	// Arguments for the template
 	type TplArgs struct {
 		Model             *model.ModelInfo
+		Entities          []*model.Entity
+		Enums             []*model.Enum
+		Constants         []*jsConstantGroup
+		SubModules        []subModule
 		GeneratorVersion  int
 		FileIdentifier    string
 		Optional          string
 		LangVersion       int
 		EmptyStringAsNull bool
 		NaNAsNull         bool
+		ExtensionHooks    bool
+		CommonJS          bool
+		BrowserSafe       bool
+		VerifyFlatBuffers bool
+		TypedArrays       bool
+		Validation        bool
+		TemplateVersion   string
 	}
 	var tplArgs TplArgs
 	tplArgs.Model = modelInfo
+	tplArgs.Entities = entities
+	tplArgs.Enums = model.EnumsOf(entities)
+	for _, group := range constants {
+		tplArgs.Constants = append(tplArgs.Constants, &jsConstantGroup{group})
+	}
+	tplArgs.SubModules = subModules
 	tplArgs.GeneratorVersion = generator.VersionId
 	tplArgs.FileIdentifier = fileIdentifier
 	tplArgs.Optional = gen.Optional
 	tplArgs.EmptyStringAsNull = gen.EmptyStringAsNull
 	tplArgs.NaNAsNull = gen.NaNAsNull
+	tplArgs.ExtensionHooks = gen.ExtensionHooks
+	tplArgs.CommonJS = gen.commonJS()
+	tplArgs.BrowserSafe = gen.BrowserSafe
+	tplArgs.VerifyFlatBuffers = gen.VerifyFlatBuffers
+	tplArgs.TypedArrays = gen.TypedArrays
+	tplArgs.Validation = gen.Validation
+	tplArgs.TemplateVersion = tpls.version
 
-	var tpl = templates.JsBindingTemplate
+	var tpl = tpls.binding
 
-	if err = tpl.Execute(writer, tplArgs); err != nil {
+	if data, err = generator.ExecuteTemplate(tpl, tplArgs); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
-
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
-	}
-
-	return b.Bytes(), nil
+	return data, nil
 }
 
 // Generate the objectbox-model.js, given the merged model info
@@ -162,7 +441,7 @@ func (gen *JSGenerator) WriteModelBindingFile(options generator.Options, mergedM
 	var modelFile = gen.ModelFile(options.ModelInfoFile, options)
 	var modelSource []byte
 
-	if modelSource, err = generateModelFile(mergedModel); err != nil {
+	if modelSource, err = gen.generateModelFile(options, mergedModel); err != nil {
 		return fmt.Errorf("can't generate model file %s: %s", modelFile, err)
 	}
 
@@ -173,7 +452,7 @@ func (gen *JSGenerator) WriteModelBindingFile(options generator.Options, mergedM
 		modelSource = formattedSource
 	}
 
-	if err = generator.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
+	if err = options.WriteFile(modelFile, modelSource, options.ModelInfoFile); err != nil {
 		return fmt.Errorf("can't write model file %s: %s", modelFile, err)
 	} else if err2 != nil {
 		// now when the model has been written (for debugging purposes), we can return the error
@@ -183,24 +462,37 @@ func (gen *JSGenerator) WriteModelBindingFile(options generator.Options, mergedM
 	return nil
 }
 
-func generateModelFile(m *model.ModelInfo) (data []byte, err error) {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
+func (gen *JSGenerator) generateModelFile(options generator.Options, m *model.ModelInfo) (data []byte, err error) {
+	tpls, err := loadTemplates(options.TemplateOverridesDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var tplArguments = struct {
 		Model            *model.ModelInfo
 		GeneratorVersion int
-	}{m, generator.VersionId}
+		CommonJS         bool
+		TemplateVersion  string
+	}{m, generator.VersionId, gen.commonJS(), tpls.version}
 
-	if err = templates.JsModelTemplate.Execute(writer, tplArguments); err != nil {
+	if data, err = generator.ExecuteTemplate(tpls.model, tplArguments); err != nil {
 		return nil, fmt.Errorf("template execution failed: %s", err)
 	}
+	return data, nil
+}
 
-	if err = writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush buffer: %s", err)
-	}
+// TemplateVersion returns an identifier of the JS templates
+func (gen *JSGenerator) TemplateVersion() string {
+	return templatesVersion
+}
 
-	return b.Bytes(), nil
+// CompatibilityOptions implements generator.CompatibilityOptionsProvider; only options changing the stored data are
+// recorded, not those only affecting the generated code (e.g. -optional)
+func (gen *JSGenerator) CompatibilityOptions() map[string]string {
+	return map[string]string{
+		"empty-string-as-null": strconv.FormatBool(gen.EmptyStringAsNull),
+		"nan-as-null":          strconv.FormatBool(gen.NaNAsNull),
+	}
 }
 
 func removeEmptyLines(source []byte) []byte {
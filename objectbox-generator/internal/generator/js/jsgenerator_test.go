@@ -0,0 +1,58 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsgenerator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+func TestUnsupported(t *testing.T) {
+	var entity = &model.Entity{Name: "Task", Properties: []*model.Property{
+		{Name: "id", Type: model.PropertyTypeLong},
+		{Name: "letter", Type: model.PropertyTypeChar},
+		{Name: "data", Type: model.PropertyTypeByteVector},
+		{Name: "embedding", Type: model.PropertyTypeFloatVector},
+	}}
+	var expected = []string{
+		"property Task.data of type ByteVector isn't read back from the database",
+		"property Task.embedding of type FloatVector isn't read back from the database",
+	}
+	var actual = (&JSGenerator{}).Unsupported(&model.ModelInfo{Entities: []*model.Entity{entity}})
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIsGeneratedFile(t *testing.T) {
+	// "clean" must remove the benchmark script as well, see test/comparison/testdata/js/benchmarks for its contents
+	for _, file := range []string{"objectbox-model.js", "schema.obx.js", filepath.Join("shop", "schema.obx.bench.js")} {
+		if !(JSGenerator{}).IsGeneratedFile(file) {
+			t.Errorf("expected %s to be a generated file", file)
+		}
+	}
+	if (JSGenerator{}).IsGeneratedFile("schema.fbs") {
+		t.Errorf("expected schema.fbs not to be a generated file")
+	}
+}
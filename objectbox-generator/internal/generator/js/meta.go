@@ -23,6 +23,7 @@ package jsgenerator
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/binding"
@@ -33,6 +34,12 @@ import (
 type fbsObject struct {
 	*binding.Object
 	fbsObject *reflection.Object
+
+	// generate private fields with get/set accessors instead of public fields
+	accessors bool
+
+	// hold the ID as a decimal string instead of a BigInt, see JSGenerator.IdType
+	stringIds bool
 }
 
 // Merge implements model.EntityMeta interface
@@ -41,9 +48,19 @@ func (mo *fbsObject) Merge(entity *model.Entity) model.EntityMeta {
 	return mo
 }
 
-// JsName returns JS symbol/variable name with reserved keywords suffixed by an underscore
+// Accessors returns true if the members are private, accessed using the generated getters and setters
+func (mo *fbsObject) Accessors() bool {
+	return mo.accessors
+}
+
+// StringIds returns true if the ID field holds a decimal string instead of a BigInt
+func (mo *fbsObject) StringIds() bool {
+	return mo.stringIds
+}
+
+// JsName returns the JS class name, a valid identifier with reserved keywords suffixed by an underscore, see jsName()
 func (mo *fbsObject) JsName() string {
-	return mo.Name
+	return jsName(mo.Name)
 }
 
 type fbsField struct {
@@ -57,9 +74,32 @@ func (mp *fbsField) Merge(property *model.Property) model.PropertyMeta {
 	return mp
 }
 
-// JsName returns C++ variable name with reserved keywords suffixed by an underscore
+// JsName returns the JS field name, a valid identifier with reserved keywords suffixed by an underscore, see jsName()
 func (mp *fbsField) JsName() string {
-	return mp.Name
+	return jsName(mp.Name)
+}
+
+// JsFieldName returns the name of the class field, i.e. JsName() or, with accessors, a private field name
+func (mp *fbsField) JsFieldName() string {
+	if mp.ModelProperty.Entity.Meta.(*fbsObject).accessors {
+		return "#" + mp.JsName()
+	}
+	return mp.JsName()
+}
+
+// JsGetter returns the getter method name used with accessors, e.g. getName (or getClass, keywords aren't suffixed)
+func (mp *fbsField) JsGetter() string {
+	return accessorName("get", mp.Name)
+}
+
+// JsSetter returns the setter method name used with accessors, e.g. setName
+func (mp *fbsField) JsSetter() string {
+	return accessorName("set", mp.Name)
+}
+
+func accessorName(prefix string, name string) string {
+	var identifier = []rune(jsIdentifier(name))
+	return prefix + strings.ToUpper(string(identifier[:1])) + string(identifier[1:])
 }
 
 // JsType returns C++ type name
@@ -206,7 +246,17 @@ func (mr *standaloneRel) Merge(rel *model.StandaloneRelation) model.StandaloneRe
 	return mr
 }
 
-// JsName returns JS variable name with reserved keywords suffixed by an underscore
+// JsName returns the JS name of the relation, a valid identifier with reserved keywords suffixed by an underscore
 func (mr *standaloneRel) JsName() string {
-	return mr.ModelRelation.Name
+	return jsName(mr.ModelRelation.Name)
+}
+
+// jsConstantGroup wraps model.ConstantGroup to provide JS specific template functions
+type jsConstantGroup struct {
+	*model.ConstantGroup
+}
+
+// JsName returns the name of the frozen object holding the constants, a valid identifier, see jsName()
+func (group *jsConstantGroup) JsName() string {
+	return jsName(group.Name)
 }
@@ -0,0 +1,68 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsgenerator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestJSNamespaceModules(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "js-namespaces")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `table Note {
+	id: ulong;
+}
+namespace shop.orders;
+table Order {
+	id: ulong;
+}
+`)
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &jsgenerator.JSGenerator{NamespaceModules: true},
+	}
+
+	bindingFiles, err := options.CodeGenerator.BindingFiles(schemaFile, options)
+	assert.NoErr(t, err)
+	var files []string
+	for _, file := range bindingFiles {
+		rel, err := filepath.Rel(dir, file)
+		assert.NoErr(t, err)
+		files = append(files, filepath.ToSlash(rel))
+	}
+	assert.Eq(t, "schema.obx.js shop/schema.obx.js shop/orders/schema.obx.js", strings.Join(files, " "))
+	// see test/comparison/testdata/js/namespace-modules for the generated modules
+
+	// the modules depend on the namespaces, so a schema that can't be parsed is an error instead of a single module
+	fixture.WriteFile(t, schemaFile, "namespace shop;\ntable Order {\n")
+	_, err = options.CodeGenerator.BindingFiles(schemaFile, options)
+	assert.Err(t, err)
+}
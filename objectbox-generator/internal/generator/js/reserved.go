@@ -0,0 +1,109 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsgenerator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// reservedKeywords can't be used as identifiers in JS modules (strict mode), e.g. as a class name
+var reservedKeywords = map[string]bool{
+	"arguments":  true,
+	"await":      true,
+	"break":      true,
+	"case":       true,
+	"catch":      true,
+	"class":      true,
+	"const":      true,
+	"continue":   true,
+	"debugger":   true,
+	"default":    true,
+	"delete":     true,
+	"do":         true,
+	"else":       true,
+	"enum":       true,
+	"eval":       true,
+	"export":     true,
+	"extends":    true,
+	"false":      true,
+	"finally":    true,
+	"for":        true,
+	"function":   true,
+	"if":         true,
+	"implements": true,
+	"import":     true,
+	"in":         true,
+	"instanceof": true,
+	"interface":  true,
+	"let":        true,
+	"new":        true,
+	"null":       true,
+	"package":    true,
+	"private":    true,
+	"protected":  true,
+	"public":     true,
+	"return":     true,
+	"static":     true,
+	"super":      true,
+	"switch":     true,
+	"this":       true,
+	"throw":      true,
+	"true":       true,
+	"try":        true,
+	"typeof":     true,
+	"var":        true,
+	"void":       true,
+	"while":      true,
+	"with":       true,
+	"yield":      true,
+}
+
+// jsIdentifier makes the given name a valid JS identifier: characters not allowed in identifiers are replaced by an
+// underscore and a leading digit is prefixed by one, e.g. "2nd-name" becomes "_2nd_name". Unicode letters are kept.
+func jsIdentifier(name string) string {
+	var result strings.Builder
+	for i, char := range name {
+		if i == 0 && unicode.IsDigit(char) {
+			result.WriteRune('_')
+		}
+		if char == '_' || char == '$' || unicode.IsLetter(char) || unicode.IsDigit(char) ||
+			(i > 0 && unicode.In(char, unicode.Mn, unicode.Mc, unicode.Pc)) {
+			result.WriteRune(char)
+		} else {
+			result.WriteRune('_')
+		}
+	}
+	if result.Len() == 0 {
+		return "_"
+	}
+	return result.String()
+}
+
+// jsName returns a valid JS identifier for the given name, see jsIdentifier(), with reserved keywords suffixed by an
+// underscore, e.g. "class_"
+func jsName(name string) string {
+	name = jsIdentifier(name)
+	if reservedKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsgenerator
+
+import (
+	"testing"
+)
+
+func TestJsName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"name":      "name",
+		"_private":  "_private",
+		"$ref":      "$ref",
+		"class":     "class_",
+		"default":   "default_",
+		"await":     "await_",
+		"Class":     "Class",
+		"2nd":       "_2nd",
+		"first-row": "first_row",
+		"my name":   "my_name",
+		"größe":     "größe",
+		"名前":        "名前",
+		"":          "_",
+	} {
+		if actual := jsName(name); actual != expected {
+			t.Errorf("jsName(%q) = %q, expected %q", name, actual, expected)
+		}
+	}
+
+	for name, expected := range map[string]string{
+		"name":  "getName",
+		"class": "getClass",
+		"2nd":   "get_2nd",
+		"über":  "getÜber",
+	} {
+		if actual := accessorName("get", name); actual != expected {
+			t.Errorf("accessorName(\"get\", %q) = %q, expected %q", name, actual, expected)
+		}
+	}
+}
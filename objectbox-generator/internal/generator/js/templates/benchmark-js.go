@@ -0,0 +1,78 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package templates
+
+import (
+	"text/template"
+)
+
+// JsBenchmarkTemplate is used to generate a node script measuring the FlatBuffers serialization of the entities
+var JsBenchmarkTemplate = template.Must(template.New("benchmark-js").Funcs(funcMap).Parse(`
+// Code generated by ObjectBox; DO NOT EDIT.
+// Benchmarks of the FlatBuffers serialization, run with ` + "`node {{.FileName}} [iterations]`" + `
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
+
+{{if .CommonJS -}}
+const fb = require("flatbuffers");
+{{- if not .BrowserSafe}}
+const { performance } = require("node:perf_hooks");
+{{- end}}
+const obx = require("./{{.BindingFile}}");
+{{- else -}}
+import * as fb from "flatbuffers";
+{{- if not .BrowserSafe}}
+import { performance } from "node:perf_hooks";
+{{- end}}
+import * as obx from "./{{.BindingFile}}";
+{{- end}}
+{{if .BrowserSafe}}
+const iterations = Number(globalThis.process?.argv?.[2] || 100000);
+{{- else}}
+const iterations = Number(process.argv[2] || 100000);
+{{- end}}
+
+function bench(name, fn) {
+	for (let i = 0; i < Math.min(iterations, 1000); i++) fn(); // warm-up
+	const start = performance.now();
+	for (let i = 0; i < iterations; i++) fn();
+	const nsPerOp = (performance.now() - start) * 1e6 / iterations;
+	console.log(name + ": " + nsPerOp.toFixed(1) + " ns/op");
+}
+{{range $entity := .Entities}}
+{{- $class := print "obx." (or (and $.NamespaceModules $entity.Meta.Namespace (print $entity.Meta.Namespace ".")) "") $entity.Meta.JsName}}
+{
+	const object = new {{$class}}();
+	{{- range $property := $entity.Properties}}
+	{{- if IsIdPropertyFlagPresent $property.Flags}}
+	object.setId({{ZeroValue $property}});
+	{{- else if $entity.Meta.Accessors}}
+	object.{{$property.Meta.JsSetter}}({{ZeroValue $property}});
+	{{- else}}
+	object.{{$property.Meta.JsName}} = {{ZeroValue $property}};
+	{{- end}}
+	{{- end}}
+	const fbb = new fb.Builder(512);
+	bench("{{$entity.Name}} toFlatbuffers", () => {{$class}}.toFlatbuffers(fbb, object));
+	const bytes = {{$class}}.toFlatbuffers(fbb, object).slice();
+	bench("{{$entity.Name}} fromFlatbuffers", () => {{$class}}.fromFlatbuffers(bytes));
+}
+{{end}}
+{{- block "file-footer" .}}{{end}}`))
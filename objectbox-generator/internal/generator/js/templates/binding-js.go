@@ -6,14 +6,114 @@ import (
 
 var JsBindingTemplate = template.Must(template.New("binding-js").Funcs(funcMap).Parse(`
 // Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
-{{define "field-value"}}{{if .Optional}}*{{end}}object.{{.JsName}}{{end -}}
+{{define "field-value"}}{{if .Optional}}*{{end}}object.{{.JsFieldName}}{{end -}}
 
+{{- if .CommonJS}}
+const fb = require("flatbuffers");
+const properties = require("#objectbox/js/model/Property.js");
+{{- range .SubModules}}
+const {{.Name}} = require("{{.Path}}");
+{{- end}}
+{{- else}}
 import * as fb from "flatbuffers";
 import * as properties from "#objectbox/js/model/Property.js";
+{{- range .SubModules}}
+export * as {{.Name}} from "{{.Path}}";
+{{- end}}
+{{- end}}
+{{- if .VerifyFlatBuffers}}
 
-{{range $entity := .Model.EntitiesWithMeta}}
-export class {{ $entity.Name }} {
+const utf8Decoder = new TextDecoder("utf-8", { fatal: true });
+
+/**
+ * Checks the FlatBuffers table of an object is well-formed before reading it, i.e. all the given fields are in bounds
+ * and strings are valid UTF-8, throwing an Error otherwise.
+ * Fields are given as [name, vtable offset, kind ("scalar", "vector" or "string"), (element) size].
+ */
+function verifyTable(bb, entityName, fields) {
+	const fail = (reason) => {
+		throw new Error(entityName + ": invalid FlatBuffers data: " + reason);
+	};
+	const end = bb.capacity();
+	if (end < 4) fail("buffer too small");
+	const tablePos = bb.readUint32(0);
+	if (tablePos + 4 > end) fail("table out of bounds");
+	const vtablePos = tablePos - bb.readInt32(tablePos);
+	if (vtablePos < 0 || vtablePos + 4 > end) fail("vtable out of bounds");
+	const vtableSize = bb.readUint16(vtablePos);
+	if (vtablePos + vtableSize > end) fail("vtable out of bounds");
+
+	// checks the length-prefixed vector (or string) referenced at the given position, returning its start and length
+	const vector = (name, pos, elementSize) => {
+		const start = pos + bb.readUint32(pos);
+		if (start + 4 > end) fail(name + " out of bounds");
+		const length = bb.readUint32(start);
+		if (start + 4 + length * elementSize > end) fail(name + " out of bounds");
+		return [start + 4, length];
+	};
+	const string = (name, pos) => {
+		const [start, length] = vector(name, pos, 1);
+		if (start + length >= end || bb.readUint8(start + length) !== 0) fail(name + " is not zero-terminated");
+		try {
+			utf8Decoder.decode(bb.bytes().subarray(start, start + length));
+		} catch (e) {
+			fail(name + " is not valid UTF-8");
+		}
+	};
+
+	for (const [name, vtableOffset, kind, size] of fields) {
+		const offset = vtableOffset < vtableSize ? bb.readUint16(vtablePos + vtableOffset) : 0;
+		if (offset === 0) continue; // the field isn't present
+		const pos = tablePos + offset;
+		if (pos + (kind === "scalar" ? size : 4) > end) fail(name + " out of bounds");
+		if (kind === "string") {
+			string(name, pos);
+		} else if (kind === "vector") {
+			vector(name, pos, size);
+		}
+	}
+}
+{{- end}}
+{{- if .Validation}}
+
+/**
+ * Thrown by toFlatbuffers() when writing an invalid object; errors lists the problems found by the entity's validate().
+ */
+{{if not $.CommonJS}}export {{end}}class ValidationError extends Error {
+	constructor(entityName, errors) {
+		super(errors.map((error) => error.message).join("; "));
+		this.name = "ValidationError";
+		this.entityName = entityName;
+		this.errors = errors;
+	}
+}
+
+// describes the given value in validation errors, e.g. "number 70000", "string" or "Int32Array"
+function describeValue(value) {
+	if (Array.isArray(value)) return "array";
+	if (ArrayBuffer.isView(value)) return value.constructor.name;
+	if (typeof value === "number" || typeof value === "bigint" || typeof value === "boolean") return typeof value + " " + value;
+	return typeof value;
+}
+{{- end}}
+{{range $enum := .Enums}}
+{{JsDoc 0 $enum.Comments}}{{if not $.CommonJS}}export {{end}}const {{ $enum.Name }} = Object.freeze({
+	{{- range $value := $enum.Values }}
+	{{ $value.Name }}: {{ EnumValue $enum $value }},
+	{{- end }}
+});
+{{end}}
+{{range $group := .Constants}}
+{{JsDoc 0 $group.Comments}}{{if not $.CommonJS}}export {{end}}const {{ $group.JsName }} = Object.freeze({
+	{{- range $constant := $group.Constants }}
+	{{ JsDoc 1 $constant.Comments }}{{ $constant.Name }}: {{ ConstantValue $constant }},
+	{{- end }}
+});
+{{end}}
+{{range $entity := .Entities}}
+{{JsDoc 0 $entity.Comments}}{{if not $.CommonJS}}export {{end}}class {{ $entity.Meta.JsName }} {
 
     static entityInfo = new Map([
 		["id", {{ $entity.Id.GetId }}n],
@@ -21,13 +121,46 @@ export class {{ $entity.Name }} {
 	]);
 		
 	{{- range $property := $entity.Properties }}
-	static _{{ $property.Meta.JsName }} = new properties.{{ OBXTypeToJSPropertyType $property.Type }}({{ $property.Id.GetId }},{{ $property.Id.GetUid }}n);
+	{{ JsDoc 1 $property.Comments }}static _{{ $property.Meta.JsName }} = new properties.{{ OBXTypeToJSPropertyType $property.Type }}({{ $property.Id.GetId }},{{ $property.Id.GetUid }}n);
+	{{- end }}
+	{{- if $entity.Meta.Accessors }}
+{{ range $property := $entity.Properties }}
+	{{ $property.Meta.JsFieldName }};
+	{{- end }}
+	{{- range $property := $entity.Properties }}
+	{{- if not (IsIdPropertyFlagPresent $property.Flags) }}
+
+	{{ JsDoc 1 $property.Comments }}{{ $property.Meta.JsGetter }}() {
+		return this.{{ $property.Meta.JsFieldName }};
+	}
+
+	{{ $property.Meta.JsSetter }}(value) {
+		this.{{ $property.Meta.JsFieldName }} = value;
+	}
+	{{- end }}
+	{{- end }}
+	{{- end }}
+	{{- range $property := $entity.Properties }}
+	{{- if $property.HnswParams }}
+
+	/**
+	 * Creates a condition matching up to maxCount objects with the {{ $property.Meta.JsName }} vector nearest to the given one,
+	 * using the HNSW index{{with $property.HnswParams.Dimensions}} ({{.}} dimensions){{end}}{{with $property.HnswParams.DistanceType}}, distance type {{.}}{{end}}.
+	 */
+	static {{ $property.Meta.JsName }}NearestNeighbors(queryVector, maxCount) {
+		return this._{{ $property.Meta.JsName }}.nearestNeighbors(queryVector, maxCount);
+	}
+	{{- end }}
 	{{- end }}
 
 	getId() {
 		{{- range $property := $entity.Properties }}
 		{{- if IsIdPropertyFlagPresent $property.Flags }}
-		return this.{{ $property.Meta.JsName }};
+		{{- if $entity.Meta.StringIds }}
+		return this.{{ $property.Meta.JsFieldName }} != null ? BigInt(this.{{ $property.Meta.JsFieldName }}) : this.{{ $property.Meta.JsFieldName }};
+		{{- else }}
+		return this.{{ $property.Meta.JsFieldName }};
+		{{- end }}
 		{{- end }}
 	    {{- end }}
 	}
@@ -35,30 +168,69 @@ export class {{ $entity.Name }} {
 	setId(id) {
 		{{- range $property := $entity.Properties }}
 		{{- if IsIdPropertyFlagPresent $property.Flags }}
-		this.{{ $property.Meta.JsName }} = id;
+		{{- if $entity.Meta.StringIds }}
+		this.{{ $property.Meta.JsFieldName }} = id != null ? String(id) : id;
+		{{- else }}
+		this.{{ $property.Meta.JsFieldName }} = id;
+		{{- end }}
+		{{- end }}
+		{{- end }}
+	}
+
+	{{- if $.Validation}}
+
+	/**
+	 * Checks the given {{ $entity.Name }} object can be written, i.e. required properties are set, values have the right type
+	 * and vectors the right number of dimensions. Returns the problems found as { property, code, message } objects,
+	 * with code one of "required", "type" or "dimensions"; an empty array if the object is valid.
+	 */
+	static validate(object) {
+		const errors = [];
+		const fail = (property, code, message) => errors.push({ property, code, message: "{{ $entity.Name }}." + property + ": " + message });
+		let value;
+		{{- range $property := $entity.Properties }}
+		{{- with JsValidateProperty $property }}
+		{{ . }}
 		{{- end }}
 		{{- end }}
+		return errors;
 	}
+	{{- end}}
 
 	/**
 	 * Encode the given {{ $entity.Name }} object into a Uint8Array (flatbuffers -formatted).
 	 */
 	static toFlatbuffers(fbb, object) {
+		{{- if $.Validation}}
+		const errors = {{ $entity.Meta.JsName }}.validate(object);
+		if (errors.length > 0) throw new ValidationError("{{ $entity.Name }}", errors);
+		{{- end}}
 		fbb.clear();
+		{{- range $property := $entity.Properties }}
+			{{- with SetIdCompanion $property }}
+		{{ . }}
+			{{- end }}
+		{{- end }}
 
 		{{ range $property := $entity.Properties -}}
+			{{- if and $.TypedArrays (eq (PropTypeName $property.Type) "FloatVector") }}
+		{{ CreateTypedArrayOffset $property }}
+			{{- else }}
 			{{- $code := CreateOffsetProperty $property  }}
 			{{- if $code }}
 		{{ $code }}
 			{{- end}}
+			{{- end}}
 		{{- end }}
 
 		fbb.startObject({{ len $entity.Properties }});
 		{{- range $property := $entity.Properties }}
 			{{- if $property.Meta.Optional}}
-				if (object.{{$property.Meta.JsName}})
+				if (object.{{$property.Meta.JsFieldName}})
 			{{- end }}
-			{{- if CreateOffsetProperty $property }}
+			{{- if and $.TypedArrays (eq (PropTypeName $property.Type) "FloatVector") }}
+		if ({{ $property.Meta.JsName }}_offset) {{ AddFieldOffset $property }}
+			{{- else if CreateOffsetProperty $property }}
 		{{ AddFieldOffset $property }}
 			{{- else }}
 				{{- if and $.NaNAsNull $property.Meta.FbIsFloatingPoint }}
@@ -80,18 +252,91 @@ export class {{ $entity.Name }} {
 			// (...) The provided ArrayBufferView value must not be shared
 			new Uint8Array(bytes)
 		);
+		{{- if $.VerifyFlatBuffers}}
+		verifyTable(bb, "{{ $entity.Name }}", [
+			{{- range $property := $entity.Properties }}
+			{{ JsVerifyField $property }},
+			{{- end }}
+		]);
+		{{- end }}
 		let bbPos = bb.readInt32(bb.position()) + bb.position();
 
 		{{- range $property := $entity.Properties }}
 		{{ WriteGetAssignOffset $property }}
 		{{- end }}
 
-		if (outObject == null) outObject = new {{ $entity.Name }}();
+		if (outObject == null) outObject = new {{ $entity.Meta.JsName }}();
 		{{- range $property := $entity.Properties }}
+		{{- if and $.TypedArrays (eq (PropTypeName $property.Type) "FloatVector") }}
+		{{ ReadTypedArray $property }}
+		{{- else }}
 		{{ ReadProperty $property }}
 		{{- end }}
+		{{- end }}
 		return outObject;
 	}
 }
+{{- if $.Validation}}
+
+/**
+ * Checks the given {{ $entity.Name }} object can be written, see {{ $entity.Meta.JsName }}.validate().
+ */
+{{if not $.CommonJS}}export {{end}}function validate{{ $entity.Name }}(object) {
+	return {{ $entity.Meta.JsName }}.validate(object);
+}
+{{- end}}
+{{- if $.ExtensionHooks}}
+
+// Extension hook: if "{{$entity.Name}}.custom.js" exists next to this file, its default export is called with the class,
+// e.g. to add methods to {{$entity.Meta.JsName}}.prototype without editing this generated file.
+{{- if $.CommonJS}}
+try {
+	const custom = require("./{{$entity.Name}}.custom.js");
+	const hook = custom.default ?? custom;
+	if (typeof hook === "function") hook({{$entity.Meta.JsName}});
+} catch (e) {
+	if (e?.code !== "MODULE_NOT_FOUND" || !String(e.message).includes("{{$entity.Name}}.custom.js")) throw e;
+}
+{{- else if $.BrowserSafe}}
+await import("./{{$entity.Name}}.custom.js").then(
+	(custom) => {
+		if (typeof custom.default === "function") custom.default({{$entity.Meta.JsName}});
+	},
+	(e) => {
+		// browsers fail to fetch a missing module with a TypeError, there's no error code as in Node
+		if (!(e instanceof TypeError) && e?.code !== "ERR_MODULE_NOT_FOUND") throw e;
+	}
+);
+{{- else}}
+try {
+	const custom = await import("./{{$entity.Name}}.custom.js");
+	if (typeof custom.default === "function") custom.default({{$entity.Meta.JsName}});
+} catch (e) {
+	if (e?.code !== "ERR_MODULE_NOT_FOUND" || !String(e.message).includes("{{$entity.Name}}.custom.js")) throw e;
+}
+{{- end}}
+{{- end}}
+{{end}}
+{{- if .CommonJS}}
+module.exports = {
+{{- range .SubModules}}
+	{{.Name}},
+{{- end}}
+{{- range .Enums}}
+	{{.Name}},
+{{- end}}
+{{- range .Constants}}
+	{{.JsName}},
+{{- end}}
+{{- range .Entities}}
+	{{.Meta.JsName}},
+{{- end}}
+{{- if .Validation}}
+	ValidationError,
+{{- range .Entities}}
+	validate{{.Name}},
+{{- end}}
+{{- end}}
+};
 {{end}}
-`))
+{{block "file-footer" .}}{{end}}`))
@@ -23,6 +23,7 @@ package templates
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -43,7 +44,50 @@ func cccToUc(str string) string {
 	return result
 }
 
+// jsName returns the JS identifier of the given property, i.e. its name with reserved keywords suffixed by an underscore
+func jsName(property model.Property) string {
+	if meta, ok := property.Meta.(interface{ JsName() string }); ok {
+		return meta.JsName()
+	}
+	return property.Name
+}
+
+// fieldName returns the name of the class field holding the given property, i.e. a private field with accessors
+func fieldName(property model.Property) string {
+	if meta, ok := property.Entity.Meta.(interface{ Accessors() bool }); ok && meta.Accessors() {
+		return "#" + jsName(property)
+	}
+	return jsName(property)
+}
+
+// isStringId returns true if the given property is the ID and held as a decimal string, see JSGenerator.IdType
+func isStringId(property model.Property) bool {
+	if property.Flags&model.PropertyFlagId == 0 {
+		return false
+	}
+	meta, ok := property.Entity.Meta.(interface{ StringIds() bool })
+	return ok && meta.StringIds()
+}
+
 var funcMap = template.FuncMap{
+	"EnumValue": func(enum *model.Enum, value *model.EnumValue) string {
+		// 64-bit values are read as BigInt, see ReadProperty
+		var suffix string
+		if enum.Type == model.PropertyTypeLong {
+			suffix = "n"
+		}
+		if enum.Unsigned {
+			return fmt.Sprint(uint64(value.Value), suffix)
+		}
+		return fmt.Sprint(value.Value, suffix)
+	},
+	"ConstantValue": func(constant *model.Constant) string {
+		// 64-bit values are BigInts, the same as when reading properties, see ReadProperty
+		if constant.Type == model.PropertyTypeLong {
+			return constant.Value + "n"
+		}
+		return constant.Value
+	},
 	"PropTypeName": func(val model.PropertyType) string {
 		return model.PropertyTypeNames[val]
 	},
@@ -107,17 +151,28 @@ var funcMap = template.FuncMap{
 	"PrintComments": func(tabs int, comments []string) string {
 		var result string
 		for _, comment := range comments {
-			result += "/// " + comment + "\n" + strings.Repeat("\t", tabs)
+			result += strings.TrimRight("/// "+comment, " ") + "\n" + strings.Repeat("\t", tabs)
 		}
 		return result
 	},
+	"JsDoc": func(tabs int, comments []string) string {
+		if len(comments) == 0 {
+			return ""
+		}
+		var indent = strings.Repeat("\t", tabs)
+		var result = "/**\n"
+		for _, comment := range comments {
+			result += indent + strings.TrimRight(" * "+comment, " ") + "\n"
+		}
+		return result + indent + " */\n" + indent
+	},
 	"IsOptionalPtr": func(optional string) bool {
 		return optional == "std::unique_ptr" || optional == "std::shared_ptr"
 	},
 	"ToUpper": strings.ToUpper,
 
 	"AddField": func(property model.Property) string {
-		varName := "object." + property.Name
+		varName := "object." + fieldName(property)
 		notSupportedComment := fmt.Sprint("// Not supported: ", model.PropertyTypeNames[property.Type])
 		isNullable := (property.Flags & model.PropertyFlagNotNull) == 0
 
@@ -140,6 +195,9 @@ var funcMap = template.FuncMap{
 		case model.PropertyTypeInt:
 			str += fmt.Sprintln("fbb.addFieldInt32(", property.FbSlot(), ", ", varName, ");")
 		case model.PropertyTypeLong:
+			if isStringId(property) {
+				varName = "BigInt(" + varName + ")"
+			}
 			str += fmt.Sprintln("fbb.addFieldInt64(", property.FbSlot(), ", ", varName, ");")
 		case model.PropertyTypeFloat:
 			str += fmt.Sprintln("fbb.addFieldFloat32(", property.FbSlot(), ", ", varName, ");")
@@ -170,8 +228,8 @@ var funcMap = template.FuncMap{
 	},
 
 	"CreateOffsetProperty": func(property model.Property) string {
-		offsetVar := property.Name + "_offset"
-		fieldVar := "object." + property.Name
+		offsetVar := jsName(property) + "_offset"
+		fieldVar := "object." + fieldName(property)
 
 		switch property.Type {
 		case model.PropertyTypeString:
@@ -179,7 +237,12 @@ var funcMap = template.FuncMap{
 		case model.PropertyTypeByteVector:
 			return fmt.Sprint("const ", offsetVar, " = fbb.createByteVector(Uint8Array.from(", fieldVar, "));")
 		case model.PropertyTypeFloatVector:
-			return fmt.Sprint("const ", offsetVar, " = fbb.createByteVector(new Uint8Array(Float32Array.from(", fieldVar, ")));")
+			var check string
+			if property.ArrayLength > 0 {
+				check = fmt.Sprintf("if (%s != null && %s.length !== %d) throw new RangeError(\"%s.%s: expected %d elements, got \" + %s.length);\n\t\t",
+					fieldVar, fieldVar, property.ArrayLength, property.Entity.Name, property.Name, property.ArrayLength, fieldVar)
+			}
+			return fmt.Sprint(check, "const ", offsetVar, " = fbb.createByteVector(new Uint8Array(Float32Array.from(", fieldVar, ")));")
 		case model.PropertyTypeStringVector:
 			return "" // TODO: string vectors not supported right now
 		default:
@@ -187,8 +250,52 @@ var funcMap = template.FuncMap{
 		}
 	},
 
+	// CreateTypedArrayOffset writes a float vector given as a Float32Array (or any array of numbers) without converting
+	// it to an intermediate array first, see JSGenerator.TypedArrays
+	"CreateTypedArrayOffset": func(property model.Property) string {
+		offsetVar := jsName(property) + "_offset"
+		fieldVar := "object." + fieldName(property)
+
+		var code = fmt.Sprint("let ", offsetVar, " = 0;\n\t\t")
+		if property.ArrayLength > 0 {
+			code += fmt.Sprintf("if (%s != null && %s.length !== %d) throw new RangeError(\"%s.%s: expected %d elements, got \" + %s.length);\n\t\t",
+				fieldVar, fieldVar, property.ArrayLength, property.Entity.Name, property.Name, property.ArrayLength, fieldVar)
+		}
+		code += fmt.Sprint("if (", fieldVar, " != null) {\n",
+			"\t\t\tfbb.startVector(4, ", fieldVar, ".length, 4);\n",
+			"\t\t\tfor (let i = ", fieldVar, ".length - 1; i >= 0; i--) fbb.addFloat32(", fieldVar, "[i]);\n",
+			"\t\t\t", offsetVar, " = fbb.endVector();\n",
+			"\t\t}")
+		return code
+	},
+
+	// ReadTypedArray reads a float vector as a Float32Array viewing the buffer, or a copy if the vector isn't aligned
+	// (FlatBuffers are little-endian, as are the typed arrays on all common platforms), see JSGenerator.TypedArrays
+	"ReadTypedArray": func(property model.Property) string {
+		offsetVar := jsName(property) + "_offset"
+		fieldVar := "outObject." + fieldName(property)
+		return fmt.Sprint("if (", offsetVar, ") {\n",
+			"\t\t\tconst start = bb.bytes().byteOffset + bb.__vector(bbPos + ", offsetVar, ");\n",
+			"\t\t\tconst length = bb.__vector_len(bbPos + ", offsetVar, ");\n",
+			"\t\t\t", fieldVar, " = start % 4 === 0\n",
+			"\t\t\t\t? new Float32Array(bb.bytes().buffer, start, length)\n",
+			"\t\t\t\t: new Float32Array(bb.bytes().buffer.slice(start, start + length * 4));\n",
+			"\t\t} else {\n",
+			"\t\t\t", fieldVar, " = null;\n",
+			"\t\t}")
+	},
+
+	"SetIdCompanion": func(property model.Property) string {
+		if property.Flags&model.PropertyFlagIdCompanion == 0 {
+			return ""
+		}
+		// like in other ObjectBox bindings, a zero ID companion date is set to the current time on put
+		fieldVar := "object." + fieldName(property)
+		return fmt.Sprint("if (!", fieldVar, ") ", fieldVar, " = BigInt(Date.now());")
+	},
+
 	"AddFieldOffset": func(property model.Property) string {
-		offsetVarName := property.Name + "_offset"
+		offsetVarName := jsName(property) + "_offset"
 		return fmt.Sprint("fbb.addFieldOffset(", property.FbSlot(), ",", offsetVarName, ");")
 	},
 
@@ -197,13 +304,104 @@ var funcMap = template.FuncMap{
 		if err != nil {
 			panic(err)
 		}
-		offsetVarName := property.Name + "_offset"
+		offsetVarName := jsName(property) + "_offset"
 		return fmt.Sprint("const ", offsetVarName, " = bb.__offset(bbPos, ", value, ");")
 	},
 
+	// JsVerifyField describes the property for verifyTable(), i.e. [name, vtable offset, kind, (element) size]
+	"JsVerifyField": func(property model.Property) string {
+		offset, err := property.FbvTableOffset()
+		if err != nil {
+			panic(err)
+		}
+		var kind, size = "scalar", 0
+		switch property.Type {
+		case model.PropertyTypeBool, model.PropertyTypeByte:
+			size = 1
+		case model.PropertyTypeShort, model.PropertyTypeChar:
+			size = 2
+		case model.PropertyTypeInt, model.PropertyTypeFloat:
+			size = 4
+		case model.PropertyTypeLong, model.PropertyTypeDouble, model.PropertyTypeDate, model.PropertyTypeDateNano, model.PropertyTypeRelation:
+			size = 8
+		case model.PropertyTypeString:
+			kind = "string"
+		case model.PropertyTypeFloatVector:
+			kind, size = "vector", 4
+		}
+		return fmt.Sprintf("[%q, %d, %q, %d]", property.Name, offset, kind, size)
+	},
+
+	// JsValidateProperty checks the value of the property in the generated validate() method, reporting problems by
+	// fail(property, code, message) with code one of "required", "type" or "dimensions", see JSGenerator.Validation
+	"JsValidateProperty": func(property model.Property) string {
+		var check, expected string
+		var isUnsigned = property.Flags&model.PropertyFlagUnsigned != 0
+		var integer = func(bits uint) {
+			var min, max = -(int64(1) << (bits - 1)), int64(1)<<(bits-1) - 1
+			if isUnsigned {
+				min, max = 0, int64(1)<<bits-1
+			}
+			check = fmt.Sprintf("Number.isInteger(value) && value >= %d && value <= %d", min, max)
+			expected = fmt.Sprintf("an integer between %d and %d", min, max)
+		}
+		switch property.Type {
+		case model.PropertyTypeBool:
+			check, expected = `typeof value === "boolean"`, "a boolean"
+		case model.PropertyTypeByte:
+			integer(8)
+		case model.PropertyTypeShort:
+			integer(16)
+		case model.PropertyTypeChar:
+			isUnsigned = true // a UTF-16 code unit
+			integer(16)
+		case model.PropertyTypeInt:
+			integer(32)
+		case model.PropertyTypeLong, model.PropertyTypeDate:
+			if isStringId(property) {
+				check, expected = `typeof value === "string" && /^[0-9]+$/.test(value)`, "a decimal string"
+			} else {
+				check, expected = `typeof value === "bigint"`, "a BigInt"
+			}
+		case model.PropertyTypeFloat, model.PropertyTypeDouble:
+			check, expected = `typeof value === "number"`, "a number"
+		case model.PropertyTypeString:
+			check, expected = `typeof value === "string"`, "a string"
+		case model.PropertyTypeByteVector:
+			check, expected = "value instanceof Uint8Array || Array.isArray(value)", "a Uint8Array or an array"
+		case model.PropertyTypeFloatVector:
+			check, expected = "value instanceof Float32Array || Array.isArray(value)", "a Float32Array or an array"
+		default:
+			return "" // not written by toFlatbuffers(), see AddField and CreateOffsetProperty
+		}
+
+		// fixed-length arrays and HNSW indexes require vectors of the given length
+		var dimensions uint64
+		if property.ArrayLength > 0 {
+			dimensions = uint64(property.ArrayLength)
+		} else if property.HnswParams != nil && property.HnswParams.Dimensions != nil {
+			dimensions = *property.HnswParams.Dimensions
+		}
+
+		var name = strconv.Quote(property.Name)
+		var code = fmt.Sprint("value = object.", fieldName(property), ";\n\t\t")
+		var nonNull string
+		if property.Flags&model.PropertyFlagNotNull != 0 {
+			code += fmt.Sprint("if (value == null) fail(", name, `, "required", "must not be null");`, "\n\t\telse ")
+		} else {
+			nonNull = "value != null && "
+		}
+		code += fmt.Sprint("if (", nonNull, "!(", check, ")) fail(", name, `, "type", "expected `, expected, `, got " + describeValue(value));`)
+		if dimensions > 0 {
+			code += fmt.Sprintf("\n\t\telse if (%svalue.length !== %d) fail(%s, \"dimensions\", \"expected %d elements, got \" + value.length);",
+				nonNull, dimensions, name, dimensions)
+		}
+		return code
+	},
+
 	"ReadProperty": func(property model.Property) string {
-		offsetVarName := property.Name + "_offset"
-		assignLhs := "outObject." + property.Name + " = "
+		offsetVarName := jsName(property) + "_offset"
+		assignLhs := "outObject." + fieldName(property) + " = "
 		switch property.Type {
 		case model.PropertyTypeBool:
 			return fmt.Sprint(assignLhs, "bb.readInt8(bbPos + ", offsetVarName, ") ? true : false;")
@@ -216,6 +414,13 @@ var funcMap = template.FuncMap{
 		case model.PropertyTypeInt:
 			return fmt.Sprint(assignLhs, "bb.readInt32(bbPos + ", offsetVarName, ");")
 		case model.PropertyTypeLong:
+			if property.Flags&model.PropertyFlagId != 0 {
+				// IDs are unsigned, readInt64() would return negative values for the upper half of the range
+				if isStringId(property) {
+					return fmt.Sprint(assignLhs, "bb.readUint64(bbPos + ", offsetVarName, ").toString();")
+				}
+				return fmt.Sprint(assignLhs, "bb.readUint64(bbPos + ", offsetVarName, ");")
+			}
 			return fmt.Sprint(assignLhs, "bb.readInt64(bbPos + ", offsetVarName, ");")
 		case model.PropertyTypeFloat:
 			return fmt.Sprint(assignLhs, "bb.readFloat32(bbPos + ", offsetVarName, ");")
@@ -246,8 +451,8 @@ var funcMap = template.FuncMap{
 			return "BoolProperty"
 		case model.PropertyTypeByte:
 			return "ByteProperty"
-		case model.PropertyTypeShort:
-			return "ShortProperty"
+		case model.PropertyTypeShort, model.PropertyTypeChar:
+			return "ShortProperty" // a char is stored as a 16-bit integer, see AddField
 		case model.PropertyTypeInt:
 			return "IntProperty"
 		case model.PropertyTypeLong:
@@ -273,6 +478,30 @@ var funcMap = template.FuncMap{
 		}
 	},
 
+	"ZeroValue": func(property model.Property) string {
+		// a value toFlatbuffers() accepts, e.g. BigInt for 64-bit integers and arrays for vectors, see AddField
+		if isStringId(property) {
+			return `"0"`
+		}
+		switch property.Type {
+		case model.PropertyTypeBool:
+			return "false"
+		case model.PropertyTypeLong, model.PropertyTypeDate, model.PropertyTypeRelation, model.PropertyTypeDateNano:
+			return "0n"
+		case model.PropertyTypeString:
+			return `""`
+		case model.PropertyTypeFloatVector:
+			if property.ArrayLength > 0 {
+				return fmt.Sprintf("new Array(%d).fill(0)", property.ArrayLength)
+			}
+			return "[]"
+		case model.PropertyTypeByteVector, model.PropertyTypeStringVector:
+			return "[]"
+		default:
+			return "0"
+		}
+	},
+
 	"IsIdPropertyFlagPresent": func(flags model.PropertyFlags) bool {
 		return flags&model.PropertyFlagId != 0
 	},
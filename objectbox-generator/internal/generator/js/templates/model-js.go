@@ -27,20 +27,30 @@ import (
 // JsModelTemplate is used to generate the model initialization code
 var JsModelTemplate = template.Must(template.New("model-js").Funcs(funcMap).Parse(`
 // Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: {{.TemplateVersion}}{{block "file-header" .}}{{end}}
 
+{{if .CommonJS -}}
+const {
+    wasm,
+    OBXEntityFlags,
+    OBXPropertyFlags,
+    OBXPropertyType
+} = require("#objectbox/js/wasm.js");
+{{- else -}}
 import { 
     wasm,
     OBXEntityFlags,
     OBXPropertyFlags,
     OBXPropertyType
 } from "#objectbox/js/wasm.js";
+{{- end}}
 
 /**
  * Initialize an ObjectBox model for all entities.
  * The returned value is a Number representing a handle to the model.
  * You will likely want to pass it to the Store (through StoreOptions), once the store is opened it MUST NOT be freed manually.
  */
-export function createModel() {
+{{if not .CommonJS}}export {{end}}function createModel() {
     let model = wasm.obx_model();
 	{{range $entity := .Model.Entities}}
 	wasm.obx_model_entity(model, "{{$entity.Name}}", {{$entity.Id.GetId}}, {{$entity.Id.GetUid}}n);
@@ -97,4 +107,8 @@ export function createModel() {
 	{{- end}}
 	return model;
 }
-`))
+{{- if .CommonJS}}
+
+module.exports = { createModel };
+{{- end}}
+{{block "file-footer" .}}{{end}}`))
@@ -0,0 +1,228 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// LintSeverity defines how a lint rule violation is reported
+type LintSeverity string
+
+const (
+	LintOff     LintSeverity = "off"
+	LintInfo    LintSeverity = "info"
+	LintWarning LintSeverity = "warning"
+	LintError   LintSeverity = "error" // fails the generation
+)
+
+// LintRules configures severities of the lint rules by their names; rules not listed use their default severity
+type LintRules map[string]LintSeverity
+
+// LintIssue is a single rule violation found by Lint()
+type LintIssue struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Entity   string       `json:"entity"`
+	Message  string       `json:"message"`
+}
+
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%s: entity %s: %s [%s]", issue.Severity, issue.Entity, issue.Message, issue.Rule)
+}
+
+// lintMaxProperties is the number of properties above which an entity is reported by the "huge-entity" rule
+const lintMaxProperties = 64
+
+var lintGenericRelationName = regexp.MustCompile(`^(?i)(rel|relation|link|ref|target)s?[0-9]*$`)
+
+var lintIdLikeName = regexp.MustCompile(`^(?:(?i:uid|uuid|guid)|.*[a-z]Id|.*_(?i:id))$`)
+
+type lintRule struct {
+	name            string
+	defaultSeverity LintSeverity
+	check           func(entity *model.Entity) []string // returns messages describing the violations
+}
+
+// lintRuleList lists the available rules. There's no rule for unindexed relation targets: to-one relation properties
+// are always indexed by the readers and standalone to-many relations don't have a property to index.
+var lintRuleList = []lintRule{
+	{"string-id-not-unique", LintWarning, func(entity *model.Entity) (messages []string) {
+		for _, property := range entity.Properties {
+			if property.Type == model.PropertyTypeString && lintIdLikeName.MatchString(property.Name) &&
+				property.Flags&model.PropertyFlagUnique == 0 {
+				messages = append(messages, fmt.Sprintf("string property %s looks like an identifier but isn't unique - "+
+					"consider the `unique` annotation", property.Name))
+			}
+		}
+		return messages
+	}},
+	{"huge-entity", LintWarning, func(entity *model.Entity) []string {
+		if len(entity.Properties) > lintMaxProperties {
+			return []string{fmt.Sprintf("has %d properties (more than %d) - consider splitting it into related entities",
+				len(entity.Properties), lintMaxProperties)}
+		}
+		return nil
+	}},
+	{"unnamed-relation", LintWarning, func(entity *model.Entity) (messages []string) {
+		for _, relation := range entity.Relations {
+			if lintGenericRelationName.MatchString(relation.Name) {
+				messages = append(messages, fmt.Sprintf("relation %s has a generic name - name it after its meaning", relation.Name))
+			}
+		}
+		for _, property := range entity.Properties {
+			if len(property.RelationTarget) > 0 && lintGenericRelationName.MatchString(property.Name) {
+				messages = append(messages, fmt.Sprintf("relation property %s has a generic name - name it after its meaning", property.Name))
+			}
+		}
+		return messages
+	}},
+	// off by default: only the JS reader can mark a property NotNull (the not-null annotation), other frontends couldn't
+	// resolve the issue
+	{"sync-not-null", LintOff, func(entity *model.Entity) (messages []string) {
+		if entity.Flags&model.EntityFlagSyncEnabled == 0 {
+			return nil
+		}
+		for _, property := range entity.Properties {
+			if !property.IsIdProperty() && property.Flags&model.PropertyFlagNotNull == 0 {
+				messages = append(messages, fmt.Sprintf("property %s of a sync-enabled entity isn't NotNull", property.Name))
+			}
+		}
+		return messages
+	}},
+}
+
+// LintRuleNames returns the names of all available lint rules
+func LintRuleNames() []string {
+	var names []string
+	for _, rule := range lintRuleList {
+		names = append(names, rule.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadLintRules reads lint rule severities from a JSON file, e.g. {"huge-entity": "error", "unnamed-relation": "off"}
+func LoadLintRules(path string) (LintRules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules LintRules
+	if err = json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("can't read lint config %s: %s", path, err)
+	}
+
+	if err = rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint config %s: %s", path, err)
+	}
+	return rules, nil
+}
+
+func (rules LintRules) validate() error {
+	for name, severity := range rules {
+		if !isLintRule(name) {
+			return fmt.Errorf("unknown rule %s - must be one of: %s", name, strings.Join(LintRuleNames(), ", "))
+		}
+		switch severity {
+		case LintOff, LintInfo, LintWarning, LintError:
+		default:
+			return fmt.Errorf("invalid severity %q of rule %s - must be one of: off, info, warning, error", severity, name)
+		}
+	}
+	return nil
+}
+
+func isLintRule(name string) bool {
+	for _, rule := range lintRuleList {
+		if rule.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks entities present in the processed sources (see Validate()) against the lint rules
+func Lint(modelInfo *model.ModelInfo, rules LintRules) ([]LintIssue, error) {
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
+	var issues []LintIssue
+	for _, entity := range modelInfo.Entities {
+		if !entity.CurrentlyPresent {
+			continue
+		}
+
+		for _, rule := range lintRuleList {
+			var severity = rule.defaultSeverity
+			if configured, found := rules[rule.name]; found {
+				severity = configured
+			}
+			if severity == LintOff {
+				continue
+			}
+
+			for _, message := range rule.check(entity) {
+				issues = append(issues, LintIssue{Rule: rule.name, Severity: severity, Entity: entity.Name, Message: message})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// lintErrors returns the number of issues with the "error" severity
+func lintErrors(issues []LintIssue) int {
+	var count int
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			count++
+		}
+	}
+	return count
+}
+
+// LintSources runs Validate() and Lint() on the sources given by options.
+// Besides the found issues, returns an error if any of them has the "error" severity.
+func LintSources(options Options) ([]LintIssue, error) {
+	modelInfo, err := Validate(options)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := Lint(modelInfo, options.LintRules)
+	if err != nil {
+		return nil, err
+	}
+
+	if count := lintErrors(issues); count > 0 {
+		return issues, fmt.Errorf("lint failed with %d error(s)", count)
+	}
+	return issues, nil
+}
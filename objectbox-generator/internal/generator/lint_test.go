@@ -0,0 +1,110 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestLint(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "lint")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, `
+table Task {
+	id: ulong;
+	externalId: string;
+	valid: string;
+	/// objectbox:relation=Task
+	rel: ulong;
+}`)
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	issues, err := generator.LintSources(options)
+	assert.NoErr(t, err)
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.Eq(t, "warning: entity Task: string property externalId looks like an identifier but isn't unique - consider the `unique` annotation [string-id-not-unique]\n"+
+		"warning: entity Task: relation property rel has a generic name - name it after its meaning [unnamed-relation]", strings.Join(messages, "\n"))
+
+	// configured severities
+	options.LintRules = generator.LintRules{"string-id-not-unique": generator.LintOff, "unnamed-relation": generator.LintError}
+	issues, err = generator.LintSources(options)
+	assert.Err(t, err)
+	assert.Eq(t, 1, len(issues))
+	assert.Eq(t, generator.LintError, issues[0].Severity)
+
+	// with lint rules configured, Process() fails without writing anything
+	assert.Err(t, generator.Process(options))
+	_, err = os.Stat(generator.ModelInfoFile(dir))
+	assert.True(t, os.IsNotExist(err))
+
+	options.LintRules = generator.LintRules{"no-such-rule": generator.LintError}
+	_, err = generator.LintSources(options)
+	assert.Err(t, err)
+
+	// sync-enabled entities
+	fixture.WriteFile(t, schemaFile, `
+/// objectbox:sync
+table Note {
+	id: ulong;
+	text: string;
+}`)
+	options.LintRules = nil
+	issues, err = generator.LintSources(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(issues)) // sync-not-null is off by default
+
+	options.LintRules = generator.LintRules{"sync-not-null": generator.LintWarning}
+	issues, err = generator.LintSources(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(issues))
+	assert.Eq(t, "warning: entity Note: property text of a sync-enabled entity isn't NotNull [sync-not-null]", issues[0].String())
+
+	// resolved by the not-null annotation, which only the JS reader supports
+	fixture.WriteFile(t, schemaFile, `
+/// objectbox:sync
+table Note {
+	id: ulong;
+	/// objectbox:not-null
+	text: string;
+}`)
+	options.CodeGenerator = &jsgenerator.JSGenerator{}
+	issues, err = generator.LintSources(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(issues))
+}
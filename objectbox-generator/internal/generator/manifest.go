@@ -0,0 +1,130 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// Manifest lists the files the generator produces for the given sources, so that hermetic build systems
+// (e.g. Bazel or Buck) can declare the outputs ahead of time.
+type Manifest struct {
+	GeneratorVersion string           `json:"generatorVersion"`
+	TemplateVersion  string           `json:"templateVersion"` // includes the template overrides, see manifestTemplateVersion()
+	Sources          []ManifestSource `json:"sources"`
+	ModelInfoFile    string           `json:"modelInfoFile"` // the model JSON file - both an input (if it exists) and an output
+	ModelFile        string           `json:"modelFile"`
+	Outputs          []string         `json:"outputs"` // all the files written by the generator, sorted
+}
+
+// ManifestSource lists the binding files generated for a single source file
+type ManifestSource struct {
+	Path    string   `json:"path"`
+	Outputs []string `json:"outputs"`
+}
+
+// BuildManifest lists the files the generator would write for the given options, without writing anything.
+func BuildManifest(options Options) (*Manifest, error) {
+	if err := options.normalizePaths(); err != nil {
+		return nil, err
+	}
+
+	if len(options.ModelInfoFile) == 0 {
+		options.ModelInfoFile = ModelInfoFile(filepath.Dir(options.InPath))
+	}
+
+	templateVersion, err := manifestTemplateVersion(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest = &Manifest{
+		GeneratorVersion: Version,
+		TemplateVersion:  templateVersion,
+		Sources:          []ManifestSource{},
+		ModelInfoFile:    options.FormatPath(options.ModelInfoFile),
+		ModelFile:        options.FormatPath(options.CodeGenerator.ModelFile(options.ModelInfoFile, options)),
+	}
+
+	err = pathForEach(options.InPath, func(filePath string) error {
+		if !options.CodeGenerator.IsSourceFile(filePath) {
+			return nil
+		}
+		bindingFiles, err := options.CodeGenerator.BindingFiles(filePath, options)
+		if err != nil {
+			return err
+		}
+		var source = ManifestSource{Path: options.FormatPath(filePath)}
+		for _, file := range bindingFiles {
+			source.Outputs = append(source.Outputs, options.FormatPath(file))
+		}
+		manifest.Sources = append(manifest.Sources, source)
+		manifest.Outputs = append(manifest.Outputs, source.Outputs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Outputs = append(manifest.Outputs, manifest.ModelInfoFile, manifest.ModelFile)
+	if len(options.AdminMetadataFile) > 0 {
+		manifest.Outputs = append(manifest.Outputs, options.FormatPath(options.AdminMetadataFile))
+	}
+	sort.Strings(manifest.Outputs)
+	return manifest, nil
+}
+
+// Write stores the manifest as a JSON file
+func (manifest *Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't serialize the manifest: %s", err)
+	}
+	if err = ioutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("can't write the manifest file %s: %s", path, err)
+	}
+	return nil
+}
+
+// manifestTemplateVersion returns the version of the code generator templates; with template overrides, it's combined
+// with a hash of the override files, so that it changes whenever the generated code may change, e.g. as a cache key
+func manifestTemplateVersion(options Options) (string, error) {
+	var version = options.CodeGenerator.TemplateVersion()
+	if len(options.TemplateOverridesDir) == 0 {
+		return version, nil
+	}
+
+	overridesHash, err := hashTemplateOverrides(options.TemplateOverridesDir)
+	if err != nil {
+		return "", err
+	}
+	var hash = sha256.New()
+	hash.Write([]byte(version))
+	hash.Write([]byte{0})
+	hash.Write(overridesHash[:])
+	return hex.EncodeToString(hash.Sum(nil))[:16], nil
+}
@@ -0,0 +1,102 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestManifest(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "manifest")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var manifestFile = filepath.Join(dir, "manifest.json")
+	var outDir = filepath.Join(dir, "generated")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\ntable Note {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		OutPath:       outDir,
+		OutPattern:    "{{.Entity}}.obx.{{.Ext}}",
+		ManifestFile:  manifestFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// validation only writes the manifest
+	_, err := generator.Validate(options)
+	assert.NoErr(t, err)
+	_, err = os.Stat(outDir)
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(manifestFile)
+	assert.NoErr(t, err)
+	var manifest generator.Manifest
+	assert.NoErr(t, json.Unmarshal(data, &manifest))
+	assert.Eq(t, generator.Version, manifest.GeneratorVersion)
+	assert.Eq(t, options.CodeGenerator.TemplateVersion(), manifest.TemplateVersion)
+	assert.Eq(t, 1, len(manifest.Sources))
+	assert.Eq(t, schemaFile, manifest.Sources[0].Path)
+
+	var outputs []string
+	for _, file := range manifest.Outputs {
+		rel, err := filepath.Rel(dir, file)
+		assert.NoErr(t, err)
+		outputs = append(outputs, filepath.ToSlash(rel))
+	}
+	assert.Eq(t, "generated/Note.obx.cpp generated/Note.obx.hpp generated/Task.obx.cpp generated/Task.obx.hpp "+
+		"generated/objectbox-model.h objectbox-model.json", strings.Join(outputs, " "))
+
+	// all the listed files are actually generated
+	assert.NoErr(t, generator.Process(options))
+	for _, file := range manifest.Outputs {
+		_, err = os.Stat(file)
+		assert.NoErr(t, err)
+	}
+
+	// template overrides change the version, as they change the generated code
+	var overridesDir = filepath.Join(dir, "templates")
+	var overrideFile = fixture.WriteFile(t, filepath.Join(overridesDir, "header.tmpl"), `{{define "file-header"}}// Copyright ACME Corp.{{end}}`)
+	options.TemplateOverridesDir = overridesDir
+	var readManifest = func() generator.Manifest {
+		_, err := generator.Validate(options)
+		assert.NoErr(t, err)
+		var manifest generator.Manifest
+		assert.NoErr(t, json.Unmarshal([]byte(fixture.ReadFile(t, manifestFile)), &manifest))
+		return manifest
+	}
+	var overridden = readManifest().TemplateVersion
+	assert.NotEq(t, options.CodeGenerator.TemplateVersion(), overridden)
+	assert.Eq(t, overridden, readManifest().TemplateVersion)
+
+	fixture.WriteFile(t, overrideFile, `{{define "file-header"}}// Copyright ACME Inc.{{end}}`)
+	assert.NotEq(t, overridden, readManifest().TemplateVersion)
+}
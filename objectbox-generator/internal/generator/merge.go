@@ -21,14 +21,19 @@
 package generator
 
 import (
-	"errors"
 	"fmt"
 	"log"
 
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
 )
 
 func mergeBindingWithModelInfo(currentModel *model.ModelInfo, storedModel *model.ModelInfo) error {
+	// reservations must be known before any IDs/UIDs are assigned
+	for _, entity := range currentModel.Entities {
+		storedModel.Reserve(entity.Reserved)
+	}
+
 	// we need to first prepare all entities - otherwise relations wouldn't be able to find them in the model
 	var models = make([]*model.Entity, len(currentModel.Entities))
 	var err error
@@ -45,6 +50,20 @@ func mergeBindingWithModelInfo(currentModel *model.ModelInfo, storedModel *model
 		}
 	}
 
+	if err := retireModelEntities(currentModel, storedModel); err != nil {
+		return err
+	}
+
+	// backlinks refer to relations of other entities so they can only be resolved after all entities are merged
+	if err := storedModel.ResolveBacklinks(); err != nil {
+		return err
+	}
+
+	// cascades may chain across entities, so cycles can only be checked on the whole merged model
+	if err := storedModel.CheckCascadeCycles(); err != nil {
+		return err
+	}
+
 	currentModel.LastEntityId = storedModel.LastEntityId
 	currentModel.LastIndexId = storedModel.LastIndexId
 	currentModel.LastRelationId = storedModel.LastRelationId
@@ -52,11 +71,42 @@ func mergeBindingWithModelInfo(currentModel *model.ModelInfo, storedModel *model
 	return nil
 }
 
+// retireModelEntities removes entities explicitly retired in the sources from the stored model, keeping their UIDs
+func retireModelEntities(currentModel *model.ModelInfo, storedModel *model.ModelInfo) error {
+	for _, name := range currentModel.RetiredEntityNames {
+		if _, err := currentModel.FindEntityByName(name); err == nil {
+			return messages.DeclaredAndRetired.Errorf(name)
+		}
+
+		// nothing to do if the entity isn't (or is no longer) present in the model
+		entity, _ := storedModel.FindEntityByName(name)
+		if entity == nil {
+			continue
+		}
+
+		log.Printf("Notice - retiring entity %s %s, its UID won't be reused", entity.Name, entity.Id)
+		if err := storedModel.RemoveEntity(entity); err != nil {
+			return fmt.Errorf("retiring entity %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
 func getModelEntity(currentEntity *model.Entity, storedModel *model.ModelInfo) (*model.Entity, error) {
 	if uid, err := currentEntity.Id.GetUidAllowZero(); err != nil {
 		return nil, err
 	} else if uid != 0 {
-		return storedModel.FindEntityByUid(uid)
+		if err := storedModel.CheckUidNotRetired(uid); err != nil {
+			return nil, err
+		}
+		entity, err := storedModel.FindEntityByUid(uid)
+		// with deterministic UIDs, the model JSON file may not be persisted so an unknown UID is a pinned (legacy) one
+		if err != nil && storedModel.DeterministicUids {
+			if _, err2 := storedModel.FindEntityByName(currentEntity.Name); err2 != nil {
+				return storedModel.CreateEntityWithUid(currentEntity.Name, uid)
+			}
+		}
+		return entity, err
 	}
 
 	// we don't care about this error = either the entity is found or we create it
@@ -74,7 +124,7 @@ func getModelEntity(currentEntity *model.Entity, storedModel *model.ModelInfo) (
 		} else {
 			errInfo = "entity not found in the model"
 		}
-		return nil, fmt.Errorf("uid annotation value must not be empty (%s) on entity %s", errInfo, currentEntity.Name)
+		return nil, messages.EmptyEntityUid.Errorf(errInfo, currentEntity.Name)
 	}
 
 	if entity == nil {
@@ -85,10 +135,16 @@ func getModelEntity(currentEntity *model.Entity, storedModel *model.ModelInfo) (
 }
 
 func mergeModelEntity(currentEntity *model.Entity, storedEntity *model.Entity, storedModel *model.ModelInfo) (err error) {
+	if err = currentEntity.CheckUniqueOnConflictReplace(); err != nil {
+		return err
+	}
+
 	storedEntity.Name = currentEntity.Name
 	storedEntity.Flags = currentEntity.Flags
 	storedEntity.Comments = currentEntity.Comments
 	storedEntity.ExternalName = currentEntity.ExternalName
+	storedEntity.Reserved.PropertyIds = currentEntity.Reserved.PropertyIds
+	storedEntity.Backlinks = currentEntity.Backlinks
 
 	if currentEntity.Meta != nil {
 		storedEntity.Meta = currentEntity.Meta.Merge(storedEntity)
@@ -123,6 +179,14 @@ func mergeModelEntity(currentEntity *model.Entity, storedEntity *model.Entity, s
 		}
 
 		for _, property := range removedProperties {
+			if transient := currentEntity.FindTransientProperty(property.Name); transient != nil && transient.Retired {
+				log.Printf("Notice - retiring property %s.%s %s, its UID won't be reused", currentEntity.Name, property.Name, property.Id)
+			} else if transient != nil {
+				if !transient.AllowDrop && !storedModel.AllowDrop {
+					return messages.TransientDrop.Errorf(property.Name)
+				}
+				log.Printf("Warning - property %s.%s was stored before but is now transient - its data will be dropped", currentEntity.Name, property.Name)
+			}
 			if err := storedEntity.RemoveProperty(property); err != nil {
 				return fmt.Errorf("removing property %s: %s", property.Name, err)
 			}
@@ -164,6 +228,9 @@ func getModelProperty(currentProperty *model.Property, storedEntity *model.Entit
 	if uid, err := currentProperty.Id.GetUidAllowZero(); err != nil {
 		return nil, err
 	} else if uid != 0 {
+		if err := storedModel.CheckUidNotRetired(uid); err != nil {
+			return nil, err
+		}
 		property, err := storedEntity.FindPropertyByUid(uid)
 		if err == nil {
 			return property, nil
@@ -172,6 +239,9 @@ func getModelProperty(currentProperty *model.Property, storedEntity *model.Entit
 		// handle "reset property data" use-case - adding a new UID to an existing property
 		property, err2 := storedEntity.FindPropertyByName(currentProperty.Name)
 		if err2 != nil {
+			if storedModel.DeterministicUids {
+				return storedEntity.CreatePropertyWithUid(uid)
+			}
 			return nil, fmt.Errorf("%v; %v", err, err2)
 		}
 
@@ -185,7 +255,7 @@ func getModelProperty(currentProperty *model.Property, storedEntity *model.Entit
 	// This effectively check for duplicate property names in the entity source definition by checking that a model
 	// property with this name has already been merged with another "currentProperty".
 	if property != nil && property.Meta != nil {
-		return nil, fmt.Errorf("duplicate property name (note that property names are case insensitive)")
+		return nil, messages.DuplicateSourceProperty.Errorf()
 	}
 
 	// handle uid request
@@ -201,16 +271,13 @@ func getModelProperty(currentProperty *model.Property, storedEntity *model.Entit
 			}
 
 			// handle "reset property data" use-case - adding a new UID to an existing property
-			return nil, fmt.Errorf(`uid annotation value must not be empty:
-    [rename] apply the current UID %d
-    [change/reset] apply a new UID %d`,
-				uid, newUid)
+			return nil, messages.EmptyPropertyUid.Errorf(uid, newUid)
 		}
-		return nil, errors.New("uid annotation value must not be empty, the property isn't present in the persisted model")
+		return nil, messages.EmptyNewPropertyUid.Errorf()
 	}
 
 	if property == nil {
-		return storedEntity.CreateProperty()
+		return storedEntity.CreateProperty(currentProperty.Name)
 	}
 
 	return property, nil
@@ -219,6 +286,8 @@ func getModelProperty(currentProperty *model.Property, storedEntity *model.Entit
 func mergeModelProperty(currentProperty *model.Property, storedProperty *model.Property) error {
 	storedProperty.Name = currentProperty.Name
 	storedProperty.Comments = currentProperty.Comments
+	storedProperty.Enum = currentProperty.Enum
+	storedProperty.ArrayLength = currentProperty.ArrayLength
 
 	if currentProperty.Meta != nil {
 		storedProperty.Meta = currentProperty.Meta.Merge(storedProperty)
@@ -232,13 +301,9 @@ func mergeModelProperty(currentProperty *model.Property, storedProperty *model.P
 	} else if oldUid, err := storedProperty.Id.GetUidAllowZero(); err != nil {
 		return err
 	} else if curUid != 0 && oldUid != curUid {
-		highestId, _, err := storedProperty.Entity.LastPropertyId.Get()
-		if err != nil {
+		if err := storedProperty.ResetUid(curUid); err != nil {
 			return err
 		}
-
-		storedProperty.Id = model.CreateIdUid(highestId+1, curUid)
-		storedProperty.Entity.LastPropertyId = storedProperty.Id
 	}
 
 	// TODO not sure we need this check
@@ -273,11 +338,15 @@ func mergeModelProperty(currentProperty *model.Property, storedProperty *model.P
 	}
 
 	storedProperty.RelationTarget = currentProperty.RelationTarget
+	storedProperty.Cascade = currentProperty.Cascade
+	storedProperty.Encrypted = currentProperty.Encrypted
 	storedProperty.Type = currentProperty.Type
 	storedProperty.Flags = currentProperty.Flags
 	storedProperty.HnswParams = currentProperty.HnswParams
+	storedProperty.StorageType = currentProperty.StorageType
 	storedProperty.ExternalName = currentProperty.ExternalName
 	storedProperty.ExternalType = currentProperty.ExternalType
+	storedProperty.ExternalId = currentProperty.ExternalId
 
 	return nil
 }
@@ -296,7 +365,16 @@ func getModelRelation(currentRelation *model.StandaloneRelation, storedEntity *m
 	if uid, err := currentRelation.Id.GetUidAllowZero(); err != nil {
 		return nil, err
 	} else if uid != 0 {
-		return storedEntity.FindRelationByUid(uid)
+		if err := storedEntity.Model.CheckUidNotRetired(uid); err != nil {
+			return nil, err
+		}
+		relation, err := storedEntity.FindRelationByUid(uid)
+		if err != nil && storedEntity.Model.DeterministicUids {
+			if _, err2 := storedEntity.FindRelationByName(currentRelation.Name); err2 != nil {
+				return storedEntity.CreateRelationWithUid(uid)
+			}
+		}
+		return relation, err
 	}
 
 	// we don't care about this error, either the relation is found or we create it
@@ -314,11 +392,11 @@ func getModelRelation(currentRelation *model.StandaloneRelation, storedEntity *m
 		} else {
 			errInfo = "relation not found in the model"
 		}
-		return nil, fmt.Errorf("uid annotation value must not be empty (%s)", errInfo)
+		return nil, messages.EmptyRelationUid.Errorf(errInfo)
 	}
 
 	if relation == nil {
-		return storedEntity.CreateRelation()
+		return storedEntity.CreateRelation(currentRelation.Name)
 	}
 
 	return relation, nil
@@ -328,6 +406,7 @@ func mergeModelRelation(currentRelation *model.StandaloneRelation, storedRelatio
 	storedRelation.Name = currentRelation.Name
 	storedRelation.ExternalName = currentRelation.ExternalName
 	storedRelation.ExternalType = currentRelation.ExternalType
+	storedRelation.Cascade = currentRelation.Cascade
 
 	if currentRelation.Meta != nil {
 		storedRelation.Meta = currentRelation.Meta.Merge(storedRelation)
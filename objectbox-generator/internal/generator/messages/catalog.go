@@ -0,0 +1,64 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package messages
+
+// Model errors, found when validating the model (1xxx)
+var (
+	DuplicatePropertyName = define("OBXG1001", "duplicate property name '%s' (note that property names are case insensitive)")
+	MultipleIdProperties  = define("OBXG1002", "multiple properties marked as ID: %s (%s) and %s (%s)")
+	MultipleReplace       = define("OBXG1003", "only a single property may replace objects on a unique conflict, found %s and %s")
+	MultipleIdCandidates  = define("OBXG1004", "multiple properties recognized as an ID: %s (%s) and %s (%s)")
+	NoIdProperty          = define("OBXG1005", "no property recognized as an ID")
+	RelationCycle         = define("OBXG1006", "relation cycle detected: %s (%s)")
+	CascadeCycle          = define("OBXG1007", "cascade delete cycle detected: %s removes %s (%s)")
+	SyncRelation          = define("OBXG1008", "sync-enabled entity %s can't have a relation to not-synced entity %s, but found relation %s - add the sync annotation to %s as well")
+	DeterministicUid      = define("OBXG1009", "deterministic UID %d generated for %s collides with a UID already used in the model - pin a different UID using the uid annotation or change the UID salt")
+	MultipleExternalIds   = define("OBXG1010", "only a single property may be the external ID, found %s and %s")
+)
+
+// Errors merging the sources with the stored model (2xxx)
+var (
+	DuplicateSourceProperty = define("OBXG2001", "duplicate property name (note that property names are case insensitive)")
+	EmptyEntityUid          = define("OBXG2002", "uid annotation value must not be empty (%s) on entity %s")
+	EmptyPropertyUid        = define("OBXG2003", "uid annotation value must not be empty:\n    [rename] apply the current UID %d\n    [change/reset] apply a new UID %d")
+	EmptyNewPropertyUid     = define("OBXG2004", "uid annotation value must not be empty, the property isn't present in the persisted model")
+	EmptyRelationUid        = define("OBXG2005", "uid annotation value must not be empty (%s)")
+	TransientDrop           = define("OBXG2006", "property %s was stored before but is now transient, its data would be dropped - confirm using the allow-drop option or by annotating the field as transient(allow-drop)")
+	DeclaredAndRetired      = define("OBXG2007", "entity %s is both declared and retired")
+)
+
+// Errors reading the sources (3xxx)
+var (
+	UnknownAnnotation  = define("OBXG3001", "unknown annotation '%s'")
+	UndeclaredTarget   = define("OBXG3002", "relation %s.%s in %s targets entity %s which isn't declared in any of the processed sources")
+	EmbeddedCycle      = define("OBXG3003", "embedded struct cycle detected: %v")
+	OutPatternConflict = define("OBXG3004", "invalid out-pattern %q: entities %s and %s would be written to the same file - use {{.Entity}} in the pattern")
+)
+
+// Errors caused by the generator options (4xxx)
+var (
+	OptionsChanged          = define("OBXG4001", "generator options affecting the stored data differ from the ones recorded in %s: %s; data written by the previously generated code may be read differently - confirm the change using -accept-options-change")
+	InvalidTenantPrefix     = define("OBXG4002", "invalid tenant prefix %q, expecting a letter followed by letters, digits or underscores")
+	TenantPrefixUnsupported = define("OBXG4003", "a tenant prefix isn't supported by the %T code generator")
+	TenantPrefixChanged     = define("OBXG4004", "the model %s was generated with tenant prefix %q but %q is given - each tenant needs its own model JSON file")
+	SameTenantPrefix        = define("OBXG4005", "module model %s has the same tenant prefix %q as %s")
+	ModelFrozen             = define("OBXG4006", "the model %s is frozen %s but the sources change it: %s - revert the source changes or unfreeze the model to change the schema")
+)
@@ -0,0 +1,152 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package messages is the catalog of generator error messages. Each message has a code, e.g. OBXG1001, which is part
+// of the error text (so it's kept when errors are wrapped) and can thus be looked up in the docs or matched by tools
+// and tests regardless of the language. The texts can be translated, see LoadTranslations().
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Message is an entry in the catalog: a code and the default (English) text, a fmt format string
+type Message struct {
+	Code   string `json:"code"`
+	Format string `json:"text"`
+}
+
+// Error is an error with a message from the catalog; the text starts with the code, e.g. "OBXG1001: duplicate ..."
+type Error struct {
+	Message *Message
+	Args    []interface{}
+}
+
+func (err *Error) Error() string {
+	return err.Message.Code + ": " + fmt.Sprintf(err.Message.text(), err.Args...)
+}
+
+// Errorf creates an error with the message formatted using the given arguments, in the current translation if any
+func (message *Message) Errorf(args ...interface{}) error {
+	return &Error{message, args}
+}
+
+func (message *Message) text() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	if translated, found := translations[message.Code]; found {
+		return translated
+	}
+	return message.Format
+}
+
+var catalog = make(map[string]*Message) // code => message
+
+var lock sync.RWMutex
+var translations map[string]string // code => format string
+
+func define(code, format string) *Message {
+	if _, found := catalog[code]; found {
+		panic("duplicate message code " + code)
+	}
+	var message = &Message{code, format}
+	catalog[code] = message
+	return message
+}
+
+// Catalog returns all messages, sorted by code, with the texts of the current translation, e.g. to document them
+func Catalog() []Message {
+	var result = make([]Message, 0, len(catalog))
+	for _, message := range catalog {
+		result = append(result, Message{message.Code, message.text()})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result
+}
+
+// Lookup returns the message with the given code, or nil if there's none
+func Lookup(code string) *Message {
+	return catalog[code]
+}
+
+var codeRegexp = regexp.MustCompile(`\bOBXG\d{4}\b`)
+
+// Code returns the code of the (innermost) catalog message in the given error, even if it was wrapped by other errors,
+// e.g. fmt.Errorf("can't merge model information: %s", err). Returns an empty string for errors not in the catalog.
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	var codes = codeRegexp.FindAllString(err.Error(), -1)
+	if len(codes) == 0 {
+		return ""
+	}
+	return codes[len(codes)-1]
+}
+
+// formatVerbRegexp matches fmt verbs (with flags, width and precision), but not an escaped percent sign
+var formatVerbRegexp = regexp.MustCompile(`%[-+# 0]*[\d.]*[a-zA-Z]`)
+
+// LoadTranslations reads the translated texts from a JSON file mapping codes to format strings, e.g.
+// {"OBXG1001": "doppelter Property-Name '%s' (Groß-/Kleinschreibung wird nicht unterschieden)"}. The translations
+// replace the ones loaded before; messages missing in the file keep their default text.
+func LoadTranslations(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var loaded map[string]string
+	if err = json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("invalid translations file %s: %s", file, err)
+	}
+
+	for code, text := range loaded {
+		var message = catalog[code]
+		if message == nil {
+			return fmt.Errorf("invalid translations file %s: unknown message code %s", file, code)
+		}
+		// the arguments are given in the order of the default text so the translation must use the same verbs
+		var expected = formatVerbRegexp.FindAllString(strings.Replace(message.Format, "%%", "", -1), -1)
+		var actual = formatVerbRegexp.FindAllString(strings.Replace(text, "%%", "", -1), -1)
+		if strings.Join(expected, " ") != strings.Join(actual, " ") {
+			return fmt.Errorf("invalid translations file %s: message %s must use the placeholders %s in the same order, found %s",
+				file, code, strings.Join(expected, " "), strings.Join(actual, " "))
+		}
+	}
+
+	lock.Lock()
+	translations = loaded
+	lock.Unlock()
+	return nil
+}
+
+// ResetTranslations restores the default texts of all messages
+func ResetTranslations() {
+	lock.Lock()
+	translations = nil
+	lock.Unlock()
+}
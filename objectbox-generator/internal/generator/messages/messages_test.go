@@ -0,0 +1,78 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package messages_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "messages")
+	defer remove()
+
+	// codes are unique and documented, i.e. each has a text
+	var catalog = messages.Catalog()
+	assert.True(t, len(catalog) > 0)
+	for i, message := range catalog {
+		assert.True(t, len(message.Format) > 0)
+		assert.True(t, messages.Lookup(message.Code) != nil)
+		if i > 0 {
+			assert.True(t, catalog[i-1].Code < message.Code)
+		}
+	}
+
+	// the code is kept when the error is wrapped
+	err := messages.NoIdProperty.Errorf()
+	assert.Eq(t, "OBXG1005: no property recognized as an ID", err.Error())
+	assert.Eq(t, "OBXG1005", messages.Code(fmt.Errorf("entity Task: %s", err)))
+	assert.Eq(t, "", messages.Code(fmt.Errorf("unknown")))
+	assert.Eq(t, "", messages.Code(nil))
+
+	var translate = func(translations string) error {
+		var file = filepath.Join(dir, "translations.json")
+		assert.NoErr(t, ioutil.WriteFile(file, []byte(translations), 0600))
+		return messages.LoadTranslations(file)
+	}
+	defer messages.ResetTranslations()
+
+	assert.NoErr(t, translate(`{"OBXG1001": "doppelter Property-Name '%s'"}`))
+	assert.Eq(t, "OBXG1001: doppelter Property-Name 'name'", messages.DuplicatePropertyName.Errorf("name").Error())
+	assert.Eq(t, "OBXG1005: no property recognized as an ID", messages.NoIdProperty.Errorf().Error())
+	assert.Eq(t, "doppelter Property-Name '%s'", messages.Catalog()[0].Format)
+
+	// invalid translations are rejected, keeping the previous ones
+	assert.Err(t, translate(`{"OBXG0000": "unknown"}`))
+	assert.Err(t, translate(`{"OBXG1001": "doppelter Property-Name"}`))
+	assert.Err(t, translate(`{"OBXG1002": "%s (%s), %s (%s) %d"}`))
+	assert.Err(t, translate(`not json`))
+	assert.Eq(t, "OBXG1001: doppelter Property-Name 'name'", messages.DuplicatePropertyName.Errorf("name").Error())
+
+	messages.ResetTranslations()
+	assert.Eq(t, "OBXG1001: duplicate property name 'name' (note that property names are case insensitive)",
+		messages.DuplicatePropertyName.Errorf("name").Error())
+}
@@ -0,0 +1,168 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// AdminMetadataVersion is the version of the AdminMetadata format, increased on incompatible changes
+const AdminMetadataVersion = 1
+
+// AdminMetadata describes the model for ObjectBox Admin (the data browser): human-readable labels, descriptions from
+// the source comments, external names and the relations (including backlinks) of each entity, so that they show up
+// without configuring the Admin UI manually. Entities and their members are identified by their ID:UID, as stored in
+// the database, with the names as a fallback.
+type AdminMetadata struct {
+	Version  int                    `json:"version"` // see AdminMetadataVersion
+	Entities []*AdminEntityMetadata `json:"entities"`
+}
+
+// AdminEntityMetadata describes a single entity, see AdminMetadata
+type AdminEntityMetadata struct {
+	Id           IdUid                    `json:"id"`
+	Name         string                   `json:"name"`
+	Label        string                   `json:"label"`
+	Description  string                   `json:"description,omitempty"`
+	ExternalName string                   `json:"externalName,omitempty"`
+	Properties   []*AdminPropertyMetadata `json:"properties"`
+	Relations    []*AdminRelationMetadata `json:"relations,omitempty"`
+}
+
+// AdminPropertyMetadata describes a single property, see AdminMetadata
+type AdminPropertyMetadata struct {
+	Id           IdUid  `json:"id"`
+	Name         string `json:"name"`
+	Label        string `json:"label"`
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type"` // the ObjectBox property type, e.g. "String"
+	ExternalName string `json:"externalName,omitempty"`
+	ExternalType string `json:"externalType,omitempty"`
+	Target       string `json:"target,omitempty"` // the target entity of a to-one relation
+}
+
+// AdminRelationMetadata describes a to-many relation of an entity: a standalone relation or a backlink (the inverse
+// of a to-one relation property, not stored itself and thus without an ID), see AdminMetadata
+type AdminRelationMetadata struct {
+	Id           IdUid  `json:"id,omitempty"`
+	Name         string `json:"name"`
+	Label        string `json:"label"`
+	Target       string `json:"target"`
+	Backlink     string `json:"backlink,omitempty"` // the to-one relation property of the target this is the inverse of
+	ExternalName string `json:"externalName,omitempty"`
+	ExternalType string `json:"externalType,omitempty"`
+}
+
+// CreateAdminMetadata collects the Admin metadata of all entities. Descriptions are only available for entities read
+// from the sources in the current run (comments aren't stored in the model JSON file).
+func (model *ModelInfo) CreateAdminMetadata() *AdminMetadata {
+	var metadata = &AdminMetadata{Version: AdminMetadataVersion, Entities: make([]*AdminEntityMetadata, 0, len(model.Entities))}
+	for _, entity := range model.Entities {
+		var entityMetadata = &AdminEntityMetadata{
+			Id:           entity.Id,
+			Name:         entity.Name,
+			Label:        AdminLabel(entity.Name),
+			Description:  strings.Join(entity.Comments, "\n"),
+			ExternalName: entity.ExternalName,
+			Properties:   make([]*AdminPropertyMetadata, 0, len(entity.Properties)),
+		}
+		for _, property := range entity.Properties {
+			entityMetadata.Properties = append(entityMetadata.Properties, &AdminPropertyMetadata{
+				Id:           property.Id,
+				Name:         property.Name,
+				Label:        AdminLabel(property.Name),
+				Description:  strings.Join(property.Comments, "\n"),
+				Type:         PropertyTypeNames[property.Type],
+				ExternalName: property.ExternalName,
+				ExternalType: externalTypeName(property.ExternalType),
+				Target:       property.RelationTarget,
+			})
+		}
+		for _, relation := range entity.Relations {
+			var relationMetadata = &AdminRelationMetadata{
+				Id:           relation.Id,
+				Name:         relation.Name,
+				Label:        AdminLabel(relation.Name),
+				ExternalName: relation.ExternalName,
+				ExternalType: externalTypeName(relation.ExternalType),
+			}
+			if relation.Target != nil {
+				relationMetadata.Target = relation.Target.Name
+			}
+			entityMetadata.Relations = append(entityMetadata.Relations, relationMetadata)
+		}
+		for _, backlink := range entity.Backlinks {
+			if backlink.Source == nil || backlink.Property == nil {
+				continue // not resolved, e.g. the entity wasn't read in the current run
+			}
+			entityMetadata.Relations = append(entityMetadata.Relations, &AdminRelationMetadata{
+				Name:     backlink.Name,
+				Label:    AdminLabel(backlink.Name),
+				Target:   backlink.Source.Name,
+				Backlink: backlink.Property.Name,
+			})
+		}
+		metadata.Entities = append(metadata.Entities, entityMetadata)
+	}
+	return metadata
+}
+
+// AdminLabel turns an entity or a member name into a human-readable label, e.g. "dueDate" to "Due date",
+// "customer_id" to "Customer ID" and "HTTPHeaders" to "HTTP headers"
+func AdminLabel(name string) string {
+	var words []string
+	var runes = []rune(name)
+	var start = 0
+	for i := 0; i <= len(runes); i++ {
+		var split = i == len(runes) || runes[i] == '_'
+		if !split && i > 0 && unicode.IsUpper(runes[i]) {
+			// a new word starts at an upper-case letter following a lower-case one, or the last upper-case letter of an
+			// acronym followed by a lower-case one, e.g. "HTTPHeaders" splits before "Headers"
+			split = unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))
+		}
+		if !split {
+			continue
+		}
+		if i > start {
+			words = append(words, string(runes[start:i]))
+		}
+		if i < len(runes) && runes[i] == '_' {
+			start = i + 1
+		} else {
+			start = i
+		}
+	}
+
+	for i, word := range words {
+		if strings.ToLower(word) == "id" || strings.ToUpper(word) == word {
+			words[i] = strings.ToUpper(word) // acronyms, e.g. "ID" or "URL"
+		} else if i == 0 {
+			var wordRunes = []rune(strings.ToLower(word))
+			wordRunes[0] = unicode.ToUpper(wordRunes[0])
+			words[i] = string(wordRunes)
+		} else {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
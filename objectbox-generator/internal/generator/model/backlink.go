@@ -0,0 +1,118 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backlink is the to-many inverse of a to-one relation, i.e. all objects of the source entity with the relation property
+// pointing to an object of the entity declaring the backlink. Backlinks are only declared in the sources; they aren't
+// written to the model JSON file because the data is stored by the to-one relation.
+type Backlink struct {
+	Name         string    // used to name the generated accessors
+	SourceName   string    // name of the entity declaring the to-one relation
+	PropertyName string    // name of the to-one relation property; may be empty if there's only one to this entity
+	Source       *Entity   // set by ModelInfo.ResolveBacklinks()
+	Property     *Property // set by ModelInfo.ResolveBacklinks()
+}
+
+// AddBacklink declares a backlink on the entity; it's resolved later by ModelInfo.ResolveBacklinks()
+func (entity *Entity) AddBacklink(name, sourceName, propertyName string) (*Backlink, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("backlink name must not be empty")
+	}
+	if len(sourceName) == 0 {
+		return nil, fmt.Errorf("backlink %s: source entity must not be empty", name)
+	}
+	if entity.FindBacklinkByName(name) != nil {
+		return nil, fmt.Errorf("duplicate backlink %s", name)
+	}
+
+	var backlink = &Backlink{Name: name, SourceName: sourceName, PropertyName: propertyName}
+	entity.Backlinks = append(entity.Backlinks, backlink)
+	return backlink, nil
+}
+
+// FindBacklinkByName finds a backlink by name, returns nil if there's none
+func (entity *Entity) FindBacklinkByName(name string) *Backlink {
+	for _, backlink := range entity.Backlinks {
+		if strings.ToLower(backlink.Name) == strings.ToLower(name) {
+			return backlink
+		}
+	}
+	return nil
+}
+
+// ResolveBacklinks finds the source entities and to-one relation properties of all backlinks in the model.
+// Must be called after the entities read from the sources are merged into the model.
+func (model *ModelInfo) ResolveBacklinks() error {
+	for _, entity := range model.Entities {
+		for _, backlink := range entity.Backlinks {
+			if err := backlink.resolve(entity); err != nil {
+				return fmt.Errorf("entity %s backlink %s: %s", entity.Name, backlink.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (backlink *Backlink) resolve(entity *Entity) error {
+	if _, err := entity.FindPropertyByName(backlink.Name); err == nil {
+		return fmt.Errorf("name collides with a property of the same name")
+	} else if _, err := entity.FindRelationByName(backlink.Name); err == nil {
+		return fmt.Errorf("name collides with a relation of the same name")
+	}
+
+	source, err := entity.Model.FindEntityByName(backlink.SourceName)
+	if err != nil {
+		return fmt.Errorf("source entity %s not found", backlink.SourceName)
+	}
+
+	var candidates []*Property
+	for _, property := range source.Properties {
+		if property.RelationTarget != entity.Name {
+			continue
+		}
+		if len(backlink.PropertyName) == 0 || strings.ToLower(property.Name) == strings.ToLower(backlink.PropertyName) {
+			candidates = append(candidates, property)
+		}
+	}
+
+	if len(candidates) == 0 {
+		if len(backlink.PropertyName) != 0 {
+			return fmt.Errorf("%s.%s is not a to-one relation to %s", source.Name, backlink.PropertyName, entity.Name)
+		}
+		return fmt.Errorf("%s has no to-one relation to %s", source.Name, entity.Name)
+	} else if len(candidates) > 1 {
+		var names []string
+		for _, property := range candidates {
+			names = append(names, property.Name)
+		}
+		return fmt.Errorf("%s has multiple to-one relations to %s (%s) - specify the relation property of the backlink",
+			source.Name, entity.Name, strings.Join(names, ", "))
+	}
+
+	backlink.Source = source
+	backlink.Property = candidates[0]
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "fmt"
+
+// EntityBuilder assembles an entity in memory, e.g. by tools deriving the schema from a database or an API
+// specification, to generate bindings without writing source files, see generator.ProcessModel(). For example:
+//
+//	entity, err := model.NewEntity("Customer").
+//		AddProperty("id", model.PropertyTypeLong, model.PropertyFlagId).
+//		AddProperty("email", model.PropertyTypeString, model.PropertyFlagUnique).
+//		AddToOne("region", "Region").
+//		Entity()
+//
+// The methods can be chained; the first error is kept and returned by Entity(). IDs and UIDs are left empty so that
+// they're assigned from the model JSON file when generating, unless pinned with Uid() or PropertyUid().
+type EntityBuilder struct {
+	entity *Entity
+	err    error
+}
+
+// NewEntity starts building an entity with the given name
+func NewEntity(name string) *EntityBuilder {
+	var builder = &EntityBuilder{entity: &Entity{Name: name, Properties: make([]*Property, 0)}}
+	if len(name) == 0 {
+		builder.err = fmt.Errorf("entity name can't be empty")
+	}
+	return builder
+}
+
+// Flags sets the entity flags, e.g. EntityFlagSyncEnabled
+func (builder *EntityBuilder) Flags(flags EntityFlags) *EntityBuilder {
+	builder.entity.Flags = flags
+	return builder
+}
+
+// Uid pins the UID of the entity, e.g. to keep it when the entity is renamed
+func (builder *EntityBuilder) Uid(uid Uid) *EntityBuilder {
+	builder.entity.Id = CreateIdUid(0, uid)
+	return builder
+}
+
+// Comments sets the documentation of the entity, copied to the generated code
+func (builder *EntityBuilder) Comments(lines ...string) *EntityBuilder {
+	builder.entity.Comments = lines
+	return builder
+}
+
+// AddProperty adds a property of the given type with the given flags, e.g. PropertyFlagId or PropertyFlagIndexed
+func (builder *EntityBuilder) AddProperty(name string, propertyType PropertyType, flags ...PropertyFlags) *EntityBuilder {
+	if propertyType == PropertyTypeRelation {
+		return builder.fail(fmt.Errorf("property %s.%s: use AddToOne() to add a relation", builder.entity.Name, name))
+	}
+	if _, known := PropertyTypeNames[propertyType]; !known {
+		return builder.fail(fmt.Errorf("property %s.%s: unknown type %d", builder.entity.Name, name, propertyType))
+	}
+	if property := builder.addProperty(name); property != nil {
+		property.Type = propertyType
+		for _, flag := range flags {
+			property.AddFlag(flag)
+		}
+	}
+	return builder
+}
+
+// AddToOne adds a to-one relation property linking to the entity with the given name
+func (builder *EntityBuilder) AddToOne(name, target string) *EntityBuilder {
+	if len(target) == 0 {
+		return builder.fail(fmt.Errorf("relation %s.%s: target entity name can't be empty", builder.entity.Name, name))
+	}
+	if property := builder.addProperty(name); property != nil {
+		property.Type = PropertyTypeRelation
+		property.RelationTarget = target
+	}
+	return builder
+}
+
+// AddToMany adds a standalone (many-to-many) relation to the entity with the given name
+func (builder *EntityBuilder) AddToMany(name, target string) *EntityBuilder {
+	if len(name) == 0 {
+		return builder.fail(fmt.Errorf("entity %s: relation name can't be empty", builder.entity.Name))
+	}
+	if len(target) == 0 {
+		return builder.fail(fmt.Errorf("relation %s.%s: target entity name can't be empty", builder.entity.Name, name))
+	}
+	if _, err := builder.entity.FindRelationByName(name); err == nil {
+		return builder.fail(fmt.Errorf("entity %s: duplicate relation %s", builder.entity.Name, name))
+	}
+	var relation = CreateStandaloneRelation(builder.entity, "")
+	relation.Name = name
+	relation.Target = &Entity{Name: target}
+	builder.entity.Relations = append(builder.entity.Relations, relation)
+	return builder
+}
+
+// PropertyUid pins the UID of a previously added property, e.g. to keep it when the property is renamed
+func (builder *EntityBuilder) PropertyUid(name string, uid Uid) *EntityBuilder {
+	if property, err := builder.entity.FindPropertyByName(name); err != nil {
+		return builder.fail(err)
+	} else {
+		property.Id = CreateIdUid(0, uid)
+	}
+	return builder
+}
+
+// Entity returns the built entity or the first error encountered while building it
+func (builder *EntityBuilder) Entity() (*Entity, error) {
+	if builder.err != nil {
+		return nil, builder.err
+	}
+	return builder.entity, nil
+}
+
+func (builder *EntityBuilder) addProperty(name string) *Property {
+	if builder.err != nil {
+		return nil
+	}
+	if len(name) == 0 {
+		builder.err = fmt.Errorf("entity %s: property name can't be empty", builder.entity.Name)
+		return nil
+	}
+	if _, err := builder.entity.FindPropertyByName(name); err == nil {
+		builder.err = fmt.Errorf("entity %s: duplicate property %s", builder.entity.Name, name)
+		return nil
+	}
+	var property = &Property{Name: name, Entity: builder.entity}
+	builder.entity.Properties = append(builder.entity.Properties, property)
+	return property
+}
+
+func (builder *EntityBuilder) fail(err error) *EntityBuilder {
+	if builder.err == nil {
+		builder.err = err
+	}
+	return builder
+}
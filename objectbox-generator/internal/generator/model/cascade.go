@@ -0,0 +1,109 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+)
+
+// CascadeDelete describes a relation annotated with `cascade`: removing an object of Entity should also remove the
+// related objects of Target. The generator only records the rules (see CreateCascadeDeletes()), it's up to the runtime
+// library or the application to honor them.
+//   - a to-one relation property, e.g. Order.customer: removing a Customer removes the Orders pointing to it
+//   - a standalone relation, e.g. Customer.addresses: removing a Customer removes the related Addresses
+type CascadeDelete struct {
+	Entity   string `json:"entity"`
+	Target   string `json:"target"`
+	Relation string `json:"relation"`        // the annotated relation, qualified by its entity, e.g. "Order.customer"
+	ToOne    bool   `json:"toOne,omitempty"` // the relation is a to-one relation property of Target pointing to Entity
+}
+
+// CreateCascadeDeletes collects cascade delete rules of all relations in the model, or nil if there are none
+func (model *ModelInfo) CreateCascadeDeletes() []*CascadeDelete {
+	var cascades []*CascadeDelete
+	for _, entity := range model.Entities {
+		for _, property := range entity.Properties {
+			if property.Cascade {
+				cascades = append(cascades, &CascadeDelete{
+					Entity:   property.RelationTarget,
+					Target:   entity.Name,
+					Relation: entity.Name + "." + property.Name,
+					ToOne:    true,
+				})
+			}
+		}
+		for _, relation := range entity.Relations {
+			if relation.Cascade && relation.Target != nil {
+				cascades = append(cascades, &CascadeDelete{
+					Entity:   entity.Name,
+					Target:   relation.Target.Name,
+					Relation: entity.Name + "." + relation.Name,
+				})
+			}
+		}
+	}
+	return cascades
+}
+
+// CascadeDeletesJSON returns the given rules as a JSON document indented by the given string, e.g. to be embedded in
+// the generated code
+func CascadeDeletesJSON(cascades []*CascadeDelete, indent string) (string, error) {
+	data, err := json.MarshalIndent(cascades, "", indent)
+	return string(data), err
+}
+
+// CheckCascadeCycles makes sure removing an object can't cascade back to its own entity, e.g. through a self-relation,
+// which would make the result depend on the order of removal (or never finish, given a cycle of objects).
+func (model *ModelInfo) CheckCascadeCycles() error {
+	var edges = make(map[string][]*CascadeDelete)
+	for _, cascade := range model.CreateCascadeDeletes() {
+		edges[cascade.Entity] = append(edges[cascade.Entity], cascade)
+	}
+
+	// DFS cycle check, storing the path of cascades in the recursion stack
+	var recursionStack = make(map[string]bool)
+	var path []string
+	var visit func(entity string) error
+	visit = func(entity string) error {
+		recursionStack[entity] = true
+		for _, cascade := range edges[entity] {
+			path = append(path, cascade.Relation)
+			if recursionStack[cascade.Target] {
+				return messages.CascadeCycle.Errorf(entity, cascade.Target, strings.Join(path, " -> "))
+			} else if err := visit(cascade.Target); err != nil {
+				return err
+			}
+			path = path[:len(path)-1]
+		}
+		delete(recursionStack, entity)
+		return nil
+	}
+
+	for _, entity := range model.Entities {
+		if err := visit(entity.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"strings"
+)
+
+// DocComments normalizes documentation comment lines read from the sources, i.e. the text without comment markers,
+// for Entity.Comments, Property.Comments etc.: lines are trimmed, leading and trailing empty lines are removed and
+// consecutive empty lines (separating paragraphs) are collapsed to one.
+func DocComments(lines []string) []string {
+	var result []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 && (len(result) == 0 || len(result[len(result)-1]) == 0) {
+			continue
+		}
+		result = append(result, line)
+	}
+	if len(result) > 0 && len(result[len(result)-1]) == 0 {
+		result = result[:len(result)-1]
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// see the doc-comments cases in test/comparison/testdata for the comments in the generated code
+func TestDocComments(t *testing.T) {
+	// leading & trailing empty lines are dropped, paragraphs are kept
+	assert.Eq(t, []string{"first", "", "second"}, DocComments([]string{"", " first ", "", "", "second", " "}))
+	assert.Eq(t, 0, len(DocComments([]string{" ", ""})))
+}
@@ -0,0 +1,49 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+// ConstantGroup is a named set of constants declared in the schema, e.g. validation limits like a maximum name length,
+// which the generated code exposes in each language so that they stay in sync with the model.
+// Constants are not stored in the model JSON.
+type ConstantGroup struct {
+	Name      string // the name without the namespace, e.g. "Limits"
+	Namespace string // dot-separated, e.g. "my.ns"
+	Constants []*Constant
+	Comments  []string
+}
+
+// Constant is a single named value of a ConstantGroup
+type Constant struct {
+	Name     string
+	Type     PropertyType // Bool, Byte, Short, Int, Long, Float or Double
+	Unsigned bool
+	Value    string // the value formatted as a literal valid in all the generated languages, e.g. "64", "0.5" or "true"
+
+	Comments []string
+}
+
+// FullName returns the namespace-qualified (dot-separated) group name
+func (group *ConstantGroup) FullName() string {
+	if len(group.Namespace) == 0 {
+		return group.Name
+	}
+	return group.Namespace + "." + group.Name
+}
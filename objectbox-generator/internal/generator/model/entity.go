@@ -24,6 +24,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 )
 
 // Entity represents a DB entity
@@ -35,11 +37,32 @@ type Entity struct {
 	Flags            EntityFlags           `json:"flags,omitempty"`
 	Properties       []*Property           `json:"properties"`
 	Relations        []*StandaloneRelation `json:"relations,omitempty"`
-	UidRequest       bool                  `json:"-"` // used when the user gives an empty uid annotation
+	AddedInVersion   int                   `json:"addedInVersion,omitempty"` // see ModelInfo.SchemaVersion
+	UidRequest       bool                  `json:"-"`                        // used when the user gives an empty uid annotation
 	Meta             EntityMeta            `json:"-"`
 	CurrentlyPresent bool                  `json:"-"`
 	Comments         []string              `json:"-"`
 	Model            *ModelInfo            `json:"-"`
+
+	// Backlinks lists the to-many inverses of to-one relations pointing to this entity, see Backlink
+	Backlinks []*Backlink `json:"-"`
+
+	// TransientProperties records fields declared in the source but explicitly excluded from persistence
+	TransientProperties []*TransientProperty `json:"-"`
+
+	// ModuleModelFile is set for entities merged from the model JSON file of another module (instead of the sources)
+	ModuleModelFile string `json:"-"`
+
+	// Reserved lists the IDs and UIDs declared as reserved by the entity source; for entities in the model, only the
+	// PropertyIds are used while the rest is added to the model, see ModelInfo.Reserve()
+	Reserved Reservations `json:"-"`
+}
+
+// TransientProperty is a field ignored by an explicit annotation, e.g. `objectbox:"-"` or `objectbox:"transient"`
+type TransientProperty struct {
+	Name      string
+	AllowDrop bool // confirms dropping the data of a previously stored property with the same name
+	Retired   bool // explicitly retired, e.g. `objectbox:"retired"`; its UID is kept in the retired list
 }
 
 // CreateEntity constructs an Entity
@@ -80,7 +103,7 @@ func (entity *Entity) Validate() (err error) {
 			// ObjectBox core internally converts to lowercase so we should check it as this as well
 			var realName = strings.ToLower(property.Name)
 			if propertiesByName[realName] {
-				return fmt.Errorf("duplicate property name '%s' (note that property names are case insensitive)", property.Name)
+				return messages.DuplicatePropertyName.Errorf(property.Name)
 			}
 			propertiesByName[realName] = true
 
@@ -112,19 +135,16 @@ func (entity *Entity) Validate() (err error) {
 		return fmt.Errorf("properties are not defined or not an array")
 	}
 
-	var idProp *Property
 	for _, property := range entity.Properties {
 		err = property.Validate()
 		if err != nil {
 			return fmt.Errorf("property %s %s is invalid: %s", property.Name, string(property.Id), err)
 		}
-		if property.IsIdProperty() {
-			if idProp != nil {
-				return fmt.Errorf("multiple properties marked as ID: %s (%s) and %s (%s)",
-					idProp.Name, idProp.Id, property.Name, property.Id)
-			}
-			idProp = property
-		}
+	}
+
+	// flags which are only valid for sync-enabled entities or on a single property, see Validate()
+	if errs := entity.checkFlags(); len(errs) > 0 {
+		return errs[0]
 	}
 
 	for _, relation := range entity.Relations {
@@ -143,10 +163,45 @@ func (entity *Entity) Validate() (err error) {
 	return nil
 }
 
+// CheckUniqueOnConflictReplace verifies that at most a single property replaces objects on a unique conflict,
+// see PropertyFlagUniqueOnConflictReplace
+func (entity *Entity) CheckUniqueOnConflictReplace() error {
+	var replaceProp *Property
+	for _, property := range entity.Properties {
+		if property.Flags&PropertyFlagUniqueOnConflictReplace == 0 {
+			continue
+		}
+		if property.Flags&PropertyFlagUnique == 0 {
+			return fmt.Errorf("property %s replaces objects on a unique conflict but isn't unique", property.Name)
+		}
+		if replaceProp != nil {
+			return messages.MultipleReplace.Errorf(replaceProp.Name, property.Name)
+		}
+		replaceProp = property
+	}
+	return nil
+}
+
+// checkExternalId verifies that at most a single property is the external ID, i.e. the ID of the object in an external
+// system, see Property.ExternalId
+func (entity *Entity) checkExternalId() error {
+	var externalIdProp *Property
+	for _, property := range entity.Properties {
+		if !property.ExternalId {
+			continue
+		}
+		if externalIdProp != nil {
+			return messages.MultipleExternalIds.Errorf(externalIdProp.Name, property.Name)
+		}
+		externalIdProp = property
+	}
+	return nil
+}
+
 func (entity *Entity) finalize() error {
 	for _, property := range entity.Properties {
 		if err := property.finalize(); err != nil {
-			return err
+			return fmt.Errorf("property %s %s is invalid: %s", property.Name, string(property.Id), err)
 		}
 	}
 	if err := entity.AutosetIdProperty(nil); err != nil {
@@ -172,14 +227,13 @@ func (entity *Entity) AutosetIdProperty(acceptedTypes []PropertyType) error {
 		for _, property := range entity.Properties {
 			if strings.ToLower(property.Name) == "id" && property.hasValidTypeAsId(acceptedTypes) {
 				if idProp != nil {
-					return fmt.Errorf("multiple properties recognized as an ID: %s (%s) and %s (%s)",
-						idProp.Name, idProp.Id, property.Name, property.Id)
+					return messages.MultipleIdCandidates.Errorf(idProp.Name, idProp.Id, property.Name, property.Id)
 				}
 				idProp = property
 			}
 		}
 		if idProp == nil {
-			return errors.New("no property recognized as an ID")
+			return messages.NoIdProperty.Errorf()
 		}
 
 		idProp.Flags = idProp.Flags | PropertyFlagId
@@ -196,6 +250,26 @@ func (entity *Entity) AddFlag(flag EntityFlags) {
 	entity.Flags = entity.Flags | flag
 }
 
+// AddTransientProperty records a field excluded from persistence, see TransientProperties
+func (entity *Entity) AddTransientProperty(name string, allowDrop bool) {
+	entity.TransientProperties = append(entity.TransientProperties, &TransientProperty{Name: name, AllowDrop: allowDrop})
+}
+
+// AddRetiredProperty records a field explicitly retired from persistence, see TransientProperty.Retired
+func (entity *Entity) AddRetiredProperty(name string) {
+	entity.TransientProperties = append(entity.TransientProperties, &TransientProperty{Name: name, AllowDrop: true, Retired: true})
+}
+
+// FindTransientProperty finds a transient property by name, returning nil if there's none
+func (entity *Entity) FindTransientProperty(name string) *TransientProperty {
+	for _, transient := range entity.TransientProperties {
+		if strings.ToLower(transient.Name) == strings.ToLower(name) {
+			return transient
+		}
+	}
+	return nil
+}
+
 // IdProperty updates finds a property that's defined as an ID and if none is, tries to set one based on its name and type
 func (entity *Entity) IdProperty() (*Property, error) {
 	prop := entity.getIdProperty()
@@ -228,25 +302,41 @@ func (entity *Entity) FindPropertyByName(name string) (*Property, error) {
 	return nil, fmt.Errorf("property named '%s' not found in '%s'", name, entity.Name)
 }
 
-// CreateProperty creates a property
-func (entity *Entity) CreateProperty() (*Property, error) {
-	var id Id = 1
-	if len(entity.Properties) > 0 {
-		id = entity.LastPropertyId.getIdSafe() + 1
+// CreateProperty creates a property; the name is only used to generate a deterministic UID, see GenerateUidFor()
+func (entity *Entity) CreateProperty(name string) (*Property, error) {
+	uniqueUid, err := entity.Model.GenerateUidFor(uidKey("property", entity.Name, name))
+
+	if err != nil {
+		return nil, err
 	}
 
-	uniqueUid, err := entity.Model.GenerateUid()
+	return entity.createProperty(uniqueUid), nil
+}
 
-	if err != nil {
+// CreatePropertyWithUid creates a property with the given (pinned) UID
+func (entity *Entity) CreatePropertyWithUid(uid Uid) (*Property, error) {
+	if err := entity.Model.checkPinnedUid(uid); err != nil {
 		return nil, err
 	}
 
+	return entity.createProperty(uid), nil
+}
+
+func (entity *Entity) createProperty(uniqueUid Uid) *Property {
+	var id Id = 1
+	if len(entity.Properties) > 0 {
+		id = entity.LastPropertyId.getIdSafe() + 1
+	}
+	for entity.Reserved.hasPropertyId(id) {
+		id++
+	}
+
 	var property = CreateProperty(entity, id, uniqueUid)
 
 	entity.Properties = append(entity.Properties, property)
 	entity.LastPropertyId = property.Id
 
-	return property, nil
+	return property
 }
 
 // RemoveProperty removes a property
@@ -274,7 +364,7 @@ func (entity *Entity) RemoveProperty(property *Property) error {
 	entity.Properties = append(entity.Properties[:indexToRemove], entity.Properties[indexToRemove+1:]...)
 
 	// store the UID in the "retired" list so that it's not reused in the future
-	entity.Model.RetiredPropertyUids = append(entity.Model.RetiredPropertyUids, property.Id.getUidSafe())
+	entity.Model.retireUid(&entity.Model.RetiredPropertyUids, property.Id.getUidSafe())
 
 	return nil
 }
@@ -302,9 +392,9 @@ func (entity *Entity) FindRelationByName(name string) (*StandaloneRelation, erro
 	return nil, fmt.Errorf("relation named '%s' not found in '%s'", name, entity.Name)
 }
 
-// CreateRelation creates relation
-func (entity *Entity) CreateRelation() (*StandaloneRelation, error) {
-	id, err := entity.Model.createRelationId()
+// CreateRelation creates relation; the name is only used to generate a deterministic UID, see GenerateUidFor()
+func (entity *Entity) CreateRelation(name string) (*StandaloneRelation, error) {
+	id, err := entity.Model.createRelationId(uidKey("relation", entity.Name, name))
 	if err != nil {
 		return nil, err
 	}
@@ -314,6 +404,17 @@ func (entity *Entity) CreateRelation() (*StandaloneRelation, error) {
 	return relation, nil
 }
 
+// CreateRelationWithUid creates relation with the given (pinned) UID
+func (entity *Entity) CreateRelationWithUid(uid Uid) (*StandaloneRelation, error) {
+	if err := entity.Model.checkPinnedUid(uid); err != nil {
+		return nil, err
+	}
+
+	var relation = CreateStandaloneRelation(entity, entity.Model.createRelationIdWithUid(uid))
+	entity.Relations = append(entity.Relations, relation)
+	return relation, nil
+}
+
 // RemoveRelation removes relation
 func (entity *Entity) RemoveRelation(relation *StandaloneRelation) error {
 	var indexToRemove = -1
@@ -332,7 +433,7 @@ func (entity *Entity) RemoveRelation(relation *StandaloneRelation) error {
 	entity.Relations = append(entity.Relations[:indexToRemove], entity.Relations[indexToRemove+1:]...)
 
 	// store the UID in the "retired" list so that it's not reused in the future
-	entity.Model.RetiredRelationUids = append(entity.Model.RetiredRelationUids, relation.Id.getUidSafe())
+	entity.Model.retireUid(&entity.Model.RetiredRelationUids, relation.Id.getUidSafe())
 
 	return nil
 }
@@ -0,0 +1,71 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"sort"
+)
+
+// Enum is a named set of integer constants used as the type of a property, e.g. a FlatBuffers enum.
+// Enums are not stored in the model JSON, the property keeps the underlying integer type.
+type Enum struct {
+	Name      string       // the name without the namespace, e.g. "Color"
+	Namespace string       // dot-separated, e.g. "my.ns"
+	Type      PropertyType // the underlying (integer) type
+	Unsigned  bool
+	Values    []*EnumValue
+	Comments  []string
+}
+
+// EnumValue is a single named constant of an Enum
+type EnumValue struct {
+	Name  string
+	Value int64 // values of unsigned 64-bit enums above math.MaxInt64 are stored as their two's complement
+
+	Comments []string
+}
+
+// FullName returns the namespace-qualified (dot-separated) enum name
+func (enum *Enum) FullName() string {
+	if len(enum.Namespace) == 0 {
+		return enum.Name
+	}
+	return enum.Namespace + "." + enum.Name
+}
+
+// EnumsOf returns all distinct enums used by the properties of the given entities, sorted by their full name
+func EnumsOf(entities []*Entity) []*Enum {
+	var enums []*Enum
+	var seen = make(map[string]bool)
+	for _, entity := range entities {
+		for _, property := range entity.Properties {
+			if property.Enum == nil || seen[property.Enum.FullName()] {
+				continue
+			}
+			seen[property.Enum.FullName()] = true
+			enums = append(enums, property.Enum)
+		}
+	}
+	sort.Slice(enums, func(i, j int) bool {
+		return enums[i].FullName() < enums[j].FullName()
+	})
+	return enums
+}
@@ -0,0 +1,131 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "encoding/json"
+
+// ExternalMapping describes how the entities map to an external system, e.g. to configure the ObjectBox Sync MongoDB
+// connector: each entity corresponds to a collection and each property to a document field. External names default
+// to the ObjectBox names so that the mapping is complete without looking at the model itself.
+type ExternalMapping struct {
+	Entities []*ExternalEntityMapping `json:"entities"`
+}
+
+// ExternalEntityMapping is the external mapping of a single entity, see ExternalMapping
+type ExternalEntityMapping struct {
+	Name         string                   `json:"name"`
+	ExternalName string                   `json:"externalName"`
+	Sync         bool                     `json:"sync,omitempty"`
+	Properties   []*ExternalMemberMapping `json:"properties"`
+	Relations    []*ExternalMemberMapping `json:"relations,omitempty"`
+}
+
+// ExternalMemberMapping is the external mapping of a property or a standalone relation, see ExternalMapping
+type ExternalMemberMapping struct {
+	Name         string `json:"name"`
+	ExternalName string `json:"externalName"`
+	Type         string `json:"type,omitempty"`         // the ObjectBox property type, e.g. "String"; not set for relations
+	ExternalType string `json:"externalType,omitempty"` // e.g. "MongoId"
+	ExternalId   bool   `json:"externalId,omitempty"`   // the property identifies the object in the external system
+	Target       string `json:"target,omitempty"`       // the target entity of a relation (or a to-one relation property)
+}
+
+// HasExternalMapping checks whether any of the entities, properties or relations has an external name or type, or if
+// any of the properties is an external ID
+func (model *ModelInfo) HasExternalMapping() bool {
+	for _, entity := range model.Entities {
+		if len(entity.ExternalName) > 0 {
+			return true
+		}
+		for _, property := range entity.Properties {
+			if len(property.ExternalName) > 0 || property.ExternalType != ExternalTypeNone || property.ExternalId {
+				return true
+			}
+		}
+		for _, relation := range entity.Relations {
+			if len(relation.ExternalName) > 0 || relation.ExternalType != ExternalTypeNone {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CreateExternalMapping collects the external names and types of all entities, properties and relations.
+// Returns nil if the model doesn't use any external names or types, see HasExternalMapping().
+func (model *ModelInfo) CreateExternalMapping() *ExternalMapping {
+	if !model.HasExternalMapping() {
+		return nil
+	}
+
+	var mapping = &ExternalMapping{Entities: make([]*ExternalEntityMapping, 0, len(model.Entities))}
+	for _, entity := range model.Entities {
+		var entityMapping = &ExternalEntityMapping{
+			Name:         entity.Name,
+			ExternalName: externalNameOr(entity.ExternalName, entity.Name),
+			Sync:         entity.Flags&EntityFlagSyncEnabled != 0,
+			Properties:   make([]*ExternalMemberMapping, 0, len(entity.Properties)),
+		}
+		for _, property := range entity.Properties {
+			entityMapping.Properties = append(entityMapping.Properties, &ExternalMemberMapping{
+				Name:         property.Name,
+				ExternalName: externalNameOr(property.ExternalName, property.Name),
+				Type:         PropertyTypeNames[property.Type],
+				ExternalType: externalTypeName(property.ExternalType),
+				ExternalId:   property.ExternalId,
+				Target:       property.RelationTarget,
+			})
+		}
+		for _, relation := range entity.Relations {
+			var memberMapping = &ExternalMemberMapping{
+				Name:         relation.Name,
+				ExternalName: externalNameOr(relation.ExternalName, relation.Name),
+				ExternalType: externalTypeName(relation.ExternalType),
+			}
+			if relation.Target != nil {
+				memberMapping.Target = relation.Target.Name
+			}
+			entityMapping.Relations = append(entityMapping.Relations, memberMapping)
+		}
+		mapping.Entities = append(mapping.Entities, entityMapping)
+	}
+	return mapping
+}
+
+// JSON returns the mapping as a JSON document indented by the given string, e.g. to be embedded in the generated code
+func (mapping *ExternalMapping) JSON(indent string) (string, error) {
+	data, err := json.MarshalIndent(mapping, "", indent)
+	return string(data), err
+}
+
+func externalNameOr(externalName, name string) string {
+	if len(externalName) > 0 {
+		return externalName
+	}
+	return name
+}
+
+func externalTypeName(externalType ExternalType) string {
+	if externalType == ExternalTypeNone {
+		return ""
+	}
+	return ExternalTypeNames[externalType]
+}
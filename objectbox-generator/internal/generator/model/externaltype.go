@@ -0,0 +1,132 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// externalTypeBaseTypes lists property types that may represent the given external type, as documented in objectbox-c.
+var externalTypeBaseTypes = map[ExternalType][]PropertyType{
+	ExternalTypeInt128:         {PropertyTypeByteVector},
+	ExternalTypeUuid:           {PropertyTypeByteVector},
+	ExternalTypeDecimal128:     {PropertyTypeByteVector},
+	ExternalTypeUuidString:     {PropertyTypeString},
+	ExternalTypeUuidV4:         {PropertyTypeByteVector},
+	ExternalTypeUuidV4String:   {PropertyTypeString},
+	ExternalTypeFlexMap:        {PropertyTypeByteVector},
+	ExternalTypeFlexVector:     {PropertyTypeByteVector},
+	ExternalTypeJson:           {PropertyTypeString},
+	ExternalTypeBson:           {PropertyTypeByteVector},
+	ExternalTypeJavaScript:     {PropertyTypeString},
+	ExternalTypeJsonToNative:   {PropertyTypeString},
+	ExternalTypeInt128Vector:   {PropertyTypeByteVector},
+	ExternalTypeUuidVector:     {PropertyTypeByteVector},
+	ExternalTypeMongoId:        {PropertyTypeByteVector, PropertyTypeString},
+	ExternalTypeMongoIdVector:  {PropertyTypeByteVector},
+	ExternalTypeMongoTimestamp: {PropertyTypeLong},
+	ExternalTypeMongoBinary:    {PropertyTypeByteVector},
+	ExternalTypeMongoRegex:     {PropertyTypeString},
+}
+
+// externalIdTypes lists external types that can represent object IDs, i.e. are valid on ID properties and relations.
+var externalIdTypes = map[ExternalType]bool{
+	ExternalTypeInt128:        true,
+	ExternalTypeInt128Vector:  true,
+	ExternalTypeUuid:          true,
+	ExternalTypeUuidString:    true,
+	ExternalTypeUuidV4:        true,
+	ExternalTypeUuidV4String:  true,
+	ExternalTypeUuidVector:    true,
+	ExternalTypeMongoId:       true,
+	ExternalTypeMongoIdVector: true,
+}
+
+// validateExternalType checks the external type can be represented by the given property
+func (property *Property) validateExternalType() error {
+	if property.ExternalType == ExternalTypeNone {
+		return nil
+	}
+
+	baseTypes, known := externalTypeBaseTypes[property.ExternalType]
+	if !known {
+		return fmt.Errorf("unknown external-type %d", property.ExternalType)
+	}
+
+	// IDs and to-one relations are always stored as Long, the external type describes their representation elsewhere
+	if property.IsIdProperty() || property.Type == PropertyTypeRelation {
+		if !externalIdTypes[property.ExternalType] {
+			return fmt.Errorf("external-type %s can't be used on an ID or relation property - use one of %s",
+				ExternalTypeNames[property.ExternalType], externalIdTypeNames())
+		}
+		return nil
+	}
+
+	for _, baseType := range baseTypes {
+		if property.Type == baseType {
+			return nil
+		}
+	}
+
+	var names = make([]string, len(baseTypes))
+	for i, baseType := range baseTypes {
+		names[i] = PropertyTypeNames[baseType]
+	}
+	return fmt.Errorf("external-type %s requires the property type to be %s but found %s",
+		ExternalTypeNames[property.ExternalType], strings.Join(names, " or "), PropertyTypeNames[property.Type])
+}
+
+// validateExternalId checks the external ID can identify a single object, i.e. it's unique (which implies an index),
+// and that its external type (if any) is one of the ID types
+func (property *Property) validateExternalId() error {
+	if property.IsIdProperty() {
+		return fmt.Errorf("the ID property can't be an external ID, an external ID is a secondary ID")
+	}
+	if property.Flags&PropertyFlagUnique == 0 || property.Flags&(PropertyFlagIndexed|PropertyFlagIndexHash|PropertyFlagIndexHash64) == 0 {
+		return fmt.Errorf("external ID must be unique and indexed")
+	}
+	if property.ExternalType != ExternalTypeNone && !externalIdTypes[property.ExternalType] {
+		return fmt.Errorf("external-type %s can't be used on an external ID - use one of %s",
+			ExternalTypeNames[property.ExternalType], externalIdTypeNames())
+	}
+	return nil
+}
+
+// validateExternalType checks the external type can represent IDs of the relation target objects
+func (relation *StandaloneRelation) validateExternalType() error {
+	if relation.ExternalType == ExternalTypeNone || externalIdTypes[relation.ExternalType] {
+		return nil
+	}
+	return fmt.Errorf("external-type %s can't be used on a relation - use one of %s",
+		ExternalTypeNames[relation.ExternalType], externalIdTypeNames())
+}
+
+// externalIdTypeNames returns a sorted, comma-separated list of external types valid for IDs
+func externalIdTypeNames() string {
+	var names []string
+	for externalType := range externalIdTypes {
+		names = append(names, ExternalTypeNames[externalType])
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -22,10 +22,12 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 )
 
 // LoadOrCreateModel reads a model file or creates a new one if it doesn't exist
@@ -38,16 +40,45 @@ func LoadOrCreateModel(path string) (model *ModelInfo, err error) {
 
 // Close and unlock model
 func (model *ModelInfo) Close() error {
+	if model.file == nil { // read-only model, see LoadModelReadOnly()
+		return nil
+	}
 	return model.file.Close()
 }
 
-// Write current model data to file
+// Write current model data to file, see MarshalCanonical()
 func (model *ModelInfo) Write() error {
-	data, err := json.MarshalIndent(model, "", "  ")
+	return model.WriteUsing(nil)
+}
+
+// WriteUsing writes the current model data like Write() but using the given function to replace the file content,
+// e.g. writing a temporary file renamed to the model file. The model file is reopened afterwards so that the model can
+// be written again. If writeFile is nil, the file is updated in place.
+func (model *ModelInfo) WriteUsing(writeFile func(path string, data []byte) error) error {
+	if model.file == nil {
+		return errors.New("the model has been loaded as read-only")
+	}
+
+	model.ExternalMapping = model.CreateExternalMapping()
+
+	data, err := model.MarshalCanonical()
 	if err != nil {
 		return err
 	}
 
+	if writeFile != nil {
+		var path = model.file.Name()
+		if err = writeFile(path, data); err != nil {
+			return err
+		}
+		// the previous handle may refer to the replaced file now
+		if err = model.file.Close(); err != nil {
+			return err
+		}
+		model.file, err = os.OpenFile(path, os.O_RDWR, 0)
+		return err
+	}
+
 	if err = model.file.Truncate(0); err != nil {
 		return err
 	}
@@ -63,6 +94,26 @@ func (model *ModelInfo) Write() error {
 	return nil
 }
 
+// MarshalCanonical returns the model JSON in its canonical format, so that the file only changes if the model does:
+// two-space indentation, a trailing newline and the retired UIDs sorted (their order isn't semantic, unlike the
+// order of entities and properties). Keys are written in the fixed order of the ModelInfo fields (maps sorted).
+// The model itself isn't changed, the retired UIDs are sorted in a copy.
+func (model *ModelInfo) MarshalCanonical() ([]byte, error) {
+	var canonical = *model
+	for _, list := range []*[]Uid{&canonical.RetiredEntityUids, &canonical.RetiredIndexUids, &canonical.RetiredPropertyUids, &canonical.RetiredRelationUids} {
+		var sorted = make([]Uid, len(*list)) // also "[]" instead of "null"
+		copy(sorted, *list)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		*list = sorted
+	}
+
+	data, err := json.MarshalIndent(&canonical, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
@@ -97,6 +148,23 @@ func LoadModelFromJSONFile(path string) (model *ModelInfo, err error) {
 	return model, nil
 }
 
+// LoadModelReadOnly loads the model from the given JSON file without keeping the file open, i.e. the model can't be
+// written. If the file doesn't exist, an empty model is returned instead of creating the file.
+func LoadModelReadOnly(path string) (*ModelInfo, error) {
+	if !fileExists(path) {
+		return createModelInfo(), nil
+	}
+
+	model, err := LoadModelFromJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = model.file.Close()
+	model.file = nil
+	return model, err
+}
+
 func createModelJSONFile(path string) (model *ModelInfo, err error) {
 	model = createModelInfo()
 
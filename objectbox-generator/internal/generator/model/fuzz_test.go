@@ -0,0 +1,78 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzModelJSON feeds arbitrary model JSON files to the loader; errors are fine, crashes and hangs are not.
+// Run with `go test ./internal/generator/model -fuzz FuzzModelJSON`.
+func FuzzModelJSON(f *testing.F) {
+	f.Add(`{}`)
+	f.Add(`{"entities": [], "lastEntityId": "", "lastIndexId": "", "lastRelationId": "", "modelVersion": 5, "modelVersionParserMinimum": 5}`)
+	f.Add(`{
+  "entities": [
+    {
+      "id": "1:8717895732742165505",
+      "lastPropertyId": "2:2669985732393126063",
+      "name": "Entity",
+      "properties": [
+        {"id": "1:501233450539197794", "name": "id", "type": 6, "flags": 1},
+        {"id": "2:2669985732393126063", "name": "relId", "indexId": "1:1774932891286980153", "type": 11, "flags": 520, "relationTarget": "Entity"}
+      ],
+      "relations": [
+        {"id": "1:3390393562759376202", "name": "rel", "targetId": "1:8717895732742165505"}
+      ]
+    }
+  ],
+  "lastEntityId": "1:8717895732742165505",
+  "lastIndexId": "1:1774932891286980153",
+  "lastRelationId": "1:3390393562759376202",
+  "modelVersion": 5,
+  "modelVersionParserMinimum": 5,
+  "retiredEntityUids": [],
+  "retiredIndexUids": [],
+  "retiredPropertyUids": [],
+  "retiredRelationUids": []
+}`)
+
+	f.Fuzz(func(t *testing.T, data string) {
+		var path = filepath.Join(t.TempDir(), "objectbox-model.json")
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		model, err := LoadModelReadOnly(path)
+		if err != nil {
+			return
+		}
+		if err = model.Validate(); err != nil {
+			return
+		}
+		_ = model.CheckRelationCycles()
+	})
+}
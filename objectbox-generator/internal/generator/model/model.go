@@ -61,8 +61,42 @@ type ModelInfo struct {
 	RetiredRelationUids  []Uid     `json:"retiredRelationUids"`
 	Version              int       `json:"version"` // user specified version
 
+	// SchemaVersion is incremented by the generator whenever the schema changes, see UpdateSchemaVersion()
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+
+	// RetiredUidVersions maps retired UIDs to the (user specified) model version they were retired in
+	RetiredUidVersions map[Uid]int `json:"retiredUidVersions,omitempty"`
+
+	// GeneratorOptions records the code generator options affecting how the generated code stores data, e.g. whether
+	// empty strings are stored as null; changing them requires a confirmation, see generator.CompatibilityOptionsProvider
+	GeneratorOptions map[string]string `json:"generatorOptions,omitempty"`
+
+	// TenantPrefix records the prefix of all entity names if the model is a tenant's variant of a schema, see
+	// generator.Options.TenantPrefix
+	TenantPrefix string `json:"tenantPrefix,omitempty"`
+
+	// ExternalMapping is (re)created when writing the model JSON file, see CreateExternalMapping()
+	ExternalMapping *ExternalMapping `json:"externalMapping,omitempty"`
+
 	file *os.File   // file handle, locked while the model is open
 	Rand *rand.Rand `json:"-"` // seeded random number generator
+
+	// ConstantGroups declared by the source being processed, see ConstantGroup; like the entities' Meta, they're set on
+	// the stored model for each source before its binding files are written
+	ConstantGroups []*ConstantGroup `json:"-"`
+
+	// DeterministicUids makes new UIDs derived from names (and UidSalt) instead of Rand, see GenerateUidFor()
+	DeterministicUids bool   `json:"-"`
+	UidSalt           string `json:"-"`
+
+	// RetiredEntityNames lists entities explicitly retired in the sources, e.g. using `objectbox:"retired"`
+	RetiredEntityNames []string `json:"-"`
+
+	// AllowDrop permits previously stored properties to become transient, dropping their data, see Entity.TransientProperties
+	AllowDrop bool `json:"-"`
+
+	// Reserved lists entity IDs and UIDs declared as reserved in the processed sources, see Reserve()
+	Reserved Reservations `json:"-"`
 }
 
 var defaultModel = ModelInfo{
@@ -205,7 +239,7 @@ func (model *ModelInfo) Validate() (err error) {
 		return fmt.Errorf("retiredPropertyUids are not defined or not an array")
 	}
 
-	return nil
+	return model.validateRetiredUids()
 }
 
 // Finalize should be called after making changes to the model (e.g. from user schema definitions) to verify and update
@@ -254,17 +288,33 @@ func (model *ModelInfo) FindEntityByName(name string) (*Entity, error) {
 
 // CreateEntity creates an entity
 func (model *ModelInfo) CreateEntity(name string) (*Entity, error) {
-	var id Id = 1
-	if len(model.Entities) > 0 {
-		id = model.LastEntityId.getIdSafe() + 1
+	uniqueUid, err := model.GenerateUidFor(uidKey("entity", name))
+
+	if err != nil {
+		return nil, err
 	}
 
-	uniqueUid, err := model.GenerateUid()
+	return model.createEntity(name, uniqueUid)
+}
 
-	if err != nil {
+// CreateEntityWithUid creates an entity with the given (pinned) UID
+func (model *ModelInfo) CreateEntityWithUid(name string, uid Uid) (*Entity, error) {
+	if err := model.checkPinnedUid(uid); err != nil {
 		return nil, err
 	}
 
+	return model.createEntity(name, uid)
+}
+
+func (model *ModelInfo) createEntity(name string, uniqueUid Uid) (*Entity, error) {
+	var id Id = 1
+	if len(model.Entities) > 0 {
+		id = model.LastEntityId.getIdSafe() + 1
+	}
+	for model.Reserved.hasEntityId(id) {
+		id++
+	}
+
 	var entity = CreateEntity(model, id, uniqueUid)
 	entity.Name = name
 
@@ -304,7 +354,7 @@ func (model *ModelInfo) RemoveEntity(entity *Entity) error {
 	model.Entities = append(model.Entities[:indexToRemove], model.Entities[indexToRemove+1:]...)
 
 	// store the UID in the "retired" list so that it's not reused in the future
-	model.RetiredEntityUids = append(model.RetiredEntityUids, entity.Id.getUidSafe())
+	model.retireUid(&model.RetiredEntityUids, entity.Id.getUidSafe())
 
 	return nil
 }
@@ -337,13 +387,13 @@ func (model *ModelInfo) EntitiesWithMeta() []*Entity {
 	return result
 }
 
-func (model *ModelInfo) createIndexId() (IdUid, error) {
+func (model *ModelInfo) createIndexId(uidKey string) (IdUid, error) {
 	var id Id = 1
 	if len(model.LastIndexId) > 0 {
 		id = model.LastIndexId.getIdSafe() + 1
 	}
 
-	uniqueUid, err := model.GenerateUid()
+	uniqueUid, err := model.GenerateUidFor(uidKey)
 
 	if err != nil {
 		return "", err
@@ -353,20 +403,24 @@ func (model *ModelInfo) createIndexId() (IdUid, error) {
 	return model.LastIndexId, nil
 }
 
-func (model *ModelInfo) createRelationId() (IdUid, error) {
-	var id Id = 1
-	if len(model.LastRelationId) > 0 {
-		id = model.LastRelationId.getIdSafe() + 1
-	}
-
-	uniqueUid, err := model.GenerateUid()
+func (model *ModelInfo) createRelationId(uidKey string) (IdUid, error) {
+	uniqueUid, err := model.GenerateUidFor(uidKey)
 
 	if err != nil {
 		return "", err
 	}
 
+	return model.createRelationIdWithUid(uniqueUid), nil
+}
+
+func (model *ModelInfo) createRelationIdWithUid(uniqueUid Uid) IdUid {
+	var id Id = 1
+	if len(model.LastRelationId) > 0 {
+		id = model.LastRelationId.getIdSafe() + 1
+	}
+
 	model.LastRelationId = CreateIdUid(id, uniqueUid)
-	return model.LastRelationId, nil
+	return model.LastRelationId
 }
 
 // recursively checks whether given UID is present in the model
@@ -395,6 +449,14 @@ func (model *ModelInfo) containsUid(searched Uid) bool {
 		return true
 	}
 
+	if searchSliceUid(model.RetiredRelationUids, searched) {
+		return true
+	}
+
+	if model.Reserved.hasUid(searched) {
+		return true
+	}
+
 	for _, entity := range model.Entities {
 		if entity.containsUid(searched) {
 			return true
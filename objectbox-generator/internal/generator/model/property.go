@@ -35,6 +35,12 @@ const (
 	HnswDistanceType_Geo                     = "Geo"
 )
 
+// Storage types of float vectors, see Property.StorageType
+const (
+	StorageTypeFloat32 = "float32" // the default, i.e. not recorded in the model
+	StorageTypeFloat16 = "float16" // half precision, halving the size of stored vectors at the cost of accuracy
+)
+
 type HnswParams struct {
 	Dimensions                    *uint64    `json:"dimensions,omitempty"`
 	DistanceType                  string     `json:"distance-type,omitempty"`
@@ -53,13 +59,20 @@ type Property struct {
 	Type           PropertyType  `json:"type"`
 	ExternalName   string        `json:"externalName,omitempty"`
 	ExternalType   ExternalType  `json:"externalType,omitempty"`
+	ExternalId     bool          `json:"externalId,omitempty"` // the ID of the object in an external system, e.g. a MongoDB _id
 	Flags          PropertyFlags `json:"flags,omitempty"`
 	RelationTarget string        `json:"relationTarget,omitempty"`
+	Cascade        bool          `json:"cascade,omitempty"`   // removing the target object removes this (source) object, see CascadeDelete
+	Encrypted      bool          `json:"encrypted,omitempty"` // stored encrypted by user-supplied functions, recorded for audits
 	Entity         *Entity       `json:"-"`
 	UidRequest     bool          `json:"-"` // used when the user gives an empty uid annotation
 	HnswParams     *HnswParams   `json:"hnswParams,omitempty"`
+	StorageType    string        `json:"storageType,omitempty"`    // float vectors stored with half precision are "float16"
+	AddedInVersion int           `json:"addedInVersion,omitempty"` // see ModelInfo.SchemaVersion
 	Meta           PropertyMeta  `json:"-"`
 	Comments       []string      `json:"-"`
+	Enum           *Enum         `json:"-"` // set if the property is declared with an enum type
+	ArrayLength    uint32        `json:"-"` // set if the property is declared as a fixed-length array, see SetArrayLength()
 }
 
 // CreateProperty creates a property
@@ -85,6 +98,28 @@ func (property *Property) CheckHnswParams() error {
 	return nil
 }
 
+// SetArrayLength marks the property as a fixed-length array, e.g. `[float:4]` in a FlatBuffers schema.
+// Call after processing the annotations: an HNSW index defaults to the same number of dimensions.
+func (property *Property) SetArrayLength(length uint32) error {
+	if property.Type != PropertyTypeFloatVector {
+		return fmt.Errorf("fixed-length arrays are only supported for float vectors, found %s", PropertyTypeNames[property.Type])
+	}
+	if length == 0 {
+		return fmt.Errorf("fixed-length array must not be empty")
+	}
+	property.ArrayLength = length
+
+	if property.HnswParams != nil {
+		if property.HnswParams.Dimensions == nil {
+			var dimensions = uint64(length)
+			property.HnswParams.Dimensions = &dimensions
+		} else if *property.HnswParams.Dimensions != uint64(length) {
+			return fmt.Errorf("HNSW dimensions %d don't match the array length %d", *property.HnswParams.Dimensions, length)
+		}
+	}
+	return nil
+}
+
 // Validate performs initial validation of loaded data so that it doesn't have to be checked in each function
 func (property *Property) Validate() error {
 	if property.Entity == nil {
@@ -111,11 +146,9 @@ func (property *Property) Validate() error {
 	//	return fmt.Errorf("type is undefined")
 	// }
 
-	// IDs must not be tagged unsigned for compatibility reasons
-	if property.IsIdProperty() {
-		if !property.hasValidTypeAsId(nil) {
-			return fmt.Errorf("invalid type on property marked as ID: %d", property.Type)
-		}
+	// flags and settings depending on the type, see Validate()
+	if errs := property.checkFlags(); len(errs) > 0 {
+		return errs[0]
 	}
 
 	return nil
@@ -158,7 +191,7 @@ func (property *Property) CreateIndex() error {
 		return fmt.Errorf("can't create an index - it already exists")
 	}
 
-	indexId, err := property.Entity.Model.createIndexId()
+	indexId, err := property.Entity.Model.createIndexId(uidKey("index", property.Entity.Name, property.Name))
 	if err != nil {
 		return err
 	}
@@ -167,13 +200,27 @@ func (property *Property) CreateIndex() error {
 	return nil
 }
 
+// ResetUid assigns a new ID & the given UID to the property, effectively resetting its data.
+// The previous UID is retired so that it's not reused in the future.
+func (property *Property) ResetUid(uid Uid) error {
+	highestId, _, err := property.Entity.LastPropertyId.Get()
+	if err != nil {
+		return err
+	}
+
+	property.Entity.Model.retireUid(&property.Entity.Model.RetiredPropertyUids, property.Id.getUidSafe())
+	property.Id = CreateIdUid(highestId+1, uid)
+	property.Entity.LastPropertyId = property.Id
+	return nil
+}
+
 // RemoveIndex removes an index
 func (property *Property) RemoveIndex() error {
 	if property.IndexId == nil {
 		return fmt.Errorf("can't remove index - it's not defined")
 	}
 
-	property.Entity.Model.RetiredIndexUids = append(property.Entity.Model.RetiredIndexUids, property.IndexId.getUidSafe())
+	property.Entity.Model.retireUid(&property.Entity.Model.RetiredIndexUids, property.IndexId.getUidSafe())
 
 	property.IndexId = nil
 
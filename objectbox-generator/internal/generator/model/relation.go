@@ -27,15 +27,17 @@ import (
 
 // StandaloneRelation in a model
 type StandaloneRelation struct {
-	Id           IdUid                  `json:"id"`
-	Name         string                 `json:"name"`
-	Target       *Entity                `json:"-"` // TODO consider changing to TargetName, nothing else seems to be used.
-	ExternalName string					`json:"externalName,omitempty"`
-	ExternalType ExternalType			`json:"externalType,omitempty"`
-	TargetId     IdUid                  `json:"targetId"`
-	UidRequest   bool                   `json:"-"` // used when the user gives an empty uid annotation // TODO test
-	Meta         StandaloneRelationMeta `json:"-"`
-	entity       *Entity
+	Id             IdUid                  `json:"id"`
+	Name           string                 `json:"name"`
+	Target         *Entity                `json:"-"` // TODO consider changing to TargetName, nothing else seems to be used.
+	ExternalName   string                 `json:"externalName,omitempty"`
+	ExternalType   ExternalType           `json:"externalType,omitempty"`
+	TargetId       IdUid                  `json:"targetId"`
+	Cascade        bool                   `json:"cascade,omitempty"`        // removing the source object removes the targets, see CascadeDelete
+	AddedInVersion int                    `json:"addedInVersion,omitempty"` // see ModelInfo.SchemaVersion
+	UidRequest     bool                   `json:"-"`                        // used when the user gives an empty uid annotation // TODO test
+	Meta           StandaloneRelationMeta `json:"-"`
+	entity         *Entity
 }
 
 // CreateStandaloneRelation creates a standalone relation
@@ -69,6 +71,10 @@ func (relation *StandaloneRelation) Validate() error {
 		}
 	}
 
+	if err := relation.validateExternalType(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -20,7 +20,7 @@
 
 package model
 
-import "fmt"
+import "github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
 
 // CheckRelationCycles finds relations cycles
 func (model *ModelInfo) CheckRelationCycles() error {
@@ -69,7 +69,7 @@ func checkRelationCycle(recursionStack *map[*Entity]bool, path string, relTarget
 	}
 
 	if (*recursionStack)[relTarget] {
-		return fmt.Errorf("relation cycle detected: %s (%s)", path, relTarget.Name)
+		return messages.RelationCycle.Errorf(path, relTarget.Name)
 	}
 
 	return relTarget.checkRelationCycles(recursionStack, path)
@@ -0,0 +1,99 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "fmt"
+
+// Reservations lists IDs and UIDs the generator must never assign, declared in the sources using the `reserved`,
+// `reserved-entity-ids` and `reserved-uids` entity annotations, e.g. to coordinate entities split or moved across
+// files or teams. Unlike retired UIDs, reservations aren't stored in the model JSON file; they apply while the source
+// declaring them is processed.
+type Reservations struct {
+	PropertyIds []Id  // IDs of properties of the entity declaring them
+	EntityIds   []Id  // entity IDs, model-wide
+	Uids        []Uid // UIDs of any entity, property, index or relation, model-wide
+}
+
+func (reservations *Reservations) hasPropertyId(id Id) bool {
+	return searchSliceId(reservations.PropertyIds, id)
+}
+
+func (reservations *Reservations) hasEntityId(id Id) bool {
+	return searchSliceId(reservations.EntityIds, id)
+}
+
+func (reservations *Reservations) hasUid(uid Uid) bool {
+	return searchSliceUid(reservations.Uids, uid)
+}
+
+// Reserve adds the model-wide reservations (entity IDs and UIDs) declared by an entity read from the sources
+func (model *ModelInfo) Reserve(reservations Reservations) {
+	for _, id := range reservations.EntityIds {
+		if !model.Reserved.hasEntityId(id) {
+			model.Reserved.EntityIds = append(model.Reserved.EntityIds, id)
+		}
+	}
+	for _, uid := range reservations.Uids {
+		if !model.Reserved.hasUid(uid) {
+			model.Reserved.Uids = append(model.Reserved.Uids, uid)
+		}
+	}
+}
+
+// CheckReserved makes sure none of the entities, properties, indexes and relations in the model uses a reserved ID or
+// UID. New ones are assigned around the reservations so this only fails for elements already present in the model
+// (or with a pinned UID).
+func (model *ModelInfo) CheckReserved() error {
+	for _, entity := range model.Entities {
+		if model.Reserved.hasEntityId(entity.Id.getIdSafe()) {
+			return fmt.Errorf("entity %s %s uses a reserved ID", entity.Name, entity.Id)
+		}
+		if model.Reserved.hasUid(entity.Id.getUidSafe()) {
+			return fmt.Errorf("entity %s %s uses a reserved UID", entity.Name, entity.Id)
+		}
+		for _, property := range entity.Properties {
+			if entity.Reserved.hasPropertyId(property.Id.getIdSafe()) {
+				return fmt.Errorf("property %s.%s %s uses an ID reserved in the entity", entity.Name, property.Name, property.Id)
+			}
+			if model.Reserved.hasUid(property.Id.getUidSafe()) {
+				return fmt.Errorf("property %s.%s %s uses a reserved UID", entity.Name, property.Name, property.Id)
+			}
+			if property.IndexId != nil && model.Reserved.hasUid(property.IndexId.getUidSafe()) {
+				return fmt.Errorf("index of property %s.%s %s uses a reserved UID", entity.Name, property.Name, *property.IndexId)
+			}
+		}
+		for _, relation := range entity.Relations {
+			if model.Reserved.hasUid(relation.Id.getUidSafe()) {
+				return fmt.Errorf("relation %s.%s %s uses a reserved UID", entity.Name, relation.Name, relation.Id)
+			}
+		}
+	}
+	return nil
+}
+
+func searchSliceId(slice []Id, searched Id) bool {
+	for _, i := range slice {
+		if i == searched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "fmt"
+
+// retireUid stores the UID in the given "retired" list so that it's not reused in the future and records the (user
+// specified) model version it was retired in, see RetiredUidVersions
+func (model *ModelInfo) retireUid(list *[]Uid, uid Uid) {
+	*list = append(*list, uid)
+	if model.RetiredUidVersions == nil {
+		model.RetiredUidVersions = make(map[Uid]int)
+	}
+	model.RetiredUidVersions[uid] = model.Version
+}
+
+// retiredUidKind returns the kind of the model element the given UID was retired from, or an empty string
+func (model *ModelInfo) retiredUidKind(uid Uid) string {
+	if searchSliceUid(model.RetiredEntityUids, uid) {
+		return "entity"
+	} else if searchSliceUid(model.RetiredPropertyUids, uid) {
+		return "property"
+	} else if searchSliceUid(model.RetiredIndexUids, uid) {
+		return "index"
+	} else if searchSliceUid(model.RetiredRelationUids, uid) {
+		return "relation"
+	}
+	return ""
+}
+
+// CheckUidNotRetired returns an error if the given UID has been retired, i.e. it must never be used again
+func (model *ModelInfo) CheckUidNotRetired(uid Uid) error {
+	var kind = model.retiredUidKind(uid)
+	if len(kind) == 0 {
+		return nil
+	}
+	if version, found := model.RetiredUidVersions[uid]; found {
+		return fmt.Errorf("UID %d belongs to a retired %s (retired in model version %d) and must not be reused", uid, kind, version)
+	}
+	return fmt.Errorf("UID %d belongs to a retired %s and must not be reused", uid, kind)
+}
+
+// validateRetiredUids checks none of the entities, properties, indexes and relations uses a retired UID
+func (model *ModelInfo) validateRetiredUids() error {
+	for _, entity := range model.Entities {
+		if err := model.CheckUidNotRetired(entity.Id.getUidSafe()); err != nil {
+			return fmt.Errorf("entity %s %s: %s", entity.Name, entity.Id, err)
+		}
+		for _, property := range entity.Properties {
+			if err := model.CheckUidNotRetired(property.Id.getUidSafe()); err != nil {
+				return fmt.Errorf("property %s.%s %s: %s", entity.Name, property.Name, property.Id, err)
+			}
+			if property.IndexId != nil {
+				if err := model.CheckUidNotRetired(property.IndexId.getUidSafe()); err != nil {
+					return fmt.Errorf("index of property %s.%s %s: %s", entity.Name, property.Name, *property.IndexId, err)
+				}
+			}
+		}
+		for _, relation := range entity.Relations {
+			if err := model.CheckUidNotRetired(relation.Id.getUidSafe()); err != nil {
+				return fmt.Errorf("relation %s.%s %s: %s", entity.Name, relation.Name, relation.Id, err)
+			}
+		}
+	}
+	return nil
+}
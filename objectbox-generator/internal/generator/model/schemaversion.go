@@ -0,0 +1,127 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "encoding/json"
+
+// SchemaElementVersion is the schema version an entity, a property ("Entity.property") or a standalone relation
+// ("Entity.relation") was added in, see ModelInfo.SchemaElementVersions()
+type SchemaElementVersion struct {
+	Name    string
+	Version int
+}
+
+// UpgradeSchemaVersion starts the schema versioning of a model stored before it was introduced: the current schema
+// becomes version 1, including all its entities, properties and relations
+func (model *ModelInfo) UpgradeSchemaVersion() {
+	if model.SchemaVersion != 0 || len(model.Entities) == 0 {
+		return
+	}
+	model.SchemaVersion = 1
+	model.setMissingAddedInVersion()
+}
+
+// SchemaFingerprint describes the stored schema, i.e. everything but the versions, see UpdateSchemaVersion()
+func (model *ModelInfo) SchemaFingerprint() string {
+	type fingerprintRelation struct {
+		Id, Name, TargetId string
+	}
+	type fingerprintProperty struct {
+		Id, Name, IndexId, RelationTarget, ExternalName string
+		Type                                            PropertyType
+		ExternalType                                    ExternalType
+		Flags                                           PropertyFlags
+		HnswParams                                      *HnswParams
+		StorageType                                     string `json:",omitempty"`
+		ExternalId                                      bool   `json:",omitempty"`
+	}
+	type fingerprintEntity struct {
+		Id, Name, ExternalName string
+		Flags                  EntityFlags
+		Properties             []fingerprintProperty
+		Relations              []fingerprintRelation
+	}
+
+	var entities []fingerprintEntity
+	for _, entity := range model.Entities {
+		var fpEntity = fingerprintEntity{Id: string(entity.Id), Name: entity.Name, ExternalName: entity.ExternalName, Flags: entity.Flags}
+		for _, property := range entity.Properties {
+			var fpProperty = fingerprintProperty{Id: string(property.Id), Name: property.Name, RelationTarget: property.RelationTarget,
+				ExternalName: property.ExternalName, Type: property.Type, ExternalType: property.ExternalType,
+				Flags: property.Flags, HnswParams: property.HnswParams, StorageType: property.StorageType,
+				ExternalId: property.ExternalId}
+			if property.IndexId != nil {
+				fpProperty.IndexId = string(*property.IndexId)
+			}
+			fpEntity.Properties = append(fpEntity.Properties, fpProperty)
+		}
+		for _, relation := range entity.Relations {
+			fpEntity.Relations = append(fpEntity.Relations, fingerprintRelation{string(relation.Id), relation.Name, string(relation.TargetId)})
+		}
+		entities = append(entities, fpEntity)
+	}
+	data, _ := json.Marshal(entities) // can't fail for these types
+	return string(data)
+}
+
+// UpdateSchemaVersion increments the schema version if the schema differs from the given fingerprint (taken before
+// merging the sources, see SchemaFingerprint()) and records it as the version the new entities, properties and
+// relations were added in
+func (model *ModelInfo) UpdateSchemaVersion(previousFingerprint string) {
+	if model.SchemaVersion == 0 || model.SchemaFingerprint() != previousFingerprint {
+		model.SchemaVersion++
+	}
+	model.setMissingAddedInVersion()
+}
+
+func (model *ModelInfo) setMissingAddedInVersion() {
+	for _, entity := range model.Entities {
+		if entity.AddedInVersion == 0 {
+			entity.AddedInVersion = model.SchemaVersion
+		}
+		for _, property := range entity.Properties {
+			if property.AddedInVersion == 0 {
+				property.AddedInVersion = model.SchemaVersion
+			}
+		}
+		for _, relation := range entity.Relations {
+			if relation.AddedInVersion == 0 {
+				relation.AddedInVersion = model.SchemaVersion
+			}
+		}
+	}
+}
+
+// SchemaElementVersions lists the versions all entities and their properties and relations were added in, e.g. for the
+// generated code to let apps backfill data created by older app versions
+func (model *ModelInfo) SchemaElementVersions() []SchemaElementVersion {
+	var result []SchemaElementVersion
+	for _, entity := range model.Entities {
+		result = append(result, SchemaElementVersion{entity.Name, entity.AddedInVersion})
+		for _, property := range entity.Properties {
+			result = append(result, SchemaElementVersion{entity.Name + "." + property.Name, property.AddedInVersion})
+		}
+		for _, relation := range entity.Relations {
+			result = append(result, SchemaElementVersion{entity.Name + "." + relation.Name, relation.AddedInVersion})
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+
+// CheckSyncRelations makes sure sync-enabled entities only have relations to other sync-enabled entities
+func (model *ModelInfo) CheckSyncRelations() error {
+	for _, entity := range model.Entities {
+		if entity.Flags&EntityFlagSyncEnabled == 0 {
+			continue
+		}
+
+		// to-many relations
+		for _, rel := range entity.Relations {
+			if err := checkSyncRelation(entity, rel.Name, rel.Target); err != nil {
+				return err
+			}
+		}
+
+		// to-one relations
+		for _, prop := range entity.Properties {
+			if prop.RelationTarget == "" {
+				continue
+			}
+
+			relTarget, _ := model.FindEntityByName(prop.RelationTarget)
+			if err := checkSyncRelation(entity, prop.Name, relTarget); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkSyncRelation(entity *Entity, relName string, relTarget *Entity) error {
+	// this happens if the relation target entity hasn't been defined (yet)
+	if relTarget == nil {
+		return nil
+	}
+
+	if relTarget.Flags&EntityFlagSyncEnabled == 0 {
+		return messages.SyncRelation.Errorf(entity.Name, relTarget.Name, relName, relTarget.Name)
+	}
+
+	return nil
+}
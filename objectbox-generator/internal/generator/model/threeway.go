@@ -0,0 +1,474 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MergeConflictError lists the changes of a three-way merge which can't be combined, see MergeThreeWay()
+type MergeConflictError struct {
+	Conflicts []string
+}
+
+func (err MergeConflictError) Error() string {
+	return fmt.Sprintf("can't merge the models, resolve the conflicts manually:\n  %s", strings.Join(err.Conflicts, "\n  "))
+}
+
+// MergeThreeWay merges the changes made to the common ancestor "base" in "theirs" into "ours", e.g. two branches of a
+// VCS both adding entities. Entities, properties, indexes and relations are matched by their UIDs; elements added on
+// both sides are kept and those added in "theirs" get new IDs if "ours" has assigned IDs since "base", so that no ID
+// is used twice (the UIDs never change). Elements retired on either side are retired in the result.
+// Returns notices about the reassigned IDs, i.e. the bindings generated from "theirs" need to be regenerated, or a
+// MergeConflictError if both sides changed the same element differently, leaving "ours" in an undefined state.
+func MergeThreeWay(base, ours, theirs *ModelInfo) ([]string, error) {
+	var merge = &threeWayMerge{base: base, ours: ours, theirs: theirs, baseUids: modelUids(base), oursUids: modelUids(ours)}
+	var baseFingerprint, oursFingerprint, theirsFingerprint = base.SchemaFingerprint(), ours.SchemaFingerprint(), theirs.SchemaFingerprint()
+	merge.schemaChangedInBoth = oursFingerprint != baseFingerprint && theirsFingerprint != baseFingerprint && oursFingerprint != theirsFingerprint
+	merge.mergeRetired()
+	merge.mergeEntities()
+	merge.mergeModelInfo()
+	if len(merge.conflicts) > 0 {
+		return nil, MergeConflictError{merge.conflicts}
+	}
+
+	merge.reassignIds()
+	if err := ours.Validate(); err != nil {
+		return nil, fmt.Errorf("the merged model is invalid: %s", err)
+	}
+	return merge.notices, nil
+}
+
+type threeWayMerge struct {
+	base, ours, theirs *ModelInfo
+	conflicts          []string
+	notices            []string
+
+	// all UIDs known before merging, i.e. an element is added in theirs if its UID isn't in either of them
+	baseUids, oursUids map[Uid]bool
+
+	// the original last IDs of "ours" entities, their properties are assigned IDs after all the conflicts are known
+	oursLastPropertyIds map[Uid]IdUid
+
+	// whether ours and theirs have both changed the schema since base (differently), see mergeModelInfo()
+	schemaChangedInBoth bool
+}
+
+func (merge *threeWayMerge) conflict(format string, args ...interface{}) {
+	merge.conflicts = append(merge.conflicts, fmt.Sprintf(format, args...))
+}
+
+// mergeRetired adds the UIDs retired in "theirs" to "ours"
+func (merge *threeWayMerge) mergeRetired() {
+	var ours, theirs = merge.ours, merge.theirs
+	for _, lists := range [][2]*[]Uid{
+		{&ours.RetiredEntityUids, &theirs.RetiredEntityUids},
+		{&ours.RetiredIndexUids, &theirs.RetiredIndexUids},
+		{&ours.RetiredPropertyUids, &theirs.RetiredPropertyUids},
+		{&ours.RetiredRelationUids, &theirs.RetiredRelationUids},
+	} {
+		for _, uid := range *lists[1] {
+			if !searchSliceUid(*lists[0], uid) {
+				*lists[0] = append(*lists[0], uid)
+			}
+		}
+	}
+	for uid, version := range theirs.RetiredUidVersions {
+		if _, found := ours.RetiredUidVersions[uid]; !found {
+			if ours.RetiredUidVersions == nil {
+				ours.RetiredUidVersions = make(map[Uid]int)
+			}
+			ours.RetiredUidVersions[uid] = version
+		}
+	}
+}
+
+// mergeModelInfo merges the model-level values; the versions only increase so the higher one wins. If both sides have
+// changed the schema, the merged schema differs from either of them so its version is incremented past both.
+func (merge *threeWayMerge) mergeModelInfo() {
+	var base, ours, theirs = merge.base, merge.ours, merge.theirs
+	for _, values := range [][2]*int{
+		{&ours.Version, &theirs.Version},
+		{&ours.SchemaVersion, &theirs.SchemaVersion},
+		{&ours.ModelVersion, &theirs.ModelVersion},
+		{&ours.MinimumParserVersion, &theirs.MinimumParserVersion},
+	} {
+		if *values[1] > *values[0] {
+			*values[0] = *values[1]
+		}
+	}
+	if merge.schemaChangedInBoth && ours.SchemaVersion > 0 {
+		ours.SchemaVersion++
+	}
+
+	if ours.TenantPrefix == base.TenantPrefix {
+		ours.TenantPrefix = theirs.TenantPrefix
+	} else if theirs.TenantPrefix != base.TenantPrefix && theirs.TenantPrefix != ours.TenantPrefix {
+		merge.conflict("tenant prefix changed to %q in ours and to %q in theirs", ours.TenantPrefix, theirs.TenantPrefix)
+	}
+
+	for name, value := range theirs.GeneratorOptions {
+		var baseValue, inBase = base.GeneratorOptions[name]
+		var oursValue, inOurs = ours.GeneratorOptions[name]
+		if !inOurs || (inBase && oursValue == baseValue) {
+			if ours.GeneratorOptions == nil {
+				ours.GeneratorOptions = make(map[string]string)
+			}
+			ours.GeneratorOptions[name] = value
+		} else if value != oursValue && (!inBase || value != baseValue) {
+			merge.conflict("generator option %s changed to %q in ours and to %q in theirs", name, oursValue, value)
+		}
+	}
+}
+
+func (merge *threeWayMerge) mergeEntities() {
+	var baseEntities, theirsEntities = entitiesByUid(merge.base), entitiesByUid(merge.theirs)
+	merge.oursLastPropertyIds = make(map[Uid]IdUid)
+
+	var entities = make([]*Entity, 0, len(merge.ours.Entities))
+	var names = make(map[string]bool)
+	for _, oursEntity := range merge.ours.Entities {
+		var uid = oursEntity.Id.getUidSafe()
+		var baseEntity, theirsEntity = baseEntities[uid], theirsEntities[uid]
+		merge.oursLastPropertyIds[uid] = oursEntity.LastPropertyId
+
+		if theirsEntity == nil && baseEntity != nil {
+			// removed in theirs
+			if !jsonEqual(oursEntity, baseEntity) {
+				merge.conflict("entity %s was removed in theirs but changed in ours", oursEntity.Name)
+				entities = append(entities, oursEntity)
+			}
+			continue
+		}
+		if theirsEntity != nil {
+			merge.mergeEntity(baseEntity, oursEntity, theirsEntity)
+		}
+		entities = append(entities, oursEntity)
+		names[strings.ToLower(oursEntity.Name)] = true
+	}
+
+	var oursEntities = entitiesByUid(merge.ours)
+	for _, theirsEntity := range merge.theirs.Entities {
+		var uid = theirsEntity.Id.getUidSafe()
+		if oursEntities[uid] != nil {
+			continue
+		}
+		if baseEntity := baseEntities[uid]; baseEntity != nil {
+			// removed in ours
+			if !jsonEqual(theirsEntity, baseEntity) {
+				merge.conflict("entity %s was removed in ours but changed in theirs", theirsEntity.Name)
+			}
+			continue
+		}
+		if names[strings.ToLower(theirsEntity.Name)] {
+			merge.conflict("entity %s was added in both ours and theirs, with different UIDs", theirsEntity.Name)
+			continue
+		}
+		theirsEntity.Model = merge.ours
+		entities = append(entities, theirsEntity)
+		names[strings.ToLower(theirsEntity.Name)] = true
+	}
+	merge.ours.Entities = entities
+}
+
+// mergeEntity merges the changes of an entity present in both ours and theirs; baseEntity may be nil if both added it
+func (merge *threeWayMerge) mergeEntity(baseEntity, oursEntity, theirsEntity *Entity) {
+	if baseEntity == nil {
+		baseEntity = &Entity{Name: oursEntity.Name}
+	}
+
+	// the entity itself, without its ID (e.g. deterministic UIDs added in both) and its properties and relations
+	var header = func(entity *Entity) Entity {
+		return Entity{Name: entity.Name, ExternalName: entity.ExternalName, Flags: entity.Flags, AddedInVersion: entity.AddedInVersion}
+	}
+	switch pick(header(baseEntity), header(oursEntity), header(theirsEntity)) {
+	case pickTheirs:
+		oursEntity.Name = theirsEntity.Name
+		oursEntity.ExternalName = theirsEntity.ExternalName
+		oursEntity.Flags = theirsEntity.Flags
+		oursEntity.AddedInVersion = theirsEntity.AddedInVersion
+	case pickConflict:
+		merge.conflict("entity %s was changed in both ours and theirs (e.g. renamed or its flags)", oursEntity.Name)
+	}
+
+	// properties
+	var baseProperties, theirsProperties = propertiesByUid(baseEntity), propertiesByUid(theirsEntity)
+	var properties = make([]*Property, 0, len(oursEntity.Properties))
+	var names = make(map[string]bool)
+	for _, oursProperty := range oursEntity.Properties {
+		var uid = oursProperty.Id.getUidSafe()
+		var baseProperty, theirsProperty = baseProperties[uid], theirsProperties[uid]
+		if theirsProperty == nil && baseProperty != nil {
+			if !jsonEqual(oursProperty, baseProperty) {
+				merge.conflict("property %s.%s was removed in theirs but changed in ours", oursEntity.Name, oursProperty.Name)
+				properties = append(properties, oursProperty)
+			}
+			continue
+		}
+		if theirsProperty != nil {
+			switch pick(baseProperty, oursProperty, theirsProperty) {
+			case pickTheirs:
+				theirsProperty.Entity = oursEntity
+				oursProperty = theirsProperty
+			case pickConflict:
+				merge.conflict("property %s.%s was changed in both ours and theirs", oursEntity.Name, oursProperty.Name)
+			}
+		}
+		properties = append(properties, oursProperty)
+		names[strings.ToLower(oursProperty.Name)] = true
+	}
+	var oursProperties = propertiesByUid(oursEntity)
+	for _, theirsProperty := range theirsEntity.Properties {
+		var uid = theirsProperty.Id.getUidSafe()
+		if oursProperties[uid] != nil {
+			continue
+		} else if baseProperty := baseProperties[uid]; baseProperty != nil {
+			if !jsonEqual(theirsProperty, baseProperty) {
+				merge.conflict("property %s.%s was removed in ours but changed in theirs", oursEntity.Name, theirsProperty.Name)
+			}
+			continue
+		} else if names[strings.ToLower(theirsProperty.Name)] {
+			merge.conflict("property %s.%s was added in both ours and theirs, with different UIDs", oursEntity.Name, theirsProperty.Name)
+			continue
+		}
+		theirsProperty.Entity = oursEntity
+		properties = append(properties, theirsProperty)
+		names[strings.ToLower(theirsProperty.Name)] = true
+	}
+	oursEntity.Properties = properties
+
+	// standalone relations
+	var baseRelations, theirsRelations = relationsByUid(baseEntity), relationsByUid(theirsEntity)
+	var relations = make([]*StandaloneRelation, 0, len(oursEntity.Relations))
+	names = make(map[string]bool)
+	for _, oursRelation := range oursEntity.Relations {
+		var uid = oursRelation.Id.getUidSafe()
+		var baseRelation, theirsRelation = baseRelations[uid], theirsRelations[uid]
+		if theirsRelation == nil && baseRelation != nil {
+			if !jsonEqual(oursRelation, baseRelation) {
+				merge.conflict("relation %s.%s was removed in theirs but changed in ours", oursEntity.Name, oursRelation.Name)
+				relations = append(relations, oursRelation)
+			}
+			continue
+		}
+		if theirsRelation != nil {
+			switch pick(baseRelation, oursRelation, theirsRelation) {
+			case pickTheirs:
+				oursRelation = theirsRelation
+			case pickConflict:
+				merge.conflict("relation %s.%s was changed in both ours and theirs", oursEntity.Name, oursRelation.Name)
+			}
+		}
+		relations = append(relations, oursRelation)
+		names[strings.ToLower(oursRelation.Name)] = true
+	}
+	var oursRelations = relationsByUid(oursEntity)
+	for _, theirsRelation := range theirsEntity.Relations {
+		var uid = theirsRelation.Id.getUidSafe()
+		if oursRelations[uid] != nil {
+			continue
+		} else if baseRelation := baseRelations[uid]; baseRelation != nil {
+			if !jsonEqual(theirsRelation, baseRelation) {
+				merge.conflict("relation %s.%s was removed in ours but changed in theirs", oursEntity.Name, theirsRelation.Name)
+			}
+			continue
+		} else if names[strings.ToLower(theirsRelation.Name)] {
+			merge.conflict("relation %s.%s was added in both ours and theirs, with different UIDs", oursEntity.Name, theirsRelation.Name)
+			continue
+		}
+		relations = append(relations, theirsRelation)
+		names[strings.ToLower(theirsRelation.Name)] = true
+	}
+	if len(relations) > 0 {
+		oursEntity.Relations = relations
+	} else {
+		oursEntity.Relations = nil
+	}
+}
+
+// reassignIds gives the elements added in theirs new IDs where ours has assigned IDs since base and updates the
+// last IDs of the merged model
+func (merge *threeWayMerge) reassignIds() {
+	var base, ours, theirs = merge.base, merge.ours, merge.theirs
+	var theirsAdded = func(idUid IdUid) bool {
+		var uid = idUid.getUidSafe()
+		return !merge.baseUids[uid] && !merge.oursUids[uid]
+	}
+	var baseEntities, theirsEntities = entitiesByUid(base), entitiesByUid(theirs)
+
+	var entityIds = newIdReassigner(base.LastEntityId, ours.LastEntityId, theirs.LastEntityId)
+	var indexIds = newIdReassigner(base.LastIndexId, ours.LastIndexId, theirs.LastIndexId)
+	var relationIds = newIdReassigner(base.LastRelationId, ours.LastRelationId, theirs.LastRelationId)
+	var entityIdsByUid = make(map[Uid]IdUid)
+
+	for _, entity := range ours.Entities {
+		var entityUid = entity.Id.getUidSafe()
+		if theirsAdded(entity.Id) {
+			if previous, changed := entityIds.reassign(&entity.Id); changed {
+				merge.notices = append(merge.notices, fmt.Sprintf("entity %s: ID changed from %s to %s", entity.Name, previous, entity.Id))
+			}
+		} else if theirsEntity := theirsEntities[entityUid]; theirsEntity != nil {
+			// properties added in both: their IDs are local to the entity
+			var baseLastId IdUid
+			if baseEntity := baseEntities[entityUid]; baseEntity != nil {
+				baseLastId = baseEntity.LastPropertyId
+			}
+			var propertyIds = newIdReassigner(baseLastId, merge.oursLastPropertyIds[entityUid], theirsEntity.LastPropertyId)
+			for _, property := range entity.Properties {
+				if theirsAdded(property.Id) {
+					if previous, changed := propertyIds.reassign(&property.Id); changed {
+						merge.notices = append(merge.notices, fmt.Sprintf("property %s.%s: ID changed from %s to %s", entity.Name, property.Name, previous, property.Id))
+					}
+				}
+			}
+			entity.LastPropertyId = propertyIds.last
+		}
+		entityIdsByUid[entityUid] = entity.Id
+
+		for _, property := range entity.Properties {
+			if property.IndexId != nil && theirsAdded(*property.IndexId) {
+				var indexId = *property.IndexId
+				if previous, changed := indexIds.reassign(&indexId); changed {
+					property.IndexId = &indexId
+					merge.notices = append(merge.notices, fmt.Sprintf("index of property %s.%s: ID changed from %s to %s", entity.Name, property.Name, previous, indexId))
+				}
+			}
+		}
+		for _, relation := range entity.Relations {
+			if theirsAdded(relation.Id) {
+				if previous, changed := relationIds.reassign(&relation.Id); changed {
+					merge.notices = append(merge.notices, fmt.Sprintf("relation %s.%s: ID changed from %s to %s", entity.Name, relation.Name, previous, relation.Id))
+				}
+			}
+		}
+	}
+
+	// relations refer to their targets by ID
+	for _, entity := range ours.Entities {
+		for _, relation := range entity.Relations {
+			if targetId, found := entityIdsByUid[relation.TargetId.getUidSafe()]; found {
+				relation.TargetId = targetId
+			}
+		}
+	}
+
+	ours.LastEntityId = entityIds.last
+	ours.LastIndexId = indexIds.last
+	ours.LastRelationId = relationIds.last
+}
+
+// idReassigner assigns new IDs to the elements added in theirs if ours has added elements since base, i.e. their IDs
+// may collide (even with elements added and retired in ours, which aren't known anymore)
+type idReassigner struct {
+	baseId    Id
+	oursAdded bool
+	last      IdUid
+}
+
+func newIdReassigner(base, ours, theirs IdUid) *idReassigner {
+	var reassigner = &idReassigner{baseId: base.getIdSafe(), oursAdded: ours.getIdSafe() > base.getIdSafe(), last: ours}
+	if theirs.getIdSafe() > ours.getIdSafe() {
+		reassigner.last = theirs
+	}
+	return reassigner
+}
+
+// reassign gives the element added in theirs a new ID if needed, returning the previous one
+func (reassigner *idReassigner) reassign(idUid *IdUid) (IdUid, bool) {
+	var previous = *idUid
+	if !reassigner.oursAdded || idUid.getIdSafe() <= reassigner.baseId {
+		return previous, false
+	}
+	*idUid = CreateIdUid(reassigner.last.getIdSafe()+1, idUid.getUidSafe())
+	reassigner.last = *idUid
+	return previous, true
+}
+
+const (
+	pickOurs = iota
+	pickTheirs
+	pickConflict
+)
+
+// pick decides which side of a three-way merge to take by comparing the JSON representation of the elements
+func pick(base, ours, theirs interface{}) int {
+	if jsonEqual(ours, theirs) || jsonEqual(base, theirs) {
+		return pickOurs
+	} else if jsonEqual(base, ours) {
+		return pickTheirs
+	}
+	return pickConflict
+}
+
+func jsonEqual(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(dataA) == string(dataB)
+}
+
+func entitiesByUid(model *ModelInfo) map[Uid]*Entity {
+	var result = make(map[Uid]*Entity)
+	for _, entity := range model.Entities {
+		result[entity.Id.getUidSafe()] = entity
+	}
+	return result
+}
+
+func propertiesByUid(entity *Entity) map[Uid]*Property {
+	var result = make(map[Uid]*Property)
+	for _, property := range entity.Properties {
+		result[property.Id.getUidSafe()] = property
+	}
+	return result
+}
+
+func relationsByUid(entity *Entity) map[Uid]*StandaloneRelation {
+	var result = make(map[Uid]*StandaloneRelation)
+	for _, relation := range entity.Relations {
+		result[relation.Id.getUidSafe()] = relation
+	}
+	return result
+}
+
+// modelUids returns the UIDs of all entities, properties, indexes and relations of the model, including retired ones
+func modelUids(model *ModelInfo) map[Uid]bool {
+	var result = make(map[Uid]bool)
+	for _, list := range [][]Uid{model.RetiredEntityUids, model.RetiredIndexUids, model.RetiredPropertyUids, model.RetiredRelationUids} {
+		for _, uid := range list {
+			result[uid] = true
+		}
+	}
+	for _, entity := range model.Entities {
+		result[entity.Id.getUidSafe()] = true
+		for _, property := range entity.Properties {
+			result[property.Id.getUidSafe()] = true
+			if property.IndexId != nil {
+				result[property.IndexId.getUidSafe()] = true
+			}
+		}
+		for _, relation := range entity.Relations {
+			result[relation.Id.getUidSafe()] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+)
+
+// GenerateUidFor generates a unique UID for the model element identified by the given key, see uidKey().
+// With DeterministicUids, the UID is derived from the key (and UidSalt) so it's the same on each (clean) generation,
+// otherwise it's random.
+func (model *ModelInfo) GenerateUidFor(key string) (Uid, error) {
+	if !model.DeterministicUids {
+		return model.GenerateUid()
+	}
+
+	// names are case-insensitive in the model so the key is as well
+	var hash = sha256.Sum256([]byte(model.UidSalt + "\x00" + strings.ToLower(key)))
+	var candidate = Uid(binary.BigEndian.Uint64(hash[:8]) & math.MaxInt64)
+	if candidate == 0 || model.containsUid(candidate) {
+		return 0, messages.DeterministicUid.Errorf(candidate, key)
+	}
+	return candidate, nil
+}
+
+// TenantUid derives the UID of a tenant's variant of a model element from the UID it has in the (logical) schema,
+// e.g. pinned using the uid annotation, so that variants for different tenants don't share UIDs
+func TenantUid(tenantPrefix string, uid Uid) Uid {
+	var hash = sha256.Sum256([]byte(fmt.Sprintf("tenant %s\x00%d", tenantPrefix, uid)))
+	var result = Uid(binary.BigEndian.Uint64(hash[:8]) & math.MaxInt64)
+	if result == 0 {
+		return uid
+	}
+	return result
+}
+
+// uidKey identifies a model element for deterministic UID generation, e.g. uidKey("property", "Task", "text")
+func uidKey(kind string, names ...string) string {
+	return kind + " " + strings.Join(names, ".")
+}
+
+// checkPinnedUid verifies a UID given by the user (uid annotation) can be used for a new model element
+func (model *ModelInfo) checkPinnedUid(uid Uid) error {
+	if uid == 0 {
+		return fmt.Errorf("invalid UID 0")
+	}
+	if model.Reserved.hasUid(uid) {
+		return fmt.Errorf("UID %d is reserved", uid)
+	}
+	if model.containsUid(uid) {
+		return fmt.Errorf("UID %d is already used in the model", uid)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+)
+
+// Issue is a problem found by Validate(), e.g. a hash index on a number property
+type Issue struct {
+	Entity   string // name of the entity
+	Property string // name of the property or the standalone relation; empty for issues of the entity itself
+	Err      error  // describes the problem, may be a cataloged message, see messages.Code()
+}
+
+func (issue Issue) Error() string {
+	if len(issue.Property) == 0 {
+		return fmt.Sprintf("entity %s: %s", issue.Entity, issue.Err)
+	}
+	return fmt.Sprintf("entity %s property %s: %s", issue.Entity, issue.Property, issue.Err)
+}
+
+// Validate checks the combinations of the entity and property flags, the types they're set on, relation targets and
+// HNSW parameters of all the entities, returning all the issues found instead of stopping at the first one.
+// The flags are checked by the generator for all frontends the same way (see ModelInfo.Finalize()), so tools building
+// a model programmatically (see EntityBuilder) can report the problems before generating. Relation targets are only
+// looked up among the given entities here, the generator resolves them across all sources and module models.
+// IDs and UIDs aren't checked as they may not be assigned yet, ModelInfo.Validate() does that for a loaded model.
+func Validate(model *ModelInfo) []Issue {
+	var issues []Issue
+	for _, entity := range model.Entities {
+		for _, err := range entity.checkFlags() {
+			issues = append(issues, Issue{Entity: entity.Name, Err: err})
+		}
+
+		for _, property := range entity.Properties {
+			for _, err := range property.checkFlags() {
+				issues = append(issues, Issue{Entity: entity.Name, Property: property.Name, Err: err})
+			}
+			if len(property.RelationTarget) > 0 {
+				if _, err := model.FindEntityByName(property.RelationTarget); err != nil {
+					issues = append(issues, Issue{Entity: entity.Name, Property: property.Name,
+						Err: fmt.Errorf("relation target entity %s not found", property.RelationTarget)})
+				}
+			}
+		}
+
+		for _, relation := range entity.Relations {
+			if err := relation.validateExternalType(); err != nil {
+				issues = append(issues, Issue{Entity: entity.Name, Property: relation.Name, Err: err})
+			}
+			if relation.Target == nil || len(relation.Target.Name) == 0 {
+				issues = append(issues, Issue{Entity: entity.Name, Property: relation.Name, Err: errors.New("relation target entity is undefined")})
+			} else if _, err := model.FindEntityByName(relation.Target.Name); err != nil {
+				issues = append(issues, Issue{Entity: entity.Name, Property: relation.Name,
+					Err: fmt.Errorf("relation target entity %s not found", relation.Target.Name)})
+			}
+		}
+	}
+	return issues
+}
+
+// checkFlags returns the problems of the entity flags and of the property flags which may only be set on a single
+// property of the entity, e.g. multiple IDs
+func (entity *Entity) checkFlags() []error {
+	var errs []error
+	if entity.Flags&EntityFlagSharedGlobalIds != 0 && entity.Flags&EntityFlagSyncEnabled == 0 {
+		errs = append(errs, fmt.Errorf("flag SharedGlobalIds is only valid for sync-enabled entities"))
+	}
+
+	var idProp *Property
+	for _, property := range entity.Properties {
+		if property.IsIdProperty() {
+			if idProp != nil {
+				errs = append(errs, messages.MultipleIdProperties.Errorf(idProp.Name, idProp.Id, property.Name, property.Id))
+			}
+			idProp = property
+		}
+	}
+
+	if err := entity.CheckUniqueOnConflictReplace(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := entity.checkExternalId(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// indexFlags are the flags selecting the index type, at most one may be set
+var indexFlags = []PropertyFlags{PropertyFlagIndexed, PropertyFlagIndexHash, PropertyFlagIndexHash64}
+
+// checkFlags returns the problems of the property flags (and the settings depending on the type) given its type,
+// e.g. a hash index on a number. Type-dependent checks are skipped while the type isn't known yet.
+func (property *Property) checkFlags() []error {
+	var errs []error
+	var typeName = PropertyTypeNames[property.Type]
+
+	if property.IsIdProperty() {
+		// IDs must not be tagged unsigned for compatibility reasons
+		if !property.hasValidTypeAsId(nil) {
+			errs = append(errs, fmt.Errorf("invalid type on property marked as ID: %d", property.Type))
+		}
+	} else {
+		for _, flag := range []PropertyFlags{PropertyFlagIdSelfAssignable, PropertyFlagIdMonotonicSequence} {
+			if property.Flags&flag != 0 {
+				errs = append(errs, fmt.Errorf("flag %s is only valid on the ID property", PropertyFlagNames[flag]))
+			}
+		}
+	}
+
+	var indexFlagNames []string
+	for _, flag := range indexFlags {
+		if property.Flags&flag != 0 {
+			indexFlagNames = append(indexFlagNames, PropertyFlagNames[flag])
+		}
+	}
+	if len(indexFlagNames) > 1 {
+		errs = append(errs, fmt.Errorf("only one index type can be set, found flags %v", indexFlagNames))
+	}
+
+	// the type is only known after reading the source, see the note in Property.Validate()
+	if property.Type != 0 {
+		if property.Flags&PropertyFlagIdCompanion != 0 && property.Type != PropertyTypeDate && property.Type != PropertyTypeDateNano {
+			errs = append(errs, fmt.Errorf("flag IdCompanion is only valid on Date and DateNano properties, found %s", typeName))
+		}
+
+		if property.Flags&(PropertyFlagIndexHash|PropertyFlagIndexHash64) != 0 && property.Type != PropertyTypeString {
+			errs = append(errs, fmt.Errorf("hash indexes are only supported for strings, found %s", typeName))
+		}
+
+		if property.Flags&PropertyFlagIndexed != 0 {
+			switch property.Type {
+			case PropertyTypeFloatVector:
+				if property.HnswParams == nil {
+					errs = append(errs, fmt.Errorf("float vectors can only be indexed using HNSW parameters"))
+				}
+			case PropertyTypeByteVector, PropertyTypeStringVector:
+				errs = append(errs, fmt.Errorf("index isn't supported for %s properties", typeName))
+			}
+		}
+
+		if property.HnswParams != nil {
+			if property.Type != PropertyTypeFloatVector {
+				errs = append(errs, fmt.Errorf("HNSW parameters are only supported for float vectors, found %s", typeName))
+			} else if dimensions := property.HnswParams.Dimensions; dimensions != nil && property.ArrayLength > 0 && *dimensions != uint64(property.ArrayLength) {
+				errs = append(errs, fmt.Errorf("HNSW dimensions %d don't match the array length %d", *dimensions, property.ArrayLength))
+			}
+		}
+
+		if property.ArrayLength > 0 && property.Type != PropertyTypeFloatVector {
+			errs = append(errs, fmt.Errorf("fixed-length arrays are only supported for float vectors, found %s", typeName))
+		}
+
+		if property.Type == PropertyTypeRelation && len(property.RelationTarget) == 0 {
+			errs = append(errs, fmt.Errorf("relation target entity is undefined"))
+		} else if property.Type != PropertyTypeRelation && len(property.RelationTarget) > 0 {
+			errs = append(errs, fmt.Errorf("relation target %s is only valid on relation properties, found %s", property.RelationTarget, typeName))
+		}
+
+		if err := property.validateExternalType(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if property.ExternalId {
+		if err := property.validateExternalId(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(property.StorageType) != 0 {
+		if property.StorageType != StorageTypeFloat16 {
+			errs = append(errs, fmt.Errorf("unknown storage type %s", property.StorageType))
+		} else if property.Type != 0 && property.Type != PropertyTypeFloatVector {
+			errs = append(errs, fmt.Errorf("storage-type is only supported for float vectors, found %s", typeName))
+		}
+	}
+	return errs
+}
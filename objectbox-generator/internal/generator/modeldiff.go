@@ -0,0 +1,176 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// ModelChange describes a single difference between two models, see DiffModels()
+type ModelChange struct {
+	Action  string    `json:"action"`            // one of "add", "remove", "rename", "change"
+	Kind    string    `json:"kind"`              // one of "entity", "property", "index", "relation"
+	Name    string    `json:"name"`              // e.g. "Task" or "Task.text"
+	OldName string    `json:"oldName,omitempty"` // set for "rename"
+	Detail  string    `json:"detail,omitempty"`  // set for "change", e.g. "type Int -> Long"
+	Uid     model.Uid `json:"uid"`
+}
+
+func (change ModelChange) String() string {
+	var text = fmt.Sprintf("%s %s %s", change.Action, change.Kind, change.Name)
+	if len(change.OldName) > 0 {
+		text += " (previously " + change.OldName + ")"
+	}
+	if len(change.Detail) > 0 {
+		text += ": " + change.Detail
+	}
+	return text
+}
+
+// DiffModels lists changes between the stored model and the current one, e.g. as returned by Validate().
+// Model elements are matched by their UIDs, so a changed UID is reported as a removal and an addition.
+func DiffModels(stored, current *model.ModelInfo) []ModelChange {
+	var changes []ModelChange
+
+	var storedEntities = make(map[model.Uid]*model.Entity)
+	for _, entity := range stored.Entities {
+		storedEntities[uidOf(entity.Id)] = entity
+	}
+
+	var currentEntities = make(map[model.Uid]bool)
+	for _, entity := range current.Entities {
+		var uid = uidOf(entity.Id)
+		currentEntities[uid] = true
+
+		if storedEntity := storedEntities[uid]; storedEntity == nil {
+			changes = append(changes, ModelChange{Action: "add", Kind: "entity", Name: entity.Name, Uid: uid})
+			changes = append(changes, diffEntities(&model.Entity{Name: entity.Name}, entity)...)
+		} else {
+			if storedEntity.Name != entity.Name {
+				changes = append(changes, ModelChange{Action: "rename", Kind: "entity", Name: entity.Name, OldName: storedEntity.Name, Uid: uid})
+			}
+			changes = append(changes, diffEntities(storedEntity, entity)...)
+		}
+	}
+
+	for _, entity := range stored.Entities {
+		if uid := uidOf(entity.Id); !currentEntities[uid] {
+			changes = append(changes, ModelChange{Action: "remove", Kind: "entity", Name: entity.Name, Uid: uid})
+		}
+	}
+
+	return changes
+}
+
+func diffEntities(stored, current *model.Entity) []ModelChange {
+	var changes []ModelChange
+	var prefix = current.Name + "."
+
+	var storedProperties = make(map[model.Uid]*model.Property)
+	for _, property := range stored.Properties {
+		storedProperties[uidOf(property.Id)] = property
+	}
+
+	var currentProperties = make(map[model.Uid]bool)
+	for _, property := range current.Properties {
+		var uid = uidOf(property.Id)
+		currentProperties[uid] = true
+
+		var storedProperty = storedProperties[uid]
+		if storedProperty == nil {
+			changes = append(changes, ModelChange{Action: "add", Kind: "property", Name: prefix + property.Name, Uid: uid})
+			storedProperty = &model.Property{Type: property.Type, Encrypted: property.Encrypted, StorageType: property.StorageType}
+		} else if storedProperty.Name != property.Name {
+			changes = append(changes, ModelChange{Action: "rename", Kind: "property", Name: prefix + property.Name, OldName: prefix + storedProperty.Name, Uid: uid})
+		}
+
+		if storedProperty.Type != property.Type {
+			changes = append(changes, ModelChange{Action: "change", Kind: "property", Name: prefix + property.Name, Uid: uid,
+				Detail: fmt.Sprintf("type %s -> %s", model.PropertyTypeNames[storedProperty.Type], model.PropertyTypeNames[property.Type])})
+		}
+
+		if storedProperty.Encrypted != property.Encrypted {
+			var detail = "encryption added"
+			if !property.Encrypted {
+				detail = "encryption removed"
+			}
+			changes = append(changes, ModelChange{Action: "change", Kind: "property", Name: prefix + property.Name, Uid: uid, Detail: detail})
+		}
+
+		if storedProperty.StorageType != property.StorageType {
+			changes = append(changes, ModelChange{Action: "change", Kind: "property", Name: prefix + property.Name, Uid: uid,
+				Detail: fmt.Sprintf("storage type %s -> %s", storageTypeName(storedProperty), storageTypeName(property))})
+		}
+
+		if storedProperty.IndexId == nil && property.IndexId != nil {
+			changes = append(changes, ModelChange{Action: "add", Kind: "index", Name: prefix + property.Name, Uid: uidOf(*property.IndexId)})
+		} else if storedProperty.IndexId != nil && property.IndexId == nil {
+			changes = append(changes, ModelChange{Action: "remove", Kind: "index", Name: prefix + property.Name, Uid: uidOf(*storedProperty.IndexId)})
+		}
+	}
+
+	for _, property := range stored.Properties {
+		if uid := uidOf(property.Id); !currentProperties[uid] {
+			changes = append(changes, ModelChange{Action: "remove", Kind: "property", Name: prefix + property.Name, Uid: uid})
+		}
+	}
+
+	var storedRelations = make(map[model.Uid]*model.StandaloneRelation)
+	for _, relation := range stored.Relations {
+		storedRelations[uidOf(relation.Id)] = relation
+	}
+
+	var currentRelations = make(map[model.Uid]bool)
+	for _, relation := range current.Relations {
+		var uid = uidOf(relation.Id)
+		currentRelations[uid] = true
+
+		if storedRelation := storedRelations[uid]; storedRelation == nil {
+			changes = append(changes, ModelChange{Action: "add", Kind: "relation", Name: prefix + relation.Name, Uid: uid})
+		} else if storedRelation.Name != relation.Name {
+			changes = append(changes, ModelChange{Action: "rename", Kind: "relation", Name: prefix + relation.Name, OldName: prefix + storedRelation.Name, Uid: uid})
+		}
+	}
+
+	for _, relation := range stored.Relations {
+		if uid := uidOf(relation.Id); !currentRelations[uid] {
+			changes = append(changes, ModelChange{Action: "remove", Kind: "relation", Name: prefix + relation.Name, Uid: uid})
+		}
+	}
+
+	return changes
+}
+
+// uidOf returns the UID of a model element; the models have been validated so we can ignore the error
+func uidOf(idUid model.IdUid) model.Uid {
+	uid, _ := idUid.GetUidAllowZero()
+	return uid
+}
+
+// storageTypeName returns the storage type of a float vector property, the default one isn't recorded in the model
+func storageTypeName(property *model.Property) string {
+	if len(property.StorageType) == 0 {
+		return model.StorageTypeFloat32
+	}
+	return property.StorageType
+}
@@ -0,0 +1,167 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestValidateAndModelDiff(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "validate")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var modelFile = generator.ModelInfoFile(dir)
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		ModelInfoFile: modelFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// validation doesn't write anything, not even the model JSON file
+	currentModel, err := generator.Validate(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(currentModel.Entities))
+	_, err = os.Stat(modelFile)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoErr(t, generator.Process(options))
+	modelJSON, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n done: bool;\n}\n")
+
+	storedModel, err := model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	currentModel, err = generator.Validate(options)
+	assert.NoErr(t, err)
+
+	var changes []string
+	for _, change := range generator.DiffModels(storedModel, currentModel) {
+		changes = append(changes, change.String())
+	}
+	assert.Eq(t, "add property Task.done; remove property Task.text", strings.Join(changes, "; "))
+
+	modelJSONAfter, err := ioutil.ReadFile(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(modelJSON), string(modelJSONAfter))
+}
+
+// encryption changes are reported, e.g. to be audited, because the stored values aren't converted
+func TestEncryptedModelDiff(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "encrypted")
+	defer remove()
+
+	var sourceFile = filepath.Join(dir, "task.go")
+	var modelFile = generator.ModelInfoFile(dir)
+	var options = generator.Options{
+		InPath:        sourceFile,
+		ModelInfoFile: modelFile,
+		CodeGenerator: &gogenerator.GoGenerator{},
+	}
+	var writeSource = func(tag string) {
+		var source = "package object\n\ntype Task struct {\n\tId uint64\n\tText string " + tag + "\n}\n"
+		assert.NoErr(t, ioutil.WriteFile(sourceFile, []byte(source), 0600))
+	}
+	var diff = func() string {
+		storedModel, err := model.LoadModelReadOnly(modelFile)
+		assert.NoErr(t, err)
+		currentModel, err := generator.Validate(options)
+		assert.NoErr(t, err)
+
+		var changes []string
+		for _, change := range generator.DiffModels(storedModel, currentModel) {
+			changes = append(changes, change.String())
+		}
+		return strings.Join(changes, "; ")
+	}
+
+	writeSource("")
+	assert.NoErr(t, generator.Process(options))
+
+	writeSource("`objectbox:\"encrypted\"`")
+	assert.Eq(t, "change property Task.Text: type String -> ByteVector; change property Task.Text: encryption added", diff())
+	assert.NoErr(t, generator.Process(options))
+	storedModel, err := model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	assert.True(t, storedModel.Entities[0].Properties[1].Encrypted)
+
+	writeSource("`objectbox:\"encrypted:vault\"`")
+	assert.Eq(t, "", diff())
+
+	writeSource("`objectbox:\"type:[]byte converter:plain\"`")
+	assert.Eq(t, "change property Task.Text: encryption removed", diff())
+}
+
+func TestFloat16ModelDiff(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "float16")
+	defer remove()
+
+	var sourceFile = filepath.Join(dir, "document.go")
+	var modelFile = generator.ModelInfoFile(dir)
+	var options = generator.Options{
+		InPath:        sourceFile,
+		ModelInfoFile: modelFile,
+		CodeGenerator: &gogenerator.GoGenerator{},
+	}
+	var writeSource = func(tag string) {
+		var source = "package object\n\ntype Document struct {\n\tId uint64\n\tEmbedding []float32 " + tag + "\n}\n"
+		assert.NoErr(t, ioutil.WriteFile(sourceFile, []byte(source), 0600))
+	}
+	var diff = func() string {
+		storedModel, err := model.LoadModelReadOnly(modelFile)
+		assert.NoErr(t, err)
+		currentModel, err := generator.Validate(options)
+		assert.NoErr(t, err)
+
+		var changes []string
+		for _, change := range generator.DiffModels(storedModel, currentModel) {
+			changes = append(changes, change.String())
+		}
+		return strings.Join(changes, "; ")
+	}
+
+	writeSource("")
+	assert.NoErr(t, generator.Process(options))
+
+	writeSource("`objectbox:\"storage-type:float16\"`")
+	assert.Eq(t, "change property Document.Embedding: storage type float32 -> float16", diff())
+	assert.NoErr(t, generator.Process(options))
+	storedModel, err := model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, model.StorageTypeFloat16, storedModel.Entities[0].Properties[1].StorageType)
+
+	writeSource("`objectbox:\"storage-type:float32\"`")
+	assert.Eq(t, "change property Document.Embedding: storage type float16 -> float32", diff())
+}
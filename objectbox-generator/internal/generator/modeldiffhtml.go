@@ -0,0 +1,215 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Statuses of the entities and their members in the HTML report, see WriteModelDiffHTML()
+const (
+	diffStatusAdded     = "added"
+	diffStatusRemoved   = "removed"
+	diffStatusChanged   = "changed"
+	diffStatusUnchanged = "unchanged"
+)
+
+// diffReportEntity is an entity of either of the models with its members and their changes
+type diffReportEntity struct {
+	Name    string
+	OldName string
+	Status  string
+	Members []diffReportMember
+}
+
+// diffReportMember is a property or a standalone relation
+type diffReportMember struct {
+	Kind    string // "property" or "relation"
+	Name    string
+	Type    string // the property type or the relation target
+	OldName string
+	Status  string
+	Details []string // e.g. "type Int -> Long", "index added"
+}
+
+// WriteModelDiffHTML writes a self-contained HTML page showing the changes between the stored model and the current
+// one (see DiffModels()): all entities with their properties and relations, colored by whether they were added,
+// removed or changed, e.g. to be published as a CI artifact and reviewed by people not reading JSON.
+func WriteModelDiffHTML(w io.Writer, stored, current *model.ModelInfo) error {
+	var changes = DiffModels(stored, current)
+	var report = struct {
+		Entities []diffReportEntity
+		Summary  map[string]int
+		Changes  int
+	}{createDiffReport(stored, current, changes), make(map[string]int), len(changes)}
+
+	for _, change := range changes {
+		report.Summary[change.Action]++
+	}
+	return modelDiffHTMLTemplate.Execute(w, report)
+}
+
+func createDiffReport(stored, current *model.ModelInfo, changes []ModelChange) []diffReportEntity {
+	// changes by the "Entity.member" name they refer to (current names, except for removed members)
+	var memberChanges = make(map[string][]ModelChange)
+	var entityChanges = make(map[string]ModelChange)
+	for _, change := range changes {
+		if change.Kind == "entity" {
+			entityChanges[change.Name] = change
+		} else {
+			var key = change.Kind + " " + change.Name
+			if change.Kind == "index" {
+				key = "property " + change.Name
+			}
+			memberChanges[key] = append(memberChanges[key], change)
+		}
+	}
+
+	var entities []diffReportEntity
+	for _, entity := range current.Entities {
+		var reportEntity = diffReportEntity{Name: entity.Name, Status: diffStatusUnchanged}
+		if change, found := entityChanges[entity.Name]; found {
+			if change.Action == "add" {
+				reportEntity.Status = diffStatusAdded
+			} else {
+				reportEntity.Status = diffStatusChanged
+				reportEntity.OldName = change.OldName
+			}
+		}
+
+		for _, property := range entity.Properties {
+			reportEntity.addMember("property", property.Name, model.PropertyTypeNames[property.Type], memberChanges)
+		}
+		for _, relation := range entity.Relations {
+			var target string
+			if relation.Target != nil {
+				target = "-> " + relation.Target.Name
+			}
+			reportEntity.addMember("relation", relation.Name, target, memberChanges)
+		}
+
+		// members missing in the current model
+		for _, change := range changes {
+			if change.Action == "remove" && change.Kind != "entity" && change.Kind != "index" &&
+				strings.HasPrefix(change.Name, entity.Name+".") {
+				reportEntity.Members = append(reportEntity.Members, diffReportMember{
+					Kind:   change.Kind,
+					Name:   strings.TrimPrefix(change.Name, entity.Name+"."),
+					Status: diffStatusRemoved,
+				})
+			}
+		}
+
+		if reportEntity.Status == diffStatusUnchanged {
+			for _, member := range reportEntity.Members {
+				if member.Status != diffStatusUnchanged {
+					reportEntity.Status = diffStatusChanged
+					break
+				}
+			}
+		}
+		entities = append(entities, reportEntity)
+	}
+
+	for _, entity := range stored.Entities {
+		if change, found := entityChanges[entity.Name]; !found || change.Action != "remove" {
+			continue
+		}
+		var reportEntity = diffReportEntity{Name: entity.Name, Status: diffStatusRemoved}
+		for _, property := range entity.Properties {
+			reportEntity.Members = append(reportEntity.Members, diffReportMember{Kind: "property", Name: property.Name,
+				Type: model.PropertyTypeNames[property.Type], Status: diffStatusRemoved})
+		}
+		for _, relation := range entity.Relations {
+			reportEntity.Members = append(reportEntity.Members, diffReportMember{Kind: "relation", Name: relation.Name,
+				Status: diffStatusRemoved})
+		}
+		entities = append(entities, reportEntity)
+	}
+
+	return entities
+}
+
+func (entity *diffReportEntity) addMember(kind, name, typ string, memberChanges map[string][]ModelChange) {
+	var member = diffReportMember{Kind: kind, Name: name, Type: typ, Status: diffStatusUnchanged}
+	for _, change := range memberChanges[kind+" "+entity.Name+"."+name] {
+		switch {
+		case change.Kind == "index" && change.Action == "add":
+			member.Details = append(member.Details, "index added")
+		case change.Kind == "index":
+			member.Details = append(member.Details, "index removed")
+		case change.Action == "add":
+			member.Status = diffStatusAdded
+		case change.Action == "rename":
+			member.OldName = strings.TrimPrefix(change.OldName, entity.Name+".")
+		case change.Action == "change":
+			member.Details = append(member.Details, change.Detail)
+		default:
+			continue // removals are listed separately, the member isn't in the current model
+		}
+	}
+	if member.Status == diffStatusUnchanged && (len(member.OldName) > 0 || len(member.Details) > 0) {
+		member.Status = diffStatusChanged
+	}
+	entity.Members = append(entity.Members, member)
+}
+
+var modelDiffHTMLTemplate = template.Must(template.New("model-diff").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ObjectBox model changes</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+.entity { border: 1px solid #ccc; border-radius: 4px; margin: 1em 0; padding: 0.5em 1em; }
+.entity h2 { margin: 0.3em 0; font-size: 1.2em; }
+table { border-collapse: collapse; }
+td { padding: 0.2em 1em 0.2em 0; }
+.old { color: #777; font-size: 0.9em; }
+.added { background: #e6ffed; border-color: #34d058; }
+.removed { background: #ffeef0; border-color: #d73a49; }
+.removed td.name, .removed h2 { text-decoration: line-through; }
+.changed { background: #fff8c5; border-color: #dbab09; }
+.entity.unchanged { color: #777; }
+.legend span { padding: 0.2em 0.5em; margin-right: 0.5em; border-radius: 4px; }
+</style>
+</head>
+<body>
+<h1>ObjectBox model changes</h1>
+<p>{{if .Changes}}{{.Changes}} change(s): {{range $action, $count := .Summary}}{{$count}} {{$action}} {{end}}{{else}}No changes to the model{{end}}</p>
+<p class="legend"><span class="added">added</span><span class="removed">removed</span><span class="changed">changed</span></p>
+{{- range $entity := .Entities}}
+<div class="entity {{$entity.Status}}">
+<h2>{{$entity.Name}}{{with $entity.OldName}} <span class="old">(previously {{.}})</span>{{end}}</h2>
+<table>
+{{- range $member := $entity.Members}}
+<tr class="{{$member.Status}}"><td>{{$member.Kind}}</td><td class="name">{{$member.Name}}{{with $member.OldName}} <span class="old">(previously {{.}})</span>{{end}}</td><td>{{$member.Type}}</td><td>{{range $i, $detail := $member.Details}}{{if $i}}, {{end}}{{$detail}}{{end}}</td></tr>
+{{- end}}
+</table>
+</div>
+{{- end}}
+</body>
+</html>
+`))
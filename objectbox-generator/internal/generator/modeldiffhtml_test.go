@@ -0,0 +1,74 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestModelDiffHTML(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "model-diff-html")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var modelFile = generator.ModelInfoFile(dir)
+	var options = generator.Options{
+		InPath:        dir, // entities are only removed from the model when processing a directory
+		ModelInfoFile: modelFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n text: string;\n count: int;\n}\n"+
+		"table Note {\n id: ulong;\n}\ntable Label {\n id: ulong;\n}\n"), 0600))
+	assert.NoErr(t, generator.Process(options))
+
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n done: bool;\n count: long;\n}\n"+
+		"table Project {\n id: ulong;\n}\ntable Label {\n id: ulong;\n}\n"), 0600))
+	storedModel, err := model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	currentModel, err := generator.Validate(options)
+	assert.NoErr(t, err)
+
+	var b bytes.Buffer
+	assert.NoErr(t, generator.WriteModelDiffHTML(&b, storedModel, currentModel))
+	var html = b.String()
+	for _, expected := range []string{
+		`<div class="entity changed">` + "\n<h2>Task</h2>",
+		`<tr class="added"><td>property</td><td class="name">done</td><td>Bool</td>`,
+		`<tr class="changed"><td>property</td><td class="name">count</td><td>Long</td><td>type Int -&gt; Long</td></tr>`,
+		`<tr class="removed"><td>property</td><td class="name">text</td>`,
+		`<div class="entity added">` + "\n<h2>Project</h2>",
+		`<div class="entity unchanged">` + "\n<h2>Label</h2>",
+		`<div class="entity removed">` + "\n<h2>Note</h2>",
+	} {
+		assert.True(t, strings.Contains(html, expected))
+	}
+}
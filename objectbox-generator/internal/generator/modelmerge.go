@@ -0,0 +1,83 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// MergeModelFiles merges the changes made to the model JSON file baseFile in theirsFile into oursFile, i.e. the
+// arguments of a git merge driver (%O %A %B), see model.MergeThreeWay(). The merged model is written to oursFile in the
+// canonical format; nothing is written if the models conflict. An empty baseFile stands for a model added on both sides.
+// Returns notices about the IDs reassigned to elements added in theirsFile, whose bindings need to be regenerated.
+func MergeModelFiles(baseFile, oursFile, theirsFile string) ([]string, error) {
+	base, err := loadMergedModel(baseFile, true)
+	if err != nil {
+		return nil, err
+	}
+	ours, err := loadMergedModel(oursFile, false)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := loadMergedModel(theirsFile, false)
+	if err != nil {
+		return nil, err
+	}
+
+	notices, err := model.MergeThreeWay(base, ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	ours.ExternalMapping = ours.CreateExternalMapping()
+	data, err := ours.MarshalCanonical()
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(oursFile, data, 0644); err != nil {
+		return nil, fmt.Errorf("can't write model file %s: %s", oursFile, err)
+	}
+	return notices, nil
+}
+
+// loadMergedModel reads one of the model files given to MergeModelFiles()
+func loadMergedModel(file string, allowEmpty bool) (*model.ModelInfo, error) {
+	if allowEmpty {
+		if data, err := ioutil.ReadFile(file); err != nil {
+			return nil, err
+		} else if len(bytes.TrimSpace(data)) == 0 {
+			return &model.ModelInfo{}, nil
+		}
+	}
+
+	modelInfo, err := model.LoadModelReadOnly(file)
+	if err == nil {
+		err = modelInfo.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read model file %s: %s", file, err)
+	}
+	return modelInfo, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestModelMerge(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "model-merge")
+	defer remove()
+
+	// generates the schema in a copy of the base model, returning the model file
+	var generate = func(name string, schema string, baseModelFile string) string {
+		var subDir = filepath.Join(dir, name)
+		assert.NoErr(t, os.Mkdir(subDir, 0755))
+		if len(baseModelFile) > 0 {
+			data, err := ioutil.ReadFile(baseModelFile)
+			assert.NoErr(t, err)
+			assert.NoErr(t, ioutil.WriteFile(generator.ModelInfoFile(subDir), data, 0600))
+		}
+		var schemaFile = filepath.Join(subDir, "schema.fbs")
+		assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(schema), 0600))
+		fixture.Generate(t, schemaFile, &cgenerator.CGenerator{LangVersion: 11})
+		return generator.ModelInfoFile(subDir)
+	}
+
+	var base = generate("base", "table Task {\n id: ulong;\n text: string;\n}\n", "")
+	var ours = generate("ours", "table Task {\n id: ulong;\n /// objectbox:index\n text: string;\n prio: int;\n}\n"+
+		"table Note {\n id: ulong;\n}\n", base)
+	var theirs = generate("theirs", "table Task {\n id: ulong;\n text: string;\n /// objectbox:index\n due: long;\n}\n"+
+		"table Tag {\n id: ulong;\n}\n", base)
+	theirsModel, err := model.LoadModelReadOnly(theirs)
+	assert.NoErr(t, err)
+	baseModel, err := model.LoadModelReadOnly(base)
+	assert.NoErr(t, err)
+	assert.Eq(t, baseModel.SchemaVersion+1, theirsModel.SchemaVersion)
+
+	// the elements added in theirs get new IDs as ours has added some too, UIDs are kept
+	notices, err := generator.MergeModelFiles(base, ours, theirs)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(notices))
+	assert.True(t, strings.HasPrefix(notices[0], "property Task.due: ID changed from 3:"))
+	assert.True(t, strings.HasPrefix(notices[1], "index of property Task.due: ID changed from 1:"))
+	assert.True(t, strings.HasPrefix(notices[2], "entity Tag: ID changed from 2:"))
+
+	merged, err := model.LoadModelReadOnly(ours)
+	assert.NoErr(t, err)
+	assert.NoErr(t, merged.Validate())
+	assert.Eq(t, 3, len(merged.Entities))
+	tag, err := merged.FindEntityByName("Tag")
+	assert.NoErr(t, err)
+	tagId, tagUid, err := tag.Id.Get()
+	assert.NoErr(t, err)
+	assert.Eq(t, model.Id(3), tagId)
+	theirsTag, err := theirsModel.FindEntityByName("Tag")
+	assert.NoErr(t, err)
+	theirsTagUid, err := theirsTag.Id.GetUid()
+	assert.NoErr(t, err)
+	assert.Eq(t, theirsTagUid, tagUid)
+
+	// both sides changed the schema (to version base+1), the merged one differs from both
+	assert.Eq(t, baseModel.SchemaVersion+2, merged.SchemaVersion)
+
+	// generating from the merged schema doesn't change the merged model
+	mergedData, err := ioutil.ReadFile(ours)
+	assert.NoErr(t, err)
+	var schemaFile = filepath.Join(filepath.Dir(ours), "schema.fbs")
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n /// objectbox:index\n text: string;\n"+
+		" prio: int;\n /// objectbox:index\n due: long;\n}\ntable Note {\n id: ulong;\n}\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	fixture.Generate(t, schemaFile, &cgenerator.CGenerator{LangVersion: 11})
+	regeneratedData, err := ioutil.ReadFile(ours)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(mergedData), string(regeneratedData))
+
+	// theirs retiring an entity and adding a property, ours unchanged: theirs' IDs are kept
+	var retiring = generate("retiring", "table Task {\n id: ulong;\n /// objectbox:index\n text: string;\n prio: int;\n"+
+		" /// objectbox:index\n due: long;\n done: bool;\n}\n/// objectbox:retired\ntable Note {\n id: ulong;\n}\n"+
+		"table Tag {\n id: ulong;\n}\n", ours)
+	var unchanged = filepath.Join(dir, "unchanged.json")
+	assert.NoErr(t, ioutil.WriteFile(unchanged, mergedData, 0600))
+	notices, err = generator.MergeModelFiles(ours, unchanged, retiring)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(notices))
+	unchangedData, err := ioutil.ReadFile(unchanged)
+	assert.NoErr(t, err)
+	retiringData, err := ioutil.ReadFile(retiring)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(retiringData), string(unchangedData))
+
+	// both sides adding an entity with the same name is a conflict, ours isn't changed
+	var conflicting = generate("conflicting", "table Task {\n id: ulong;\n text: string;\n}\ntable Note {\n id: ulong;\n}\n", base)
+	conflictingData, err := ioutil.ReadFile(conflicting)
+	assert.NoErr(t, err)
+	_, err = generator.MergeModelFiles(base, conflicting, ours)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "entity Note was added in both ours and theirs, with different UIDs"))
+	unchangedData, err = ioutil.ReadFile(conflicting)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(conflictingData), string(unchangedData))
+
+	// an empty base, e.g. the model file added on both branches
+	var emptyBase = filepath.Join(dir, "empty.json")
+	assert.NoErr(t, ioutil.WriteFile(emptyBase, []byte{}, 0600))
+	_, err = generator.MergeModelFiles(emptyBase, unchanged, unchanged)
+	assert.NoErr(t, err)
+}
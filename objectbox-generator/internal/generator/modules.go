@@ -0,0 +1,155 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// mergeModuleModels merges the entities of Options.ModuleModelFiles into the stored model, after the sources have
+// been merged (i.e. entities declared in the sources are CurrentlyPresent). The entities keep their UIDs while their
+// IDs are assigned by the stored model, as for any other entity.
+func mergeModuleModels(options Options, storedModel *model.ModelInfo) error {
+	// where the currently present entities come from, for collision errors
+	var origins = make(map[*model.Entity]string)
+	var originOf = func(entity *model.Entity) string {
+		if origin, found := origins[entity]; found {
+			return origin
+		}
+		return "the processed sources"
+	}
+
+	// tenant prefix => the model file of the tenant's variant, see Options.TenantPrefix
+	var tenants = make(map[string]string)
+	if len(storedModel.TenantPrefix) > 0 {
+		tenants[storedModel.TenantPrefix] = "the model being generated"
+	}
+
+	for _, file := range options.ModuleModelFiles {
+		if abs, err := filepath.Abs(file); err == nil {
+			if storedAbs, err := filepath.Abs(options.ModelInfoFile); err == nil && abs == storedAbs {
+				return fmt.Errorf("module model %s is the same file as the model being generated", file)
+			}
+		}
+
+		moduleModel, err := model.LoadModelReadOnly(file)
+		if err != nil {
+			return fmt.Errorf("can't read module model %s: %s", file, err)
+		} else if err = moduleModel.Validate(); err != nil {
+			return fmt.Errorf("invalid module model %s: %s", file, err)
+		}
+
+		// the variants of a schema for the same tenant would collide entirely
+		if prefix := moduleModel.TenantPrefix; len(prefix) > 0 {
+			if other, found := tenants[prefix]; found {
+				return messages.SameTenantPrefix.Errorf(file, prefix, other)
+			}
+			tenants[prefix] = "module model " + file
+		}
+
+		// check for collisions and create the missing entities (and their properties & relations) with the pinned UIDs
+		for _, entity := range moduleModel.Entities {
+			uid, err := entity.Id.GetUid()
+			if err != nil {
+				return fmt.Errorf("module model %s: entity %s: %s", file, entity.Name, err)
+			}
+
+			if existing, _ := storedModel.FindEntityByName(entity.Name); existing != nil {
+				if existing.CurrentlyPresent {
+					return fmt.Errorf("entity %s from module model %s collides with entity %s declared in %s",
+						entity.Name, file, existing.Name, originOf(existing))
+				} else if existingUid, _ := existing.Id.GetUid(); existingUid != uid {
+					return fmt.Errorf("entity %s from module model %s has UID %d but the model already contains an entity of the same name with UID %d",
+						entity.Name, file, uid, existingUid)
+				}
+			}
+
+			storedEntity, _ := storedModel.FindEntityByUid(uid)
+			if storedEntity != nil && storedEntity.CurrentlyPresent {
+				return fmt.Errorf("entity %s from module model %s has the same UID %d as entity %s declared in %s",
+					entity.Name, file, uid, storedEntity.Name, originOf(storedEntity))
+			} else if storedEntity == nil {
+				if storedEntity, err = storedModel.CreateEntityWithUid(entity.Name, uid); err != nil {
+					return fmt.Errorf("module model %s: entity %s: %s", file, entity.Name, err)
+				}
+			}
+
+			if err = createModuleEntityMembers(entity, storedEntity); err != nil {
+				return fmt.Errorf("module model %s: entity %s: %s", file, entity.Name, err)
+			}
+		}
+
+		if err = mergeBindingWithModelInfo(moduleModel, storedModel); err != nil {
+			return fmt.Errorf("can't merge module model %s: %s", file, err)
+		}
+
+		for _, entity := range moduleModel.Entities {
+			var storedEntity, _ = storedModel.FindEntityByName(entity.Name)
+			storedEntity.CurrentlyPresent = true
+			storedEntity.ModuleModelFile = file
+			origins[storedEntity] = "module model " + file
+		}
+	}
+
+	if len(options.ModuleModelFiles) > 0 {
+		if err := storedModel.Finalize(); err != nil {
+			return fmt.Errorf("model finalization failed: %s", err)
+		}
+	}
+	return nil
+}
+
+// createModuleEntityMembers creates properties and relations missing in the stored entity with the UIDs of the module
+// entity, so that merging them keeps the UIDs. Members found by name are left for the merge, e.g. to reset a property.
+func createModuleEntityMembers(moduleEntity *model.Entity, storedEntity *model.Entity) error {
+	for _, property := range moduleEntity.Properties {
+		uid, err := property.Id.GetUid()
+		if err != nil {
+			return fmt.Errorf("property %s: %s", property.Name, err)
+		}
+		if existing, _ := storedEntity.FindPropertyByUid(uid); existing != nil {
+			continue
+		} else if existing, _ := storedEntity.FindPropertyByName(property.Name); existing != nil {
+			continue
+		} else if _, err = storedEntity.CreatePropertyWithUid(uid); err != nil {
+			return fmt.Errorf("property %s: %s", property.Name, err)
+		}
+	}
+
+	for _, relation := range moduleEntity.Relations {
+		uid, err := relation.Id.GetUid()
+		if err != nil {
+			return fmt.Errorf("relation %s: %s", relation.Name, err)
+		}
+		if existing, _ := storedEntity.FindRelationByUid(uid); existing != nil {
+			continue
+		} else if existing, _ := storedEntity.FindRelationByName(relation.Name); existing != nil {
+			continue
+		} else if _, err = storedEntity.CreateRelationWithUid(uid); err != nil {
+			return fmt.Errorf("relation %s: %s", relation.Name, err)
+		}
+	}
+	return nil
+}
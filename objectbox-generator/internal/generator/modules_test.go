@@ -0,0 +1,152 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestModuleModels(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "modules")
+	defer remove()
+
+	// each module is generated separately, with its own model
+	var generateModule = func(name, source string) string {
+		assert.NoErr(t, os.MkdirAll(filepath.Join(dir, name), 0700))
+		var schemaFile = filepath.Join(dir, name, "schema.fbs")
+		assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(source), 0600))
+		assert.NoErr(t, generator.Process(generator.Options{
+			InPath:        schemaFile,
+			CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+		}))
+		return generator.ModelInfoFile(filepath.Join(dir, name))
+	}
+	var usersModel = generateModule("users", "table User {\n id: ulong;\n /// objectbox:index\n name: string;\n}\n")
+	var ordersModel = generateModule("orders", "table Order {\n id: ulong;\n /// objectbox:relation=User\n userId: ulong;\n}\n")
+
+	usersInfo, err := model.LoadModelFromJSONFile(usersModel)
+	assert.NoErr(t, err)
+
+	var appFile = filepath.Join(dir, "app.fbs")
+	fixture.WriteFile(t, appFile, "table Setting {\n id: ulong;\n value: string;\n}\n")
+	var options = generator.Options{
+		InPath:           appFile,
+		ModuleModelFiles: []string{usersModel, ordersModel},
+		CodeGenerator:    &cgenerator.CGenerator{LangVersion: 14},
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	// the combined model contains the entities of all modules, keeping their UIDs
+	combined, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(combined.Entities))
+	user, err := combined.FindEntityByName("User")
+	assert.NoErr(t, err)
+	var uidOf = func(id model.IdUid) model.Uid {
+		uid, err := id.GetUid()
+		assert.NoErr(t, err)
+		return uid
+	}
+	assert.Eq(t, uidOf(usersInfo.Entities[0].Id), uidOf(user.Id))
+	assert.Eq(t, uidOf(usersInfo.Entities[0].Properties[1].Id), uidOf(user.Properties[1].Id))
+	assert.True(t, user.Properties[1].IndexId != nil)
+	order, err := combined.FindEntityByName("Order")
+	assert.NoErr(t, err)
+	assert.Eq(t, "User", order.Properties[1].RelationTarget)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "objectbox-model.h"))
+	assert.NoErr(t, err)
+	for _, name := range []string{"Setting", "User", "Order"} {
+		assert.True(t, strings.Contains(string(data), `obx_model_entity(model, "`+name+`"`))
+	}
+
+	// bindings are only generated for the processed sources
+	_, err = os.Stat(filepath.Join(dir, "users.obx.hpp"))
+	assert.True(t, os.IsNotExist(err))
+
+	// regenerating keeps the model unchanged
+	assert.NoErr(t, generator.Process(options))
+	combinedAgain, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, combined.LastEntityId, combinedAgain.LastEntityId)
+	assert.Eq(t, 3, len(combinedAgain.Entities))
+
+	// entity names must be unique across the sources and modules
+	fixture.WriteFile(t, appFile, "table Setting {\n id: ulong;\n}\ntable User {\n id: ulong;\n}\n")
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.Eq(t, "entity User from module model "+usersModel+" collides with entity User declared in the processed sources", err.Error())
+
+	// ... and so must be the UIDs
+	var copiedModel = filepath.Join(dir, "orders", "copy.json")
+	copied, err := ioutil.ReadFile(usersModel)
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(copiedModel, []byte(strings.Replace(string(copied), `"name": "User"`, `"name": "Customer"`, 1)), 0600))
+	fixture.WriteFile(t, appFile, "table Setting {\n id: ulong;\n}\n")
+	options.ModuleModelFiles = []string{usersModel, copiedModel}
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.Eq(t, fmt.Sprintf("entity Customer from module model %s has the same UID %d as entity User declared in module model %s",
+		copiedModel, uidOf(user.Id), usersModel), err.Error())
+}
+
+func TestGoModuleModels(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "go-modules")
+	defer remove()
+
+	fixture.WriteFile(t, filepath.Join(dir, "go.mod"), "module example.com/mono\n")
+	assert.NoErr(t, os.MkdirAll(filepath.Join(dir, "users", "model"), 0700))
+	assert.NoErr(t, os.MkdirAll(filepath.Join(dir, "app"), 0700))
+
+	var usersFile = filepath.Join(dir, "users", "model", "user.go")
+	fixture.WriteFile(t, usersFile, "package usermodel\n\ntype User struct {\n\tId   uint64\n\tName string\n}\n")
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:        usersFile,
+		CodeGenerator: &gogenerator.GoGenerator{},
+	}))
+
+	var appFile = filepath.Join(dir, "app", "setting.go")
+	fixture.WriteFile(t, appFile, "package app\n\ntype Setting struct {\n\tId    uint64\n\tValue string\n}\n")
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:           appFile,
+		ModuleModelFiles: []string{generator.ModelInfoFile(filepath.Join(dir, "users", "model"))},
+		CodeGenerator:    &gogenerator.GoGenerator{},
+	}))
+
+	// the model binding imports the bindings of the other module
+	data, err := ioutil.ReadFile(filepath.Join(dir, "app", "objectbox-model.go"))
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(data), `usermodel "example.com/mono/users/model"`))
+	assert.True(t, strings.Contains(string(data), "model.RegisterBinding(usermodel.UserBinding)"))
+	assert.True(t, strings.Contains(string(data), "model.RegisterBinding(SettingBinding)"))
+}
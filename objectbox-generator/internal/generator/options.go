@@ -20,7 +20,16 @@
 
 package generator
 
-import "math/rand"
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
 
 // Options provide configuration for the generator
 type Options struct {
@@ -30,6 +39,209 @@ type Options struct {
 	OutPath        string
 	OutHeadersPath string
 
+	// OutPattern, if set, makes the generator produce one binding file per entity instead of one per source file.
+	// It's a text/template for the file name, e.g. "{{.Entity}}.obx.{{.Ext}}", with the following fields available:
+	// Entity (entity name), Source (source file name without extension) and Ext (e.g. "hpp").
+	OutPattern string
+
+	// PerPackage processes each directory matched by InPath (e.g. "./...") separately if it contains sources declaring
+	// entities, e.g. each Go package of a monorepo: every package gets its own model JSON and model file, next to the
+	// sources or, with OutPath, in the same relative directory under OutPath (mirroring the structure of the sources).
+	// Packages without entities are skipped. Only supported by Process().
+	PerPackage bool
+
+	// DeterministicUids derives new UIDs from entity/property/relation names (with the optional UidSalt) instead of
+	// generating random ones, so that the model is reproducible even without a persisted model JSON file.
+	// UIDs of existing model elements can still be pinned using the uid annotation.
+	DeterministicUids bool
+	UidSalt           string
+
+	// TemplateOverridesDir, if set, points to a directory with "*.tmpl" files customizing the generated code.
+	// See OverrideTemplates() for how they're applied.
+	TemplateOverridesDir string
+
+	// ManifestFile, if set, receives a JSON list of all the files generated (or, with Validate(), that would be
+	// generated), see BuildManifest()
+	ManifestFile string
+
+	// AdminMetadataFile, if set, receives a JSON description of the model for ObjectBox Admin, e.g. labels and relation
+	// names, along with the model JSON file, see model.AdminMetadata
+	AdminMetadataFile string
+
+	// BundleFile, if set, makes Process() write all the generated files to a zip archive instead of the source tree,
+	// see ProcessBundle()
+	BundleFile string
+
+	// AtomicWrites makes the generator write each file to a temporary file in the same directory first and rename it
+	// to the target, so that an interrupted build (or a concurrent one) never leaves a partially written file behind.
+	// See Options.WriteFile(); the model JSON file is replaced the same way.
+	AtomicWrites bool
+
+	// BackupFiles keeps the previous content of a generated file as "<file>.bak" when the content changes, e.g. to
+	// review or restore the previous output. See Options.WriteFile().
+	BackupFiles bool
+
+	// BuildInfo stamps each generated file with a build info block (generator version, input and options hashes) in
+	// its header comment, e.g. to find out which generator run produced a file. See Options.WriteFile(), VerifyBuildInfo().
+	BuildInfo bool
+
+	// Checksums appends a checksum of the content to each generated file (in a footer comment), so that manual edits
+	// are detected: the generator refuses to overwrite (or clean up) a file whose content doesn't match its checksum,
+	// failing with a ManualEditError showing the diff, unless confirmed by Force. All the files to be generated are
+	// checked before any is written, so an edited file doesn't leave the others half-updated. See Options.WriteFile().
+	Checksums bool
+
+	// Force overwrites generated files edited manually, see Checksums
+	Force bool
+
+	// Strict turns constructs the selected CodeGenerator doesn't fully support (e.g. property types it can't read or
+	// write, or skipped fields) into errors, instead of generating incomplete code. See StrictChecker.
+	Strict bool
+
+	// KeepGoing continues reading and validating the sources after an error, e.g. an invalid annotation, so that all
+	// of them are reported at once, see ErrorList. No files are written unless all the sources are valid.
+	KeepGoing bool
+
+	// AllowDrop confirms that previously stored properties may become transient (e.g. `objectbox:"-"`), which drops
+	// their data. Without it, such a change is an error unless the field is annotated as transient(allow-drop).
+	AllowDrop bool
+
+	// AcceptOptionsChange confirms that code generator options recorded in the model JSON file may change, e.g.
+	// EmptyStringAsNull, even though data written by the previously generated code may be read differently.
+	// See CompatibilityOptionsProvider.
+	AcceptOptionsChange bool
+
+	// Frozen forbids any changes of the model schema, e.g. on a release branch: the generation only succeeds if the
+	// sources match the stored model, i.e. no new IDs/UIDs are assigned and nothing is removed or changed. Nothing is
+	// written otherwise. A FreezeFileName next to the model JSON file freezes the model as well.
+	Frozen bool
+
+	// LintRules, if not nil, enables linting of the sources before generating, see Lint()
+	LintRules LintRules
+
+	// GenerateBenchmarks makes the generator produce an additional benchmark file for each source file, measuring
+	// FlatBuffers serialization (put) and deserialization (get) of each entity, so that users can track regressions
+	// caused by schema changes. Supported for Go (testing.B), C++ (google-benchmark) and JS (a node script).
+	GenerateBenchmarks bool
+
+	// GenerateFixtures makes the generator produce an additional file for each source file with functions creating
+	// entity objects for tests: with sensible defaults and, for a given seed, filled with pseudo-random values.
+	// Supported for Go (<source>.obx.fixtures_test.go) and C++ (<source>.obx.fixtures.hpp).
+	GenerateFixtures bool
+
+	// GenerateExport makes the generator produce an additional file for each source file with functions exporting
+	// objects of each entity to JSON and CSV and importing them back, e.g. for backup and migration scripts. The values
+	// are read from (and written to) the FlatBuffers representation, i.e. as stored in the database.
+	// Supported for Go (<source>.obx.export.go) and C++ (<source>.obx.export.hpp, using nlohmann::json).
+	GenerateExport bool
+
+	// ModuleModelFiles lists model JSON files of other modules (e.g. one per Go module in a monorepo) to merge into the
+	// model of ModelInfoFile, so that the generated model binding covers the entities of all modules. The other models
+	// are only read; entity names and UIDs must not collide across them and the processed sources.
+	ModuleModelFiles []string
+
+	// OnlyEntities and ExcludeEntities, if set, restrict the binding files written to those of the selected entities,
+	// see EntitySelected(), e.g. to regenerate only the entities affected by a change of a large schema. All the sources
+	// are still read and merged, so the model JSON file and the model binding file stay complete and consistent.
+	// Binding files generated per source file are written if any of its entities is selected. The implicit cleanup of
+	// the generated files (when generating for a directory) is skipped. Unknown entity names are reported as errors.
+	OnlyEntities    []string
+	ExcludeEntities []string
+
+	// TenantPrefix, if set, is prepended to the names of all entities, e.g. "Acme_", generating a tenant's variant of
+	// the schema for apps embedding several isolated stores. With DeterministicUids, UIDs are derived from the prefixed
+	// names and UIDs pinned in the sources are derived for the tenant. The prefix is recorded in the model JSON file,
+	// which must thus be separate for each tenant. Only supported by code generators declaring the entity types in the
+	// generated code (implementing EntityRenamer), e.g. C, C++ and JS.
+	TenantPrefix string
+
+	// PathStyle selects the separators of the paths the generator writes, e.g. to the manifest: PathStyleNative (the
+	// default if empty) or PathStyleSlash, making the output the same on all OSes. Paths given in the options are
+	// normalized regardless of the style, see NormalizePath().
+	PathStyle string
+
+	// Output receives the messages printed while generating, e.g. the files removed by the implicit cleanup or lint
+	// issues; os.Stdout if nil. Set it to os.Stderr to keep stdout clean, e.g. for machine-readable output.
+	Output io.Writer
+
 	// NOTE - currently only supports one
 	CodeGenerator CodeGenerator
+
+	// CodeGenerators, if set instead of CodeGenerator, makes Process() generate for all the listed targets at once,
+	// e.g. C++ and JS from the same FlatBuffers schema. The sources of all targets are merged into the model which is
+	// written only once, so the outputs are consistent with each other. The generators implementing ParseCacheUser
+	// share a ParseCache so that each source file is parsed only once.
+	CodeGenerators []CodeGenerator
+
+	// checkEditsOnly makes WriteFile() only check the files for manual edits instead of writing them, see process()
+	checkEditsOnly bool
+}
+
+// Targets returns the code generators to run: CodeGenerators if set, the CodeGenerator otherwise
+func (options Options) Targets() []CodeGenerator {
+	if len(options.CodeGenerators) > 0 {
+		return options.CodeGenerators
+	}
+	return []CodeGenerator{options.CodeGenerator}
+}
+
+// output returns the writer receiving the messages printed while generating, see Output
+func (options Options) output() io.Writer {
+	if options.Output == nil {
+		return os.Stdout
+	}
+	return options.Output
+}
+
+// EntitySelected checks whether the binding files of the given entity should be written, see OnlyEntities and
+// ExcludeEntities. Entity names are matched case-insensitively, without the TenantPrefix.
+func (options Options) EntitySelected(name string) bool {
+	if len(options.TenantPrefix) > 0 {
+		name = strings.TrimPrefix(name, options.TenantPrefix)
+	}
+	if len(options.OnlyEntities) > 0 && !containsEntityName(options.OnlyEntities, name) {
+		return false
+	}
+	return !containsEntityName(options.ExcludeEntities, name)
+}
+
+// hasEntityFilter returns true if only some of the entities may be selected, see EntitySelected()
+func (options Options) hasEntityFilter() bool {
+	return len(options.OnlyEntities) > 0 || len(options.ExcludeEntities) > 0
+}
+
+func containsEntityName(names []string, name string) bool {
+	for _, item := range names {
+		if strings.EqualFold(item, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// OutPatternFile evaluates OutPattern, returning the (base) name of a binding file for the given entity
+func (options Options) OutPatternFile(sourceFile, entityName, ext string) (string, error) {
+	tpl, err := template.New("out-pattern").Option("missingkey=error").Parse(options.OutPattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid out-pattern: %s", err)
+	}
+
+	var source = filepath.Base(sourceFile)
+	var data = struct {
+		Entity string
+		Source string
+		Ext    string
+	}{entityName, strings.TrimSuffix(source, filepath.Ext(source)), ext}
+
+	var b bytes.Buffer
+	if err = tpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("invalid out-pattern: %s", err)
+	}
+
+	var name = b.String()
+	if len(name) == 0 || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid out-pattern: %q produces %q for entity %s - expecting a file name without a directory",
+			options.OutPattern, name, entityName)
+	}
+	return name, nil
 }
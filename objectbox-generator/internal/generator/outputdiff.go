@@ -0,0 +1,132 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileDiff describes the changes the generator would make to a single file, see DiffOutput()
+type FileDiff struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // "added" or "modified"
+	Diff   string `json:"diff"`   // in the unified diff format
+}
+
+// DiffOutput generates the code like ProcessStream(), i.e. without writing to the source tree, and compares it with
+// the generated files (and the model JSON) currently on disk. Returns the differences of the files that would change.
+func DiffOutput(options Options) ([]FileDiff, error) {
+	if err := options.normalizePaths(); err != nil {
+		return nil, err
+	}
+
+	tempDir, err := ioutil.TempDir("", "objectbox-generator-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if len(options.ModelInfoFile) == 0 {
+		options.ModelInfoFile = ModelInfoFile(filepath.Dir(options.InPath))
+	}
+
+	var outDir = filepath.Join(tempDir, "out")
+	files, err := processToDir(options, outDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var generated = make(map[string]string)
+	for _, file := range files {
+		generated[file.Name] = file.Content
+	}
+
+	// the options used by processToDir(), to pair the files generated there with the ones on disk
+	var tempOptions = options
+	tempOptions.ModelInfoFile = ModelInfoFile(outDir)
+	tempOptions.OutPath = outDir
+	tempOptions.OutHeadersPath = ""
+
+	var diffs []FileDiff
+	var compare = func(tempFile, file string) error {
+		name, err := filepath.Rel(outDir, tempFile)
+		if err != nil {
+			return err
+		}
+		content, found := generated[filepath.ToSlash(name)]
+		if !found {
+			return nil
+		}
+
+		var diff = FileDiff{Path: options.FormatPath(file), Status: "modified"}
+		var oldName = diff.Path
+		existing, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			diff.Status = "added"
+			oldName = "/dev/null"
+		} else if err != nil {
+			return fmt.Errorf("can't read %s: %s", file, err)
+		}
+
+		if diff.Diff = UnifiedDiff(oldName, diff.Path, string(existing), content); len(diff.Diff) > 0 {
+			diffs = append(diffs, diff)
+		}
+		return nil
+	}
+
+	err = pathForEach(options.InPath, func(sourceFile string) error {
+		if !options.CodeGenerator.IsSourceFile(sourceFile) {
+			return nil
+		}
+		files, err := options.CodeGenerator.BindingFiles(sourceFile, options)
+		if err != nil {
+			return err
+		}
+		tempFiles, err := tempOptions.CodeGenerator.BindingFiles(sourceFile, tempOptions)
+		if err != nil {
+			return err
+		}
+		if len(files) != len(tempFiles) {
+			return fmt.Errorf("can't match the files generated for %s", sourceFile)
+		}
+		for i := range files {
+			if err := compare(tempFiles[i], files[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = compare(tempOptions.ModelInfoFile, options.ModelInfoFile); err != nil {
+		return nil, err
+	}
+	if err = compare(tempOptions.CodeGenerator.ModelFile(tempOptions.ModelInfoFile, tempOptions),
+		options.CodeGenerator.ModelFile(options.ModelInfoFile, options)); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
@@ -0,0 +1,82 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestDiffOutput(t *testing.T) {
+	assert.Eq(t, "", generator.UnifiedDiff("a", "b", "x\n", "x\n"))
+	assert.Eq(t, "--- a\n+++ b\n@@ -1,3 +1,3 @@\n x\n-y\n+z\n w\n", generator.UnifiedDiff("a", "b", "x\ny\nw\n", "x\nz\nw\n"))
+	assert.Eq(t, "--- /dev/null\n+++ b\n@@ -0,0 +1,1 @@\n+x\n", generator.UnifiedDiff("/dev/null", "b", "", "x\n"))
+	assert.Eq(t, "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-x\n\\ No newline at end of file\n+x\n", generator.UnifiedDiff("a", "b", "x", "x\n"))
+
+	// distant changes are in separate hunks
+	var lines = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	assert.Eq(t, "--- a\n+++ b\n@@ -1,4 +1,3 @@\n-1\n 2\n 3\n 4\n@@ -7,4 +6,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		generator.UnifiedDiff("a", "b", lines, strings.Replace(lines[2:], "10", "ten", 1)))
+
+	dir, remove := fixture.TempDir(t, "diff")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+	var options = generator.Options{InPath: schemaFile, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+
+	// nothing generated yet
+	diffs, err := generator.DiffOutput(options)
+	assert.NoErr(t, err)
+	var statuses []string
+	for _, diff := range diffs {
+		statuses = append(statuses, filepath.Base(diff.Path)+":"+diff.Status)
+	}
+	assert.Eq(t, "schema.obx.hpp:added schema.obx.cpp:added objectbox-model.json:added objectbox-model.h:added", strings.Join(statuses, " "))
+	_, err = os.Stat(generator.ModelInfoFile(dir))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoErr(t, generator.Process(options))
+	diffs, err = generator.DiffOutput(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(diffs))
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n done: bool;\n}\n")
+	diffs, err = generator.DiffOutput(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(diffs))
+	assert.Eq(t, filepath.Join(dir, "schema.obx.hpp"), diffs[0].Path)
+	assert.Eq(t, "modified", diffs[0].Status)
+	assert.True(t, strings.Contains(diffs[0].Diff, "\n+    bool done;\n"))
+
+	// the files on disk are unchanged
+	data, err := ioutil.ReadFile(filepath.Join(dir, "schema.obx.hpp"))
+	assert.NoErr(t, err)
+	assert.True(t, !strings.Contains(string(data), "done"))
+}
@@ -0,0 +1,107 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// processPackages runs Process() for each directory matched by Options.InPath which contains sources declaring
+// entities, see Options.PerPackage
+func processPackages(options Options) error {
+	if len(options.ModelInfoFile) > 0 {
+		return errors.New("a model file can't be given when generating per package, each package has its own one")
+	}
+	if len(options.BundleFile) > 0 {
+		return errors.New("generating per package isn't supported when writing to a bundle")
+	}
+
+	if err := options.normalizePaths(); err != nil {
+		return err
+	}
+	if !PathIsDirOrPattern(options.InPath) {
+		return fmt.Errorf("generating per package requires a directory or a path pattern, e.g. ./..., but %s is a file", options.InPath)
+	}
+
+	packages, err := entityPackages(options)
+	if err != nil {
+		return err
+	}
+
+	var root = inPathRoot(options.InPath)
+	var errs ErrorList
+	for _, dir := range packages {
+		var packageOptions = options
+		packageOptions.PerPackage = false
+		packageOptions.InPath = dir
+		packageOptions.ModelInfoFile = ModelInfoFile(dir)
+		if len(options.OutPath) > 0 {
+			// mirror the structure of the sources
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return err
+			}
+			packageOptions.OutPath = filepath.Join(options.OutPath, rel)
+		}
+
+		fmt.Fprintf(options.output(), "Generating ObjectBox bindings for package %s\n", dir)
+		if err = errs.add(options, Process(packageOptions)); err != nil {
+			return err
+		}
+	}
+	return errs.err()
+}
+
+// entityPackages lists the directories (i.e. Go packages) with source files declaring at least one entity, in the order
+// they're found in
+func entityPackages(options Options) ([]string, error) {
+	var packages []string
+	var found = make(map[string]bool)
+	err := pathForEach(options.InPath, func(filePath string) error {
+		var dir = filepath.Dir(filePath)
+		if found[dir] || !options.CodeGenerator.IsSourceFile(filePath) || options.CodeGenerator.IsGeneratedFile(filePath) {
+			return nil
+		}
+
+		currentModel, err := parseSource(options, filePath)
+		if err != nil {
+			return sourceError(filePath, DiagnosticParse, err)
+		}
+		if len(currentModel.Entities) > 0 {
+			found[dir] = true
+			packages = append(packages, dir)
+		}
+		return nil
+	})
+	return packages, err
+}
+
+// inPathRoot returns the directory a path pattern starts at, e.g. "models" for "models/..."
+func inPathRoot(path string) string {
+	var dir = filepath.Clean(NormalizePath(path))
+	for strings.HasSuffix(dir, "...") || strings.ContainsAny(dir, "*?[") {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
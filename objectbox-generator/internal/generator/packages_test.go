@@ -0,0 +1,93 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestPerPackage(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "per-package")
+	defer remove()
+
+	var write = func(file, source string) {
+		file = filepath.Join(dir, "src", file)
+		assert.NoErr(t, os.MkdirAll(filepath.Dir(file), 0700))
+		assert.NoErr(t, ioutil.WriteFile(file, []byte(source), 0600))
+	}
+	write("tasks/task.go", "package tasks\n\ntype Task struct {\n\tId uint64\n}\n")
+	write("tasks/util.go", "package tasks\n\nfunc Util() {}\n")
+	write("notes/archive/note.go", "package archive\n\ntype Note struct {\n\tId uint64\n}\n")
+	write("util/util.go", "package util\n\nfunc Util() {}\n")
+
+	var exists = func(file string) bool {
+		_, err := os.Stat(filepath.Join(dir, file))
+		return err == nil
+	}
+
+	// the output mirrors the structure of the sources
+	var options = generator.Options{
+		InPath:        filepath.Join(dir, "src") + "/...",
+		OutPath:       filepath.Join(dir, "out"),
+		CodeGenerator: &gogenerator.GoGenerator{},
+		PerPackage:    true,
+	}
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("out/tasks/task.obx.go"))
+	assert.True(t, exists("out/tasks/objectbox-model.go"))
+	assert.True(t, exists("out/notes/archive/note.obx.go"))
+	assert.True(t, !exists("out/util"))
+
+	options.OutPath = ""
+	assert.NoErr(t, generator.Process(options))
+	assert.True(t, exists("src/tasks/objectbox-model.json"))
+	assert.True(t, exists("src/tasks/objectbox-model.go"))
+	assert.True(t, exists("src/tasks/task.obx.go"))
+	assert.True(t, exists("src/notes/archive/objectbox-model.json"))
+	assert.True(t, exists("src/notes/archive/note.obx.go"))
+	assert.True(t, !exists("src/objectbox-model.json"))
+	assert.True(t, !exists("src/util/objectbox-model.json"))
+	assert.True(t, !exists("src/util/util.obx.go"))
+
+	// each package has its own model, in its own package
+	modelSource, err := ioutil.ReadFile(filepath.Join(dir, "src/notes/archive/objectbox-model.go"))
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(modelSource), "package archive\n"))
+	assert.True(t, !strings.Contains(string(modelSource), "Task"))
+
+	// regenerating in place is fine, the generated files aren't read as sources
+	assert.NoErr(t, generator.Process(options))
+
+	options.ModelInfoFile = filepath.Join(dir, "objectbox-model.json")
+	assert.Err(t, generator.Process(options))
+	options.ModelInfoFile = ""
+	_, err = generator.Validate(options)
+	assert.Err(t, err)
+}
@@ -0,0 +1,98 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+)
+
+// ParseCacheUser may be implemented by a CodeGenerator to reuse the results of parsing a source file, e.g. the parsed
+// FlatBuffers schema, with other code generators of the same run, see Options.CodeGenerators.
+type ParseCacheUser interface {
+	// SetParseCache is called before any sources are read; the cache is shared by all the code generators of the run
+	SetParseCache(cache *ParseCache)
+}
+
+// ParseCache keeps the results of parsing source files, keyed by the file path, its contents hash and a key
+// describing how the file is parsed (e.g. the include directories). Only the contents of the file itself are hashed,
+// files it includes are expected not to change during a run. All methods are safe to call on a nil cache (not caching).
+type ParseCache struct {
+	mutex   sync.Mutex
+	entries map[string]parseCacheEntry
+	parsed  int
+}
+
+type parseCacheEntry struct {
+	hash  [sha256.Size]byte
+	value interface{}
+}
+
+// NewParseCache creates an empty cache
+func NewParseCache() *ParseCache {
+	return &ParseCache{entries: make(map[string]parseCacheEntry)}
+}
+
+// Load returns the value cached for the given file and key or calls parse() to create it if there's none or the file
+// contents have changed since. Errors are not cached.
+func (cache *ParseCache) Load(file, key string, parse func() (interface{}, error)) (interface{}, error) {
+	if cache == nil {
+		return parse()
+	}
+
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return parse() // let the parser report the error
+	}
+	var hash = sha256.Sum256(contents)
+
+	if absPath, err := filepath.Abs(file); err == nil {
+		file = absPath
+	}
+	var id = file + "\x00" + key
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if entry, found := cache.entries[id]; found && entry.hash == hash {
+		return entry.value, nil
+	}
+
+	value, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	cache.parsed++
+	cache.entries[id] = parseCacheEntry{hash: hash, value: value}
+	return value, nil
+}
+
+// Parsed returns the number of times a file has been parsed (i.e. not found in the cache)
+func (cache *ParseCache) Parsed() int {
+	if cache == nil {
+		return 0
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.parsed
+}
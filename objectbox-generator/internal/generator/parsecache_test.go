@@ -0,0 +1,129 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+// parseCacheRecorder keeps the cache passed to SetParseCache()
+type parseCacheRecorder struct {
+	*cgenerator.CGenerator
+	cache *generator.ParseCache
+}
+
+func (recorder *parseCacheRecorder) SetParseCache(cache *generator.ParseCache) {
+	recorder.cache = cache
+	recorder.CGenerator.SetParseCache(cache)
+}
+
+func TestMultipleTargets(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "targets")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task { id: ulong; text: string; }\n")
+
+	var cache = generator.NewParseCache()
+	var parse = func() (interface{}, error) { return "parsed", nil }
+	for i := 0; i < 2; i++ {
+		value, err := cache.Load(schemaFile, "key", parse)
+		assert.NoErr(t, err)
+		assert.Eq(t, "parsed", value)
+	}
+	assert.Eq(t, 1, cache.Parsed())
+
+	// parsed again with a different key or after the file has changed
+	_, err := cache.Load(schemaFile, "other", parse)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, cache.Parsed())
+	fixture.WriteFile(t, schemaFile, "table Task { id: ulong; text: string; done: bool; }\n")
+	_, err = cache.Load(schemaFile, "key", parse)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, cache.Parsed())
+
+	var cppGenerator = &parseCacheRecorder{CGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+	var jsGenerator = &jsgenerator.JSGenerator{}
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:         schemaFile,
+		CodeGenerators: []generator.CodeGenerator{cppGenerator, jsGenerator},
+	}))
+	for _, file := range []string{"schema.obx.hpp", "schema.obx.js", "objectbox-model.h", "objectbox-model.js"} {
+		_, err = os.Stat(filepath.Join(dir, file))
+		assert.NoErr(t, err)
+	}
+
+	modelInfo, err := model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(modelInfo.Entities))
+	assert.Eq(t, 3, len(modelInfo.Entities[0].Properties))
+
+	// the schema has been parsed once and shared by both generators (BindingFiles() parses it too)
+	assert.True(t, cppGenerator.cache != nil)
+	assert.Eq(t, 1, cppGenerator.cache.Parsed())
+
+	assert.Err(t, generator.Process(generator.Options{
+		InPath:         schemaFile,
+		CodeGenerator:  cppGenerator,
+		CodeGenerators: []generator.CodeGenerator{jsGenerator},
+	}))
+
+	// targets writing the same files or recording different options can't be generated together
+	err = generator.Process(generator.Options{
+		InPath:         schemaFile,
+		CodeGenerators: []generator.CodeGenerator{&cgenerator.CGenerator{LangVersion: 14}, &cgenerator.CGenerator{LangVersion: 11}},
+	})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "can't generate for multiple targets writing the same files"))
+
+	err = generator.Process(generator.Options{
+		InPath:         schemaFile,
+		CodeGenerators: []generator.CodeGenerator{&cgenerator.CGenerator{LangVersion: 14, NaNAsNull: true}, &jsgenerator.JSGenerator{}},
+	})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), `nan-as-null differs ("true" and "false")`))
+
+	// a directory with sources of different languages: all of them end up in a single model
+	fixture.WriteFile(t, filepath.Join(dir, "note.go"), "package notes\n\ntype Note struct {\n\tId uint64\n}\n")
+	assert.NoErr(t, generator.Process(generator.Options{
+		InPath:         dir,
+		ModelInfoFile:  generator.ModelInfoFile(dir),
+		CodeGenerators: []generator.CodeGenerator{&cgenerator.CGenerator{LangVersion: 14}, &gogenerator.GoGenerator{}},
+	}))
+	modelInfo, err = model.LoadModelReadOnly(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, len(modelInfo.Entities))
+	for _, file := range []string{"schema.obx.hpp", "note.obx.go", "objectbox-model.h", "objectbox-model.go"} {
+		_, err = os.Stat(filepath.Join(dir, file))
+		assert.NoErr(t, err)
+	}
+}
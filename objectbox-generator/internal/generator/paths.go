@@ -0,0 +1,102 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Path styles of the paths the generator writes, e.g. to the manifest, see Options.PathStyle
+const (
+	PathStyleNative = "native" // the separators of the current OS, the default
+	PathStyleSlash  = "slash"  // forward slashes on all OSes
+)
+
+// PathStyles lists the values accepted by Options.PathStyle
+var PathStyles = []string{PathStyleNative, PathStyleSlash}
+
+const windowsLongPathPrefix = `\\?\`
+const windowsLongUncPrefix = `\\?\UNC\`
+
+// NormalizePath prepares a path given by the user (e.g. Options.InPath) for the generator.
+// On Windows, see NormalizeWindowsPath(); other OSes use the path as is.
+func NormalizePath(path string) string {
+	if runtime.GOOS == "windows" {
+		return NormalizeWindowsPath(path)
+	}
+	return path
+}
+
+// NormalizeWindowsPath converts forward slashes to backslashes and removes the long path prefix, i.e. `\\?\C:\dir`
+// becomes `C:\dir` and `\\?\UNC\server\share` becomes `\\server\share`. The prefix would otherwise be treated as
+// a glob pattern (because of the "?") and it isn't necessary: Go adds it to long paths when accessing files.
+// A recursion suffix, i.e. `.\...`, is kept as "/..." so that it's recognized regardless of the separator used.
+func NormalizeWindowsPath(path string) string {
+	if strings.HasPrefix(path, windowsLongUncPrefix) {
+		path = `\\` + path[len(windowsLongUncPrefix):]
+	} else if strings.HasPrefix(path, windowsLongPathPrefix) {
+		path = path[len(windowsLongPathPrefix):]
+	}
+
+	var recursive = strings.HasSuffix(path, recursionSuffix) || strings.HasSuffix(path, `\...`)
+	if recursive {
+		path = path[0 : len(path)-len(recursionSuffix)]
+	}
+
+	path = strings.ReplaceAll(path, "/", `\`)
+
+	if recursive {
+		path += recursionSuffix
+	}
+	return path
+}
+
+// FormatPath returns the given path in the configured PathStyle, for paths written by the generator
+func (options Options) FormatPath(path string) string {
+	if options.PathStyle == PathStyleSlash {
+		return filepath.ToSlash(path)
+	}
+	return path
+}
+
+// normalizePaths applies NormalizePath() to all the paths in the options
+func (options *Options) normalizePaths() error {
+	switch options.PathStyle {
+	case "", PathStyleNative, PathStyleSlash:
+	default:
+		return fmt.Errorf("unknown path style %q, expecting one of: %s", options.PathStyle, strings.Join(PathStyles, ", "))
+	}
+
+	for _, path := range []*string{&options.InPath, &options.OutPath, &options.OutHeadersPath, &options.ModelInfoFile, &options.TemplateOverridesDir, &options.ManifestFile, &options.AdminMetadataFile} {
+		if len(*path) > 0 {
+			*path = NormalizePath(*path)
+		}
+	}
+	var moduleModelFiles = make([]string, len(options.ModuleModelFiles)) // don't modify the caller's slice
+	for i, file := range options.ModuleModelFiles {
+		moduleModelFiles[i] = NormalizePath(file)
+	}
+	options.ModuleModelFiles = moduleModelFiles
+	return nil
+}
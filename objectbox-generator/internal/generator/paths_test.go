@@ -0,0 +1,67 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestPathNormalization(t *testing.T) {
+	assert.Eq(t, `C:\work\schema.fbs`, generator.NormalizeWindowsPath(`\\?\C:\work\schema.fbs`))
+	assert.Eq(t, `C:\work\schema.fbs`, generator.NormalizeWindowsPath(`C:/work/schema.fbs`))
+	assert.Eq(t, `\\server\share\schema.fbs`, generator.NormalizeWindowsPath(`\\?\UNC\server\share\schema.fbs`))
+	assert.Eq(t, `\\server\share\schema.fbs`, generator.NormalizeWindowsPath(`//server/share/schema.fbs`))
+	assert.Eq(t, `.\src/...`, generator.NormalizeWindowsPath(`./src/...`))
+	assert.Eq(t, `.\src/...`, generator.NormalizeWindowsPath(`.\src\...`))
+	assert.Eq(t, `\\server\share/...`, generator.NormalizeWindowsPath(`\\?\UNC\server\share\...`))
+
+	// paths written by the generator
+	var options = generator.Options{PathStyle: generator.PathStyleSlash}
+	assert.Eq(t, "a/b/schema.fbs", options.FormatPath(filepath.Join("a", "b", "schema.fbs")))
+	options.PathStyle = generator.PathStyleNative
+	assert.Eq(t, filepath.Join("a", "b", "schema.fbs"), options.FormatPath(filepath.Join("a", "b", "schema.fbs")))
+
+	dir, remove := fixture.TempDir(t, "paths")
+	defer remove()
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+
+	options = generator.Options{InPath: schemaFile, PathStyle: generator.PathStyleSlash, CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+	manifest, err := generator.BuildManifest(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, filepath.ToSlash(schemaFile), manifest.Sources[0].Path)
+	assert.Eq(t, filepath.ToSlash(filepath.Join(dir, "objectbox-model.json")), manifest.ModelInfoFile)
+	for _, file := range manifest.Outputs {
+		assert.True(t, !strings.Contains(file, `\`))
+	}
+
+	options.PathStyle = "backslash"
+	_, err = generator.Validate(options)
+	assert.Err(t, err)
+	assert.Eq(t, `unknown path style "backslash", expecting one of: native, slash`, err.Error())
+}
@@ -0,0 +1,97 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestReserved(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "reserved")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// new entities & properties are assigned IDs around the reserved ones
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("/// objectbox:reserved=2,3 reserved-entity-ids=1\n"+
+		"table Task {\n id: ulong;\n text: string;\n done: bool;\n}\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	assert.NoErr(t, generator.Process(options))
+
+	storedModel, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	task, err := storedModel.FindEntityByName("Task")
+	assert.NoErr(t, err)
+	for _, entity := range storedModel.Entities {
+		id, err := entity.Id.GetId()
+		assert.NoErr(t, err)
+		assert.True(t, id != 1)
+	}
+	var propertyIds []model.Id
+	for _, property := range task.Properties {
+		id, err := property.Id.GetId()
+		assert.NoErr(t, err)
+		propertyIds = append(propertyIds, id)
+	}
+	assert.Eq(t, []model.Id{1, 4, 5}, propertyIds)
+	textUid, err := task.Properties[1].Id.GetUid()
+	assert.NoErr(t, err)
+	assert.NoErr(t, storedModel.Close())
+
+	// reserving an ID or UID already in use fails
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("/// objectbox:reserved=4\n"+
+		"table Task {\n id: ulong;\n text: string;\n done: bool;\n}\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.Eq(t, "property Task.text 4:"+fmt.Sprint(textUid)+" uses an ID reserved in the entity", err.Error())
+
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(fmt.Sprintf("/// objectbox:reserved-uids=%d\n", textUid)+
+		"table Task {\n id: ulong;\n text: string;\n done: bool;\n}\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "uses a reserved UID"))
+
+	// a reserved UID can't be pinned
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("/// objectbox:reserved-uids=1234567\n"+
+		"table Task {\n id: ulong;\n text: string;\n done: bool;\n /// objectbox:uid=1234567\n note: string;\n}\n"), 0600))
+	options.DeterministicUids = true
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "UID 1234567 is reserved"))
+
+	fixture.WriteFile(t, schemaFile, "/// objectbox:reserved=0\ntable Task {\n id: ulong;\n}\n")
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), `invalid reserved annotation value "0"`))
+}
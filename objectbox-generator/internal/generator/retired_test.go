@@ -0,0 +1,96 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestRetired(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "retired")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\ntable Tag {\n id: ulong;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+
+	storedModel, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	task, err := storedModel.FindEntityByName("Task")
+	assert.NoErr(t, err)
+	text, err := task.FindPropertyByName("text")
+	assert.NoErr(t, err)
+	textUid, err := text.Id.GetUid()
+	assert.NoErr(t, err)
+	tag, err := storedModel.FindEntityByName("Tag")
+	assert.NoErr(t, err)
+	tagUid, err := tag.Id.GetUid()
+	assert.NoErr(t, err)
+	tagIdUid, err := tag.Properties[0].Id.GetUid()
+	assert.NoErr(t, err)
+
+	// retiring doesn't need a confirmation to drop the data, even when processing a single file
+	storedModel.Version = 3
+	assert.NoErr(t, storedModel.Write())
+	assert.NoErr(t, storedModel.Close())
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n /// objectbox:retired\n text: string;\n}\n"+
+		"/// objectbox:retired\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	assert.NoErr(t, generator.Process(options))
+
+	storedModel, err = model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(storedModel.Entities))
+	assert.Eq(t, 1, len(storedModel.Entities[0].Properties))
+	var retiredPropertyUids = []model.Uid{textUid, tagIdUid} // Task.text & Tag.id, sorted
+	if tagIdUid < textUid {
+		retiredPropertyUids = []model.Uid{tagIdUid, textUid}
+	}
+	assert.Eq(t, retiredPropertyUids, storedModel.RetiredPropertyUids)
+	assert.Eq(t, []model.Uid{tagUid}, storedModel.RetiredEntityUids)
+	assert.Eq(t, 3, storedModel.RetiredUidVersions[textUid])
+	assert.Eq(t, 3, storedModel.RetiredUidVersions[tagUid])
+
+	// retired UIDs must not be reused
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(fmt.Sprintf("table Task {\n id: ulong;\n /// objectbox:uid=%d\n note: string;\n}\n", textUid)), 0600))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), fmt.Sprintf("UID %d belongs to a retired property (retired in model version 3)", textUid)))
+
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(fmt.Sprintf("/// objectbox:uid=%d\ntable Label {\n id: ulong;\n}\n", tagUid)), 0600))
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "belongs to a retired entity"))
+}
@@ -0,0 +1,223 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Kinds of retired model elements, in the order they're listed by RetiredReport.Write()
+var retiredKinds = []string{"entity", "property", "index", "relation"}
+
+// RetiredNameResolver recovers the names of retired model elements, which the model JSON file doesn't keep.
+type RetiredNameResolver interface {
+	// ResolveRetiredNames returns the names of the given UIDs, e.g. "Task" for an entity or "Task.text" for a property,
+	// its index or a standalone relation; UIDs which can't be resolved are left out of the result.
+	ResolveRetiredNames(modelFile string, uids []model.Uid) (map[model.Uid]string, error)
+}
+
+// GitNameResolver looks up retired UIDs in the previous revisions of the model file in its git history, using the
+// git executable. Model files outside of a git work tree (or without git installed) don't get any names resolved.
+type GitNameResolver struct{}
+
+// ResolveRetiredNames implements RetiredNameResolver, preferring the latest name of each UID, i.e. before it was retired
+func (GitNameResolver) ResolveRetiredNames(modelFile string, uids []model.Uid) (map[model.Uid]string, error) {
+	var names = make(map[model.Uid]string)
+	if len(uids) == 0 {
+		return names, nil
+	} else if _, err := exec.LookPath("git"); err != nil {
+		return names, nil
+	}
+
+	var dir, file = filepath.Dir(modelFile), filepath.Base(modelFile)
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return names, nil
+	}
+
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%H", "--", file).Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't list the git history of %s: %s", modelFile, err)
+	}
+
+	var unresolved = make(map[model.Uid]bool, len(uids))
+	for _, uid := range uids {
+		unresolved[uid] = true
+	}
+
+	// revisions are listed newest first
+	for _, revision := range strings.Fields(string(out)) {
+		if len(unresolved) == 0 {
+			break
+		}
+		data, err := exec.Command("git", "-C", dir, "show", revision+":./"+file).Output()
+		if err != nil {
+			return nil, fmt.Errorf("can't read %s at git revision %s: %s", modelFile, revision, err)
+		}
+		var revisionModel model.ModelInfo
+		if err := json.Unmarshal(data, &revisionModel); err != nil {
+			continue // e.g. a revision with unresolved merge conflicts
+		}
+		for uid, name := range modelElementNames(&revisionModel) {
+			if unresolved[uid] {
+				names[uid] = name
+				delete(unresolved, uid)
+			}
+		}
+	}
+	return names, nil
+}
+
+// modelElementNames maps the UIDs of all entities, properties, indexes and relations of the model to their names
+func modelElementNames(modelInfo *model.ModelInfo) map[model.Uid]string {
+	var names = make(map[model.Uid]string)
+	var add = func(idUid model.IdUid, name string) {
+		if uid, err := idUid.GetUid(); err == nil {
+			names[uid] = name
+		}
+	}
+	for _, entity := range modelInfo.Entities {
+		add(entity.Id, entity.Name)
+		for _, property := range entity.Properties {
+			add(property.Id, entity.Name+"."+property.Name)
+			if property.IndexId != nil {
+				add(*property.IndexId, entity.Name+"."+property.Name)
+			}
+		}
+		for _, relation := range entity.Relations {
+			add(relation.Id, entity.Name+"."+relation.Name)
+		}
+	}
+	return names
+}
+
+// RetiredReport lists the retired UIDs of a model grouped by the model version they were retired in, see ReportRetired()
+type RetiredReport struct {
+	ModelFile string           `json:"modelFile"`
+	Versions  []RetiredVersion `json:"versions"`
+}
+
+// RetiredVersion lists the elements retired in a (user specified) model version; Version is 0 for UIDs retired
+// before the generator started recording the versions in objectbox-model.json
+type RetiredVersion struct {
+	Version  int              `json:"version"`
+	Elements []RetiredElement `json:"elements"`
+}
+
+// RetiredElement describes a retired UID; Name is only set if it has been resolved, see RetiredNameResolver
+type RetiredElement struct {
+	Uid  model.Uid `json:"uid"`
+	Kind string    `json:"kind"`
+	Name string    `json:"name,omitempty"`
+}
+
+// ReportRetired reads the retired UIDs of the model and the versions they were retired in, e.g. to audit which data
+// has been dropped when. options.InPath is the model file or its directory. The names of the retired elements are
+// looked up using the given resolver, if any.
+func ReportRetired(options Options, resolver RetiredNameResolver) (*RetiredReport, error) {
+	if err := options.normalizePaths(); err != nil {
+		return nil, err
+	}
+
+	modelFile, err := findModelFile(options)
+	if err != nil {
+		return nil, err
+	}
+
+	modelInfo, err := model.LoadModelReadOnly(modelFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't read model file %s: %s", modelFile, err)
+	}
+
+	var kinds = map[string][]model.Uid{
+		"entity":   modelInfo.RetiredEntityUids,
+		"property": modelInfo.RetiredPropertyUids,
+		"index":    modelInfo.RetiredIndexUids,
+		"relation": modelInfo.RetiredRelationUids,
+	}
+	var uids []model.Uid
+	for _, kind := range retiredKinds {
+		uids = append(uids, kinds[kind]...)
+	}
+
+	var names map[model.Uid]string
+	if resolver != nil {
+		if names, err = resolver.ResolveRetiredNames(modelFile, uids); err != nil {
+			return nil, err
+		}
+	}
+
+	var report = &RetiredReport{ModelFile: options.FormatPath(modelFile), Versions: []RetiredVersion{}}
+	var versions = make(map[int]*RetiredVersion)
+	for _, kind := range retiredKinds {
+		for _, uid := range kinds[kind] {
+			var version = modelInfo.RetiredUidVersions[uid]
+			if versions[version] == nil {
+				versions[version] = &RetiredVersion{Version: version}
+			}
+			versions[version].Elements = append(versions[version].Elements, RetiredElement{Uid: uid, Kind: kind, Name: names[uid]})
+		}
+	}
+
+	for _, version := range versions {
+		report.Versions = append(report.Versions, *version)
+	}
+	sort.Slice(report.Versions, func(i, j int) bool {
+		return report.Versions[i].Version < report.Versions[j].Version
+	})
+	return report, nil
+}
+
+// Write prints the retired elements grouped by version, oldest first
+func (report *RetiredReport) Write(w io.Writer) error {
+	if len(report.Versions) == 0 {
+		_, err := fmt.Fprintf(w, "No retired UIDs in %s\n", report.ModelFile)
+		return err
+	}
+
+	var tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for k, version := range report.Versions {
+		if k > 0 {
+			fmt.Fprintln(tw)
+		}
+		if version.Version == 0 {
+			fmt.Fprintln(tw, "Unknown version (retired before versions were recorded):")
+		} else {
+			fmt.Fprintf(tw, "Version %d:\n", version.Version)
+		}
+		for _, element := range version.Elements {
+			var name = element.Name
+			if len(name) == 0 {
+				name = "(unknown name)"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\tUID %d\n", element.Kind, name, element.Uid)
+		}
+	}
+	return tw.Flush()
+}
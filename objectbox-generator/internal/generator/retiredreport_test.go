@@ -0,0 +1,131 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+// retiredNames is a RetiredNameResolver with fixed names
+type retiredNames map[model.Uid]string
+
+func (names retiredNames) ResolveRetiredNames(modelFile string, uids []model.Uid) (map[model.Uid]string, error) {
+	return names, nil
+}
+
+func TestRetiredReport(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "retired-report")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var options = generator.Options{
+		InPath:        schemaFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\ntable Tag {\n id: ulong;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+
+	var modelFile = generator.ModelInfoFile(dir)
+	report, err := generator.ReportRetired(generator.Options{InPath: dir}, nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(report.Versions))
+
+	// the names are only available in the git history once retired
+	var hasGit = true
+	if _, err := exec.LookPath("git"); err != nil {
+		hasGit = false
+	} else {
+		for _, args := range [][]string{{"init", "-q"}, {"add", "objectbox-model.json"}, {"commit", "-q", "-m", "initial model"}} {
+			var git = exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			if out, err := git.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %s %s", args, err, out)
+			}
+		}
+	}
+
+	storedModel, err := model.LoadModelFromJSONFile(modelFile)
+	assert.NoErr(t, err)
+	var names = map[string]model.Uid{}
+	var addName = func(name string, id model.IdUid) {
+		uid, err := id.GetUid()
+		assert.NoErr(t, err)
+		names[name] = uid
+	}
+	for _, entity := range storedModel.Entities {
+		addName(entity.Name, entity.Id)
+		for _, property := range entity.Properties {
+			addName(entity.Name+"."+property.Name, property.Id)
+		}
+	}
+	storedModel.Version = 3
+	storedModel.RetiredIndexUids = append(storedModel.RetiredIndexUids, 42) // retired before versions were recorded
+	assert.NoErr(t, storedModel.Write())
+	assert.NoErr(t, storedModel.Close())
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n /// objectbox:retired\n text: string;\n}\n"+
+		"/// objectbox:retired\ntable Tag {\n id: ulong;\n}\n"), 0600))
+	assert.NoErr(t, generator.Process(options))
+
+	report, err = generator.ReportRetired(generator.Options{InPath: modelFile}, retiredNames{names["Tag"]: "Tag", 42: "Task.done"})
+	assert.NoErr(t, err)
+	assert.Eq(t, modelFile, report.ModelFile)
+	assert.Eq(t, 2, len(report.Versions))
+	assert.Eq(t, generator.RetiredVersion{Version: 0, Elements: []generator.RetiredElement{{Uid: 42, Kind: "index", Name: "Task.done"}}}, report.Versions[0])
+	assert.Eq(t, 3, report.Versions[1].Version)
+	assert.Eq(t, 3, len(report.Versions[1].Elements))
+	assert.Eq(t, generator.RetiredElement{Uid: names["Tag"], Kind: "entity", Name: "Tag"}, report.Versions[1].Elements[0])
+	for _, element := range report.Versions[1].Elements[1:] {
+		assert.Eq(t, "property", element.Kind)
+		assert.Eq(t, "", element.Name)
+	}
+
+	if hasGit {
+		report, err = generator.ReportRetired(generator.Options{InPath: dir}, generator.GitNameResolver{})
+		assert.NoErr(t, err)
+		var resolved = map[model.Uid]string{}
+		for _, version := range report.Versions {
+			for _, element := range version.Elements {
+				resolved[element.Uid] = element.Name
+			}
+		}
+		assert.Eq(t, map[model.Uid]string{names["Tag"]: "Tag", names["Tag.id"]: "Tag.id", names["Task.text"]: "Task.text", 42: ""}, resolved)
+
+		var b bytes.Buffer
+		assert.NoErr(t, report.Write(&b))
+		assert.True(t, strings.HasPrefix(b.String(), "Unknown version (retired before versions were recorded):\n  index "))
+		assert.True(t, strings.Contains(b.String(), "(unknown name)  UID 42\n\nVersion 3:\n  entity "))
+		assert.True(t, strings.Contains(b.String(), fmt.Sprintf("Task.text  UID %d\n", names["Task.text"])))
+	}
+
+	_, err = generator.ReportRetired(generator.Options{InPath: filepath.Join(dir, "missing")}, nil)
+	assert.Err(t, err)
+}
@@ -0,0 +1,75 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "schema-version")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var modelFile = generator.ModelInfoFile(dir)
+	var options = generator.Options{
+		InPath:        schemaFile,
+		ModelInfoFile: modelFile,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	assert.NoErr(t, generator.Process(options))
+
+	// unchanged schema keeps the version
+	assert.NoErr(t, generator.Process(options))
+	modelInfo, err := model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, modelInfo.SchemaVersion)
+
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte("table Task {\n id: ulong;\n text: string;\n done: bool;\n}\n"+
+		"table Note {\n id: ulong;\n}\n"), 0600))
+	assert.NoErr(t, generator.Process(options))
+	modelInfo, err = model.LoadModelReadOnly(modelFile)
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, modelInfo.SchemaVersion)
+
+	var versions []string
+	for _, element := range modelInfo.SchemaElementVersions() {
+		versions = append(versions, fmt.Sprintf("%s:%d", element.Name, element.Version))
+	}
+	assert.Eq(t, "Task:1 Task.id:1 Task.text:1 Task.done:2 Note:2 Note.id:2", strings.Join(versions, " "))
+
+	header, err := ioutil.ReadFile(filepath.Join(dir, "objectbox-model.h"))
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(header), "#define OBX_SCHEMA_VERSION 2\n"))
+	assert.True(t, strings.Contains(string(header), "#define OBX_SCHEMA_ADDED_IN_Task_done 2\n"))
+}
@@ -0,0 +1,179 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Output formats supported by ProcessStream()
+const (
+	StreamFormatJSON = "json"
+	StreamFormatTar  = "tar"
+)
+
+// StreamedFile is a single file in the output of ProcessStream()
+type StreamedFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// ProcessStream runs Process() without side effects on the file system (apart from a temporary directory) and writes
+// all the generated files, including the updated model JSON, to out: either as a JSON envelope ({"files": [...]})
+// or as a tar archive, depending on the given format. This is meant for build sandboxes, e.g. Bazel.
+// If source is not nil, it's read as a single source file called sourceName; otherwise options.InPath is used.
+// An existing model JSON file (options.ModelInfoFile or the default one next to the sources) is only read.
+func ProcessStream(options Options, source io.Reader, sourceName string, out io.Writer, format string) error {
+	if err := options.normalizePaths(); err != nil {
+		return err
+	}
+
+	if format != StreamFormatJSON && format != StreamFormatTar {
+		return fmt.Errorf("unknown output format %q, expecting one of: %s, %s", format, StreamFormatJSON, StreamFormatTar)
+	}
+
+	tempDir, err := ioutil.TempDir("", "objectbox-generator-stream")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if source != nil {
+		options.InPath = filepath.Join(tempDir, "in", filepath.Base(sourceName))
+		var recognized bool
+		for _, codeGenerator := range options.Targets() {
+			recognized = recognized || codeGenerator.IsSourceFile(options.InPath)
+		}
+		if !recognized {
+			return fmt.Errorf("%s is not recognized as a source file by the selected generator", sourceName)
+		}
+		if err = os.Mkdir(filepath.Dir(options.InPath), 0700); err != nil {
+			return err
+		}
+		data, err := ioutil.ReadAll(source)
+		if err != nil {
+			return fmt.Errorf("can't read the source: %s", err)
+		}
+		if err = ioutil.WriteFile(options.InPath, data, 0600); err != nil {
+			return err
+		}
+	} else if PathIsDirOrPattern(options.InPath) {
+		return fmt.Errorf("streaming the output is only supported for a single source file, given %s", options.InPath)
+	}
+
+	if len(options.ModelInfoFile) == 0 && source == nil {
+		options.ModelInfoFile = ModelInfoFile(filepath.Dir(options.InPath))
+	}
+
+	files, err := processToDir(options, filepath.Join(tempDir, "out"))
+	if err != nil {
+		return err
+	}
+
+	if format == StreamFormatTar {
+		return writeTar(out, files)
+	}
+
+	var envelope = struct {
+		Files []StreamedFile `json:"files"`
+	}{files}
+	var encoder = json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(envelope)
+}
+
+// processToDir runs Process() with all the output, including a copy of the stored model JSON (if any), redirected to
+// the given (new) directory and returns the generated files, sorted by name
+func processToDir(options Options, outDir string) ([]StreamedFile, error) {
+	if err := os.Mkdir(outDir, 0700); err != nil {
+		return nil, err
+	}
+
+	// start with a copy of the stored model JSON so that the IDs/UIDs stay stable
+	var modelInfoFile = ModelInfoFile(outDir)
+	if len(options.ModelInfoFile) > 0 {
+		if data, err := ioutil.ReadFile(options.ModelInfoFile); err == nil {
+			if err = ioutil.WriteFile(modelInfoFile, data, 0600); err != nil {
+				return nil, err
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("can't read the model JSON file: %s", err)
+		}
+	}
+
+	options.ModelInfoFile = modelInfoFile
+	options.OutPath = outDir
+	options.OutHeadersPath = ""
+	options.ManifestFile = "" // would only list temporary files
+	if len(options.AdminMetadataFile) > 0 {
+		options.AdminMetadataFile = filepath.Join(outDir, filepath.Base(options.AdminMetadataFile)) // streamed as well
+	}
+	options.BundleFile = ""
+	if err := Process(options); err != nil {
+		return nil, err
+	}
+
+	var files []StreamedFile
+	err := filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(outDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, StreamedFile{Name: filepath.ToSlash(name), Content: string(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't collect the generated files: %s", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+func writeTar(out io.Writer, files []StreamedFile) error {
+	var writer = tar.NewWriter(out)
+	for _, file := range files {
+		var header = &tar.Header{Name: file.Name, Mode: 0644, Size: int64(len(file.Content))}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := writer.Write([]byte(file.Content)); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
@@ -0,0 +1,84 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestProcessStream(t *testing.T) {
+	var source = "table Task {\n id: ulong;\n text: string;\n}\n"
+	var options = generator.Options{CodeGenerator: &cgenerator.CGenerator{LangVersion: 14}}
+
+	var out bytes.Buffer
+	assert.NoErr(t, generator.ProcessStream(options, strings.NewReader(source), "tasks.fbs", &out, generator.StreamFormatJSON))
+
+	var envelope struct {
+		Files []generator.StreamedFile
+	}
+	assert.NoErr(t, json.Unmarshal(out.Bytes(), &envelope))
+	var names []string
+	for _, file := range envelope.Files {
+		names = append(names, file.Name)
+		assert.True(t, len(file.Content) > 0)
+	}
+	assert.Eq(t, "objectbox-model.h objectbox-model.json tasks.obx.cpp tasks.obx.hpp", strings.Join(names, " "))
+
+	// the same as a tar archive, with a stored model JSON file being used (but not changed)
+	dir, remove := fixture.TempDir(t, "stream-test")
+	defer remove()
+	options.ModelInfoFile = filepath.Join(dir, "objectbox-model.json")
+	assert.NoErr(t, ioutil.WriteFile(options.ModelInfoFile, []byte(envelope.Files[1].Content), 0600))
+
+	out.Reset()
+	assert.NoErr(t, generator.ProcessStream(options, strings.NewReader(source), "tasks.fbs", &out, generator.StreamFormatTar))
+	var reader = tar.NewReader(&out)
+	for _, expected := range envelope.Files {
+		header, err := reader.Next()
+		assert.NoErr(t, err)
+		assert.Eq(t, expected.Name, header.Name)
+		content, err := ioutil.ReadAll(reader)
+		assert.NoErr(t, err)
+		assert.Eq(t, expected.Content, string(content))
+	}
+	_, err := reader.Next()
+	assert.Eq(t, io.EOF, err)
+
+	// nothing has been written next to the stored model
+	files, err := ioutil.ReadDir(dir)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(files))
+
+	assert.Err(t, generator.ProcessStream(options, strings.NewReader(source), "tasks.txt", &out, generator.StreamFormatJSON))
+	assert.Err(t, generator.ProcessStream(options, strings.NewReader(source), "tasks.fbs", &out, "zip"))
+}
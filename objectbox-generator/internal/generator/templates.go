@@ -0,0 +1,205 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TemplateVersion computes an identifier of the given templates: a (shortened) hash of their parsed source.
+// Generated code only changes with the generator or its templates, so it's suitable for build cache keys.
+func TemplateVersion(templates ...*template.Template) string {
+	var hash = sha256.New()
+	for _, tpl := range templates {
+		// associated templates (i.e. {{define}} blocks) are kept in a map, sort them for a stable result
+		var associated = tpl.Templates()
+		sort.Slice(associated, func(i, j int) bool {
+			return associated[i].Name() < associated[j].Name()
+		})
+		for _, t := range associated {
+			hash.Write([]byte(t.Name()))
+			hash.Write([]byte{0})
+			if t.Tree != nil && t.Tree.Root != nil {
+				hash.Write([]byte(t.Tree.Root.String()))
+			}
+			hash.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// OverrideTemplates applies user-provided templates from the given directory to (clones of) the given templates.
+// Each "*.tmpl" file in the directory is parsed into every template: a file named after a template (e.g. "model.tmpl")
+// replaces that template completely, while any other file may {{define}} blocks the templates provide as hooks,
+// such as "file-header" and "file-footer". If dir is empty, the templates are returned unchanged.
+func OverrideTemplates(dir string, templates ...*template.Template) ([]*template.Template, error) {
+	if len(dir) == 0 {
+		return templates, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("can't list template overrides in %s: %s", dir, err)
+	} else if len(files) == 0 {
+		return nil, fmt.Errorf("no template overrides (*.tmpl files) found in %s", dir)
+	}
+	sort.Strings(files)
+
+	var result = make([]*template.Template, len(templates))
+	for i, tpl := range templates {
+		clone, err := tpl.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("can't clone template %s: %s", tpl.Name(), err)
+		}
+
+		for _, file := range files {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("can't read template override %s: %s", file, err)
+			}
+			var name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			if _, err = clone.New(name).Parse(string(content)); err != nil {
+				return nil, fmt.Errorf("invalid template override %s: %s", file, err)
+			}
+		}
+
+		// clone.New() with the same name as the original replaces it in the set of associated templates
+		result[i] = clone.Lookup(tpl.Name())
+	}
+	return result, nil
+}
+
+// loadedTemplates is a LoadTemplates() result, valid as long as the override files have the same contents
+type loadedTemplates struct {
+	overridesHash [sha256.Size]byte
+	templates     []*template.Template
+	version       string
+}
+
+// templatesCache keeps the LoadTemplates() results, keyed by the overrides directory and the templates, so that
+// generating hundreds of files doesn't parse the overrides and compute the templates version for each of them
+var templatesCache = struct {
+	sync.Mutex
+	entries map[string]*loadedTemplates
+}{entries: make(map[string]*loadedTemplates)}
+
+// LoadTemplates returns the given templates with overrides from the given directory applied (see OverrideTemplates())
+// and their version (see TemplateVersion()). The result is cached and reused until the override files change, e.g.
+// between requests of a long-running server, so the code generators can call it for each generated file.
+func LoadTemplates(overridesDir string, templates ...*template.Template) ([]*template.Template, string, error) {
+	var key = overridesDir
+	for _, tpl := range templates {
+		key += fmt.Sprintf("\x00%p", tpl)
+	}
+
+	var overridesHash [sha256.Size]byte
+	if len(overridesDir) > 0 {
+		var err error
+		if overridesHash, err = hashTemplateOverrides(overridesDir); err != nil {
+			return nil, "", err
+		}
+	}
+
+	templatesCache.Lock()
+	defer templatesCache.Unlock()
+
+	if entry, found := templatesCache.entries[key]; found && entry.overridesHash == overridesHash {
+		return entry.templates, entry.version, nil
+	}
+
+	result, err := OverrideTemplates(overridesDir, templates...)
+	if err != nil {
+		return nil, "", err
+	}
+	var entry = &loadedTemplates{overridesHash: overridesHash, templates: result, version: TemplateVersion(result...)}
+	templatesCache.entries[key] = entry
+	return entry.templates, entry.version, nil
+}
+
+// hashTemplateOverrides computes a hash of the names and contents of the "*.tmpl" files in the given directory
+func hashTemplateOverrides(dir string) (result [sha256.Size]byte, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return result, fmt.Errorf("can't list template overrides in %s: %s", dir, err)
+	}
+	sort.Strings(files)
+
+	var hash = sha256.New()
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return result, fmt.Errorf("can't read template override %s: %s", file, err)
+		}
+		hash.Write([]byte(filepath.Base(file)))
+		hash.Write([]byte{0})
+		hash.Write(content)
+		hash.Write([]byte{0})
+	}
+	copy(result[:], hash.Sum(nil))
+	return result, nil
+}
+
+// templateBuffers are reused by ExecuteTemplate(); generated files are similar in size, so after a few files the
+// buffers don't need to grow anymore while executing
+var templateBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// maxPooledTemplateBuffer limits the capacity of the buffers returned to the pool, so that a single huge file doesn't
+// keep its memory allocated
+const maxPooledTemplateBuffer = 1 << 20
+
+// ExecutableTemplate is a template ExecuteTemplate() can run, i.e. a text/template or an html/template one
+type ExecutableTemplate interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// ExecuteTemplate executes the template with the given data and returns the generated source
+func ExecuteTemplate(tpl ExecutableTemplate, data interface{}) ([]byte, error) {
+	var buffer = templateBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		if buffer.Cap() <= maxPooledTemplateBuffer {
+			buffer.Reset()
+			templateBuffers.Put(buffer)
+		}
+	}()
+
+	if err := tpl.Execute(buffer, data); err != nil {
+		return nil, err
+	}
+
+	// the buffer is reused, the caller gets a copy of exactly the generated size
+	var result = make([]byte, buffer.Len())
+	copy(result, buffer.Bytes())
+	return result, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestTemplateVersion(t *testing.T) {
+	var tplA = template.Must(template.New("a").Parse(`{{define "inner"}}x{{end}}{{template "inner"}}`))
+	var tplB = template.Must(template.New("b").Parse(`{{define "inner"}}y{{end}}{{template "inner"}}`))
+
+	var versionA = generator.TemplateVersion(tplA)
+	assert.Eq(t, 16, len(versionA))
+	assert.Eq(t, versionA, generator.TemplateVersion(tplA)) // stable
+	assert.True(t, versionA != generator.TemplateVersion(tplB))
+	assert.True(t, versionA != generator.TemplateVersion(tplA, tplB))
+
+	for _, gen := range []generator.CodeGenerator{&cgenerator.CGenerator{}, &gogenerator.GoGenerator{}, &jsgenerator.JSGenerator{}, &docsgenerator.DocsGenerator{}} {
+		assert.Eq(t, 16, len(gen.TemplateVersion()))
+	}
+}
+
+func TestTemplateOverrides(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "templates")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var overridesDir = filepath.Join(dir, "templates")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n text: string;\n}\n")
+	assert.NoErr(t, os.Mkdir(overridesDir, 0700))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(overridesDir, "company.tmpl"), []byte(
+		`{{define "file-header"}}`+"\n// Copyright ACME Inc.\n"+`{{end}}{{define "file-footer"}}`+"\n// end of generated code\n"+`{{end}}`), 0600))
+
+	var gen = &cgenerator.CGenerator{PlainC: true}
+	var options = generator.Options{
+		InPath:               schemaFile,
+		ModelInfoFile:        generator.ModelInfoFile(dir),
+		TemplateOverridesDir: overridesDir,
+		CodeGenerator:        gen,
+	}
+	assert.NoErr(t, generator.Process(options))
+
+	bindingFiles, err := gen.BindingFiles(schemaFile, options)
+	assert.NoErr(t, err)
+	for _, file := range []string{bindingFiles[0], gen.ModelFile(options.ModelInfoFile, options)} {
+		source, err := ioutil.ReadFile(file)
+		assert.NoErr(t, err)
+		assert.True(t, strings.Contains(string(source), "// Copyright ACME Inc."))
+		assert.True(t, strings.HasSuffix(string(source), "// end of generated code\n"))
+		// the template version reflects the overrides
+		assert.True(t, !strings.Contains(string(source), gen.TemplateVersion()))
+	}
+
+	// the templates are compiled once and reused, until the overrides change
+	var tpl = template.Must(template.New("tpl").Parse(`{{block "file-header" .}}{{end}}`))
+	first, version, err := generator.LoadTemplates(overridesDir, tpl)
+	assert.NoErr(t, err)
+	second, secondVersion, err := generator.LoadTemplates(overridesDir, tpl)
+	assert.NoErr(t, err)
+	assert.True(t, first[0] == second[0])
+	assert.Eq(t, version, secondVersion)
+
+	fixture.WriteFile(t, filepath.Join(overridesDir, "company.tmpl"), `{{define "file-header"}}// Copyright ACME Corp.{{end}}`)
+	second, secondVersion, err = generator.LoadTemplates(overridesDir, tpl)
+	assert.NoErr(t, err)
+	assert.True(t, first[0] != second[0])
+	assert.NotEq(t, version, secondVersion)
+	source, err := generator.ExecuteTemplate(second[0], nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, "// Copyright ACME Corp.", string(source))
+
+	assert.NoErr(t, generator.Process(options))
+	source, err = ioutil.ReadFile(bindingFiles[0])
+	assert.NoErr(t, err)
+	assert.True(t, strings.Contains(string(source), "// Copyright ACME Corp."))
+
+	fixture.WriteFile(t, filepath.Join(overridesDir, "invalid.tmpl"), `{{if}}`)
+	assert.Err(t, generator.Process(options))
+}
@@ -0,0 +1,149 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// EntityRenamer may be implemented by a CodeGenerator whose generated code declares the entity types, e.g. from a
+// FlatBuffers schema, so that entities can be renamed after parsing the sources, see Options.TenantPrefix.
+// Code generators binding types declared in the sources (e.g. Go structs) can't support it.
+type EntityRenamer interface {
+	RenameEntity(entity *model.Entity, name string)
+}
+
+var tenantPrefixRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// parseSource parses the given source file, applying the Options.TenantPrefix
+func parseSource(options Options, filePath string) (*model.ModelInfo, error) {
+	currentModel, err := options.CodeGenerator.ParseSource(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyTenantPrefix(options, currentModel); err != nil {
+		return nil, err
+	}
+	return currentModel, nil
+}
+
+// checkTenantPrefix verifies the Options.TenantPrefix can be used with the stored model and records it there.
+// Changing the prefix of an existing model would replace all its entities (dropping their data), so it's an error.
+func checkTenantPrefix(options Options, modelInfo *model.ModelInfo) error {
+	if len(options.TenantPrefix) > 0 {
+		if !tenantPrefixRegexp.MatchString(options.TenantPrefix) {
+			return messages.InvalidTenantPrefix.Errorf(options.TenantPrefix)
+		}
+		for _, codeGenerator := range options.Targets() {
+			if _, ok := codeGenerator.(EntityRenamer); !ok {
+				return messages.TenantPrefixUnsupported.Errorf(codeGenerator)
+			}
+		}
+	}
+
+	if modelInfo.TenantPrefix != options.TenantPrefix && len(modelInfo.Entities) > 0 {
+		return messages.TenantPrefixChanged.Errorf(options.ModelInfoFile, modelInfo.TenantPrefix, options.TenantPrefix)
+	}
+	modelInfo.TenantPrefix = options.TenantPrefix
+	return nil
+}
+
+// applyTenantPrefix prefixes the names of the entities parsed from a source, including the relations and backlinks
+// referring to them. With Options.DeterministicUids, where all UIDs are derived from the schema, the UIDs pinned in the
+// source are derived for the tenant as well, so that the variants of the same schema don't collide when their models
+// are merged (see Options.ModuleModelFiles).
+func applyTenantPrefix(options Options, currentModel *model.ModelInfo) error {
+	var prefix = options.TenantPrefix
+	if len(prefix) == 0 {
+		return nil
+	}
+	renamer, ok := options.CodeGenerator.(EntityRenamer)
+	if !ok {
+		return messages.TenantPrefixUnsupported.Errorf(options.CodeGenerator)
+	}
+
+	var tenantIdUid = func(idUid *model.IdUid) error {
+		if len(*idUid) == 0 || !options.DeterministicUids {
+			return nil
+		}
+		id, err := idUid.GetIdAllowZero()
+		if err != nil {
+			return err
+		}
+		uid, err := idUid.GetUidAllowZero()
+		if err != nil {
+			return err
+		} else if uid != 0 {
+			*idUid = model.CreateIdUid(id, model.TenantUid(prefix, uid))
+		}
+		return nil
+	}
+
+	var renamed = make(map[*model.Entity]bool)
+	for _, entity := range currentModel.Entities {
+		renamed[entity] = true
+	}
+
+	for _, entity := range currentModel.Entities {
+		renamer.RenameEntity(entity, prefix+entity.Name)
+		if err := tenantIdUid(&entity.Id); err != nil {
+			return fmt.Errorf("entity %s: %s", entity.Name, err)
+		}
+
+		for _, property := range entity.Properties {
+			if len(property.RelationTarget) > 0 {
+				property.RelationTarget = prefix + property.RelationTarget
+			}
+			if err := tenantIdUid(&property.Id); err != nil {
+				return fmt.Errorf("property %s.%s: %s", entity.Name, property.Name, err)
+			}
+		}
+
+		for _, relation := range entity.Relations {
+			// targets are usually placeholders (with just a name) until merged
+			if relation.Target != nil && !renamed[relation.Target] {
+				relation.Target.Name = prefix + relation.Target.Name
+			}
+			if err := tenantIdUid(&relation.Id); err != nil {
+				return fmt.Errorf("relation %s.%s: %s", entity.Name, relation.Name, err)
+			}
+		}
+
+		for _, backlink := range entity.Backlinks {
+			backlink.SourceName = prefix + backlink.SourceName
+		}
+
+		if options.DeterministicUids {
+			for i, uid := range entity.Reserved.Uids {
+				entity.Reserved.Uids[i] = model.TenantUid(prefix, uid)
+			}
+		}
+	}
+
+	for i, name := range currentModel.RetiredEntityNames {
+		currentModel.RetiredEntityNames[i] = prefix + name
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestTenantPrefix(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "tenants")
+	defer remove()
+
+	var schema = "table Task {\n id: ulong;\n /// objectbox:relation=Project\n projectId: ulong;\n}\n" +
+		"/// objectbox:uid=4918476352198012345\ntable Project {\n id: ulong;\n name: string;\n}\n"
+
+	// see test/comparison/testdata/fbs/tenant-prefix for the prefixed names in the generated code;
+	// each tenant gets its own variant of the same schema, with its own model
+	var generateTenant = func(name, prefix string, moduleModels ...string) (string, error) {
+		assert.NoErr(t, os.MkdirAll(filepath.Join(dir, name), 0700))
+		var schemaFile = filepath.Join(dir, name, "schema.fbs")
+		assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(schema), 0600))
+		return generator.ModelInfoFile(filepath.Join(dir, name)), generator.Process(generator.Options{
+			InPath:            schemaFile,
+			TenantPrefix:      prefix,
+			DeterministicUids: true,
+			ModuleModelFiles:  moduleModels,
+			CodeGenerator:     &cgenerator.CGenerator{LangVersion: 14},
+		})
+	}
+	acmeModel, err := generateTenant("acme", "Acme_")
+	assert.NoErr(t, err)
+	globexModel, err := generateTenant("globex", "Globex_", acmeModel)
+	assert.NoErr(t, err)
+
+	acme, err := model.LoadModelFromJSONFile(acmeModel)
+	assert.NoErr(t, err)
+	assert.Eq(t, "Acme_", acme.TenantPrefix)
+	task, err := acme.FindEntityByName("Acme_Task")
+	assert.NoErr(t, err)
+	assert.Eq(t, "Acme_Project", task.Properties[1].RelationTarget)
+
+	// the merged model contains both variants, with distinct UIDs, even for the pinned one
+	globex, err := model.LoadModelFromJSONFile(globexModel)
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(globex.Entities))
+	acmeProject, err := globex.FindEntityByName("Acme_Project")
+	assert.NoErr(t, err)
+	globexProject, err := globex.FindEntityByName("Globex_Project")
+	assert.NoErr(t, err)
+	acmeUid, err := acmeProject.Id.GetUid()
+	assert.NoErr(t, err)
+	globexUid, err := globexProject.Id.GetUid()
+	assert.NoErr(t, err)
+	assert.Eq(t, model.TenantUid("Acme_", 4918476352198012345), acmeUid)
+	assert.Eq(t, model.TenantUid("Globex_", 4918476352198012345), globexUid)
+
+	// merging a variant for the same tenant collides
+	_, err = generateTenant("globex-copy", "Globex_", acmeModel, globexModel)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), `same tenant prefix "Globex_"`))
+
+	// a model can't change the tenant
+	var options = generator.Options{
+		InPath:        filepath.Join(dir, "acme", "schema.fbs"),
+		TenantPrefix:  "Initech_",
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+	err = generator.Process(options)
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), `was generated with tenant prefix "Acme_"`))
+
+	// Go binds the structs declared in the sources so the entities can't be renamed
+	var goFile = filepath.Join(dir, "task.go")
+	fixture.WriteFile(t, goFile, "package tenants\n\ntype Task struct {\n\tId uint64\n}\n")
+	err = generator.Process(generator.Options{InPath: goFile, TenantPrefix: "Acme_", CodeGenerator: &gogenerator.GoGenerator{}})
+	assert.Err(t, err)
+	assert.True(t, strings.Contains(err.Error(), "tenant prefix isn't supported"))
+}
@@ -0,0 +1,198 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a unified diff
+const diffContext = 3
+
+// diffOp is a single line of an edit script: kind is ' ' (unchanged), '-' (removed) or '+' (added);
+// a and b are the (0-based) positions in the old and the new text, respectively
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// UnifiedDiff returns the differences between the old and the new text in the unified diff format, as printed by
+// `diff -u` (without timestamps), or an empty string if the texts are equal.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	var a, b = splitLines(oldText), splitLines(newText)
+	var ops = diffLines(a, b)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(ops); {
+		// find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// extend the hunk as long as the changes are close enough to share the context
+		var end = start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		var from, to = start - diffContext, end + diffContext
+		if from < 0 {
+			from = 0
+		}
+		if to > len(ops) {
+			to = len(ops)
+		}
+		writeHunk(&result, a, b, ops[from:to])
+		start = to
+	}
+	return result.String()
+}
+
+func writeHunk(result *strings.Builder, a, b []string, ops []diffOp) {
+	var oldCount, newCount int
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+
+	// an empty range is given by the line before it
+	var oldStart, newStart = ops[0].a, ops[0].b
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	fmt.Fprintf(result, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, op := range ops {
+		var line string
+		if op.kind == '+' {
+			line = b[op.b]
+		} else {
+			line = a[op.a]
+		}
+		result.WriteByte(op.kind)
+		result.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			result.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// splitLines splits the text after each newline, keeping it, so that a missing newline at the end is a difference
+func splitLines(text string) []string {
+	var lines = strings.SplitAfter(text, "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the shortest edit script transforming a to b, using the Myers' diff algorithm
+func diffLines(a, b []string) []diffOp {
+	var n, m = len(a), len(b)
+	var max = n + m
+	var offset = max + 1
+	var v = make([]int, 2*max+3)
+	var trace [][]int
+
+	// forward: find the furthest reaching path for each number of edits d
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		var done bool
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // down, i.e. an insertion
+			} else {
+				x = v[offset+k-1] + 1 // right, i.e. a deletion
+			}
+			var y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	// backward: collect the edits from the end
+	var ops []diffOp
+	var x, y = n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v = trace[d]
+		var k = x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		var prevX = v[offset+prevK]
+		var prevY = prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, diffOp{'+', x, y})
+			} else {
+				x--
+				ops = append(ops, diffOp{'-', x, y})
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,101 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// OutdatedFile describes a generated file which doesn't match the current generator, see CheckVersions()
+type OutdatedFile struct {
+	Path             string `json:"path"`
+	GeneratorVersion int    `json:"generatorVersion,omitempty"` // the embedded VersionId, if the file contains one
+	TemplateVersion  string `json:"templateVersion,omitempty"`  // the embedded templates version, if the file contains one
+	Reason           string `json:"reason"`
+}
+
+func (file OutdatedFile) String() string {
+	return file.Path + ": " + file.Reason
+}
+
+// the Go binding (GeneratorVersion() method) and Go model (model.GeneratorVersion() call) embed the VersionId
+var generatorVersionRegexp = regexp.MustCompile(`GeneratorVersion\(\)\s*int\s*{\s*return\s+(\d+)|model\.GeneratorVersion\((\d+)\)`)
+
+// all generated files start with a header including the templates version
+var templateVersionRegexp = regexp.MustCompile(`ObjectBox Generator templates version: ([0-9a-f]+)`)
+
+// CheckVersions scans the generated files (in the output path, or the source path if no output path is configured)
+// and lists those generated by a different generator version (VersionId) or using different templates.
+// The Go generated files embed the VersionId; templates versions are only compared without template overrides.
+func CheckVersions(options Options) ([]OutdatedFile, error) {
+	if err := options.normalizePaths(); err != nil {
+		return nil, err
+	}
+
+	var path = options.OutPath
+	if len(path) == 0 {
+		path = options.InPath
+	}
+	if !PathIsDirOrPattern(path) {
+		path = filepath.Dir(path)
+	}
+
+	var templateVersion = options.CodeGenerator.TemplateVersion()
+	var outdated []OutdatedFile
+	err := pathForEach(path, func(filePath string) error {
+		if !options.CodeGenerator.IsGeneratedFile(filePath) {
+			return nil
+		}
+		source, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("can't read generated file %s: %s", filePath, err)
+		}
+
+		var file = OutdatedFile{Path: options.FormatPath(filePath)}
+		if match := generatorVersionRegexp.FindSubmatch(source); match != nil {
+			var version = string(match[1]) + string(match[2])
+			if file.GeneratorVersion, err = strconv.Atoi(version); err != nil {
+				return fmt.Errorf("invalid generator version %s in %s: %s", version, filePath, err)
+			}
+			if file.GeneratorVersion != VersionId {
+				file.Reason = fmt.Sprintf("generated by generator version #%d, the current version is #%d", file.GeneratorVersion, VersionId)
+			}
+		}
+		if match := templateVersionRegexp.FindSubmatch(source); match != nil {
+			file.TemplateVersion = string(match[1])
+			if len(file.Reason) == 0 && len(options.TemplateOverridesDir) == 0 && file.TemplateVersion != templateVersion {
+				file.Reason = fmt.Sprintf("generated using templates version %s, the current version is %s", file.TemplateVersion, templateVersion)
+			}
+		}
+		if len(file.Reason) > 0 {
+			outdated = append(outdated, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outdated, nil
+}
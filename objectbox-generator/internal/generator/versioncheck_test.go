@@ -0,0 +1,102 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestVersionCheck(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "version-check")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	fixture.WriteFile(t, schemaFile, "table Task {\n id: ulong;\n}\n")
+
+	var options = generator.Options{
+		InPath:        schemaFile,
+		OutPath:       dir,
+		CodeGenerator: &cgenerator.CGenerator{LangVersion: 14},
+	}
+
+	// nothing generated yet
+	outdated, err := generator.CheckVersions(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(outdated))
+
+	assert.NoErr(t, generator.Process(options))
+	outdated, err = generator.CheckVersions(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(outdated))
+
+	// files generated using different templates
+	var hppFile = filepath.Join(dir, "schema.obx.hpp")
+	source, err := ioutil.ReadFile(hppFile)
+	assert.NoErr(t, err)
+	var templateVersion = options.CodeGenerator.TemplateVersion()
+	source = bytes.Replace(source, []byte(templateVersion), []byte("0123456789abcdef"), 1)
+	assert.NoErr(t, ioutil.WriteFile(hppFile, source, 0600))
+
+	outdated, err = generator.CheckVersions(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(outdated))
+	assert.Eq(t, hppFile, outdated[0].Path)
+	assert.Eq(t, "0123456789abcdef", outdated[0].TemplateVersion)
+	assert.Eq(t, hppFile+": generated using templates version 0123456789abcdef, the current version is "+templateVersion, outdated[0].String())
+
+	// regenerating brings the file up to date
+	assert.NoErr(t, generator.Process(options))
+	outdated, err = generator.CheckVersions(options)
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(outdated))
+
+	// Go files embed the generator version
+	var goDir = filepath.Join(dir, "go")
+	assert.NoErr(t, os.Mkdir(goDir, 0700))
+	var goGen = &gogenerator.GoGenerator{}
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(goDir, "objectbox-model.go"), []byte(fmt.Sprintf(`// Code generated by ObjectBox; DO NOT EDIT.
+// ObjectBox Generator templates version: %s
+package model
+
+func ObjectBoxModel() *objectbox.Model {
+	model := objectbox.NewModel()
+	model.GeneratorVersion(%d)
+	return model
+}
+`, goGen.TemplateVersion(), generator.VersionId-1)), 0600))
+
+	outdated, err = generator.CheckVersions(generator.Options{InPath: goDir, CodeGenerator: goGen})
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(outdated))
+	assert.Eq(t, generator.VersionId-1, outdated[0].GeneratorVersion)
+	assert.Eq(t, fmt.Sprintf("generated by generator version #%d, the current version is #%d", generator.VersionId-1, generator.VersionId), outdated[0].Reason)
+}
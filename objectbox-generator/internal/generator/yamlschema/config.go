@@ -0,0 +1,86 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package yamlschema
+
+import (
+	"fmt"
+)
+
+// Setting is a single key of a YAML settings file (e.g. the generator config file) with its value(s)
+type Setting struct {
+	Section string   // the key of the enclosing mapping, e.g. "lint", or empty for top-level settings
+	Key     string   // e.g. "out"
+	Values  []string // a single value unless given as a sequence, e.g. `module-model: [a.json, b.json]`
+	Line    int      // position of the key in the file (1-based)
+	Column  int
+}
+
+// ParseSettings reads a YAML mapping of settings with scalar or sequence values; a top-level key may also hold a
+// mapping (one level deep), its keys are returned with the Section set. The file name is used in error messages.
+func ParseSettings(file string, source []byte) ([]Setting, error) {
+	root, err := parseYaml(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s", file, err)
+	}
+	if root.kind != mappingNode {
+		return nil, fmt.Errorf("%s:%d:%d: expecting a mapping of settings, e.g. `out: generated`", file, root.line, root.column)
+	}
+
+	var result []Setting
+	for _, key := range root.keys {
+		var value = root.values[key]
+		if value.kind != mappingNode {
+			setting, err := parseSetting(file, "", key, root.keyPos[key], value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, setting)
+			continue
+		}
+
+		for _, nestedKey := range value.keys {
+			setting, err := parseSetting(file, key, nestedKey, value.keyPos[nestedKey], value.values[nestedKey])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, setting)
+		}
+	}
+	return result, nil
+}
+
+func parseSetting(file, section, key string, pos *node, value *node) (Setting, error) {
+	var setting = Setting{Section: section, Key: key, Line: pos.line, Column: pos.column}
+	switch value.kind {
+	case scalarNode:
+		setting.Values = []string{value.value}
+	case sequenceNode:
+		for _, item := range value.items {
+			if item.kind != scalarNode {
+				return setting, fmt.Errorf("%s:%d:%d: %s: expecting a sequence of values", file, item.line, item.column, key)
+			}
+			setting.Values = append(setting.Values, item.value)
+		}
+	default:
+		return setting, fmt.Errorf("%s:%d:%d: %s: mappings can't be nested in this section", file, value.line, value.column, key)
+	}
+	return setting, nil
+}
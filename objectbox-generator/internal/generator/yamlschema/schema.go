@@ -0,0 +1,376 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package yamlschema converts entities declared in YAML to a FlatBuffers schema, so they can be processed by the
+// generators reading FlatBuffers schema (C, C++ and JS) without knowing the FlatBuffers syntax. For example:
+//
+//	namespace: shop
+//	entities:
+//	  - name: Customer
+//	    sync: true
+//	    properties:
+//	      - name: id
+//	        type: ulong
+//	      - name: email
+//	        type: string
+//	        unique: true
+//	      - name: created
+//	        type: date
+//	    relations:
+//	      - name: orders
+//	        to: Order
+//
+// Any other key of an entity or a property is an annotation, same as `/// objectbox:<key>=<value>` in FlatBuffers:
+// `true` results in an annotation without a value, a sequence or a mapping in details, e.g. `id: [assignable]`.
+package yamlschema
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IsSchemaFile returns true if the given file is a YAML schema, i.e. its name ends with ".schema.yaml" (or ".yml").
+// A specific suffix is required so that other YAML files, e.g. CI configuration, aren't processed as schema.
+func IsSchemaFile(file string) bool {
+	return strings.HasSuffix(file, ".schema.yaml") || strings.HasSuffix(file, ".schema.yml")
+}
+
+// types maps the types available in YAML to FlatBuffers types and the annotation (if any) they imply.
+// FlatBuffers vector types are given as a sequence, e.g. `type: [ubyte]` or `type: [float:4]` for a fixed length.
+var types = map[string][2]string{
+	"bool":      {"bool"},
+	"byte":      {"byte"},
+	"ubyte":     {"ubyte"},
+	"short":     {"short"},
+	"ushort":    {"ushort"},
+	"int":       {"int"},
+	"uint":      {"uint"},
+	"long":      {"long"},
+	"ulong":     {"ulong"},
+	"float":     {"float"},
+	"double":    {"double"},
+	"string":    {"string"},
+	"int8":      {"int8"},
+	"uint8":     {"uint8"},
+	"int16":     {"int16"},
+	"uint16":    {"uint16"},
+	"int32":     {"int32"},
+	"uint32":    {"uint32"},
+	"int64":     {"int64"},
+	"uint64":    {"uint64"},
+	"float32":   {"float32"},
+	"float64":   {"float64"},
+	"date":      {"long", "date"},
+	"date-nano": {"long", "date-nano"},
+	"bytes":     {"[ubyte]"},
+	"floats":    {"[float]"},
+	"strings":   {"[string]"},
+}
+
+// vectorElementRegexp matches the element of a vector type, optionally with a fixed length, e.g. "float:4"
+var vectorElementRegexp = regexp.MustCompile(`^(ubyte|byte|uint8|int8|float|float32|string)(:[1-9]\d*)?$`)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var namespaceRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// annotationValueRegexp matches values which can be given in an annotation without quotes
+var annotationValueRegexp = regexp.MustCompile(`^[^\s,="()]*$`)
+
+// ToFbs converts the given YAML schema to a FlatBuffers schema. The file name is used in error messages which include
+// the position in the YAML source, e.g. "shop.schema.yaml:12:9: unknown type ..."
+func ToFbs(file string, source []byte) (string, error) {
+	root, err := parseYaml(string(source))
+	if err != nil {
+		return "", fmt.Errorf("%s:%s", file, err)
+	}
+
+	var c = converter{file: file}
+	c.convert(root)
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.out.String(), nil
+}
+
+type converter struct {
+	file string
+	out  strings.Builder
+	err  error
+}
+
+func (c *converter) fail(n *node, format string, args ...interface{}) {
+	if c.err == nil {
+		c.err = fmt.Errorf("%s:%d:%d: %s", c.file, n.line, n.column, fmt.Sprintf(format, args...))
+	}
+}
+
+func (c *converter) convert(root *node) {
+	if root.kind != mappingNode {
+		c.fail(root, "expecting a mapping with `entities` at the top level")
+		return
+	}
+	c.checkKeys(root, "namespace", "entities")
+	if c.err != nil {
+		return
+	}
+
+	fmt.Fprintf(&c.out, "// Generated by ObjectBox Generator from %s, which is the source to edit\n", filepath.Base(c.file))
+
+	if namespace := root.values["namespace"]; namespace != nil {
+		if !c.isScalar(namespace, "namespace") {
+			return
+		} else if !namespaceRegexp.MatchString(namespace.value) {
+			c.fail(namespace, "invalid namespace %q", namespace.value)
+			return
+		}
+		fmt.Fprintf(&c.out, "\nnamespace %s;\n", namespace.value)
+	}
+
+	var entities = root.values["entities"]
+	if entities == nil {
+		c.fail(root, "missing `entities`")
+		return
+	} else if entities.kind != sequenceNode {
+		c.fail(entities, "`entities` must be a sequence, e.g. `- name: Customer`")
+		return
+	}
+	for _, entity := range entities.items {
+		c.convertEntity(entity)
+	}
+}
+
+func (c *converter) convertEntity(entity *node) {
+	if entity.kind != mappingNode {
+		c.fail(entity, "an entity must be a mapping with a `name` and `properties`")
+		return
+	}
+
+	var name = c.identifier(entity, "entity")
+	var properties = entity.values["properties"]
+	if c.err != nil {
+		return
+	} else if properties == nil || properties.kind != sequenceNode || len(properties.items) == 0 {
+		c.fail(entity, "entity %s: `properties` must be a non-empty sequence", name)
+		return
+	}
+
+	c.out.WriteString("\n")
+	c.writeComment(entity, "")
+
+	var annotations = c.annotations(entity, "name", "comment", "properties", "relations")
+	if relations := entity.values["relations"]; relations != nil {
+		if relations.kind != sequenceNode {
+			c.fail(relations, "entity %s: `relations` must be a sequence", name)
+			return
+		}
+		for _, relation := range relations.items {
+			annotations = append(annotations, c.relationAnnotation(relation))
+		}
+	}
+	c.writeAnnotations(annotations, "")
+
+	fmt.Fprintf(&c.out, "table %s {\n", name)
+	for _, property := range properties.items {
+		c.convertProperty(property)
+	}
+	c.out.WriteString("}\n")
+}
+
+func (c *converter) convertProperty(property *node) {
+	if property.kind != mappingNode {
+		c.fail(property, "a property must be a mapping with a `name` and a `type`")
+		return
+	}
+
+	var name = c.identifier(property, "property")
+	var typeNode = property.values["type"]
+	if c.err != nil {
+		return
+	} else if typeNode == nil {
+		c.fail(property, "property %s: missing `type`", name)
+		return
+	}
+
+	var fbsType string
+	var annotations = c.annotations(property, "name", "comment", "type")
+	if typeNode.kind == sequenceNode {
+		if len(typeNode.items) != 1 || typeNode.items[0].kind != scalarNode || !vectorElementRegexp.MatchString(typeNode.items[0].value) {
+			c.fail(typeNode, "property %s: invalid vector type, expecting e.g. [ubyte], [float], [float:4] or [string]", name)
+			return
+		}
+		fbsType = "[" + typeNode.items[0].value + "]"
+	} else if known, found := types[typeNode.value]; typeNode.kind == scalarNode && found {
+		fbsType = known[0]
+		if len(known[1]) > 0 {
+			annotations = append([]string{known[1]}, annotations...)
+		}
+	} else {
+		c.fail(typeNode, "property %s: unknown type %q, expecting one of: %s", name, typeNode.value, strings.Join(typeNames(), ", "))
+		return
+	}
+
+	c.writeComment(property, "    ")
+	c.writeAnnotations(annotations, "    ")
+	fmt.Fprintf(&c.out, "    %s: %s;\n", name, fbsType)
+}
+
+// relationAnnotation converts a standalone relation, e.g. `{name: orders, to: Order}`, to a relation annotation
+func (c *converter) relationAnnotation(relation *node) string {
+	if relation.kind != mappingNode {
+		c.fail(relation, "a relation must be a mapping with a `name` and a target entity (`to`)")
+		return ""
+	}
+	c.checkKeys(relation, "name", "to", "uid", "external-name", "external-type")
+	if c.err != nil {
+		return ""
+	}
+	var name = c.identifier(relation, "relation")
+	if to := relation.values["to"]; c.err == nil && to == nil {
+		c.fail(relation, "relation %s: missing the target entity (`to`)", name)
+	}
+
+	var details []string
+	for _, key := range relation.keys {
+		var value = relation.values[key]
+		if !c.isScalar(value, key) {
+			return ""
+		}
+		details = append(details, key+"="+c.annotationValue(value))
+	}
+	return "relation(" + strings.Join(details, ",") + ")"
+}
+
+// annotations converts all keys except for the given ones to annotations
+func (c *converter) annotations(n *node, skipKeys ...string) []string {
+	var skip = make(map[string]bool)
+	for _, key := range skipKeys {
+		skip[key] = true
+	}
+
+	var result []string
+	for _, key := range n.keys {
+		if skip[key] {
+			continue
+		} else if !identifierRegexp.MatchString(strings.Replace(key, "-", "_", -1)) {
+			c.fail(n.keyPos[key], "invalid annotation name %q", key)
+			return nil
+		}
+
+		var value = n.values[key]
+		switch value.kind {
+		case scalarNode:
+			if !value.quoted && (value.value == "true" || value.value == "") {
+				result = append(result, key)
+			} else if value.quoted || value.value != "false" {
+				result = append(result, key+"="+c.annotationValue(value))
+			}
+		case sequenceNode:
+			var details []string
+			for _, item := range value.items {
+				if c.isScalar(item, key) {
+					details = append(details, c.annotationValue(item))
+				}
+			}
+			result = append(result, key+"("+strings.Join(details, ",")+")")
+		case mappingNode:
+			var details []string
+			for _, detailKey := range value.keys {
+				var detail = value.values[detailKey]
+				if c.isScalar(detail, detailKey) {
+					details = append(details, detailKey+"="+c.annotationValue(detail))
+				}
+			}
+			result = append(result, key+"("+strings.Join(details, ",")+")")
+		}
+	}
+	return result
+}
+
+func (c *converter) annotationValue(value *node) string {
+	if annotationValueRegexp.MatchString(value.value) {
+		return value.value
+	} else if strings.ContainsAny(value.value, "\"\n") {
+		c.fail(value, "the value %q can't be used in an annotation", value.value)
+	}
+	return `"` + value.value + `"`
+}
+
+func (c *converter) writeAnnotations(annotations []string, indent string) {
+	for _, annotation := range annotations {
+		fmt.Fprintf(&c.out, "%s/// objectbox:%s\n", indent, annotation)
+	}
+}
+
+func (c *converter) writeComment(n *node, indent string) {
+	if comment := n.values["comment"]; comment != nil && c.isScalar(comment, "comment") {
+		for _, line := range strings.Split(strings.TrimRight(comment.value, "\n"), "\n") {
+			fmt.Fprintf(&c.out, "%s/// %s\n", indent, line)
+		}
+	}
+}
+
+// identifier returns the name of the given entity, property or relation, verifying it's a valid identifier
+func (c *converter) identifier(n *node, kind string) string {
+	var name = n.values["name"]
+	if name == nil {
+		c.fail(n, "%s name missing", kind)
+		return ""
+	} else if !c.isScalar(name, "name") {
+		return ""
+	} else if !identifierRegexp.MatchString(name.value) {
+		c.fail(name, "invalid %s name %q, expecting letters, digits and underscores", kind, name.value)
+		return ""
+	}
+	return name.value
+}
+
+func (c *converter) isScalar(n *node, key string) bool {
+	if n.kind != scalarNode {
+		c.fail(n, "`%s` must be a single value", key)
+		return false
+	}
+	return true
+}
+
+// checkKeys reports the first key not in the given list
+func (c *converter) checkKeys(n *node, allowed ...string) {
+	for _, key := range n.keys {
+		var found = false
+		for _, allowedKey := range allowed {
+			found = found || key == allowedKey
+		}
+		if !found {
+			c.fail(n.keyPos[key], "unknown key %q, expecting one of: %s", key, strings.Join(allowed, ", "))
+			return
+		}
+	}
+}
+
+func typeNames() []string {
+	var names []string
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,90 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package yamlschema
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+func TestToFbs(t *testing.T) {
+	fbs, err := ToFbs("shop.schema.yaml", []byte(`# a comment
+namespace: shop
+entities:
+  - name: Customer
+    comment: "A customer, e.g. #1"
+    sync: true
+    properties:
+      - name: id
+        type: ulong
+        id: [assignable]
+      - name: email
+        type: string
+        unique: true   # implies an index
+      - name: created
+        type: date
+      - name: vector
+        type: [float:4]
+        index: hnsw
+    relations:
+      - name: orders
+        to: Order
+`))
+	assert.NoErr(t, err)
+	assert.Eq(t, `// Generated by ObjectBox Generator from shop.schema.yaml, which is the source to edit
+
+namespace shop;
+
+/// A customer, e.g. #1
+/// objectbox:sync
+/// objectbox:relation(name=orders,to=Order)
+table Customer {
+    /// objectbox:id(assignable)
+    id: ulong;
+    /// objectbox:unique
+    email: string;
+    /// objectbox:date
+    created: long;
+    /// objectbox:index=hnsw
+    vector: [float:4];
+}
+`, fbs)
+
+	// errors include the position in the YAML source
+	var expectError = func(source, expected string) {
+		_, err := ToFbs("shop.schema.yaml", []byte(source))
+		assert.Err(t, err)
+		assert.Eq(t, expected, err.Error())
+	}
+	expectError("entities:\n  - name: Customer\n    properties:\n      - name: id\n        type: uuid\n",
+		"shop.schema.yaml:5:15: property id: unknown type \"uuid\", expecting one of: "+
+			"bool, byte, bytes, date, date-nano, double, float, float32, float64, floats, int, int16, int32, int64, int8, "+
+			"long, short, string, strings, ubyte, uint, uint16, uint32, uint64, uint8, ulong, ushort")
+	expectError("entities:\n  - name: Customer\n    properties:\n      - name: id\n        name: id\n",
+		"shop.schema.yaml:5:9: duplicate key \"name\"")
+	expectError("entities:\n  - name: Customer\n   properties: []\n", "shop.schema.yaml:3:4: unexpected indentation")
+	expectError("entities:\n  - name: Customer-1\n", "shop.schema.yaml:2:11: invalid entity name \"Customer-1\", expecting letters, digits and underscores")
+	expectError("entity: []\n", "shop.schema.yaml:1:1: unknown key \"entity\", expecting one of: namespace, entities")
+	expectError("entities:\n  - name: Customer\n    properties:\n      - name: id\n        type: ulong\n        id: [assignable, \"a\\\"b\"]\n",
+		"shop.schema.yaml:6:26: the value \"a\\\"b\" can't be used in an annotation")
+
+}
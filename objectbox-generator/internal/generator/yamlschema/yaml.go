@@ -0,0 +1,358 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package yamlschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The schema files are parsed by a minimal YAML parser supporting the subset needed to declare entities: block mappings
+// and sequences, plain and quoted scalars, flow sequences of scalars (e.g. `[a, b]`) and comments. Anchors, tags,
+// multi-line scalars and flow mappings aren't supported.
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	mappingNode
+	sequenceNode
+)
+
+// node is a parsed YAML value with the position it was declared at (1-based)
+type node struct {
+	kind   nodeKind
+	line   int
+	column int
+
+	value  string // scalarNode
+	quoted bool   // scalarNode: the value was quoted, i.e. it's always a string (e.g. "true")
+
+	keys   []string         // mappingNode: keys in the declaration order
+	values map[string]*node // mappingNode
+	keyPos map[string]*node // mappingNode: position of each key, as an empty scalar
+
+	items []*node // sequenceNode
+}
+
+// yamlLine is a non-empty source line without the comment
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYaml parses the given document; an empty document results in an empty mapping
+func parseYaml(source string) (*node, error) {
+	var p = &yamlParser{}
+	for i, line := range strings.Split(strings.Replace(source, "\r\n", "\n", -1), "\n") {
+		var text = strings.TrimRight(stripComment(line), " \t")
+		var trimmed = strings.TrimLeft(text, " ")
+		if len(trimmed) == 0 || (len(p.lines) == 0 && trimmed == "---") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("%d:%d: tabs can't be used for indentation", i+1, len(text)-len(trimmed)+1)
+		}
+		p.lines = append(p.lines, yamlLine{i + 1, len(text) - len(trimmed), trimmed})
+	}
+
+	if len(p.lines) == 0 {
+		return &node{kind: mappingNode, line: 1, column: 1, values: map[string]*node{}, keyPos: map[string]*node{}}, nil
+	}
+
+	var root, err = p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos], 0, "unexpected indentation")
+	}
+	return root, nil
+}
+
+func (p *yamlParser) errorf(line yamlLine, offset int, format string, args ...interface{}) error {
+	return fmt.Errorf("%d:%d: %s", line.number, line.indent+offset+1, fmt.Sprintf(format, args...))
+}
+
+// parseBlock parses a mapping or a sequence starting at the current line
+func (p *yamlParser) parseBlock(indent int) (*node, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+// parseNested parses the value of a mapping key or a sequence item declared on the following lines, if any
+func (p *yamlParser) parseNested(parent yamlLine, indent int, allowSequence bool) (*node, error) {
+	if p.pos < len(p.lines) {
+		var next = p.lines[p.pos]
+		if next.indent > indent || (allowSequence && next.indent == indent && isSequenceItem(next.text)) {
+			return p.parseBlock(next.indent)
+		}
+	}
+	// an empty value, e.g. "key:" without anything nested
+	return &node{kind: scalarNode, line: parent.number, column: parent.indent + len(parent.text) + 1}, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (*node, error) {
+	var result = &node{kind: mappingNode, line: p.lines[p.pos].number, column: indent + 1,
+		values: map[string]*node{}, keyPos: map[string]*node{}}
+
+	for p.pos < len(p.lines) {
+		var line = p.lines[p.pos]
+		if line.indent < indent {
+			break
+		} else if line.indent > indent {
+			return nil, p.errorf(line, 0, "unexpected indentation")
+		} else if isSequenceItem(line.text) {
+			return nil, p.errorf(line, 0, "unexpected sequence item, expecting a key")
+		}
+
+		var sep = findKeySeparator(line.text)
+		if sep < 0 {
+			return nil, p.errorf(line, 0, "expecting a key followed by a colon, e.g. `name: value`")
+		}
+		key, err := parseScalar(strings.TrimSpace(line.text[:sep]))
+		if err != nil {
+			return nil, p.errorf(line, 0, "%s", err)
+		} else if len(key.value) == 0 {
+			return nil, p.errorf(line, 0, "empty key")
+		} else if result.values[key.value] != nil {
+			return nil, p.errorf(line, 0, "duplicate key %q", key.value)
+		}
+		p.pos++
+
+		var value *node
+		var rest = strings.TrimLeft(line.text[sep+1:], " ")
+		if len(rest) == 0 {
+			if value, err = p.parseNested(line, indent, true); err != nil {
+				return nil, err
+			}
+		} else if value, err = parseInline(rest); err != nil {
+			return nil, p.errorf(line, len(line.text)-len(rest), "%s", err)
+		} else {
+			value.setPosition(line.number, line.indent+len(line.text)-len(rest)+1)
+		}
+
+		result.keys = append(result.keys, key.value)
+		result.values[key.value] = value
+		result.keyPos[key.value] = &node{line: line.number, column: line.indent + 1}
+	}
+	return result, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (*node, error) {
+	var result = &node{kind: sequenceNode, line: p.lines[p.pos].number, column: indent + 1}
+
+	for p.pos < len(p.lines) {
+		var line = p.lines[p.pos]
+		if line.indent < indent {
+			break
+		} else if line.indent > indent {
+			return nil, p.errorf(line, 0, "unexpected indentation")
+		} else if !isSequenceItem(line.text) {
+			break // e.g. the next key of the parent mapping, with the sequence at the same indentation
+		}
+
+		var rest = strings.TrimLeft(line.text[1:], " ")
+		var offset = len(line.text) - len(rest)
+		var item *node
+		var err error
+		if len(rest) == 0 {
+			p.pos++
+			if item, err = p.parseNested(line, indent, false); err != nil {
+				return nil, err
+			}
+		} else if findKeySeparator(rest) >= 0 {
+			// a mapping starting on the same line as the dash, e.g. "- name: value"; continue as if it was indented
+			p.lines[p.pos] = yamlLine{line.number, indent + offset, rest}
+			if item, err = p.parseMapping(indent + offset); err != nil {
+				return nil, err
+			}
+		} else {
+			p.pos++
+			if item, err = parseInline(rest); err != nil {
+				return nil, p.errorf(line, offset, "%s", err)
+			}
+			item.setPosition(line.number, indent+offset+1)
+		}
+		result.items = append(result.items, item)
+	}
+	return result, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// stripComment removes a comment, i.e. a # at the beginning of the line or after a space, outside of quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		var c = line[i]
+		if quote != 0 {
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		} else if c == '"' || c == '\'' {
+			quote = c
+		} else if c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// findKeySeparator returns the position of the colon separating a key from its value, or -1 if there's none
+func findKeySeparator(text string) int {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return -1
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		var c = text[i]
+		if quote != 0 {
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		} else if (c == '"' || c == '\'') && i == 0 {
+			quote = c
+		} else if c == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseInline parses a value given on the same line as its key: a scalar or a flow sequence of scalars
+func parseInline(text string) (*node, error) {
+	if strings.HasPrefix(text, "{") {
+		if text == "{}" {
+			return &node{kind: mappingNode, values: map[string]*node{}, keyPos: map[string]*node{}}, nil
+		}
+		return nil, fmt.Errorf("flow mappings aren't supported, use a block mapping (a key per line) instead")
+	}
+	if strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">") {
+		return nil, fmt.Errorf("multi-line scalars aren't supported, use a quoted string with \\n instead")
+	}
+	if strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!") {
+		return nil, fmt.Errorf("anchors, aliases and tags aren't supported")
+	}
+	if !strings.HasPrefix(text, "[") {
+		return parseScalar(text)
+	}
+
+	if !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("unterminated flow sequence, expecting ']' at the end of the line")
+	}
+	var result = &node{kind: sequenceNode}
+	var content = text[1 : len(text)-1]
+	if len(strings.TrimSpace(content)) == 0 {
+		return result, nil
+	}
+	var itemTexts, starts = splitFlowItems(content)
+	for i, itemText := range itemTexts {
+		var itemTrimmed = strings.TrimLeft(itemText, " ")
+		var column = 1 + starts[i] + len(itemText) - len(itemTrimmed) // relative to the sequence, see setPosition()
+		itemTrimmed = strings.TrimRight(itemTrimmed, " ")
+		if strings.HasPrefix(itemTrimmed, "[") || strings.HasPrefix(itemTrimmed, "{") {
+			return nil, fmt.Errorf("nested flow collections aren't supported")
+		}
+		item, err := parseScalar(itemTrimmed)
+		if err != nil {
+			return nil, err
+		}
+		item.column = column
+		result.items = append(result.items, item)
+	}
+	return result, nil
+}
+
+// setPosition sets the position of a value parsed by parseInline(), including the items of a flow sequence, whose
+// columns are relative to the sequence until then
+func (n *node) setPosition(line, column int) {
+	n.line, n.column = line, column
+	if n.kind == sequenceNode {
+		for _, item := range n.items {
+			item.line, item.column = line, column+item.column
+		}
+	}
+}
+
+// splitFlowItems splits the content of a flow sequence on commas outside of quotes, returning the items and their offsets
+func splitFlowItems(content string) (items []string, starts []int) {
+	var quote byte
+	var start = 0
+	starts = append(starts, 0)
+	for i := 0; i < len(content); i++ {
+		var c = content[i]
+		if quote != 0 {
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		} else if c == '"' || c == '\'' {
+			quote = c
+		} else if c == ',' {
+			items = append(items, content[start:i])
+			start = i + 1
+			starts = append(starts, start)
+		}
+	}
+	return append(items, content[start:]), starts
+}
+
+// parseScalar parses a plain, 'single-quoted' or "double-quoted" scalar
+func parseScalar(text string) (*node, error) {
+	if strings.HasPrefix(text, `"`) {
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", text)
+		}
+		return &node{kind: scalarNode, value: value, quoted: true}, nil
+	}
+	if strings.HasPrefix(text, "'") {
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("invalid single-quoted string %s", text)
+		}
+		var inner = text[1 : len(text)-1]
+		if strings.Contains(strings.Replace(inner, "''", "", -1), "'") {
+			return nil, fmt.Errorf("invalid single-quoted string %s", text)
+		}
+		return &node{kind: scalarNode, value: strings.Replace(inner, "''", "'", -1), quoted: true}, nil
+	}
+	if text == "~" || text == "null" {
+		return &node{kind: scalarNode}, nil
+	}
+	return &node{kind: scalarNode, value: text}, nil
+}
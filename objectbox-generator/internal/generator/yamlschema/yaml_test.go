@@ -0,0 +1,147 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package yamlschema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// dump renders the parsed node in a compact flow style, with quoted scalars marked by quotes and the positions of
+// the values as @line:column
+func dump(n *node) string {
+	switch n.kind {
+	case mappingNode:
+		var entries []string
+		for _, key := range n.keys {
+			entries = append(entries, key+": "+dump(n.values[key]))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case sequenceNode:
+		var items []string
+		for _, item := range n.items {
+			items = append(items, dump(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	if n.quoted {
+		return fmt.Sprintf("%q@%d:%d", n.value, n.line, n.column)
+	}
+	return fmt.Sprintf("%s@%d:%d", n.value, n.line, n.column)
+}
+
+func parseAndDump(t *testing.T, source string) string {
+	root, err := parseYaml(source)
+	assert.NoErr(t, err)
+	return dump(root)
+}
+
+func TestParseYamlScalars(t *testing.T) {
+	assert.Eq(t, "{plain: some text@1:8, number: 42@2:9, empty: @3:7, nothing: @4:10, tilde: @5:8}",
+		parseAndDump(t, "plain: some text\nnumber: 42\nempty:\nnothing: null\ntilde: ~\n"))
+
+	// quoted scalars are always strings and may contain the characters with a special meaning
+	assert.Eq(t, `{double: "a: b # c"@1:9, escaped: "tab\t\"quote\""@2:10, single: "it's"@3:9, bool: "true"@4:7}`,
+		parseAndDump(t, `double: "a: b # c"
+escaped: "tab\t\"quote\""
+single: 'it''s'
+bool: "true"
+`))
+
+	// quoted keys
+	assert.Eq(t, `{a key: x@1:10, b: y@2:6}`, parseAndDump(t, "\"a key\": x\n'b': y\n"))
+
+	// flow sequences of scalars, the items have their own positions
+	assert.Eq(t, `{list: [a@1:8, "b, c"@1:11, "d"@1:19], empty: []}`, parseAndDump(t, "list: [a, \"b, c\", 'd']\nempty: []\n"))
+}
+
+func TestParseYamlNesting(t *testing.T) {
+	assert.Eq(t, "{entities: [{name: Task@2:11, properties: [{name: id@4:15, type: ulong@5:15}]}], namespace: shop@6:12}",
+		parseAndDump(t, `entities:
+  - name: Task
+    properties:
+      - name: id
+        type: ulong
+namespace: shop
+`))
+
+	// a sequence may be at the same indentation as its key
+	assert.Eq(t, "{a: [x@2:3, y@3:3], b: z@4:4}", parseAndDump(t, "a:\n- x\n- y\nb: z\n"))
+
+	// flow sequences as sequence items
+	assert.Eq(t, "[[a@1:4, b@1:7], c@2:3]", parseAndDump(t, "- [a, b]\n- c\n"))
+
+	// items declared on the lines after the dash
+	assert.Eq(t, "[{a: 1@2:6, b: 2@3:6}, [x@5:5]]", parseAndDump(t, "-\n  a: 1\n  b: 2\n-\n  - x\n"))
+
+	// an empty document is an empty mapping
+	assert.Eq(t, "{}", parseAndDump(t, ""))
+	assert.Eq(t, "{}", parseAndDump(t, "# just a comment\n\n"))
+}
+
+func TestParseYamlComments(t *testing.T) {
+	assert.Eq(t, `{a: x@3:4, b: "# not a comment"@4:4, c: d#e@5:4, f: [g@6:5]}`, parseAndDump(t, `---
+# a comment line
+a: x # a trailing comment
+b: "# not a comment"
+c: d#e
+f: [g] # after a flow sequence
+`))
+
+	// Windows line endings
+	assert.Eq(t, "{a: x@1:4, b: y@2:4}", parseAndDump(t, "a: x\r\nb: y\r\n"))
+}
+
+func TestParseYamlErrors(t *testing.T) {
+	var expectError = func(source, expected string) {
+		_, err := parseYaml(source)
+		assert.Err(t, err)
+		assert.Eq(t, expected, err.Error())
+	}
+
+	// the positions are 1-based line:column
+	expectError("a:\n\tb: c\n", "2:1: tabs can't be used for indentation")
+	expectError("a: x\n  b: y\n", "2:3: unexpected indentation")
+	expectError("a:\n  b: x\n   c: y\n", "3:4: unexpected indentation")
+	expectError("a: x\n- b\n", "2:1: unexpected sequence item, expecting a key")
+	expectError("a: x\njust text\n", "2:1: expecting a key followed by a colon, e.g. `name: value`")
+	expectError("a: x\n\"\": y\n", "2:1: empty key")
+	expectError("a: x\nb: y\na: z\n", "3:1: duplicate key \"a\"")
+	expectError("- a\n  - b\n", "2:3: unexpected indentation")
+
+	// unsupported syntax, the column is the one of the value
+	expectError("a: {b: c}\n", "1:4: flow mappings aren't supported, use a block mapping (a key per line) instead")
+	expectError("a: |\n  text\n", "1:4: multi-line scalars aren't supported, use a quoted string with \\n instead")
+	expectError("a: &anchor x\n", "1:4: anchors, aliases and tags aren't supported")
+	expectError("a: !!str x\n", "1:4: anchors, aliases and tags aren't supported")
+	expectError("a: [x, [y]]\n", "1:4: nested flow collections aren't supported")
+	expectError("a: [x, y\n", "1:4: unterminated flow sequence, expecting ']' at the end of the line")
+	expectError("- [x\n", "1:3: unterminated flow sequence, expecting ']' at the end of the line")
+
+	// malformed quoted scalars
+	expectError("a: \"x\n", "1:4: invalid double-quoted string \"x")
+	expectError("a: \"\\q\"\n", "1:4: invalid double-quoted string \"\\q\"")
+	expectError("a: 'x\n", "1:4: invalid single-quoted string 'x")
+	expectError("a: 'x'y'\n", "1:4: invalid single-quoted string 'x'y'")
+}
@@ -0,0 +1,223 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+)
+
+// scope defines where an annotation can be used
+type scope int
+
+const (
+	goEntity scope = 1 << iota
+	goProperty
+	fbsEntity
+	fbsProperty
+)
+
+// annotationDoc describes an annotation for completion and hover
+type annotationDoc struct {
+	name  string
+	scope scope
+	doc   string
+}
+
+// annotationTexts describes the annotations by name; where they can be used is taken from the readers, see
+// supportedAnnotationDocs()
+var annotationTexts = map[string]string{
+	"-":                                    "Excludes the field from persistence, same as `transient`.",
+	"accessors":                            "C++, JS: generates private members with get/set accessors; `accessors=false` disables them if the `-accessors` flag is given.",
+	"backlink":                             "Declares the to-many inverse of a to-one relation, e.g. `backlink(name=orders, to=Order, property=customer)` on a table or `backlink:Customer` on a Go slice field; generates accessors, e.g. `FetchOrders()` in Go or `orders()` in C++.",
+	"cascade":                              "Removing an object also removes its related objects, e.g. on the to-one relation `Order.customer` removing a Customer removes its Orders; on a standalone relation use `relation(name=tags,to=Tag,cascade)`. Recorded in the generated model, cycles are rejected.",
+	"constants":                            "C, C++, JS: the table isn't an entity, its fields declare constants with their default values, e.g. `maxNameLength: int = 64;`, generated as C macros, C++ `constexpr` values and JS frozen objects.",
+	"converter":                            "Converts the field value using the functions `<converter>ToDatabaseValue` and `<converter>ToEntityProperty`; the stored type is given by the `type` annotation.",
+	"date":                                 "Stores the value as a date with millisecond precision, i.e. milliseconds since the Unix epoch.",
+	"date-nano":                            "Stores the value as a date with nanosecond precision, i.e. nanoseconds since the Unix epoch.",
+	"encrypted":                            "Stores a `string` or `[]byte` field encrypted by the user-supplied functions `objectboxEncrypt(property string, plaintext []byte)` and `objectboxDecrypt(property string, ciphertext []byte)`; `encrypted:vault` calls `vaultEncrypt()` and `vaultDecrypt()` instead.",
+	"external-id":                          "A secondary, unique string ID used by other systems, e.g. a UUID or a MongoDB `_id` (combine with `external-type=MongoId`); generates a lookup, e.g. `GetByUuid()` in Go or `findByUuid()` in C++, and is marked in the external mapping used by ObjectBox Sync.",
+	"external-name":                        "The name in an external system, e.g. a MongoDB collection or field, used by ObjectBox Sync.",
+	"external-type":                        "The type in an external system used by ObjectBox Sync, e.g. `Uuid`, `MongoId` or `Json`.",
+	"flex":                                 "Stores a slice of structs with fields of basic types, e.g. `Tags []Tag`, as a FlexBuffers vector of maps using converters generated into the binding.",
+	"hnsw-dimensions":                      "HNSW index: the number of dimensions of the indexed vectors; requires `index=hnsw`.",
+	"hnsw-distance-type":                   "HNSW index: the distance function, e.g. `Euclidean` (the default), `Cosine`, `DotProduct` or `Geo`.",
+	"hnsw-flags":                           "HNSW index: flags, e.g. `DebugLogs` or `VectorCacheSimdPaddingOff`.",
+	"hnsw-indexing-search-count":           "HNSW index: the number of neighbors searched for when indexing a vector.",
+	"hnsw-neighbors-per-node":              "HNSW index: the maximum number of connections per node.",
+	"hnsw-reparation-backlink-probability": "HNSW index: the probability (0.0 - 1.0) of repairing the graph when removing nodes.",
+	"hnsw-vector-cache-hint-size-kb":       "HNSW index: a hint for the size of the vector cache in KB.",
+	"id":                                   "Marks the ID property; `id(assignable)` lets the application assign IDs instead of the database.",
+	"id-companion":                         "A date property complementing the ID, e.g. for time series data.",
+	"index":                                "Creates an index; optionally of the given type: `value`, `hash` or `hash64` (strings only, `hash` is their default) or `hnsw` (float vectors only).",
+	"inline":                               "Embeds the fields of the struct into the entity.",
+	"lazy":                                 "Loads a to-many relation on first access instead of together with the object.",
+	"link":                                 "Stores a relation to another entity: to-one for a struct pointer, to-many for a slice.",
+	"name":                                 "The name in the database, if different from the source.",
+	"not-null":                             "JS: the property is required; with the `-validation` flag, the generated `validate<Entity>()` reports it missing and `toFlatbuffers()` throws.",
+	"optional":                             "The field may be unset (null); the generated type depends on the `-optional` flag.",
+	"pmr":                                  "C++17: generates `std::pmr::string` and `std::pmr::vector` members, e.g. to allocate objects from a `std::pmr::memory_resource` arena; `pmr=false` disables them if the `-pmr` flag is given.",
+	"related":                              "Stores a slice of structs, e.g. `Tags []Tag`, as objects of a generated entity (e.g. `TaskTags`) linked to the owner, put and loaded with it and removed along with it.",
+	"relation":                             "A to-one relation of an ID field to the given entity, e.g. `relation=Customer`.",
+	"reserved":                             "Property IDs of the entity the generator must never assign, e.g. `reserved=5,6` for properties moved elsewhere; a property already using one is reported as an error.",
+	"reserved-entity-ids":                  "Entity IDs the generator must never assign in the whole model, e.g. `reserved-entity-ids=7`; an entity already using one is reported as an error.",
+	"reserved-uids":                        "UIDs the generator must never assign in the whole model, e.g. `reserved-uids=4918476352198012345`; an entity, property or relation already using one is reported as an error.",
+	"retired":                              "Removes the entity or the property from the model, keeping its UID retired so that it's never reused.",
+	"storage-type":                         "Float vectors only: `float16` stores the values with half precision, halving the storage size at the cost of accuracy; `float32` is the default.",
+	"sync":                                 "Enables ObjectBox Sync for the entity; `sync(sharedGlobalIds)` shares the IDs across all clients.",
+	"transient":                            "Excludes the entity or the property from persistence; `transient(allow-drop)` confirms dropping the data of a property stored before.",
+	"type":                                 "The stored type: with a converter, the type passed to it; without one, a smaller integer type, e.g. `type:int8`.",
+	"uid":                                  "The UID in the model JSON, e.g. to rename the entity or the property; an empty value makes the generator suggest one.",
+	"unique":                               "Creates a unique index; putting an object with an existing value fails, unless given as `unique=replace` (Go: `unique:replace`), which replaces the stored object.",
+}
+
+// scopedAnnotationTexts describes the annotations with a different meaning depending on where they're used
+var scopedAnnotationTexts = map[scope]map[string]string{
+	fbsEntity: {"relation": "A standalone to-many relation, e.g. `relation(name=tasks,to=Task)`."},
+}
+
+// annotationDocs lists the annotations supported by the Go source reader and the FlatBuffers schema readers
+var annotationDocs = supportedAnnotationDocs()
+
+// supportedAnnotationDocs collects the annotations the readers accept and the scopes they're accepted in. The C/C++
+// and JS readers both read FlatBuffers schemas, so their annotations are merged.
+func supportedAnnotationDocs() []annotationDoc {
+	var scopes = make(map[string]scope)
+	var names []string
+	var add = func(s scope, supported []string) {
+		for _, name := range supported {
+			if scopes[name] == 0 {
+				names = append(names, name)
+			}
+			scopes[name] |= s
+		}
+	}
+	goEntities, goProperties := gogenerator.SupportedAnnotations()
+	add(goEntity, goEntities)
+	add(goProperty, goProperties)
+	cEntities, cProperties := cgenerator.SupportedAnnotations()
+	add(fbsEntity, cEntities)
+	add(fbsProperty, cProperties)
+	jsEntities, jsProperties := jsgenerator.SupportedAnnotations()
+	add(fbsEntity, jsEntities)
+	add(fbsProperty, jsProperties)
+	sort.Strings(names)
+
+	var docs []annotationDoc
+	for _, name := range names {
+		var remaining = scopes[name]
+		for _, s := range []scope{goEntity, goProperty, fbsEntity, fbsProperty} {
+			if text, found := scopedAnnotationTexts[s][name]; found && remaining&s != 0 {
+				docs = append(docs, annotationDoc{name, s, text})
+				remaining &^= s
+			}
+		}
+		if remaining != 0 {
+			docs = append(docs, annotationDoc{name, remaining, annotationTexts[name]})
+		}
+	}
+	return docs
+}
+
+// attributeDocs describes native FlatBuffers attributes with a special meaning for ObjectBox
+var attributeDocs = map[string]string{
+	"sync":              "Enables ObjectBox Sync for the table, same as the `/// objectbox:sync` annotation; declare it using `attribute \"sync\";`.",
+	"key":               "Not supported by ObjectBox, use the `/// objectbox:unique` or `/// objectbox:index` annotation instead; rejected with `-strict-schema`.",
+	"required":          "Ignored by ObjectBox, objects are stored even if the field is not set; rejected with `-strict-schema`.",
+	"nested_flatbuffer": "Ignored by ObjectBox, the field is stored as a plain byte vector; rejected with `-strict-schema`.",
+}
+
+// annotationsFor returns the docs of the annotations available in the given scope
+func annotationsFor(s scope) []annotationDoc {
+	var result []annotationDoc
+	for _, doc := range annotationDocs {
+		if doc.scope&s != 0 {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+// annotationDocFor returns the doc of the named annotation in the given scope, or nil if it isn't supported there
+func annotationDocFor(name string, s scope) *annotationDoc {
+	for i := range annotationDocs {
+		if annotationDocs[i].name == name && annotationDocs[i].scope&s != 0 {
+			return &annotationDocs[i]
+		}
+	}
+	return nil
+}
+
+var goTagStart = regexp.MustCompile(`objectbox:"[^"]*$`)
+var goEntityComment = regexp.MustCompile(`^\s*//\s*objectbox:`)
+var fbsAnnotationComment = regexp.MustCompile(`^\s*///\s*objectbox:`)
+var fbsAttributesStart = regexp.MustCompile(`\([^)]*$`)
+
+// annotationScope returns the scope of the annotation being written at the given column of the line,
+// or zero if the position isn't in an annotation
+func annotationScope(lines []string, line, column int, isGo bool) scope {
+	var before = lines[line][:column]
+	if isGo {
+		if goTagStart.MatchString(before) {
+			return goProperty
+		} else if goEntityComment.MatchString(before) {
+			return goEntity
+		}
+		return 0
+	}
+
+	if !fbsAnnotationComment.MatchString(before) {
+		return 0
+	}
+	// the annotation comment applies to the next declaration - a table or a field
+	for _, next := range lines[line+1:] {
+		next = strings.TrimSpace(next)
+		if len(next) == 0 || strings.HasPrefix(next, "//") {
+			continue
+		} else if strings.HasPrefix(next, "table ") || strings.HasPrefix(next, "struct ") {
+			return fbsEntity
+		}
+		break
+	}
+	return fbsProperty
+}
+
+// inFbsAttributes returns true if the given column of the line is inside a FlatBuffers attribute list, e.g. `(key)`
+func inFbsAttributes(text string, column int) bool {
+	return !strings.HasPrefix(strings.TrimSpace(text), "//") && fbsAttributesStart.MatchString(text[:column])
+}
+
+// wordAt returns the annotation name (letters, digits, '-' and '_') at the given column of the line and its bounds
+func wordAt(text string, column int) (word string, start, end int) {
+	var isWordChar = func(c byte) bool {
+		return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+	}
+	for start = column; start > 0 && isWordChar(text[start-1]); start-- {
+	}
+	for end = column; end < len(text) && isWordChar(text[end]); end++ {
+	}
+	return text[start:end], start, end
+}
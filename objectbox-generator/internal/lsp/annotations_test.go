@@ -0,0 +1,49 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lsp
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+func TestAnnotationDocs(t *testing.T) {
+	// each annotation the readers support is documented and each documented annotation is still supported
+	var documented = make(map[string]bool)
+	for _, doc := range annotationDocs {
+		if len(doc.doc) == 0 {
+			t.Errorf("annotation %s is supported by the readers but has no doc in annotationTexts", doc.name)
+		}
+		documented[doc.name] = true
+	}
+	for name := range annotationTexts {
+		if !documented[name] {
+			t.Errorf("annotation %s is documented in annotationTexts but isn't supported by any reader", name)
+		}
+	}
+
+	assert.Eq(t, "A standalone to-many relation, e.g. `relation(name=tasks,to=Task)`.", annotationDocFor("relation", fbsEntity).doc)
+	assert.True(t, annotationDocFor("relation", fbsProperty) != nil)
+	assert.True(t, annotationDocFor("relation", goProperty) == nil)
+	assert.True(t, annotationDocFor("not-null", fbsProperty) != nil)
+	assert.True(t, annotationDocFor("reserved-uids", goEntity) != nil)
+}
@@ -0,0 +1,312 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package lsp implements a language server (see https://microsoft.github.io/language-server-protocol/) for ObjectBox
+// sources: Go structs and FlatBuffers schemas. It provides diagnostics, completion of annotations and hover docs.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+)
+
+// Server is a language server communicating over the given reader and writer, usually stdin and stdout.
+type Server struct {
+	reader *bufio.Reader
+	writer io.Writer
+
+	// open documents: URI => lines of the current text
+	documents map[string][]string
+
+	shutdown bool
+}
+
+// NewServer creates a server reading requests from the given reader and writing responses to the writer
+func NewServer(reader io.Reader, writer io.Writer) *Server {
+	return &Server{
+		reader:    bufio.NewReader(reader),
+		writer:    writer,
+		documents: make(map[string][]string),
+	}
+}
+
+// Serve processes messages until the client sends the "exit" notification or closes the input.
+// Returns an error if the client exits without requesting a shutdown first, as required by the protocol.
+func (server *Server) Serve() error {
+	for {
+		req, err := readMessage(server.reader)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if req.Method == "exit" {
+			if !server.shutdown {
+				return fmt.Errorf("exit requested without a shutdown")
+			}
+			return nil
+		}
+
+		if err = server.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches the request to its handler and writes the response (only for requests, not notifications)
+func (server *Server) handle(req *request) error {
+	var result interface{}
+	var err error
+
+	switch req.Method {
+	case "initialize":
+		result = map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // full text
+					"save":      true,
+				},
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{":", "\"", ",", "("},
+				},
+				"hoverProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "objectbox-lsp", "version": generator.Version},
+		}
+	case "shutdown":
+		server.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			server.documents[params.TextDocument.URI] = splitLines(params.TextDocument.Text)
+			err = server.validate(params.TextDocument.URI)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(req.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			server.documents[params.TextDocument.URI] = splitLines(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didSave":
+		var params documentParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			err = server.validate(params.TextDocument.URI)
+		}
+	case "textDocument/didClose":
+		var params documentParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			delete(server.documents, params.TextDocument.URI)
+		}
+	case "textDocument/completion":
+		var params positionParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			result = server.completion(params)
+		}
+	case "textDocument/hover":
+		var params positionParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			if h := server.hover(params); h != nil {
+				result = h
+			}
+		}
+	default:
+		if req.ID != nil && !strings.HasPrefix(req.Method, "$/") {
+			return writeMessage(server.writer, errorResponse{"2.0", req.ID, responseError{errMethodNotFound, "method not supported: " + req.Method}})
+		}
+		return nil // ignore unknown notifications
+	}
+
+	if req.ID == nil { // a notification, no response
+		return err
+	} else if err != nil {
+		return writeMessage(server.writer, errorResponse{"2.0", req.ID, responseError{errInvalidParams, err.Error()}})
+	}
+	return writeMessage(server.writer, response{"2.0", req.ID, result})
+}
+
+func splitLines(text string) []string {
+	return strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+}
+
+// codeGeneratorFor returns the generator reading the given source file, or nil if it's not a supported source
+func codeGeneratorFor(path string) generator.CodeGenerator {
+	switch filepath.Ext(path) {
+	case ".go":
+		var gen = &gogenerator.GoGenerator{}
+		if !gen.IsGeneratedFile(path) && !strings.HasSuffix(path, "_test.go") {
+			return gen
+		}
+	case ".fbs":
+		return &cgenerator.CGenerator{LangVersion: 14}
+	}
+	return nil
+}
+
+// validate checks the saved source file the same way the generator does (against the model JSON in its directory)
+// and publishes the resulting diagnostics, clearing previous ones if the source is valid
+func (server *Server) validate(uri string) error {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return err
+	}
+	var gen = codeGeneratorFor(path)
+	if gen == nil {
+		return nil
+	}
+
+	var diagnostics = make(map[string][]diagnostic)
+	diagnostics[uri] = []diagnostic{}
+
+	// messages printed while validating must not interfere with the protocol on stdout
+	_, err = generator.Validate(generator.Options{InPath: path, CodeGenerator: gen, Output: os.Stderr})
+	for _, diag := range generator.Diagnostics(err) {
+		var diagURI = uri
+		if len(diag.File) > 0 && diag.File != path {
+			diagURI = pathToURI(diag.File)
+		}
+
+		// LSP positions are zero-based; without a known position, the error is shown at the beginning of the file
+		var start = position{Line: diag.Line - 1, Character: diag.Column - 1}
+		if diag.Line == 0 {
+			start = position{}
+		} else if diag.Column == 0 {
+			start.Character = 0
+		}
+		var end = start
+		if lines, open := server.documents[diagURI]; open && start.Line < len(lines) {
+			end.Character = len(lines[start.Line])
+		}
+
+		diagnostics[diagURI] = append(diagnostics[diagURI], diagnostic{
+			Range:    textRange{start, end},
+			Severity: lspSeverity(diag.Severity),
+			Code:     diag.Code,
+			Source:   "objectbox",
+			Message:  diag.Message,
+		})
+	}
+
+	for diagURI, list := range diagnostics {
+		var params = publishDiagnosticsParams{URI: diagURI, Diagnostics: list}
+		if err = writeMessage(server.writer, notification{"2.0", "textDocument/publishDiagnostics", params}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lspSeverity(severity generator.LintSeverity) int {
+	switch severity {
+	case generator.LintWarning:
+		return severityWarning
+	case generator.LintInfo:
+		return severityInformation
+	default:
+		return severityError
+	}
+}
+
+// line returns the given line of an open document and the column clamped to its length; ok is false if not available
+func (server *Server) line(params positionParams) (lines []string, column int, ok bool) {
+	lines, ok = server.documents[params.TextDocument.URI]
+	if !ok || params.Position.Line < 0 || params.Position.Line >= len(lines) {
+		return nil, 0, false
+	}
+	column = params.Position.Character
+	if column > len(lines[params.Position.Line]) {
+		column = len(lines[params.Position.Line])
+	} else if column < 0 {
+		column = 0
+	}
+	return lines, column, true
+}
+
+// completion offers the annotations available at the given position, e.g. in an `objectbox:"..."` tag
+func (server *Server) completion(params positionParams) []completionItem {
+	var items = []completionItem{}
+	lines, column, ok := server.line(params)
+	if !ok {
+		return items
+	}
+
+	var isGo = strings.HasSuffix(params.TextDocument.URI, ".go")
+	var s = annotationScope(lines, params.Position.Line, column, isGo)
+	for _, doc := range annotationsFor(s) {
+		items = append(items, completionItem{
+			Label:         doc.name,
+			Kind:          completionKindProperty,
+			Documentation: &markupContent{"markdown", doc.doc},
+		})
+	}
+
+	if s == 0 && !isGo && inFbsAttributes(lines[params.Position.Line], column) {
+		var names []string
+		for name := range attributeDocs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			items = append(items, completionItem{
+				Label:         name,
+				Kind:          completionKindKeyword,
+				Documentation: &markupContent{"markdown", attributeDocs[name]},
+			})
+		}
+	}
+	return items
+}
+
+// hover describes the annotation (or the FlatBuffers attribute) at the given position
+func (server *Server) hover(params positionParams) *hover {
+	lines, column, ok := server.line(params)
+	if !ok {
+		return nil
+	}
+
+	var text = lines[params.Position.Line]
+	word, start, end := wordAt(text, column)
+	if len(word) == 0 {
+		return nil
+	}
+	var wordRange = &textRange{position{params.Position.Line, start}, position{params.Position.Line, end}}
+
+	var isGo = strings.HasSuffix(params.TextDocument.URI, ".go")
+	if s := annotationScope(lines, params.Position.Line, start, isGo); s != 0 {
+		if doc := annotationDocFor(strings.ToLower(word), s); doc != nil {
+			return &hover{markupContent{"markdown", "**objectbox:" + doc.name + "**\n\n" + doc.doc}, wordRange}
+		}
+	} else if !isGo && inFbsAttributes(text, start) {
+		if doc, found := attributeDocs[word]; found {
+			return &hover{markupContent{"markdown", "**" + word + "** (FlatBuffers attribute)\n\n" + doc}, wordRange}
+		}
+	}
+	return nil
+}
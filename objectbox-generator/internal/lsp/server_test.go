@@ -0,0 +1,119 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestLanguageServer(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "lsp")
+	defer remove()
+
+	var schemaFile = filepath.Join(dir, "schema.fbs")
+	var schema = "/// objectbox:sync\ntable Task {\n id: ulong;\n /// objectbox:index=unknown\n text: string (key);\n}\n"
+	assert.NoErr(t, ioutil.WriteFile(schemaFile, []byte(schema), 0600))
+	var uri = "file://" + filepath.ToSlash(schemaFile)
+	var goUri = "file://" + filepath.ToSlash(filepath.Join(dir, "task.go"))
+
+	// all requests are sent at once, the server responds in the same order
+	var input bytes.Buffer
+	var id = 0
+	var send = func(method string, params interface{}) {
+		var msg = map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+		if !strings.Contains(method, "/did") {
+			id++
+			msg["id"] = id
+		}
+		data, err := json.Marshal(msg)
+		assert.NoErr(t, err)
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+	var at = func(uri string, line, character int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": map[string]string{"uri": uri},
+			"position":     map[string]int{"line": line, "character": character},
+		}
+	}
+	send("initialize", map[string]interface{}{})
+	send("textDocument/didOpen", map[string]interface{}{"textDocument": map[string]string{"uri": uri, "text": schema}})
+	send("textDocument/completion", at(uri, 0, 14))
+	send("textDocument/completion", at(uri, 3, 15))
+	send("textDocument/hover", at(uri, 3, 16))
+	send("textDocument/hover", at(uri, 4, 17))
+	send("textDocument/didOpen", map[string]interface{}{"textDocument": map[string]string{"uri": goUri,
+		"text": "package task\n\ntype Task struct {\n\tId uint64 `objectbox:\"id\"`\n}\n"}})
+	send("textDocument/completion", at(goUri, 3, 25))
+	send("shutdown", nil)
+	send("exit", nil)
+
+	var output bytes.Buffer
+	assert.NoErr(t, NewServer(&input, &output).Serve())
+
+	type message struct {
+		ID     int             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		Result json.RawMessage `json:"result"`
+	}
+	var messages []message
+	for _, part := range strings.Split(output.String(), "Content-Length: ")[1:] {
+		var msg message
+		assert.NoErr(t, json.Unmarshal([]byte(part[strings.Index(part, "\r\n\r\n")+4:]), &msg))
+		messages = append(messages, msg)
+	}
+	assert.Eq(t, 9, len(messages)) // 7 responses and the diagnostics for both documents
+
+	// diagnostics published on open, the error position is the declaration of the property
+	assert.Eq(t, "textDocument/publishDiagnostics", messages[1].Method)
+	assert.True(t, strings.Contains(string(messages[1].Params), `"range":{"start":{"line":4,"character":1},"end":{"line":4,"character":20}}`))
+	assert.True(t, strings.Contains(string(messages[1].Params), "unknown index type unknown"))
+
+	// completion: entity annotations on the table, property annotations on the field
+	assert.True(t, strings.Contains(string(messages[2].Result), `"label":"sync"`))
+	assert.True(t, strings.Contains(string(messages[2].Result), `"label":"pmr"`))
+	assert.True(t, strings.Contains(string(messages[2].Result), `"label":"reserved-entity-ids"`))
+	assert.True(t, strings.Contains(string(messages[2].Result), `"label":"constants"`))
+	assert.True(t, !strings.Contains(string(messages[2].Result), `"label":"unique"`))
+	assert.True(t, strings.Contains(string(messages[3].Result), `"label":"unique"`))
+	assert.True(t, strings.Contains(string(messages[3].Result), `"label":"not-null"`))
+	assert.True(t, !strings.Contains(string(messages[3].Result), `"label":"sync"`))
+
+	// hover: an annotation and a native FlatBuffers attribute
+	assert.True(t, strings.Contains(string(messages[4].Result), "**objectbox:index**"))
+	assert.True(t, strings.Contains(string(messages[5].Result), "**key** (FlatBuffers attribute)"))
+
+	// Go struct tags; the Go file doesn't exist on disk so there are no diagnostics
+	assert.True(t, strings.Contains(string(messages[6].Params), `"diagnostics":[]`))
+	assert.True(t, strings.Contains(string(messages[7].Result), `"label":"converter"`))
+	assert.True(t, strings.Contains(string(messages[7].Result), `"label":"flex"`))
+	assert.True(t, strings.Contains(string(messages[7].Result), `"label":"encrypted"`))
+	assert.Eq(t, "null", string(messages[8].Result)) // shutdown
+}
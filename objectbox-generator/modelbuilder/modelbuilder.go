@@ -0,0 +1,159 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package modelbuilder lets Go programs define ObjectBox entities in code (e.g. derived from another schema) instead
+// of declaring them in source files, and generate the bindings for them like the objectbox-generator command does.
+//
+// The types and constants are aliases of the generator's model package so entities built here can be passed on as-is.
+package modelbuilder
+
+import (
+	"fmt"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+)
+
+// Entity is an entity (a class/table) of the model, see EntityBuilder.Entity()
+type Entity = model.Entity
+
+// EntityBuilder defines an entity property by property, see NewEntity()
+type EntityBuilder = model.EntityBuilder
+
+// Uid is a unique identifier of an entity or a property in the model, assigned by the generator unless set explicitly
+type Uid = model.Uid
+
+// EntityFlags is a bit combination of 0..n entity flags
+type EntityFlags = model.EntityFlags
+
+// PropertyFlags is a bit combination of 0..n property flags
+type PropertyFlags = model.PropertyFlags
+
+// PropertyType is the type of a property
+type PropertyType = model.PropertyType
+
+const (
+	EntityFlagSyncEnabled     = model.EntityFlagSyncEnabled
+	EntityFlagSharedGlobalIds = model.EntityFlagSharedGlobalIds
+)
+
+const (
+	PropertyFlagId                      = model.PropertyFlagId
+	PropertyFlagNonPrimitiveType        = model.PropertyFlagNonPrimitiveType
+	PropertyFlagNotNull                 = model.PropertyFlagNotNull
+	PropertyFlagIndexed                 = model.PropertyFlagIndexed
+	PropertyFlagReserved                = model.PropertyFlagReserved
+	PropertyFlagUnique                  = model.PropertyFlagUnique
+	PropertyFlagIdMonotonicSequence     = model.PropertyFlagIdMonotonicSequence
+	PropertyFlagIdSelfAssignable        = model.PropertyFlagIdSelfAssignable
+	PropertyFlagIndexPartialSkipNull    = model.PropertyFlagIndexPartialSkipNull
+	PropertyFlagIndexPartialSkipZero    = model.PropertyFlagIndexPartialSkipZero
+	PropertyFlagVirtual                 = model.PropertyFlagVirtual
+	PropertyFlagIndexHash               = model.PropertyFlagIndexHash
+	PropertyFlagIndexHash64             = model.PropertyFlagIndexHash64
+	PropertyFlagUnsigned                = model.PropertyFlagUnsigned
+	PropertyFlagIdCompanion             = model.PropertyFlagIdCompanion
+	PropertyFlagUniqueOnConflictReplace = model.PropertyFlagUniqueOnConflictReplace
+)
+
+const (
+	PropertyTypeBool         = model.PropertyTypeBool
+	PropertyTypeByte         = model.PropertyTypeByte
+	PropertyTypeShort        = model.PropertyTypeShort
+	PropertyTypeChar         = model.PropertyTypeChar
+	PropertyTypeInt          = model.PropertyTypeInt
+	PropertyTypeLong         = model.PropertyTypeLong
+	PropertyTypeFloat        = model.PropertyTypeFloat
+	PropertyTypeDouble       = model.PropertyTypeDouble
+	PropertyTypeString       = model.PropertyTypeString
+	PropertyTypeDate         = model.PropertyTypeDate
+	PropertyTypeRelation     = model.PropertyTypeRelation
+	PropertyTypeDateNano     = model.PropertyTypeDateNano
+	PropertyTypeByteVector   = model.PropertyTypeByteVector
+	PropertyTypeFloatVector  = model.PropertyTypeFloatVector
+	PropertyTypeStringVector = model.PropertyTypeStringVector
+)
+
+// NewEntity starts building an entity with the given name. Errors (e.g. duplicate properties) are collected and
+// returned by EntityBuilder.Entity().
+func NewEntity(name string) *EntityBuilder {
+	return model.NewEntity(name)
+}
+
+// Issue is a problem found by Validate(), e.g. a hash index on a number property
+type Issue = model.Issue
+
+// Validate checks the flags of the given entities and their properties, the types they're set on and relation
+// targets, returning all the issues found instead of stopping at the first one. ProcessModel() does the same check
+// before writing anything; call Validate() to report the issues, e.g. with the origin of each entity, beforehand.
+func Validate(entities []*Entity) []Issue {
+	return model.Validate(&model.ModelInfo{Entities: entities})
+}
+
+// Options configures ProcessModel()
+type Options struct {
+	// SchemaFile is the FlatBuffers schema (".fbs") the entities are written to before generating the bindings
+	SchemaFile string
+
+	// ModelInfoFile is the model JSON file keeping IDs and UIDs stable; defaults to objectbox-model.json next to the
+	// schema
+	ModelInfoFile string
+
+	// OutPath is the directory the bindings are written to; defaults to the directory of the schema
+	OutPath string
+
+	// Language selects the bindings to generate, same as the command line flags: c, cpp, cpp11, js or docs
+	Language string
+}
+
+// ProcessModel writes the given entities as a FlatBuffers schema and generates the bindings and the model for them.
+// IDs and UIDs are assigned and kept stable using the model JSON file, the same way as for entities declared in
+// source files.
+func ProcessModel(options Options, entities []*Entity) error {
+	codeGenerator, err := codeGenerator(options.Language)
+	if err != nil {
+		return err
+	}
+	return generator.ProcessModel(generator.Options{
+		InPath:        options.SchemaFile,
+		ModelInfoFile: options.ModelInfoFile,
+		OutPath:       options.OutPath,
+		CodeGenerator: codeGenerator,
+	}, entities)
+}
+
+func codeGenerator(lang string) (generator.CodeGenerator, error) {
+	switch lang {
+	case "c":
+		return &cgenerator.CGenerator{PlainC: true, LangVersion: -1}, nil
+	case "cpp":
+		return &cgenerator.CGenerator{LangVersion: 14}, nil
+	case "cpp11":
+		return &cgenerator.CGenerator{LangVersion: 11}, nil
+	case "js":
+		return &jsgenerator.JSGenerator{}, nil
+	case "docs":
+		return &docsgenerator.DocsGenerator{Source: &cgenerator.CGenerator{LangVersion: 14}}, nil
+	}
+	return nil, fmt.Errorf("unknown language %q, expecting one of: c, cpp, cpp11, js, docs", lang)
+}
@@ -0,0 +1,160 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package modelbuilder_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/model"
+	"github.com/objectbox/objectbox-generator/v4/modelbuilder"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/fixture"
+)
+
+func TestProcessModel(t *testing.T) {
+	dir, remove := fixture.TempDir(t, "process-model")
+	defer remove()
+
+	customer, err := modelbuilder.NewEntity("Customer").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		AddProperty("email", modelbuilder.PropertyTypeString, modelbuilder.PropertyFlagUnique).
+		AddProperty("registeredAt", modelbuilder.PropertyTypeDate).
+		AddToOne("region", "Region").
+		AddToMany("tags", "Tag").
+		Entity()
+	assert.NoErr(t, err)
+	region, err := modelbuilder.NewEntity("Region").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		AddProperty("name", modelbuilder.PropertyTypeString).
+		Entity()
+	assert.NoErr(t, err)
+	tag, err := modelbuilder.NewEntity("Tag").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		Entity()
+	assert.NoErr(t, err)
+
+	var options = modelbuilder.Options{
+		SchemaFile: filepath.Join(dir, "model.fbs"),
+		Language:   "cpp11",
+	}
+	assert.NoErr(t, modelbuilder.ProcessModel(options, []*modelbuilder.Entity{customer, region, tag}))
+
+	storedModel, err := model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, len(storedModel.Entities))
+	storedCustomer, err := storedModel.FindEntityByName("Customer")
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, len(storedCustomer.Properties))
+	assert.Eq(t, 1, len(storedCustomer.Relations))
+	registeredAt, err := storedCustomer.FindPropertyByName("registeredAt")
+	assert.NoErr(t, err)
+	assert.Eq(t, modelbuilder.PropertyTypeDate, registeredAt.Type)
+	customerId := storedCustomer.Id
+	assert.NoErr(t, storedModel.Close())
+
+	bindingFiles, err := filepath.Glob(filepath.Join(dir, "*.obx.hpp"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, len(bindingFiles))
+
+	// IDs are kept when generating again, e.g. after the source the model is derived from has changed
+	region, err = modelbuilder.NewEntity("Region").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		AddProperty("name", modelbuilder.PropertyTypeString).
+		AddProperty("code", modelbuilder.PropertyTypeString, modelbuilder.PropertyFlagIndexed).
+		Entity()
+	assert.NoErr(t, err)
+	assert.NoErr(t, modelbuilder.ProcessModel(options, []*modelbuilder.Entity{customer, region, tag}))
+	storedModel, err = model.LoadModelFromJSONFile(generator.ModelInfoFile(dir))
+	assert.NoErr(t, err)
+	storedCustomer, err = storedModel.FindEntityByName("Customer")
+	assert.NoErr(t, err)
+	assert.Eq(t, customerId, storedCustomer.Id)
+	assert.NoErr(t, storedModel.Close())
+
+	// builder errors are reported when the entity is retrieved
+	_, err = modelbuilder.NewEntity("Task").
+		AddProperty("id", modelbuilder.PropertyTypeLong).
+		AddProperty("ID", modelbuilder.PropertyTypeLong).
+		Entity()
+	assert.Err(t, err)
+	assert.Eq(t, "entity Task: duplicate property ID", err.Error())
+
+	options.Language = "go"
+	err = modelbuilder.ProcessModel(options, []*modelbuilder.Entity{customer, region, tag})
+	assert.Err(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), `unknown language "go"`))
+}
+
+func TestValidate(t *testing.T) {
+	customer, err := modelbuilder.NewEntity("Customer").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		AddProperty("email", modelbuilder.PropertyTypeString, modelbuilder.PropertyFlagIndexHash).
+		AddToOne("region", "Region").
+		Entity()
+	assert.NoErr(t, err)
+	region, err := modelbuilder.NewEntity("Region").
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		Entity()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, len(modelbuilder.Validate([]*modelbuilder.Entity{customer, region})))
+
+	// all the issues are reported, not just the first one
+	invalid, err := modelbuilder.NewEntity("Invalid").
+		Flags(modelbuilder.EntityFlagSharedGlobalIds).
+		AddProperty("id", modelbuilder.PropertyTypeLong, modelbuilder.PropertyFlagId).
+		AddProperty("priority", modelbuilder.PropertyTypeInt, modelbuilder.PropertyFlagIndexHash).
+		AddProperty("code", modelbuilder.PropertyTypeString, modelbuilder.PropertyFlagIdSelfAssignable).
+		AddProperty("embedding", modelbuilder.PropertyTypeFloatVector, modelbuilder.PropertyFlagIndexed).
+		AddToOne("owner", "Owner").
+		AddToMany("regions", "Region").
+		Entity()
+	assert.NoErr(t, err)
+	var issues = modelbuilder.Validate([]*modelbuilder.Entity{invalid, region})
+	var messages []string
+	for _, issue := range issues {
+		assert.Eq(t, "Invalid", issue.Entity)
+		messages = append(messages, issue.Error())
+	}
+	assert.Eq(t, []string{
+		"entity Invalid: flag SharedGlobalIds is only valid for sync-enabled entities",
+		"entity Invalid property priority: hash indexes are only supported for strings, found Int",
+		"entity Invalid property code: flag IdSelfAssignable is only valid on the ID property",
+		"entity Invalid property embedding: float vectors can only be indexed using HNSW parameters",
+		"entity Invalid property owner: relation target entity Owner not found",
+	}, messages)
+
+	// ProcessModel() reports the issues before writing anything
+	dir, remove := fixture.TempDir(t, "validate-model")
+	defer remove()
+	var options = modelbuilder.Options{
+		SchemaFile: filepath.Join(dir, "model.fbs"),
+		Language:   "cpp11",
+	}
+	err = modelbuilder.ProcessModel(options, []*modelbuilder.Entity{invalid, region})
+	assert.Err(t, err)
+	assert.Eq(t, strings.Join(messages, "\n"), err.Error())
+	_, err = os.Stat(options.SchemaFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,107 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+)
+
+// benchmarkSchemaFiles is the number of schema files generated in each benchmark iteration
+const benchmarkSchemaFiles = 200
+
+// Run with `go test ./test/ -run '^$' -bench . -benchmem`; compare the results before and after changing the
+// generator (e.g. using benchstat) to make sure schemas with hundreds of files don't get slower to generate.
+
+func BenchmarkGenerateCpp(b *testing.B) {
+	benchmarkGenerate(b, &cgenerator.CGenerator{LangVersion: 14}, "")
+}
+
+func BenchmarkGenerateC(b *testing.B) {
+	benchmarkGenerate(b, &cgenerator.CGenerator{PlainC: true}, "")
+}
+
+func BenchmarkGenerateJS(b *testing.B) {
+	benchmarkGenerate(b, &jsgenerator.JSGenerator{}, "")
+}
+
+func BenchmarkGenerateTemplateOverrides(b *testing.B) {
+	dir, err := ioutil.TempDir("", "objectbox-generator-benchmark-overrides")
+	noErr(b, err)
+	defer os.RemoveAll(dir)
+	noErr(b, ioutil.WriteFile(filepath.Join(dir, "header.tmpl"), []byte(`{{define "file-header"}}// Copyright (c) ACME{{end}}`), 0600))
+
+	benchmarkGenerate(b, &cgenerator.CGenerator{LangVersion: 14}, dir)
+}
+
+// benchmarkGenerate generates a schema split into benchmarkSchemaFiles files, each declaring a few entities
+func benchmarkGenerate(b *testing.B, codeGenerator generator.CodeGenerator, overridesDir string) {
+	dir, err := ioutil.TempDir("", "objectbox-generator-benchmark")
+	noErr(b, err)
+	defer os.RemoveAll(dir)
+
+	for i := 0; i < benchmarkSchemaFiles; i++ {
+		var schema string
+		for e := 0; e < 3; e++ {
+			schema += fmt.Sprintf(`table Entity%d_%d {
+	id: ulong;
+	name: string;
+	/// objectbox:index
+	count: int;
+	price: double;
+	/// objectbox:date
+	created: long;
+	done: bool;
+}
+`, i, e)
+		}
+		noErr(b, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("schema%03d.fbs", i)), []byte(schema), 0600))
+	}
+
+	var options = generator.Options{
+		InPath:               dir,
+		ModelInfoFile:        filepath.Join(dir, "objectbox-model.json"),
+		CodeGenerator:        codeGenerator,
+		TemplateOverridesDir: overridesDir,
+	}
+
+	// the first run assigns IDs and UIDs, later runs only regenerate the files
+	noErr(b, generator.Process(options))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		noErr(b, generator.Process(options))
+	}
+}
+
+func noErr(b *testing.B, err error) {
+	if err != nil {
+		b.Fatal(err)
+	}
+}
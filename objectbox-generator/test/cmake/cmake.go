@@ -82,6 +82,26 @@ func (cmake *Cmake) CreateTempDirs() error {
 	return nil
 }
 
+// UseDirs sets up the conf and build dir in the given directory, keeping any existing contents (e.g. a CMake cache from
+// a previous build) so that subsequent builds are faster. Unlike with CreateTempDirs(), the directories are kept.
+func (cmake *Cmake) UseDirs(root string) error {
+	if len(cmake.tempRoot) != 0 {
+		return errors.New("temp root is already set")
+	}
+
+	var confDir = filepath.Join(root, "conf")
+	var buildDir = filepath.Join(root, "build")
+	for _, dir := range []string{confDir, buildDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+
+	cmake.BuildDir = buildDir
+	cmake.ConfDir = confDir
+	return nil
+}
+
 func createTempDir(parent, name string) (string, error) {
 	var path = filepath.Join(parent, name)
 	if err := os.Mkdir(path, 0700); err != nil {
@@ -0,0 +1,85 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2020-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package comparison
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/golden"
+)
+
+// buildCacheEnvVar enables a shared build environment for the compilation of the generated code: if set to a directory
+// (or to "1" for a directory in the system temp dir), each test configuration (e.g. "cpp") keeps its CMake conf and
+// build dirs there, so that the expensive configuration step and the dependencies are reused by all the test cases
+// (and subsequent test runs) instead of being set up for each test case again.
+const buildCacheEnvVar = "OBX_TEST_BUILD_CACHE"
+
+var buildCacheLocks = struct {
+	sync.Mutex
+	byConf map[string]*sync.Mutex
+}{byConf: make(map[string]*sync.Mutex)}
+
+// lockBuildCache returns the cached build environment dir for the given configuration, or an empty string if the
+// cache isn't enabled. Test cases run in parallel, so the dir is locked until the returned unlock function is called.
+func lockBuildCache(t *testing.T, confName string) (dir string, unlock func()) {
+	var root = os.Getenv(buildCacheEnvVar)
+	if len(root) == 0 {
+		return "", func() {}
+	} else if root == "1" {
+		root = filepath.Join(os.TempDir(), "objectbox-generator-build-cache")
+	}
+
+	buildCacheLocks.Lock()
+	var lock = buildCacheLocks.byConf[confName]
+	if lock == nil {
+		lock = &sync.Mutex{}
+		buildCacheLocks.byConf[confName] = lock
+	}
+	buildCacheLocks.Unlock()
+
+	lock.Lock()
+	dir, err := filepath.Abs(filepath.Join(root, confName))
+	if err != nil {
+		lock.Unlock()
+		assert.NoErr(t, err)
+	}
+	t.Logf("Using the cached build environment in %s", dir)
+	return dir, lock.Unlock
+}
+
+// replaceSources copies the files of the current test case (e.g. the generated code) to the cached build environment,
+// removing the ones of the previous test case, so that each test case is still built in isolation
+func replaceSources(t *testing.T, fromDir, toDir string) {
+	assert.NoErr(t, os.RemoveAll(toDir))
+	assert.NoErr(t, os.MkdirAll(toDir, 0700))
+
+	files, err := filepath.Glob(filepath.Join(fromDir, "*"))
+	assert.NoErr(t, err)
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			assert.NoErr(t, golden.CopyFile(file, filepath.Join(toDir, filepath.Base(file)), 0600))
+		}
+	}
+}
@@ -21,10 +21,13 @@
 package comparison
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
@@ -51,12 +54,86 @@ func (h *cTestHelper) init(t *testing.T, conf testSpec) {
 	}
 }
 
+var cGeneratorArgsRegexp = regexp.MustCompile("// *objectbox-generator (.+)[\n|\r]")
+
 func (h cTestHelper) generatorFor(t *testing.T, conf testSpec, sourceFile string, genDir string) generator.CodeGenerator {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
 	// make a copy of the default generator
 	var gen = *conf.generator.(*cgenerator.CGenerator)
+
+	if match := cGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
+		for _, arg := range strings.Fields(string(match[1])) {
+			switch {
+			case arg == "-cpp-only":
+				if !h.cpp {
+					t.Skip("the test case is only supported by the C++ generator")
+				}
+			case arg == "-cpp17-only":
+				if !h.cpp || gen.LangVersion == 11 {
+					t.Skip("the test case is only supported by the C++ generator, compiled as C++17")
+				}
+			case arg == "-strict-schema":
+				gen.StrictSchema = true
+			case strings.HasPrefix(arg, "-c-optional="):
+				if !h.cpp {
+					gen.Optional = strings.TrimPrefix(arg, "-c-optional=")
+				}
+			case strings.HasPrefix(arg, "-cpp-optional="):
+				if h.cpp {
+					gen.Optional = strings.TrimPrefix(arg, "-cpp-optional=")
+				}
+			case arg == "-extension-hooks":
+				gen.ExtensionHooks = h.cpp // C++ only
+			case arg == "-accessors":
+				gen.Accessors = h.cpp // C++ only
+			case arg == "-json-helpers":
+				gen.JsonHelpers = h.cpp // C++ only
+			case arg == "-verify-flatbuffers":
+				gen.VerifyFlatBuffers = true
+			case arg == "-reuse-buffers":
+				gen.ReuseBuffers = h.cpp // C++ only
+			case arg == "-pmr":
+				gen.Pmr = h.cpp // C++ only
+			case arg == "-thread-safety":
+				gen.ThreadSafety = h.cpp // C++ only
+			case strings.HasPrefix(arg, "-out-pattern="), arg == "-deterministic-uids", strings.HasPrefix(arg, "-uid-salt="), strings.HasPrefix(arg, "-tenant-prefix="), arg == "-benchmarks", arg == "-fixtures", arg == "-export":
+				// handled by configureOptions()
+			default:
+				t.Fatalf("unknown option '%s'", arg)
+			}
+		}
+	}
 	return &gen
 }
 
+func (cTestHelper) configureOptions(t *testing.T, sourceFile string, options *generator.Options) {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
+	if match := cGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
+		for _, arg := range strings.Fields(string(match[1])) {
+			switch {
+			case strings.HasPrefix(arg, "-out-pattern="):
+				options.OutPattern = strings.TrimPrefix(arg, "-out-pattern=")
+			case arg == "-deterministic-uids":
+				options.DeterministicUids = true
+			case strings.HasPrefix(arg, "-uid-salt="):
+				options.UidSalt = strings.TrimPrefix(arg, "-uid-salt=")
+			case strings.HasPrefix(arg, "-tenant-prefix="):
+				options.TenantPrefix = strings.TrimPrefix(arg, "-tenant-prefix=")
+			case arg == "-benchmarks":
+				options.GenerateBenchmarks = true
+			case arg == "-fixtures":
+				options.GenerateFixtures = true
+			case arg == "-export":
+				options.GenerateExport = true
+			}
+		}
+	}
+}
+
 func (cTestHelper) prepareTempDir(t *testing.T, conf testSpec, srcDir, tempDir, tempRoot string) func(err error) error {
 	return nil
 }
@@ -66,9 +143,37 @@ func (h cTestHelper) build(t *testing.T, conf testSpec, dir string, expectedErro
 		t.Skip("Compilation not available")
 	}
 
+	if h.cpp && usesJsonHelpers(t, dir) && !nlohmannJsonAvailable(repoRoot(t)) {
+		t.Skip("Compilation not available: nlohmann/json.hpp not found")
+	}
+
 	includeDir, err := filepath.Abs(dir) // main.c/cpp will include generated headers from here
 	assert.NoErr(t, err)
 
+	// std::pmr containers require C++17, built in a separate cache to keep the C++14 build configuration
+	var cpp17 = h.cpp && usesPmr(t, dir)
+	var cacheName = conf.targetLang
+	if cpp17 {
+		cacheName += "-cpp17"
+	}
+
+	cacheDir, unlockCache := lockBuildCache(t, cacheName)
+	defer unlockCache()
+	if len(cacheDir) > 0 {
+		// a fixed include dir keeps the CMakeLists.txt the same, i.e. there's no need to configure again
+		var testCaseDir = includeDir
+		includeDir = filepath.Join(cacheDir, "sources")
+		replaceSources(t, testCaseDir, includeDir)
+
+		var originalTransformer = errorTransformer
+		errorTransformer = func(err error) error {
+			if err != nil {
+				err = errors.New(strings.Replace(err.Error(), includeDir, testCaseDir, -1))
+			}
+			return originalTransformer(err)
+		}
+	}
+
 	cmak := cmake.Cmake{
 		Name:        "compilation-test",
 		IsCpp:       h.cpp,
@@ -76,13 +181,19 @@ func (h cTestHelper) build(t *testing.T, conf testSpec, dir string, expectedErro
 		LinkDirs:    build.LibDirs(repoRoot(t)),
 		LinkLibs:    []string{"objectbox"},
 	}
-	assert.NoErr(t, cmak.CreateTempDirs())
-	defer cmak.RemoveTempDirs()
+	if len(cacheDir) > 0 {
+		assert.NoErr(t, cmak.UseDirs(cacheDir))
+	} else {
+		assert.NoErr(t, cmak.CreateTempDirs())
+		defer cmak.RemoveTempDirs()
+	}
 
 	var mainFile string
 	if cmak.IsCpp {
 		if conf.targetLang == "cpp11" {
 			cmak.Standard = 11
+		} else if cpp17 {
+			cmak.Standard = 17
 		} else {
 			cmak.Standard = 14
 		}
@@ -114,7 +225,8 @@ func (h cTestHelper) build(t *testing.T, conf testSpec, dir string, expectedErro
 		files, err := ioutil.ReadDir(includeDir)
 		assert.NoErr(t, err)
 		for _, file := range files {
-			if conf.generator.IsGeneratedFile(file.Name()) {
+			// benchmarks need google-benchmark and define their own main()
+			if conf.generator.IsGeneratedFile(file.Name()) && !strings.HasSuffix(file.Name(), ".obx.bench.cpp") {
 				mainSrc = mainSrc + "#include \"" + file.Name() + "\"\n"
 			}
 		}
@@ -137,3 +249,41 @@ func (h cTestHelper) build(t *testing.T, conf testSpec, dir string, expectedErro
 		t.Logf("build output:\n%s", string(stdOut))
 	}
 }
+
+// usesJsonHelpers checks whether the C++ headers generated in the given directory depend on nlohmann::json
+func usesJsonHelpers(t *testing.T, dir string) bool {
+	headers, err := filepath.Glob(filepath.Join(dir, "*.obx.hpp"))
+	assert.NoErr(t, err)
+	for _, header := range headers {
+		source, err := ioutil.ReadFile(header)
+		assert.NoErr(t, err)
+		if strings.Contains(string(source), "#include <nlohmann/json.hpp>") {
+			return true
+		}
+	}
+	return false
+}
+
+// usesPmr checks whether any of the generated headers in the given directory uses std::pmr containers
+func usesPmr(t *testing.T, dir string) bool {
+	headers, err := filepath.Glob(filepath.Join(dir, "*.obx.hpp"))
+	assert.NoErr(t, err)
+	for _, header := range headers {
+		source, err := ioutil.ReadFile(header)
+		assert.NoErr(t, err)
+		if strings.Contains(string(source), "#include <memory_resource>") {
+			return true
+		}
+	}
+	return false
+}
+
+// nlohmannJsonAvailable checks whether nlohmann/json.hpp can be found in the build or the system include directories
+func nlohmannJsonAvailable(repoRoot string) bool {
+	for _, dir := range append(build.IncludeDirs(repoRoot), "/usr/include", "/usr/local/include") {
+		if _, err := os.Stat(filepath.Join(dir, "nlohmann", "json.hpp")); err == nil {
+			return true
+		}
+	}
+	return false
+}
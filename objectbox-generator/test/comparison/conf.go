@@ -25,7 +25,9 @@ import (
 
 	"github.com/objectbox/objectbox-generator/v4/internal/generator"
 	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
 	gogenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/go"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
 )
 
 type testHelper interface {
@@ -35,6 +37,9 @@ type testHelper interface {
 	// generatorFor constructs and configures a code generator for the given source file
 	generatorFor(t *testing.T, conf testSpec, sourceFile string, genDir string) generator.CodeGenerator
 
+	// configureOptions adjusts generator options (e.g. output file naming) for the given source file
+	configureOptions(t *testing.T, sourceFile string, options *generator.Options)
+
 	// prepareTempDir prepares tempDir contents (already a copy of the srcDir) with any language specific setup.
 	// Returns an errorTransformer.
 	prepareTempDir(t *testing.T, conf testSpec, srcDir, tempDir, tempRoot string) func(err error) error
@@ -56,5 +61,13 @@ var confs = map[string]testSpec{
 	"fbs-c":     {"c", ".fbs", []string{".obx.h"}, &cgenerator.CGenerator{PlainC: true, LangVersion: -1}, &cTestHelper{cpp: false}},
 	"fbs-cpp":   {"cpp", ".fbs", []string{".obx.hpp", ".obx.cpp"}, &cgenerator.CGenerator{PlainC: false, LangVersion: 14}, &cTestHelper{cpp: true}},
 	"fbs-cpp11": {"cpp11", ".fbs", []string{".obx.hpp", ".obx.cpp"}, &cgenerator.CGenerator{PlainC: false, LangVersion: 11}, &cTestHelper{cpp: true}},
-	"go":        {"go", ".go", []string{".obx.go"}, &gogenerator.GoGenerator{}, &goTestHelper{}},
+	"go":        {"go", ".go", []string{".obx.go", ".obx.fbs"}, &gogenerator.GoGenerator{}, &goTestHelper{}},
+
+	// JS test cases are FlatBuffers schemas in a separate directory, with expected files per module format
+	"js-esm": {"js", ".fbs", []string{".obx.js", ".obx.bench.js"}, &jsgenerator.JSGenerator{ModuleFormat: jsgenerator.ModuleFormatESM}, &jsTestHelper{}},
+	"js-cjs": {"js", ".fbs", []string{".obx.js", ".obx.bench.js"}, &jsgenerator.JSGenerator{ModuleFormat: jsgenerator.ModuleFormatCommonJS}, &jsTestHelper{}},
+
+	// documentation test cases are FlatBuffers schemas in a separate directory, with expected files per format
+	"docs-md":   {"md", ".fbs", []string{".obx.md"}, &docsgenerator.DocsGenerator{Format: docsgenerator.FormatMarkdown, Source: &cgenerator.CGenerator{LangVersion: 14}}, &docsTestHelper{}},
+	"docs-html": {"html", ".fbs", []string{".obx.html"}, &docsgenerator.DocsGenerator{Format: docsgenerator.FormatHtml, Source: &cgenerator.CGenerator{LangVersion: 14}}, &docsTestHelper{}},
 }
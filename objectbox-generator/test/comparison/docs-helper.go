@@ -0,0 +1,52 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package comparison
+
+import (
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	cgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/c"
+	docsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/docs"
+)
+
+// docsTestHelper tests the documentation generator; the source files are FlatBuffers schemas
+type docsTestHelper struct{}
+
+func (docsTestHelper) init(t *testing.T, conf testSpec) {}
+
+func (docsTestHelper) generatorFor(t *testing.T, conf testSpec, sourceFile string, genDir string) generator.CodeGenerator {
+	// make a copy of the default generator, including the one reading the sources
+	var gen = *conf.generator.(*docsgenerator.DocsGenerator)
+	var source = *gen.Source.(*cgenerator.CGenerator)
+	gen.Source = &source
+	return &gen
+}
+
+func (docsTestHelper) configureOptions(t *testing.T, sourceFile string, options *generator.Options) {}
+
+func (docsTestHelper) prepareTempDir(t *testing.T, conf testSpec, srcDir, tempDir, tempRoot string) func(err error) error {
+	return nil
+}
+
+func (docsTestHelper) build(t *testing.T, conf testSpec, dir string, expectedError error, errorTransformer func(err error) error) {
+	t.Skip("documentation isn't compiled")
+}
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/objectbox/objectbox-generator/v4/test/assert"
+	"github.com/objectbox/objectbox-generator/v4/test/golden"
 )
 
 // used during development of generator to overwrite the "golden" files
@@ -44,11 +45,10 @@ func TestCompare(t *testing.T) {
 	} else if parts := strings.Split(*target, "/"); len(parts) == 1 {
 		generateAllDirs(t, *overwriteExpected, parts[0])
 	} else if len(parts) == 2 {
-		srcType, genType := typesFromConfKey(parts[0])
 		conf, ok := confs[parts[0]]
 		assert.True(t, ok)
 		conf.helper.init(t, conf)
-		generateOneDir(t, *overwriteExpected, conf, srcType, genType, parts[1])
+		golden.RunCase(t, goldenConfig(parts[0], conf, *overwriteExpected), parts[1])
 	} else {
 		t.Fatal("invalid target specification, expected 1 or two parts separated by '/'")
 	}
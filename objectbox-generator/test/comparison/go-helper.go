@@ -54,11 +54,22 @@ func (h goTestHelper) generatorFor(t *testing.T, conf testSpec, sourceFile strin
 	if match := goGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
 		var args = argsToMap(string(match[1]))
 		for name, value := range args {
-			_ = value // get rid of the testHelper warning until we start using some options with values
-
 			switch name {
 			case "byValue":
 				gen.ByValue = true
+			case "fbs":
+				gen.Fbs = true
+			case "noAutoConverters":
+				gen.NoAutoConverters = true
+			case "entityHelpers":
+				gen.EntityHelpers = true
+			case "tinyGoCheck":
+				gen.TinyGoCheck = true
+			case "benchmarks", "fixtures", "export":
+				// handled by configureOptions()
+			case "typeMappings":
+				gen.TypeMappings, err = gogenerator.LoadTypeMappings(path.Join(path.Dir(sourceFile), value))
+				assert.NoErr(t, err)
 			default:
 				t.Fatalf("unknown option '%s'", name)
 			}
@@ -67,6 +78,24 @@ func (h goTestHelper) generatorFor(t *testing.T, conf testSpec, sourceFile strin
 	return &gen
 }
 
+func (goTestHelper) configureOptions(t *testing.T, sourceFile string, options *generator.Options) {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
+	if match := goGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
+		var args = argsToMap(string(match[1]))
+		if _, found := args["benchmarks"]; found {
+			options.GenerateBenchmarks = true
+		}
+		if _, found := args["fixtures"]; found {
+			options.GenerateFixtures = true
+		}
+		if _, found := args["export"]; found {
+			options.GenerateExport = true
+		}
+	}
+}
+
 func argsToMap(args string) map[string]string {
 	var result = map[string]string{}
 
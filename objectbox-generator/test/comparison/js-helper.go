@@ -0,0 +1,107 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package comparison
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// jsTestHelper tests the JS generator; the source files are FlatBuffers schemas, taking the same
+// "// objectbox-generator" arguments line as the C/C++ tests (see cGeneratorArgsRegexp)
+type jsTestHelper struct{}
+
+func (h *jsTestHelper) init(t *testing.T, conf testSpec) {}
+
+func (jsTestHelper) generatorFor(t *testing.T, conf testSpec, sourceFile string, genDir string) generator.CodeGenerator {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
+	// make a copy of the default generator
+	var gen = *conf.generator.(*jsgenerator.JSGenerator)
+
+	if match := cGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
+		for _, arg := range strings.Fields(string(match[1])) {
+			switch arg {
+			case "-strict-schema":
+				gen.StrictSchema = true
+			case "-extension-hooks":
+				gen.ExtensionHooks = true
+			case "-accessors":
+				gen.Accessors = true
+			case "-namespace-modules":
+				gen.NamespaceModules = true
+			case "-browser-safe":
+				gen.BrowserSafe = true
+			case "-verify-flatbuffers":
+				gen.VerifyFlatBuffers = true
+			case "-typed-arrays":
+				gen.TypedArrays = true
+			case "-validation":
+				gen.Validation = true
+			case "-id-type=" + jsgenerator.IdTypeString:
+				gen.IdType = jsgenerator.IdTypeString
+			case "-benchmarks":
+				// handled by configureOptions()
+			default:
+				t.Fatalf("unknown option '%s'", arg)
+			}
+		}
+	}
+	return &gen
+}
+
+func (jsTestHelper) configureOptions(t *testing.T, sourceFile string, options *generator.Options) {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
+	if match := cGeneratorArgsRegexp.FindSubmatch(source); len(match) > 1 {
+		for _, arg := range strings.Fields(string(match[1])) {
+			if arg == "-benchmarks" {
+				options.GenerateBenchmarks = true
+			}
+		}
+	}
+}
+
+func (jsTestHelper) prepareTempDir(t *testing.T, conf testSpec, srcDir, tempDir, tempRoot string) func(err error) error {
+	return nil
+}
+
+// build runs the generated bindings under Node, see runJsRoundTrip()
+func (jsTestHelper) build(t *testing.T, conf testSpec, dir string, expectedError error, errorTransformer func(err error) error) {
+	// enabled on CI, see the test-depend-js make target; node missing there is an error instead of a skipped test
+	var nodeModules = os.Getenv(jsNodeModulesEnvVar)
+	if len(nodeModules) == 0 {
+		t.Skip("JS round-trip tests not available: set " + jsNodeModulesEnvVar + " to a node_modules directory with the flatbuffers package")
+	}
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Fatalf("JS round-trip tests enabled by %s but node not found: %s", jsNodeModulesEnvVar, err)
+	}
+	runJsRoundTrip(t, conf, dir, nodeModules)
+}
@@ -0,0 +1,267 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package comparison
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	jsgenerator "github.com/objectbox/objectbox-generator/v4/internal/generator/js"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// jsNodeModulesEnvVar points to a node_modules directory with the flatbuffers package, enabling the JS round-trip tests
+const jsNodeModulesEnvVar = "OBX_TEST_NODE_MODULES"
+
+// jsPropertyClassRegexp finds the property classes the bindings use from the objectbox package
+var jsPropertyClassRegexp = regexp.MustCompile(`properties\.(\w+)`)
+
+// runJsRoundTrip executes the generated bindings under Node: for each entity, an object with sample values is written
+// using toFlatbuffers() and read back using fromFlatbuffers(), expecting the same values (see jsRoundTripScript).
+// Values not read back at all fail the test, unless the property type is listed by jsgenerator.UnsupportedPropertyTypes().
+// The objectbox package isn't needed, the property classes the bindings declare are replaced by a stub.
+func runJsRoundTrip(t *testing.T, conf testSpec, dir string, nodeModules string) {
+	var commonJS = conf.generator.(*jsgenerator.JSGenerator).ModuleFormat == jsgenerator.ModuleFormatCommonJS
+
+	var bindings []string
+	var propertyClasses = make(map[string]bool)
+	assert.NoErr(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".obx.js") {
+			return err
+		}
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range jsPropertyClassRegexp.FindAllStringSubmatch(string(source), -1) {
+			propertyClasses[match[1]] = true
+		}
+		bindings = append(bindings, path)
+		return nil
+	}))
+	if len(bindings) == 0 {
+		t.Skip("no bindings generated")
+	}
+
+	var stubDir = filepath.Join(dir, "roundtrip")
+	assert.NoErr(t, os.MkdirAll(stubDir, 0700))
+
+	var classNames []string
+	for name := range propertyClasses {
+		classNames = append(classNames, name)
+	}
+	sort.Strings(classNames)
+	var stub strings.Builder
+	var stubFile = "Property.mjs"
+	if commonJS {
+		stubFile = "Property.cjs"
+	}
+	for _, name := range classNames {
+		var class = fmt.Sprintf("class %s {\n    constructor(id, uid) {\n        this.id = id;\n        this.uid = uid;\n    }\n}", name)
+		if commonJS {
+			fmt.Fprintf(&stub, "exports.%s = %s;\n", name, class)
+		} else {
+			fmt.Fprintf(&stub, "export %s\n", class)
+		}
+	}
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(stubDir, stubFile), []byte(stub.String()), 0600))
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(stubDir, "roundtrip.mjs"), []byte(jsRoundTripScript), 0600))
+
+	// properties of these types may be left unset when reading, any other property must be read back
+	var unsupportedTypes []int
+	for propertyType := range jsgenerator.UnsupportedPropertyTypes() {
+		unsupportedTypes = append(unsupportedTypes, int(propertyType))
+	}
+	sort.Ints(unsupportedTypes)
+	unsupportedJSON, err := json.Marshal(unsupportedTypes)
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(stubDir, "unsupported-types.json"), unsupportedJSON, 0600))
+
+	var moduleType = "module"
+	if commonJS {
+		moduleType = "commonjs"
+	}
+	packageJSON, err := json.MarshalIndent(map[string]interface{}{
+		"type":    moduleType,
+		"imports": map[string]string{"#objectbox/js/model/Property.js": "./roundtrip/" + stubFile},
+	}, "", "  ")
+	assert.NoErr(t, err)
+	assert.NoErr(t, ioutil.WriteFile(filepath.Join(dir, "package.json"), packageJSON, 0600))
+
+	nodeModules, err = filepath.Abs(nodeModules)
+	assert.NoErr(t, err)
+	assert.NoErr(t, os.Symlink(nodeModules, filepath.Join(dir, "node_modules")))
+
+	var cmd = exec.Command("node", append([]string{filepath.Join(stubDir, "roundtrip.mjs"), dir}, bindings...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("JS round-trip failed: %s\n%s", err, output)
+	} else if testing.Verbose() {
+		t.Logf("%s", output)
+	}
+}
+
+// jsRoundTripScript is run by node with the directory of the model JSON file and the bindings to test as arguments
+const jsRoundTripScript = `
+import * as fs from "node:fs";
+import * as path from "node:path";
+import { isDeepStrictEqual } from "node:util";
+import { pathToFileURL } from "node:url";
+import * as fb from "flatbuffers";
+
+const [dir, ...bindings] = process.argv.slice(2);
+const model = JSON.parse(fs.readFileSync(path.join(dir, "objectbox-model.json"), "utf8"));
+const unsupportedTypes = new Set(JSON.parse(fs.readFileSync(path.join(dir, "roundtrip", "unsupported-types.json"), "utf8")));
+
+// the entity classes exported by the bindings, including the nested namespace modules and CommonJS exports
+const classes = new Map();
+const visited = new Set();
+const collect = (exports) => {
+    if (visited.has(exports)) return;
+    visited.add(exports);
+    for (const value of Object.values(exports)) {
+        if (typeof value === "function" && typeof value.fromFlatbuffers === "function") {
+            classes.set(value.entityInfo.get("uid"), value); // by UID, class names may differ, e.g. "class_"
+        } else if (value !== null && typeof value === "object" && !Object.isFrozen(value)) {
+            collect(value); // enums are frozen
+        }
+    }
+};
+for (const binding of bindings) {
+    collect(await import(pathToFileURL(binding).href));
+}
+
+// sample values by the model property type
+const samples = {
+    1: true, // Bool
+    2: 7, // Byte
+    3: 1234, // Short
+    4: 65, // Char
+    5: 123456, // Int
+    6: 1234567890123n, // Long
+    7: 1.5, // Float
+    8: 2.25, // Double
+    9: "sample ü ✓", // String
+    10: 1700000000000n, // Date
+    11: 7n, // Relation
+    12: 1700000000000000001n, // DateNano
+    23: new Uint8Array([1, 2, 3]), // ByteVector
+    28: [1.5, 2.5], // FloatVector; the length is adjusted for fixed-length arrays
+    30: ["a", "b"], // StringVector
+};
+
+// IDs are set and read using setId() & getId(), with a value in the upper half of the unsigned 64-bit range
+const idSample = 18446744073709551557n;
+const isId = (property) => (property.flags & 1) !== 0;
+
+// fields are looked up by the property UIDs, their names may differ from the model, e.g. "default_"
+const uidOf = (idUid) => BigInt(idUid.split(":")[1]);
+const fieldNames = (Entity) => {
+    const result = new Map();
+    for (const [key, value] of Object.entries(Entity)) {
+        if (key.startsWith("_") && value !== null && typeof value === "object" && typeof value.uid === "bigint") {
+            result.set(value.uid, key.slice(1));
+        }
+    }
+    return result;
+};
+const accessor = (object, prefix, name) => object[prefix + name[0].toUpperCase() + name.slice(1)];
+const get = (object, name, field) => {
+    const getter = accessor(object, "get", name);
+    return typeof getter === "function" ? getter.call(object) : object[field];
+};
+const set = (object, name, field, value) => {
+    const setter = accessor(object, "set", name);
+    typeof setter === "function" ? setter.call(object, value) : (object[field] = value);
+};
+const normalize = (value) => (ArrayBuffer.isView(value) ? Array.from(value) : value);
+
+let failures = 0;
+for (const entity of model.entities) {
+    const Entity = classes.get(uidOf(entity.id));
+    if (!Entity) {
+        console.log("FAIL " + entity.name + ": class not exported by the bindings");
+        failures++;
+        continue;
+    }
+
+    const fields = fieldNames(Entity);
+    const field = (name) => fields.get(uidOf(entity.properties.find((property) => property.name === name).id));
+    const object = new Entity();
+    const expected = new Map();
+    for (const property of entity.properties) {
+        if (!(property.type in samples)) {
+            console.log("skip " + entity.name + "." + property.name + ": no sample value for type " + property.type);
+            continue;
+        }
+        if (isId(property)) {
+            expected.set(property.name, idSample);
+            object.setId(idSample);
+            continue;
+        }
+        expected.set(property.name, samples[property.type]);
+        set(object, property.name, field(property.name), samples[property.type]);
+    }
+
+    let bytes;
+    for (let attempt = 0; !bytes; attempt++) {
+        try {
+            bytes = Entity.toFlatbuffers(new fb.Builder(256), object).slice();
+        } catch (e) {
+            // fixed-length float arrays (and HNSW-indexed vectors with -validation) report the expected length,
+            // e.g. "Task.embedding: expected 3 elements"
+            const messages = e.name === "ValidationError" ? e.errors.map((error) => error.message) : e instanceof RangeError ? [e.message] : [];
+            const match = attempt < entity.properties.length && messages.map((message) => /^\w+\.(\w+): expected (\d+) elements/.exec(message)).find(Boolean);
+            if (!match) throw e;
+            const value = Array.from({ length: Number(match[2]) }, (_, i) => i + 0.5);
+            expected.set(match[1], value);
+            set(object, match[1], field(match[1]), value);
+        }
+    }
+
+    const read = Entity.fromFlatbuffers(bytes);
+    for (const [name, value] of expected) {
+        const property = entity.properties.find((property) => property.name === name);
+        const actual = isId(property) ? read.getId() : get(read, name, field(name));
+        if (actual === undefined && unsupportedTypes.has(property.type)) {
+            console.log("skip " + entity.name + "." + name + ": type " + property.type + " isn't read by the generated code");
+        } else if (actual === undefined) {
+            console.log("FAIL " + entity.name + "." + name + ": not read by the generated code");
+            failures++;
+        } else if (!isDeepStrictEqual(normalize(actual), normalize(value))) {
+            console.log("FAIL " + entity.name + "." + name + ": expected " + String(value) + ", got " + String(actual));
+            failures++;
+        } else {
+            console.log("ok " + entity.name + "." + name);
+        }
+    }
+}
+process.exit(failures > 0 ? 1 : 0);
+`
@@ -0,0 +1,9 @@
+package object
+
+// Embedding tests fixed-length arrays, stored as float vectors
+type Embedding struct {
+	Id     uint64
+	Vector [4]float32 `objectbox:"index:hnsw"` // the HNSW index defaults to the array length as its dimensions
+	Color  [3]float32
+	Values []float32
+}
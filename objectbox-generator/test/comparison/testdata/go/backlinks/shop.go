@@ -0,0 +1,23 @@
+package object
+
+// Customer has backlinks from Order (with a single relation to Customer) and Ticket (with two relations to Customer)
+type Customer struct {
+	Id       uint64
+	Name     string
+	Orders   []*Order  `objectbox:"backlink"`
+	Reported []*Ticket `objectbox:"backlink:Reporter"`
+	Assigned []*Ticket `objectbox:"backlink:assigneeId"`
+}
+
+type Order struct {
+	Id       uint64
+	Number   string
+	Customer *Customer `objectbox:"link"`
+}
+
+type Ticket struct {
+	Id         int64
+	Title      string
+	Reporter   Customer `objectbox:"link"`
+	AssigneeId uint64   `objectbox:"link:Customer"`
+}
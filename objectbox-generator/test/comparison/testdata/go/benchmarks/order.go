@@ -0,0 +1,25 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -benchmarks
+
+type Customer struct {
+	Id   uint64
+	Name string `objectbox:"index"`
+}
+
+// Order can't be loaded without a store because of the eagerly loaded Customers relation; its Load benchmark is skipped
+type Order struct {
+	Id        uint64
+	Number    string
+	Date      int64     `objectbox:"date"`
+	Customer  *Customer `objectbox:"link"`
+	Customers []*Customer
+	Total     float64
+	Location  [4]float32
+}
+
+type Note struct {
+	Id        uint64
+	Text      string
+	Customers []*Customer `objectbox:"lazy"`
+}
@@ -0,0 +1,32 @@
+package object
+
+// Removing a Customer removes its Addresses (standalone relation) and its Orders (to-one relation Order.Customer),
+// which in turn remove their Items; Notes only refer to the Customer and are kept.
+type Customer struct {
+	Id        uint64
+	Name      string
+	Addresses []*Address `objectbox:"cascade"`
+}
+
+type Address struct {
+	Id     uint64
+	Street string
+}
+
+type Order struct {
+	Id       uint64
+	Number   string
+	Customer *Customer `objectbox:"link cascade"`
+	Items    []*Item   `objectbox:"cascade"`
+}
+
+type Item struct {
+	Id   uint64
+	Name string
+}
+
+type Note struct {
+	Id       uint64
+	Text     string
+	Customer *Customer `objectbox:"link"`
+}
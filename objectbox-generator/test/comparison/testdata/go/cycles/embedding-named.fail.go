@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't prepare bindings for cycles/embedding-named.fail.go: embedded struct cycle detected: EmbeddingNamedChainA.BPtr.CPtr on property APtr found in EmbeddingNamedChainA.BPtr.CPtr
+// ERROR = OBXG3003
 
 type EmbeddingNamedChainA struct {
 	Id   uint64
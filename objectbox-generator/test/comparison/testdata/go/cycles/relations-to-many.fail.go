@@ -1,6 +1,6 @@
 package object
 
-// ERROR = relation cycle detected: RelationToManyChainA.BPtrSlice.CPtrSlice.APtrSlice (RelationToManyChainA)
+// ERROR = OBXG1006
 
 type RelationToManyChainA struct {
 	Id        uint64
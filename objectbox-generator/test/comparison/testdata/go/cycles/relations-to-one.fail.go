@@ -1,6 +1,6 @@
 package object
 
-// ERROR = relation cycle detected: RelationToOneChainA.BPtr.CPtr.APtr (RelationToOneChainA)
+// ERROR = OBXG1006
 
 type RelationToOneChainA struct {
 	Id   uint64
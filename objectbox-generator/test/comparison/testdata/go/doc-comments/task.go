@@ -0,0 +1,15 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -fbs
+
+// Task is a to-do item.
+//
+// `objectbox:"sync"`
+type Task struct {
+	Id uint64
+
+	// Text is displayed to the user.
+	Text string
+
+	Priority int // higher is more urgent
+}
@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't merge model information: merging entity Negative1: property Value: duplicate property name (note that property names are case insensitive)
+// ERROR = OBXG2001
 
 // both contain Value field but of two distinct types
 type Negative1 struct {
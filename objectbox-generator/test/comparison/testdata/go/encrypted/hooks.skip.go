@@ -0,0 +1,27 @@
+package object
+
+type Insurance struct {
+	Company string
+	Number  string `objectbox:"encrypted"`
+}
+
+// objectboxEncrypt would use a real cipher, e.g. AES-GCM with a key chosen by the property
+func objectboxEncrypt(property string, plaintext []byte) ([]byte, error) {
+	var ciphertext = make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		ciphertext[i] = b ^ 0x5a
+	}
+	return ciphertext, nil
+}
+
+func objectboxDecrypt(property string, ciphertext []byte) ([]byte, error) {
+	return objectboxEncrypt(property, ciphertext)
+}
+
+func vaultEncrypt(property string, plaintext []byte) ([]byte, error) {
+	return objectboxEncrypt(property, plaintext)
+}
+
+func vaultDecrypt(property string, ciphertext []byte) ([]byte, error) {
+	return objectboxDecrypt(property, ciphertext)
+}
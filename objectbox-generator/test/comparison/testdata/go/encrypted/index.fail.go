@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for encrypted/index.fail.go: encrypted properties can't be annotated with `index` on property Email found in User
+
+type User struct {
+	Id    uint64
+	Email string `objectbox:"encrypted index"`
+}
@@ -0,0 +1,11 @@
+package object
+
+// Encrypted properties are stored as bytes, encrypted by the hooks in hooks.skip.go (including the embedded Insurance)
+type Patient struct {
+	Id        uint64
+	Name      string `objectbox:"index"`
+	Diagnosis string `objectbox:"encrypted"`
+	Scan      []byte `objectbox:"encrypted"`
+	Insurance Insurance
+	Notes     string `objectbox:"encrypted:vault"` // using vaultEncrypt() and vaultDecrypt()
+}
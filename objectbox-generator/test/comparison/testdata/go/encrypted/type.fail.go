@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for encrypted/type.fail.go: encrypted properties must be of type string or []byte, found int64 on property Balance found in Account
+
+type Account struct {
+	Id      uint64
+	Balance int64 `objectbox:"encrypted"`
+}
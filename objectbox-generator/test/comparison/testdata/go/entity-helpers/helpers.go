@@ -0,0 +1,25 @@
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -entityHelpers
+
+package object
+
+import "time"
+
+// Note has String(), Equal() and Clone() methods generated along with the binding
+type Note struct {
+	Id      uint64
+	Text    string
+	Rating  *int32
+	Data    []byte
+	Tags    []string
+	Vector  [3]float32
+	Created time.Time
+	Author  *Person `objectbox:"link"`
+	Readers []*Person
+	Audit
+	Location *Location `objectbox:"inline"`
+}
+
+type Person struct {
+	Id   uint64
+	Name string
+}
@@ -0,0 +1,10 @@
+package object
+
+type Audit struct {
+	Revision int64
+}
+
+type Location struct {
+	Name   string
+	Coords []float32
+}
@@ -0,0 +1,35 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -export
+
+import "time"
+
+type Customer struct {
+	Id     uint64
+	Name   string `objectbox:"unique"`
+	Rating *float32
+}
+
+// Order needs an ObjectBox instance to be imported because of the linked Customer
+type Order struct {
+	Id        uint64
+	Number    string
+	Created   time.Time `objectbox:"date"`
+	Customer  *Customer `objectbox:"link"`
+	Items     []*Item
+	Paid      bool
+	Quantity  int `objectbox:"type:int16"`
+	Total     float64
+	Comment   *string
+	Tags      []string
+	Payload   []byte
+	Embedding []float32
+	*Shipping `objectbox:"inline"`
+}
+
+type Item struct {
+	Id       uint64
+	Name     string
+	Amount   uint32
+	Discount *int8
+}
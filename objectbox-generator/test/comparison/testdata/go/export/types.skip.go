@@ -0,0 +1,6 @@
+package object
+
+type Shipping struct {
+	Address string
+	Express bool
+}
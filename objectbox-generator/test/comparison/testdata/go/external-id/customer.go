@@ -0,0 +1,19 @@
+package object
+
+type Customer struct {
+	Id   uint64
+	Uuid string `objectbox:"external-id"`
+	Name string
+}
+
+type Order struct {
+	Id           uint64
+	ExternalCode string `objectbox:"external-id index:value"`
+}
+
+// `objectbox:"external-name:documents"`
+type Document struct {
+	Id      uint64
+	MongoId string `objectbox:"external-id external-type:MongoId external-name:_id"`
+	Title   string
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = model finalization failed: entity ExternalIdWithInvalidExternalType 4:5617773211005988520 is invalid: property Document 2:7144924247938981575 is invalid: external-type Json can't be used on an external ID - use one of Int128, Int128Vector, MongoId, MongoIdVector, Uuid, UuidString, UuidV4, UuidV4String, UuidVector
+
+type ExternalIdWithInvalidExternalType struct {
+	Id       uint64
+	Document string `objectbox:"external-id external-type:Json"`
+}
@@ -0,0 +1,7 @@
+package object
+
+// ERROR = can't prepare bindings for external-id/id.fail.go: external-id and id annotations cannot be used at the same time, an external ID is a secondary ID on property Id found in ExternalIdOnId
+
+type ExternalIdOnId struct {
+	Id string `objectbox:"id external-id"`
+}
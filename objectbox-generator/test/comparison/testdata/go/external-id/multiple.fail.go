@@ -0,0 +1,9 @@
+package object
+
+// ERROR = OBXG1010
+
+type MultipleExternalIds struct {
+	Id      uint64
+	Uuid    string `objectbox:"external-id"`
+	MongoId string `objectbox:"external-id external-type:MongoId"`
+}
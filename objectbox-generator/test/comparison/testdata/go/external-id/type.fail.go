@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for external-id/type.fail.go: invalid underlying type 'Long' for external-id field; expecting string on property Uuid found in ExternalIdWithInvalidType
+
+type ExternalIdWithInvalidType struct {
+	Id   uint64
+	Uuid int64 `objectbox:"external-id"`
+}
@@ -4,7 +4,7 @@ package externaltype
 type Entity struct {
 	ID            int64          `objectbox:"id"`
 	Name          string         `objectbox:"external-name:MyExtPropName"`
-	Email         string         `objectbox:"external-name:MyExtPropName2 external-type:Uuid"`
+	Email         string         `objectbox:"external-name:MyExtPropName2 external-type:UuidString"`
 	ChildEntities []*ChildEntity `objectbox:"external-name:MyExtRelName external-type:MongoId"`
 }
 
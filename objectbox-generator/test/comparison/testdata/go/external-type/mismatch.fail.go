@@ -0,0 +1,8 @@
+package externaltype
+
+// ERROR = model finalization failed: entity MismatchedExternalType 3:8274930044578894929 is invalid: property Data 2:2661732831099943416 is invalid: external-type Json requires the property type to be String but found ByteVector
+
+type MismatchedExternalType struct {
+	Id   uint64
+	Data []byte `objectbox:"external-type:Json"`
+}
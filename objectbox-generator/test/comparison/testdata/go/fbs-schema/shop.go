@@ -0,0 +1,42 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -fbs
+
+// `objectbox:"sync"`
+type Customer struct {
+	Id   uint64
+	Name string `objectbox:"index"`
+}
+
+// `objectbox:"sync"`
+type Order struct {
+	Id        uint64    `objectbox:"id(assignable)"`
+	Number    string    `objectbox:"unique"`
+	Date      int64     `objectbox:"date"`
+	Customer  *Customer `objectbox:"link"`
+	Customers []*Customer
+	Paid      bool
+	Priority  int8
+	Flags     uint8
+	Quantity  int16
+	Discount  int32
+	Items     uint32
+	Total     float64
+	Weight    float32
+	Signature []byte
+	Tags      []string
+	Location  []float32 `objectbox:"index:hnsw"`
+	Note      string    `objectbox:"index:hash64"`
+	Uuid      []byte    `objectbox:"external-type:Uuid external-name:uuid"`
+	Status    OrderStatus
+	Embedding [3]float32
+}
+
+// OrderStatus is declared as an enum in the FlatBuffers schema
+type OrderStatus uint16
+
+const (
+	OrderStatusNew OrderStatus = iota
+	OrderStatusPaid
+	OrderStatusShipped
+)
@@ -0,0 +1,43 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -fixtures
+
+import "time"
+
+type Status int8
+
+const (
+	StatusNew Status = iota
+	StatusPaid
+	StatusShipped
+)
+
+type Customer struct {
+	Id   uint64
+	Name string `objectbox:"index"`
+}
+
+// Order keeps its relations, the time.Time field (stored using a converter) and the ID empty in random fixtures
+type Order struct {
+	Id        uint64
+	Number    string
+	Date      int64     `objectbox:"date"`
+	Created   time.Time `objectbox:"date"`
+	Customer  *Customer `objectbox:"link"`
+	Customers []*Customer
+	Status    Status
+	Paid      bool
+	Quantity  int `objectbox:"type:int16"`
+	Total     float64
+	Discount  *float32
+	Tags      []string
+	Payload   []byte
+	Location  [4]float32
+	Embedding []float32 `objectbox:"index:hnsw"`
+	*Shipping `objectbox:"inline"`
+}
+
+type Note struct {
+	Id   uint64
+	Text *string
+}
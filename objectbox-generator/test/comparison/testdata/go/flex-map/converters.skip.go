@@ -0,0 +1,13 @@
+package object
+
+import "encoding/json"
+
+func labelsJsonToEntityProperty(dbValue []byte) (map[string]string, error) {
+	var result map[string]string
+	err := json.Unmarshal(dbValue, &result)
+	return result, err
+}
+
+func labelsJsonToDatabaseValue(goValue map[string]string) ([]byte, error) {
+	return json.Marshal(goValue)
+}
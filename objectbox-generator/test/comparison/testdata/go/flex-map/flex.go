@@ -0,0 +1,11 @@
+package object
+
+type Tags map[string]string
+
+type FlexEntity struct {
+	Id         uint64
+	Labels     map[string]string
+	Attributes map[string]interface{}
+	Tags       Tags
+	Custom     map[string]string `objectbox:"type:[]byte converter:labelsJson"`
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for flex-map/unsupported.fail.go: unsupported map type map[string]int - only map[string]string and map[string]interface{} are supported, use a custom converter for others on property Counts found in UnsupportedMap
+
+type UnsupportedMap struct {
+	Id     uint64
+	Counts map[string]int
+}
@@ -0,0 +1,11 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -export
+
+// Document tests float vectors stored with half precision
+type Document struct {
+	Id        uint64
+	Embedding []float32  `objectbox:"index:hnsw storage-type:float16"`
+	Thumbnail [4]float32 `objectbox:"storage-type:float16"`
+	Scores    []float32  `objectbox:"storage-type:float32"` // the default
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for float16-vectors/type.fail.go: storage-type is only supported for float vectors, found String on property Title found in Note
+
+type Note struct {
+	Id    uint64
+	Title string `objectbox:"storage-type:float16"`
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for float16-vectors/unknown.fail.go: unknown storage-type bfloat16, expecting 'float16' or 'float32' on property Values found in Sample
+
+type Sample struct {
+	Id     uint64
+	Values []float32 `objectbox:"storage-type:bfloat16"`
+}
@@ -0,0 +1,38 @@
+package object
+
+// Optional is a generic container, e.g. for values coming from an API that may not be set
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Valid: true}
+}
+
+type Nullable[V any] struct {
+	value *V
+}
+
+func NullableOf[V any](value V) Nullable[V] {
+	return Nullable[V]{value: &value}
+}
+
+func (n Nullable[V]) Get() V {
+	if n.value == nil {
+		var zero V
+		return zero
+	}
+	return *n.value
+}
+
+func optionalBoolByteToEntityProperty(dbValue int8) (Optional[bool], error) {
+	return Some(dbValue != 0), nil
+}
+
+func optionalBoolByteToDatabaseValue(goValue Optional[bool]) (int8, error) {
+	if goValue.Value {
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,18 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -typeMappings mappings.json
+
+// Optional and Nullable (see containers.skip.go) are stored as their type argument thanks to the type mappings
+type Task struct {
+	Id       uint64
+	Text     Optional[string]
+	Priority Optional[int32]
+	Due      Optional[int64] `objectbox:"date"`
+	Payload  Nullable[[]byte]
+	Done     Optional[bool] `objectbox:"type:int8 converter:optionalBoolByte"` // explicit annotations take precedence
+}
+
+type Note struct {
+	Id   uint64
+	Text Optional[string]
+}
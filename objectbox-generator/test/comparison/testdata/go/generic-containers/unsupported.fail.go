@@ -0,0 +1,12 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -typeMappings mappings.json
+
+// ERROR = can't prepare bindings for generic-containers/unsupported.fail.go: unsupported type argument Status of the generic container Optional - only basic types can be stored on property Status found in Ticket
+
+type Status int
+
+type Ticket struct {
+	Id     uint64
+	Status Optional[Status]
+}
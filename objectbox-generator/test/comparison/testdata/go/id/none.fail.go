@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't prepare bindings for id/none.fail.go: no property recognized as an ID on entity None
+// ERROR = OBXG1005
 
 type None struct {
 }
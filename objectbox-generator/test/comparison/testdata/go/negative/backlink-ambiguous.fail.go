@@ -0,0 +1,14 @@
+package negative
+
+// ERROR = can't merge model information: entity BacklinkTarget backlink Sources: BacklinkSource has multiple to-one relations to BacklinkTarget (First, Second) - specify the relation property of the backlink
+
+type BacklinkTarget struct {
+	Id      uint64
+	Sources []*BacklinkSource `objectbox:"backlink"`
+}
+
+type BacklinkSource struct {
+	Id     uint64
+	First  *BacklinkTarget `objectbox:"link"`
+	Second *BacklinkTarget `objectbox:"link"`
+}
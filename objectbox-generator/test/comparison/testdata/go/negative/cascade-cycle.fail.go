@@ -0,0 +1,9 @@
+package negative
+
+// ERROR = OBXG1007
+
+// removing a manager would remove its reports, including (transitively) any manager in a cycle of objects
+type CascadeEmployee struct {
+	Id      uint64
+	Manager *CascadeEmployee `objectbox:"link cascade"`
+}
@@ -1,6 +1,6 @@
 package negative
 
-// ERROR = can't merge model information: merging entity DuplicateProperty: property text: duplicate property name (note that property names are case insensitive)
+// ERROR = OBXG2001
 
 type DuplicateProperty struct {
 	Id   uint64 `objectbox:"id"`
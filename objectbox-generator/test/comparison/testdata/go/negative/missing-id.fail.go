@@ -1,6 +1,6 @@
 package negative
 
-// ERROR = can't prepare bindings for negative/missing-id.fail.go: no property recognized as an ID on entity MissingId
+// ERROR = OBXG1005
 
 type MissingId struct {
 	Text string
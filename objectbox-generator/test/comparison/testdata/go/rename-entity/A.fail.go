@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't merge model information: entity A: uid annotation value must not be empty (model entity UID = 8717895732742165505) on entity A
+// ERROR = OBXG2002
 
 // will fail as uid-request (print UID from model)
 // `objectbox:"uid"`
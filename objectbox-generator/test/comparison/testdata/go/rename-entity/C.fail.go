@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't merge model information: entity C: uid annotation value must not be empty (entity not found in the model) on entity C
+// ERROR = OBXG2002
 
 // negative test, tag `objectbox:"uid"` on an unknown (new) entity
 // `objectbox:"uid"`
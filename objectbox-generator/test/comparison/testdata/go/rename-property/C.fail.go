@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't merge model information: merging entity C: property New: uid annotation value must not be empty, the property isn't present in the persisted model
+// ERROR = OBXG2004
 
 // negative test, tag `objectbox:"uid"` on an unknown property
 type C struct {
@@ -1,10 +1,6 @@
 package object
 
-/* ERROR:
-can't merge model information: merging entity NegTaskRelId: property Group: uid annotation value must not be empty:
-    [rename] apply the current UID 6745438398739480977
-    [change/reset] apply a new UID 3959279844101328186
-*/
+// ERROR = OBXG2003
 
 type NegTaskRelId struct {
 	Id    uint64
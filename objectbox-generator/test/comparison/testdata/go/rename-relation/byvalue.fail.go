@@ -1,10 +1,6 @@
 package object
 
-/* ERROR:
-can't merge model information: merging entity NegTaskRelValue: property Group: uid annotation value must not be empty:
-    [rename] apply the current UID 167566062957544642
-    [change/reset] apply a new UID 3959279844101328186
-*/
+// ERROR = OBXG2003
 
 type NegTaskRelValue struct {
 	Id    uint64
@@ -1,6 +1,6 @@
 package object
 
-// ERROR = can't merge model information: merging entity NegTaskRelEmbedded: relation Groups: uid annotation value must not be empty (model relation UID = 7845762441295307478)
+// ERROR = OBXG2005
 
 type NegTaskRelEmbedded struct {
 	Id uint64
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for struct-slices/combined.fail.go: `flex` annotation can't be combined with `index` on property Tags found in Note
+
+type Note struct {
+	Id   uint64
+	Tags []Tag `objectbox:"flex index"`
+}
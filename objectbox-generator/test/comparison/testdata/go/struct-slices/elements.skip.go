@@ -0,0 +1,19 @@
+package object
+
+// Elements of the slices in task.go; declared here (skipped by the generator), they aren't entities themselves
+type Tag struct {
+	Name   string
+	Weight float32
+	Hidden bool
+}
+
+type Attachment struct {
+	Name string
+	Data []byte
+	Size int64
+}
+
+type Item struct {
+	Text string
+	Done bool
+}
@@ -0,0 +1,10 @@
+package object
+
+// Slices of structs stored as a FlexBuffers vector (`flex`) or as objects of a generated entity (`related`)
+type Task struct {
+	Id          uint64
+	Text        string
+	Tags        []Tag         `objectbox:"flex"`
+	Attachments []*Attachment `objectbox:"flex"`
+	Items       []Item        `objectbox:"related"`
+}
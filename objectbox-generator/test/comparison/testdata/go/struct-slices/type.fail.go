@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for struct-slices/type.fail.go: `related` annotation is only supported on slices of structs declared in the same package, e.g. []Tag or []*Tag, got []string on property Items found in Note
+
+type Note struct {
+	Id    uint64
+	Items []string `objectbox:"related"`
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = OBXG3001
+
+// `objectbox:"sync(globalIds)"`
+type SyncWithUnknownDetail struct {
+	Id uint64
+}
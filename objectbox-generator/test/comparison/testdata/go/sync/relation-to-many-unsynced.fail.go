@@ -0,0 +1,13 @@
+package object
+
+// ERROR = OBXG1008
+
+// `objectbox:"sync"`
+type SyncedWithUnsyncedRelMany struct {
+	Id  uint64
+	Rel []NotSyncedMany
+}
+
+type NotSyncedMany struct {
+	Id uint64
+}
@@ -0,0 +1,13 @@
+package object
+
+// ERROR = OBXG1008
+
+// `objectbox:"sync"`
+type SyncedWithUnsyncedRel struct {
+	Id  uint64
+	Rel NotSynced `objectbox:"link"`
+}
+
+type NotSynced struct {
+	Id uint64
+}
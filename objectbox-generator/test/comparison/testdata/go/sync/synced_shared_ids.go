@@ -0,0 +1,8 @@
+package object
+
+// Tests "sync" entity annotation with the "sharedGlobalIds" detail
+
+// `objectbox:"sync(sharedGlobalIds)"`
+type SyncedSharedIds struct {
+	Id uint64
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for sync/value.fail.go: sync annotation value must be empty on entity SyncWithValue
+
+// `objectbox:"sync:true"`
+type SyncWithValue struct {
+	Id uint64
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for task/index-bytes.fail.go: index isn't supported for ByteVector properties on property Payload found in IndexOnBytes
+
+type IndexOnBytes struct {
+	Id      uint64
+	Payload []byte `objectbox:"index:value"`
+}
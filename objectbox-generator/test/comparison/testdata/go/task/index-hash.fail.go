@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for task/index-hash.fail.go: index type 'hash64' is only supported for strings, found Long - use index (or index:value) instead on property Priority found in IndexHashOnInteger
+
+type IndexHashOnInteger struct {
+	Id       uint64
+	Priority int64 `objectbox:"index:hash64"`
+}
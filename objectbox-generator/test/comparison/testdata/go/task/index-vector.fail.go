@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for task/index-vector.fail.go: float vectors can only be indexed using index type 'hnsw', e.g. for nearest neighbor search on property Embedding found in IndexOnVector
+
+type IndexOnVector struct {
+	Id        uint64
+	Embedding []float32 `objectbox:"index"`
+}
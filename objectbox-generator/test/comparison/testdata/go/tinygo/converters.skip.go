@@ -0,0 +1,22 @@
+package object
+
+import "strings"
+
+// labelsBinaryToEntityProperty and labelsBinaryToDatabaseValue store the labels as "key=value" lines, without reflection
+func labelsBinaryToEntityProperty(dbValue []byte) (map[string]string, error) {
+	var result = make(map[string]string)
+	for _, line := range strings.Split(string(dbValue), "\n") {
+		if pos := strings.IndexByte(line, '='); pos > 0 {
+			result[line[:pos]] = line[pos+1:]
+		}
+	}
+	return result, nil
+}
+
+func labelsBinaryToDatabaseValue(goValue map[string]string) ([]byte, error) {
+	var lines []string
+	for key, value := range goValue {
+		lines = append(lines, key+"="+value)
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,10 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -tinyGoCheck -entityHelpers
+
+// ERROR = entity helpers aren't supported with TinyGo - String() formats the values using fmt, which relies on reflection
+
+type HelperReading struct {
+	Id    uint64
+	Value float64
+}
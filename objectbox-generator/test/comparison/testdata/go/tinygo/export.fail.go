@@ -0,0 +1,10 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -tinyGoCheck -export
+
+// ERROR = export functions aren't supported with TinyGo - they use encoding/json and encoding/csv, which rely on reflection
+
+type ExportReading struct {
+	Id    uint64
+	Value float64
+}
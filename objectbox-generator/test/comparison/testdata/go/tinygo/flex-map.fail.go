@@ -0,0 +1,10 @@
+package object
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -tinyGoCheck
+
+// ERROR = can't prepare bindings for tinygo/flex-map.fail.go: map type map[string]interface{} isn't supported with TinyGo - the FlexBuffers converters use reflection, use a custom converter instead on property Attributes found in FlexReading
+
+type FlexReading struct {
+	Id         uint64
+	Attributes map[string]interface{}
+}
@@ -0,0 +1,15 @@
+package object
+
+import "time"
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -tinyGoCheck
+
+// Reading is a sensor value recorded on a device
+type Reading struct {
+	Id       uint64
+	Sensor   string `objectbox:"index"`
+	Value    float64
+	Raw      []byte
+	Recorded time.Time
+	Labels   map[string]string `objectbox:"type:[]byte converter:labelsBinary"`
+}
@@ -0,0 +1,7 @@
+package object
+
+type A struct {
+	Id    uint64
+	Name  string
+	Cache string `objectbox:"transient(allow-drop)"`
+}
@@ -0,0 +1,8 @@
+package object
+
+// ERROR = OBXG2006
+
+type B struct {
+	Id      uint64
+	Removed string `objectbox:"-"`
+}
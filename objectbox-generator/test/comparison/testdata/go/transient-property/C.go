@@ -0,0 +1,7 @@
+package object
+
+// C declares a transient field which has never been stored, so there's nothing to confirm
+type C struct {
+	Id      uint64
+	Scratch []byte `objectbox:"-"`
+}
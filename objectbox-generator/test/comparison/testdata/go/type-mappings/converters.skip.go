@@ -0,0 +1,56 @@
+package object
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type Money struct {
+	Units int64
+	Nanos int32
+}
+
+func moneyStringToEntityProperty(dbValue string) (Money, error) {
+	var money Money
+	_, err := fmt.Sscanf(dbValue, "%d.%d", &money.Units, &money.Nanos)
+	return money, err
+}
+
+func moneyStringToDatabaseValue(goValue Money) (string, error) {
+	return fmt.Sprintf("%d.%09d", goValue.Units, goValue.Nanos), nil
+}
+
+func moneyPtrStringToEntityProperty(dbValue string) (*Money, error) {
+	if dbValue == "" {
+		return nil, nil
+	}
+	money, err := moneyStringToEntityProperty(dbValue)
+	return &money, err
+}
+
+func moneyPtrStringToDatabaseValue(goValue *Money) (string, error) {
+	if goValue == nil {
+		return "", nil
+	}
+	return moneyStringToDatabaseValue(*goValue)
+}
+
+func moneyCentsToEntityProperty(dbValue int64) (Money, error) {
+	return Money{Units: dbValue / 100, Nanos: int32(dbValue%100) * 10000000}, nil
+}
+
+func moneyCentsToDatabaseValue(goValue Money) (int64, error) {
+	return goValue.Units*100 + int64(goValue.Nanos/10000000), nil
+}
+
+func bigIntStringToEntityProperty(dbValue string) (big.Int, error) {
+	var value big.Int
+	if _, ok := value.SetString(dbValue, 10); !ok {
+		return value, fmt.Errorf("invalid big.Int value %s", dbValue)
+	}
+	return value, nil
+}
+
+func bigIntStringToDatabaseValue(goValue big.Int) (string, error) {
+	return goValue.String(), nil
+}
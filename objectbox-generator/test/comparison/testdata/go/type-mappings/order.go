@@ -0,0 +1,14 @@
+package object
+
+import "math/big"
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -typeMappings mappings.json
+
+// Money (see converters.skip.go) would be embedded, but it's stored as a string thanks to the type mapping
+type Order struct {
+	Id       uint64
+	Total    Money
+	Discount *Money
+	Amount   big.Int
+	Paid     Money `objectbox:"type:int64 converter:moneyCents"` // explicit annotations take precedence
+}
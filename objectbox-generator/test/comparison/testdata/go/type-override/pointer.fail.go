@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for type-override/pointer.fail.go: type annotation without a converter can only be used to store an integer field as another integer type, e.g. `type:int8` on an int field; got type int8 on a *int field on property Value found in PointerOverride
+
+type PointerOverride struct {
+	Id    uint64
+	Value *int `objectbox:"type:int8"`
+}
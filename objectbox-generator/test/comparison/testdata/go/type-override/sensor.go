@@ -0,0 +1,11 @@
+package object
+
+type Level int
+
+type Sensor struct {
+	Id          uint64
+	Battery     int   `objectbox:"type:uint8"`
+	Temperature int   `objectbox:"type:int16"`
+	Level       Level `objectbox:"type:int8"`
+	Count       int64 `objectbox:"type:int64"`
+}
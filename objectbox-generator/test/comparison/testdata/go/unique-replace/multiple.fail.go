@@ -0,0 +1,9 @@
+package object
+
+// ERROR = OBXG1003
+
+type MultipleReplace struct {
+	Id   uint64
+	Sku  string `objectbox:"unique:replace"`
+	Code int64  `objectbox:"unique:replace"`
+}
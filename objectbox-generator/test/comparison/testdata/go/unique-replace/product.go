@@ -0,0 +1,9 @@
+package object
+
+// Product is imported from a catalog: putting a product with an existing SKU replaces the stored one
+type Product struct {
+	Id    uint64
+	Sku   string `objectbox:"unique:replace"`
+	Name  string `objectbox:"unique"`
+	Price float64
+}
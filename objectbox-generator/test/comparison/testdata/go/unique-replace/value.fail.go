@@ -0,0 +1,8 @@
+package object
+
+// ERROR = can't prepare bindings for unique-replace/value.fail.go: unknown unique conflict resolution ignore, expecting 'replace' or no value on property Sku found in UnknownConflictResolution
+
+type UnknownConflictResolution struct {
+	Id  uint64
+	Sku string `objectbox:"unique:ignore"`
+}
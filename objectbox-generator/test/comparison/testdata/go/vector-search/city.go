@@ -0,0 +1,7 @@
+package object
+
+type City struct {
+	Id       uint64
+	Name     string
+	Location []float32 `objectbox:"index:hnsw"`
+}
@@ -0,0 +1,18 @@
+package object
+
+import (
+	"math/big"
+	"time"
+
+	guuid "github.com/google/uuid"
+)
+
+// Account has fields of well-known types, stored using generated converters without any annotations
+type Account struct {
+	Id       uint64
+	Uid      guuid.UUID
+	Balance  *big.Int
+	Opened   time.Time
+	Closed   time.Time  `objectbox:"date-nano"`
+	Previous guuid.UUID `objectbox:"type:string converter:uuidString"` // explicit annotations take precedence
+}
@@ -0,0 +1,14 @@
+package object
+
+import guuid "github.com/google/uuid"
+
+func uuidStringToEntityProperty(dbValue string) (guuid.UUID, error) {
+	if dbValue == "" {
+		return guuid.Nil, nil
+	}
+	return guuid.Parse(dbValue)
+}
+
+func uuidStringToDatabaseValue(goValue guuid.UUID) (string, error) {
+	return goValue.String(), nil
+}
@@ -0,0 +1,12 @@
+package object
+
+import "time"
+
+//go:generate go run github.com/objectbox/objectbox-go/cmd/objectbox-gogen -noAutoConverters
+
+// ERROR = can't prepare bindings for well-known-types/opt-out.fail.go: time.Time requires a `date` or `date-nano` annotation or a custom converter when automatic converters are disabled on property Created found in OptOut
+
+type OptOut struct {
+	Id      uint64
+	Created time.Time
+}
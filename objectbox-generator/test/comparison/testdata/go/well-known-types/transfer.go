@@ -0,0 +1,15 @@
+package object
+
+import (
+	"math/big"
+
+	"github.com/google/uuid"
+)
+
+// Transfer uses the same types, with converters generated separately for each entity
+type Transfer struct {
+	Id     uint64
+	From   uuid.UUID
+	To     uuid.UUID
+	Amount *big.Int
+}
@@ -0,0 +1,62 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2018-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package fixture sets up the source files the generator tests run on, shared by the tests of all packages:
+// a temporary directory with the sources and running the generator on them.
+package fixture
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// TempDir creates a temporary directory for the sources of a test, returning its path and a function removing it
+func TempDir(t *testing.T, name string) (string, func()) {
+	dir, err := ioutil.TempDir("", "objectbox-generator-"+name)
+	assert.NoErr(t, err)
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// WriteFile writes the content to the given file, creating its directory if necessary, and returns the file path
+func WriteFile(t *testing.T, file string, content string) string {
+	assert.NoErr(t, os.MkdirAll(filepath.Dir(file), 0700))
+	assert.NoErr(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+// ReadFile returns the content of the given file, e.g. a generated one
+func ReadFile(t *testing.T, file string) string {
+	data, err := ioutil.ReadFile(file)
+	assert.NoErr(t, err)
+	return string(data)
+}
+
+// Generate runs the code generator on the given source file (or directory), failing the test on errors;
+// the model JSON file is the default one, next to the sources
+func Generate(t *testing.T, sourcePath string, codeGenerator generator.CodeGenerator) {
+	assert.NoErr(t, generator.Process(generator.Options{InPath: sourcePath, CodeGenerator: codeGenerator}))
+}
@@ -0,0 +1,167 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2020-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// AssertSameFile checks the generated file has the same contents as the expected one (ignoring line endings).
+// If update is true, the expected file is overwritten by the generated one first.
+func AssertSameFile(t *testing.T, file string, expectedFile string, update bool) {
+	if update && fileExists(file) {
+		assert.NoErr(t, CopyFile(file, expectedFile, 0))
+	}
+
+	// if no file is expected
+	if !fileExists(expectedFile) {
+		// there can be no source file either
+		if fileExists(file) {
+			assert.Failf(t, "%s is missing but %s exists", expectedFile, file)
+		}
+		return
+	}
+
+	content, err := ioutil.ReadFile(file)
+	assert.NoErr(t, err)
+
+	contentExpected, err := ioutil.ReadFile(expectedFile)
+	assert.NoErr(t, err)
+
+	// Normalize line endings for cross-platform comparison
+	content = normalizeLineEndings(content)
+	contentExpected = normalizeLineEndings(contentExpected)
+
+	if 0 != bytes.Compare(content, contentExpected) {
+		assert.Failf(t, "generated file %s is not the same as %s", file, expectedFile)
+	}
+
+	// Use git diff to compare the files
+	cmd := exec.Command("git", "diff", "--no-index", file, expectedFile)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+
+	if err != nil {
+		assert.Failf(t, "generated file %s is not the same as %s\n\n%s", file, expectedFile, out.String())
+	}
+}
+
+// normalizeLineEndings converts all line endings to LF (\n) for consistent comparison across platforms
+func normalizeLineEndings(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
+
+// normalizeErrorString normalizes error strings for cross-platform comparison by:
+// 1. Converting CRLF to LF
+// 2. Trimming trailing whitespace from each line
+// 3. Trimming leading/trailing whitespace from the entire string
+func normalizeErrorString(s string) string {
+	// Convert CRLF to LF
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	// Split into lines, trim trailing spaces from each line, then rejoin
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	s = strings.Join(lines, "\n")
+
+	// Trim leading/trailing whitespace from the entire string
+	return strings.TrimSpace(s)
+}
+
+// CopyFile copies the file contents, with the given permissions or, if zero, those of the existing target or the source
+func CopyFile(sourceFile, targetFile string, permsOverride os.FileMode) error {
+	data, err := ioutil.ReadFile(sourceFile)
+	if err != nil {
+		return err
+	}
+
+	// copy permissions either from the existing target file or from the source file
+	var perm os.FileMode = permsOverride
+	if perm == 0 {
+		if info, _ := os.Stat(targetFile); info != nil {
+			perm = info.Mode()
+		} else if info, err := os.Stat(sourceFile); info != nil {
+			perm = info.Mode()
+		} else {
+			return err
+		}
+	}
+
+	err = ioutil.WriteFile(targetFile, data, perm)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !os.IsNotExist(err)
+}
+
+// CopyDirectory recursively copies the directory contents
+func CopyDirectory(sourceDir, targetDir string, dirPerms, filePerms os.FileMode) error {
+	if err := os.MkdirAll(targetDir, dirPerms); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(sourceDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		sourcePath := filepath.Join(sourceDir, entry.Name())
+		targetPath := filepath.Join(targetDir, entry.Name())
+
+		info, err := os.Stat(sourcePath)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if err := CopyDirectory(sourcePath, targetPath, dirPerms, filePerms); err != nil {
+				return err
+			}
+		} else if info.Mode().IsRegular() {
+			if err := CopyFile(sourcePath, targetPath, filePerms); err != nil {
+				return err
+			}
+		} else {
+			return fmt.Errorf("not a regular file or directory: %s", sourcePath)
+		}
+	}
+	return nil
+}
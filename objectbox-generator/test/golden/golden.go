@@ -0,0 +1,326 @@
+/*
+ * ObjectBox Generator - a build time tool for ObjectBox
+ * Copyright (C) 2020-2024 ObjectBox Ltd. All rights reserved.
+ * https://objectbox.io
+ *
+ * This file is part of ObjectBox Generator.
+ *
+ * ObjectBox Generator is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ * ObjectBox Generator is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with ObjectBox Generator.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package golden runs golden-file tests of code generators: the code generated for each source file of a test case
+// is compared to the expected (".expected") files stored next to the sources. This is what the generator's own
+// comparison tests use; code generator authors can use it with the same conventions to test their generators:
+//   - each subdirectory of Config.SourceDir is a test case, with source files processed one by one, in alphabetical order,
+//   - generated files are expected as "<file>.expected", in the test case directory or its Config.ExpectedSubdir,
+//   - "objectbox-model.json.expected" (and the model code file, e.g. "objectbox-model.h.expected") are compared after
+//     all the sources of a test case have been processed,
+//   - "*.initial" files are copied to the file name without the extension before generating (e.g. an initial model),
+//   - "*.skip.<ext>" source files are not processed directly (but may be used by the other sources),
+//   - "*.fail.<ext>" source files are negative tests, the expected error given in the file as `// ERROR = text`
+//     or as a multi-line `/* ERROR ... */` comment; an error code from the message catalog, e.g. `// ERROR = OBXG1001`,
+//     only matches the code so the test doesn't depend on the exact (possibly translated) text,
+//   - "compile-error.expected", if present, is the expected output of Config.Build.
+//
+// The tested generator is given as a Generator, which only uses standard types, so it can wrap any code generator,
+// e.g. one running an external executable.
+package golden
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/objectbox/objectbox-generator/v4/internal/generator"
+	"github.com/objectbox/objectbox-generator/v4/internal/generator/messages"
+	"github.com/objectbox/objectbox-generator/v4/test/assert"
+)
+
+// Generator is the code generator under test, generating the files for a single source file at a time
+type Generator interface {
+	// Generate processes the source file, updating the model JSON file modelInfoFile and writing the generated files
+	// to outDir. The generated code must be deterministic, e.g. the UIDs of new model elements must be the same each
+	// time the test runs.
+	Generate(sourceFile, modelInfoFile, outDir string) error
+
+	// BindingFiles returns the paths of the files generated for the given source file in outDir
+	BindingFiles(sourceFile, outDir string) ([]string, error)
+
+	// ModelFile returns the path of the model code file generated for modelInfoFile, e.g. "objectbox-model.h", in
+	// outDir or, if outDir is empty, next to modelInfoFile
+	ModelFile(modelInfoFile, outDir string) string
+
+	// IsGeneratedFile returns whether the given file is a generated file (i.e. not a source file)
+	IsGeneratedFile(file string) bool
+}
+
+// Config describes the golden-file tests of a single code generator
+type Config struct {
+	// SourceDir contains a subdirectory with source files for each test case, e.g. "testdata/fbs"
+	SourceDir string
+
+	// ExpectedSubdir, if set, is the subdirectory of each test case with the expected files, e.g. "cpp".
+	// Useful if there are multiple generators for the same sources.
+	ExpectedSubdir string
+
+	// SourceExt is the extension of the source files, e.g. ".fbs"
+	SourceExt string
+
+	// GeneratedExt lists extensions of the generated files, e.g. ".obx.h"; these are removed before updating
+	GeneratedExt []string
+
+	// Generator is the code generator under test
+	Generator Generator
+
+	// GeneratorFor, if set, returns the code generator for the given source file, e.g. a copy of Generator configured
+	// by arguments given in the source file
+	GeneratorFor func(t *testing.T, sourceFile string, genDir string) Generator
+
+	// PrepareTempDir, if set, is called after the test case sources are copied to a temporary directory (tempDir).
+	// It may return a function transforming the errors before they're compared to the expected ones, e.g. mapping paths.
+	PrepareTempDir func(t *testing.T, srcDir, tempDir, tempRoot string) func(err error) error
+
+	// Build, if set, compiles the generated code in the given directory; skipped in short mode (go test -short)
+	Build func(t *testing.T, dir string, expectedError error, errorTransformer func(err error) error)
+
+	// Update overwrites the expected files with the generated ones, e.g. when set by an "-update" test flag.
+	// It's up to the developer to check the changes before committing them.
+	Update bool
+}
+
+// RunAll runs a subtest named "<name>/<test case>" for each test case in Config.SourceDir, in parallel
+func RunAll(t *testing.T, name string, conf Config) {
+	testCases, err := ioutil.ReadDir(conf.SourceDir)
+	assert.NoErr(t, err)
+
+	for _, testCase := range testCases {
+		if !testCase.IsDir() {
+			continue
+		}
+
+		var tc = testCase.Name() // need to create a variable in order to be captured properly by the lambda below
+		t.Run(name+"/"+tc, func(t *testing.T) {
+			t.Parallel()
+			RunCase(t, conf, tc)
+		})
+	}
+}
+
+// RunCase runs a single test case, i.e. a subdirectory of Config.SourceDir
+func RunCase(t *testing.T, conf Config, testCase string) {
+	var srcDir = filepath.Join(conf.SourceDir, testCase) // where input files, e.g. schema.fbs, are
+	var expDir = filepath.Join(srcDir, conf.ExpectedSubdir)
+
+	var errorTransformer = func(err error) error {
+		return err
+	}
+
+	var cleanup = func() {}
+	defer func() {
+		cleanup()
+	}()
+
+	// Test in a temporary directory - if tested by an end user, the repo is read-only.
+	tempRoot, err := ioutil.TempDir("", "objectbox-generator-test")
+	assert.NoErr(t, err)
+
+	// we can't defer directly because compilation step is run in a separate goroutine after this function exits
+	cleanup = func() {
+		assert.NoErr(t, os.RemoveAll(tempRoot))
+	}
+
+	var genDir = filepath.Join(tempRoot, testCase) // where should the generator output the files
+	t.Logf("Testing in a temporary directory %s", genDir)
+	assert.NoErr(t, os.MkdirAll(genDir, 0700))
+
+	// copy the source dir, including the relative paths (to make sure expected errors contain same paths)
+	assert.NoErr(t, CopyDirectory(srcDir, genDir, 0700, 0600))
+
+	if conf.PrepareTempDir != nil {
+		if errTrans := conf.PrepareTempDir(t, srcDir, genDir, tempRoot); errTrans != nil {
+			errorTransformer = errTrans
+		}
+	}
+
+	modelInfoFile := generator.ModelInfoFile(genDir)
+	modelInfoExpectedFile := generator.ModelInfoFile(srcDir) + ".expected"
+
+	modelCodeFile := conf.Generator.ModelFile(modelInfoFile, genDir)
+	modelCodeExpectedFile := conf.Generator.ModelFile(generator.ModelInfoFile(expDir), "") + ".expected"
+
+	// Go generator updates generator go.mod when loading files (adds the missing objectbox-go import).
+	// Therefore, we'll load files from the temp dir instead
+	srcDir = genDir
+
+	// run the generation twice, first time with deleting old modelInfo
+	for i := 0; i <= 1; i++ {
+		if i == 0 {
+			t.Logf("Testing '%s' without model info JSON", testCase)
+			os.Remove(modelInfoFile)
+		} else if testing.Short() {
+			continue // don't test twice in "short" tests
+		} else {
+			t.Logf("Testing '%s' with previous model info JSON", testCase)
+		}
+
+		// setup the desired directory contents by copying "*.initial" files to their name without the extension
+		setupInitialFiles(t, srcDir, genDir)
+		setupInitialFiles(t, expDir, genDir)
+
+		if 0 != generateAllFiles(t, conf, srcDir, expDir, genDir, modelInfoFile, errorTransformer) {
+			AssertSameFile(t, modelInfoFile, modelInfoExpectedFile, conf.Update)
+			AssertSameFile(t, modelCodeFile, modelCodeExpectedFile, conf.Update)
+		}
+	}
+
+	// verify the result can be built
+	if conf.Build != nil && !testing.Short() {
+		// override the defer to prevent cleanup before compilation is actually run
+		var cleanupAfterCompile = cleanup
+		cleanup = func() {}
+
+		t.Run("compile", func(t *testing.T) {
+			defer cleanupAfterCompile()
+			t.Parallel()
+			var expectedError error
+			if fileExists(path.Join(expDir, "compile-error.expected")) {
+				content, err := ioutil.ReadFile(path.Join(expDir, "compile-error.expected"))
+				assert.NoErr(t, err)
+				expectedError = errors.New(string(content))
+			}
+			conf.Build(t, genDir, expectedError, errorTransformer)
+		})
+	}
+}
+
+func setupInitialFiles(t *testing.T, srcDir, targetDir string) {
+	srcFiles, err := filepath.Glob(filepath.Join(srcDir, "*.initial"))
+	assert.NoErr(t, err)
+	for _, srcFile := range srcFiles {
+		targetFile := filepath.Base(srcFile)
+		targetFile = targetFile[0 : len(targetFile)-len(".initial")]
+		targetFile = filepath.Join(targetDir, targetFile)
+		assert.NoErr(t, CopyFile(srcFile, targetFile, 0))
+	}
+}
+
+func generateAllFiles(t *testing.T, conf Config, srcDir, expDir, genDir string, modelInfoFile string, errorTransformer func(error) error) int {
+	// remove generated files during development (they might be syntactically wrong)
+	if conf.Update {
+		for _, generatedExt := range conf.GeneratedExt {
+			files, err := filepath.Glob(filepath.Join(genDir, "*"+generatedExt))
+			assert.NoErr(t, err)
+
+			for _, file := range files {
+				t.Logf("removing previously generated %s", file)
+				assert.NoErr(t, os.Remove(file))
+			}
+		}
+	}
+
+	var positiveTestsCount = 0
+
+	// process all source files in the directory
+	inputFiles, err := filepath.Glob(filepath.Join(srcDir, "*"+conf.SourceExt))
+	assert.NoErr(t, err)
+	assert.True(t, len(inputFiles) > 0)
+
+	for _, sourceFile := range inputFiles {
+		// skip generated files & "expected results" files
+		if conf.Generator.IsGeneratedFile(sourceFile) ||
+			strings.HasSuffix(sourceFile, ".skip"+conf.SourceExt) ||
+			strings.HasSuffix(sourceFile, "expected") ||
+			strings.HasSuffix(sourceFile, "initial") {
+			continue
+		}
+
+		t.Logf("  %s", filepath.Base(sourceFile))
+
+		var codeGenerator = conf.Generator
+		if conf.GeneratorFor != nil {
+			codeGenerator = conf.GeneratorFor(t, sourceFile, genDir)
+		}
+		err = errorTransformer(codeGenerator.Generate(sourceFile, modelInfoFile, genDir))
+
+		// handle negative test
+		var shouldFail = strings.HasSuffix(filepath.Base(sourceFile), ".fail"+conf.SourceExt)
+		if shouldFail {
+			if err == nil {
+				assert.Failf(t, "Unexpected PASS on a negative test %s", sourceFile)
+			} else {
+				if expectedCode := ExpectedError(t, sourceFile).Error(); errorCodeRegexp.MatchString(expectedCode) {
+					if messages.Code(err) != expectedCode {
+						t.Logf("Full error: %s", err)
+					}
+					assert.Eq(t, expectedCode, messages.Code(err))
+					continue
+				}
+
+				var unifiedError = strings.Replace(err.Error(), "\\", "/", -1) // "Unify" Windows paths
+				// Normalize line endings and trim trailing spaces from each line for cross-platform comparison
+				unifiedError = normalizeErrorString(unifiedError)
+				expectedError := ExpectedError(t, sourceFile).Error()
+				expectedError = normalizeErrorString(expectedError)
+				if strings.HasPrefix(unifiedError, "error generating model from schema ") {
+					// Compare only the last part of unifiedError as it contains the full path to the schema file
+					unifiedError = unifiedError[len(unifiedError)-len(expectedError):]
+					if unifiedError != expectedError {
+						t.Logf("Full error: %s", err) // Initial error, which may contain additional information
+					}
+				}
+				assert.Eq(t, expectedError, unifiedError)
+				continue
+			}
+		} else {
+			positiveTestsCount++
+		}
+
+		assert.NoErr(t, err)
+
+		bindingFiles, err := codeGenerator.BindingFiles(sourceFile, genDir)
+		assert.NoErr(t, err)
+		for _, bindingFile := range bindingFiles {
+			var expectedFile = strings.Replace(bindingFile, genDir, expDir, 1) + ".expected"
+			AssertSameFile(t, bindingFile, expectedFile, conf.Update)
+		}
+	}
+	return positiveTestsCount
+}
+
+// errorCodeRegexp matches an expected error given as a code of the message catalog, e.g. OBXG1001
+var errorCodeRegexp = regexp.MustCompile(`^OBXG\d{4}$`)
+
+var expectedErrorRegexp = regexp.MustCompile(`// *ERROR *=(.+)[\n|\r]`)
+var expectedErrorRegexpMulti = regexp.MustCompile(`(?sU)/\* *ERROR.*[\n|\r](.+)\*/`)
+
+// ExpectedError returns the error declared in the given (negative test) source file, failing the test if there's none
+func ExpectedError(t *testing.T, sourceFile string) error {
+	source, err := ioutil.ReadFile(sourceFile)
+	assert.NoErr(t, err)
+
+	if match := expectedErrorRegexp.FindSubmatch(source); len(match) > 1 {
+		return errors.New(strings.TrimSpace(string(match[1]))) // this is a "positive" return
+	}
+
+	if match := expectedErrorRegexpMulti.FindSubmatch(source); len(match) > 1 {
+		return errors.New(strings.TrimSpace(string(match[1]))) // this is a "positive" return
+	}
+
+	assert.Failf(t, "missing error declaration in %s - add comment to the file // ERROR = expected error text", sourceFile)
+	return nil
+}
@@ -38,7 +38,7 @@ import (
 	"github.com/objectbox/objectbox-generator/v4/test/assert"
 	"github.com/objectbox/objectbox-generator/v4/test/build"
 	"github.com/objectbox/objectbox-generator/v4/test/cmake"
-	"github.com/objectbox/objectbox-generator/v4/test/comparison"
+	"github.com/objectbox/objectbox-generator/v4/test/golden"
 )
 
 type cCppStandard string
@@ -99,13 +99,14 @@ func repoRoot(t *testing.T) string {
 // 	return
 // }
 
-func generateCCpp(t *testing.T, srcPath string, outDir string, cGenerator *cgenerator.CGenerator) {
+func generateCCpp(t *testing.T, srcPath string, outDir string, cGenerator *cgenerator.CGenerator, acceptOptionsChange bool) {
 	t.Logf("generating code for %s into %s", srcPath, outDir)
 	var options = generator.Options{
-		ModelInfoFile: path.Join(outDir, "objectbox-model.json"),
-		CodeGenerator: cGenerator,
-		InPath:        srcPath,
-		OutPath:       outDir,
+		ModelInfoFile:       path.Join(outDir, "objectbox-model.json"),
+		CodeGenerator:       cGenerator,
+		InPath:              srcPath,
+		OutPath:             outDir,
+		AcceptOptionsChange: acceptOptionsChange,
 	}
 	assert.NoErr(t, generator.Process(options))
 }
@@ -113,6 +114,10 @@ func generateCCpp(t *testing.T, srcPath string, outDir string, cGenerator *cgene
 type CCppTestConf struct {
 	Cmake     *cmake.Cmake
 	Generator *cgenerator.CGenerator
+
+	// AcceptOptionsChange allows generating sources with different -empty-string-as-null and -nan-as-null options
+	// into the same model, see generator.Options.AcceptOptionsChange
+	AcceptOptionsChange bool
 }
 
 func sourceExt(cpp bool) string {
@@ -209,7 +214,7 @@ func (conf *CCppTestConf) Generate(t *testing.T, schemas map[string]string) {
 		}
 	}
 
-	generateCCpp(t, srcPath, conf.Cmake.ConfDir, cGenerator)
+	generateCCpp(t, srcPath, conf.Cmake.ConfDir, cGenerator, conf.AcceptOptionsChange)
 }
 
 // Build compiles the test sources producing an executable
@@ -269,7 +274,7 @@ func (conf *CCppTestConf) copyObjectBoxDll(t *testing.T) {
 	for _, dllFile := range dllFiles {
 		dllName := filepath.Base(dllFile)
 		t.Logf("Copying DLL from lib: %s", dllName)
-		assert.NoErr(t, comparison.CopyFile(
+		assert.NoErr(t, golden.CopyFile(
 			dllFile,
 			filepath.Join(conf.Cmake.BuildDir, dllName),
 			0))
@@ -296,7 +301,7 @@ func (conf *CCppTestConf) copyMinGwDlls(t *testing.T) {
 			srcPath := filepath.Join(mingwBinDir, dllName)
 			if _, err := os.Stat(srcPath); err == nil {
 				t.Logf("Copying MinGW runtime DLL: %s", dllName)
-				assert.NoErr(t, comparison.CopyFile(
+				assert.NoErr(t, golden.CopyFile(
 					srcPath,
 					filepath.Join(conf.Cmake.BuildDir, dllName),
 					0))
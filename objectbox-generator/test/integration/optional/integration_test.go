@@ -106,7 +106,8 @@ const asNullSchemaFields = `
 `
 
 func TestCppAndC(t *testing.T) {
-	conf := &integration.CCppTestConf{}
+	// the sources are generated with and without -empty-string-as-null and -nan-as-null into the same model
+	conf := &integration.CCppTestConf{AcceptOptionsChange: true}
 	defer conf.Cleanup()
 	conf.CreateCMake(t, integration.Cpp17, "main.cpp")
 	conf.Generate(t, map[string]string{"rel.fbs": "table RelTarget {id: uint64;}"})
@@ -114,4 +114,23 @@ TEST_CASE("update-entity", "") {
     box.get(id, myObj2);
     REQUIRE(myObj2.int_ == 23);
     REQUIRE(myObj2.string.empty());
-}
\ No newline at end of file
+}
+TEST_CASE("query-helpers", "") {
+    Store store = testStore(true, "c-cpp-tests-db");
+    auto box = store.box<Annotated>();
+
+    Annotated item{};
+    item.fullName = "Foo";
+    box.put(item);
+    item.identifier = 0;
+    item.fullName = "Bar";
+    box.put(item);
+
+    REQUIRE(box.query(Annotated_::fullName.startsWith("F")).build().count() == 1);
+
+    // index-based lookup is case-sensitive
+    std::vector<Annotated> found = Annotated_::findByFullName(box, "Bar");
+    REQUIRE(found.size() == 1);
+    REQUIRE(found[0].fullName == "Bar");
+    REQUIRE(Annotated_::findByFullName(box, "bar").empty());
+}
@@ -37,12 +37,41 @@ typedef struct FBS_bytes {
 
 void fbs_error_free(const char* error);
 
+/// Attribute recognized by the schema parser without a declaration, see fbs_schema_parse_source()
+#define FBS_ARRAY_LENGTH_ATTRIBUTE "obx_array_length"
+
 /// Parses a FlatBuffers schema file.
 /// @param out_error - error if any occurred in which case you must free it using fbs_error_free() after reading.
 /// @param filename absolute path to a schema file file.
 /// @return a pointer to the loaded FB of the schema. Must be freed after use by calling fbs_schema_free()
 FBS_bytes* fbs_schema_parse_file(const char* filename, const char** out_error);
 
+/// Parses a FlatBuffers schema file, looking up `include "file.fbs";` statements relative to the including file and in
+/// the given include directories, in this order. Objects carry their declaration file relative to the directory of the
+/// parsed schema file, e.g. "//schema.fbs" for the parsed file itself.
+/// @param include_dirs directories to look up included files in; may be NULL if include_dirs_count is zero.
+/// @see fbs_schema_parse_file() for the other arguments and the return value.
+FBS_bytes* fbs_schema_parse_file_with_includes(const char* filename, const char** include_dirs,
+                                               size_t include_dirs_count, const char** out_error);
+
+/// Parses the given FlatBuffers schema source instead of reading the file, e.g. after rewriting fixed-length array
+/// fields in tables (which the FlatBuffers parser only accepts in structs) to vectors carrying the array length in the
+/// FBS_ARRAY_LENGTH_ATTRIBUTE attribute. The filename is still used to report errors and to look up included files.
+/// @param source null-terminated schema source; if NULL, the file is read, same as fbs_schema_parse_file_with_includes()
+/// @see fbs_schema_parse_file_with_includes() for the other arguments and the return value.
+FBS_bytes* fbs_schema_parse_source(const char* filename, const char* source, const char** include_dirs,
+                                   size_t include_dirs_count, const char** out_error);
+
+/// Parses the given FlatBuffers schema source like fbs_schema_parse_source(), with the sources of (some of) the included
+/// files given as well, e.g. rewritten the same way as the parsed source. The other included files are read from disk.
+/// Included files are matched by their absolute path.
+/// @param included_files paths of the included files whose sources are given, may be NULL if included_count is zero.
+/// @param included_sources null-terminated sources of the included files, in the same order as included_files.
+/// @see fbs_schema_parse_source() for the other arguments and the return value.
+FBS_bytes* fbs_schema_parse_sources(const char* filename, const char* source, const char** include_dirs,
+                                    size_t include_dirs_count, const char** included_files,
+                                    const char** included_sources, size_t included_count, const char** out_error);
+
 /// Frees memory of both FBS_bytes as well as the inner schema->data
 void fbs_schema_free(FBS_bytes* schema);
 
@@ -22,8 +22,34 @@
 #include <flatbuffers/idl.h>
 #include <flatbuffers/util.h>
 
+#include <map>
+#include <mutex>
+#include <vector>
+
 #include "utils.h"
 
+namespace {
+
+/// Sources of the included files given to fbs_schema_parse_sources(), by absolute path, while parsing on this thread
+thread_local const std::map<std::string, std::string>* includedSources = nullptr;
+
+/// The file loader replaced by loadIncludedSource(), reading files from disk
+flatbuffers::LoadFileFunction loadFileFromDisk = nullptr;
+
+/// Loads included files from includedSources, if given, falling back to reading the file from disk
+bool loadIncludedSource(const char* name, bool binary, std::string* buf) {
+    if (includedSources) {
+        auto it = includedSources->find(flatbuffers::AbsolutePath(name));
+        if (it != includedSources->end()) {
+            *buf = it->second;
+            return true;
+        }
+    }
+    return loadFileFromDisk(name, binary, buf);
+}
+
+}  // namespace
+
 void fbs_error_free(const char* error) {
     if (error == nullptr) return;
     if (error == ErrorAllocationError) return;
@@ -32,19 +58,65 @@ void fbs_error_free(const char* error) {
 }
 
 FBS_bytes* fbs_schema_parse_file(const char* filename, const char** out_error) {
+    return fbs_schema_parse_file_with_includes(filename, nullptr, 0, out_error);
+}
+
+FBS_bytes* fbs_schema_parse_file_with_includes(const char* filename, const char** include_dirs,
+                                               size_t include_dirs_count, const char** out_error) {
+    return fbs_schema_parse_source(filename, nullptr, include_dirs, include_dirs_count, out_error);
+}
+
+FBS_bytes* fbs_schema_parse_source(const char* filename, const char* source, const char** include_dirs,
+                                   size_t include_dirs_count, const char** out_error) {
+    return fbs_schema_parse_sources(filename, source, include_dirs, include_dirs_count, nullptr, nullptr, 0, out_error);
+}
+
+FBS_bytes* fbs_schema_parse_sources(const char* filename, const char* source, const char** include_dirs,
+                                    size_t include_dirs_count, const char** included_files,
+                                    const char** included_sources, size_t included_count, const char** out_error) {
     return runCpp(out_error, nullptr, [&]() -> FBS_bytes* {
         VERIFY_ARGUMENT_NOT_NULL(filename);
+        if (include_dirs_count > 0) VERIFY_ARGUMENT_NOT_NULL(include_dirs);
+        if (included_count > 0) {
+            VERIFY_ARGUMENT_NOT_NULL(included_files);
+            VERIFY_ARGUMENT_NOT_NULL(included_sources);
+        }
+
+        // the loader is global, installed once; the sources are only used by the parser running on this thread
+        static std::once_flag loaderInstalled;
+        std::call_once(loaderInstalled, []() { loadFileFromDisk = flatbuffers::SetLoadFileFunction(loadIncludedSource); });
+
+        std::map<std::string, std::string> sources;
+        for (size_t i = 0; i < included_count; i++) {
+            VERIFY_ARGUMENT_NOT_NULL(included_files[i]);
+            VERIFY_ARGUMENT_NOT_NULL(included_sources[i]);
+            sources[flatbuffers::AbsolutePath(included_files[i])] = included_sources[i];
+        }
+        struct SourcesScope {
+            explicit SourcesScope(const std::map<std::string, std::string>* sources) { includedSources = sources; }
+            ~SourcesScope() { includedSources = nullptr; }
+        } sourcesScope(sources.empty() ? nullptr : &sources);
+
+        // the parser expects a null-terminated list; the current dir comes last, it's the only one used by default
+        std::vector<const char*> include_paths(include_dirs, include_dirs + include_dirs_count);
+        include_paths.push_back("");
+        include_paths.push_back(nullptr);
 
         std::string contents;
-        if (!flatbuffers::LoadFile(filename, true, &contents)) {
+        if (source) {
+            contents = source;
+        } else if (!flatbuffers::LoadFile(filename, true, &contents)) {
             throw std::invalid_argument(std::string("unable to load file: ") + filename);
         }
 
         auto options = flatbuffers::IDLOptions();
         options.binary_schema_comments = true;  // include doc comments in the binary schema
+        options.binary_schema_builtins = true;  // include built-in attributes, e.g. to reject nested_flatbuffer
+        options.project_root = flatbuffers::StripFileName(flatbuffers::AbsolutePath(filename));  // declaration files
 
         flatbuffers::Parser parser(options);
-        if (!parser.Parse(contents.c_str(), nullptr, filename)) {
+        parser.known_attributes_[FBS_ARRAY_LENGTH_ATTRIBUTE] = true;  // built-in, doesn't need to be declared
+        if (!parser.Parse(contents.c_str(), include_paths.data(), filename)) {
             throw std::runtime_error(parser.error_);
         }
 
@@ -62,6 +62,15 @@ TEST_CASE("schema-parser-errors", "") {
     REQUIRE_THAT(error.text, Catch::Contains("must not be null"));
 }
 
+TEST_CASE("schema-parser-include-dirs-errors", "") {
+    // include dirs must be given if their count is non-zero
+    Error error;
+    FBS_bytes* schema = fbs_schema_parse_file_with_includes(TEST_SRC_DIRECTORY "schema.fbs", nullptr, 1, error.ptr());
+    REQUIRE(schema == nullptr);
+    REQUIRE(error.text != nullptr);
+    REQUIRE_THAT(error.text, Catch::Contains("must not be null"));
+}
+
 TEST_CASE("schema-parser", "") {
     Error error;
     FBS_bytes* schema = fbs_schema_parse_file(TEST_SRC_DIRECTORY "schema.fbs", error.ptr());
@@ -92,6 +101,20 @@ TEST_CASE("schema-parser", "") {
     fbs_schema_free(schema);
 }
 
+TEST_CASE("schema-parser-source", "") {
+    // the array length attribute doesn't need to be declared
+    Error error;
+    FBS_bytes* schema = fbs_schema_parse_source(TEST_SRC_DIRECTORY "virtual.fbs",
+                                                "table Doc { vec:[float] (" FBS_ARRAY_LENGTH_ATTRIBUTE ": 4); }",
+                                                nullptr, 0, error.ptr());
+    REQUIRE(error.text == nullptr);
+    REQUIRE(schema != nullptr);
+
+    std::string str(static_cast<char*>(schema->data), schema->size);
+    REQUIRE_THAT(str, Catch::Contains(FBS_ARRAY_LENGTH_ATTRIBUTE));
+    fbs_schema_free(schema);
+}
+
 TEST_CASE("flatc-main", "") {
     Error error;
 